@@ -1,18 +1,30 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/nats-io/nats.go"
 	"github.com/zukigit/chat/backend/internal/interceptors"
 	"github.com/zukigit/chat/backend/internal/lib"
+	"github.com/zukigit/chat/backend/internal/mailer"
+	"github.com/zukigit/chat/backend/internal/oauth"
 	"github.com/zukigit/chat/backend/internal/services"
+	"github.com/zukigit/chat/backend/internal/webauthn"
 	"github.com/zukigit/chat/backend/proto/auth"
 	"github.com/zukigit/chat/backend/proto/chat"
 	"github.com/zukigit/chat/backend/proto/friendship"
+	"github.com/zukigit/chat/backend/proto/moderation"
 	"github.com/zukigit/chat/backend/proto/notification"
 	"github.com/zukigit/chat/backend/proto/session"
 	"google.golang.org/grpc"
@@ -47,25 +59,75 @@ func main() {
 	}
 	defer nc.Close()
 
+	// suspended and banned users are turned away on every call
+	standingTTL, err := time.ParseDuration(lib.Getenv("ACCOUNT_STANDING_CACHE_TTL", "30s"))
+	if err != nil {
+		lib.ErrorLog.Fatalf("Invalid ACCOUNT_STANDING_CACHE_TTL: %v", err)
+	}
+	standing := services.NewAccountStanding(sqlDB, standingTTL)
+
+	// tokens of signed-out logins are turned away on every call
+	sessionTTL, err := time.ParseDuration(lib.Getenv("LOGIN_SESSION_CACHE_TTL", "30s"))
+	if err != nil {
+		lib.ErrorLog.Fatalf("Invalid LOGIN_SESSION_CACHE_TTL: %v", err)
+	}
+	loginSessions := services.NewLoginSessions(sqlDB, sessionTTL, js, nc)
+	interceptors.SetSessionCheck(loginSessions.Check)
+
 	// gRPC server setup
 	srv := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			interceptors.UnaryRecoveryInterceptor,
 			interceptors.UnaryJWTInterceptor,
+			interceptors.UnaryAccountInterceptor(standing.Check),
 		),
 		grpc.ChainStreamInterceptor(
 			interceptors.StreamRecoveryInterceptor,
 			interceptors.StreamJWTInterceptor,
+			interceptors.StreamAccountInterceptor(standing.Check),
 		),
 	)
 
 	// services registration
-	auth.RegisterAuthServer(srv, services.NewAuthServer(sqlDB))
 	notifServer := services.NewNotificationServer(sqlDB, js)
+	authServer := services.NewAuthServer(sqlDB, notifServer)
+	refreshTTL, err := time.ParseDuration(lib.Getenv("REFRESH_TOKEN_TTL", services.DefaultRefreshTokenTTL.String()))
+	if err != nil {
+		lib.ErrorLog.Fatalf("Invalid REFRESH_TOKEN_TTL: %v", err)
+	}
+	authServer.SetRefreshTokenTTL(refreshTTL)
+	authServer.SetLoginSessions(loginSessions)
+	reset := passwordReset()
+	authServer.SetPasswordReset(reset)
+	authServer.SetTOTPIssuer(lib.Getenv("TOTP_ISSUER", services.DefaultTOTPIssuer))
+	authServer.SetWebAuthn(relyingParty())
+	authServer.SetOAuthProviders(oauthProviders())
+	auth.RegisterAuthServer(srv, authServer)
 	notification.RegisterNotificationServer(srv, notifServer)
-	friendship.RegisterFriendshipServer(srv, services.NewFriendshipServer(sqlDB, notifServer))
-	session.RegisterSessionServer(srv, services.NewSessionServer())
+	friendshipServer := services.NewFriendshipServer(sqlDB, notifServer)
+	friendshipServer.SetRequestLimits(friendRequestLimits())
+	friendship.RegisterFriendshipServer(srv, friendshipServer)
+	session.RegisterSessionServer(srv, services.NewSessionServer(sqlDB))
 	chat.RegisterChatServer(srv, services.NewChatServer(sqlDB, notifServer))
+	moderation.RegisterModerationServer(srv, services.NewModerationServer(sqlDB, notifServer, standing))
+
+	// background conversation exports
+	exportTTL, err := time.ParseDuration(lib.Getenv("EXPORT_LINK_TTL", "24h"))
+	if err != nil {
+		lib.ErrorLog.Fatalf("Invalid EXPORT_LINK_TTL: %v", err)
+	}
+	exportDir := lib.Getenv("EXPORT_DIR", filepath.Join(os.TempDir(), "chat-exports"))
+	go services.NewExportWorker(sqlDB, exportDir, exportTTL).Run(context.Background())
+
+	// background password reset mail
+	go services.NewPasswordResetWorker(sqlDB, reset).Run(context.Background())
+
+	// background friend request expiry
+	friendRequestTTL, err := time.ParseDuration(lib.Getenv("FRIEND_REQUEST_TTL", "720h"))
+	if err != nil {
+		lib.ErrorLog.Fatalf("Invalid FRIEND_REQUEST_TTL: %v", err)
+	}
+	go services.NewFriendRequestExpiryWorker(sqlDB, notifServer, friendRequestTTL).Run(context.Background())
 
 	// start listening
 	listener, err := net.Listen("tcp", lib.Getenv("BACKEND_LISTEN_ADDRESS", ":1234"))
@@ -79,3 +141,146 @@ func main() {
 		lib.ErrorLog.Fatalf("Failed to serve: %v", err)
 	}
 }
+
+// friendRequestLimits reads the friend request limits from the environment,
+// falling back to services.DefaultFriendRequestLimits.
+func friendRequestLimits() services.FriendRequestLimits {
+	limits := services.DefaultFriendRequestLimits
+	var err error
+	if limits.PerHour, err = strconv.Atoi(lib.Getenv("FRIEND_REQUESTS_PER_HOUR", strconv.Itoa(limits.PerHour))); err != nil {
+		lib.ErrorLog.Fatalf("Invalid FRIEND_REQUESTS_PER_HOUR: %v", err)
+	}
+	if limits.PerDay, err = strconv.Atoi(lib.Getenv("FRIEND_REQUESTS_PER_DAY", strconv.Itoa(limits.PerDay))); err != nil {
+		lib.ErrorLog.Fatalf("Invalid FRIEND_REQUESTS_PER_DAY: %v", err)
+	}
+	if limits.DeclineCooldown, err = time.ParseDuration(lib.Getenv("FRIEND_REQUEST_DECLINE_COOLDOWN", limits.DeclineCooldown.String())); err != nil {
+		lib.ErrorLog.Fatalf("Invalid FRIEND_REQUEST_DECLINE_COOLDOWN: %v", err)
+	}
+	return limits
+}
+
+// passwordReset reads the password reset settings from the environment.
+// MAILER picks how reset links are delivered: "smtp" through SMTP_ADDR,
+// "file" as .eml files in MAIL_OUTBOX_DIR, or "log" (the default) to the
+// info log for setups without a mail server.
+func passwordReset() services.PasswordReset {
+	from := lib.Getenv("MAIL_FROM", "no-reply@localhost")
+
+	var m mailer.Mailer
+	switch kind := lib.Getenv("MAILER", "log"); kind {
+	case "smtp":
+		addr := lib.Getenv("SMTP_ADDR", "")
+		if addr == "" {
+			lib.ErrorLog.Fatalf("SMTP_ADDR is required when MAILER=smtp")
+		}
+		m = mailer.NewSMTPMailer(addr, lib.Getenv("SMTP_USERNAME", ""), lib.Getenv("SMTP_PASSWORD", ""), from)
+	case "file":
+		outbox, err := mailer.NewFileOutbox(lib.Getenv("MAIL_OUTBOX_DIR", filepath.Join(os.TempDir(), "chat-outbox")), from)
+		if err != nil {
+			lib.ErrorLog.Fatalf("Failed to set up mail outbox: %v", err)
+		}
+		m = outbox
+	case "log":
+		m = mailer.LogMailer{}
+	default:
+		lib.ErrorLog.Fatalf("Invalid MAILER %q: want smtp, file or log", kind)
+	}
+
+	ttl, err := time.ParseDuration(lib.Getenv("PASSWORD_RESET_TTL", services.DefaultPasswordResetTTL.String()))
+	if err != nil {
+		lib.ErrorLog.Fatalf("Invalid PASSWORD_RESET_TTL: %v", err)
+	}
+	perHour, err := strconv.Atoi(lib.Getenv("PASSWORD_RESET_PER_HOUR", strconv.Itoa(services.DefaultPasswordResetsPerHour)))
+	if err != nil {
+		lib.ErrorLog.Fatalf("Invalid PASSWORD_RESET_PER_HOUR: %v", err)
+	}
+	return services.PasswordReset{
+		Mailer:  m,
+		LinkURL: lib.Getenv("PASSWORD_RESET_URL", "http://localhost:5173/reset-password"),
+		TTL:     ttl,
+		PerHour: perHour,
+	}
+}
+
+// relyingParty reads the passkey settings from the environment.
+// WEBAUTHN_RP_ID is the domain passkeys are registered for and
+// WEBAUTHN_ORIGINS the comma-separated origins of the web app using them.
+func relyingParty() *webauthn.RelyingParty {
+	rp := &webauthn.RelyingParty{
+		ID:   lib.Getenv("WEBAUTHN_RP_ID", "localhost"),
+		Name: lib.Getenv("WEBAUTHN_RP_NAME", "Chat"),
+	}
+	for _, origin := range strings.Split(lib.Getenv("WEBAUTHN_ORIGINS", "http://localhost:5173"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			rp.Origins = append(rp.Origins, origin)
+		}
+	}
+	if len(rp.Origins) == 0 {
+		lib.ErrorLog.Fatalf("WEBAUTHN_ORIGINS is empty")
+	}
+	return rp
+}
+
+// oauthProviderName is what OAUTH_PROVIDERS may list; each name is also the
+// {provider} of the gateway's /oauth routes.
+var oauthProviderName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// oauthProviders reads the sign-in providers from the environment.
+// OAUTH_PROVIDERS is a comma-separated list of names, each configured by
+// OAUTH_<NAME>_CLIENT_ID, OAUTH_<NAME>_CLIENT_SECRET and optionally
+// OAUTH_<NAME>_SCOPES. "github" is GitHub; every other name is an OpenID
+// Connect issuer at OAUTH_<NAME>_ISSUER, which "google" need not set.
+// GITHUB_OAUTH_CLIENT_ID and GITHUB_OAUTH_CLIENT_SECRET still enable GitHub.
+func oauthProviders() map[string]oauth.Provider {
+	gatewayURL := strings.TrimSuffix(lib.Getenv("GATEWAY_PUBLIC_URL", "http://localhost:8080"), "/")
+
+	var names []string
+	for _, name := range strings.Split(lib.Getenv("OAUTH_PROVIDERS", ""), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if lib.Getenv("GITHUB_OAUTH_CLIENT_ID", "") != "" && !slices.Contains(names, "github") {
+		names = append(names, "github")
+	}
+
+	providers := make(map[string]oauth.Provider, len(names))
+	for _, name := range names {
+		if !oauthProviderName.MatchString(name) {
+			lib.ErrorLog.Fatalf("Invalid OAUTH_PROVIDERS name %q: want lowercase letters, digits and dashes", name)
+		}
+		prefix := "OAUTH_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		cfg := oauth.Config{
+			ClientID:     lib.Getenv(prefix+"CLIENT_ID", ""),
+			ClientSecret: lib.Getenv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  gatewayURL + "/oauth/" + name + "/callback",
+			Scopes:       strings.Fields(strings.ReplaceAll(lib.Getenv(prefix+"SCOPES", ""), ",", " ")),
+		}
+		if len(cfg.Scopes) == 0 {
+			cfg.Scopes = nil
+		}
+
+		if name == "github" && cfg.ClientID == "" {
+			cfg.ClientID = lib.Getenv("GITHUB_OAUTH_CLIENT_ID", "")
+			cfg.ClientSecret = lib.Getenv("GITHUB_OAUTH_CLIENT_SECRET", "")
+		}
+		if cfg.ClientID == "" || cfg.ClientSecret == "" {
+			lib.ErrorLog.Fatalf("%sCLIENT_ID and %sCLIENT_SECRET are required for OAuth provider %q", prefix, prefix, name)
+		}
+
+		switch name {
+		case "github":
+			providers[name] = oauth.NewGitHub(cfg)
+		default:
+			issuer := lib.Getenv(prefix+"ISSUER", "")
+			if issuer == "" && name == "google" {
+				issuer = "https://accounts.google.com"
+			}
+			if issuer == "" {
+				lib.ErrorLog.Fatalf("%sISSUER is required for OAuth provider %q", prefix, name)
+			}
+			providers[name] = oauth.NewOIDC(issuer, cfg)
+		}
+	}
+	return providers
+}
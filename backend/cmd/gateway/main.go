@@ -61,6 +61,12 @@ func main() {
 	}
 	defer chatClient.Close()
 
+	moderationClient, err := clients.NewModerationClient(backendAddr)
+	if err != nil {
+		lib.ErrorLog.Fatalf("Failed to connect to backend (moderation): %v", err)
+	}
+	defer moderationClient.Close()
+
 	// ping backend
 	lib.InfoLog.Printf("Pinging backend: %s", backendAddr)
 	if err := sessionClient.Ping(context.Background()); err != nil {
@@ -70,19 +76,46 @@ func main() {
 	// handlers preparation
 	authHandler := handlers.NewAuthHandler(authClient)
 	friendshipHandler := handlers.NewFriendshipHandler(friendshipClient)
-	sessionHandler := handlers.NewSessionHandler(sessionClient, chatClient, js)
+	sessionHandler := handlers.NewSessionHandler(sessionClient, chatClient, js, nc)
 	notificationHandler := handlers.NewNotificationHandler(notiClient)
 	chatHandler := handlers.NewChatHandler(chatClient)
+	moderationHandler := handlers.NewModerationHandler(moderationClient)
 
 	r := mux.NewRouter()
 	r.HandleFunc("/version", sessionHandler.GetChatEnvelopeRequestVersion).Methods(http.MethodGet)
 	r.HandleFunc("/login", authHandler.Login).Methods(http.MethodPost)
+	r.HandleFunc("/login/verify", authHandler.VerifySecondFactor).Methods(http.MethodPost)
+	r.HandleFunc("/login/verify/passkey/begin", authHandler.BeginSecondFactorPasskey).Methods(http.MethodPost)
+	r.HandleFunc("/login/passkey/begin", authHandler.BeginPasskeyLogin).Methods(http.MethodPost)
+	r.HandleFunc("/login/passkey/finish", authHandler.FinishPasskeyLogin).Methods(http.MethodPost)
 	r.HandleFunc("/signup", authHandler.Signup).Methods(http.MethodPost)
+	r.HandleFunc("/logout", authHandler.Logout).Methods(http.MethodPost)
 	r.HandleFunc("/users/search", authHandler.SearchUsers).Methods(http.MethodGet)
-
-	r.HandleFunc("/oauth/github/url", authHandler.GetGithubOAuthURL).Methods(http.MethodPost)
-	r.HandleFunc("/oauth/github/callback", authHandler.GithubOAuthCallback).Methods(http.MethodGet)
+	r.HandleFunc("/users/{id}", authHandler.GetUser).Methods(http.MethodGet)
+	r.HandleFunc("/me", authHandler.GetMe).Methods(http.MethodGet)
+	r.HandleFunc("/me", authHandler.UpdateMe).Methods(http.MethodPatch)
+	r.HandleFunc("/me/username", authHandler.ChangeUsername).Methods(http.MethodPost)
+	r.HandleFunc("/me/privacy", authHandler.GetPrivacy).Methods(http.MethodGet)
+	r.HandleFunc("/me/privacy", authHandler.UpdatePrivacy).Methods(http.MethodPatch)
+	r.HandleFunc("/me/sessions", authHandler.ListSessions).Methods(http.MethodGet)
+	r.HandleFunc("/me/sessions/revoke-others", authHandler.RevokeOtherSessions).Methods(http.MethodPost)
+	r.HandleFunc("/me/sessions/{login_id}", authHandler.RevokeSession).Methods(http.MethodDelete)
+	r.HandleFunc("/me/password", authHandler.ChangePassword).Methods(http.MethodPost)
+	r.HandleFunc("/me/email", authHandler.SetRecoveryEmail).Methods(http.MethodPost)
+	r.HandleFunc("/me/2fa/totp", authHandler.EnrollTOTP).Methods(http.MethodPost)
+	r.HandleFunc("/me/2fa/totp/confirm", authHandler.ConfirmTOTP).Methods(http.MethodPost)
+	r.HandleFunc("/me/2fa/totp/disable", authHandler.DisableTOTP).Methods(http.MethodPost)
+	r.HandleFunc("/me/passkeys", authHandler.ListPasskeys).Methods(http.MethodGet)
+	r.HandleFunc("/me/passkeys", authHandler.FinishPasskeyRegistration).Methods(http.MethodPost)
+	r.HandleFunc("/me/passkeys/begin", authHandler.BeginPasskeyRegistration).Methods(http.MethodPost)
+	r.HandleFunc("/me/passkeys/{id}", authHandler.DeletePasskey).Methods(http.MethodDelete)
+	r.HandleFunc("/password/forgot", authHandler.ForgotPassword).Methods(http.MethodPost)
+	r.HandleFunc("/password/reset", authHandler.ResetPassword).Methods(http.MethodPost)
+
+	r.HandleFunc("/oauth/{provider}/url", authHandler.GetOAuthURL).Methods(http.MethodPost)
+	r.HandleFunc("/oauth/{provider}/callback", authHandler.OAuthCallback).Methods(http.MethodGet)
 	r.HandleFunc("/token/exchange", authHandler.ExchangeToken).Methods(http.MethodPost)
+	r.HandleFunc("/token/refresh", authHandler.RefreshToken).Methods(http.MethodPost)
 
 	r.HandleFunc("/keys/setup", authHandler.SetupKeys).Methods(http.MethodPost)
 	r.HandleFunc("/keys/me", authHandler.GetMyKeys).Methods(http.MethodGet)
@@ -91,6 +124,19 @@ func main() {
 	r.HandleFunc("/friends/request", friendshipHandler.SendFriendRequest).Methods(http.MethodPost)
 	r.HandleFunc("/friends/accept", friendshipHandler.AcceptFriendRequest).Methods(http.MethodPost)
 	r.HandleFunc("/friends/reject", friendshipHandler.RejectFriendRequest).Methods(http.MethodPost)
+	r.HandleFunc("/friends/cancel", friendshipHandler.CancelFriendRequest).Methods(http.MethodPost)
+	r.HandleFunc("/friends/requests", friendshipHandler.ListFriendRequests).Methods(http.MethodGet)
+	r.HandleFunc("/friends/remove", friendshipHandler.RemoveFriend).Methods(http.MethodPost)
+	r.HandleFunc("/friends/block", friendshipHandler.BlockUser).Methods(http.MethodPost)
+	r.HandleFunc("/friends/unblock", friendshipHandler.UnblockUser).Methods(http.MethodPost)
+	r.HandleFunc("/friends/blocked", friendshipHandler.ListBlocked).Methods(http.MethodGet)
+	r.HandleFunc("/friends/suggestions", friendshipHandler.SuggestFriends).Methods(http.MethodGet)
+	r.HandleFunc("/friends/labels", friendshipHandler.ListLabels).Methods(http.MethodGet)
+	r.HandleFunc("/friends/labels", friendshipHandler.CreateLabel).Methods(http.MethodPost)
+	r.HandleFunc("/friends/labels/{id}", friendshipHandler.RenameLabel).Methods(http.MethodPatch)
+	r.HandleFunc("/friends/labels/{id}", friendshipHandler.DeleteLabel).Methods(http.MethodDelete)
+	r.HandleFunc("/friends/labels/{id}/members/add", friendshipHandler.AddLabelMembers).Methods(http.MethodPost)
+	r.HandleFunc("/friends/labels/{id}/members/remove", friendshipHandler.RemoveLabelMembers).Methods(http.MethodPost)
 	r.HandleFunc("/friends", friendshipHandler.GetFriends).Methods(http.MethodGet)
 	r.HandleFunc("/notifications/read", notificationHandler.MarkNotificationRead).Methods(http.MethodPost)
 	r.HandleFunc("/sessions/notification", sessionHandler.NotificationSession).Methods(http.MethodGet)
@@ -98,13 +144,34 @@ func main() {
 	r.HandleFunc("/conversations", chatHandler.CreateConversation).Methods(http.MethodPost)
 	r.HandleFunc("/conversations", chatHandler.GetConversations).Methods(http.MethodGet)
 	r.HandleFunc("/conversations/search", chatHandler.GetConversationsByName).Methods(http.MethodGet)
+	r.HandleFunc("/conversations/messages", chatHandler.GetMessages).Methods(http.MethodGet)
+	r.HandleFunc("/conversations/messages/search", chatHandler.SearchMessages).Methods(http.MethodPost)
+	r.HandleFunc("/conversations/members/add", chatHandler.AddMembers).Methods(http.MethodPost)
+	r.HandleFunc("/conversations/members/remove", chatHandler.RemoveMember).Methods(http.MethodPost)
+	r.HandleFunc("/conversations/rename", chatHandler.RenameConversation).Methods(http.MethodPost)
+	r.HandleFunc("/conversations/role", chatHandler.SetMemberRole).Methods(http.MethodPost)
+	r.HandleFunc("/conversations/slow-mode", chatHandler.SetSlowMode).Methods(http.MethodPost)
+	r.HandleFunc("/conversations/clear", chatHandler.ClearHistory).Methods(http.MethodPost)
+	r.HandleFunc("/conversations/delete", chatHandler.DeleteConversation).Methods(http.MethodPost)
+	r.HandleFunc("/conversations/{id:[0-9]+}", chatHandler.GetConversation).Methods(http.MethodGet)
+	r.HandleFunc("/conversations/{id:[0-9]+}/export", chatHandler.ExportConversation).Methods(http.MethodGet)
+	r.HandleFunc("/conversations/exports", chatHandler.StartConversationExport).Methods(http.MethodPost)
+	r.HandleFunc("/conversations/exports/download", chatHandler.DownloadConversationExport).Methods(http.MethodGet)
+	r.HandleFunc("/conversations/exports/{export_id}", chatHandler.GetConversationExport).Methods(http.MethodGet)
+
+	r.HandleFunc("/reports", moderationHandler.Report).Methods(http.MethodPost)
+	r.HandleFunc("/admin/reports", moderationHandler.ListReports).Methods(http.MethodGet)
+	r.HandleFunc("/admin/reports/{id:[0-9]+}/triage", moderationHandler.TriageReport).Methods(http.MethodPost)
+	r.HandleFunc("/admin/reports/{id:[0-9]+}/resolve", moderationHandler.ResolveReport).Methods(http.MethodPost)
+	r.HandleFunc("/admin/users/{username}/actions", moderationHandler.ListUserActions).Methods(http.MethodGet)
+	r.HandleFunc("/admin/users/{username}/actions", moderationHandler.ModerateUser).Methods(http.MethodPost)
 
 	// setup cors
 	frontendURL := lib.Getenv("FRONTEND_URL", "")
 	lib.InfoLog.Printf("Allowing CORS for frontend URL: %s", frontendURL)
 	cors := gorhandlers.CORS(
 		gorhandlers.AllowedOrigins([]string{frontendURL}),
-		gorhandlers.AllowedMethods([]string{"GET", "POST", "OPTIONS"}),
+		gorhandlers.AllowedMethods([]string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"}),
 		gorhandlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
 	)
 
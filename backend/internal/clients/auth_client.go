@@ -40,46 +40,51 @@ func (a *AuthClient) Close() {
 	a.conn.Close()
 }
 
-// Login forwards the login request to the backend via gRPC.
-func (a *AuthClient) Login(ctx context.Context, username, password string) (token string, err error) {
-	resp, err := a.client.Login(ctx, &auth.LoginRequest{
-		UserName: username,
-		Passwd:   password,
+// Login forwards the login request to the backend via gRPC. ctx should carry
+// the client's device info (see lib.WithClientInfo). With two-factor sign-in
+// on, the response carries a challenge token instead of the session tokens.
+func (a *AuthClient) Login(ctx context.Context, username, password, deviceName string) (*auth.LoginResponse, error) {
+	return a.client.Login(ctx, &auth.LoginRequest{
+		UserName:   username,
+		Passwd:     password,
+		DeviceName: deviceName,
 	})
-	if err != nil {
-		return "", err
-	}
-	return resp.Token, nil
 }
 
-// Signup forwards the signup request to the backend via gRPC.
-func (a *AuthClient) Signup(ctx context.Context, username, password string) error {
+// Signup forwards the signup request to the backend via gRPC. email is the
+// optional recovery email.
+func (a *AuthClient) Signup(ctx context.Context, username, password, email string) error {
 	_, err := a.client.Signup(ctx, &auth.SignupRequest{
 		UserName: username,
 		Passwd:   password,
+		Email:    email,
 	})
 	return err
 }
 
 // SearchUsers asks the backend to search for users by username or display name.
-func (a *AuthClient) SearchUsers(ctx context.Context, token, query string) (*auth.SearchUsersResponse, error) {
+// cursor is the next_cursor of a previous page, or empty for the first page.
+func (a *AuthClient) SearchUsers(ctx context.Context, token, query string, limit int32, cursor string) (*auth.SearchUsersResponse, error) {
 	return a.client.SearchUsers(lib.WithToken(ctx, token), &auth.SearchUsersRequest{
-		Query: query,
+		Query:  query,
+		Limit:  limit,
+		Cursor: cursor,
 	})
 }
 
-// GetGithubOAuthURL retrieves the GitHub OAuth authorization URL.
-func (a *AuthClient) GetGithubOAuthURL(ctx context.Context) (string, error) {
-	resp, err := a.client.GetGithubOAuthURL(ctx, &auth.GetGithubOAuthURLRequest{})
+// GetOAuthURL starts a sign-in with the named provider and returns the
+// authorization URL to send the user to.
+func (a *AuthClient) GetOAuthURL(ctx context.Context, provider string) (string, error) {
+	resp, err := a.client.GetOAuthURL(ctx, &auth.GetOAuthURLRequest{Provider: provider})
 	if err != nil {
 		return "", err
 	}
 	return resp.Url, nil
 }
 
-// GithubOAuthCallback exchanges the GitHub OAuth code for a short-lived JWT.
-func (a *AuthClient) GithubOAuthCallback(ctx context.Context, code string) (shortLivedToken, username string, err error) {
-	resp, err := a.client.GithubOAuthCallback(ctx, &auth.GithubOAuthCallbackRequest{Code: code})
+// OAuthCallback exchanges the provider's code and state for a short-lived JWT.
+func (a *AuthClient) OAuthCallback(ctx context.Context, provider, code, state string) (shortLivedToken, username string, err error) {
+	resp, err := a.client.OAuthCallback(ctx, &auth.OAuthCallbackRequest{Provider: provider, Code: code, State: state})
 	if err != nil {
 		return "", "", err
 	}
@@ -87,15 +92,173 @@ func (a *AuthClient) GithubOAuthCallback(ctx context.Context, code string) (shor
 }
 
 // ExchangeToken exchanges a short-lived OAuth token for a long-lived session token.
-func (a *AuthClient) ExchangeToken(ctx context.Context, shortLivedToken string) (longLivedToken, username string, err error) {
-	resp, err := a.client.ExchangeToken(
+// Like Login, ctx should carry the client's device info.
+func (a *AuthClient) ExchangeToken(ctx context.Context, shortLivedToken, deviceName string) (*auth.ExchangeTokenResponse, error) {
+	return a.client.ExchangeToken(
 		lib.WithToken(ctx, shortLivedToken),
-		&auth.ExchangeTokenRequest{},
+		&auth.ExchangeTokenRequest{DeviceName: deviceName},
 	)
+}
+
+// RefreshToken trades a refresh token for a new token pair via gRPC. No access
+// token is sent: the one being renewed may already have expired.
+func (a *AuthClient) RefreshToken(ctx context.Context, refreshToken string) (*auth.RefreshTokenResponse, error) {
+	return a.client.RefreshToken(ctx, &auth.RefreshTokenRequest{RefreshToken: refreshToken})
+}
+
+// Logout signs out the login of token via gRPC.
+func (a *AuthClient) Logout(ctx context.Context, token string) error {
+	_, err := a.client.Logout(lib.WithToken(ctx, token), &auth.LogoutRequest{})
+	return err
+}
+
+// ListSessions lists the places the caller is signed in via gRPC.
+func (a *AuthClient) ListSessions(ctx context.Context, token string) (*auth.ListSessionsResponse, error) {
+	return a.client.ListSessions(lib.WithToken(ctx, token), &auth.ListSessionsRequest{})
+}
+
+// RevokeSession signs out one of the caller's logins, or all but the current
+// one, via gRPC. It returns how many logins were signed out.
+func (a *AuthClient) RevokeSession(ctx context.Context, token, loginID string, allOthers bool) (int32, error) {
+	resp, err := a.client.RevokeSession(lib.WithToken(ctx, token), &auth.RevokeSessionRequest{
+		LoginId:   loginID,
+		AllOthers: allOthers,
+	})
 	if err != nil {
-		return "", "", err
+		return 0, err
+	}
+	return resp.RevokedCount, nil
+}
+
+// ChangePassword replaces the caller's password via gRPC. It returns how many
+// other logins were signed out.
+func (a *AuthClient) ChangePassword(ctx context.Context, token, currentPassword, newPassword string) (int32, error) {
+	resp, err := a.client.ChangePassword(lib.WithToken(ctx, token), &auth.ChangePasswordRequest{
+		CurrentPassword: currentPassword,
+		NewPassword:     newPassword,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return resp.RevokedCount, nil
+}
+
+// SetRecoveryEmail sets where the caller's password reset links go via gRPC.
+func (a *AuthClient) SetRecoveryEmail(ctx context.Context, token, email, password string) error {
+	_, err := a.client.SetRecoveryEmail(lib.WithToken(ctx, token), &auth.SetRecoveryEmailRequest{
+		Email:    email,
+		Password: password,
+	})
+	return err
+}
+
+// RequestPasswordReset asks the backend to mail a reset link to email.
+func (a *AuthClient) RequestPasswordReset(ctx context.Context, email string) error {
+	_, err := a.client.RequestPasswordReset(ctx, &auth.RequestPasswordResetRequest{Email: email})
+	return err
+}
+
+// ResetPassword sets a new password with a mailed reset token via gRPC.
+func (a *AuthClient) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	_, err := a.client.ResetPassword(ctx, &auth.ResetPasswordRequest{
+		Token:       resetToken,
+		NewPassword: newPassword,
+	})
+	return err
+}
+
+// VerifySecondFactor finishes a two-factor sign-in via gRPC with either a
+// code or a passkey credential JSON from BeginSecondFactorPasskey. Like
+// Login, ctx should carry the client's device info.
+func (a *AuthClient) VerifySecondFactor(ctx context.Context, challengeToken, code, passkeyCredentialJSON string) (*auth.VerifySecondFactorResponse, error) {
+	return a.client.VerifySecondFactor(ctx, &auth.VerifySecondFactorRequest{
+		ChallengeToken:        challengeToken,
+		Code:                  code,
+		PasskeyCredentialJson: passkeyCredentialJSON,
+	})
+}
+
+// EnrollTOTP generates a new authenticator secret for the caller via gRPC.
+func (a *AuthClient) EnrollTOTP(ctx context.Context, token string) (*auth.EnrollTOTPResponse, error) {
+	return a.client.EnrollTOTP(lib.WithToken(ctx, token), &auth.EnrollTOTPRequest{})
+}
+
+// ConfirmTOTP turns two-factor sign-in on via gRPC and returns the recovery
+// codes.
+func (a *AuthClient) ConfirmTOTP(ctx context.Context, token, code string) ([]string, error) {
+	resp, err := a.client.ConfirmTOTP(lib.WithToken(ctx, token), &auth.ConfirmTOTPRequest{Code: code})
+	if err != nil {
+		return nil, err
 	}
-	return resp.Token, resp.Username, nil
+	return resp.RecoveryCodes, nil
+}
+
+// DisableTOTP turns two-factor sign-in off via gRPC.
+func (a *AuthClient) DisableTOTP(ctx context.Context, token, code string) error {
+	_, err := a.client.DisableTOTP(lib.WithToken(ctx, token), &auth.DisableTOTPRequest{Code: code})
+	return err
+}
+
+// BeginPasskeyRegistration returns the WebAuthn creation options for a new
+// passkey for the caller via gRPC.
+func (a *AuthClient) BeginPasskeyRegistration(ctx context.Context, token string) (string, error) {
+	resp, err := a.client.BeginPasskeyRegistration(lib.WithToken(ctx, token), &auth.BeginPasskeyRegistrationRequest{})
+	if err != nil {
+		return "", err
+	}
+	return resp.OptionsJson, nil
+}
+
+// FinishPasskeyRegistration stores the passkey the browser created via gRPC.
+func (a *AuthClient) FinishPasskeyRegistration(ctx context.Context, token, credentialJSON, name string) (*auth.Passkey, error) {
+	resp, err := a.client.FinishPasskeyRegistration(lib.WithToken(ctx, token), &auth.FinishPasskeyRegistrationRequest{
+		CredentialJson: credentialJSON,
+		Name:           name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Passkey, nil
+}
+
+// ListPasskeys lists the caller's passkeys via gRPC.
+func (a *AuthClient) ListPasskeys(ctx context.Context, token string) (*auth.ListPasskeysResponse, error) {
+	return a.client.ListPasskeys(lib.WithToken(ctx, token), &auth.ListPasskeysRequest{})
+}
+
+// DeletePasskey removes one of the caller's passkeys via gRPC.
+func (a *AuthClient) DeletePasskey(ctx context.Context, token string, id int64) error {
+	_, err := a.client.DeletePasskey(lib.WithToken(ctx, token), &auth.DeletePasskeyRequest{Id: id})
+	return err
+}
+
+// BeginPasskeyLogin returns the WebAuthn request options for a passwordless
+// sign-in via gRPC.
+func (a *AuthClient) BeginPasskeyLogin(ctx context.Context) (string, error) {
+	resp, err := a.client.BeginPasskeyLogin(ctx, &auth.BeginPasskeyLoginRequest{})
+	if err != nil {
+		return "", err
+	}
+	return resp.OptionsJson, nil
+}
+
+// FinishPasskeyLogin signs in with a passkey via gRPC. Like Login, ctx should
+// carry the client's device info.
+func (a *AuthClient) FinishPasskeyLogin(ctx context.Context, credentialJSON, deviceName string) (*auth.FinishPasskeyLoginResponse, error) {
+	return a.client.FinishPasskeyLogin(ctx, &auth.FinishPasskeyLoginRequest{
+		CredentialJson: credentialJSON,
+		DeviceName:     deviceName,
+	})
+}
+
+// BeginSecondFactorPasskey returns the WebAuthn request options for finishing
+// a two-factor sign-in with a passkey via gRPC.
+func (a *AuthClient) BeginSecondFactorPasskey(ctx context.Context, challengeToken string) (string, error) {
+	resp, err := a.client.BeginSecondFactorPasskey(ctx, &auth.BeginSecondFactorPasskeyRequest{ChallengeToken: challengeToken})
+	if err != nil {
+		return "", err
+	}
+	return resp.OptionsJson, nil
 }
 
 // SetupKeys forwards the E2EE key setup request to the backend via gRPC.
@@ -140,3 +303,29 @@ func (a *AuthClient) GetPublicKeys(ctx context.Context, token string, userIDs []
 	}
 	return m, nil
 }
+
+// GetProfile retrieves a user's profile via gRPC. An empty userID returns the
+// caller's own profile.
+func (a *AuthClient) GetProfile(ctx context.Context, token, userID string) (*auth.Profile, error) {
+	return a.client.GetProfile(lib.WithToken(ctx, token), &auth.GetProfileRequest{UserId: userID})
+}
+
+// UpdateProfile changes the caller's profile via gRPC.
+func (a *AuthClient) UpdateProfile(ctx context.Context, token string, req *auth.UpdateProfileRequest) (*auth.Profile, error) {
+	return a.client.UpdateProfile(lib.WithToken(ctx, token), req)
+}
+
+// ChangeUsername renames the caller via gRPC.
+func (a *AuthClient) ChangeUsername(ctx context.Context, token, newUsername string) (*auth.ChangeUsernameResponse, error) {
+	return a.client.ChangeUsername(lib.WithToken(ctx, token), &auth.ChangeUsernameRequest{NewUsername: newUsername})
+}
+
+// GetPrivacySettings fetches the caller's privacy settings via gRPC.
+func (a *AuthClient) GetPrivacySettings(ctx context.Context, token string) (*auth.PrivacySettings, error) {
+	return a.client.GetPrivacySettings(lib.WithToken(ctx, token), &auth.GetPrivacySettingsRequest{})
+}
+
+// UpdatePrivacySettings changes the caller's privacy settings via gRPC.
+func (a *AuthClient) UpdatePrivacySettings(ctx context.Context, token string, req *auth.UpdatePrivacySettingsRequest) (*auth.PrivacySettings, error) {
+	return a.client.UpdatePrivacySettings(lib.WithToken(ctx, token), req)
+}
@@ -43,11 +43,13 @@ func (c *ChatClient) Close() {
 // CreateConversation forwards a create conversation request to the backend via gRPC.
 // For DMs, membersUsername must contain exactly one peer username.
 // For groups, membersUsername lists all non-caller members; name must be non-empty.
-func (c *ChatClient) CreateConversation(ctx context.Context, token string, isGroup bool, name string, membersUsername []string) (int64, error) {
+// A non-zero labelID adds the caller's friends in that label to the members.
+func (c *ChatClient) CreateConversation(ctx context.Context, token string, isGroup bool, name string, membersUsername []string, labelID int64) (int64, error) {
 	resp, err := c.client.CreateConversation(lib.WithToken(ctx, token), &pb.CreateConversationRequest{
 		IsGroup:         isGroup,
 		Name:            name,
 		MembersUsername: membersUsername,
+		LabelId:         labelID,
 	})
 	if err != nil {
 		return 0, err
@@ -57,12 +59,14 @@ func (c *ChatClient) CreateConversation(ctx context.Context, token string, isGro
 
 // SendMessage sends a message to a conversation via gRPC.
 // messageType defaults to "text" if empty. replyToMessageID is empty string if not a reply.
-func (c *ChatClient) SendMessage(ctx context.Context, token string, conversationID int64, messageID, content, messageType string, replyToMessageID string) (string, error) {
+// searchTokens are the message's blind-index keyword tokens, or nil.
+func (c *ChatClient) SendMessage(ctx context.Context, token string, conversationID int64, messageID, content, messageType string, replyToMessageID string, searchTokens []string) (string, error) {
 	req := &pb.SendMessageRequest{
 		ConversationId: conversationID,
 		MessageId:      messageID,
 		Content:        content,
 		MessageType:    messageType,
+		SearchTokens:   searchTokens,
 	}
 	if replyToMessageID != "" {
 		req.ReplyToMessageId = &replyToMessageID
@@ -101,11 +105,133 @@ func (c *ChatClient) GetConversations(ctx context.Context, token string) (*pb.Ge
 	return c.client.GetConversations(lib.WithToken(ctx, token), &pb.GetConversationsRequest{})
 }
 
-// GetConversationsByName retrieves conversations matching the search pattern via gRPC.
-// For groups: matches conversation name.
-// For DMs: matches the other member's username.
-func (c *ChatClient) GetConversationsByName(ctx context.Context, token string, name string) (*pb.GetConversationsResponse, error) {
+// GetConversationsByName retrieves conversations matching the search text via gRPC.
+// Groups match on their name; any conversation also matches on its other members' names.
+// cursor is the next_cursor of a previous page, or empty for the first page.
+func (c *ChatClient) GetConversationsByName(ctx context.Context, token string, name string, limit int32, cursor string) (*pb.GetConversationsResponse, error) {
 	return c.client.GetConversationsByName(lib.WithToken(ctx, token), &pb.GetConversationsByNameRequest{
-		Name: name,
+		Name:   name,
+		Limit:  limit,
+		Cursor: cursor,
+	})
+}
+
+// GetConversation retrieves a single conversation with its members and the caller's settings via gRPC.
+func (c *ChatClient) GetConversation(ctx context.Context, token string, conversationID int64) (*pb.GetConversationResponse, error) {
+	return c.client.GetConversation(lib.WithToken(ctx, token), &pb.GetConversationRequest{
+		ConversationId: conversationID,
+	})
+}
+
+// GetMessages retrieves a page of stored messages in a conversation via gRPC.
+// cursor is the next_cursor of a previous page, or empty for the first page.
+func (c *ChatClient) GetMessages(ctx context.Context, token string, conversationID int64, limit int32, cursor string) (*pb.GetMessagesResponse, error) {
+	return c.client.GetMessages(lib.WithToken(ctx, token), &pb.GetMessagesRequest{
+		ConversationId: conversationID,
+		Limit:          limit,
+		Cursor:         cursor,
+	})
+}
+
+// SearchMessages finds the caller's messages carrying every blind-index token
+// of their conversation's set via gRPC.
+// cursor is the next_cursor of a previous page, or empty for the first page.
+func (c *ChatClient) SearchMessages(ctx context.Context, token string, tokenSets []*pb.SearchTokenSet, limit int32, cursor string) (*pb.SearchMessagesResponse, error) {
+	return c.client.SearchMessages(lib.WithToken(ctx, token), &pb.SearchMessagesRequest{
+		TokenSets: tokenSets,
+		Limit:     limit,
+		Cursor:    cursor,
+	})
+}
+
+// SetSlowMode sets the slow-mode interval of a group conversation via gRPC; 0 turns it off.
+func (c *ChatClient) SetSlowMode(ctx context.Context, token string, conversationID int64, seconds int32) error {
+	_, err := c.client.SetSlowMode(lib.WithToken(ctx, token), &pb.SetSlowModeRequest{
+		ConversationId: conversationID,
+		Seconds:        seconds,
+	})
+	return err
+}
+
+// AddMembers adds the given usernames to a group conversation via gRPC.
+func (c *ChatClient) AddMembers(ctx context.Context, token string, conversationID int64, membersUsername []string) error {
+	_, err := c.client.AddMembers(lib.WithToken(ctx, token), &pb.AddMembersRequest{
+		ConversationId:  conversationID,
+		MembersUsername: membersUsername,
+	})
+	return err
+}
+
+// RemoveMember removes a member from a group conversation via gRPC.
+// Passing the caller's own username leaves the conversation.
+func (c *ChatClient) RemoveMember(ctx context.Context, token string, conversationID int64, username string) error {
+	_, err := c.client.RemoveMember(lib.WithToken(ctx, token), &pb.RemoveMemberRequest{
+		ConversationId: conversationID,
+		Username:       username,
+	})
+	return err
+}
+
+// RenameConversation renames a group conversation via gRPC.
+func (c *ChatClient) RenameConversation(ctx context.Context, token string, conversationID int64, name string) error {
+	_, err := c.client.RenameConversation(lib.WithToken(ctx, token), &pb.RenameConversationRequest{
+		ConversationId: conversationID,
+		Name:           name,
+	})
+	return err
+}
+
+// SetMemberRole changes a member's role in a group conversation via gRPC.
+func (c *ChatClient) SetMemberRole(ctx context.Context, token string, conversationID int64, username, role string) error {
+	_, err := c.client.SetMemberRole(lib.WithToken(ctx, token), &pb.SetMemberRoleRequest{
+		ConversationId: conversationID,
+		Username:       username,
+		Role:           role,
+	})
+	return err
+}
+
+// ClearHistory hides the conversation's current messages for the caller via gRPC.
+func (c *ChatClient) ClearHistory(ctx context.Context, token string, conversationID int64) error {
+	_, err := c.client.ClearHistory(lib.WithToken(ctx, token), &pb.ClearHistoryRequest{
+		ConversationId: conversationID,
+	})
+	return err
+}
+
+// DeleteConversation deletes a conversation for the caller (DM) or everyone (group owner) via gRPC.
+func (c *ChatClient) DeleteConversation(ctx context.Context, token string, conversationID int64) error {
+	_, err := c.client.DeleteConversation(lib.WithToken(ctx, token), &pb.DeleteConversationRequest{
+		ConversationId: conversationID,
+	})
+	return err
+}
+
+// ExportConversation opens a stream that receives the conversation archive via gRPC.
+func (c *ChatClient) ExportConversation(ctx context.Context, token string, conversationID int64) (pb.Chat_ExportConversationClient, error) {
+	return c.client.ExportConversation(lib.WithToken(ctx, token), &pb.ExportConversationRequest{
+		ConversationId: conversationID,
+	})
+}
+
+// StartConversationExport queues a background archive job via gRPC.
+func (c *ChatClient) StartConversationExport(ctx context.Context, token string, conversationID int64) (*pb.ConversationExport, error) {
+	return c.client.StartConversationExport(lib.WithToken(ctx, token), &pb.StartConversationExportRequest{
+		ConversationId: conversationID,
+	})
+}
+
+// GetConversationExport retrieves the state of an export job via gRPC.
+func (c *ChatClient) GetConversationExport(ctx context.Context, token string, exportID string) (*pb.ConversationExport, error) {
+	return c.client.GetConversationExport(lib.WithToken(ctx, token), &pb.GetConversationExportRequest{
+		ExportId: exportID,
+	})
+}
+
+// DownloadConversationExport opens a stream that receives a finished archive.
+// downloadToken comes from the export's download link; no JWT is needed.
+func (c *ChatClient) DownloadConversationExport(ctx context.Context, downloadToken string) (pb.Chat_DownloadConversationExportClient, error) {
+	return c.client.DownloadConversationExport(ctx, &pb.DownloadConversationExportRequest{
+		Token: downloadToken,
 	})
 }
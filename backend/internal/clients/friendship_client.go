@@ -57,8 +57,9 @@ func (c *FriendshipClient) AcceptFriendRequest(ctx context.Context, token, targe
 }
 
 // GetFriends returns the list of accepted friends for the caller.
-func (c *FriendshipClient) GetFriends(ctx context.Context, token string) (*pb.GetFriendsResponse, error) {
-	return c.client.GetFriends(lib.WithToken(ctx, token), &pb.GetFriendsRequest{})
+// A non-zero labelID limits the list to the friends in that label.
+func (c *FriendshipClient) GetFriends(ctx context.Context, token string, labelID int64) (*pb.GetFriendsResponse, error) {
+	return c.client.GetFriends(lib.WithToken(ctx, token), &pb.GetFriendsRequest{LabelId: labelID})
 }
 
 // RejectFriendRequest rejects a pending friend request via gRPC.
@@ -68,3 +69,86 @@ func (c *FriendshipClient) RejectFriendRequest(ctx context.Context, token, targe
 	})
 	return err
 }
+
+// ListFriendRequests returns a page of the caller's pending friend requests via gRPC.
+// direction is "incoming" or "outgoing"; cursor is the next_cursor of a previous page, or empty.
+func (c *FriendshipClient) ListFriendRequests(ctx context.Context, token, direction string, limit int32, cursor string) (*pb.ListFriendRequestsResponse, error) {
+	return c.client.ListFriendRequests(lib.WithToken(ctx, token), &pb.ListFriendRequestsRequest{
+		Direction: direction,
+		Limit:     limit,
+		Cursor:    cursor,
+	})
+}
+
+// CancelFriendRequest withdraws a friend request the caller sent via gRPC.
+func (c *FriendshipClient) CancelFriendRequest(ctx context.Context, token, targetUsername string) error {
+	_, err := c.client.CancelFriendRequest(lib.WithToken(ctx, token), &pb.FriendRequest{
+		TargetUsername: targetUsername,
+	})
+	return err
+}
+
+// RemoveFriend ends an accepted friendship via gRPC.
+func (c *FriendshipClient) RemoveFriend(ctx context.Context, token, targetUsername string) error {
+	_, err := c.client.RemoveFriend(lib.WithToken(ctx, token), &pb.FriendRequest{
+		TargetUsername: targetUsername,
+	})
+	return err
+}
+
+// BlockUser blocks a user via gRPC.
+func (c *FriendshipClient) BlockUser(ctx context.Context, token, targetUsername string) error {
+	_, err := c.client.BlockUser(lib.WithToken(ctx, token), &pb.FriendRequest{
+		TargetUsername: targetUsername,
+	})
+	return err
+}
+
+// UnblockUser lifts a block via gRPC.
+func (c *FriendshipClient) UnblockUser(ctx context.Context, token, targetUsername string) error {
+	_, err := c.client.UnblockUser(lib.WithToken(ctx, token), &pb.FriendRequest{
+		TargetUsername: targetUsername,
+	})
+	return err
+}
+
+// SuggestFriends returns up to limit people the caller may know via gRPC.
+func (c *FriendshipClient) SuggestFriends(ctx context.Context, token string, limit int32) (*pb.SuggestFriendsResponse, error) {
+	return c.client.SuggestFriends(lib.WithToken(ctx, token), &pb.SuggestFriendsRequest{Limit: limit})
+}
+
+// ListBlocked returns the users the caller has blocked.
+func (c *FriendshipClient) ListBlocked(ctx context.Context, token string) (*pb.ListBlockedResponse, error) {
+	return c.client.ListBlocked(lib.WithToken(ctx, token), &pb.ListBlockedRequest{})
+}
+
+// ListLabels returns the caller's friend labels via gRPC.
+func (c *FriendshipClient) ListLabels(ctx context.Context, token string) (*pb.ListLabelsResponse, error) {
+	return c.client.ListLabels(lib.WithToken(ctx, token), &pb.ListLabelsRequest{})
+}
+
+// CreateLabel creates a friend label via gRPC.
+func (c *FriendshipClient) CreateLabel(ctx context.Context, token, name string) (*pb.FriendLabel, error) {
+	return c.client.CreateLabel(lib.WithToken(ctx, token), &pb.CreateLabelRequest{Name: name})
+}
+
+// RenameLabel renames a friend label via gRPC.
+func (c *FriendshipClient) RenameLabel(ctx context.Context, token string, labelID int64, name string) (*pb.FriendLabel, error) {
+	return c.client.RenameLabel(lib.WithToken(ctx, token), &pb.RenameLabelRequest{LabelId: labelID, Name: name})
+}
+
+// DeleteLabel deletes a friend label via gRPC.
+func (c *FriendshipClient) DeleteLabel(ctx context.Context, token string, labelID int64) error {
+	_, err := c.client.DeleteLabel(lib.WithToken(ctx, token), &pb.DeleteLabelRequest{LabelId: labelID})
+	return err
+}
+
+// AddLabelMembers adds friends to a label via gRPC.
+func (c *FriendshipClient) AddLabelMembers(ctx context.Context, token string, labelID int64, usernames []string) (*pb.FriendLabel, error) {
+	return c.client.AddLabelMembers(lib.WithToken(ctx, token), &pb.LabelMembersRequest{LabelId: labelID, Usernames: usernames})
+}
+
+// RemoveLabelMembers removes users from a label via gRPC.
+func (c *FriendshipClient) RemoveLabelMembers(ctx context.Context, token string, labelID int64, usernames []string) (*pb.FriendLabel, error) {
+	return c.client.RemoveLabelMembers(lib.WithToken(ctx, token), &pb.LabelMembersRequest{LabelId: labelID, Usernames: usernames})
+}
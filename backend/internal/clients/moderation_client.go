@@ -0,0 +1,78 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/zukigit/chat/backend/internal/lib"
+	pb "github.com/zukigit/chat/backend/proto/moderation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ModerationClient wraps the gRPC Moderation client.
+type ModerationClient struct {
+	client pb.ModerationClient
+	conn   *grpc.ClientConn
+}
+
+// NewModerationClient dials the backend gRPC server and returns a ModerationClient.
+// The caller is responsible for calling Close() when done.
+func NewModerationClient(backendAddr string) (*ModerationClient, error) {
+	var opts []grpc.DialOption
+	if lib.Getenv("GRPC_TLS_MODE", "") == "disable" {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, "")))
+	}
+	conn, err := grpc.NewClient(backendAddr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ModerationClient{
+		client: pb.NewModerationClient(conn),
+		conn:   conn,
+	}, nil
+}
+
+// Close releases the underlying gRPC connection.
+func (c *ModerationClient) Close() {
+	c.conn.Close()
+}
+
+// Report files the caller's report against a user or message via gRPC.
+func (c *ModerationClient) Report(ctx context.Context, token string, req *pb.ReportRequest) (*pb.ReportResponse, error) {
+	return c.client.Report(lib.WithToken(ctx, token), req)
+}
+
+// ListReports returns a page of the moderation queue via gRPC.
+// status filters by report status, or is empty for all.
+func (c *ModerationClient) ListReports(ctx context.Context, token, status string, limit int32, afterID int64) (*pb.ListReportsResponse, error) {
+	return c.client.ListReports(lib.WithToken(ctx, token), &pb.ListReportsRequest{
+		Status:  status,
+		Limit:   limit,
+		AfterId: afterID,
+	})
+}
+
+// TriageReport assigns an open report to the caller via gRPC.
+func (c *ModerationClient) TriageReport(ctx context.Context, token string, reportID int64) error {
+	_, err := c.client.TriageReport(lib.WithToken(ctx, token), &pb.TriageReportRequest{ReportId: reportID})
+	return err
+}
+
+// ResolveReport closes a report, optionally acting on the reported user, via gRPC.
+func (c *ModerationClient) ResolveReport(ctx context.Context, token string, req *pb.ResolveReportRequest) (*pb.ResolveReportResponse, error) {
+	return c.client.ResolveReport(lib.WithToken(ctx, token), req)
+}
+
+// ModerateUser acts on a user without a report via gRPC.
+func (c *ModerationClient) ModerateUser(ctx context.Context, token string, req *pb.ModerateUserRequest) error {
+	_, err := c.client.ModerateUser(lib.WithToken(ctx, token), req)
+	return err
+}
+
+// ListUserActions returns a user's moderation record via gRPC.
+func (c *ModerationClient) ListUserActions(ctx context.Context, token, username string) (*pb.ListUserActionsResponse, error) {
+	return c.client.ListUserActions(lib.WithToken(ctx, token), &pb.ListUserActionsRequest{Username: username})
+}
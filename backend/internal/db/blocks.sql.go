@@ -0,0 +1,122 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: blocks.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const blockUser = `-- name: BlockUser :execrows
+INSERT INTO blocks (blocker_id, blocked_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type BlockUserParams struct {
+	BlockerID uuid.UUID `json:"blocker_id"`
+	BlockedID uuid.UUID `json:"blocked_id"`
+}
+
+func (q *Queries) BlockUser(ctx context.Context, arg BlockUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, blockUser, arg.BlockerID, arg.BlockedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getBlockState = `-- name: GetBlockState :one
+SELECT
+    EXISTS (SELECT 1 FROM blocks b WHERE b.blocker_id = $1 AND b.blocked_id = $2) AS has_blocked,
+    EXISTS (SELECT 1 FROM blocks b WHERE b.blocker_id = $2 AND b.blocked_id = $1) AS is_blocked_by
+`
+
+type GetBlockStateParams struct {
+	UserID  uuid.UUID `json:"user_id"`
+	OtherID uuid.UUID `json:"other_id"`
+}
+
+type GetBlockStateRow struct {
+	HasBlocked  bool `json:"has_blocked"`
+	IsBlockedBy bool `json:"is_blocked_by"`
+}
+
+// Reports whether user_id has blocked other_id and whether other_id has blocked user_id.
+func (q *Queries) GetBlockState(ctx context.Context, arg GetBlockStateParams) (GetBlockStateRow, error) {
+	row := q.db.QueryRowContext(ctx, getBlockState, arg.UserID, arg.OtherID)
+	var i GetBlockStateRow
+	err := row.Scan(&i.HasBlocked, &i.IsBlockedBy)
+	return i, err
+}
+
+const listBlocked = `-- name: ListBlocked :many
+SELECT u.user_id, u.user_name, u.display_name, u.avatar_url, b.created_at AS blocked_at
+FROM blocks b
+JOIN users u ON u.user_id = b.blocked_id
+WHERE b.blocker_id = $1
+ORDER BY b.created_at DESC, u.user_id
+`
+
+type ListBlockedRow struct {
+	UserID      uuid.UUID      `json:"user_id"`
+	UserName    string         `json:"user_name"`
+	DisplayName sql.NullString `json:"display_name"`
+	AvatarUrl   sql.NullString `json:"avatar_url"`
+	BlockedAt   time.Time      `json:"blocked_at"`
+}
+
+// Returns the users blocked by blocker_id, most recently blocked first.
+func (q *Queries) ListBlocked(ctx context.Context, blockerID uuid.UUID) ([]ListBlockedRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBlocked, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBlockedRow
+	for rows.Next() {
+		var i ListBlockedRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.UserName,
+			&i.DisplayName,
+			&i.AvatarUrl,
+			&i.BlockedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unblockUser = `-- name: UnblockUser :execrows
+DELETE FROM blocks
+WHERE blocker_id = $1
+  AND blocked_id = $2
+`
+
+type UnblockUserParams struct {
+	BlockerID uuid.UUID `json:"blocker_id"`
+	BlockedID uuid.UUID `json:"blocked_id"`
+}
+
+func (q *Queries) UnblockUser(ctx context.Context, arg UnblockUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unblockUser, arg.BlockerID, arg.BlockedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
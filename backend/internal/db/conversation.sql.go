@@ -62,6 +62,51 @@ func (q *Queries) AddMemberWithRole(ctx context.Context, arg AddMemberWithRolePa
 	return i, err
 }
 
+const claimSendSlot = `-- name: ClaimSendSlot :execrows
+UPDATE conversation_members cm
+SET last_sent_at = NOW()
+FROM conversations c
+WHERE c.id = cm.conversation_id
+  AND cm.conversation_id = $1
+  AND cm.user_id = $2
+  AND (c.slow_mode_seconds = 0
+       OR cm.role IN ('admin', 'owner')
+       OR cm.last_sent_at IS NULL
+       OR cm.last_sent_at <= NOW() - make_interval(secs => c.slow_mode_seconds))
+`
+
+type ClaimSendSlotParams struct {
+	ConversationID int64     `json:"conversation_id"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+// Records a send by the member unless slow mode applies to them and their
+// previous send is too recent. The row lock serialises concurrent sends by
+// the same member, so zero rows affected means the member must wait.
+func (q *Queries) ClaimSendSlot(ctx context.Context, arg ClaimSendSlotParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimSendSlot, arg.ConversationID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const clearConversationHistory = `-- name: ClearConversationHistory :exec
+UPDATE conversation_members
+SET cleared_at = NOW()
+WHERE conversation_id = $1 AND user_id = $2
+`
+
+type ClearConversationHistoryParams struct {
+	ConversationID int64     `json:"conversation_id"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) ClearConversationHistory(ctx context.Context, arg ClearConversationHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, clearConversationHistory, arg.ConversationID, arg.UserID)
+	return err
+}
+
 const createConversation = `-- name: CreateConversation :one
 INSERT INTO conversations (is_group, name)
 VALUES ($1, $2)
@@ -73,9 +118,17 @@ type CreateConversationParams struct {
 	Name    sql.NullString `json:"name"`
 }
 
-func (q *Queries) CreateConversation(ctx context.Context, arg CreateConversationParams) (Conversation, error) {
+type CreateConversationRow struct {
+	ID        int64          `json:"id"`
+	IsGroup   bool           `json:"is_group"`
+	Name      sql.NullString `json:"name"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (q *Queries) CreateConversation(ctx context.Context, arg CreateConversationParams) (CreateConversationRow, error) {
 	row := q.db.QueryRowContext(ctx, createConversation, arg.IsGroup, arg.Name)
-	var i Conversation
+	var i CreateConversationRow
 	err := row.Scan(
 		&i.ID,
 		&i.IsGroup,
@@ -105,8 +158,18 @@ func (q *Queries) CreateDmPeer(ctx context.Context, arg CreateDmPeerParams) (DmP
 	return i, err
 }
 
+const deleteConversation = `-- name: DeleteConversation :exec
+DELETE FROM conversations
+WHERE id = $1
+`
+
+func (q *Queries) DeleteConversation(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteConversation, id)
+	return err
+}
+
 const getConversation = `-- name: GetConversation :one
-SELECT id, is_group, name, created_at, updated_at
+SELECT id, is_group, name, created_at, updated_at, slow_mode_seconds
 FROM conversations
 WHERE id = $1
 LIMIT 1
@@ -121,13 +184,45 @@ func (q *Queries) GetConversation(ctx context.Context, id int64) (Conversation,
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SlowModeSeconds,
+	)
+	return i, err
+}
+
+const getConversationMember = `-- name: GetConversationMember :one
+SELECT conversation_id, user_id, role, last_read_message_id, last_delivered_message_id, joined_at, cleared_at, hidden_at, last_sent_at
+FROM conversation_members
+WHERE conversation_id = $1
+  AND user_id = $2
+LIMIT 1
+`
+
+type GetConversationMemberParams struct {
+	ConversationID int64     `json:"conversation_id"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+// Returns the caller's own membership row; sql.ErrNoRows means not a member.
+func (q *Queries) GetConversationMember(ctx context.Context, arg GetConversationMemberParams) (ConversationMember, error) {
+	row := q.db.QueryRowContext(ctx, getConversationMember, arg.ConversationID, arg.UserID)
+	var i ConversationMember
+	err := row.Scan(
+		&i.ConversationID,
+		&i.UserID,
+		&i.Role,
+		&i.LastReadMessageID,
+		&i.LastDeliveredMessageID,
+		&i.JoinedAt,
+		&i.ClearedAt,
+		&i.HiddenAt,
+		&i.LastSentAt,
 	)
 	return i, err
 }
 
 const getConversationMembers = `-- name: GetConversationMembers :many
-SELECT cm.conversation_id, cm.user_id, cm.joined_at,
-       u.user_id, u.user_name, u.display_name, u.avatar_url, u.last_seen_at
+SELECT cm.conversation_id, cm.user_id, cm.role, cm.joined_at,
+       u.user_id, u.user_name, u.display_name, u.avatar_url, u.is_e2ee_ready, u.last_seen_at
 FROM conversation_members cm
 JOIN users u ON u.user_id = cm.user_id
 WHERE cm.conversation_id = $1
@@ -136,11 +231,13 @@ WHERE cm.conversation_id = $1
 type GetConversationMembersRow struct {
 	ConversationID int64          `json:"conversation_id"`
 	UserID         uuid.UUID      `json:"user_id"`
+	Role           MemberRole     `json:"role"`
 	JoinedAt       time.Time      `json:"joined_at"`
 	UserID_2       uuid.UUID      `json:"user_id_2"`
 	UserName       string         `json:"user_name"`
 	DisplayName    sql.NullString `json:"display_name"`
 	AvatarUrl      sql.NullString `json:"avatar_url"`
+	IsE2eeReady    bool           `json:"is_e2ee_ready"`
 	LastSeenAt     sql.NullTime   `json:"last_seen_at"`
 }
 
@@ -156,11 +253,13 @@ func (q *Queries) GetConversationMembers(ctx context.Context, conversationID int
 		if err := rows.Scan(
 			&i.ConversationID,
 			&i.UserID,
+			&i.Role,
 			&i.JoinedAt,
 			&i.UserID_2,
 			&i.UserName,
 			&i.DisplayName,
 			&i.AvatarUrl,
+			&i.IsE2eeReady,
 			&i.LastSeenAt,
 		); err != nil {
 			return nil, err
@@ -176,47 +275,145 @@ func (q *Queries) GetConversationMembers(ctx context.Context, conversationID int
 	return items, nil
 }
 
+const getConversationReceipts = `-- name: GetConversationReceipts :many
+SELECT cm.user_id, cm.last_read_message_id, cm.last_delivered_message_id,
+    COALESCE(us.read_receipts, TRUE)::bool AS read_receipts
+FROM conversation_members cm
+LEFT JOIN user_settings us ON us.user_id = cm.user_id
+WHERE cm.conversation_id = $1
+`
+
+type GetConversationReceiptsRow struct {
+	UserID                 uuid.UUID     `json:"user_id"`
+	LastReadMessageID      uuid.NullUUID `json:"last_read_message_id"`
+	LastDeliveredMessageID uuid.NullUUID `json:"last_delivered_message_id"`
+	ReadReceipts           bool          `json:"read_receipts"`
+}
+
+// read_receipts is the member's privacy setting; when false their read
+// position is private.
+func (q *Queries) GetConversationReceipts(ctx context.Context, conversationID int64) ([]GetConversationReceiptsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getConversationReceipts, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetConversationReceiptsRow
+	for rows.Next() {
+		var i GetConversationReceiptsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.LastReadMessageID,
+			&i.LastDeliveredMessageID,
+			&i.ReadReceipts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getConversationsByName = `-- name: GetConversationsByName :many
-SELECT c.id, c.is_group, c.name, c.created_at, c.updated_at
-FROM conversations c
-JOIN conversation_members cm ON cm.conversation_id = c.id
-WHERE cm.user_id = $1
-  AND (
-    (c.is_group = true AND c.name ILIKE '%' || $2 || '%')
-    OR (c.is_group = false AND EXISTS (
-      SELECT 1 FROM conversation_members cm2
-      JOIN users u ON u.user_id = cm2.user_id
-      WHERE cm2.conversation_id = c.id
-        AND cm2.user_id != $1
-        AND u.user_name ILIKE '%' || $2 || '%'
-    ))
-  )
-ORDER BY c.updated_at DESC
+WITH matches AS (
+  SELECT c.id, c.is_group, c.name, c.created_at, c.updated_at,
+      (lower(COALESCE(c.name, '')) = lower($5::text) OR EXISTS (
+        SELECT 1 FROM conversation_members cm2
+        JOIN users u ON u.user_id = cm2.user_id
+        WHERE cm2.conversation_id = c.id
+          AND cm2.user_id != $6
+          AND lower(u.user_name) = lower($5::text)
+      ))::int AS exact_match,
+      GREATEST(
+        similarity(COALESCE(c.name, ''), $5::text),
+        COALESCE((
+          SELECT MAX(GREATEST(similarity(u.user_name, $5::text), similarity(COALESCE(u.display_name, ''), $5::text)))
+          FROM conversation_members cm2
+          JOIN users u ON u.user_id = cm2.user_id
+          WHERE cm2.conversation_id = c.id
+            AND cm2.user_id != $6
+        ), 0)
+      )::float8 AS score
+  FROM conversations c
+  JOIN conversation_members cm ON cm.conversation_id = c.id
+  WHERE cm.user_id = $6
+    AND cm.hidden_at IS NULL
+    AND (
+      (c.is_group = true AND (c.name ILIKE '%' || $5::text || '%' OR c.name % $5::text))
+      OR EXISTS (
+        SELECT 1 FROM conversation_members cm2
+        JOIN users u ON u.user_id = cm2.user_id
+        WHERE cm2.conversation_id = c.id
+          AND cm2.user_id != $6
+          AND (u.user_name ILIKE '%' || $5::text || '%'
+            OR u.display_name ILIKE '%' || $5::text || '%'
+            OR u.user_name % $5::text
+            OR u.display_name % $5::text)
+      )
+    )
+)
+SELECT m.id, m.is_group, m.name, m.created_at, m.updated_at, m.exact_match, m.score
+FROM matches m
+WHERE $1::bigint IS NULL
+   OR (m.exact_match, m.score, m.id) < ($2::int, $3::float8, $1::bigint)
+ORDER BY m.exact_match DESC, m.score DESC, m.id DESC
+LIMIT $4
 `
 
 type GetConversationsByNameParams struct {
-	UserID  uuid.UUID      `json:"user_id"`
-	Column2 sql.NullString `json:"column_2"`
+	CursorID    sql.NullInt64   `json:"cursor_id"`
+	CursorExact sql.NullInt32   `json:"cursor_exact"`
+	CursorScore sql.NullFloat64 `json:"cursor_score"`
+	PageLimit   int32           `json:"page_limit"`
+	Query       string          `json:"query"`
+	CallerID    uuid.UUID       `json:"caller_id"`
 }
 
-// Returns conversations matching the search pattern.
-// For groups: matches conversation name.
-// For DMs: matches the other member's username.
-func (q *Queries) GetConversationsByName(ctx context.Context, arg GetConversationsByNameParams) ([]Conversation, error) {
-	rows, err := q.db.QueryContext(ctx, getConversationsByName, arg.UserID, arg.Column2)
+type GetConversationsByNameRow struct {
+	ID         int64          `json:"id"`
+	IsGroup    bool           `json:"is_group"`
+	Name       sql.NullString `json:"name"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	ExactMatch int32          `json:"exact_match"`
+	Score      float64        `json:"score"`
+}
+
+// Returns the caller's conversations matching the search text.
+// Groups match on their name; every conversation also matches on the
+// username or display name of its other members. Results are ranked like
+// SearchUsers (exact matches first, then trigram similarity) and keyset-paged
+// on (exact_match, score, id).
+func (q *Queries) GetConversationsByName(ctx context.Context, arg GetConversationsByNameParams) ([]GetConversationsByNameRow, error) {
+	rows, err := q.db.QueryContext(ctx, getConversationsByName,
+		arg.CursorID,
+		arg.CursorExact,
+		arg.CursorScore,
+		arg.PageLimit,
+		arg.Query,
+		arg.CallerID,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Conversation
+	var items []GetConversationsByNameRow
 	for rows.Next() {
-		var i Conversation
+		var i GetConversationsByNameRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.IsGroup,
 			&i.Name,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ExactMatch,
+			&i.Score,
 		); err != nil {
 			return nil, err
 		}
@@ -236,19 +433,28 @@ SELECT c.id, c.is_group, c.name, c.created_at, c.updated_at
 FROM conversations c
 JOIN conversation_members cm ON cm.conversation_id = c.id
 WHERE cm.user_id = $1
+  AND cm.hidden_at IS NULL
 ORDER BY c.updated_at DESC
 `
 
+type GetConversationsByUserRow struct {
+	ID        int64          `json:"id"`
+	IsGroup   bool           `json:"is_group"`
+	Name      sql.NullString `json:"name"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
 // Returns all conversations a user is a member of, most recently updated first.
-func (q *Queries) GetConversationsByUser(ctx context.Context, userID uuid.UUID) ([]Conversation, error) {
+func (q *Queries) GetConversationsByUser(ctx context.Context, userID uuid.UUID) ([]GetConversationsByUserRow, error) {
 	rows, err := q.db.QueryContext(ctx, getConversationsByUser, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Conversation
+	var items []GetConversationsByUserRow
 	for rows.Next() {
-		var i Conversation
+		var i GetConversationsByUserRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.IsGroup,
@@ -289,6 +495,73 @@ func (q *Queries) GetDmPeer(ctx context.Context, arg GetDmPeerParams) (DmPeer, e
 	return i, err
 }
 
+const getSlowModeRetryAfter = `-- name: GetSlowModeRetryAfter :one
+SELECT GREATEST(0, CEIL(EXTRACT(EPOCH FROM
+         cm.last_sent_at + make_interval(secs => c.slow_mode_seconds) - NOW()) * 1000))::bigint AS retry_after_ms
+FROM conversation_members cm
+JOIN conversations c ON c.id = cm.conversation_id
+WHERE cm.conversation_id = $1
+  AND cm.user_id = $2
+`
+
+type GetSlowModeRetryAfterParams struct {
+	ConversationID int64     `json:"conversation_id"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+// Milliseconds until the member may send again under slow mode.
+func (q *Queries) GetSlowModeRetryAfter(ctx context.Context, arg GetSlowModeRetryAfterParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getSlowModeRetryAfter, arg.ConversationID, arg.UserID)
+	var retry_after_ms int64
+	err := row.Scan(&retry_after_ms)
+	return retry_after_ms, err
+}
+
+const hideConversation = `-- name: HideConversation :exec
+UPDATE conversation_members
+SET hidden_at  = NOW(),
+    cleared_at = NOW()
+WHERE conversation_id = $1 AND user_id = $2
+`
+
+type HideConversationParams struct {
+	ConversationID int64     `json:"conversation_id"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+// Hides the conversation and its current history for one member.
+func (q *Queries) HideConversation(ctx context.Context, arg HideConversationParams) error {
+	_, err := q.db.ExecContext(ctx, hideConversation, arg.ConversationID, arg.UserID)
+	return err
+}
+
+const isDmReadOnly = `-- name: IsDmReadOnly :one
+SELECT (
+  NOT EXISTS (
+    SELECT 1 FROM friendships f
+    WHERE f.user1_userid = d.user1_id
+      AND f.user2_userid = d.user2_id
+      AND f.status = 'accepted'
+  )
+  OR EXISTS (
+    SELECT 1 FROM blocks b
+    WHERE (b.blocker_id = d.user1_id AND b.blocked_id = d.user2_id)
+       OR (b.blocker_id = d.user2_id AND b.blocked_id = d.user1_id)
+  )
+)::boolean AS read_only
+FROM dm_peers d
+WHERE d.conversation_id = $1
+`
+
+// A DM is read-only while its two members are not accepted friends or either
+// has blocked the other. sql.ErrNoRows means the conversation is not a DM.
+func (q *Queries) IsDmReadOnly(ctx context.Context, conversationID int64) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isDmReadOnly, conversationID)
+	var read_only bool
+	err := row.Scan(&read_only)
+	return read_only, err
+}
+
 const isMember = `-- name: IsMember :one
 SELECT EXISTS (
   SELECT 1 FROM conversation_members
@@ -324,6 +597,70 @@ func (q *Queries) RemoveMemberFromConversation(ctx context.Context, arg RemoveMe
 	return err
 }
 
+const renameConversation = `-- name: RenameConversation :exec
+UPDATE conversations
+SET name       = $2,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+type RenameConversationParams struct {
+	ID   int64          `json:"id"`
+	Name sql.NullString `json:"name"`
+}
+
+func (q *Queries) RenameConversation(ctx context.Context, arg RenameConversationParams) error {
+	_, err := q.db.ExecContext(ctx, renameConversation, arg.ID, arg.Name)
+	return err
+}
+
+const setSlowMode = `-- name: SetSlowMode :execrows
+UPDATE conversations
+SET slow_mode_seconds = $1, updated_at = NOW()
+WHERE id = $2 AND is_group = TRUE
+`
+
+type SetSlowModeParams struct {
+	SlowModeSeconds int32 `json:"slow_mode_seconds"`
+	ConversationID  int64 `json:"conversation_id"`
+}
+
+func (q *Queries) SetSlowMode(ctx context.Context, arg SetSlowModeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setSlowMode, arg.SlowModeSeconds, arg.ConversationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const unhideConversation = `-- name: UnhideConversation :exec
+UPDATE conversation_members
+SET hidden_at = NULL
+WHERE conversation_id = $1 AND hidden_at IS NOT NULL
+`
+
+// Makes the conversation visible again for every member that hid it.
+func (q *Queries) UnhideConversation(ctx context.Context, conversationID int64) error {
+	_, err := q.db.ExecContext(ctx, unhideConversation, conversationID)
+	return err
+}
+
+const unhideConversationForUser = `-- name: UnhideConversationForUser :exec
+UPDATE conversation_members
+SET hidden_at = NULL
+WHERE conversation_id = $1 AND user_id = $2 AND hidden_at IS NOT NULL
+`
+
+type UnhideConversationForUserParams struct {
+	ConversationID int64     `json:"conversation_id"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) UnhideConversationForUser(ctx context.Context, arg UnhideConversationForUserParams) error {
+	_, err := q.db.ExecContext(ctx, unhideConversationForUser, arg.ConversationID, arg.UserID)
+	return err
+}
+
 const updateLastDeliveredMessageID = `-- name: UpdateLastDeliveredMessageID :execresult
 UPDATE conversation_members
 SET last_delivered_message_id = $3
@@ -358,3 +695,21 @@ func (q *Queries) UpdateLastReadMessageID(ctx context.Context, arg UpdateLastRea
 	_, err := q.db.ExecContext(ctx, updateLastReadMessageID, arg.ConversationID, arg.UserID, arg.LastReadMessageID)
 	return err
 }
+
+const updateMemberRole = `-- name: UpdateMemberRole :exec
+UPDATE conversation_members
+SET role = $3
+WHERE conversation_id = $1
+  AND user_id = $2
+`
+
+type UpdateMemberRoleParams struct {
+	ConversationID int64      `json:"conversation_id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Role           MemberRole `json:"role"`
+}
+
+func (q *Queries) UpdateMemberRole(ctx context.Context, arg UpdateMemberRoleParams) error {
+	_, err := q.db.ExecContext(ctx, updateMemberRole, arg.ConversationID, arg.UserID, arg.Role)
+	return err
+}
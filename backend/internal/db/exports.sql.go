@@ -0,0 +1,258 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: exports.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const claimPendingConversationExport = `-- name: ClaimPendingConversationExport :one
+UPDATE conversation_exports
+SET status     = 'running',
+    started_at = NOW(),
+    updated_at = NOW()
+WHERE id = (
+  SELECT e.id FROM conversation_exports e
+  WHERE e.status = 'pending'
+     OR (e.status = 'running' AND e.started_at < $1::timestamptz)
+  ORDER BY e.created_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, conversation_id, user_id, status, download_token, file_path, error, expires_at, created_at, updated_at, started_at
+`
+
+// Marks the oldest pending job as running, or takes over a running job whose
+// worker claimed it before stale_before and never finished. SKIP LOCKED lets
+// several backend instances run workers without picking the same job.
+func (q *Queries) ClaimPendingConversationExport(ctx context.Context, staleBefore time.Time) (ConversationExport, error) {
+	row := q.db.QueryRowContext(ctx, claimPendingConversationExport, staleBefore)
+	var i ConversationExport
+	err := row.Scan(
+		&i.ID,
+		&i.ConversationID,
+		&i.UserID,
+		&i.Status,
+		&i.DownloadToken,
+		&i.FilePath,
+		&i.Error,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+	)
+	return i, err
+}
+
+const completeConversationExport = `-- name: CompleteConversationExport :execrows
+UPDATE conversation_exports
+SET status         = 'done',
+    download_token = $1,
+    file_path      = $2,
+    expires_at     = $3,
+    updated_at     = NOW()
+WHERE id = $4
+  AND status = 'running'
+  AND started_at = $5
+`
+
+type CompleteConversationExportParams struct {
+	DownloadToken sql.NullString `json:"download_token"`
+	FilePath      sql.NullString `json:"file_path"`
+	ExpiresAt     sql.NullTime   `json:"expires_at"`
+	ID            uuid.UUID      `json:"id"`
+	StartedAt     sql.NullTime   `json:"started_at"`
+}
+
+// Only the worker holding the current claim (matched on started_at) may
+// finish the job; a job taken over after its lease ran out affects no rows.
+func (q *Queries) CompleteConversationExport(ctx context.Context, arg CompleteConversationExportParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, completeConversationExport,
+		arg.DownloadToken,
+		arg.FilePath,
+		arg.ExpiresAt,
+		arg.ID,
+		arg.StartedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createConversationExport = `-- name: CreateConversationExport :one
+INSERT INTO conversation_exports (conversation_id, user_id)
+VALUES ($1, $2)
+RETURNING id, conversation_id, user_id, status, download_token, file_path, error, expires_at, created_at, updated_at, started_at
+`
+
+type CreateConversationExportParams struct {
+	ConversationID int64     `json:"conversation_id"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) CreateConversationExport(ctx context.Context, arg CreateConversationExportParams) (ConversationExport, error) {
+	row := q.db.QueryRowContext(ctx, createConversationExport, arg.ConversationID, arg.UserID)
+	var i ConversationExport
+	err := row.Scan(
+		&i.ID,
+		&i.ConversationID,
+		&i.UserID,
+		&i.Status,
+		&i.DownloadToken,
+		&i.FilePath,
+		&i.Error,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+	)
+	return i, err
+}
+
+const deleteConversationExports = `-- name: DeleteConversationExports :many
+DELETE FROM conversation_exports
+WHERE conversation_id = $1
+RETURNING file_path
+`
+
+// Removes every job of a conversation that is being deleted and returns
+// their file paths so the caller can delete the archives after commit.
+func (q *Queries) DeleteConversationExports(ctx context.Context, conversationID int64) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, deleteConversationExports, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullString
+	for rows.Next() {
+		var file_path sql.NullString
+		if err := rows.Scan(&file_path); err != nil {
+			return nil, err
+		}
+		items = append(items, file_path)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteExpiredConversationExports = `-- name: DeleteExpiredConversationExports :many
+DELETE FROM conversation_exports
+WHERE expires_at <= NOW()
+RETURNING file_path
+`
+
+// Removes finished jobs whose link has expired and returns their file paths
+// so the caller can delete the archives from disk.
+func (q *Queries) DeleteExpiredConversationExports(ctx context.Context) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, deleteExpiredConversationExports)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullString
+	for rows.Next() {
+		var file_path sql.NullString
+		if err := rows.Scan(&file_path); err != nil {
+			return nil, err
+		}
+		items = append(items, file_path)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const failConversationExport = `-- name: FailConversationExport :exec
+UPDATE conversation_exports
+SET status     = 'failed',
+    error      = $1,
+    updated_at = NOW()
+WHERE id = $2
+  AND status = 'running'
+  AND started_at = $3
+`
+
+type FailConversationExportParams struct {
+	Error     sql.NullString `json:"error"`
+	ID        uuid.UUID      `json:"id"`
+	StartedAt sql.NullTime   `json:"started_at"`
+}
+
+func (q *Queries) FailConversationExport(ctx context.Context, arg FailConversationExportParams) error {
+	_, err := q.db.ExecContext(ctx, failConversationExport, arg.Error, arg.ID, arg.StartedAt)
+	return err
+}
+
+const getConversationExport = `-- name: GetConversationExport :one
+SELECT id, conversation_id, user_id, status, download_token, file_path, error, expires_at, created_at, updated_at, started_at
+FROM conversation_exports
+WHERE id = $1 AND user_id = $2
+`
+
+type GetConversationExportParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetConversationExport(ctx context.Context, arg GetConversationExportParams) (ConversationExport, error) {
+	row := q.db.QueryRowContext(ctx, getConversationExport, arg.ID, arg.UserID)
+	var i ConversationExport
+	err := row.Scan(
+		&i.ID,
+		&i.ConversationID,
+		&i.UserID,
+		&i.Status,
+		&i.DownloadToken,
+		&i.FilePath,
+		&i.Error,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+	)
+	return i, err
+}
+
+const getConversationExportByToken = `-- name: GetConversationExportByToken :one
+SELECT id, conversation_id, user_id, status, download_token, file_path, error, expires_at, created_at, updated_at, started_at
+FROM conversation_exports
+WHERE download_token = $1
+  AND status = 'done'
+  AND expires_at > NOW()
+`
+
+func (q *Queries) GetConversationExportByToken(ctx context.Context, downloadToken sql.NullString) (ConversationExport, error) {
+	row := q.db.QueryRowContext(ctx, getConversationExportByToken, downloadToken)
+	var i ConversationExport
+	err := row.Scan(
+		&i.ID,
+		&i.ConversationID,
+		&i.UserID,
+		&i.Status,
+		&i.DownloadToken,
+		&i.FilePath,
+		&i.Error,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+	)
+	return i, err
+}
@@ -11,8 +11,22 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const deleteFriendRequestLogBefore = `-- name: DeleteFriendRequestLogBefore :execrows
+DELETE FROM friend_request_log
+WHERE sent_at < $1
+`
+
+func (q *Queries) DeleteFriendRequestLogBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFriendRequestLogBefore, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteFriendship = `-- name: DeleteFriendship :exec
 DELETE FROM friendships
 WHERE user1_userid = $1
@@ -24,12 +38,113 @@ type DeleteFriendshipParams struct {
 	User2Userid uuid.UUID `json:"user2_userid"`
 }
 
-// Deletes the friendship row (used when a request is rejected).
+// Deletes the friendship row (used when a request is rejected or a friend is removed).
 func (q *Queries) DeleteFriendship(ctx context.Context, arg DeleteFriendshipParams) error {
 	_, err := q.db.ExecContext(ctx, deleteFriendship, arg.User1Userid, arg.User2Userid)
 	return err
 }
 
+const expireFriendRequests = `-- name: ExpireFriendRequests :many
+DELETE FROM friendships
+WHERE (user1_userid, user2_userid) IN (
+    SELECT f.user1_userid, f.user2_userid
+    FROM friendships f
+    WHERE f.status = 'pending'
+      AND f.created_at < $1
+    ORDER BY f.created_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING user1_userid, user2_userid, initiator_userid
+`
+
+type ExpireFriendRequestsParams struct {
+	Cutoff    time.Time `json:"cutoff"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+type ExpireFriendRequestsRow struct {
+	User1Userid     uuid.UUID `json:"user1_userid"`
+	User2Userid     uuid.UUID `json:"user2_userid"`
+	InitiatorUserid uuid.UUID `json:"initiator_userid"`
+}
+
+// Deletes pending requests created before cutoff, oldest first, at most
+// batch_size of them.
+func (q *Queries) ExpireFriendRequests(ctx context.Context, arg ExpireFriendRequestsParams) ([]ExpireFriendRequestsRow, error) {
+	rows, err := q.db.QueryContext(ctx, expireFriendRequests, arg.Cutoff, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExpireFriendRequestsRow
+	for rows.Next() {
+		var i ExpireFriendRequestsRow
+		if err := rows.Scan(&i.User1Userid, &i.User2Userid, &i.InitiatorUserid); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFriendRequestCounts = `-- name: GetFriendRequestCounts :one
+SELECT
+    COUNT(*) FILTER (WHERE sent_at > NOW() - INTERVAL '1 hour')::int AS last_hour,
+    COUNT(*)::int AS last_day,
+    COALESCE(MIN(sent_at) FILTER (WHERE sent_at > NOW() - INTERVAL '1 hour'), NOW())::timestamptz AS oldest_in_hour,
+    COALESCE(MIN(sent_at), NOW())::timestamptz AS oldest_in_day
+FROM friend_request_log
+WHERE sender_id = $1
+  AND sent_at > NOW() - INTERVAL '1 day'
+`
+
+type GetFriendRequestCountsRow struct {
+	LastHour     int32     `json:"last_hour"`
+	LastDay      int32     `json:"last_day"`
+	OldestInHour time.Time `json:"oldest_in_hour"`
+	OldestInDay  time.Time `json:"oldest_in_day"`
+}
+
+// Counts the requests sender_id sent in the last hour and the last day, with
+// the oldest send time in each window (now when the window is empty).
+func (q *Queries) GetFriendRequestCounts(ctx context.Context, senderID uuid.UUID) (GetFriendRequestCountsRow, error) {
+	row := q.db.QueryRowContext(ctx, getFriendRequestCounts, senderID)
+	var i GetFriendRequestCountsRow
+	err := row.Scan(
+		&i.LastHour,
+		&i.LastDay,
+		&i.OldestInHour,
+		&i.OldestInDay,
+	)
+	return i, err
+}
+
+const getFriendRequestDecline = `-- name: GetFriendRequestDecline :one
+SELECT declined_at
+FROM friend_request_declines
+WHERE requester_id = $1
+  AND addressee_id = $2
+`
+
+type GetFriendRequestDeclineParams struct {
+	RequesterID uuid.UUID `json:"requester_id"`
+	AddresseeID uuid.UUID `json:"addressee_id"`
+}
+
+func (q *Queries) GetFriendRequestDecline(ctx context.Context, arg GetFriendRequestDeclineParams) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getFriendRequestDecline, arg.RequesterID, arg.AddresseeID)
+	var declined_at time.Time
+	err := row.Scan(&declined_at)
+	return declined_at, err
+}
+
 const getFriends = `-- name: GetFriends :many
 SELECT
     CASE
@@ -41,7 +156,13 @@ SELECT
     u.avatar_url AS friend_avatar_url,
     f.status,
     f.created_at,
-    f.updated_at
+    f.updated_at,
+    ARRAY(
+        SELECT m.label_id FROM friend_label_members m
+        JOIN friend_labels l ON l.id = m.label_id
+        WHERE l.owner_id = $1 AND m.user_id = u.user_id
+        ORDER BY m.label_id
+    )::bigint[] AS label_ids
 FROM friendships f
 JOIN users u ON u.user_id = (
     CASE
@@ -51,8 +172,17 @@ JOIN users u ON u.user_id = (
 )
 WHERE (f.user1_userid = $1 OR f.user2_userid = $1)
   AND (f.status = 'accepted' OR (f.status = 'pending' AND f.initiator_userid != $1))
+  AND ($2::bigint IS NULL OR (
+    f.status = 'accepted'
+    AND EXISTS (SELECT 1 FROM friend_label_members m WHERE m.label_id = $2::bigint AND m.user_id = u.user_id)
+  ))
 `
 
+type GetFriendsParams struct {
+	UserID  uuid.UUID     `json:"user_id"`
+	LabelID sql.NullInt64 `json:"label_id"`
+}
+
 type GetFriendsRow struct {
 	FriendUserid      uuid.UUID        `json:"friend_userid"`
 	FriendUsername    string           `json:"friend_username"`
@@ -61,11 +191,14 @@ type GetFriendsRow struct {
 	Status            FriendshipStatus `json:"status"`
 	CreatedAt         time.Time        `json:"created_at"`
 	UpdatedAt         time.Time        `json:"updated_at"`
+	LabelIds          []int64          `json:"label_ids"`
 }
 
-// Returns all friends (accepted or pending) for a user, including their user_id, username, display_name, avatar_url, and status.
-func (q *Queries) GetFriends(ctx context.Context, user1Userid uuid.UUID) ([]GetFriendsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getFriends, user1Userid)
+// Returns all friends (accepted or pending) for a user, including their user_id, username, display_name, avatar_url, and status,
+// plus the IDs of the user's labels each friend is in.
+// When label_id is set, only accepted friends in that label are returned.
+func (q *Queries) GetFriends(ctx context.Context, arg GetFriendsParams) ([]GetFriendsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFriends, arg.UserID, arg.LabelID)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +214,7 @@ func (q *Queries) GetFriends(ctx context.Context, user1Userid uuid.UUID) ([]GetF
 			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			pq.Array(&i.LabelIds),
 		); err != nil {
 			return nil, err
 		}
@@ -123,31 +257,125 @@ func (q *Queries) GetFriendship(ctx context.Context, arg GetFriendshipParams) (F
 	return i, err
 }
 
-const getPendingRequests = `-- name: GetPendingRequests :many
-SELECT user1_userid, user2_userid, initiator_userid, status, created_at, updated_at
-FROM friendships
-WHERE user2_userid = $1
-  AND status = 'pending'
-ORDER BY created_at DESC
+const getMutualFriends = `-- name: GetMutualFriends :many
+WITH caller_friends AS (
+  SELECT (CASE WHEN f.user1_userid = $3 THEN f.user2_userid ELSE f.user1_userid END)::uuid AS friend_id
+  FROM friendships f
+  WHERE (f.user1_userid = $3 OR f.user2_userid = $3)
+    AND f.status = 'accepted'
+),
+mutual AS (
+  SELECT (CASE WHEN f.user1_userid = cf.friend_id THEN f.user2_userid ELSE f.user1_userid END)::uuid AS user_id,
+      cf.friend_id
+  FROM caller_friends cf
+  JOIN friendships f ON (f.user1_userid = cf.friend_id OR f.user2_userid = cf.friend_id)
+    AND f.status = 'accepted'
+)
+SELECT m.user_id,
+    COUNT(*)::int AS mutual_count,
+    (array_agg(u.user_name ORDER BY u.user_name))[1:$1::int]::text[] AS sample
+FROM mutual m
+JOIN users u ON u.user_id = m.friend_id
+WHERE m.user_id = ANY($2::uuid[])
+GROUP BY m.user_id
 `
 
-// Returns incoming friend requests pending for a user.
-func (q *Queries) GetPendingRequests(ctx context.Context, user2Userid uuid.UUID) ([]Friendship, error) {
-	rows, err := q.db.QueryContext(ctx, getPendingRequests, user2Userid)
+type GetMutualFriendsParams struct {
+	SampleSize int32       `json:"sample_size"`
+	UserIds    []uuid.UUID `json:"user_ids"`
+	CallerID   uuid.UUID   `json:"caller_id"`
+}
+
+type GetMutualFriendsRow struct {
+	UserID      uuid.UUID `json:"user_id"`
+	MutualCount int32     `json:"mutual_count"`
+	Sample      []string  `json:"sample"`
+}
+
+// For each of @user_ids, counts the accepted friends they share with
+// @caller_id and returns up to @sample_size of their usernames, alphabetically.
+// Users with no mutual friends are omitted.
+func (q *Queries) GetMutualFriends(ctx context.Context, arg GetMutualFriendsParams) ([]GetMutualFriendsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getMutualFriends, arg.SampleSize, pq.Array(arg.UserIds), arg.CallerID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Friendship
+	var items []GetMutualFriendsRow
 	for rows.Next() {
-		var i Friendship
+		var i GetMutualFriendsRow
+		if err := rows.Scan(&i.UserID, &i.MutualCount, pq.Array(&i.Sample)); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFriendRequests = `-- name: ListFriendRequests :many
+SELECT u.user_id, u.user_name, u.display_name, u.avatar_url, f.created_at
+FROM friendships f
+JOIN users u ON u.user_id = (
+    CASE
+        WHEN f.user1_userid = $1 THEN f.user2_userid
+        ELSE f.user1_userid
+    END
+)
+WHERE (f.user1_userid = $1 OR f.user2_userid = $1)
+  AND f.status = 'pending'
+  AND (f.initiator_userid = $1) = $2::boolean
+  AND ($3::uuid IS NULL
+       OR (f.created_at, u.user_id) < ($4::timestamptz, $3::uuid))
+ORDER BY f.created_at DESC, u.user_id DESC
+LIMIT $5
+`
+
+type ListFriendRequestsParams struct {
+	UserID          uuid.UUID     `json:"user_id"`
+	Outgoing        bool          `json:"outgoing"`
+	CursorUserID    uuid.NullUUID `json:"cursor_user_id"`
+	CursorCreatedAt sql.NullTime  `json:"cursor_created_at"`
+	PageLimit       int32         `json:"page_limit"`
+}
+
+type ListFriendRequestsRow struct {
+	UserID      uuid.UUID      `json:"user_id"`
+	UserName    string         `json:"user_name"`
+	DisplayName sql.NullString `json:"display_name"`
+	AvatarUrl   sql.NullString `json:"avatar_url"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// Returns pending requests involving user_id with the other user's profile:
+// outgoing (user_id sent them) or incoming (sent to user_id).
+// Newest first, keyset-paged on (created_at, other user_id).
+func (q *Queries) ListFriendRequests(ctx context.Context, arg ListFriendRequestsParams) ([]ListFriendRequestsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFriendRequests,
+		arg.UserID,
+		arg.Outgoing,
+		arg.CursorUserID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFriendRequestsRow
+	for rows.Next() {
+		var i ListFriendRequestsRow
 		if err := rows.Scan(
-			&i.User1Userid,
-			&i.User2Userid,
-			&i.InitiatorUserid,
-			&i.Status,
+			&i.UserID,
+			&i.UserName,
+			&i.DisplayName,
+			&i.AvatarUrl,
 			&i.CreatedAt,
-			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -162,6 +390,49 @@ func (q *Queries) GetPendingRequests(ctx context.Context, user2Userid uuid.UUID)
 	return items, nil
 }
 
+const lockFriendRequestSender = `-- name: LockFriendRequestSender :exec
+SELECT pg_advisory_xact_lock(hashtext('friend_request:' || s.sender_id::text))
+FROM (SELECT $1::uuid AS sender_id) s
+`
+
+// Serializes SendFriendRequest per sender so concurrent requests cannot
+// overshoot the rate limits. Held until the transaction ends.
+func (q *Queries) LockFriendRequestSender(ctx context.Context, senderID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, lockFriendRequestSender, senderID)
+	return err
+}
+
+const logFriendRequest = `-- name: LogFriendRequest :exec
+INSERT INTO friend_request_log (sender_id, recipient_id)
+VALUES ($1, $2)
+`
+
+type LogFriendRequestParams struct {
+	SenderID    uuid.UUID `json:"sender_id"`
+	RecipientID uuid.UUID `json:"recipient_id"`
+}
+
+func (q *Queries) LogFriendRequest(ctx context.Context, arg LogFriendRequestParams) error {
+	_, err := q.db.ExecContext(ctx, logFriendRequest, arg.SenderID, arg.RecipientID)
+	return err
+}
+
+const recordFriendRequestDecline = `-- name: RecordFriendRequestDecline :exec
+INSERT INTO friend_request_declines (requester_id, addressee_id)
+VALUES ($1, $2)
+ON CONFLICT (requester_id, addressee_id) DO UPDATE SET declined_at = NOW()
+`
+
+type RecordFriendRequestDeclineParams struct {
+	RequesterID uuid.UUID `json:"requester_id"`
+	AddresseeID uuid.UUID `json:"addressee_id"`
+}
+
+func (q *Queries) RecordFriendRequestDecline(ctx context.Context, arg RecordFriendRequestDeclineParams) error {
+	_, err := q.db.ExecContext(ctx, recordFriendRequestDecline, arg.RequesterID, arg.AddresseeID)
+	return err
+}
+
 const sendFriendRequest = `-- name: SendFriendRequest :one
 INSERT INTO friendships (user1_userid, user2_userid, initiator_userid)
 VALUES ($1, $2, $3)
@@ -191,6 +462,101 @@ func (q *Queries) SendFriendRequest(ctx context.Context, arg SendFriendRequestPa
 	return i, err
 }
 
+const suggestFriends = `-- name: SuggestFriends :many
+WITH caller_friends AS (
+  SELECT (CASE WHEN f.user1_userid = $1 THEN f.user2_userid ELSE f.user1_userid END)::uuid AS friend_id
+  FROM friendships f
+  WHERE (f.user1_userid = $1 OR f.user2_userid = $1)
+    AND f.status = 'accepted'
+),
+candidates AS (
+  SELECT (CASE WHEN f.user1_userid = cf.friend_id THEN f.user2_userid ELSE f.user1_userid END)::uuid AS user_id,
+      1 AS mutual, 0 AS shared_group
+  FROM caller_friends cf
+  JOIN friendships f ON (f.user1_userid = cf.friend_id OR f.user2_userid = cf.friend_id)
+    AND f.status = 'accepted'
+  UNION ALL
+  SELECT other.user_id, 0, 1
+  FROM conversation_members me
+  JOIN conversations c ON c.id = me.conversation_id AND c.is_group
+  JOIN conversation_members other ON other.conversation_id = me.conversation_id
+  WHERE me.user_id = $1
+),
+scored AS (
+  SELECT user_id, SUM(mutual)::int AS mutual_count, SUM(shared_group)::int AS shared_group_count
+  FROM candidates
+  GROUP BY user_id
+)
+SELECT u.user_id, u.user_name, u.display_name, u.avatar_url, s.mutual_count, s.shared_group_count
+FROM scored s
+JOIN users u ON u.user_id = s.user_id
+LEFT JOIN user_settings us ON us.user_id = u.user_id
+WHERE u.user_id != $1
+  AND audience_allows(COALESCE(us.discoverability, 'everyone'), u.user_id, $1)
+  AND audience_allows(COALESCE(us.friend_request_policy, 'everyone'), u.user_id, $1)
+  AND NOT EXISTS (
+    SELECT 1 FROM friendships f
+    WHERE (f.user1_userid = $1 AND f.user2_userid = u.user_id)
+       OR (f.user1_userid = u.user_id AND f.user2_userid = $1)
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM blocks b
+    WHERE (b.blocker_id = $1 AND b.blocked_id = u.user_id)
+       OR (b.blocker_id = u.user_id AND b.blocked_id = $1)
+  )
+ORDER BY s.mutual_count DESC, s.shared_group_count DESC, u.user_name
+LIMIT $2
+`
+
+type SuggestFriendsParams struct {
+	CallerID  uuid.UUID `json:"caller_id"`
+	PageLimit int32     `json:"page_limit"`
+}
+
+type SuggestFriendsRow struct {
+	UserID           uuid.UUID      `json:"user_id"`
+	UserName         string         `json:"user_name"`
+	DisplayName      sql.NullString `json:"display_name"`
+	AvatarUrl        sql.NullString `json:"avatar_url"`
+	MutualCount      int32          `json:"mutual_count"`
+	SharedGroupCount int32          `json:"shared_group_count"`
+}
+
+// Ranks users the caller has no friendship or pending request with by the
+// number of accepted friends they share, then by the number of group
+// conversations they share. Users blocked by or blocking the caller are
+// excluded, as are users whose discoverability or friend request setting does
+// not include the caller.
+func (q *Queries) SuggestFriends(ctx context.Context, arg SuggestFriendsParams) ([]SuggestFriendsRow, error) {
+	rows, err := q.db.QueryContext(ctx, suggestFriends, arg.CallerID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SuggestFriendsRow
+	for rows.Next() {
+		var i SuggestFriendsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.UserName,
+			&i.DisplayName,
+			&i.AvatarUrl,
+			&i.MutualCount,
+			&i.SharedGroupCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateFriendshipStatus = `-- name: UpdateFriendshipStatus :one
 UPDATE friendships
 SET    status     = $3,
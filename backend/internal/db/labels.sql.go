@@ -0,0 +1,280 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: labels.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const addFriendLabelMembers = `-- name: AddFriendLabelMembers :exec
+INSERT INTO friend_label_members (label_id, user_id)
+SELECT $1, unnest($2::uuid[])
+ON CONFLICT DO NOTHING
+`
+
+type AddFriendLabelMembersParams struct {
+	LabelID int64       `json:"label_id"`
+	UserIds []uuid.UUID `json:"user_ids"`
+}
+
+func (q *Queries) AddFriendLabelMembers(ctx context.Context, arg AddFriendLabelMembersParams) error {
+	_, err := q.db.ExecContext(ctx, addFriendLabelMembers, arg.LabelID, pq.Array(arg.UserIds))
+	return err
+}
+
+const createFriendLabel = `-- name: CreateFriendLabel :one
+INSERT INTO friend_labels (owner_id, name)
+VALUES ($1, $2)
+RETURNING id, owner_id, name, created_at, updated_at
+`
+
+type CreateFriendLabelParams struct {
+	OwnerID uuid.UUID `json:"owner_id"`
+	Name    string    `json:"name"`
+}
+
+func (q *Queries) CreateFriendLabel(ctx context.Context, arg CreateFriendLabelParams) (FriendLabel, error) {
+	row := q.db.QueryRowContext(ctx, createFriendLabel, arg.OwnerID, arg.Name)
+	var i FriendLabel
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteFriendLabel = `-- name: DeleteFriendLabel :execrows
+DELETE FROM friend_labels
+WHERE id = $1 AND owner_id = $2
+`
+
+type DeleteFriendLabelParams struct {
+	ID      int64     `json:"id"`
+	OwnerID uuid.UUID `json:"owner_id"`
+}
+
+func (q *Queries) DeleteFriendLabel(ctx context.Context, arg DeleteFriendLabelParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFriendLabel, arg.ID, arg.OwnerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteFriendLabelMembershipsBetween = `-- name: DeleteFriendLabelMembershipsBetween :exec
+DELETE FROM friend_label_members m
+USING friend_labels l
+WHERE m.label_id = l.id
+  AND ((l.owner_id = $1 AND m.user_id = $2)
+    OR (l.owner_id = $2 AND m.user_id = $1))
+`
+
+type DeleteFriendLabelMembershipsBetweenParams struct {
+	UserA uuid.UUID `json:"user_a"`
+	UserB uuid.UUID `json:"user_b"`
+}
+
+// Takes each user out of the other's labels, e.g. after they stop being friends.
+func (q *Queries) DeleteFriendLabelMembershipsBetween(ctx context.Context, arg DeleteFriendLabelMembershipsBetweenParams) error {
+	_, err := q.db.ExecContext(ctx, deleteFriendLabelMembershipsBetween, arg.UserA, arg.UserB)
+	return err
+}
+
+const getFriendLabel = `-- name: GetFriendLabel :one
+SELECT l.id, l.name, l.created_at, l.updated_at,
+    (SELECT COUNT(*) FROM friend_label_members m WHERE m.label_id = l.id)::int AS member_count
+FROM friend_labels l
+WHERE l.id = $1 AND l.owner_id = $2
+`
+
+type GetFriendLabelParams struct {
+	ID      int64     `json:"id"`
+	OwnerID uuid.UUID `json:"owner_id"`
+}
+
+type GetFriendLabelRow struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	MemberCount int32     `json:"member_count"`
+}
+
+// Returns the label if @owner_id owns it; sql.ErrNoRows otherwise.
+func (q *Queries) GetFriendLabel(ctx context.Context, arg GetFriendLabelParams) (GetFriendLabelRow, error) {
+	row := q.db.QueryRowContext(ctx, getFriendLabel, arg.ID, arg.OwnerID)
+	var i GetFriendLabelRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MemberCount,
+	)
+	return i, err
+}
+
+const getFriendsByUsernames = `-- name: GetFriendsByUsernames :many
+SELECT u.user_id, u.user_name
+FROM users u
+JOIN friendships f ON f.status = 'accepted'
+  AND ((f.user1_userid = $1 AND f.user2_userid = u.user_id)
+    OR (f.user1_userid = u.user_id AND f.user2_userid = $1))
+WHERE u.user_name = ANY($2::text[])
+`
+
+type GetFriendsByUsernamesParams struct {
+	OwnerID   uuid.UUID `json:"owner_id"`
+	Usernames []string  `json:"usernames"`
+}
+
+type GetFriendsByUsernamesRow struct {
+	UserID   uuid.UUID `json:"user_id"`
+	UserName string    `json:"user_name"`
+}
+
+// Returns the accepted friends of @owner_id among @usernames.
+func (q *Queries) GetFriendsByUsernames(ctx context.Context, arg GetFriendsByUsernamesParams) ([]GetFriendsByUsernamesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFriendsByUsernames, arg.OwnerID, pq.Array(arg.Usernames))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFriendsByUsernamesRow
+	for rows.Next() {
+		var i GetFriendsByUsernamesRow
+		if err := rows.Scan(&i.UserID, &i.UserName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFriendLabelMemberUsernames = `-- name: ListFriendLabelMemberUsernames :many
+SELECT u.user_name
+FROM friend_label_members m
+JOIN users u ON u.user_id = m.user_id
+WHERE m.label_id = $1
+ORDER BY u.user_name
+`
+
+// Returns the usernames of the label's members, alphabetically.
+func (q *Queries) ListFriendLabelMemberUsernames(ctx context.Context, labelID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listFriendLabelMemberUsernames, labelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var user_name string
+		if err := rows.Scan(&user_name); err != nil {
+			return nil, err
+		}
+		items = append(items, user_name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFriendLabels = `-- name: ListFriendLabels :many
+SELECT l.id, l.name, l.created_at, l.updated_at,
+    (SELECT COUNT(*) FROM friend_label_members m WHERE m.label_id = l.id)::int AS member_count
+FROM friend_labels l
+WHERE l.owner_id = $1
+ORDER BY lower(l.name)
+`
+
+type ListFriendLabelsRow struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	MemberCount int32     `json:"member_count"`
+}
+
+func (q *Queries) ListFriendLabels(ctx context.Context, ownerID uuid.UUID) ([]ListFriendLabelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFriendLabels, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFriendLabelsRow
+	for rows.Next() {
+		var i ListFriendLabelsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MemberCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeFriendLabelMembers = `-- name: RemoveFriendLabelMembers :exec
+DELETE FROM friend_label_members
+WHERE label_id = $1 AND user_id = ANY($2::uuid[])
+`
+
+type RemoveFriendLabelMembersParams struct {
+	LabelID int64       `json:"label_id"`
+	UserIds []uuid.UUID `json:"user_ids"`
+}
+
+func (q *Queries) RemoveFriendLabelMembers(ctx context.Context, arg RemoveFriendLabelMembersParams) error {
+	_, err := q.db.ExecContext(ctx, removeFriendLabelMembers, arg.LabelID, pq.Array(arg.UserIds))
+	return err
+}
+
+const renameFriendLabel = `-- name: RenameFriendLabel :execrows
+UPDATE friend_labels
+SET name = $1, updated_at = NOW()
+WHERE id = $2 AND owner_id = $3
+`
+
+type RenameFriendLabelParams struct {
+	Name    string    `json:"name"`
+	ID      int64     `json:"id"`
+	OwnerID uuid.UUID `json:"owner_id"`
+}
+
+func (q *Queries) RenameFriendLabel(ctx context.Context, arg RenameFriendLabelParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, renameFriendLabel, arg.Name, arg.ID, arg.OwnerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,212 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: login_sessions.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createLoginSession = `-- name: CreateLoginSession :exec
+INSERT INTO login_sessions (login_id, user_id, device_name, user_agent, ip_address)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateLoginSessionParams struct {
+	LoginID    uuid.UUID `json:"login_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IpAddress  string    `json:"ip_address"`
+}
+
+func (q *Queries) CreateLoginSession(ctx context.Context, arg CreateLoginSessionParams) error {
+	_, err := q.db.ExecContext(ctx, createLoginSession,
+		arg.LoginID,
+		arg.UserID,
+		arg.DeviceName,
+		arg.UserAgent,
+		arg.IpAddress,
+	)
+	return err
+}
+
+const getLegacyLoginCutoff = `-- name: GetLegacyLoginCutoff :one
+SELECT accept_until
+FROM legacy_login_cutoff
+`
+
+// Until when a login without a login_sessions row, i.e. one from before they
+// were recorded, counts as active.
+func (q *Queries) GetLegacyLoginCutoff(ctx context.Context) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getLegacyLoginCutoff)
+	var accept_until time.Time
+	err := row.Scan(&accept_until)
+	return accept_until, err
+}
+
+const getUnrevokedLoginSessionForUpdate = `-- name: GetUnrevokedLoginSessionForUpdate :one
+SELECT login_id
+FROM login_sessions
+WHERE login_id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL
+FOR UPDATE
+`
+
+type GetUnrevokedLoginSessionForUpdateParams struct {
+	LoginID uuid.UUID `json:"login_id"`
+	UserID  uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetUnrevokedLoginSessionForUpdate(ctx context.Context, arg GetUnrevokedLoginSessionForUpdateParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, getUnrevokedLoginSessionForUpdate, arg.LoginID, arg.UserID)
+	var login_id uuid.UUID
+	err := row.Scan(&login_id)
+	return login_id, err
+}
+
+const listActiveLoginSessions = `-- name: ListActiveLoginSessions :many
+SELECT s.login_id, s.device_name, s.user_agent, s.ip_address, s.created_at, s.last_active_at
+FROM login_sessions s
+WHERE s.user_id = $1
+  AND s.revoked_at IS NULL
+  AND (
+      s.last_active_at > $2
+      OR EXISTS (
+          SELECT 1
+          FROM refresh_tokens rt
+          WHERE rt.login_id = s.login_id
+            AND rt.used_at IS NULL
+            AND rt.revoked_at IS NULL
+            AND rt.expires_at > NOW()
+      )
+  )
+ORDER BY s.last_active_at DESC, s.login_id
+`
+
+type ListActiveLoginSessionsParams struct {
+	UserID      uuid.UUID `json:"user_id"`
+	ActiveSince time.Time `json:"active_since"`
+}
+
+type ListActiveLoginSessionsRow struct {
+	LoginID      uuid.UUID `json:"login_id"`
+	DeviceName   string    `json:"device_name"`
+	UserAgent    string    `json:"user_agent"`
+	IpAddress    string    `json:"ip_address"`
+	CreatedAt    time.Time `json:"created_at"`
+	LastActiveAt time.Time `json:"last_active_at"`
+}
+
+// A user's logins that are not revoked and can still be used: one with a
+// recent call (its access token may be valid) or an unused, unexpired refresh
+// token.
+func (q *Queries) ListActiveLoginSessions(ctx context.Context, arg ListActiveLoginSessionsParams) ([]ListActiveLoginSessionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveLoginSessions, arg.UserID, arg.ActiveSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveLoginSessionsRow
+	for rows.Next() {
+		var i ListActiveLoginSessionsRow
+		if err := rows.Scan(
+			&i.LoginID,
+			&i.DeviceName,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.CreatedAt,
+			&i.LastActiveAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnrevokedLoginIDs = `-- name: ListUnrevokedLoginIDs :many
+SELECT login_id
+FROM login_sessions
+WHERE user_id = $1
+  AND login_id <> $2
+  AND revoked_at IS NULL
+FOR UPDATE
+`
+
+type ListUnrevokedLoginIDsParams struct {
+	UserID        uuid.UUID `json:"user_id"`
+	ExceptLoginID uuid.UUID `json:"except_login_id"`
+}
+
+// Every login of the user that is not revoked, except except_login_id.
+func (q *Queries) ListUnrevokedLoginIDs(ctx context.Context, arg ListUnrevokedLoginIDsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listUnrevokedLoginIDs, arg.UserID, arg.ExceptLoginID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var login_id uuid.UUID
+		if err := rows.Scan(&login_id); err != nil {
+			return nil, err
+		}
+		items = append(items, login_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeLoginSession = `-- name: RevokeLoginSession :exec
+INSERT INTO login_sessions (login_id, user_id, revoked_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (login_id) DO UPDATE
+SET revoked_at = COALESCE(login_sessions.revoked_at, NOW())
+WHERE login_sessions.user_id = EXCLUDED.user_id
+`
+
+type RevokeLoginSessionParams struct {
+	LoginID uuid.UUID `json:"login_id"`
+	UserID  uuid.UUID `json:"user_id"`
+}
+
+// Marks one of the user's logins revoked, recording logins that predate the
+// table too. Revoking again keeps the first revocation time.
+func (q *Queries) RevokeLoginSession(ctx context.Context, arg RevokeLoginSessionParams) error {
+	_, err := q.db.ExecContext(ctx, revokeLoginSession, arg.LoginID, arg.UserID)
+	return err
+}
+
+const touchLoginSession = `-- name: TouchLoginSession :one
+UPDATE login_sessions
+SET last_active_at = CASE WHEN revoked_at IS NULL THEN NOW() ELSE last_active_at END
+WHERE login_id = $1
+RETURNING (revoked_at IS NOT NULL)::boolean AS revoked
+`
+
+// Records activity on a login and reports whether it was revoked. Logins
+// without a row return no rows; see GetLegacyLoginCutoff.
+func (q *Queries) TouchLoginSession(ctx context.Context, loginID uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, touchLoginSession, loginID)
+	var revoked bool
+	err := row.Scan(&revoked)
+	return revoked, err
+}
@@ -11,8 +11,21 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const countConversationMessages = `-- name: CountConversationMessages :one
+SELECT COUNT(*) FROM messages
+WHERE conversation_id = $1
+`
+
+func (q *Queries) CountConversationMessages(ctx context.Context, conversationID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countConversationMessages, conversationID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const editMessage = `-- name: EditMessage :one
 UPDATE messages
 SET content    = $2,
@@ -59,20 +72,68 @@ func (q *Queries) EditMessage(ctx context.Context, arg EditMessageParams) (EditM
 	return i, err
 }
 
+const getConversationFormerSenders = `-- name: GetConversationFormerSenders :many
+SELECT u.user_id, u.user_name, u.display_name
+FROM users u
+WHERE u.user_id IN (SELECT m.sender_id FROM messages m WHERE m.conversation_id = $1::bigint)
+  AND NOT EXISTS (
+    SELECT 1 FROM conversation_members cm
+    WHERE cm.conversation_id = $1::bigint AND cm.user_id = u.user_id
+  )
+ORDER BY u.user_name
+`
+
+type GetConversationFormerSendersRow struct {
+	UserID      uuid.UUID      `json:"user_id"`
+	UserName    string         `json:"user_name"`
+	DisplayName sql.NullString `json:"display_name"`
+}
+
+// Users who sent a message in the conversation but are no longer members.
+func (q *Queries) GetConversationFormerSenders(ctx context.Context, conversationID int64) ([]GetConversationFormerSendersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getConversationFormerSenders, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetConversationFormerSendersRow
+	for rows.Next() {
+		var i GetConversationFormerSendersRow
+		if err := rows.Scan(&i.UserID, &i.UserName, &i.DisplayName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getConversationMessages = `-- name: GetConversationMessages :many
-SELECT id, conversation_id, sender_id, reply_to_message_id, content, message_type, is_edited, created_at
-FROM messages
-WHERE conversation_id = $1
-  AND deleted_at IS NULL
-  AND ($2::uuid IS NULL OR id > $2)
-ORDER BY id ASC
-LIMIT $3
+SELECT m.id, m.conversation_id, m.sender_id, m.reply_to_message_id, m.content, m.message_type, m.is_edited, m.created_at
+FROM messages m
+WHERE m.conversation_id = $1
+  AND m.deleted_at IS NULL
+  AND m.created_at > COALESCE((
+    SELECT cm.cleared_at FROM conversation_members cm
+    WHERE cm.conversation_id = $1 AND cm.user_id = $2
+  ), '-infinity'::timestamptz)
+  AND ($3::uuid IS NULL OR (m.created_at, m.id) > (
+    SELECT c.created_at, c.id FROM messages c WHERE c.id = $3::uuid
+  ))
+ORDER BY m.created_at ASC, m.id ASC
+LIMIT $4
 `
 
 type GetConversationMessagesParams struct {
-	ConversationID int64     `json:"conversation_id"`
-	Column2        uuid.UUID `json:"column_2"`
-	Limit          int32     `json:"limit"`
+	ConversationID int64         `json:"conversation_id"`
+	ViewerID       uuid.UUID     `json:"viewer_id"`
+	Cursor         uuid.NullUUID `json:"cursor"`
+	PageLimit      int32         `json:"page_limit"`
 }
 
 type GetConversationMessagesRow struct {
@@ -86,10 +147,16 @@ type GetConversationMessagesRow struct {
 	CreatedAt        time.Time     `json:"created_at"`
 }
 
-// Cursor-based pagination: pass the last seen message id as cursor (empty for first page).
-// Returns non-deleted messages ordered oldest-first.
+// Cursor-based pagination: pass the last seen message id as cursor (NULL for first page).
+// Returns non-deleted messages, including system events, ordered oldest-first.
+// Messages the viewer cleared with ClearHistory are skipped.
 func (q *Queries) GetConversationMessages(ctx context.Context, arg GetConversationMessagesParams) ([]GetConversationMessagesRow, error) {
-	rows, err := q.db.QueryContext(ctx, getConversationMessages, arg.ConversationID, arg.Column2, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, getConversationMessages,
+		arg.ConversationID,
+		arg.ViewerID,
+		arg.Cursor,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -120,6 +187,220 @@ func (q *Queries) GetConversationMessages(ctx context.Context, arg GetConversati
 	return items, nil
 }
 
+const getConversationParticipantIDs = `-- name: GetConversationParticipantIDs :many
+SELECT cm.user_id FROM conversation_members cm WHERE cm.conversation_id = $1::bigint
+UNION
+SELECT m.sender_id FROM messages m WHERE m.conversation_id = $1::bigint
+`
+
+// Current members plus anyone who ever sent a message in the conversation.
+func (q *Queries) GetConversationParticipantIDs(ctx context.Context, conversationID int64) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getConversationParticipantIDs, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getMessagesForExport = `-- name: GetMessagesForExport :many
+SELECT m.id, m.conversation_id, m.sender_id, m.sender_login_id, m.reply_to_message_id, m.content, m.message_type, m.media_url, m.is_edited, m.deleted_at, m.created_at, m.updated_at
+FROM messages m
+WHERE m.conversation_id = $1
+  AND ($2::uuid IS NULL OR (m.created_at, m.id) > (
+    SELECT c.created_at, c.id FROM messages c WHERE c.id = $2::uuid
+  ))
+ORDER BY m.created_at ASC, m.id ASC
+LIMIT $3
+`
+
+type GetMessagesForExportParams struct {
+	ConversationID int64         `json:"conversation_id"`
+	Cursor         uuid.NullUUID `json:"cursor"`
+	PageLimit      int32         `json:"page_limit"`
+}
+
+// Same keyset paging as GetConversationMessages but returns full rows,
+// including soft-deleted messages, for conversation archives.
+func (q *Queries) GetMessagesForExport(ctx context.Context, arg GetMessagesForExportParams) ([]Message, error) {
+	rows, err := q.db.QueryContext(ctx, getMessagesForExport, arg.ConversationID, arg.Cursor, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Message
+	for rows.Next() {
+		var i Message
+		if err := rows.Scan(
+			&i.ID,
+			&i.ConversationID,
+			&i.SenderID,
+			&i.SenderLoginID,
+			&i.ReplyToMessageID,
+			&i.Content,
+			&i.MessageType,
+			&i.MediaUrl,
+			&i.IsEdited,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertMessageSearchTokens = `-- name: InsertMessageSearchTokens :exec
+INSERT INTO message_search_tokens (message_id, conversation_id, token)
+SELECT $1::uuid, $2::bigint, unnest($3::text[])
+ON CONFLICT DO NOTHING
+`
+
+type InsertMessageSearchTokensParams struct {
+	MessageID      uuid.UUID `json:"message_id"`
+	ConversationID int64     `json:"conversation_id"`
+	Tokens         []string  `json:"tokens"`
+}
+
+func (q *Queries) InsertMessageSearchTokens(ctx context.Context, arg InsertMessageSearchTokensParams) error {
+	_, err := q.db.ExecContext(ctx, insertMessageSearchTokens, arg.MessageID, arg.ConversationID, pq.Array(arg.Tokens))
+	return err
+}
+
+const isMessageInConversation = `-- name: IsMessageInConversation :one
+SELECT EXISTS (
+  SELECT 1 FROM messages
+  WHERE id = $1 AND conversation_id = $2
+)
+`
+
+type IsMessageInConversationParams struct {
+	ID             uuid.UUID `json:"id"`
+	ConversationID int64     `json:"conversation_id"`
+}
+
+// Whether message id exists in the conversation; GetMessages checks its cursor
+// with this so a stale or foreign cursor is not mistaken for the end of history.
+func (q *Queries) IsMessageInConversation(ctx context.Context, arg IsMessageInConversationParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isMessageInConversation, arg.ID, arg.ConversationID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const searchMessagesByTokens = `-- name: SearchMessagesByTokens :many
+WITH wanted AS (
+  -- set-returning functions in the select list are zipped pairwise
+  SELECT unnest($4::bigint[]) AS conversation_id,
+         unnest($5::text[]) AS token
+), required AS (
+  SELECT wanted.conversation_id, COUNT(DISTINCT wanted.token) AS n
+  FROM wanted
+  GROUP BY wanted.conversation_id
+), hits AS (
+  SELECT t.message_id
+  FROM message_search_tokens t
+  JOIN wanted w ON w.conversation_id = t.conversation_id AND w.token = t.token
+  JOIN required r ON r.conversation_id = t.conversation_id
+  GROUP BY t.message_id, r.n
+  HAVING COUNT(DISTINCT t.token) = r.n
+)
+SELECT m.id, m.conversation_id, m.sender_id, m.reply_to_message_id, m.content, m.message_type, m.is_edited, m.created_at
+FROM hits h
+JOIN messages m ON m.id = h.message_id
+JOIN conversation_members cm ON cm.conversation_id = m.conversation_id AND cm.user_id = $1
+WHERE m.deleted_at IS NULL
+  AND m.created_at > COALESCE(cm.cleared_at, '-infinity'::timestamptz)
+  AND ($2::uuid IS NULL OR (m.created_at, m.id) < (
+    SELECT c.created_at, c.id FROM messages c WHERE c.id = $2::uuid
+  ))
+ORDER BY m.created_at DESC, m.id DESC
+LIMIT $3
+`
+
+type SearchMessagesByTokensParams struct {
+	CallerID        uuid.UUID     `json:"caller_id"`
+	Cursor          uuid.NullUUID `json:"cursor"`
+	PageLimit       int32         `json:"page_limit"`
+	ConversationIds []int64       `json:"conversation_ids"`
+	Tokens          []string      `json:"tokens"`
+}
+
+type SearchMessagesByTokensRow struct {
+	ID               uuid.UUID     `json:"id"`
+	ConversationID   int64         `json:"conversation_id"`
+	SenderID         uuid.UUID     `json:"sender_id"`
+	ReplyToMessageID uuid.NullUUID `json:"reply_to_message_id"`
+	Content          string        `json:"content"`
+	MessageType      MessageType   `json:"message_type"`
+	IsEdited         bool          `json:"is_edited"`
+	CreatedAt        time.Time     `json:"created_at"`
+}
+
+// Token sets are passed as parallel arrays of (conversation_id, token) pairs.
+// A message matches when it carries every token of its conversation's set.
+// Only conversations the caller is a member of are searched, and messages
+// the caller cleared are skipped. Newest first, keyset-paged on (created_at, id).
+func (q *Queries) SearchMessagesByTokens(ctx context.Context, arg SearchMessagesByTokensParams) ([]SearchMessagesByTokensRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchMessagesByTokens,
+		arg.CallerID,
+		arg.Cursor,
+		arg.PageLimit,
+		pq.Array(arg.ConversationIds),
+		pq.Array(arg.Tokens),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchMessagesByTokensRow
+	for rows.Next() {
+		var i SearchMessagesByTokensRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ConversationID,
+			&i.SenderID,
+			&i.ReplyToMessageID,
+			&i.Content,
+			&i.MessageType,
+			&i.IsEdited,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const sendMessage = `-- name: SendMessage :one
 INSERT INTO messages (id, conversation_id, sender_id, sender_login_id, reply_to_message_id, content, message_type, media_url)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
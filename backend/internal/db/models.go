@@ -13,6 +13,50 @@ import (
 	"github.com/google/uuid"
 )
 
+type ExportStatus string
+
+const (
+	ExportStatusPending ExportStatus = "pending"
+	ExportStatusRunning ExportStatus = "running"
+	ExportStatusDone    ExportStatus = "done"
+	ExportStatusFailed  ExportStatus = "failed"
+)
+
+func (e *ExportStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ExportStatus(s)
+	case string:
+		*e = ExportStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ExportStatus: %T", src)
+	}
+	return nil
+}
+
+type NullExportStatus struct {
+	ExportStatus ExportStatus `json:"export_status"`
+	Valid        bool         `json:"valid"` // Valid is true if ExportStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullExportStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ExportStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ExportStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullExportStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ExportStatus), nil
+}
+
 type FriendshipStatus string
 
 const (
@@ -101,10 +145,11 @@ func (ns NullMemberRole) Value() (driver.Value, error) {
 type MessageType string
 
 const (
-	MessageTypeText  MessageType = "text"
-	MessageTypeImage MessageType = "image"
-	MessageTypeFile  MessageType = "file"
-	MessageTypeAudio MessageType = "audio"
+	MessageTypeText   MessageType = "text"
+	MessageTypeImage  MessageType = "image"
+	MessageTypeFile   MessageType = "file"
+	MessageTypeAudio  MessageType = "audio"
+	MessageTypeSystem MessageType = "system"
 )
 
 func (e *MessageType) Scan(src interface{}) error {
@@ -142,11 +187,62 @@ func (ns NullMessageType) Value() (driver.Value, error) {
 	return string(ns.MessageType), nil
 }
 
+type ModerationActionType string
+
+const (
+	ModerationActionTypeWarn    ModerationActionType = "warn"
+	ModerationActionTypeSuspend ModerationActionType = "suspend"
+	ModerationActionTypeBan     ModerationActionType = "ban"
+	ModerationActionTypeLift    ModerationActionType = "lift"
+)
+
+func (e *ModerationActionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ModerationActionType(s)
+	case string:
+		*e = ModerationActionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ModerationActionType: %T", src)
+	}
+	return nil
+}
+
+type NullModerationActionType struct {
+	ModerationActionType ModerationActionType `json:"moderation_action_type"`
+	Valid                bool                 `json:"valid"` // Valid is true if ModerationActionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullModerationActionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.ModerationActionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ModerationActionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullModerationActionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ModerationActionType), nil
+}
+
 type NotificationType string
 
 const (
-	NotificationTypeMessage       NotificationType = "message"
-	NotificationTypeFriendRequest NotificationType = "friend_request"
+	NotificationTypeMessage           NotificationType = "message"
+	NotificationTypeFriendRequest     NotificationType = "friend_request"
+	NotificationTypeFriendRemoved     NotificationType = "friend_removed"
+	NotificationTypeFriendAccepted    NotificationType = "friend_accepted"
+	NotificationTypeGroupInvite       NotificationType = "group_invite"
+	NotificationTypeAddedToGroup      NotificationType = "added_to_group"
+	NotificationTypeRoleChanged       NotificationType = "role_changed"
+	NotificationTypeModerationWarning NotificationType = "moderation_warning"
+	NotificationTypeNewLogin          NotificationType = "new_login"
 )
 
 func (e *NotificationType) Scan(src interface{}) error {
@@ -184,12 +280,148 @@ func (ns NullNotificationType) Value() (driver.Value, error) {
 	return string(ns.NotificationType), nil
 }
 
+type PrivacyAudience string
+
+const (
+	PrivacyAudienceEveryone         PrivacyAudience = "everyone"
+	PrivacyAudienceFriendsOfFriends PrivacyAudience = "friends_of_friends"
+	PrivacyAudienceFriends          PrivacyAudience = "friends"
+	PrivacyAudienceNobody           PrivacyAudience = "nobody"
+)
+
+func (e *PrivacyAudience) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PrivacyAudience(s)
+	case string:
+		*e = PrivacyAudience(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PrivacyAudience: %T", src)
+	}
+	return nil
+}
+
+type NullPrivacyAudience struct {
+	PrivacyAudience PrivacyAudience `json:"privacy_audience"`
+	Valid           bool            `json:"valid"` // Valid is true if PrivacyAudience is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPrivacyAudience) Scan(value interface{}) error {
+	if value == nil {
+		ns.PrivacyAudience, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PrivacyAudience.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPrivacyAudience) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PrivacyAudience), nil
+}
+
+type ReportReason string
+
+const (
+	ReportReasonSpam          ReportReason = "spam"
+	ReportReasonHarassment    ReportReason = "harassment"
+	ReportReasonHate          ReportReason = "hate"
+	ReportReasonSexualContent ReportReason = "sexual_content"
+	ReportReasonViolence      ReportReason = "violence"
+	ReportReasonImpersonation ReportReason = "impersonation"
+	ReportReasonOther         ReportReason = "other"
+)
+
+func (e *ReportReason) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReportReason(s)
+	case string:
+		*e = ReportReason(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReportReason: %T", src)
+	}
+	return nil
+}
+
+type NullReportReason struct {
+	ReportReason ReportReason `json:"report_reason"`
+	Valid        bool         `json:"valid"` // Valid is true if ReportReason is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReportReason) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReportReason, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReportReason.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReportReason) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReportReason), nil
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusTriaged   ReportStatus = "triaged"
+	ReportStatusResolved  ReportStatus = "resolved"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+func (e *ReportStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReportStatus(s)
+	case string:
+		*e = ReportStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReportStatus: %T", src)
+	}
+	return nil
+}
+
+type NullReportStatus struct {
+	ReportStatus ReportStatus `json:"report_status"`
+	Valid        bool         `json:"valid"` // Valid is true if ReportStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReportStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReportStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReportStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReportStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReportStatus), nil
+}
+
 type SignupType string
 
 const (
 	SignupTypeEmail  SignupType = "email"
 	SignupTypeGoogle SignupType = "google"
 	SignupTypeGithub SignupType = "github"
+	SignupTypeOidc   SignupType = "oidc"
 )
 
 func (e *SignupType) Scan(src interface{}) error {
@@ -227,12 +459,41 @@ func (ns NullSignupType) Value() (driver.Value, error) {
 	return string(ns.SignupType), nil
 }
 
+type AccountRestriction struct {
+	UserID    uuid.UUID            `json:"user_id"`
+	ActionID  int64                `json:"action_id"`
+	Kind      ModerationActionType `json:"kind"`
+	ExpiresAt sql.NullTime         `json:"expires_at"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+type Block struct {
+	BlockerID uuid.UUID `json:"blocker_id"`
+	BlockedID uuid.UUID `json:"blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Conversation struct {
-	ID        int64          `json:"id"`
-	IsGroup   bool           `json:"is_group"`
-	Name      sql.NullString `json:"name"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	ID              int64          `json:"id"`
+	IsGroup         bool           `json:"is_group"`
+	Name            sql.NullString `json:"name"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	SlowModeSeconds int32          `json:"slow_mode_seconds"`
+}
+
+type ConversationExport struct {
+	ID             uuid.UUID      `json:"id"`
+	ConversationID int64          `json:"conversation_id"`
+	UserID         uuid.UUID      `json:"user_id"`
+	Status         ExportStatus   `json:"status"`
+	DownloadToken  sql.NullString `json:"download_token"`
+	FilePath       sql.NullString `json:"file_path"`
+	Error          sql.NullString `json:"error"`
+	ExpiresAt      sql.NullTime   `json:"expires_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	StartedAt      sql.NullTime   `json:"started_at"`
 }
 
 type ConversationMember struct {
@@ -242,6 +503,9 @@ type ConversationMember struct {
 	LastReadMessageID      uuid.NullUUID `json:"last_read_message_id"`
 	LastDeliveredMessageID uuid.NullUUID `json:"last_delivered_message_id"`
 	JoinedAt               time.Time     `json:"joined_at"`
+	ClearedAt              sql.NullTime  `json:"cleared_at"`
+	HiddenAt               sql.NullTime  `json:"hidden_at"`
+	LastSentAt             sql.NullTime  `json:"last_sent_at"`
 }
 
 type DmPeer struct {
@@ -250,6 +514,33 @@ type DmPeer struct {
 	ConversationID int64     `json:"conversation_id"`
 }
 
+type FriendLabel struct {
+	ID        int64     `json:"id"`
+	OwnerID   uuid.UUID `json:"owner_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type FriendLabelMember struct {
+	LabelID int64     `json:"label_id"`
+	UserID  uuid.UUID `json:"user_id"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+type FriendRequestDecline struct {
+	RequesterID uuid.UUID `json:"requester_id"`
+	AddresseeID uuid.UUID `json:"addressee_id"`
+	DeclinedAt  time.Time `json:"declined_at"`
+}
+
+type FriendRequestLog struct {
+	ID          int64     `json:"id"`
+	SenderID    uuid.UUID `json:"sender_id"`
+	RecipientID uuid.UUID `json:"recipient_id"`
+	SentAt      time.Time `json:"sent_at"`
+}
+
 type Friendship struct {
 	User1Userid     uuid.UUID        `json:"user1_userid"`
 	User2Userid     uuid.UUID        `json:"user2_userid"`
@@ -259,6 +550,32 @@ type Friendship struct {
 	UpdatedAt       time.Time        `json:"updated_at"`
 }
 
+type LegacyLoginCutoff struct {
+	ID          bool      `json:"id"`
+	AcceptUntil time.Time `json:"accept_until"`
+}
+
+type LoginChallenge struct {
+	ID         int64        `json:"id"`
+	UserID     uuid.UUID    `json:"user_id"`
+	TokenHash  string       `json:"token_hash"`
+	DeviceName string       `json:"device_name"`
+	CreatedAt  time.Time    `json:"created_at"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+	UsedAt     sql.NullTime `json:"used_at"`
+}
+
+type LoginSession struct {
+	LoginID      uuid.UUID    `json:"login_id"`
+	UserID       uuid.UUID    `json:"user_id"`
+	CreatedAt    time.Time    `json:"created_at"`
+	RevokedAt    sql.NullTime `json:"revoked_at"`
+	DeviceName   string       `json:"device_name"`
+	UserAgent    string       `json:"user_agent"`
+	IpAddress    string       `json:"ip_address"`
+	LastActiveAt time.Time    `json:"last_active_at"`
+}
+
 type Message struct {
 	ID               uuid.UUID      `json:"id"`
 	ConversationID   int64          `json:"conversation_id"`
@@ -274,15 +591,58 @@ type Message struct {
 	UpdatedAt        time.Time      `json:"updated_at"`
 }
 
+type MessageSearchToken struct {
+	MessageID      uuid.UUID `json:"message_id"`
+	ConversationID int64     `json:"conversation_id"`
+	Token          string    `json:"token"`
+}
+
+type ModerationAction struct {
+	ID          int64                `json:"id"`
+	UserID      uuid.UUID            `json:"user_id"`
+	ModeratorID uuid.NullUUID        `json:"moderator_id"`
+	ReportID    sql.NullInt64        `json:"report_id"`
+	Action      ModerationActionType `json:"action"`
+	Note        string               `json:"note"`
+	ExpiresAt   sql.NullTime         `json:"expires_at"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
 type Notification struct {
-	ID          int64            `json:"id"`
-	UserID      uuid.UUID        `json:"user_id"`
-	SenderID    uuid.NullUUID    `json:"sender_id"`
-	Type        NotificationType `json:"type"`
-	Message     string           `json:"message"`
-	ReferenceID sql.NullInt64    `json:"reference_id"`
-	IsRead      bool             `json:"is_read"`
-	CreatedAt   time.Time        `json:"created_at"`
+	ID                      int64            `json:"id"`
+	UserID                  uuid.UUID        `json:"user_id"`
+	SenderID                uuid.NullUUID    `json:"sender_id"`
+	Type                    NotificationType `json:"type"`
+	Message                 string           `json:"message"`
+	ReferenceConversationID sql.NullInt64    `json:"reference_conversation_id"`
+	IsRead                  bool             `json:"is_read"`
+	CreatedAt               time.Time        `json:"created_at"`
+	ReferenceUserID         uuid.NullUUID    `json:"reference_user_id"`
+}
+
+type OauthState struct {
+	StateHash    string    `json:"state_hash"`
+	Provider     string    `json:"provider"`
+	CodeVerifier string    `json:"code_verifier"`
+	Nonce        string    `json:"nonce"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type PasswordResetRequest struct {
+	ID          int64        `json:"id"`
+	Email       string       `json:"email"`
+	RequestedAt time.Time    `json:"requested_at"`
+	ProcessedAt sql.NullTime `json:"processed_at"`
+}
+
+type PasswordResetToken struct {
+	ID        int64        `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	TokenHash string       `json:"token_hash"`
+	CreatedAt time.Time    `json:"created_at"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
 }
 
 type PublicKey struct {
@@ -293,6 +653,61 @@ type PublicKey struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type RecoveryEmail struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RefreshToken struct {
+	ID        int64        `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	LoginID   uuid.UUID    `json:"login_id"`
+	TokenHash string       `json:"token_hash"`
+	CreatedAt time.Time    `json:"created_at"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+}
+
+type Report struct {
+	ID               int64          `json:"id"`
+	ReporterID       uuid.UUID      `json:"reporter_id"`
+	TargetUserID     uuid.UUID      `json:"target_user_id"`
+	MessageID        uuid.NullUUID  `json:"message_id"`
+	Reason           ReportReason   `json:"reason"`
+	Details          string         `json:"details"`
+	MessagePlaintext sql.NullString `json:"message_plaintext"`
+	Status           ReportStatus   `json:"status"`
+	AssignedTo       uuid.NullUUID  `json:"assigned_to"`
+	ResolutionNote   string         `json:"resolution_note"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	ResolvedAt       sql.NullTime   `json:"resolved_at"`
+}
+
+type SiteAdmin struct {
+	UserID    uuid.UUID `json:"user_id"`
+	GrantedAt time.Time `json:"granted_at"`
+}
+
+type TotpFactor struct {
+	UserID         uuid.UUID    `json:"user_id"`
+	Secret         string       `json:"secret"`
+	CreatedAt      time.Time    `json:"created_at"`
+	ConfirmedAt    sql.NullTime `json:"confirmed_at"`
+	LastUsedStep   int64        `json:"last_used_step"`
+	FailedAttempts int32        `json:"failed_attempts"`
+	LastFailedAt   sql.NullTime `json:"last_failed_at"`
+}
+
+type TotpRecoveryCode struct {
+	ID       int64        `json:"id"`
+	UserID   uuid.UUID    `json:"user_id"`
+	CodeHash string       `json:"code_hash"`
+	UsedAt   sql.NullTime `json:"used_at"`
+}
+
 type User struct {
 	UserID              uuid.UUID      `json:"user_id"`
 	UserName            string         `json:"user_name"`
@@ -305,4 +720,53 @@ type User struct {
 	LastSeenAt          sql.NullTime   `json:"last_seen_at"`
 	CreatedAt           time.Time      `json:"created_at"`
 	UpdatedAt           time.Time      `json:"updated_at"`
+	Bio                 sql.NullString `json:"bio"`
+	StatusText          sql.NullString `json:"status_text"`
+	StatusExpiresAt     sql.NullTime   `json:"status_expires_at"`
+}
+
+type UserIdentity struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserSetting struct {
+	UserID              uuid.UUID       `json:"user_id"`
+	Discoverability     PrivacyAudience `json:"discoverability"`
+	FriendRequestPolicy PrivacyAudience `json:"friend_request_policy"`
+	ReadReceipts        bool            `json:"read_receipts"`
+	LastSeenVisibility  PrivacyAudience `json:"last_seen_visibility"`
+	UpdatedAt           time.Time       `json:"updated_at"`
+}
+
+type UsernameHistory struct {
+	ID        int64     `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type WebauthnChallenge struct {
+	ID            int64         `json:"id"`
+	ChallengeHash string        `json:"challenge_hash"`
+	UserID        uuid.NullUUID `json:"user_id"`
+	Purpose       string        `json:"purpose"`
+	CreatedAt     time.Time     `json:"created_at"`
+	ExpiresAt     time.Time     `json:"expires_at"`
+	UsedAt        sql.NullTime  `json:"used_at"`
+}
+
+type WebauthnCredential struct {
+	ID             int64        `json:"id"`
+	UserID         uuid.UUID    `json:"user_id"`
+	CredentialID   []byte       `json:"credential_id"`
+	PublicKey      []byte       `json:"public_key"`
+	SignCount      int64        `json:"sign_count"`
+	Transports     string       `json:"transports"`
+	Name           string       `json:"name"`
+	BackupEligible bool         `json:"backup_eligible"`
+	CreatedAt      time.Time    `json:"created_at"`
+	LastUsedAt     sql.NullTime `json:"last_used_at"`
 }
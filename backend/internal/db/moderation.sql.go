@@ -0,0 +1,378 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: moderation.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const closeReport = `-- name: CloseReport :exec
+UPDATE reports
+SET status          = $1,
+    resolution_note = $2,
+    assigned_to     = COALESCE(assigned_to, $3),
+    updated_at      = NOW(),
+    resolved_at     = NOW()
+WHERE id = $4
+`
+
+type CloseReportParams struct {
+	Status         ReportStatus  `json:"status"`
+	ResolutionNote string        `json:"resolution_note"`
+	ModeratorID    uuid.NullUUID `json:"moderator_id"`
+	ID             int64         `json:"id"`
+}
+
+func (q *Queries) CloseReport(ctx context.Context, arg CloseReportParams) error {
+	_, err := q.db.ExecContext(ctx, closeReport,
+		arg.Status,
+		arg.ResolutionNote,
+		arg.ModeratorID,
+		arg.ID,
+	)
+	return err
+}
+
+const createModerationAction = `-- name: CreateModerationAction :one
+INSERT INTO moderation_actions (user_id, moderator_id, report_id, action, note, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at
+`
+
+type CreateModerationActionParams struct {
+	UserID      uuid.UUID            `json:"user_id"`
+	ModeratorID uuid.NullUUID        `json:"moderator_id"`
+	ReportID    sql.NullInt64        `json:"report_id"`
+	Action      ModerationActionType `json:"action"`
+	Note        string               `json:"note"`
+	ExpiresAt   sql.NullTime         `json:"expires_at"`
+}
+
+type CreateModerationActionRow struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) CreateModerationAction(ctx context.Context, arg CreateModerationActionParams) (CreateModerationActionRow, error) {
+	row := q.db.QueryRowContext(ctx, createModerationAction,
+		arg.UserID,
+		arg.ModeratorID,
+		arg.ReportID,
+		arg.Action,
+		arg.Note,
+		arg.ExpiresAt,
+	)
+	var i CreateModerationActionRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
+const createReport = `-- name: CreateReport :one
+INSERT INTO reports (reporter_id, target_user_id, message_id, reason, details, message_plaintext)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, status, created_at
+`
+
+type CreateReportParams struct {
+	ReporterID       uuid.UUID      `json:"reporter_id"`
+	TargetUserID     uuid.UUID      `json:"target_user_id"`
+	MessageID        uuid.NullUUID  `json:"message_id"`
+	Reason           ReportReason   `json:"reason"`
+	Details          string         `json:"details"`
+	MessagePlaintext sql.NullString `json:"message_plaintext"`
+}
+
+type CreateReportRow struct {
+	ID        int64        `json:"id"`
+	Status    ReportStatus `json:"status"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+func (q *Queries) CreateReport(ctx context.Context, arg CreateReportParams) (CreateReportRow, error) {
+	row := q.db.QueryRowContext(ctx, createReport,
+		arg.ReporterID,
+		arg.TargetUserID,
+		arg.MessageID,
+		arg.Reason,
+		arg.Details,
+		arg.MessagePlaintext,
+	)
+	var i CreateReportRow
+	err := row.Scan(&i.ID, &i.Status, &i.CreatedAt)
+	return i, err
+}
+
+const deleteAccountRestriction = `-- name: DeleteAccountRestriction :execrows
+DELETE FROM account_restrictions
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteAccountRestriction(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAccountRestriction, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAccountRestriction = `-- name: GetAccountRestriction :one
+SELECT kind, expires_at
+FROM account_restrictions
+WHERE user_id = $1
+  AND (expires_at IS NULL OR expires_at > NOW())
+`
+
+type GetAccountRestrictionRow struct {
+	Kind      ModerationActionType `json:"kind"`
+	ExpiresAt sql.NullTime         `json:"expires_at"`
+}
+
+// Returns the suspension or ban in force for user_id.
+func (q *Queries) GetAccountRestriction(ctx context.Context, userID uuid.UUID) (GetAccountRestrictionRow, error) {
+	row := q.db.QueryRowContext(ctx, getAccountRestriction, userID)
+	var i GetAccountRestrictionRow
+	err := row.Scan(&i.Kind, &i.ExpiresAt)
+	return i, err
+}
+
+const getReportForUpdate = `-- name: GetReportForUpdate :one
+SELECT id, reporter_id, target_user_id, status
+FROM reports
+WHERE id = $1
+FOR UPDATE
+`
+
+type GetReportForUpdateRow struct {
+	ID           int64        `json:"id"`
+	ReporterID   uuid.UUID    `json:"reporter_id"`
+	TargetUserID uuid.UUID    `json:"target_user_id"`
+	Status       ReportStatus `json:"status"`
+}
+
+func (q *Queries) GetReportForUpdate(ctx context.Context, id int64) (GetReportForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getReportForUpdate, id)
+	var i GetReportForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.ReporterID,
+		&i.TargetUserID,
+		&i.Status,
+	)
+	return i, err
+}
+
+const getReportableMessage = `-- name: GetReportableMessage :one
+SELECT m.sender_id
+FROM messages m
+JOIN conversation_members cm ON cm.conversation_id = m.conversation_id AND cm.user_id = $1
+WHERE m.id = $2
+  AND m.deleted_at IS NULL
+`
+
+type GetReportableMessageParams struct {
+	ViewerID  uuid.UUID `json:"viewer_id"`
+	MessageID uuid.UUID `json:"message_id"`
+}
+
+// Returns the sender of message_id if viewer_id is a member of its
+// conversation and the message is not deleted.
+func (q *Queries) GetReportableMessage(ctx context.Context, arg GetReportableMessageParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, getReportableMessage, arg.ViewerID, arg.MessageID)
+	var sender_id uuid.UUID
+	err := row.Scan(&sender_id)
+	return sender_id, err
+}
+
+const isSiteAdmin = `-- name: IsSiteAdmin :one
+SELECT EXISTS (SELECT 1 FROM site_admins WHERE user_id = $1)::boolean AS is_admin
+`
+
+func (q *Queries) IsSiteAdmin(ctx context.Context, userID uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isSiteAdmin, userID)
+	var is_admin bool
+	err := row.Scan(&is_admin)
+	return is_admin, err
+}
+
+const listModerationActions = `-- name: ListModerationActions :many
+SELECT a.id, a.action, a.note, a.expires_at, a.report_id, a.created_at,
+    moderator.user_name AS moderator_username
+FROM moderation_actions a
+LEFT JOIN users moderator ON moderator.user_id = a.moderator_id
+WHERE a.user_id = $1
+ORDER BY a.created_at DESC
+`
+
+type ListModerationActionsRow struct {
+	ID                int64                `json:"id"`
+	Action            ModerationActionType `json:"action"`
+	Note              string               `json:"note"`
+	ExpiresAt         sql.NullTime         `json:"expires_at"`
+	ReportID          sql.NullInt64        `json:"report_id"`
+	CreatedAt         time.Time            `json:"created_at"`
+	ModeratorUsername sql.NullString       `json:"moderator_username"`
+}
+
+func (q *Queries) ListModerationActions(ctx context.Context, userID uuid.UUID) ([]ListModerationActionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listModerationActions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListModerationActionsRow
+	for rows.Next() {
+		var i ListModerationActionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Action,
+			&i.Note,
+			&i.ExpiresAt,
+			&i.ReportID,
+			&i.CreatedAt,
+			&i.ModeratorUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReports = `-- name: ListReports :many
+SELECT r.id, r.reason, r.details, r.message_id, r.message_plaintext, r.status,
+    r.resolution_note, r.created_at, r.updated_at, r.resolved_at,
+    reporter.user_name AS reporter_username,
+    target.user_name AS target_username,
+    assignee.user_name AS assignee_username,
+    (SELECT COUNT(*) FROM reports o WHERE o.target_user_id = r.target_user_id)::int AS target_report_count
+FROM reports r
+JOIN users reporter ON reporter.user_id = r.reporter_id
+JOIN users target ON target.user_id = r.target_user_id
+LEFT JOIN users assignee ON assignee.user_id = r.assigned_to
+WHERE ($1::report_status IS NULL OR r.status = $1::report_status)
+  AND r.id > $2
+ORDER BY r.id
+LIMIT $3
+`
+
+type ListReportsParams struct {
+	Status    NullReportStatus `json:"status"`
+	AfterID   int64            `json:"after_id"`
+	PageLimit int32            `json:"page_limit"`
+}
+
+type ListReportsRow struct {
+	ID                int64          `json:"id"`
+	Reason            ReportReason   `json:"reason"`
+	Details           string         `json:"details"`
+	MessageID         uuid.NullUUID  `json:"message_id"`
+	MessagePlaintext  sql.NullString `json:"message_plaintext"`
+	Status            ReportStatus   `json:"status"`
+	ResolutionNote    string         `json:"resolution_note"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	ResolvedAt        sql.NullTime   `json:"resolved_at"`
+	ReporterUsername  string         `json:"reporter_username"`
+	TargetUsername    string         `json:"target_username"`
+	AssigneeUsername  sql.NullString `json:"assignee_username"`
+	TargetReportCount int32          `json:"target_report_count"`
+}
+
+// The moderation queue: reports in the given status (any when NULL), oldest
+// first, keyset-paged on id.
+func (q *Queries) ListReports(ctx context.Context, arg ListReportsParams) ([]ListReportsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listReports, arg.Status, arg.AfterID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListReportsRow
+	for rows.Next() {
+		var i ListReportsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Reason,
+			&i.Details,
+			&i.MessageID,
+			&i.MessagePlaintext,
+			&i.Status,
+			&i.ResolutionNote,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ResolvedAt,
+			&i.ReporterUsername,
+			&i.TargetUsername,
+			&i.AssigneeUsername,
+			&i.TargetReportCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setAccountRestriction = `-- name: SetAccountRestriction :exec
+INSERT INTO account_restrictions (user_id, action_id, kind, expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET action_id  = EXCLUDED.action_id,
+    kind       = EXCLUDED.kind,
+    expires_at = EXCLUDED.expires_at,
+    created_at = NOW()
+`
+
+type SetAccountRestrictionParams struct {
+	UserID    uuid.UUID            `json:"user_id"`
+	ActionID  int64                `json:"action_id"`
+	Kind      ModerationActionType `json:"kind"`
+	ExpiresAt sql.NullTime         `json:"expires_at"`
+}
+
+// Replaces the user's restriction.
+func (q *Queries) SetAccountRestriction(ctx context.Context, arg SetAccountRestrictionParams) error {
+	_, err := q.db.ExecContext(ctx, setAccountRestriction,
+		arg.UserID,
+		arg.ActionID,
+		arg.Kind,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const triageReport = `-- name: TriageReport :exec
+UPDATE reports
+SET status      = 'triaged',
+    assigned_to = $1,
+    updated_at  = NOW()
+WHERE id = $2
+`
+
+type TriageReportParams struct {
+	AssignedTo uuid.NullUUID `json:"assigned_to"`
+	ID         int64         `json:"id"`
+}
+
+func (q *Queries) TriageReport(ctx context.Context, arg TriageReportParams) error {
+	_, err := q.db.ExecContext(ctx, triageReport, arg.AssignedTo, arg.ID)
+	return err
+}
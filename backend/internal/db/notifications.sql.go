@@ -13,17 +13,18 @@ import (
 )
 
 const createNotification = `-- name: CreateNotification :one
-INSERT INTO notifications (user_id, sender_id, type, message, reference_id)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, user_id, sender_id, type, message, reference_id, is_read, created_at
+INSERT INTO notifications (user_id, sender_id, type, message, reference_conversation_id, reference_user_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, sender_id, type, message, reference_conversation_id, is_read, created_at, reference_user_id
 `
 
 type CreateNotificationParams struct {
-	UserID      uuid.UUID        `json:"user_id"`
-	SenderID    uuid.NullUUID    `json:"sender_id"`
-	Type        NotificationType `json:"type"`
-	Message     string           `json:"message"`
-	ReferenceID sql.NullInt64    `json:"reference_id"`
+	UserID                  uuid.UUID        `json:"user_id"`
+	SenderID                uuid.NullUUID    `json:"sender_id"`
+	Type                    NotificationType `json:"type"`
+	Message                 string           `json:"message"`
+	ReferenceConversationID sql.NullInt64    `json:"reference_conversation_id"`
+	ReferenceUserID         uuid.NullUUID    `json:"reference_user_id"`
 }
 
 func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error) {
@@ -32,7 +33,8 @@ func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotification
 		arg.SenderID,
 		arg.Type,
 		arg.Message,
-		arg.ReferenceID,
+		arg.ReferenceConversationID,
+		arg.ReferenceUserID,
 	)
 	var i Notification
 	err := row.Scan(
@@ -41,15 +43,54 @@ func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotification
 		&i.SenderID,
 		&i.Type,
 		&i.Message,
-		&i.ReferenceID,
+		&i.ReferenceConversationID,
 		&i.IsRead,
 		&i.CreatedAt,
+		&i.ReferenceUserID,
 	)
 	return i, err
 }
 
+const deleteNotificationsFrom = `-- name: DeleteNotificationsFrom :many
+DELETE FROM notifications
+WHERE user_id = $1
+  AND sender_id = $2
+  AND type = $3
+RETURNING id
+`
+
+type DeleteNotificationsFromParams struct {
+	UserID   uuid.UUID        `json:"user_id"`
+	SenderID uuid.NullUUID    `json:"sender_id"`
+	Type     NotificationType `json:"type"`
+}
+
+// Retracts the notifications of one type that sender_id caused for user_id.
+func (q *Queries) DeleteNotificationsFrom(ctx context.Context, arg DeleteNotificationsFromParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, deleteNotificationsFrom, arg.UserID, arg.SenderID, arg.Type)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNotificationsForUser = `-- name: GetNotificationsForUser :many
-SELECT id, user_id, sender_id, type, message, reference_id, is_read, created_at
+SELECT id, user_id, sender_id, type, message, reference_conversation_id, is_read, created_at, reference_user_id
 FROM notifications
 WHERE user_id = $1
 ORDER BY created_at DESC
@@ -71,9 +112,10 @@ func (q *Queries) GetNotificationsForUser(ctx context.Context, userID uuid.UUID)
 			&i.SenderID,
 			&i.Type,
 			&i.Message,
-			&i.ReferenceID,
+			&i.ReferenceConversationID,
 			&i.IsRead,
 			&i.CreatedAt,
+			&i.ReferenceUserID,
 		); err != nil {
 			return nil, err
 		}
@@ -118,7 +160,7 @@ const markNotificationAsRead = `-- name: MarkNotificationAsRead :one
 UPDATE notifications
 SET is_read = TRUE
 WHERE id = $1
-RETURNING id, user_id, sender_id, type, message, reference_id, is_read, created_at
+RETURNING id, user_id, sender_id, type, message, reference_conversation_id, is_read, created_at, reference_user_id
 `
 
 func (q *Queries) MarkNotificationAsRead(ctx context.Context, id int64) (Notification, error) {
@@ -130,9 +172,10 @@ func (q *Queries) MarkNotificationAsRead(ctx context.Context, id int64) (Notific
 		&i.SenderID,
 		&i.Type,
 		&i.Message,
-		&i.ReferenceID,
+		&i.ReferenceConversationID,
 		&i.IsRead,
 		&i.CreatedAt,
+		&i.ReferenceUserID,
 	)
 	return i, err
 }
@@ -0,0 +1,136 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: oauth.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const consumeOAuthState = `-- name: ConsumeOAuthState :one
+DELETE FROM oauth_states
+WHERE state_hash = $1
+RETURNING provider, code_verifier, nonce, expires_at
+`
+
+type ConsumeOAuthStateRow struct {
+	Provider     string    `json:"provider"`
+	CodeVerifier string    `json:"code_verifier"`
+	Nonce        string    `json:"nonce"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// Deleting the state as it is read makes each one good for a single callback.
+func (q *Queries) ConsumeOAuthState(ctx context.Context, stateHash string) (ConsumeOAuthStateRow, error) {
+	row := q.db.QueryRowContext(ctx, consumeOAuthState, stateHash)
+	var i ConsumeOAuthStateRow
+	err := row.Scan(
+		&i.Provider,
+		&i.CodeVerifier,
+		&i.Nonce,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const createOAuthState = `-- name: CreateOAuthState :exec
+INSERT INTO oauth_states (state_hash, provider, code_verifier, nonce, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateOAuthStateParams struct {
+	StateHash    string    `json:"state_hash"`
+	Provider     string    `json:"provider"`
+	CodeVerifier string    `json:"code_verifier"`
+	Nonce        string    `json:"nonce"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateOAuthState(ctx context.Context, arg CreateOAuthStateParams) error {
+	_, err := q.db.ExecContext(ctx, createOAuthState,
+		arg.StateHash,
+		arg.Provider,
+		arg.CodeVerifier,
+		arg.Nonce,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createUserIdentity = `-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (provider, subject, user_id)
+VALUES ($1, $2, $3)
+`
+
+type CreateUserIdentityParams struct {
+	Provider string    `json:"provider"`
+	Subject  string    `json:"subject"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) error {
+	_, err := q.db.ExecContext(ctx, createUserIdentity, arg.Provider, arg.Subject, arg.UserID)
+	return err
+}
+
+const deleteExpiredOAuthStates = `-- name: DeleteExpiredOAuthStates :exec
+DELETE FROM oauth_states
+WHERE expires_at < NOW()
+`
+
+func (q *Queries) DeleteExpiredOAuthStates(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredOAuthStates)
+	return err
+}
+
+const getUserByIdentity = `-- name: GetUserByIdentity :one
+SELECT u.user_id, u.user_name, u.signup_type
+FROM user_identities i
+JOIN users u ON u.user_id = i.user_id
+WHERE i.provider = $1
+  AND i.subject = $2
+`
+
+type GetUserByIdentityParams struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+}
+
+type GetUserByIdentityRow struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	UserName   string     `json:"user_name"`
+	SignupType SignupType `json:"signup_type"`
+}
+
+func (q *Queries) GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (GetUserByIdentityRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserByIdentity, arg.Provider, arg.Subject)
+	var i GetUserByIdentityRow
+	err := row.Scan(&i.UserID, &i.UserName, &i.SignupType)
+	return i, err
+}
+
+const userHasIdentity = `-- name: UserHasIdentity :one
+SELECT EXISTS (
+    SELECT 1 FROM user_identities
+    WHERE user_id = $1
+      AND provider = $2
+)
+`
+
+type UserHasIdentityParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Provider string    `json:"provider"`
+}
+
+// Whether the user has linked an account at provider.
+func (q *Queries) UserHasIdentity(ctx context.Context, arg UserHasIdentityParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, userHasIdentity, arg.UserID, arg.Provider)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,231 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: passkeys.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createPasskey = `-- name: CreatePasskey :one
+INSERT INTO webauthn_credentials (user_id, credential_id, public_key, sign_count, transports, name, backup_eligible)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, created_at
+`
+
+type CreatePasskeyParams struct {
+	UserID         uuid.UUID `json:"user_id"`
+	CredentialID   []byte    `json:"credential_id"`
+	PublicKey      []byte    `json:"public_key"`
+	SignCount      int64     `json:"sign_count"`
+	Transports     string    `json:"transports"`
+	Name           string    `json:"name"`
+	BackupEligible bool      `json:"backup_eligible"`
+}
+
+type CreatePasskeyRow struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) CreatePasskey(ctx context.Context, arg CreatePasskeyParams) (CreatePasskeyRow, error) {
+	row := q.db.QueryRowContext(ctx, createPasskey,
+		arg.UserID,
+		arg.CredentialID,
+		arg.PublicKey,
+		arg.SignCount,
+		arg.Transports,
+		arg.Name,
+		arg.BackupEligible,
+	)
+	var i CreatePasskeyRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
+const createWebAuthnChallenge = `-- name: CreateWebAuthnChallenge :exec
+INSERT INTO webauthn_challenges (challenge_hash, user_id, purpose, expires_at)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateWebAuthnChallengeParams struct {
+	ChallengeHash string        `json:"challenge_hash"`
+	UserID        uuid.NullUUID `json:"user_id"`
+	Purpose       string        `json:"purpose"`
+	ExpiresAt     time.Time     `json:"expires_at"`
+}
+
+func (q *Queries) CreateWebAuthnChallenge(ctx context.Context, arg CreateWebAuthnChallengeParams) error {
+	_, err := q.db.ExecContext(ctx, createWebAuthnChallenge,
+		arg.ChallengeHash,
+		arg.UserID,
+		arg.Purpose,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const deleteExpiredWebAuthnChallenges = `-- name: DeleteExpiredWebAuthnChallenges :exec
+DELETE FROM webauthn_challenges
+WHERE expires_at < NOW()
+`
+
+func (q *Queries) DeleteExpiredWebAuthnChallenges(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredWebAuthnChallenges)
+	return err
+}
+
+const deletePasskey = `-- name: DeletePasskey :execrows
+DELETE FROM webauthn_credentials
+WHERE id = $1
+  AND user_id = $2
+`
+
+type DeletePasskeyParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeletePasskey(ctx context.Context, arg DeletePasskeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePasskey, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getPasskeyByCredentialIDForUpdate = `-- name: GetPasskeyByCredentialIDForUpdate :one
+SELECT id, user_id, public_key, sign_count
+FROM webauthn_credentials
+WHERE credential_id = $1
+FOR UPDATE
+`
+
+type GetPasskeyByCredentialIDForUpdateRow struct {
+	ID        int64     `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	PublicKey []byte    `json:"public_key"`
+	SignCount int64     `json:"sign_count"`
+}
+
+func (q *Queries) GetPasskeyByCredentialIDForUpdate(ctx context.Context, credentialID []byte) (GetPasskeyByCredentialIDForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getPasskeyByCredentialIDForUpdate, credentialID)
+	var i GetPasskeyByCredentialIDForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PublicKey,
+		&i.SignCount,
+	)
+	return i, err
+}
+
+const getWebAuthnChallengeForUpdate = `-- name: GetWebAuthnChallengeForUpdate :one
+SELECT id, user_id, purpose, expires_at, used_at
+FROM webauthn_challenges
+WHERE challenge_hash = $1
+FOR UPDATE
+`
+
+type GetWebAuthnChallengeForUpdateRow struct {
+	ID        int64         `json:"id"`
+	UserID    uuid.NullUUID `json:"user_id"`
+	Purpose   string        `json:"purpose"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	UsedAt    sql.NullTime  `json:"used_at"`
+}
+
+func (q *Queries) GetWebAuthnChallengeForUpdate(ctx context.Context, challengeHash string) (GetWebAuthnChallengeForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getWebAuthnChallengeForUpdate, challengeHash)
+	var i GetWebAuthnChallengeForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Purpose,
+		&i.ExpiresAt,
+		&i.UsedAt,
+	)
+	return i, err
+}
+
+const listPasskeys = `-- name: ListPasskeys :many
+SELECT id, credential_id, transports, name, backup_eligible, created_at, last_used_at
+FROM webauthn_credentials
+WHERE user_id = $1
+ORDER BY created_at, id
+`
+
+type ListPasskeysRow struct {
+	ID             int64        `json:"id"`
+	CredentialID   []byte       `json:"credential_id"`
+	Transports     string       `json:"transports"`
+	Name           string       `json:"name"`
+	BackupEligible bool         `json:"backup_eligible"`
+	CreatedAt      time.Time    `json:"created_at"`
+	LastUsedAt     sql.NullTime `json:"last_used_at"`
+}
+
+func (q *Queries) ListPasskeys(ctx context.Context, userID uuid.UUID) ([]ListPasskeysRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPasskeys, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPasskeysRow
+	for rows.Next() {
+		var i ListPasskeysRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CredentialID,
+			&i.Transports,
+			&i.Name,
+			&i.BackupEligible,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markWebAuthnChallengeUsed = `-- name: MarkWebAuthnChallengeUsed :exec
+UPDATE webauthn_challenges
+SET used_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkWebAuthnChallengeUsed(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markWebAuthnChallengeUsed, id)
+	return err
+}
+
+const recordPasskeyUse = `-- name: RecordPasskeyUse :exec
+UPDATE webauthn_credentials
+SET sign_count = $1,
+    last_used_at = NOW()
+WHERE id = $2
+`
+
+type RecordPasskeyUseParams struct {
+	SignCount int64 `json:"sign_count"`
+	ID        int64 `json:"id"`
+}
+
+func (q *Queries) RecordPasskeyUse(ctx context.Context, arg RecordPasskeyUseParams) error {
+	_, err := q.db.ExecContext(ctx, recordPasskeyUse, arg.SignCount, arg.ID)
+	return err
+}
@@ -0,0 +1,235 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: password_reset.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const claimPasswordResetRequest = `-- name: ClaimPasswordResetRequest :one
+UPDATE password_reset_requests
+SET processed_at = NOW()
+WHERE id = (
+  SELECT r.id FROM password_reset_requests r
+  WHERE r.processed_at IS NULL
+  ORDER BY r.id
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, email
+`
+
+type ClaimPasswordResetRequestRow struct {
+	ID    int64  `json:"id"`
+	Email string `json:"email"`
+}
+
+// Marks the oldest unprocessed request processed. SKIP LOCKED lets several
+// backend instances run workers without picking the same request.
+func (q *Queries) ClaimPasswordResetRequest(ctx context.Context) (ClaimPasswordResetRequestRow, error) {
+	row := q.db.QueryRowContext(ctx, claimPasswordResetRequest)
+	var i ClaimPasswordResetRequestRow
+	err := row.Scan(&i.ID, &i.Email)
+	return i, err
+}
+
+const createPasswordResetRequest = `-- name: CreatePasswordResetRequest :exec
+INSERT INTO password_reset_requests (email)
+VALUES ($1)
+`
+
+func (q *Queries) CreatePasswordResetRequest(ctx context.Context, email string) error {
+	_, err := q.db.ExecContext(ctx, createPasswordResetRequest, email)
+	return err
+}
+
+const createPasswordResetToken = `-- name: CreatePasswordResetToken :exec
+INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+VALUES ($1, $2, $3)
+`
+
+type CreatePasswordResetTokenParams struct {
+	UserID    uuid.UUID `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) error {
+	_, err := q.db.ExecContext(ctx, createPasswordResetToken, arg.UserID, arg.TokenHash, arg.ExpiresAt)
+	return err
+}
+
+const deleteExpiredPasswordResetTokens = `-- name: DeleteExpiredPasswordResetTokens :exec
+DELETE FROM password_reset_tokens
+WHERE user_id = $1
+  AND expires_at < NOW()
+`
+
+func (q *Queries) DeleteExpiredPasswordResetTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredPasswordResetTokens, userID)
+	return err
+}
+
+const deletePasswordResetRequestsBefore = `-- name: DeletePasswordResetRequestsBefore :execrows
+DELETE FROM password_reset_requests
+WHERE requested_at < $1
+  AND processed_at IS NOT NULL
+`
+
+func (q *Queries) DeletePasswordResetRequestsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePasswordResetRequestsBefore, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getPasswordResetRequestCount = `-- name: GetPasswordResetRequestCount :one
+SELECT
+    COUNT(*)::int AS last_hour,
+    COALESCE(MIN(requested_at), NOW())::timestamptz AS oldest_in_hour
+FROM password_reset_requests
+WHERE lower(email) = lower($1)
+  AND requested_at > NOW() - INTERVAL '1 hour'
+`
+
+type GetPasswordResetRequestCountRow struct {
+	LastHour     int32     `json:"last_hour"`
+	OldestInHour time.Time `json:"oldest_in_hour"`
+}
+
+// Counts the reset requests for email in the last hour, with the oldest
+// request time in that window (now when the window is empty).
+func (q *Queries) GetPasswordResetRequestCount(ctx context.Context, email string) (GetPasswordResetRequestCountRow, error) {
+	row := q.db.QueryRowContext(ctx, getPasswordResetRequestCount, email)
+	var i GetPasswordResetRequestCountRow
+	err := row.Scan(&i.LastHour, &i.OldestInHour)
+	return i, err
+}
+
+const getPasswordResetTokenForUpdate = `-- name: GetPasswordResetTokenForUpdate :one
+SELECT id, user_id, expires_at, used_at
+FROM password_reset_tokens
+WHERE token_hash = $1
+FOR UPDATE
+`
+
+type GetPasswordResetTokenForUpdateRow struct {
+	ID        int64        `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+}
+
+func (q *Queries) GetPasswordResetTokenForUpdate(ctx context.Context, tokenHash string) (GetPasswordResetTokenForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getPasswordResetTokenForUpdate, tokenHash)
+	var i GetPasswordResetTokenForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.UsedAt,
+	)
+	return i, err
+}
+
+const getPasswordUserByRecoveryEmail = `-- name: GetPasswordUserByRecoveryEmail :one
+SELECT u.user_id, u.user_name, e.email
+FROM recovery_emails e
+JOIN users u ON u.user_id = e.user_id
+WHERE lower(e.email) = lower($1)
+  AND u.hashed_passwd IS NOT NULL
+`
+
+type GetPasswordUserByRecoveryEmailRow struct {
+	UserID   uuid.UUID `json:"user_id"`
+	UserName string    `json:"user_name"`
+	Email    string    `json:"email"`
+}
+
+// The account a reset link for email goes to. Accounts without a password
+// (OAuth sign-ups) cannot reset one.
+func (q *Queries) GetPasswordUserByRecoveryEmail(ctx context.Context, email string) (GetPasswordUserByRecoveryEmailRow, error) {
+	row := q.db.QueryRowContext(ctx, getPasswordUserByRecoveryEmail, email)
+	var i GetPasswordUserByRecoveryEmailRow
+	err := row.Scan(&i.UserID, &i.UserName, &i.Email)
+	return i, err
+}
+
+const getRecoveryEmailOwner = `-- name: GetRecoveryEmailOwner :one
+SELECT user_id
+FROM recovery_emails
+WHERE lower(email) = lower($1)
+`
+
+func (q *Queries) GetRecoveryEmailOwner(ctx context.Context, email string) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, getRecoveryEmailOwner, email)
+	var user_id uuid.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const lockPasswordResetEmail = `-- name: LockPasswordResetEmail :exec
+SELECT pg_advisory_xact_lock(hashtext('password_reset:' || lower(s.email)))
+FROM (SELECT $1::text AS email) s
+`
+
+// Serializes RequestPasswordReset per address so concurrent requests cannot
+// overshoot the rate limit. Held until the transaction ends.
+func (q *Queries) LockPasswordResetEmail(ctx context.Context, email string) error {
+	_, err := q.db.ExecContext(ctx, lockPasswordResetEmail, email)
+	return err
+}
+
+const retirePasswordResetTokens = `-- name: RetirePasswordResetTokens :exec
+UPDATE password_reset_tokens
+SET used_at = NOW()
+WHERE user_id = $1
+  AND used_at IS NULL
+`
+
+// Marks every outstanding reset token of the user used.
+func (q *Queries) RetirePasswordResetTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, retirePasswordResetTokens, userID)
+	return err
+}
+
+const setRecoveryEmail = `-- name: SetRecoveryEmail :exec
+INSERT INTO recovery_emails (user_id, email)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET email = EXCLUDED.email, updated_at = NOW()
+`
+
+type SetRecoveryEmailParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+}
+
+func (q *Queries) SetRecoveryEmail(ctx context.Context, arg SetRecoveryEmailParams) error {
+	_, err := q.db.ExecContext(ctx, setRecoveryEmail, arg.UserID, arg.Email)
+	return err
+}
+
+const updatePassword = `-- name: UpdatePassword :exec
+UPDATE users
+SET hashed_passwd = $1, updated_at = NOW()
+WHERE user_id = $2
+`
+
+type UpdatePasswordParams struct {
+	HashedPasswd sql.NullString `json:"hashed_passwd"`
+	UserID       uuid.UUID      `json:"user_id"`
+}
+
+func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updatePassword, arg.HashedPasswd, arg.UserID)
+	return err
+}
@@ -0,0 +1,101 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: refresh_tokens.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createRefreshToken = `-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (user_id, login_id, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateRefreshTokenParams struct {
+	UserID    uuid.UUID `json:"user_id"`
+	LoginID   uuid.UUID `json:"login_id"`
+	TokenHash string    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error {
+	_, err := q.db.ExecContext(ctx, createRefreshToken,
+		arg.UserID,
+		arg.LoginID,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const deleteExpiredRefreshTokens = `-- name: DeleteExpiredRefreshTokens :exec
+DELETE FROM refresh_tokens
+WHERE user_id = $1
+  AND expires_at < NOW()
+`
+
+func (q *Queries) DeleteExpiredRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredRefreshTokens, userID)
+	return err
+}
+
+const getRefreshTokenForUpdate = `-- name: GetRefreshTokenForUpdate :one
+SELECT id, user_id, login_id, expires_at, used_at, revoked_at
+FROM refresh_tokens
+WHERE token_hash = $1
+FOR UPDATE
+`
+
+type GetRefreshTokenForUpdateRow struct {
+	ID        int64        `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	LoginID   uuid.UUID    `json:"login_id"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+}
+
+func (q *Queries) GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (GetRefreshTokenForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getRefreshTokenForUpdate, tokenHash)
+	var i GetRefreshTokenForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.LoginID,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const markRefreshTokenUsed = `-- name: MarkRefreshTokenUsed :exec
+UPDATE refresh_tokens
+SET used_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkRefreshTokenUsed(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markRefreshTokenUsed, id)
+	return err
+}
+
+const revokeLoginRefreshTokens = `-- name: RevokeLoginRefreshTokens :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE login_id = $1
+  AND revoked_at IS NULL
+`
+
+// Revokes every refresh token of one login.
+func (q *Queries) RevokeLoginRefreshTokens(ctx context.Context, loginID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, revokeLoginRefreshTokens, loginID)
+	return err
+}
@@ -0,0 +1,112 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: settings.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const audienceAllows = `-- name: AudienceAllows :one
+SELECT audience_allows($1::privacy_audience, $2::uuid, $3::uuid)::bool AS allowed
+`
+
+type AudienceAllowsParams struct {
+	Audience PrivacyAudience `json:"audience"`
+	OwnerID  uuid.UUID       `json:"owner_id"`
+	ViewerID uuid.UUID       `json:"viewer_id"`
+}
+
+// Reports whether @viewer_id is within @audience as seen from @owner_id.
+func (q *Queries) AudienceAllows(ctx context.Context, arg AudienceAllowsParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, audienceAllows, arg.Audience, arg.OwnerID, arg.ViewerID)
+	var allowed bool
+	err := row.Scan(&allowed)
+	return allowed, err
+}
+
+const getPrivacySettings = `-- name: GetPrivacySettings :one
+SELECT u.user_id,
+    COALESCE(s.discoverability, 'everyone')::privacy_audience AS discoverability,
+    COALESCE(s.friend_request_policy, 'everyone')::privacy_audience AS friend_request_policy,
+    COALESCE(s.read_receipts, TRUE)::bool AS read_receipts,
+    COALESCE(s.last_seen_visibility, 'everyone')::privacy_audience AS last_seen_visibility
+FROM users u
+LEFT JOIN user_settings s ON s.user_id = u.user_id
+WHERE u.user_id = $1
+`
+
+type GetPrivacySettingsRow struct {
+	UserID              uuid.UUID       `json:"user_id"`
+	Discoverability     PrivacyAudience `json:"discoverability"`
+	FriendRequestPolicy PrivacyAudience `json:"friend_request_policy"`
+	ReadReceipts        bool            `json:"read_receipts"`
+	LastSeenVisibility  PrivacyAudience `json:"last_seen_visibility"`
+}
+
+// Returns the user's privacy settings, falling back to the defaults for users
+// who never changed them.
+func (q *Queries) GetPrivacySettings(ctx context.Context, userID uuid.UUID) (GetPrivacySettingsRow, error) {
+	row := q.db.QueryRowContext(ctx, getPrivacySettings, userID)
+	var i GetPrivacySettingsRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Discoverability,
+		&i.FriendRequestPolicy,
+		&i.ReadReceipts,
+		&i.LastSeenVisibility,
+	)
+	return i, err
+}
+
+const upsertPrivacySettings = `-- name: UpsertPrivacySettings :one
+INSERT INTO user_settings (user_id, discoverability, friend_request_policy, read_receipts, last_seen_visibility)
+VALUES (
+    $1,
+    COALESCE($2::privacy_audience, 'everyone'),
+    COALESCE($3::privacy_audience, 'everyone'),
+    COALESCE($4::bool, TRUE),
+    COALESCE($5::privacy_audience, 'everyone')
+)
+ON CONFLICT (user_id) DO UPDATE
+SET discoverability       = COALESCE($2::privacy_audience, user_settings.discoverability),
+    friend_request_policy = COALESCE($3::privacy_audience, user_settings.friend_request_policy),
+    read_receipts         = COALESCE($4::bool, user_settings.read_receipts),
+    last_seen_visibility  = COALESCE($5::privacy_audience, user_settings.last_seen_visibility),
+    updated_at            = NOW()
+RETURNING user_id, discoverability, friend_request_policy, read_receipts, last_seen_visibility, updated_at
+`
+
+type UpsertPrivacySettingsParams struct {
+	UserID              uuid.UUID           `json:"user_id"`
+	Discoverability     NullPrivacyAudience `json:"discoverability"`
+	FriendRequestPolicy NullPrivacyAudience `json:"friend_request_policy"`
+	ReadReceipts        sql.NullBool        `json:"read_receipts"`
+	LastSeenVisibility  NullPrivacyAudience `json:"last_seen_visibility"`
+}
+
+// Changes the settings that are not NULL and keeps the rest.
+func (q *Queries) UpsertPrivacySettings(ctx context.Context, arg UpsertPrivacySettingsParams) (UserSetting, error) {
+	row := q.db.QueryRowContext(ctx, upsertPrivacySettings,
+		arg.UserID,
+		arg.Discoverability,
+		arg.FriendRequestPolicy,
+		arg.ReadReceipts,
+		arg.LastSeenVisibility,
+	)
+	var i UserSetting
+	err := row.Scan(
+		&i.UserID,
+		&i.Discoverability,
+		&i.FriendRequestPolicy,
+		&i.ReadReceipts,
+		&i.LastSeenVisibility,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
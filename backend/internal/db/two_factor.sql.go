@@ -0,0 +1,264 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.31.1
+// source: two_factor.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const confirmTOTPFactor = `-- name: ConfirmTOTPFactor :exec
+UPDATE totp_factors
+SET confirmed_at = NOW()
+WHERE user_id = $1
+`
+
+func (q *Queries) ConfirmTOTPFactor(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, confirmTOTPFactor, userID)
+	return err
+}
+
+const createLoginChallenge = `-- name: CreateLoginChallenge :exec
+INSERT INTO login_challenges (user_id, token_hash, device_name, expires_at)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateLoginChallengeParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	TokenHash  string    `json:"token_hash"`
+	DeviceName string    `json:"device_name"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateLoginChallenge(ctx context.Context, arg CreateLoginChallengeParams) error {
+	_, err := q.db.ExecContext(ctx, createLoginChallenge,
+		arg.UserID,
+		arg.TokenHash,
+		arg.DeviceName,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createRecoveryCode = `-- name: CreateRecoveryCode :exec
+INSERT INTO totp_recovery_codes (user_id, code_hash)
+VALUES ($1, $2)
+`
+
+type CreateRecoveryCodeParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	CodeHash string    `json:"code_hash"`
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error {
+	_, err := q.db.ExecContext(ctx, createRecoveryCode, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const deleteExpiredLoginChallenges = `-- name: DeleteExpiredLoginChallenges :exec
+DELETE FROM login_challenges
+WHERE user_id = $1
+  AND expires_at < NOW()
+`
+
+func (q *Queries) DeleteExpiredLoginChallenges(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredLoginChallenges, userID)
+	return err
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE FROM totp_recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteRecoveryCodes, userID)
+	return err
+}
+
+const deleteTOTPFactor = `-- name: DeleteTOTPFactor :exec
+DELETE FROM totp_factors
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteTOTPFactor(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTOTPFactor, userID)
+	return err
+}
+
+const getLoginChallengeForUpdate = `-- name: GetLoginChallengeForUpdate :one
+SELECT id, user_id, device_name, expires_at, used_at
+FROM login_challenges
+WHERE token_hash = $1
+FOR UPDATE
+`
+
+type GetLoginChallengeForUpdateRow struct {
+	ID         int64        `json:"id"`
+	UserID     uuid.UUID    `json:"user_id"`
+	DeviceName string       `json:"device_name"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+	UsedAt     sql.NullTime `json:"used_at"`
+}
+
+func (q *Queries) GetLoginChallengeForUpdate(ctx context.Context, tokenHash string) (GetLoginChallengeForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getLoginChallengeForUpdate, tokenHash)
+	var i GetLoginChallengeForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.DeviceName,
+		&i.ExpiresAt,
+		&i.UsedAt,
+	)
+	return i, err
+}
+
+const getTOTPFactorForUpdate = `-- name: GetTOTPFactorForUpdate :one
+SELECT secret, confirmed_at, last_used_step, failed_attempts, last_failed_at
+FROM totp_factors
+WHERE user_id = $1
+FOR UPDATE
+`
+
+type GetTOTPFactorForUpdateRow struct {
+	Secret         string       `json:"secret"`
+	ConfirmedAt    sql.NullTime `json:"confirmed_at"`
+	LastUsedStep   int64        `json:"last_used_step"`
+	FailedAttempts int32        `json:"failed_attempts"`
+	LastFailedAt   sql.NullTime `json:"last_failed_at"`
+}
+
+func (q *Queries) GetTOTPFactorForUpdate(ctx context.Context, userID uuid.UUID) (GetTOTPFactorForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getTOTPFactorForUpdate, userID)
+	var i GetTOTPFactorForUpdateRow
+	err := row.Scan(
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+		&i.FailedAttempts,
+		&i.LastFailedAt,
+	)
+	return i, err
+}
+
+const isTOTPEnabled = `-- name: IsTOTPEnabled :one
+SELECT EXISTS (
+    SELECT 1 FROM totp_factors
+    WHERE user_id = $1
+      AND confirmed_at IS NOT NULL
+)::boolean
+`
+
+func (q *Queries) IsTOTPEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isTOTPEnabled, userID)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const markLoginChallengeUsed = `-- name: MarkLoginChallengeUsed :exec
+UPDATE login_challenges
+SET used_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkLoginChallengeUsed(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markLoginChallengeUsed, id)
+	return err
+}
+
+const recordTOTPFailure = `-- name: RecordTOTPFailure :exec
+UPDATE totp_factors
+SET failed_attempts = CASE
+        WHEN last_failed_at >= $1::timestamptz THEN failed_attempts + 1
+        ELSE 1
+    END,
+    last_failed_at = NOW()
+WHERE user_id = $2
+`
+
+type RecordTOTPFailureParams struct {
+	ResetBefore time.Time `json:"reset_before"`
+	UserID      uuid.UUID `json:"user_id"`
+}
+
+// Counts a wrong code. Failures before reset_before no longer count.
+func (q *Queries) RecordTOTPFailure(ctx context.Context, arg RecordTOTPFailureParams) error {
+	_, err := q.db.ExecContext(ctx, recordTOTPFailure, arg.ResetBefore, arg.UserID)
+	return err
+}
+
+const recordTOTPSuccess = `-- name: RecordTOTPSuccess :exec
+UPDATE totp_factors
+SET last_used_step = GREATEST(last_used_step, $1::bigint),
+    failed_attempts = 0,
+    last_failed_at = NULL
+WHERE user_id = $2
+`
+
+type RecordTOTPSuccessParams struct {
+	Step   int64     `json:"step"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// Clears the failure count. step is the time step of an accepted TOTP code;
+// codes at or before it are refused from now on.
+func (q *Queries) RecordTOTPSuccess(ctx context.Context, arg RecordTOTPSuccessParams) error {
+	_, err := q.db.ExecContext(ctx, recordTOTPSuccess, arg.Step, arg.UserID)
+	return err
+}
+
+const startTOTPEnrollment = `-- name: StartTOTPEnrollment :execrows
+INSERT INTO totp_factors (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret,
+    created_at = NOW(),
+    last_used_step = 0,
+    failed_attempts = 0,
+    last_failed_at = NULL
+WHERE totp_factors.confirmed_at IS NULL
+`
+
+type StartTOTPEnrollmentParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Secret string    `json:"secret"`
+}
+
+// Stores a new, unconfirmed secret. Affects no rows if two-factor sign-in is
+// already on.
+func (q *Queries) StartTOTPEnrollment(ctx context.Context, arg StartTOTPEnrollmentParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, startTOTPEnrollment, arg.UserID, arg.Secret)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :execrows
+UPDATE totp_recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL
+`
+
+type UseRecoveryCodeParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	CodeHash string    `json:"code_hash"`
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useRecoveryCode, arg.UserID, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -8,14 +8,50 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const changeUsername = `-- name: ChangeUsername :one
+UPDATE users
+SET user_name  = $1,
+    updated_at = NOW()
+WHERE user_id = $2
+RETURNING user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at, bio, status_text, status_expires_at
+`
+
+type ChangeUsernameParams struct {
+	NewUserName string    `json:"new_user_name"`
+	UserID      uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) ChangeUsername(ctx context.Context, arg ChangeUsernameParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, changeUsername, arg.NewUserName, arg.UserID)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.UserName,
+		&i.HashedPasswd,
+		&i.SignupType,
+		&i.DisplayName,
+		&i.AvatarUrl,
+		&i.EncryptedPrivateKey,
+		&i.IsE2eeReady,
+		&i.LastSeenAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Bio,
+		&i.StatusText,
+		&i.StatusExpiresAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (user_name, hashed_passwd, signup_type)
 VALUES ($1, $2, $3)
-RETURNING user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at
+RETURNING user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at, bio, status_text, status_expires_at
 `
 
 type CreateUserParams struct {
@@ -39,6 +75,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.LastSeenAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Bio,
+		&i.StatusText,
+		&i.StatusExpiresAt,
 	)
 	return i, err
 }
@@ -61,8 +100,24 @@ func (q *Queries) GetE2EEKeysByUserID(ctx context.Context, userID uuid.UUID) (Ge
 	return i, err
 }
 
+const getLastUsernameChange = `-- name: GetLastUsernameChange :one
+SELECT changed_at
+FROM username_history
+WHERE user_id = $1
+ORDER BY changed_at DESC
+LIMIT 1
+`
+
+// Returns when user_id last changed their username.
+func (q *Queries) GetLastUsernameChange(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getLastUsernameChange, userID)
+	var changed_at time.Time
+	err := row.Scan(&changed_at)
+	return changed_at, err
+}
+
 const getUserByID = `-- name: GetUserByID :one
-SELECT user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at
+SELECT user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at, bio, status_text, status_expires_at
 FROM users
 WHERE user_id = $1
 LIMIT 1
@@ -83,12 +138,36 @@ func (q *Queries) GetUserByID(ctx context.Context, userID uuid.UUID) (User, erro
 		&i.LastSeenAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Bio,
+		&i.StatusText,
+		&i.StatusExpiresAt,
 	)
 	return i, err
 }
 
+const getUserByIDForUpdate = `-- name: GetUserByIDForUpdate :one
+SELECT user_id, user_name
+FROM users
+WHERE user_id = $1
+FOR UPDATE
+`
+
+type GetUserByIDForUpdateRow struct {
+	UserID   uuid.UUID `json:"user_id"`
+	UserName string    `json:"user_name"`
+}
+
+// Locks the user row so changes that check earlier ones, like the username
+// change cooldown, run one at a time per user.
+func (q *Queries) GetUserByIDForUpdate(ctx context.Context, userID uuid.UUID) (GetUserByIDForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserByIDForUpdate, userID)
+	var i GetUserByIDForUpdateRow
+	err := row.Scan(&i.UserID, &i.UserName)
+	return i, err
+}
+
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at
+SELECT user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at, bio, status_text, status_expires_at
 FROM users
 WHERE user_name = $1
 LIMIT 1
@@ -109,37 +188,145 @@ func (q *Queries) GetUserByUsername(ctx context.Context, userName string) (User,
 		&i.LastSeenAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Bio,
+		&i.StatusText,
+		&i.StatusExpiresAt,
 	)
 	return i, err
 }
 
+const getUsernameReservation = `-- name: GetUsernameReservation :one
+SELECT user_id
+FROM username_history
+WHERE user_name = $1
+  AND changed_at > $2
+ORDER BY changed_at DESC
+LIMIT 1
+`
+
+type GetUsernameReservationParams struct {
+	UserName      string    `json:"user_name"`
+	ReleasedAfter time.Time `json:"released_after"`
+}
+
+// Returns the user who released user_name most recently after released_after,
+// i.e. who still holds a reservation on it.
+func (q *Queries) GetUsernameReservation(ctx context.Context, arg GetUsernameReservationParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, getUsernameReservation, arg.UserName, arg.ReleasedAfter)
+	var user_id uuid.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const insertUsernameHistory = `-- name: InsertUsernameHistory :exec
+INSERT INTO username_history (user_id, user_name)
+VALUES ($1, $2)
+`
+
+type InsertUsernameHistoryParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	UserName string    `json:"user_name"`
+}
+
+func (q *Queries) InsertUsernameHistory(ctx context.Context, arg InsertUsernameHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, insertUsernameHistory, arg.UserID, arg.UserName)
+	return err
+}
+
+const listProfileAudience = `-- name: ListProfileAudience :many
+SELECT DISTINCT peer_id::uuid AS user_id
+FROM (
+  SELECT CASE WHEN f.user1_userid = $1 THEN f.user2_userid ELSE f.user1_userid END AS peer_id
+  FROM friendships f
+  WHERE (f.user1_userid = $1 OR f.user2_userid = $1)
+    AND f.status = 'accepted'
+  UNION
+  SELECT other.user_id
+  FROM conversation_members me
+  JOIN conversation_members other ON other.conversation_id = me.conversation_id
+  WHERE me.user_id = $1
+    AND other.user_id != $1
+) peers
+WHERE NOT EXISTS (
+  SELECT 1 FROM blocks b
+  WHERE (b.blocker_id = $1 AND b.blocked_id = peers.peer_id)
+     OR (b.blocker_id = peers.peer_id AND b.blocked_id = $1)
+)
+`
+
+// Returns everyone who should see user_id's profile changes live: accepted
+// friends and members of any shared conversation, minus users blocked by or
+// blocking them.
+func (q *Queries) ListProfileAudience(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listProfileAudience, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchUsers = `-- name: SearchUsers :many
-SELECT u.user_id, u.user_name, u.display_name, u.avatar_url,
+WITH ranked AS (
+  SELECT u.user_id, u.user_name, u.display_name, u.avatar_url,
+      (lower(u.user_name) = lower($6::text))::int AS exact_match,
+      GREATEST(
+        similarity(u.user_name, $6::text),
+        similarity(COALESCE(u.display_name, ''), $6::text)
+      )::float8 AS score
+  FROM users u
+  LEFT JOIN user_settings us ON us.user_id = u.user_id
+  WHERE u.user_id != $1
+    AND NOT EXISTS (SELECT 1 FROM blocks b WHERE b.blocker_id = u.user_id AND b.blocked_id = $1)
+    AND audience_allows(COALESCE(us.discoverability, 'everyone'), u.user_id, $1)
+    AND (u.user_name ILIKE '%' || $6::text || '%'
+      OR u.display_name ILIKE '%' || $6::text || '%'
+      OR u.user_name % $6::text
+      OR u.display_name % $6::text)
+)
+SELECT r.user_id, r.user_name, r.display_name, r.avatar_url, r.exact_match, r.score,
     COALESCE(
       (SELECT f.status::text FROM friendships f
-       WHERE (f.user1_userid = $2 AND f.user2_userid = u.user_id)
-          OR (f.user1_userid = u.user_id AND f.user2_userid = $2)
+       WHERE (f.user1_userid = $1 AND f.user2_userid = r.user_id)
+          OR (f.user1_userid = r.user_id AND f.user2_userid = $1)
        LIMIT 1),
       ''
     ) AS friendship_status,
     COALESCE(
       (SELECT f.initiator_userid::text FROM friendships f
-       WHERE (f.user1_userid = $2 AND f.user2_userid = u.user_id)
-          OR (f.user1_userid = u.user_id AND f.user2_userid = $2)
+       WHERE (f.user1_userid = $1 AND f.user2_userid = r.user_id)
+          OR (f.user1_userid = r.user_id AND f.user2_userid = $1)
        LIMIT 1),
       ''
     ) AS friendship_initiator_userid
-FROM users u
-WHERE (u.user_name ILIKE '%' || $1 || '%'
-    OR u.display_name ILIKE '%' || $1 || '%')
-  AND u.user_id != $2
-ORDER BY u.user_name
-LIMIT 50
+FROM ranked r
+WHERE $2::uuid IS NULL
+   OR (r.exact_match, r.score, r.user_id) < ($3::int, $4::float8, $2::uuid)
+ORDER BY r.exact_match DESC, r.score DESC, r.user_id DESC
+LIMIT $5
 `
 
 type SearchUsersParams struct {
-	Column1     sql.NullString `json:"column_1"`
-	User1Userid uuid.UUID      `json:"user1_userid"`
+	CallerID     uuid.UUID       `json:"caller_id"`
+	CursorUserID uuid.NullUUID   `json:"cursor_user_id"`
+	CursorExact  sql.NullInt32   `json:"cursor_exact"`
+	CursorScore  sql.NullFloat64 `json:"cursor_score"`
+	PageLimit    int32           `json:"page_limit"`
+	Query        string          `json:"query"`
 }
 
 type SearchUsersRow struct {
@@ -147,14 +334,28 @@ type SearchUsersRow struct {
 	UserName                  string         `json:"user_name"`
 	DisplayName               sql.NullString `json:"display_name"`
 	AvatarUrl                 sql.NullString `json:"avatar_url"`
+	ExactMatch                int32          `json:"exact_match"`
+	Score                     float64        `json:"score"`
 	FriendshipStatus          interface{}    `json:"friendship_status"`
 	FriendshipInitiatorUserid interface{}    `json:"friendship_initiator_userid"`
 }
 
-// Searches for users by username or display name, excluding the caller.
+// Searches for users by username or display name, excluding the caller,
+// anyone who has blocked the caller, and anyone whose discoverability setting
+// does not include the caller.
+// Matches substrings and trigram-similar names; exact username matches come
+// first, then the rest by similarity. Keyset-paged on (exact_match, score, user_id):
+// pass the last row's values as the cursor (all NULL for the first page).
 // Returns the friendship status and initiator user ID if a relationship exists, or empty string otherwise.
 func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
-	rows, err := q.db.QueryContext(ctx, searchUsers, arg.Column1, arg.User1Userid)
+	rows, err := q.db.QueryContext(ctx, searchUsers,
+		arg.CallerID,
+		arg.CursorUserID,
+		arg.CursorExact,
+		arg.CursorScore,
+		arg.PageLimit,
+		arg.Query,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +368,8 @@ func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]Sea
 			&i.UserName,
 			&i.DisplayName,
 			&i.AvatarUrl,
+			&i.ExactMatch,
+			&i.Score,
 			&i.FriendshipStatus,
 			&i.FriendshipInitiatorUserid,
 		); err != nil {
@@ -204,31 +407,91 @@ func (q *Queries) SetE2EEKeys(ctx context.Context, arg SetE2EEKeysParams) error
 const updateLastSeen = `-- name: UpdateLastSeen :exec
 UPDATE users
 SET last_seen_at = NOW()
-WHERE user_name = $1
+WHERE user_id = $1
 `
 
-func (q *Queries) UpdateLastSeen(ctx context.Context, userName string) error {
-	_, err := q.db.ExecContext(ctx, updateLastSeen, userName)
+func (q *Queries) UpdateLastSeen(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, updateLastSeen, userID)
 	return err
 }
 
+const updateProfile = `-- name: UpdateProfile :one
+UPDATE users
+SET display_name      = CASE WHEN $1::bool THEN NULLIF($2::text, '') ELSE display_name END,
+    avatar_url        = CASE WHEN $3::bool THEN NULLIF($4::text, '') ELSE avatar_url END,
+    bio               = CASE WHEN $5::bool THEN NULLIF($6::text, '') ELSE bio END,
+    status_text       = CASE WHEN $7::bool THEN NULLIF($8::text, '') ELSE status_text END,
+    status_expires_at = CASE WHEN $7::bool THEN $9::timestamptz ELSE status_expires_at END,
+    updated_at        = NOW()
+WHERE user_id = $10
+RETURNING user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at, bio, status_text, status_expires_at
+`
+
+type UpdateProfileParams struct {
+	SetDisplayName  bool         `json:"set_display_name"`
+	DisplayName     string       `json:"display_name"`
+	SetAvatarUrl    bool         `json:"set_avatar_url"`
+	AvatarUrl       string       `json:"avatar_url"`
+	SetBio          bool         `json:"set_bio"`
+	Bio             string       `json:"bio"`
+	SetStatus       bool         `json:"set_status"`
+	StatusText      string       `json:"status_text"`
+	StatusExpiresAt sql.NullTime `json:"status_expires_at"`
+	UserID          uuid.UUID    `json:"user_id"`
+}
+
+// Updates the profile fields whose set_* flag is true; the rest are kept.
+// An empty string clears a field. Setting the status always replaces its expiry.
+func (q *Queries) UpdateProfile(ctx context.Context, arg UpdateProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateProfile,
+		arg.SetDisplayName,
+		arg.DisplayName,
+		arg.SetAvatarUrl,
+		arg.AvatarUrl,
+		arg.SetBio,
+		arg.Bio,
+		arg.SetStatus,
+		arg.StatusText,
+		arg.StatusExpiresAt,
+		arg.UserID,
+	)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.UserName,
+		&i.HashedPasswd,
+		&i.SignupType,
+		&i.DisplayName,
+		&i.AvatarUrl,
+		&i.EncryptedPrivateKey,
+		&i.IsE2eeReady,
+		&i.LastSeenAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Bio,
+		&i.StatusText,
+		&i.StatusExpiresAt,
+	)
+	return i, err
+}
+
 const updateUserProfile = `-- name: UpdateUserProfile :one
 UPDATE users
 SET display_name = $2,
     avatar_url   = $3,
     updated_at   = NOW()
-WHERE user_name = $1
-RETURNING user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at
+WHERE user_id = $1
+RETURNING user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at, bio, status_text, status_expires_at
 `
 
 type UpdateUserProfileParams struct {
-	UserName    string         `json:"user_name"`
+	UserID      uuid.UUID      `json:"user_id"`
 	DisplayName sql.NullString `json:"display_name"`
 	AvatarUrl   sql.NullString `json:"avatar_url"`
 }
 
 func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserProfile, arg.UserName, arg.DisplayName, arg.AvatarUrl)
+	row := q.db.QueryRowContext(ctx, updateUserProfile, arg.UserID, arg.DisplayName, arg.AvatarUrl)
 	var i User
 	err := row.Scan(
 		&i.UserID,
@@ -242,6 +505,9 @@ func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfilePa
 		&i.LastSeenAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Bio,
+		&i.StatusText,
+		&i.StatusExpiresAt,
 	)
 	return i, err
 }
@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 
+	"github.com/gorilla/mux"
 	"github.com/zukigit/chat/backend/internal/clients"
 	"github.com/zukigit/chat/backend/internal/lib"
+	"github.com/zukigit/chat/backend/proto/auth"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -42,7 +46,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.authClient.Login(r.Context(), req.Username, req.Password)
+	resp, err := h.authClient.Login(lib.WithClientInfo(r.Context(), r), req.Username, req.Password, req.DeviceName)
 	if err != nil {
 		grpcStatus, _ := status.FromError(err)
 		switch grpcStatus.Code() {
@@ -56,6 +60,11 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 				Success: false,
 				Message: grpcStatus.Message(),
 			})
+		case codes.PermissionDenied:
+			lib.WriteJSON(w, http.StatusForbidden, lib.Response{
+				Success: false,
+				Message: grpcStatus.Message(),
+			})
 		default:
 			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{
 				Success: false,
@@ -65,10 +74,19 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if resp.SecondFactorRequired {
+		lib.WriteJSON(w, http.StatusOK, lib.Response{
+			Success: true,
+			Message: "second factor required",
+			Data:    map[string]any{"second_factor_required": true, "challenge_token": resp.ChallengeToken},
+		})
+		return
+	}
+
 	lib.WriteJSON(w, http.StatusOK, lib.Response{
 		Success: true,
 		Message: "login successful",
-		Data:    map[string]string{"token": token},
+		Data:    map[string]string{"token": resp.Token, "refresh_token": resp.RefreshToken},
 	})
 }
 
@@ -92,7 +110,7 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.authClient.Signup(r.Context(), req.Username, req.Password); err != nil {
+	if err := h.authClient.Signup(r.Context(), req.Username, req.Password, req.Email); err != nil {
 		grpcStatus, _ := status.FromError(err)
 		switch grpcStatus.Code() {
 		case codes.InvalidArgument:
@@ -100,7 +118,7 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 				Success: false,
 				Message: grpcStatus.Message(),
 			})
-		case codes.AlreadyExists:
+		case codes.AlreadyExists, codes.Aborted:
 			lib.WriteJSON(w, http.StatusConflict, lib.Response{
 				Success: false,
 				Message: grpcStatus.Message(),
@@ -120,8 +138,8 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// SearchUsers handles GET /users/search?q=<query>
-// Returns a list of users matching the query by username or display name.
+// SearchUsers handles GET /users/search?q=<query>&limit=<n>&cursor=<cursor>
+// Returns a page of users matching the query by username or display name.
 func (h *AuthHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
 	token, ok := lib.BearerToken(r)
 	if !ok || token == "" {
@@ -138,7 +156,16 @@ func (h *AuthHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.authClient.SearchUsers(r.Context(), token, query)
+	limit, err := parseLimit(r)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{
+			Success: false,
+			Message: "invalid limit query parameter",
+		})
+		return
+	}
+
+	resp, err := h.authClient.SearchUsers(r.Context(), token, query, limit, r.URL.Query().Get("cursor"))
 	if err != nil {
 		grpcStatus, _ := status.FromError(err)
 		switch grpcStatus.Code() {
@@ -164,33 +191,46 @@ func (h *AuthHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
 	lib.WriteJSON(w, http.StatusOK, lib.Response{
 		Success: true,
 		Message: "users found",
-		Data:    resp.Users,
+		Data:    resp,
 	})
 }
 
-// GetGithubOAuthURL handles POST /oauth/github/url
-// Returns the GitHub OAuth authorization URL.
-func (h *AuthHandler) GetGithubOAuthURL(w http.ResponseWriter, r *http.Request) {
-	url, err := h.authClient.GetGithubOAuthURL(r.Context())
+// GetOAuthURL handles POST /oauth/{provider}/url
+// Returns the provider's authorization URL.
+func (h *AuthHandler) GetOAuthURL(w http.ResponseWriter, r *http.Request) {
+	url, err := h.authClient.GetOAuthURL(r.Context(), mux.Vars(r)["provider"])
 	if err != nil {
 		grpcStatus, _ := status.FromError(err)
-		lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		code := http.StatusInternalServerError
+		switch grpcStatus.Code() {
+		case codes.NotFound:
+			code = http.StatusNotFound
+		case codes.Unavailable:
+			code = http.StatusBadGateway
+		}
+		lib.WriteJSON(w, code, lib.Response{Success: false, Message: grpcStatus.Message()})
 		return
 	}
 	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: map[string]string{"url": url}})
 }
 
-// GithubOAuthCallback handles GET /oauth/github/callback
-// GitHub redirects here after user authorization. The gateway exchanges the code
-// for a short-lived token and redirects to the frontend.
-func (h *AuthHandler) GithubOAuthCallback(w http.ResponseWriter, r *http.Request) {
+// OAuthCallback handles GET /oauth/{provider}/callback
+// The provider redirects here after user authorization. The gateway exchanges
+// the code for a short-lived token and redirects to the frontend.
+func (h *AuthHandler) OAuthCallback(w http.ResponseWriter, r *http.Request) {
 	frontendURL := lib.Getenv("FRONTEND_URL", "")
-	code := r.URL.Query().Get("code")
+	q := r.URL.Query()
+	// The user declined, or the provider refused the request.
+	if e := q.Get("error"); e != "" {
+		http.Redirect(w, r, frontendURL+"?error="+url.QueryEscape(e), http.StatusFound)
+		return
+	}
+	code := q.Get("code")
 	if code == "" {
 		http.Redirect(w, r, frontendURL+"?error=missing_code", http.StatusFound)
 		return
 	}
-	shortLivedToken, _, err := h.authClient.GithubOAuthCallback(r.Context(), code)
+	shortLivedToken, _, err := h.authClient.OAuthCallback(r.Context(), mux.Vars(r)["provider"], code, q.Get("state"))
 	if err != nil {
 		grpcStatus, _ := status.FromError(err)
 		http.Redirect(w, r, frontendURL+"?error="+url.QueryEscape(grpcStatus.Message()), http.StatusFound)
@@ -207,10 +247,182 @@ func (h *AuthHandler) ExchangeToken(w http.ResponseWriter, r *http.Request) {
 		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
 		return
 	}
-	longLivedToken, username, err := h.authClient.ExchangeToken(r.Context(), shortLivedToken)
+	// The body is optional: it only names the device.
+	var req exchangeTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	resp, err := h.authClient.ExchangeToken(lib.WithClientInfo(r.Context(), r), shortLivedToken, req.DeviceName)
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.PermissionDenied:
+			lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+	if resp.SecondFactorRequired {
+		lib.WriteJSON(w, http.StatusOK, lib.Response{
+			Success: true,
+			Message: "second factor required",
+			Data:    map[string]any{"username": resp.Username, "second_factor_required": true, "challenge_token": resp.ChallengeToken},
+		})
+		return
+	}
+	lib.WriteJSON(w, http.StatusOK, lib.Response{
+		Success: true,
+		Data:    map[string]string{"token": resp.Token, "username": resp.Username, "refresh_token": resp.RefreshToken},
+	})
+}
+
+// VerifySecondFactor handles POST /login/verify
+// Finishes a sign-in that returned second_factor_required, with a code from
+// the user's authenticator, a recovery code or a passkey credential answering
+// /login/verify/passkey/begin.
+func (h *AuthHandler) VerifySecondFactor(w http.ResponseWriter, r *http.Request) {
+	var req verifySecondFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	resp, err := h.authClient.VerifySecondFactor(lib.WithClientInfo(r.Context(), r), req.ChallengeToken, req.Code, string(req.Credential))
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.PermissionDenied:
+			lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.ResourceExhausted:
+			lib.WriteJSON(w, http.StatusTooManyRequests, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+	lib.WriteJSON(w, http.StatusOK, lib.Response{
+		Success: true,
+		Message: "login successful",
+		Data:    map[string]string{"token": resp.Token, "username": resp.Username, "refresh_token": resp.RefreshToken},
+	})
+}
+
+// RefreshToken handles POST /token/refresh
+// Trades a refresh token for a new access token and a new refresh token. The
+// presented refresh token stops working.
+func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req refreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	resp, err := h.authClient.RefreshToken(r.Context(), req.RefreshToken)
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.PermissionDenied:
+			lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+	lib.WriteJSON(w, http.StatusOK, lib.Response{
+		Success: true,
+		Data:    map[string]string{"token": resp.Token, "username": resp.Username, "refresh_token": resp.RefreshToken},
+	})
+}
+
+// Logout handles POST /logout
+// Signs out the login of the bearer token: its tokens stop working and its
+// live sessions are closed.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	if err := h.authClient.Logout(r.Context(), token); err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "logged out"})
+}
+
+// ListSessions handles GET /me/sessions
+// Lists the places the caller is signed in.
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	resp, err := h.authClient.ListSessions(r.Context(), token)
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: resp})
+}
+
+// RevokeSession handles DELETE /me/sessions/{login_id}
+// Signs out one of the caller's logins, e.g. on a lost device.
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	h.revokeSessions(w, r, mux.Vars(r)["login_id"], false)
+}
+
+// RevokeOtherSessions handles POST /me/sessions/revoke-others
+// Signs out every login of the caller except the current one.
+func (h *AuthHandler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	h.revokeSessions(w, r, "", true)
+}
+
+func (h *AuthHandler) revokeSessions(w http.ResponseWriter, r *http.Request, loginID string, allOthers bool) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	revoked, err := h.authClient.RevokeSession(r.Context(), token, loginID, allOthers)
 	if err != nil {
 		grpcStatus, _ := status.FromError(err)
 		switch grpcStatus.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.NotFound:
+			lib.WriteJSON(w, http.StatusNotFound, lib.Response{Success: false, Message: grpcStatus.Message()})
 		case codes.Unauthenticated:
 			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
 		default:
@@ -218,12 +430,391 @@ func (h *AuthHandler) ExchangeToken(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "signed out", Data: map[string]int32{"revoked_count": revoked}})
+}
+
+// ChangePassword handles POST /me/password
+// Replaces the caller's password; every other login is signed out.
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	var req changePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	revoked, err := h.authClient.ChangePassword(r.Context(), token, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.InvalidArgument, codes.FailedPrecondition:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.PermissionDenied:
+			lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "password changed", Data: map[string]int32{"revoked_count": revoked}})
+}
+
+// SetRecoveryEmail handles POST /me/email
+// Sets where the caller's password reset links are sent.
+func (h *AuthHandler) SetRecoveryEmail(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	var req setRecoveryEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	if err := h.authClient.SetRecoveryEmail(r.Context(), token, req.Email, req.Password); err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.InvalidArgument, codes.FailedPrecondition:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.PermissionDenied:
+			lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.Aborted:
+			lib.WriteJSON(w, http.StatusConflict, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "recovery email updated"})
+}
+
+// ForgotPassword handles POST /password/forgot
+// Queues a reset link for the email if it belongs to an account. The
+// response does not say whether it does.
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req forgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	if err := h.authClient.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.FailedPrecondition:
+			lib.WriteJSON(w, http.StatusServiceUnavailable, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.ResourceExhausted:
+			lib.SetRetryAfter(w, err)
+			lib.WriteJSON(w, http.StatusTooManyRequests, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "if the email belongs to an account, a reset link has been sent"})
+}
+
+// ResetPassword handles POST /password/reset
+// Sets a new password with the token from a reset link and signs out every
+// login of the account.
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req resetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	if err := h.authClient.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "password reset; sign in with the new password"})
+}
+
+// EnrollTOTP handles POST /me/2fa/totp
+// Generates a new authenticator secret. Two-factor sign-in stays off until it
+// is confirmed.
+func (h *AuthHandler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	resp, err := h.authClient.EnrollTOTP(r.Context(), token)
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.FailedPrecondition:
+			lib.WriteJSON(w, http.StatusConflict, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+
 	lib.WriteJSON(w, http.StatusOK, lib.Response{
 		Success: true,
-		Data:    map[string]string{"token": longLivedToken, "username": username},
+		Data:    map[string]string{"secret": resp.Secret, "otpauth_uri": resp.OtpauthUri},
 	})
 }
 
+// ConfirmTOTP handles POST /me/2fa/totp/confirm
+// Turns two-factor sign-in on and returns the recovery codes.
+func (h *AuthHandler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	var req totpCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	recoveryCodes, err := h.authClient.ConfirmTOTP(r.Context(), token, req.Code)
+	if err != nil {
+		h.writeTOTPError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{
+		Success: true,
+		Message: "two-factor sign-in turned on",
+		Data:    map[string][]string{"recovery_codes": recoveryCodes},
+	})
+}
+
+// DisableTOTP handles POST /me/2fa/totp/disable
+// Turns two-factor sign-in off; takes a current code or a recovery code.
+func (h *AuthHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	var req totpCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	if err := h.authClient.DisableTOTP(r.Context(), token, req.Code); err != nil {
+		h.writeTOTPError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "two-factor sign-in turned off"})
+}
+
+// writeTOTPError maps the errors of ConfirmTOTP and DisableTOTP to HTTP.
+func (h *AuthHandler) writeTOTPError(w http.ResponseWriter, err error) {
+	grpcStatus, _ := status.FromError(err)
+	switch grpcStatus.Code() {
+	case codes.InvalidArgument:
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+	case codes.FailedPrecondition:
+		lib.WriteJSON(w, http.StatusConflict, lib.Response{Success: false, Message: grpcStatus.Message()})
+	case codes.ResourceExhausted:
+		lib.WriteJSON(w, http.StatusTooManyRequests, lib.Response{Success: false, Message: grpcStatus.Message()})
+	case codes.Unauthenticated:
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+	default:
+		lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+	}
+}
+
+// BeginPasskeyRegistration handles POST /me/passkeys/begin
+// Returns the options to pass to navigator.credentials.create for a new
+// passkey.
+func (h *AuthHandler) BeginPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	options, err := h.authClient.BeginPasskeyRegistration(r.Context(), token)
+	if err != nil {
+		h.writePasskeyError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: json.RawMessage(options)})
+}
+
+// FinishPasskeyRegistration handles POST /me/passkeys
+// Stores the passkey navigator.credentials.create returned.
+func (h *AuthHandler) FinishPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	var req passkeyRegistrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	passkey, err := h.authClient.FinishPasskeyRegistration(r.Context(), token, string(req.Credential), req.Name)
+	if err != nil {
+		h.writePasskeyError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusCreated, lib.Response{Success: true, Message: "passkey added", Data: passkey})
+}
+
+// ListPasskeys handles GET /me/passkeys
+// Lists the caller's passkeys, oldest first.
+func (h *AuthHandler) ListPasskeys(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	resp, err := h.authClient.ListPasskeys(r.Context(), token)
+	if err != nil {
+		h.writePasskeyError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: resp})
+}
+
+// DeletePasskey handles DELETE /me/passkeys/{id}
+// Removes one of the caller's passkeys.
+func (h *AuthHandler) DeletePasskey(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid passkey id"})
+		return
+	}
+
+	if err := h.authClient.DeletePasskey(r.Context(), token, id); err != nil {
+		h.writePasskeyError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "passkey removed"})
+}
+
+// BeginPasskeyLogin handles POST /login/passkey/begin
+// Returns the options to pass to navigator.credentials.get for a
+// passwordless sign-in.
+func (h *AuthHandler) BeginPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	options, err := h.authClient.BeginPasskeyLogin(r.Context())
+	if err != nil {
+		h.writePasskeyError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: json.RawMessage(options)})
+}
+
+// FinishPasskeyLogin handles POST /login/passkey/finish
+// Signs in with the passkey navigator.credentials.get returned. No password
+// or second factor is needed.
+func (h *AuthHandler) FinishPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	var req passkeyLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	resp, err := h.authClient.FinishPasskeyLogin(lib.WithClientInfo(r.Context(), r), string(req.Credential), req.DeviceName)
+	if err != nil {
+		h.writePasskeyError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{
+		Success: true,
+		Message: "login successful",
+		Data:    map[string]string{"token": resp.Token, "username": resp.Username, "refresh_token": resp.RefreshToken},
+	})
+}
+
+// BeginSecondFactorPasskey handles POST /login/verify/passkey/begin
+// Returns the options to pass to navigator.credentials.get for finishing a
+// sign-in that returned second_factor_required with a passkey; the result
+// goes to /login/verify.
+func (h *AuthHandler) BeginSecondFactorPasskey(w http.ResponseWriter, r *http.Request) {
+	var req secondFactorPasskeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	options, err := h.authClient.BeginSecondFactorPasskey(r.Context(), req.ChallengeToken)
+	if err != nil {
+		h.writePasskeyError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: json.RawMessage(options)})
+}
+
+// writePasskeyError maps the errors of the passkey RPCs to HTTP.
+func (h *AuthHandler) writePasskeyError(w http.ResponseWriter, err error) {
+	grpcStatus, _ := status.FromError(err)
+	switch grpcStatus.Code() {
+	case codes.InvalidArgument:
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+	case codes.FailedPrecondition:
+		lib.WriteJSON(w, http.StatusConflict, lib.Response{Success: false, Message: grpcStatus.Message()})
+	case codes.AlreadyExists:
+		lib.WriteJSON(w, http.StatusConflict, lib.Response{Success: false, Message: grpcStatus.Message()})
+	case codes.NotFound:
+		lib.WriteJSON(w, http.StatusNotFound, lib.Response{Success: false, Message: grpcStatus.Message()})
+	case codes.PermissionDenied:
+		lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: grpcStatus.Message()})
+	case codes.Unauthenticated:
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+	default:
+		lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+	}
+}
+
 // SetupKeys handles POST /keys/setup
 // Stores the user's E2EE public key and PIN-encrypted private key.
 func (h *AuthHandler) SetupKeys(w http.ResponseWriter, r *http.Request) {
@@ -323,3 +914,178 @@ func (h *AuthHandler) GetPublicKeys(w http.ResponseWriter, r *http.Request) {
 		Data:    keys,
 	})
 }
+
+// GetMe handles GET /me
+// Returns the caller's own profile.
+func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	h.getProfile(w, r, "")
+}
+
+// GetUser handles GET /users/{id}
+// Returns another user's profile.
+func (h *AuthHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	h.getProfile(w, r, mux.Vars(r)["id"])
+}
+
+// getProfile writes the profile of userID, or of the caller when it is empty.
+func (h *AuthHandler) getProfile(w http.ResponseWriter, r *http.Request, userID string) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	profile, err := h.authClient.GetProfile(r.Context(), token, userID)
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.NotFound:
+			lib.WriteJSON(w, http.StatusNotFound, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: profile})
+}
+
+// UpdateMe handles PATCH /me
+// Changes the fields present in the body and returns the updated profile.
+func (h *AuthHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	var req updateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	profile, err := h.authClient.UpdateProfile(r.Context(), token, &auth.UpdateProfileRequest{
+		DisplayName:     req.DisplayName,
+		AvatarUrl:       req.AvatarURL,
+		Bio:             req.Bio,
+		StatusText:      req.StatusText,
+		StatusExpiresAt: req.StatusExpiresAt,
+	})
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "profile updated", Data: profile})
+}
+
+// ChangeUsername handles POST /me/username
+// Renames the caller and returns the new username with a fresh token.
+func (h *AuthHandler) ChangeUsername(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	var req changeUsernameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	resp, err := h.authClient.ChangeUsername(r.Context(), token, req.Username)
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.AlreadyExists, codes.FailedPrecondition:
+			lib.WriteJSON(w, http.StatusConflict, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "username changed", Data: map[string]string{
+		"username": resp.GetUsername(),
+		"token":    resp.GetToken(),
+	}})
+}
+
+// GetPrivacy handles GET /me/privacy
+// Returns the caller's privacy settings.
+func (h *AuthHandler) GetPrivacy(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	settings, err := h.authClient.GetPrivacySettings(r.Context(), token)
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: settings})
+}
+
+// UpdatePrivacy handles PATCH /me/privacy
+// Changes the settings present in the body and returns the result.
+func (h *AuthHandler) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok || token == "" {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	var req updatePrivacyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	settings, err := h.authClient.UpdatePrivacySettings(r.Context(), token, &auth.UpdatePrivacySettingsRequest{
+		Discoverability: req.Discoverability,
+		FriendRequests:  req.FriendRequests,
+		ReadReceipts:    req.ReadReceipts,
+		LastSeen:        req.LastSeen,
+	})
+	if err != nil {
+		grpcStatus, _ := status.FromError(err)
+		switch grpcStatus.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: grpcStatus.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: grpcStatus.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: grpcStatus.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "privacy settings updated", Data: settings})
+}
@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
+	"github.com/gorilla/mux"
 	"github.com/zukigit/chat/backend/internal/clients"
 	"github.com/zukigit/chat/backend/internal/lib"
+	pb "github.com/zukigit/chat/backend/proto/chat"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -40,7 +46,7 @@ func (h *ChatHandler) CreateConversation(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	conversationID, err := h.client.CreateConversation(r.Context(), token, req.IsGroup, req.Name, req.MembersUsername)
+	conversationID, err := h.client.CreateConversation(r.Context(), token, req.IsGroup, req.Name, req.MembersUsername, req.LabelID)
 	if err != nil {
 		st, _ := status.FromError(err)
 		switch st.Code() {
@@ -48,6 +54,8 @@ func (h *ChatHandler) CreateConversation(w http.ResponseWriter, r *http.Request)
 			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: st.Message()})
 		case codes.PermissionDenied:
 			lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: st.Message()})
+		case codes.NotFound:
+			lib.WriteJSON(w, http.StatusNotFound, lib.Response{Success: false, Message: st.Message()})
 		case codes.Unauthenticated:
 			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
 		default:
@@ -93,7 +101,7 @@ func (h *ChatHandler) GetConversations(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetConversationsByName handles GET /conversations/search
-// Query params: name (required)
+// Query params: name (required), limit (optional), cursor (optional)
 func (h *ChatHandler) GetConversationsByName(w http.ResponseWriter, r *http.Request) {
 	token, ok := lib.BearerToken(r)
 	if !ok {
@@ -113,12 +121,217 @@ func (h *ChatHandler) GetConversationsByName(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	resp, err := h.client.GetConversationsByName(r.Context(), token, name)
+	limit, err := parseLimit(r)
 	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{
+			Success: false,
+			Message: "invalid limit query parameter",
+		})
+		return
+	}
+
+	resp, err := h.client.GetConversationsByName(r.Context(), token, name, limit, r.URL.Query().Get("cursor"))
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: st.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: st.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// GetConversation handles GET /conversations/{id}
+func (h *ChatHandler) GetConversation(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{
+			Success: false,
+			Message: "missing or malformed Authorization header",
+		})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{
+			Success: false,
+			Message: "invalid conversation id",
+		})
+		return
+	}
+
+	resp, err := h.client.GetConversation(r.Context(), token, conversationID)
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: st.Message()})
+		case codes.PermissionDenied:
+			lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: st.Message()})
+		case codes.NotFound:
+			lib.WriteJSON(w, http.StatusNotFound, lib.Response{Success: false, Message: st.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: st.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// GetMessages handles GET /conversations/messages
+// Query params: conversation_id (required), limit (optional), cursor (optional)
+func (h *ChatHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{
+			Success: false,
+			Message: "missing or malformed Authorization header",
+		})
+		return
+	}
+
+	query := r.URL.Query()
+	conversationID, err := strconv.ParseInt(query.Get("conversation_id"), 10, 64)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{
+			Success: false,
+			Message: "conversation_id query parameter is required",
+		})
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{
+			Success: false,
+			Message: "invalid limit query parameter",
+		})
+		return
+	}
+
+	resp, err := h.client.GetMessages(r.Context(), token, conversationID, limit, query.Get("cursor"))
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: st.Message()})
+		case codes.PermissionDenied:
+			lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: st.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: st.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// SearchMessages handles POST /conversations/messages/search
+// Body: {"token_sets": [{"conversation_id": 1, "tokens": ["..."]}], "limit": 50, "cursor": ""}
+func (h *ChatHandler) SearchMessages(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{
+			Success: false,
+			Message: "missing or malformed Authorization header",
+		})
+		return
+	}
+
+	var req searchMessagesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.TokenSets) == 0 {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{
+			Success: false,
+			Message: "invalid request body: token_sets is required",
+		})
+		return
+	}
+
+	tokenSets := make([]*pb.SearchTokenSet, 0, len(req.TokenSets))
+	for _, set := range req.TokenSets {
+		tokenSets = append(tokenSets, &pb.SearchTokenSet{
+			ConversationId: set.ConversationID,
+			Tokens:         set.Tokens,
+		})
+	}
+
+	resp, err := h.client.SearchMessages(r.Context(), token, tokenSets, req.Limit, req.Cursor)
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: st.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: st.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{
+		Success: true,
+		Data:    resp,
+	})
+}
+
+// handleConversationAction is shared logic for the group management endpoints.
+func (h *ChatHandler) handleConversationAction(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(ctx context.Context, token string, req updateConversationRequest) error,
+	successMsg string,
+) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{
+			Success: false,
+			Message: "missing or malformed Authorization header",
+		})
+		return
+	}
+
+	var req updateConversationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ConversationID == 0 {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{
+			Success: false,
+			Message: "invalid request body: conversation_id is required",
+		})
+		return
+	}
+
+	if err := action(r.Context(), token, req); err != nil {
 		st, _ := status.FromError(err)
 		switch st.Code() {
 		case codes.InvalidArgument:
 			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: st.Message()})
+		case codes.NotFound:
+			lib.WriteJSON(w, http.StatusNotFound, lib.Response{Success: false, Message: st.Message()})
+		case codes.AlreadyExists, codes.FailedPrecondition:
+			lib.WriteJSON(w, http.StatusConflict, lib.Response{Success: false, Message: st.Message()})
+		case codes.PermissionDenied:
+			lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: st.Message()})
 		case codes.Unauthenticated:
 			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
 		default:
@@ -127,8 +340,215 @@ func (h *ChatHandler) GetConversationsByName(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: successMsg})
+}
+
+// AddMembers handles POST /conversations/members/add
+func (h *ChatHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
+	h.handleConversationAction(w, r, func(ctx context.Context, token string, req updateConversationRequest) error {
+		return h.client.AddMembers(ctx, token, req.ConversationID, req.MembersUsername)
+	}, "members added")
+}
+
+// RemoveMember handles POST /conversations/members/remove
+func (h *ChatHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	h.handleConversationAction(w, r, func(ctx context.Context, token string, req updateConversationRequest) error {
+		return h.client.RemoveMember(ctx, token, req.ConversationID, req.Username)
+	}, "member removed")
+}
+
+// RenameConversation handles POST /conversations/rename
+func (h *ChatHandler) RenameConversation(w http.ResponseWriter, r *http.Request) {
+	h.handleConversationAction(w, r, func(ctx context.Context, token string, req updateConversationRequest) error {
+		return h.client.RenameConversation(ctx, token, req.ConversationID, req.Name)
+	}, "conversation renamed")
+}
+
+// SetMemberRole handles POST /conversations/role
+func (h *ChatHandler) SetMemberRole(w http.ResponseWriter, r *http.Request) {
+	h.handleConversationAction(w, r, func(ctx context.Context, token string, req updateConversationRequest) error {
+		return h.client.SetMemberRole(ctx, token, req.ConversationID, req.Username, req.Role)
+	}, "member role updated")
+}
+
+// SetSlowMode handles POST /conversations/slow-mode
+func (h *ChatHandler) SetSlowMode(w http.ResponseWriter, r *http.Request) {
+	h.handleConversationAction(w, r, func(ctx context.Context, token string, req updateConversationRequest) error {
+		return h.client.SetSlowMode(ctx, token, req.ConversationID, req.Seconds)
+	}, "slow mode updated")
+}
+
+// ClearHistory handles POST /conversations/clear
+func (h *ChatHandler) ClearHistory(w http.ResponseWriter, r *http.Request) {
+	h.handleConversationAction(w, r, func(ctx context.Context, token string, req updateConversationRequest) error {
+		return h.client.ClearHistory(ctx, token, req.ConversationID)
+	}, "history cleared")
+}
+
+// DeleteConversation handles POST /conversations/delete
+func (h *ChatHandler) DeleteConversation(w http.ResponseWriter, r *http.Request) {
+	h.handleConversationAction(w, r, func(ctx context.Context, token string, req updateConversationRequest) error {
+		return h.client.DeleteConversation(ctx, token, req.ConversationID)
+	}, "conversation deleted")
+}
+
+// ExportConversation handles GET /conversations/{id}/export
+// Streams the conversation archive as newline-delimited JSON.
+func (h *ChatHandler) ExportConversation(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{
+			Success: false,
+			Message: "missing or malformed Authorization header",
+		})
+		return
+	}
+
+	conversationID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{
+			Success: false,
+			Message: "invalid conversation id",
+		})
+		return
+	}
+
+	stream, err := h.client.ExportConversation(r.Context(), token, conversationID)
+	if err != nil {
+		writeExportError(w, err)
+		return
+	}
+	writeExportStream(w, stream.Recv, fmt.Sprintf("conversation-%d.jsonl", conversationID))
+}
+
+// StartConversationExport handles POST /conversations/exports
+func (h *ChatHandler) StartConversationExport(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{
+			Success: false,
+			Message: "missing or malformed Authorization header",
+		})
+		return
+	}
+
+	var req startExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ConversationID == 0 {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{
+			Success: false,
+			Message: "invalid request body: conversation_id is required",
+		})
+		return
+	}
+
+	resp, err := h.client.StartConversationExport(r.Context(), token, req.ConversationID)
+	if err != nil {
+		writeExportError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusAccepted, lib.Response{
+		Success: true,
+		Message: "export started",
+		Data:    resp,
+	})
+}
+
+// GetConversationExport handles GET /conversations/exports/{export_id}
+func (h *ChatHandler) GetConversationExport(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{
+			Success: false,
+			Message: "missing or malformed Authorization header",
+		})
+		return
+	}
+
+	resp, err := h.client.GetConversationExport(r.Context(), token, mux.Vars(r)["export_id"])
+	if err != nil {
+		writeExportError(w, err)
+		return
+	}
+
 	lib.WriteJSON(w, http.StatusOK, lib.Response{
 		Success: true,
 		Data:    resp,
 	})
 }
+
+// DownloadConversationExport handles GET /conversations/exports/download?token=
+// The token in the link is the credential, so no Authorization header is needed.
+func (h *ChatHandler) DownloadConversationExport(w http.ResponseWriter, r *http.Request) {
+	downloadToken := r.URL.Query().Get("token")
+	if downloadToken == "" {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{
+			Success: false,
+			Message: "token query parameter is required",
+		})
+		return
+	}
+
+	stream, err := h.client.DownloadConversationExport(r.Context(), downloadToken)
+	if err != nil {
+		writeExportError(w, err)
+		return
+	}
+	writeExportStream(w, stream.Recv, "conversation-export.jsonl")
+}
+
+// writeExportStream relays archive chunks to the HTTP response. The first
+// chunk is received before any header is written so that an error returned by
+// the backend (e.g. PermissionDenied) still maps to a proper status code.
+func writeExportStream(w http.ResponseWriter, recv func() (*pb.ExportChunk, error), filename string) {
+	chunk, err := recv()
+	if err != nil && err != io.EOF {
+		writeExportError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	for err == nil {
+		if _, werr := w.Write(chunk.GetData()); werr != nil {
+			return
+		}
+		chunk, err = recv()
+	}
+	if err != io.EOF {
+		// Headers are already sent; the truncated body is all we can signal.
+		lib.ErrorLog.Printf("export stream: %v", err)
+	}
+}
+
+// writeExportError maps a gRPC error from the export RPCs to a JSON response.
+func writeExportError(w http.ResponseWriter, err error) {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: st.Message()})
+	case codes.PermissionDenied:
+		lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: st.Message()})
+	case codes.NotFound:
+		lib.WriteJSON(w, http.StatusNotFound, lib.Response{Success: false, Message: st.Message()})
+	case codes.FailedPrecondition:
+		lib.WriteJSON(w, http.StatusConflict, lib.Response{Success: false, Message: st.Message()})
+	case codes.Unauthenticated:
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
+	default:
+		lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: st.Message()})
+	}
+}
+
+// parseLimit reads the optional limit query parameter of paged endpoints.
+// A missing limit returns 0, which lets the backend apply its default.
+func parseLimit(r *http.Request) (int32, error) {
+	l := r.URL.Query().Get("limit")
+	if l == "" {
+		return 0, nil
+	}
+	limit, err := strconv.ParseInt(l, 10, 32)
+	return int32(limit), err
+}
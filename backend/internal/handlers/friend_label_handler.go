@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/zukigit/chat/backend/internal/lib"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListLabels handles GET /friends/labels
+func (h *FriendshipHandler) ListLabels(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	resp, err := h.client.ListLabels(r.Context(), token)
+	if err != nil {
+		writeLabelError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: resp.GetLabels()})
+}
+
+// CreateLabel handles POST /friends/labels
+func (h *FriendshipHandler) CreateLabel(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	var req labelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	label, err := h.client.CreateLabel(r.Context(), token, req.Name)
+	if err != nil {
+		writeLabelError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusCreated, lib.Response{Success: true, Message: "label created", Data: label})
+}
+
+// RenameLabel handles PATCH /friends/labels/{id}
+func (h *FriendshipHandler) RenameLabel(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	labelID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid label id"})
+		return
+	}
+
+	var req labelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	label, err := h.client.RenameLabel(r.Context(), token, labelID, req.Name)
+	if err != nil {
+		writeLabelError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "label renamed", Data: label})
+}
+
+// DeleteLabel handles DELETE /friends/labels/{id}
+func (h *FriendshipHandler) DeleteLabel(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	labelID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid label id"})
+		return
+	}
+
+	if err := h.client.DeleteLabel(r.Context(), token, labelID); err != nil {
+		writeLabelError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "label deleted"})
+}
+
+// AddLabelMembers handles POST /friends/labels/{id}/members/add
+func (h *FriendshipHandler) AddLabelMembers(w http.ResponseWriter, r *http.Request) {
+	h.handleLabelMembers(w, r, true)
+}
+
+// RemoveLabelMembers handles POST /friends/labels/{id}/members/remove
+func (h *FriendshipHandler) RemoveLabelMembers(w http.ResponseWriter, r *http.Request) {
+	h.handleLabelMembers(w, r, false)
+}
+
+// handleLabelMembers is shared logic for adding friends to and removing them
+// from a label.
+func (h *FriendshipHandler) handleLabelMembers(w http.ResponseWriter, r *http.Request, add bool) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	labelID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid label id"})
+		return
+	}
+
+	var req labelMembersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Usernames) == 0 {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body: usernames is required"})
+		return
+	}
+
+	update, message := h.client.RemoveLabelMembers, "removed from label"
+	if add {
+		update, message = h.client.AddLabelMembers, "added to label"
+	}
+	label, err := update(r.Context(), token, labelID, req.Usernames)
+	if err != nil {
+		writeLabelError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: message, Data: label})
+}
+
+func writeLabelError(w http.ResponseWriter, err error) {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: st.Message()})
+	case codes.PermissionDenied:
+		lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: st.Message()})
+	case codes.NotFound:
+		lib.WriteJSON(w, http.StatusNotFound, lib.Response{Success: false, Message: st.Message()})
+	case codes.AlreadyExists:
+		lib.WriteJSON(w, http.StatusConflict, lib.Response{Success: false, Message: st.Message()})
+	case codes.Unauthenticated:
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
+	default:
+		lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: st.Message()})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/zukigit/chat/backend/internal/clients"
 	"github.com/zukigit/chat/backend/internal/lib"
@@ -21,7 +22,7 @@ func NewFriendshipHandler(client *clients.FriendshipClient) *FriendshipHandler {
 	return &FriendshipHandler{client: client}
 }
 
-// handleFriendshipAction is shared logic for Send / Accept / Reject.
+// handleFriendshipAction is shared logic for the POST endpoints that take a username.
 func (h *FriendshipHandler) handleFriendshipAction(
 	w http.ResponseWriter,
 	r *http.Request,
@@ -58,7 +59,11 @@ func (h *FriendshipHandler) handleFriendshipAction(
 		case codes.InvalidArgument:
 			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: st.Message()})
 		case codes.FailedPrecondition:
+			lib.SetRetryAfter(w, err)
 			lib.WriteJSON(w, http.StatusConflict, lib.Response{Success: false, Message: st.Message()})
+		case codes.ResourceExhausted:
+			lib.SetRetryAfter(w, err)
+			lib.WriteJSON(w, http.StatusTooManyRequests, lib.Response{Success: false, Message: st.Message()})
 		case codes.Unauthenticated:
 			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
 		default:
@@ -81,10 +86,22 @@ func (h *FriendshipHandler) GetFriends(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.client.GetFriends(r.Context(), token)
+	var labelID int64
+	if l := r.URL.Query().Get("label_id"); l != "" {
+		id, err := strconv.ParseInt(l, 10, 64)
+		if err != nil {
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid label_id"})
+			return
+		}
+		labelID = id
+	}
+
+	resp, err := h.client.GetFriends(r.Context(), token, labelID)
 	if err != nil {
 		st, _ := status.FromError(err)
 		switch st.Code() {
+		case codes.NotFound:
+			lib.WriteJSON(w, http.StatusNotFound, lib.Response{Success: false, Message: st.Message()})
 		case codes.Unauthenticated:
 			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
 		default:
@@ -97,6 +114,106 @@ func (h *FriendshipHandler) GetFriends(w http.ResponseWriter, r *http.Request) {
 }
 
 
+// ListFriendRequests handles GET /friends/requests
+// Query params: direction ("incoming" | "outgoing", required), limit (optional), cursor (optional)
+func (h *FriendshipHandler) ListFriendRequests(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{
+			Success: false,
+			Message: "missing or malformed Authorization header",
+		})
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{
+			Success: false,
+			Message: "invalid limit query parameter",
+		})
+		return
+	}
+
+	query := r.URL.Query()
+	resp, err := h.client.ListFriendRequests(r.Context(), token, query.Get("direction"), limit, query.Get("cursor"))
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.InvalidArgument:
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: st.Message()})
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: st.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: resp})
+}
+
+// SuggestFriends handles GET /friends/suggestions
+func (h *FriendshipHandler) SuggestFriends(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{
+			Success: false,
+			Message: "missing or malformed Authorization header",
+		})
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{
+			Success: false,
+			Message: "invalid limit query parameter",
+		})
+		return
+	}
+
+	resp, err := h.client.SuggestFriends(r.Context(), token, limit)
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: st.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: resp.GetSuggestions()})
+}
+
+// ListBlocked handles GET /friends/blocked
+func (h *FriendshipHandler) ListBlocked(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{
+			Success: false,
+			Message: "missing or malformed Authorization header",
+		})
+		return
+	}
+
+	resp, err := h.client.ListBlocked(r.Context(), token)
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.Unauthenticated:
+			lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
+		default:
+			lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: st.Message()})
+		}
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: resp.GetUsers()})
+}
+
 // SendFriendRequest handles POST /friends/request
 func (h *FriendshipHandler) SendFriendRequest(w http.ResponseWriter, r *http.Request) {
 	h.handleFriendshipAction(w, r, h.client.SendFriendRequest, "friend request sent")
@@ -111,3 +228,23 @@ func (h *FriendshipHandler) AcceptFriendRequest(w http.ResponseWriter, r *http.R
 func (h *FriendshipHandler) RejectFriendRequest(w http.ResponseWriter, r *http.Request) {
 	h.handleFriendshipAction(w, r, h.client.RejectFriendRequest, "friend request rejected")
 }
+
+// CancelFriendRequest handles POST /friends/cancel
+func (h *FriendshipHandler) CancelFriendRequest(w http.ResponseWriter, r *http.Request) {
+	h.handleFriendshipAction(w, r, h.client.CancelFriendRequest, "friend request cancelled")
+}
+
+// RemoveFriend handles POST /friends/remove
+func (h *FriendshipHandler) RemoveFriend(w http.ResponseWriter, r *http.Request) {
+	h.handleFriendshipAction(w, r, h.client.RemoveFriend, "friend removed")
+}
+
+// BlockUser handles POST /friends/block
+func (h *FriendshipHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	h.handleFriendshipAction(w, r, h.client.BlockUser, "user blocked")
+}
+
+// UnblockUser handles POST /friends/unblock
+func (h *FriendshipHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	h.handleFriendshipAction(w, r, h.client.UnblockUser, "user unblocked")
+}
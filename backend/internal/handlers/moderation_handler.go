@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/zukigit/chat/backend/internal/clients"
+	"github.com/zukigit/chat/backend/internal/lib"
+	pb "github.com/zukigit/chat/backend/proto/moderation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ModerationHandler handles abuse reports and the admin moderation endpoints.
+type ModerationHandler struct {
+	client *clients.ModerationClient
+}
+
+// NewModerationHandler creates a ModerationHandler with the given gRPC client.
+func NewModerationHandler(client *clients.ModerationClient) *ModerationHandler {
+	return &ModerationHandler{client: client}
+}
+
+// Report handles POST /reports
+func (h *ModerationHandler) Report(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	var req reportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	resp, err := h.client.Report(r.Context(), token, &pb.ReportRequest{
+		TargetUsername:   req.Username,
+		MessageId:        req.MessageID,
+		Reason:           req.Reason,
+		Details:          req.Details,
+		MessagePlaintext: req.MessagePlaintext,
+	})
+	if err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusCreated, lib.Response{Success: true, Message: "report submitted", Data: resp})
+}
+
+// ListReports handles GET /admin/reports
+// Query params: status (optional), limit (optional), after_id (optional)
+func (h *ModerationHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid limit query parameter"})
+		return
+	}
+
+	query := r.URL.Query()
+	var afterID int64
+	if v := query.Get("after_id"); v != "" {
+		afterID, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid after_id query parameter"})
+			return
+		}
+	}
+
+	resp, err := h.client.ListReports(r.Context(), token, query.Get("status"), limit, afterID)
+	if err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: resp})
+}
+
+// TriageReport handles POST /admin/reports/{id}/triage
+func (h *ModerationHandler) TriageReport(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	reportID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid report id"})
+		return
+	}
+
+	if err := h.client.TriageReport(r.Context(), token, reportID); err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "report assigned to you"})
+}
+
+// ResolveReport handles POST /admin/reports/{id}/resolve
+func (h *ModerationHandler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	reportID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid report id"})
+		return
+	}
+
+	var req moderationActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	resp, err := h.client.ResolveReport(r.Context(), token, &pb.ResolveReportRequest{
+		ReportId:       reportID,
+		Action:         req.Action,
+		Note:           req.Note,
+		SuspendSeconds: req.SuspendSeconds,
+	})
+	if err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "report " + resp.GetStatus(), Data: resp})
+}
+
+// ModerateUser handles POST /admin/users/{username}/actions
+func (h *ModerationHandler) ModerateUser(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	var req moderationActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: "invalid request body"})
+		return
+	}
+
+	if err := h.client.ModerateUser(r.Context(), token, &pb.ModerateUserRequest{
+		Username:       mux.Vars(r)["username"],
+		Action:         req.Action,
+		Note:           req.Note,
+		SuspendSeconds: req.SuspendSeconds,
+	}); err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Message: "action applied"})
+}
+
+// ListUserActions handles GET /admin/users/{username}/actions
+func (h *ModerationHandler) ListUserActions(w http.ResponseWriter, r *http.Request) {
+	token, ok := lib.BearerToken(r)
+	if !ok {
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: "missing or malformed Authorization header"})
+		return
+	}
+
+	resp, err := h.client.ListUserActions(r.Context(), token, mux.Vars(r)["username"])
+	if err != nil {
+		writeModerationError(w, err)
+		return
+	}
+
+	lib.WriteJSON(w, http.StatusOK, lib.Response{Success: true, Data: resp})
+}
+
+func writeModerationError(w http.ResponseWriter, err error) {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		lib.WriteJSON(w, http.StatusBadRequest, lib.Response{Success: false, Message: st.Message()})
+	case codes.PermissionDenied:
+		lib.WriteJSON(w, http.StatusForbidden, lib.Response{Success: false, Message: st.Message()})
+	case codes.NotFound:
+		lib.WriteJSON(w, http.StatusNotFound, lib.Response{Success: false, Message: st.Message()})
+	case codes.AlreadyExists, codes.FailedPrecondition:
+		lib.WriteJSON(w, http.StatusConflict, lib.Response{Success: false, Message: st.Message()})
+	case codes.Unauthenticated:
+		lib.WriteJSON(w, http.StatusUnauthorized, lib.Response{Success: false, Message: st.Message()})
+	default:
+		lib.WriteJSON(w, http.StatusInternalServerError, lib.Response{Success: false, Message: st.Message()})
+	}
+}
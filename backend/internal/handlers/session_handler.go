@@ -20,11 +20,24 @@ type SessionHandler struct {
 	client     *clients.SessionClient
 	chatClient *clients.ChatClient
 	js         nats.JetStreamContext
+	nc         *nats.Conn
 }
 
 // NewSessionHandler creates a SessionHandler with the given gRPC client.
-func NewSessionHandler(client *clients.SessionClient, chatClient *clients.ChatClient, js nats.JetStreamContext) *SessionHandler {
-	return &SessionHandler{client: client, chatClient: chatClient, js: js}
+// nc carries the backend's login revocations, which close the affected
+// WebSockets.
+func NewSessionHandler(client *clients.SessionClient, chatClient *clients.ChatClient, js nats.JetStreamContext, nc *nats.Conn) *SessionHandler {
+	return &SessionHandler{client: client, chatClient: chatClient, js: js, nc: nc}
+}
+
+// closeOnRevoke ends the session, via cancel, once the backend announces that
+// loginID was signed out. The returned subscription must be unsubscribed when
+// the session ends.
+func (s *SessionHandler) closeOnRevoke(conn *websocket.Conn, loginID string, cancel context.CancelFunc) (*nats.Subscription, error) {
+	return s.nc.Subscribe(lib.LoginRevokedSubjectPrefix+loginID, func(*nats.Msg) {
+		s.sendWSError(conn, 401, "session has been signed out", 0, "")
+		cancel()
+	})
 }
 
 // sendWSError sends an error message to the client over the WebSocket connection
@@ -43,6 +56,41 @@ func (s *SessionHandler) sendWSError(conn *websocket.Conn, code int, message str
 	conn.WriteMessage(websocket.TextMessage, data)
 }
 
+// sendWSSendError relays a failed SendMessage call as an "error" envelope. Errors
+// the client is expected to handle, such as slow mode, keep their
+// machine-readable error_code and retry delay.
+func (s *SessionHandler) sendWSSendError(conn *websocket.Conn, err error, conversationID int64, messageID string) {
+	st, _ := status.FromError(err)
+	code := http.StatusInternalServerError
+	switch st.Code() {
+	case codes.InvalidArgument:
+		code = http.StatusBadRequest
+	case codes.Unauthenticated:
+		code = http.StatusUnauthorized
+	case codes.PermissionDenied:
+		code = http.StatusForbidden
+	case codes.AlreadyExists, codes.FailedPrecondition:
+		code = http.StatusConflict
+	case codes.ResourceExhausted:
+		code = http.StatusTooManyRequests
+	}
+
+	errorCode, retryAfter := lib.ChatErrorDetails(err)
+	data, err := lib.NewChatResponseEnvelope(lib.ChatEventError, lib.ErrorEvent{
+		Code:           code,
+		Message:        fmt.Sprintf("failed to send message: %s", st.Message()),
+		ConversationID: conversationID,
+		MessageID:      messageID,
+		ErrorCode:      errorCode,
+		RetryAfterMs:   retryAfter.Milliseconds(),
+	})
+	if err != nil {
+		lib.ErrorLog.Printf("Failed to marshal WS error: %v", err)
+		return
+	}
+	conn.WriteMessage(websocket.TextMessage, data)
+}
+
 func (s *SessionHandler) NotificationSession(w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -95,6 +143,13 @@ func (s *SessionHandler) NotificationSession(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	revoked, err := s.closeOnRevoke(conn, claims.LoginID, cancel)
+	if err != nil {
+		s.sendWSError(conn, 500, fmt.Sprintf("failed to watch for logout: %v", err), 0, "")
+		return
+	}
+	defer revoked.Unsubscribe()
+
 	// Read channel from WS client so we detect close/disconnect.
 	go func() {
 		for {
@@ -182,6 +237,13 @@ func (s *SessionHandler) ChatSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	revoked, err := s.closeOnRevoke(conn, claims.LoginID, cancel)
+	if err != nil {
+		s.sendWSError(conn, 500, fmt.Sprintf("failed to watch for logout: %v", err), 0, "")
+		return
+	}
+	defer revoked.Unsubscribe()
+
 	// Read messages from the WS client and forward them to the chat backend.
 	go func() {
 		for {
@@ -205,8 +267,8 @@ func (s *SessionHandler) ChatSession(w http.ResponseWriter, r *http.Request) {
 					s.sendWSError(conn, 400, fmt.Sprintf("invalid send request: %v", err), 0, "")
 					continue
 				}
-				if _, err := s.chatClient.SendMessage(ctx, auth.Token, req.ConversationID, req.MessageID, req.Content, req.MessageType, req.ReplyToMessageID); err != nil {
-					s.sendWSError(conn, 500, fmt.Sprintf("failed to send message: %v", err), req.ConversationID, req.MessageID)
+				if _, err := s.chatClient.SendMessage(ctx, auth.Token, req.ConversationID, req.MessageID, req.Content, req.MessageType, req.ReplyToMessageID, req.SearchTokens); err != nil {
+					s.sendWSSendError(conn, err, req.ConversationID, req.MessageID)
 					continue
 				}
 			case lib.ChatRequestRead:
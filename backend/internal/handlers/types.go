@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/gorilla/websocket"
@@ -8,8 +9,19 @@ import (
 
 // loginRequest struct for /login
 type loginRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	DeviceName string `json:"device_name"` // optional
+}
+
+// exchangeTokenRequest is the optional JSON body of /token/exchange.
+type exchangeTokenRequest struct {
+	DeviceName string `json:"device_name"`
+}
+
+// refreshTokenRequest struct for /token/refresh
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 // signupRequest struct for /signup
@@ -17,9 +29,99 @@ type signupRequest struct {
 	Type     string `json:"type"`
 	Username string `json:"username"`
 	Password string `json:"password"` // required for email signup
+	Email    string `json:"email"`    // optional recovery email for password resets
 	Code     string `json:"code,omitempty"`   // required for google signup
 }
 
+// updateProfileRequest is the JSON body for PATCH /me. Omitted fields are
+// left unchanged; an empty string clears a field.
+type updateProfileRequest struct {
+	DisplayName     *string `json:"display_name"`
+	AvatarURL       *string `json:"avatar_url"`
+	Bio             *string `json:"bio"`
+	StatusText      *string `json:"status_text"`
+	StatusExpiresAt string  `json:"status_expires_at"`
+}
+
+// changeUsernameRequest is the JSON body for POST /me/username.
+type changeUsernameRequest struct {
+	Username string `json:"username"`
+}
+
+// changePasswordRequest is the JSON body for POST /me/password.
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// setRecoveryEmailRequest is the JSON body for POST /me/email.
+type setRecoveryEmailRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// verifySecondFactorRequest is the JSON body for POST /login/verify.
+type verifySecondFactorRequest struct {
+	ChallengeToken string          `json:"challenge_token"`
+	Code           string          `json:"code"`       // authenticator or recovery code
+	Credential     json.RawMessage `json:"credential"` // instead of code: PublicKeyCredential.toJSON()
+}
+
+// passkeyRegistrationRequest is the JSON body for POST /me/passkeys.
+type passkeyRegistrationRequest struct {
+	Credential json.RawMessage `json:"credential"` // PublicKeyCredential.toJSON()
+	Name       string          `json:"name"`
+}
+
+// passkeyLoginRequest is the JSON body for POST /login/passkey/finish.
+type passkeyLoginRequest struct {
+	Credential json.RawMessage `json:"credential"` // PublicKeyCredential.toJSON()
+	DeviceName string          `json:"device_name"`
+}
+
+// secondFactorPasskeyRequest is the JSON body for POST
+// /login/verify/passkey/begin.
+type secondFactorPasskeyRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+}
+
+// totpCodeRequest is the JSON body for POST /me/2fa/totp/confirm and
+// /me/2fa/totp/disable.
+type totpCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// forgotPasswordRequest is the JSON body for POST /password/forgot.
+type forgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+// resetPasswordRequest is the JSON body for POST /password/reset.
+type resetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// updatePrivacyRequest is the JSON body for PATCH /me/privacy. Omitted fields
+// are left unchanged.
+type updatePrivacyRequest struct {
+	Discoverability *string `json:"discoverability"`
+	FriendRequests  *string `json:"friend_requests"`
+	ReadReceipts    *bool   `json:"read_receipts"`
+	LastSeen        *string `json:"last_seen"`
+}
+
+// labelRequest is the JSON body for creating or renaming a friend label.
+type labelRequest struct {
+	Name string `json:"name"`
+}
+
+// labelMembersRequest is the JSON body for adding friends to or removing
+// them from a label.
+type labelMembersRequest struct {
+	Usernames []string `json:"usernames"`
+}
+
 // friendshipRequest is the shared request body for all friendship endpoints.
 type friendshipRequest struct {
 	Username string `json:"username"`
@@ -35,16 +137,48 @@ type createConversationRequest struct {
 	IsGroup         bool     `json:"is_group"`
 	Name            string   `json:"name,omitempty"`
 	MembersUsername []string `json:"members_username"`
+	LabelID         int64    `json:"label_id,omitempty"`
+}
+
+// updateConversationRequest is the shared request body for the group
+// management endpoints under /conversations. Each endpoint reads only the
+// fields it needs.
+type updateConversationRequest struct {
+	ConversationID  int64    `json:"conversation_id"`
+	Username        string   `json:"username,omitempty"`
+	MembersUsername []string `json:"members_username,omitempty"`
+	Name            string   `json:"name,omitempty"`
+	Role            string   `json:"role,omitempty"`
+	Seconds         int32    `json:"seconds,omitempty"`
+}
+
+// startExportRequest is the JSON body for POST /conversations/exports.
+type startExportRequest struct {
+	ConversationID int64 `json:"conversation_id"`
+}
+
+// searchMessagesRequest is the JSON body for POST /conversations/messages/search.
+type searchMessagesRequest struct {
+	TokenSets []searchTokenSet `json:"token_sets"`
+	Limit     int32            `json:"limit,omitempty"`
+	Cursor    string           `json:"cursor,omitempty"`
+}
+
+// searchTokenSet is the blind-index tokens to look for in one conversation.
+type searchTokenSet struct {
+	ConversationID int64    `json:"conversation_id"`
+	Tokens         []string `json:"tokens"`
 }
 
 // sendMessageRequest is the JSON payload a client sends over the chat WebSocket
 // to post a message to a conversation.
 type sendMessageRequest struct {
-	ConversationID   int64  `json:"conversation_id"`
-	MessageID        string `json:"message_id"`
-	Content          string `json:"content"`
-	MessageType      string `json:"message_type,omitempty"`
-	ReplyToMessageID string `json:"reply_to_message_id,omitempty"`
+	ConversationID   int64    `json:"conversation_id"`
+	MessageID        string   `json:"message_id"`
+	Content          string   `json:"content"`
+	MessageType      string   `json:"message_type,omitempty"`
+	ReplyToMessageID string   `json:"reply_to_message_id,omitempty"`
+	SearchTokens     []string `json:"search_tokens,omitempty"`
 }
 
 // readMessageRequest is the JSON payload a client sends over the chat
@@ -79,3 +213,20 @@ var upgrader = websocket.Upgrader{
 		return true
 	},
 }
+
+// reportRequest is the JSON body for POST /reports.
+type reportRequest struct {
+	Username         string `json:"username"`
+	MessageID        string `json:"message_id"`
+	Reason           string `json:"reason"`
+	Details          string `json:"details"`
+	MessagePlaintext string `json:"message_plaintext"`
+}
+
+// moderationActionRequest is the JSON body for POST /admin/reports/{id}/resolve
+// and POST /admin/users/{username}/actions.
+type moderationActionRequest struct {
+	Action         string `json:"action"`
+	Note           string `json:"note"`
+	SuspendSeconds int64  `json:"suspend_seconds"`
+}
@@ -0,0 +1,39 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/zukigit/chat/backend/internal/lib"
+	"google.golang.org/grpc"
+)
+
+// AccountCheck decides whether the signed-in user userID may use the API.
+// A non-nil error is returned to the client as is.
+type AccountCheck func(ctx context.Context, userID string) error
+
+// UnaryAccountInterceptor returns an interceptor that runs check for every
+// authenticated unary RPC. It must be chained after UnaryJWTInterceptor,
+// which puts the user ID in the context; calls without one are let through.
+func UnaryAccountInterceptor(check AccountCheck) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if userID := lib.CallerIDFrom(ctx); userID != "" {
+			if err := check(ctx, userID); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAccountInterceptor is the streaming counterpart of
+// UnaryAccountInterceptor.
+func StreamAccountInterceptor(check AccountCheck) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if userID := lib.CallerIDFrom(ss.Context()); userID != "" {
+			if err := check(ss.Context(), userID); err != nil {
+				return err
+			}
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,49 @@
+package interceptors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zukigit/chat/backend/internal/interceptors"
+	"github.com/zukigit/chat/backend/internal/lib"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryAccountInterceptor(t *testing.T) {
+	var checked []string
+	check := func(_ context.Context, userID string) error {
+		checked = append(checked, userID)
+		if userID == "banned" {
+			return status.Error(codes.PermissionDenied, "account banned")
+		}
+		return nil
+	}
+	interceptor := interceptors.UnaryAccountInterceptor(check)
+
+	cases := []struct {
+		name    string
+		userID  string
+		wantErr codes.Code
+	}{
+		{"no user in context", "", codes.OK},
+		{"good standing", "alice", codes.OK},
+		{"banned", "banned", codes.PermissionDenied},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.userID != "" {
+				ctx = context.WithValue(ctx, lib.ContextKeyUserID, tc.userID)
+			}
+			_, err := interceptor(ctx, nil, unaryInfo("/chat.Chat/SendMessage"), noopHandler)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Errorf("got %v, want %v", got, tc.wantErr)
+			}
+		})
+	}
+
+	if len(checked) != 2 {
+		t.Errorf("check called for %v, want [alice banned]", checked)
+	}
+}
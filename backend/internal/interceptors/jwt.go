@@ -15,11 +15,64 @@ import (
 // However, if a valid token IS present, those methods will reject the request
 // (the user is already logged in).
 var publicMethods = map[string]bool{
-	"/auth.Auth/Login":               true,
-	"/auth.Auth/Signup":              true,
-	"/auth.Auth/GetGithubOAuthURL":   true,
-	"/auth.Auth/GithubOAuthCallback": true,
-	"/session.Session/Ping":          true,
+	"/auth.Auth/Login":         true,
+	"/auth.Auth/Signup":        true,
+	"/auth.Auth/GetOAuthURL":   true,
+	"/auth.Auth/OAuthCallback": true,
+	"/session.Session/Ping":    true,
+	// Authorized by the refresh token in the request body; the access token
+	// it renews may already have expired.
+	"/auth.Auth/RefreshToken": true,
+	// Authorized by the recovery email inbox and the mailed reset token.
+	"/auth.Auth/RequestPasswordReset": true,
+	"/auth.Auth/ResetPassword":        true,
+	// Authorized by the challenge token from Login or ExchangeToken.
+	"/auth.Auth/VerifySecondFactor":       true,
+	"/auth.Auth/BeginSecondFactorPasskey": true,
+	// Authorized by the passkey's signature.
+	"/auth.Auth/BeginPasskeyLogin":  true,
+	"/auth.Auth/FinishPasskeyLogin": true,
+	// Authorized by the expiring token in the download link instead of a JWT.
+	"/chat.Chat/DownloadConversationExport": true,
+}
+
+// scopedMethods maps each method that takes a scoped token (see
+// lib.GenerateScopedToken) to its scope. These methods accept only a token
+// with that scope, and a scoped token is refused everywhere else.
+var scopedMethods = map[string]string{
+	"/auth.Auth/ExchangeToken": lib.ScopeOAuthExchange,
+}
+
+// checkScope refuses a scoped token outside the methods it was issued for,
+// and an access token, which has no scope, at a method that needs one.
+func checkScope(fullMethod string, claims *lib.Claims) error {
+	if scopedMethods[fullMethod] != claims.Scope {
+		return status.Error(codes.Unauthenticated, "token not valid for this method")
+	}
+	return nil
+}
+
+// SessionCheck decides whether the login loginID, taken from a valid token, is
+// still signed in. A non-nil error is returned to the client as is.
+type SessionCheck func(ctx context.Context, loginID string) error
+
+// sessionCheck is consulted by the JWT interceptors; nil accepts every login.
+var sessionCheck SessionCheck
+
+// SetSessionCheck makes the JWT interceptors reject tokens of logins that check
+// refuses, e.g. after logout. Call it before the server starts serving.
+func SetSessionCheck(check SessionCheck) {
+	sessionCheck = check
+}
+
+// checkSession runs sessionCheck for the login of claims, if one is set.
+// Scoped tokens belong to no login and are not checked; an access token
+// without a login_id is passed on and refused like an unknown login.
+func checkSession(ctx context.Context, claims *lib.Claims) error {
+	if sessionCheck == nil || claims.Scope != "" {
+		return nil
+	}
+	return sessionCheck(ctx, claims.LoginID)
 }
 
 // extractBearerToken reads the "authorization" metadata header and returns
@@ -47,6 +100,8 @@ func extractBearerToken(ctx context.Context) (string, bool) {
 //     · No need token.
 //   - All other methods:
 //     · No token or invalid token → reject with Unauthenticated.
+//     · Token with the wrong scope for the method → reject with Unauthenticated.
+//     · Token of a signed-out login → rejected by the SessionCheck.
 //     · Valid token → proceed, username injected into context.
 func UnaryJWTInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	tokenStr, hasToken := extractBearerToken(ctx)
@@ -64,6 +119,12 @@ func UnaryJWTInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryS
 	if err != nil {
 		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
 	}
+	if err := checkScope(info.FullMethod, claims); err != nil {
+		return nil, err
+	}
+	if err := checkSession(ctx, claims); err != nil {
+		return nil, err
+	}
 
 	ctx = context.WithValue(ctx, lib.ContextKeyUsername, claims.Username)
 	ctx = context.WithValue(ctx, lib.ContextKeyUserID, claims.UserID)
@@ -88,6 +149,12 @@ func StreamJWTInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.Stre
 	if err != nil {
 		return status.Error(codes.Unauthenticated, "invalid or expired token")
 	}
+	if err := checkScope(info.FullMethod, claims); err != nil {
+		return err
+	}
+	if err := checkSession(ctx, claims); err != nil {
+		return err
+	}
 
 	ctx = context.WithValue(ctx, lib.ContextKeyUsername, claims.Username)
 	ctx = context.WithValue(ctx, lib.ContextKeyUserID, claims.UserID)
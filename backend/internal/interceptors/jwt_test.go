@@ -3,7 +3,9 @@ package interceptors_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/zukigit/chat/backend/internal/interceptors"
 	"github.com/zukigit/chat/backend/internal/lib"
 	"google.golang.org/grpc"
@@ -93,6 +95,7 @@ func TestUnaryJWT_PublicMethods_AlwaysPass(t *testing.T) {
 		{"login_invalid_token", "/auth.Auth/Login", ctxWithToken(t, "bad-token")},
 		{"login_valid_token", "/auth.Auth/Login", ctxWithToken(t, tok)},
 		{"signup_no_token", "/auth.Auth/Signup", context.Background()},
+		{"refresh_expired_token", "/auth.Auth/RefreshToken", ctxWithToken(t, "bad-token")},
 	}
 
 	for _, tc := range cases {
@@ -125,3 +128,105 @@ func TestUnaryJWT_UsernameInContext(t *testing.T) {
 		t.Errorf("expected username 'testuser' in context, got %q", capturedUsername)
 	}
 }
+
+// ---- Scoped tokens ----
+
+func TestUnaryJWT_ScopedToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+	scoped, err := lib.GenerateScopedToken("00000000-0000-0000-0000-000000000001", "testuser", lib.ScopeOAuthExchange, time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateScopedToken: %v", err)
+	}
+	access := validToken(t)
+
+	cases := []struct {
+		name    string
+		token   string
+		method  string
+		wantErr codes.Code
+	}{
+		{"scoped_for_its_method", scoped, "/auth.Auth/ExchangeToken", codes.OK},
+		{"scoped_elsewhere", scoped, "/some.Service/Method", codes.Unauthenticated},
+		{"access_for_scoped_method", access, "/auth.Auth/ExchangeToken", codes.Unauthenticated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := interceptors.UnaryJWTInterceptor(ctxWithToken(t, tc.token), nil, unaryInfo(tc.method), noopHandler)
+			if got := status.Code(err); got != tc.wantErr {
+				t.Errorf("got gRPC code %v, want %v (err: %v)", got, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+// ---- Signed-out logins ----
+
+func TestUnaryJWT_SessionCheck(t *testing.T) {
+	tok := validToken(t)
+	claims, err := lib.ValidateToken(tok)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+
+	var checked string
+	interceptors.SetSessionCheck(func(ctx context.Context, loginID string) error {
+		checked = loginID
+		return status.Error(codes.Unauthenticated, "session has been signed out")
+	})
+	t.Cleanup(func() { interceptors.SetSessionCheck(nil) })
+
+	_, err = interceptors.UnaryJWTInterceptor(ctxWithToken(t, tok), nil, unaryInfo("/some.Service/Method"), noopHandler)
+	if st, _ := status.FromError(err); st.Code() != codes.Unauthenticated {
+		t.Errorf("protected method: got %v, want Unauthenticated", st.Code())
+	}
+	if checked != claims.LoginID {
+		t.Errorf("checked login %q, want %q", checked, claims.LoginID)
+	}
+
+	_, err = interceptors.UnaryJWTInterceptor(ctxWithToken(t, tok), nil, unaryInfo("/auth.Auth/Login"), noopHandler)
+	if err != nil {
+		t.Errorf("public method: got %v, want pass-through", err)
+	}
+}
+
+func TestUnaryJWT_SessionCheckWithoutLoginID(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+	noLogin, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &lib.Claims{
+		UserID:   "00000000-0000-0000-0000-000000000001",
+		Username: "testuser",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	scoped, err := lib.GenerateScopedToken("00000000-0000-0000-0000-000000000001", "testuser", lib.ScopeOAuthExchange, time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateScopedToken: %v", err)
+	}
+
+	checks := 0
+	interceptors.SetSessionCheck(func(ctx context.Context, loginID string) error {
+		checks++
+		if loginID == "" {
+			return status.Error(codes.Unauthenticated, "invalid or expired token")
+		}
+		return nil
+	})
+	t.Cleanup(func() { interceptors.SetSessionCheck(nil) })
+
+	// An access token without a login is not waved through.
+	_, err = interceptors.UnaryJWTInterceptor(ctxWithToken(t, noLogin), nil, unaryInfo("/some.Service/Method"), noopHandler)
+	if got := status.Code(err); got != codes.Unauthenticated {
+		t.Errorf("no login_id: got %v, want Unauthenticated", got)
+	}
+
+	// A scoped token belongs to no login and skips the check.
+	if _, err := interceptors.UnaryJWTInterceptor(ctxWithToken(t, scoped), nil, unaryInfo("/auth.Auth/ExchangeToken"), noopHandler); err != nil {
+		t.Errorf("scoped token: got %v, want pass-through", err)
+	}
+	if checks != 1 {
+		t.Errorf("session checks: got %d, want 1", checks)
+	}
+}
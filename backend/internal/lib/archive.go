@@ -0,0 +1,103 @@
+package lib
+
+import "time"
+
+// ArchiveFormat names the conversation archive format in its header record.
+const ArchiveFormat = "chat-conversation-archive"
+
+// ArchiveVersion is the current conversation archive version.
+// Increment this when making breaking changes to the record layout.
+const ArchiveVersion = 1
+
+// ArchiveRecordType identifies the kind of record on one line of an archive.
+type ArchiveRecordType string
+
+const (
+	ArchiveRecordHeader    ArchiveRecordType = "header"
+	ArchiveRecordMember    ArchiveRecordType = "member"
+	ArchiveRecordPublicKey ArchiveRecordType = "public_key"
+	ArchiveRecordReceipt   ArchiveRecordType = "receipt"
+	ArchiveRecordMessage   ArchiveRecordType = "message"
+	// ArchiveRecordReaction is reserved for message reactions. The server
+	// does not store reactions yet, so no archive contains one.
+	ArchiveRecordReaction ArchiveRecordType = "reaction"
+)
+
+// ArchiveRecord is one line of a conversation archive. An archive is
+// newline-delimited JSON: a single header record first, followed by member,
+// public_key, receipt, message and reaction records in that order.
+type ArchiveRecord struct {
+	Type ArchiveRecordType `json:"type"`
+	Data any               `json:"data"`
+}
+
+// ArchiveHeader is the Data of the header record.
+type ArchiveHeader struct {
+	Format       string              `json:"format"`
+	Version      int                 `json:"version"`
+	ExportedAt   time.Time           `json:"exported_at"`
+	ExportedBy   SystemEventUser     `json:"exported_by"`
+	Conversation ArchiveConversation `json:"conversation"`
+}
+
+// ArchiveConversation is the conversation metadata inside ArchiveHeader.
+type ArchiveConversation struct {
+	ID        int64     `json:"id"`
+	IsGroup   bool      `json:"is_group"`
+	Name      string    `json:"name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ArchiveMember is the Data of a member record. Current members come first,
+// then former members who sent a message, with Former set and no Role or
+// JoinedAt.
+type ArchiveMember struct {
+	UserID      string     `json:"user_id"`
+	Username    string     `json:"username"`
+	DisplayName string     `json:"display_name,omitempty"`
+	Role        string     `json:"role,omitempty"`
+	JoinedAt    *time.Time `json:"joined_at,omitempty"`
+	Former      bool       `json:"former,omitempty"`
+}
+
+// ArchivePublicKey is the Data of a public_key record. A key was valid from
+// ValidFrom until ValidUntil (nil for the user's current key), so a reader can
+// tell which key a message sent at a given time was encrypted to.
+type ArchivePublicKey struct {
+	UserID     string     `json:"user_id"`
+	Key        string     `json:"key"`
+	ValidFrom  time.Time  `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+// ArchiveReceipt is the Data of a receipt record.
+type ArchiveReceipt struct {
+	UserID                 string `json:"user_id"`
+	LastReadMessageID      string `json:"last_read_message_id,omitempty"`
+	LastDeliveredMessageID string `json:"last_delivered_message_id,omitempty"`
+}
+
+// ArchiveMessage is the Data of a message record. Content is exactly as
+// stored: ciphertext for user messages, JSON for "system" messages.
+type ArchiveMessage struct {
+	ID               string     `json:"id"`
+	SenderID         string     `json:"sender_id"`
+	ReplyToMessageID string     `json:"reply_to_message_id,omitempty"`
+	Content          string     `json:"content"`
+	MessageType      string     `json:"message_type"`
+	MediaURL         string     `json:"media_url,omitempty"`
+	IsEdited         bool       `json:"is_edited"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+}
+
+// ArchiveReaction is the Data of a reaction record, reserved for when the
+// server stores reactions.
+type ArchiveReaction struct {
+	MessageID string    `json:"message_id"`
+	UserID    string    `json:"user_id"`
+	Emoji     string    `json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -15,6 +15,7 @@ const (
 	ChatEventRead      ChatEventType = "read"
 	ChatEventError     ChatEventType = "error"
 	ChatEventSent      ChatEventType = "sent"
+	ChatEventSystem    ChatEventType = "system"
 )
 
 // SentEvent is the Data payload for ChatEventSent envelopes.
@@ -35,12 +36,64 @@ type ReadEvent struct {
 	MessageID      string `json:"message_id"`
 }
 
+// ChatErrorCode is a machine-readable reason inside an ErrorEvent, for errors
+// a client is expected to handle rather than just display.
+type ChatErrorCode string
+
+const (
+	// ChatErrorSlowMode means the message was rejected by the conversation's
+	// slow mode; ErrorEvent.RetryAfterMs says when the member may send again.
+	ChatErrorSlowMode ChatErrorCode = "slow_mode"
+	// ChatErrorReadOnly means the conversation is a DM between users who are
+	// no longer friends; its history stays readable but nobody can send.
+	ChatErrorReadOnly ChatErrorCode = "read_only"
+)
+
 // ErrorEvent is the Data payload for ChatEventError envelopes.
 type ErrorEvent struct {
-	ConversationID int64  `json:"conversation_id"`
-	MessageID      string `json:"message_id"`
-	Code           int    `json:"code"`
-	Message        string `json:"message"`
+	ConversationID int64         `json:"conversation_id"`
+	MessageID      string        `json:"message_id"`
+	Code           int           `json:"code"`
+	Message        string        `json:"message"`
+	ErrorCode      ChatErrorCode `json:"error_code,omitempty"`
+	RetryAfterMs   int64         `json:"retry_after_ms,omitempty"`
+}
+
+// SystemEventKind identifies what a system message records.
+type SystemEventKind string
+
+const (
+	SystemEventCreated       SystemEventKind = "created"
+	SystemEventMembersAdded  SystemEventKind = "members_added"
+	SystemEventMemberRemoved SystemEventKind = "member_removed"
+	SystemEventMemberLeft    SystemEventKind = "member_left"
+	SystemEventRenamed       SystemEventKind = "renamed"
+	SystemEventRoleChanged   SystemEventKind = "role_changed"
+	SystemEventKeyChanged    SystemEventKind = "key_changed"
+	SystemEventDeleted       SystemEventKind = "deleted"
+	SystemEventSlowMode      SystemEventKind = "slow_mode"
+)
+
+// SystemEventUser identifies a user referenced by a system event. The username
+// is captured at the time of the event so history renders even after the user
+// has left the conversation.
+type SystemEventUser struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// SystemEvent is the content of a "system" message. It is generated by the
+// server and stored unencrypted, so every client can render it in history.
+// It is delivered live as the Data of a ChatEventSystem envelope (inside a
+// Message row whose content is this JSON).
+type SystemEvent struct {
+	Kind    SystemEventKind   `json:"kind"`
+	Actor   SystemEventUser   `json:"actor"`
+	Targets []SystemEventUser `json:"targets,omitempty"`
+	Name    string            `json:"name,omitempty"` // new name for "created" / "renamed"
+	Role    string            `json:"role,omitempty"` // new role for "role_changed"
+	// SlowModeSeconds is the new interval for "slow_mode" (0 means off).
+	SlowModeSeconds *int32 `json:"slow_mode_seconds,omitempty"`
 }
 
 // ChatResponseEnvelope is the typed wrapper for all NATS chat payloads
@@ -2,6 +2,10 @@ package lib
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"log"
 	"os"
 
@@ -42,6 +46,11 @@ const ContextKeyLoginID contextKey = "login_id"
 const NotiSubjectPrefix = "sessions.noti."
 const ChatSubjectPrefix = "sessions.chat."
 
+// LoginRevokedSubjectPrefix is the core NATS subject, followed by a login_id,
+// on which the backend announces that a login was signed out. It lies outside
+// the SESSIONS stream: only gateways holding that login's WebSockets listen.
+const LoginRevokedSubjectPrefix = "logins.revoked."
+
 // CallerFrom extracts the authenticated username from the request context.
 // Returns "" if not present (should not happen for protected methods).
 func CallerFrom(ctx context.Context) string {
@@ -96,3 +105,20 @@ func OrderedUUIDPair(a, b uuid.UUID) (first, second uuid.UUID) {
 	}
 	return b, a
 }
+
+// HashToken returns the hex SHA-256 of an opaque bearer token, for storing
+// tokens server-side without keeping them usable.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RandomToken returns n cryptographically random bytes encoded as unpadded
+// URL-safe base64, suitable for links and opaque bearer tokens.
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,25 @@
+package lib
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// EncodeCursor returns an opaque page cursor holding v, for keyset-paged
+// RPCs whose sort key is more than a single ID.
+func EncodeCursor(v any) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor into v.
+func DecodeCursor(cursor string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
@@ -2,13 +2,92 @@ package lib
 
 import (
 	"errors"
+	"fmt"
+	"math"
+	"time"
 
 	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
+// ErrorDomain is the ErrorInfo domain attached to gRPC errors that carry a
+// ChatErrorCode.
+const ErrorDomain = "chat"
+
 // IsPgUniqueViolation reports whether err is a PostgreSQL unique‑constraint
 // violation (error code 23505).
 func IsPgUniqueViolation(err error) bool {
 	var pqErr *pq.Error
 	return errors.As(err, &pqErr) && pqErr.Code == "23505"
 }
+
+// IsPgForeignKeyViolation reports whether err is a PostgreSQL foreign‑key
+// violation (error code 23503).
+func IsPgForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// NewChatError returns a gRPC error with code c that carries reason as
+// ErrorInfo, which ChatErrorDetails reads back on the gateway.
+func NewChatError(c codes.Code, reason ChatErrorCode, msg string) error {
+	st, err := status.New(c, msg).WithDetails(
+		&errdetails.ErrorInfo{Reason: string(reason), Domain: ErrorDomain},
+	)
+	if err != nil {
+		return status.Error(c, msg)
+	}
+	return st.Err()
+}
+
+// NewSlowModeError returns the ResourceExhausted error for a message rejected
+// by slow mode. It carries ChatErrorSlowMode as ErrorInfo and retryAfter as
+// RetryInfo.
+func NewSlowModeError(retryAfter time.Duration) error {
+	msg := fmt.Sprintf("slow mode is on: wait %d seconds before sending again", int64(math.Ceil(retryAfter.Seconds())))
+	st, err := status.New(codes.ResourceExhausted, msg).WithDetails(
+		&errdetails.ErrorInfo{Reason: string(ChatErrorSlowMode), Domain: ErrorDomain},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)},
+	)
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, msg)
+	}
+	return st.Err()
+}
+
+// NewRetryError returns a gRPC error with code c that carries retryAfter as
+// RetryInfo, telling the client how long to wait before trying again.
+func NewRetryError(c codes.Code, msg string, retryAfter time.Duration) error {
+	st, err := status.New(c, msg).WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)},
+	)
+	if err != nil {
+		return status.Error(c, msg)
+	}
+	return st.Err()
+}
+
+// ChatErrorDetails returns the ChatErrorCode and retry delay carried by a gRPC
+// error, or zero values when it has none.
+func ChatErrorDetails(err error) (ChatErrorCode, time.Duration) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return "", 0
+	}
+	var code ChatErrorCode
+	var retryAfter time.Duration
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			if d.GetDomain() == ErrorDomain {
+				code = ChatErrorCode(d.GetReason())
+			}
+		case *errdetails.RetryInfo:
+			retryAfter = d.GetRetryDelay().AsDuration()
+		}
+	}
+	return code, retryAfter
+}
@@ -2,7 +2,9 @@ package lib
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 type Response struct {
@@ -16,3 +18,11 @@ func WriteJSON(w http.ResponseWriter, status int, payload any) {
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(payload)
 }
+
+// SetRetryAfter sets the Retry-After header, in whole seconds, when the gRPC
+// error err carries a retry delay.
+func SetRetryAfter(w http.ResponseWriter, err error) {
+	if _, retryAfter := ChatErrorDetails(err); retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(retryAfter.Seconds())), 10))
+	}
+}
@@ -12,7 +12,8 @@ import (
 type Claims struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
-	LoginID  string `json:"login_id"` // stable per login; used as durable consumer name
+	LoginID  string `json:"login_id"`        // stable per login; used as durable consumer name
+	Scope    string `json:"scope,omitempty"` // empty for access tokens; see GenerateScopedToken
 	jwt.RegisteredClaims
 }
 
@@ -27,13 +28,20 @@ func jwtSecret() []byte {
 }
 
 // GenerateTokenWithExpiry signs a JWT for the given userID and username with a
-// configurable expiry duration. Use 60*time.Second for OAuth redirect tokens
-// and 24*time.Hour for session tokens.
+// configurable expiry duration, starting a new login. OAuth redirect tokens
+// use GenerateScopedToken instead.
 func GenerateTokenWithExpiry(userID, username string, expiry time.Duration) (string, error) {
+	return GenerateTokenForLogin(userID, username, uuid.NewString(), expiry)
+}
+
+// GenerateTokenForLogin signs a JWT that continues an existing login: it keeps
+// loginID, so the session's durable consumer carries over, e.g. after the user
+// is renamed.
+func GenerateTokenForLogin(userID, username, loginID string, expiry time.Duration) (string, error) {
 	claims := &Claims{
 		UserID:   userID,
 		Username: username,
-		LoginID:  uuid.NewString(),
+		LoginID:  loginID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -44,11 +52,36 @@ func GenerateTokenWithExpiry(userID, username string, expiry time.Duration) (str
 	return token.SignedString(jwtSecret())
 }
 
-// GenerateToken signs a JWT for the given userID and username with a 24-hour expiry.
-// A fresh login_id UUID is embedded so each login session has a stable identifier
-// used as the durable NATS consumer name.
+// ScopeOAuthExchange marks the short-lived token an OAuth callback returns.
+// It is only good for ExchangeToken, so it cannot stand in for a session and
+// skip the second factor.
+const ScopeOAuthExchange = "oauth_exchange"
+
+// GenerateScopedToken signs a JWT that only the RPCs accepting scope take.
+func GenerateScopedToken(userID, username, scope string, expiry time.Duration) (string, error) {
+	claims := &Claims{
+		UserID:   userID,
+		Username: username,
+		Scope:    scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret())
+}
+
+// AccessTokenTTL is how long a session JWT is valid. Clients renew it with a
+// refresh token before it expires.
+const AccessTokenTTL = 24 * time.Hour
+
+// GenerateToken signs a JWT for the given userID and username that expires
+// after AccessTokenTTL. A fresh login_id UUID is embedded so each login
+// session has a stable identifier used as the durable NATS consumer name.
 func GenerateToken(userID, username string) (string, error) {
-	return GenerateTokenWithExpiry(userID, username, 24*time.Hour)
+	return GenerateTokenWithExpiry(userID, username, AccessTokenTTL)
 }
 
 // ValidateToken parses and validates a JWT string, returning the embedded Claims.
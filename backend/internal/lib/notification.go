@@ -0,0 +1,49 @@
+package lib
+
+import "time"
+
+// NotificationRetracted is the type of a NotificationRetractedEvent. It never
+// appears on a stored notification.
+const NotificationRetracted = "retracted"
+
+// NotificationRetractedEvent is pushed on the notification stream when stored
+// notifications are withdrawn, e.g. because a friend request was cancelled.
+// Clients should drop the listed notifications from their inbox.
+type NotificationRetractedEvent struct {
+	Type          string  `json:"type"` // always NotificationRetracted
+	IDs           []int64 `json:"ids"`
+	RetractedType string  `json:"retracted_type"` // type of the withdrawn notifications
+}
+
+// NotificationEvent is the wire form of a stored notification, pushed on the
+// notification stream when it is created. At most one of
+// ReferenceConversationID and ReferenceUserID is set, depending on Type.
+type NotificationEvent struct {
+	ID                      int64     `json:"id"`
+	UserID                  string    `json:"user_id"`
+	SenderID                *string   `json:"sender_id"`
+	Type                    string    `json:"type"`
+	Message                 string    `json:"message"`
+	ReferenceConversationID *int64    `json:"reference_conversation_id"`
+	ReferenceUserID         *string   `json:"reference_user_id"`
+	IsRead                  bool      `json:"is_read"`
+	CreatedAt               time.Time `json:"created_at"`
+}
+
+// NotificationProfileUpdated is the type of a ProfileUpdatedEvent. It never
+// appears on a stored notification.
+const NotificationProfileUpdated = "profile_updated"
+
+// ProfileUpdatedEvent is pushed on the notification stream of a user's
+// friends, conversation peers and own devices when the user changes their
+// profile. StatusExpiresAt is nil when the status does not expire.
+type ProfileUpdatedEvent struct {
+	Type            string     `json:"type"` // always NotificationProfileUpdated
+	UserID          string     `json:"user_id"`
+	UserName        string     `json:"user_name"`
+	DisplayName     string     `json:"display_name"`
+	AvatarURL       string     `json:"avatar_url"`
+	Bio             string     `json:"bio"`
+	StatusText      string     `json:"status_text"`
+	StatusExpiresAt *time.Time `json:"status_expires_at,omitempty"`
+}
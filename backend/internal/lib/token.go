@@ -2,6 +2,7 @@ package lib
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"strings"
 
@@ -23,3 +24,33 @@ func BearerToken(r *http.Request) (string, bool) {
 func WithToken(ctx context.Context, token string) context.Context {
 	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
 }
+
+// ClientInfo describes the device a request came from, as seen by the gateway.
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// WithClientInfo forwards the user agent and remote IP of r to the backend in
+// the outgoing gRPC metadata, for recording where a login came from.
+func WithClientInfo(ctx context.Context, r *http.Request) context.Context {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return metadata.AppendToOutgoingContext(ctx, "x-client-user-agent", r.UserAgent(), "x-client-ip", ip)
+}
+
+// ClientInfoFrom reads the ClientInfo forwarded by WithClientInfo from the
+// incoming gRPC metadata. Fields are empty when the gateway sent none.
+func ClientInfoFrom(ctx context.Context) ClientInfo {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var info ClientInfo
+	if v := md.Get("x-client-user-agent"); len(v) > 0 {
+		info.UserAgent = v[0]
+	}
+	if v := md.Get("x-client-ip"); len(v) > 0 {
+		info.IP = v[0]
+	}
+	return info
+}
@@ -0,0 +1,122 @@
+// Package mailer delivers the emails the backend sends, such as password reset
+// links. Mailer is the extension point: SMTPMailer talks to a mail server,
+// while FileOutbox and LogMailer keep messages local for self-hosted setups
+// without one and for tests.
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/zukigit/chat/backend/internal/lib"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers a Message.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// format renders msg as an RFC 5322 message from the given sender.
+func format(from string, msg Message, now time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// checkHeaders rejects header values that would inject further headers.
+func checkHeaders(msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return fmt.Errorf("mailer: header contains a line break")
+	}
+	return nil
+}
+
+// SMTPMailer sends mail through an SMTP server, using STARTTLS when the
+// server offers it.
+type SMTPMailer struct {
+	addr string // host:port
+	from string
+	auth smtp.Auth // nil for servers that need no login
+}
+
+// NewSMTPMailer creates an SMTPMailer for the server at addr (host:port).
+// username may be empty for servers that accept mail without a login.
+func NewSMTPMailer(addr, username, password, from string) *SMTPMailer {
+	m := &SMTPMailer{addr: addr, from: from}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+// Send delivers msg. The context is not consulted: net/smtp has no
+// cancellation.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := checkHeaders(msg); err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, format(m.from, msg, time.Now()))
+}
+
+// FileOutbox writes each message to its own .eml file in a directory instead
+// of sending it.
+type FileOutbox struct {
+	dir  string
+	from string
+	seq  atomic.Int64
+}
+
+// NewFileOutbox creates a FileOutbox writing to dir, which is created if
+// needed.
+func NewFileOutbox(dir, from string) (*FileOutbox, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("mailer: create outbox: %w", err)
+	}
+	return &FileOutbox{dir: dir, from: from}, nil
+}
+
+// Send writes msg to a new file named after the time and a sequence number,
+// so files sort in the order they were sent.
+func (o *FileOutbox) Send(ctx context.Context, msg Message) error {
+	if err := checkHeaders(msg); err != nil {
+		return err
+	}
+	now := time.Now()
+	name := fmt.Sprintf("%s-%06d.eml", now.UTC().Format("20060102T150405.000000000"), o.seq.Add(1))
+	return os.WriteFile(filepath.Join(o.dir, name), format(o.from, msg, now), 0o600)
+}
+
+// LogMailer writes each message to the info log instead of sending it. The
+// message body, including any links in it, ends up in the log.
+type LogMailer struct{}
+
+// Send logs msg.
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	if err := checkHeaders(msg); err != nil {
+		return err
+	}
+	lib.InfoLog.Printf("mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,63 @@
+package mailer_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zukigit/chat/backend/internal/mailer"
+)
+
+func TestFileOutbox(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	outbox, err := mailer.NewFileOutbox(dir, "chat@example.com")
+	if err != nil {
+		t.Fatalf("NewFileOutbox: %v", err)
+	}
+
+	for _, subject := range []string{"first", "second"} {
+		if err := outbox.Send(context.Background(), mailer.Message{
+			To:      "alice@example.com",
+			Subject: subject,
+			Body:    "line one\nline two",
+		}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d files, want 2", len(entries))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{"From: chat@example.com\r\n", "To: alice@example.com\r\n", "Subject: first\r\n", "\r\n\r\nline one\r\nline two"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message %q does not contain %q", got, want)
+		}
+	}
+}
+
+func TestHeaderInjection(t *testing.T) {
+	outbox, err := mailer.NewFileOutbox(t.TempDir(), "chat@example.com")
+	if err != nil {
+		t.Fatalf("NewFileOutbox: %v", err)
+	}
+	mailers := map[string]mailer.Mailer{"file": outbox, "log": mailer.LogMailer{}}
+	for name, m := range mailers {
+		t.Run(name, func(t *testing.T) {
+			err := m.Send(context.Background(), mailer.Message{To: "alice@example.com\r\nBcc: eve@example.com", Subject: "hi"})
+			if err == nil {
+				t.Error("expected an error for a line break in To")
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package oauth_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/zukigit/chat/backend/internal/oauth"
+)
+
+func TestGitHub(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		// GitHub reports a bad code with status 200.
+		if r.PostForm.Get("code") != "code-1" || r.PostForm.Get("code_verifier") != "verifier-1" ||
+			r.PostForm.Get("client_secret") != "secret-1" {
+			json.NewEncoder(w).Encode(map[string]string{"error": "bad_verification_code"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "token-1", "token_type": "bearer"})
+	})
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"id": 42, "login": "octocat", "name": "The Octocat"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p := oauth.NewGitHub(oauth.Config{ClientID: "client-1", ClientSecret: "secret-1", RedirectURL: redirectURL})
+	p.TokenEndpoint = srv.URL + "/token"
+	p.UserEndpoint = srv.URL + "/user"
+
+	authURL, err := p.AuthURL(context.Background(), oauth.AuthRequest{State: "state-1", CodeChallenge: oauth.CodeChallenge("verifier-1")})
+	if err != nil {
+		t.Fatalf("AuthURL: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	if q := u.Query(); q.Get("client_id") != "client-1" || q.Get("state") != "state-1" || q.Get("code_challenge_method") != "S256" {
+		t.Errorf("AuthURL query: %v", q)
+	}
+
+	id, err := p.Exchange(context.Background(), "code-1", "verifier-1", "")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if id.Subject != "42" || id.Username != "octocat" || id.Name != "The Octocat" {
+		t.Errorf("identity: got %+v", *id)
+	}
+
+	if _, err := p.Exchange(context.Background(), "code-1", "verifier-2", ""); err == nil {
+		t.Error("expected an error for the wrong verifier")
+	}
+}
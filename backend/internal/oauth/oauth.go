@@ -0,0 +1,181 @@
+// Package oauth signs users in with external identity providers. Provider is
+// the extension point: GitHub speaks GitHub's OAuth dialect, while OIDC works
+// with any OpenID Connect issuer, finding its endpoints through discovery and
+// verifying ID tokens against its published keys. Every sign-in uses PKCE.
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Identity is the provider account a sign-in vouches for.
+type Identity struct {
+	Subject       string // stable account ID at the provider
+	Username      string // suggested username; may be taken or invalid here
+	Name          string
+	AvatarURL     string
+	Email         string
+	EmailVerified bool
+}
+
+// AuthRequest carries the per-sign-in values of an authorization URL.
+type AuthRequest struct {
+	State         string // returned to the callback unchanged
+	CodeChallenge string // CodeChallenge of the PKCE verifier
+	Nonce         string // bound into the OIDC ID token
+}
+
+// Provider is an identity provider users can sign in with.
+type Provider interface {
+	// AuthURL returns the page to send the user to.
+	AuthURL(ctx context.Context, req AuthRequest) (string, error)
+	// Exchange redeems the code the provider sent to the callback, proving
+	// the sign-in started with codeVerifier and nonce, and returns the
+	// account that signed in.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error)
+}
+
+// Config is the client registration shared by all providers.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string       // the callback registered with the provider
+	Scopes       []string     // nil uses the provider's defaults
+	HTTPClient   *http.Client // nil uses a client with a 10 second timeout
+}
+
+func (c Config) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return defaultClient
+}
+
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// CodeChallenge returns the S256 PKCE challenge for verifier (RFC 7636).
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// authCodeParams are the query parameters of an authorization code request.
+func authCodeParams(cfg Config, scopes []string, req AuthRequest) url.Values {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", cfg.ClientID)
+	q.Set("redirect_uri", cfg.RedirectURL)
+	q.Set("scope", strings.Join(scopes, " "))
+	q.Set("state", req.State)
+	q.Set("code_challenge", req.CodeChallenge)
+	q.Set("code_challenge_method", "S256")
+	return q
+}
+
+// withQuery appends q to endpoint, which may already have a query.
+func withQuery(endpoint string, q url.Values) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("oauth: invalid endpoint %q: %w", endpoint, err)
+	}
+	existing := u.Query()
+	for k, vs := range q {
+		existing[k] = vs
+	}
+	u.RawQuery = existing.Encode()
+	return u.String(), nil
+}
+
+// getJSON fetches endpoint into v. header may add request headers.
+func getJSON(ctx context.Context, client *http.Client, endpoint string, header http.Header, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("oauth: create request: %w", err)
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Accept", "application/json")
+	return doJSON(client, req, v)
+}
+
+// postForm posts form to endpoint and decodes the JSON response into v.
+// With basicAuth the client ID and secret go in an Authorization header
+// (client_secret_basic); otherwise they go in the form (client_secret_post).
+func postForm(ctx context.Context, cfg Config, endpoint string, form url.Values, basicAuth bool, v any) error {
+	if basicAuth {
+		form.Del("client_secret")
+	} else {
+		form.Set("client_id", cfg.ClientID)
+		form.Set("client_secret", cfg.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("oauth: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if basicAuth {
+		req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+	}
+	return doJSON(cfg.httpClient(), req, v)
+}
+
+// maxResponseSize bounds the provider responses read into memory.
+const maxResponseSize = 1 << 20
+
+func doJSON(client *http.Client, req *http.Request, v any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("oauth: %s %s: %w", req.Method, req.URL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("oauth: read %s: %w", req.URL.Redacted(), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Error != "" {
+			return fmt.Errorf("oauth: %s %s: %s: %s", req.Method, req.URL.Redacted(), oauthErr.Error, oauthErr.Description)
+		}
+		return fmt.Errorf("oauth: %s %s: status %d", req.Method, req.URL.Redacted(), resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("oauth: decode %s: %w", req.URL.Redacted(), err)
+	}
+	return nil
+}
+
+// tokenResponse is the part of a token endpoint response providers share.
+// GitHub reports errors with status 200, so Error is checked too.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (t *tokenResponse) check() error {
+	if t.Error != "" {
+		return fmt.Errorf("oauth: token request: %s: %s", t.Error, t.ErrorDescription)
+	}
+	if t.AccessToken == "" {
+		return fmt.Errorf("oauth: token response has no access token")
+	}
+	return nil
+}
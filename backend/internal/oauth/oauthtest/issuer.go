@@ -0,0 +1,259 @@
+// Package oauthtest provides a local OpenID Connect issuer, standing in for
+// Google and friends in tests of the sign-in flow.
+package oauthtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var b64 = base64.RawURLEncoding
+
+// User is the account the issuer signs in as.
+type User struct {
+	Subject           string
+	PreferredUsername string
+	Name              string
+	Picture           string
+	Email             string
+	EmailVerified     bool
+}
+
+// Issuer is an OIDC issuer on a local HTTP server. Its authorization
+// endpoint approves every request for ClientID at once, as User, and its
+// token endpoint checks the client secret, redirect URI and PKCE verifier
+// the way a real issuer would.
+type Issuer struct {
+	URL          string
+	ClientID     string
+	ClientSecret string
+	// User is the account the next authorization signs in as.
+	User User
+
+	srv   *httptest.Server
+	mu    sync.Mutex
+	key   *rsa.PrivateKey
+	kid   string
+	codes map[string]grant
+}
+
+// grant is an issued authorization code.
+type grant struct {
+	redirectURI string
+	challenge   string
+	nonce       string
+	user        User
+}
+
+// NewIssuer starts an issuer for one client. Close it when done.
+func NewIssuer(clientID, clientSecret string) *Issuer {
+	iss := &Issuer{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		User:         User{Subject: "user-1", PreferredUsername: "alice", Name: "Alice", Email: "alice@example.com", EmailVerified: true},
+		codes:        make(map[string]grant),
+	}
+	iss.RotateKey()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", iss.discovery)
+	mux.HandleFunc("GET /jwks", iss.jwks)
+	mux.HandleFunc("GET /authorize", iss.authorize)
+	mux.HandleFunc("POST /token", iss.token)
+	iss.srv = httptest.NewServer(mux)
+	iss.URL = iss.srv.URL
+	return iss
+}
+
+// Close shuts the issuer down.
+func (iss *Issuer) Close() { iss.srv.Close() }
+
+// RotateKey replaces the signing key. Only the new key is published, so ID
+// tokens signed before the rotation no longer verify.
+func (iss *Issuer) RotateKey() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(fmt.Sprintf("oauthtest: generate key: %v", err))
+	}
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	iss.key, iss.kid = key, randomString(8)
+}
+
+// Authorize follows authURL as the user's browser would and returns the
+// code and state the issuer redirected back with.
+func (iss *Issuer) Authorize(authURL string) (code, state string, err error) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		return "", "", fmt.Errorf("oauthtest: authorize: status %d", resp.StatusCode)
+	}
+	loc, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+	q := loc.Query()
+	if e := q.Get("error"); e != "" {
+		return "", "", fmt.Errorf("oauthtest: authorize: %s", e)
+	}
+	return q.Get("code"), q.Get("state"), nil
+}
+
+func (iss *Issuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                iss.URL,
+		"authorization_endpoint":                iss.URL + "/authorize",
+		"token_endpoint":                        iss.URL + "/token",
+		"jwks_uri":                              iss.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (iss *Issuer) jwks(w http.ResponseWriter, r *http.Request) {
+	iss.mu.Lock()
+	pub, kid := iss.key.PublicKey, iss.kid
+	iss.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   b64.EncodeToString(pub.N.Bytes()),
+		"e":   b64.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+}
+
+func (iss *Issuer) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI := q.Get("redirect_uri")
+	if q.Get("client_id") != iss.ClientID || redirectURI == "" {
+		http.Error(w, "unknown client", http.StatusBadRequest)
+		return
+	}
+	back, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "bad redirect_uri", http.StatusBadRequest)
+		return
+	}
+	params := back.Query()
+	params.Set("state", q.Get("state"))
+	switch {
+	case q.Get("response_type") != "code":
+		params.Set("error", "unsupported_response_type")
+	case q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256":
+		params.Set("error", "invalid_request")
+	default:
+		code := randomString(16)
+		iss.mu.Lock()
+		iss.codes[code] = grant{redirectURI: redirectURI, challenge: q.Get("code_challenge"), nonce: q.Get("nonce"), user: iss.User}
+		iss.mu.Unlock()
+		params.Set("code", code)
+	}
+	back.RawQuery = params.Encode()
+	http.Redirect(w, r, back.String(), http.StatusFound)
+}
+
+func (iss *Issuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	id, secret, ok := r.BasicAuth()
+	if ok {
+		id, _ = url.QueryUnescape(id)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if id != iss.ClientID || secret != iss.ClientSecret {
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	// Codes are single use, whether or not the exchange succeeds.
+	iss.mu.Lock()
+	code := r.PostForm.Get("code")
+	g, found := iss.codes[code]
+	delete(iss.codes, code)
+	key, kid := iss.key, iss.kid
+	iss.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !found || g.redirectURI != r.PostForm.Get("redirect_uri") || b64.EncodeToString(sum[:]) != g.challenge {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                iss.URL,
+		"sub":                g.user.Subject,
+		"aud":                iss.ClientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(5 * time.Minute).Unix(),
+		"preferred_username": g.user.PreferredUsername,
+		"name":               g.user.Name,
+		"picture":            g.user.Picture,
+		"email":              g.user.Email,
+		"email_verified":     g.user.EmailVerified,
+	}
+	if g.nonce != "" {
+		claims["nonce"] = g.nonce
+	}
+	t := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	t.Header["kid"] = kid
+	idToken, err := t.SignedString(key)
+	if err != nil {
+		tokenError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomString(16),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func tokenError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("oauthtest: random: %v", err))
+	}
+	return b64.EncodeToString(b)
+}
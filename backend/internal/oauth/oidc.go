@@ -0,0 +1,302 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// discoveryTTL is how long a fetched discovery document is trusted.
+	discoveryTTL = time.Hour
+	// jwksRefetchInterval bounds how often unknown key IDs may refetch the
+	// issuer's keys, so forged kids cannot make us hammer the issuer.
+	jwksRefetchInterval = time.Minute
+	// clockSkew is the leeway allowed on ID token times.
+	clockSkew = time.Minute
+)
+
+// idTokenAlgs are the ID token signature algorithms accepted.
+var idTokenAlgs = []string{"RS256", "RS384", "RS512", "PS256", "ES256", "ES384"}
+
+// OIDC signs users in with an OpenID Connect issuer. Endpoints and signing
+// keys are discovered from the issuer on first use and cached.
+type OIDC struct {
+	issuer string
+	cfg    Config
+
+	mu      sync.Mutex
+	meta    *discovery
+	metaAt  time.Time
+	keys    map[string]crypto.PublicKey // nil until first fetched
+	refetch time.Time                   // last refetch for an unknown kid
+}
+
+// NewOIDC returns a provider for the issuer URL, e.g.
+// "https://accounts.google.com". It asks for the openid, profile and email
+// scopes unless cfg.Scopes says otherwise; openid is always added.
+func NewOIDC(issuer string, cfg Config) *OIDC {
+	if cfg.Scopes == nil {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	} else if !slices.Contains(cfg.Scopes, "openid") {
+		cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+	}
+	return &OIDC{issuer: strings.TrimSuffix(issuer, "/"), cfg: cfg}
+}
+
+// discovery is the part of the OpenID provider metadata the sign-in uses.
+type discovery struct {
+	Issuer                   string   `json:"issuer"`
+	AuthorizationEndpoint    string   `json:"authorization_endpoint"`
+	TokenEndpoint            string   `json:"token_endpoint"`
+	JWKSURI                  string   `json:"jwks_uri"`
+	TokenEndpointAuthMethods []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethods     []string `json:"code_challenge_methods_supported"`
+}
+
+// discover returns the issuer's metadata, fetching it when not cached.
+func (o *OIDC) discover(ctx context.Context) (*discovery, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.meta != nil && time.Since(o.metaAt) < discoveryTTL {
+		return o.meta, nil
+	}
+
+	var meta discovery
+	if err := getJSON(ctx, o.cfg.httpClient(), o.issuer+"/.well-known/openid-configuration", nil, &meta); err != nil {
+		return nil, err
+	}
+	// The issuer must be exactly the one configured (OIDC Discovery §4.3),
+	// or one issuer could mint tokens that pass for another's.
+	if meta.Issuer != o.issuer {
+		return nil, fmt.Errorf("oauth: discovery issuer %q does not match %q", meta.Issuer, o.issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("oauth: discovery for %s is missing endpoints", o.issuer)
+	}
+	if len(meta.CodeChallengeMethods) > 0 && !slices.Contains(meta.CodeChallengeMethods, "S256") {
+		return nil, fmt.Errorf("oauth: %s does not support S256 PKCE", o.issuer)
+	}
+	o.meta, o.metaAt = &meta, time.Now()
+	return o.meta, nil
+}
+
+// AuthURL implements Provider.
+func (o *OIDC) AuthURL(ctx context.Context, req AuthRequest) (string, error) {
+	meta, err := o.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	q := authCodeParams(o.cfg, o.cfg.Scopes, req)
+	q.Set("nonce", req.Nonce)
+	return withQuery(meta.AuthorizationEndpoint, q)
+}
+
+// idTokenClaims are the ID token claims the sign-in reads.
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce"`
+	AuthorizedParty   string `json:"azp"`
+	PreferredUsername string `json:"preferred_username"`
+	Name              string `json:"name"`
+	Picture           string `json:"picture"`
+	Email             string `json:"email"`
+	EmailVerified     any    `json:"email_verified"` // some issuers send "true"
+}
+
+// Exchange implements Provider. The account comes from the ID token, which
+// must be signed by the issuer, addressed to this client and carry nonce.
+func (o *OIDC) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	meta, err := o.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", o.cfg.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	basicAuth := len(meta.TokenEndpointAuthMethods) == 0 || slices.Contains(meta.TokenEndpointAuthMethods, "client_secret_basic")
+	var token tokenResponse
+	if err := postForm(ctx, o.cfg, meta.TokenEndpoint, form, basicAuth, &token); err != nil {
+		return nil, err
+	}
+	if err := token.check(); err != nil {
+		return nil, err
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("oauth: token response has no ID token")
+	}
+
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(token.IDToken, claims,
+		func(t *jwt.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+			return o.key(ctx, meta.JWKSURI, kid)
+		},
+		jwt.WithValidMethods(idTokenAlgs),
+		jwt.WithIssuer(o.issuer),
+		jwt.WithAudience(o.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("oauth: verify ID token: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, errors.New("oauth: ID token nonce does not match")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != o.cfg.ClientID {
+		return nil, errors.New("oauth: ID token was issued to another client")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("oauth: ID token has no subject")
+	}
+
+	id := &Identity{
+		Subject:   claims.Subject,
+		Username:  claims.PreferredUsername,
+		Name:      claims.Name,
+		AvatarURL: claims.Picture,
+		Email:     claims.Email,
+	}
+	switch v := claims.EmailVerified.(type) {
+	case bool:
+		id.EmailVerified = v
+	case string:
+		id.EmailVerified = v == "true"
+	}
+	if id.Username == "" && id.Email != "" {
+		id.Username, _, _ = strings.Cut(id.Email, "@")
+	}
+	return id, nil
+}
+
+// key returns the issuer's signing key kid. An unknown kid refetches the
+// key set, as the issuer may have rotated keys since it was cached. An
+// empty kid matches the only key in the set.
+func (o *OIDC) key(ctx context.Context, jwksURI, kid string) (crypto.PublicKey, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if k, ok := o.lookup(kid); ok {
+		return k, nil
+	}
+	if o.keys != nil {
+		if time.Since(o.refetch) < jwksRefetchInterval {
+			return nil, fmt.Errorf("oauth: unknown signing key %q", kid)
+		}
+		o.refetch = time.Now()
+	}
+
+	var set jwks
+	if err := getJSON(ctx, o.cfg.httpClient(), jwksURI, nil, &set); err != nil {
+		return nil, err
+	}
+	o.keys = make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// Keys of unsupported types are skipped, not fatal: issuers may
+		// publish keys for algorithms this package never accepts.
+		if pub, err := k.publicKey(); err == nil {
+			o.keys[k.Kid] = pub
+		}
+	}
+
+	if k, ok := o.lookup(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("oauth: unknown signing key %q", kid)
+}
+
+func (o *OIDC) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(o.keys) == 1 {
+		for _, k := range o.keys {
+			return k, true
+		}
+	}
+	k, ok := o.keys[kid]
+	return k, ok
+}
+
+// jwks is a JSON Web Key Set (RFC 7517).
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exp := new(big.Int).SetBytes(e)
+		if len(n) < 256 || !exp.IsInt64() || exp.Int64() < 3 || exp.Int64() > 1<<31-1 {
+			return nil, errors.New("oauth: unsupported RSA key")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("oauth: unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, errors.New("oauth: malformed EC key")
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("oauth: EC key is not on its curve")
+		}
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("oauth: unsupported key type %q", k.Kty)
+	}
+}
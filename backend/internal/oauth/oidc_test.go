@@ -0,0 +1,143 @@
+package oauth_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zukigit/chat/backend/internal/oauth"
+	"github.com/zukigit/chat/backend/internal/oauth/oauthtest"
+)
+
+const redirectURL = "http://localhost:8080/oauth/test/callback"
+
+func newOIDC(iss *oauthtest.Issuer, issuerURL string) *oauth.OIDC {
+	return oauth.NewOIDC(issuerURL, oauth.Config{
+		ClientID:     iss.ClientID,
+		ClientSecret: iss.ClientSecret,
+		RedirectURL:  redirectURL,
+	})
+}
+
+// signIn starts a sign-in with p, approves it at iss and returns the code
+// the callback would receive.
+func signIn(t *testing.T, p oauth.Provider, iss *oauthtest.Issuer, verifier, nonce string) string {
+	t.Helper()
+	authURL, err := p.AuthURL(context.Background(), oauth.AuthRequest{
+		State:         "state-1",
+		CodeChallenge: oauth.CodeChallenge(verifier),
+		Nonce:         nonce,
+	})
+	if err != nil {
+		t.Fatalf("AuthURL: %v", err)
+	}
+	code, state, err := iss.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	if state != "state-1" {
+		t.Fatalf("state: got %q, want state-1", state)
+	}
+	return code
+}
+
+func TestOIDC_SignIn(t *testing.T) {
+	iss := oauthtest.NewIssuer("client-1", "secret-1")
+	defer iss.Close()
+	iss.User.Picture = "https://example.com/alice.png"
+	p := newOIDC(iss, iss.URL)
+
+	code := signIn(t, p, iss, "verifier-1", "nonce-1")
+	id, err := p.Exchange(context.Background(), code, "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	want := oauth.Identity{
+		Subject:       "user-1",
+		Username:      "alice",
+		Name:          "Alice",
+		AvatarURL:     "https://example.com/alice.png",
+		Email:         "alice@example.com",
+		EmailVerified: true,
+	}
+	if *id != want {
+		t.Errorf("identity: got %+v, want %+v", *id, want)
+	}
+
+	// Codes are single use.
+	if _, err := p.Exchange(context.Background(), code, "verifier-1", "nonce-1"); err == nil {
+		t.Error("code redeemed twice")
+	}
+}
+
+func TestOIDC_UsernameFromEmail(t *testing.T) {
+	iss := oauthtest.NewIssuer("client-1", "secret-1")
+	defer iss.Close()
+	iss.User.PreferredUsername = ""
+	p := newOIDC(iss, iss.URL)
+
+	code := signIn(t, p, iss, "verifier-1", "nonce-1")
+	id, err := p.Exchange(context.Background(), code, "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if id.Username != "alice" {
+		t.Errorf("Username: got %q, want alice", id.Username)
+	}
+}
+
+func TestOIDC_Rejects(t *testing.T) {
+	iss := oauthtest.NewIssuer("client-1", "secret-1")
+	defer iss.Close()
+	p := newOIDC(iss, iss.URL)
+
+	t.Run("wrong verifier", func(t *testing.T) {
+		code := signIn(t, p, iss, "verifier-1", "nonce-1")
+		if _, err := p.Exchange(context.Background(), code, "verifier-2", "nonce-1"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("wrong nonce", func(t *testing.T) {
+		code := signIn(t, p, iss, "verifier-1", "nonce-1")
+		_, err := p.Exchange(context.Background(), code, "verifier-1", "nonce-2")
+		if err == nil || !strings.Contains(err.Error(), "nonce") {
+			t.Errorf("got %v, want a nonce error", err)
+		}
+	})
+
+	t.Run("wrong client secret", func(t *testing.T) {
+		bad := oauth.NewOIDC(iss.URL, oauth.Config{ClientID: iss.ClientID, ClientSecret: "wrong", RedirectURL: redirectURL})
+		code := signIn(t, bad, iss, "verifier-1", "nonce-1")
+		if _, err := bad.Exchange(context.Background(), code, "verifier-1", "nonce-1"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("issuer mismatch", func(t *testing.T) {
+		// The discovery document names the issuer by IP, not localhost.
+		other := newOIDC(iss, strings.Replace(iss.URL, "127.0.0.1", "localhost", 1))
+		_, err := other.AuthURL(context.Background(), oauth.AuthRequest{State: "s", CodeChallenge: "c", Nonce: "n"})
+		if err == nil || !strings.Contains(err.Error(), "issuer") {
+			t.Errorf("got %v, want an issuer mismatch", err)
+		}
+	})
+}
+
+func TestOIDC_KeyRotation(t *testing.T) {
+	iss := oauthtest.NewIssuer("client-1", "secret-1")
+	defer iss.Close()
+	p := newOIDC(iss, iss.URL)
+
+	code := signIn(t, p, iss, "verifier-1", "nonce-1")
+	if _, err := p.Exchange(context.Background(), code, "verifier-1", "nonce-1"); err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	// The new key is unknown to the cached key set, so it is refetched.
+	iss.RotateKey()
+	code = signIn(t, p, iss, "verifier-2", "nonce-2")
+	if _, err := p.Exchange(context.Background(), code, "verifier-2", "nonce-2"); err != nil {
+		t.Fatalf("Exchange after rotation: %v", err)
+	}
+}
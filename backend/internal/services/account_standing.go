@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AccountStanding answers whether a user is suspended or banned, caching each
+// answer for a short time so the per-RPC check does not hit the database on
+// every call.
+type AccountStanding struct {
+	sqlDB *sql.DB
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	entries map[string]standingEntry
+}
+
+// maxStandingEntries is the cache size above which expired entries are swept.
+const maxStandingEntries = 10000
+
+type standingEntry struct {
+	err     error // nil while the account is in good standing
+	expires time.Time
+}
+
+// NewAccountStanding creates an AccountStanding that caches answers for ttl.
+func NewAccountStanding(sqlDB *sql.DB, ttl time.Duration) *AccountStanding {
+	return &AccountStanding{sqlDB: sqlDB, ttl: ttl, entries: make(map[string]standingEntry)}
+}
+
+// Check returns a PermissionDenied error if userID is suspended or banned.
+// It has the signature of interceptors.AccountCheck.
+func (a *AccountStanding) Check(ctx context.Context, userID string) error {
+	now := time.Now()
+
+	a.mu.Lock()
+	entry, ok := a.entries[userID]
+	a.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.err
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "invalid or expired token")
+	}
+	standingErr, until, err := accountStandingError(ctx, db.New(a.sqlDB), id)
+	if err != nil {
+		return status.Errorf(codes.Internal, "check account standing: %v", err)
+	}
+
+	entry = standingEntry{err: standingErr, expires: now.Add(a.ttl)}
+	if !until.IsZero() && until.Before(entry.expires) {
+		// Do not keep a suspension cached past its end.
+		entry.expires = until
+	}
+
+	a.mu.Lock()
+	if len(a.entries) >= maxStandingEntries {
+		for id, e := range a.entries {
+			if !now.Before(e.expires) {
+				delete(a.entries, id)
+			}
+		}
+	}
+	a.entries[userID] = entry
+	a.mu.Unlock()
+	return standingErr
+}
+
+// Forget drops the cached answer for userID, so a moderation action applies
+// to their next call. It is nil-safe.
+func (a *AccountStanding) Forget(userID uuid.UUID) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	delete(a.entries, userID.String())
+	a.mu.Unlock()
+}
+
+// accountStandingError returns the PermissionDenied error for a suspended or
+// banned user and, for a suspension, when it ends. standingErr is nil for a
+// user in good standing; err reports a database failure.
+func accountStandingError(ctx context.Context, q *db.Queries, userID uuid.UUID) (standingErr error, until time.Time, err error) {
+	restriction, err := q.GetAccountRestriction(ctx, userID)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if restriction.Kind == db.ModerationActionTypeBan {
+		return status.Error(codes.PermissionDenied, "account banned"), time.Time{}, nil
+	}
+	until = restriction.ExpiresAt.Time
+	return status.Errorf(codes.PermissionDenied, "account suspended until %s", until.UTC().Format(time.RFC3339)), until, nil
+}
@@ -5,15 +5,17 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/zukigit/chat/backend/internal/db"
 	"github.com/zukigit/chat/backend/internal/lib"
+	"github.com/zukigit/chat/backend/internal/oauth"
+	"github.com/zukigit/chat/backend/internal/webauthn"
 	"github.com/zukigit/chat/backend/proto/auth"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
@@ -23,14 +25,25 @@ import (
 // AuthServer implements the auth.AuthServer interface
 type AuthServer struct {
 	auth.UnimplementedAuthServer
-	sqlDB *sql.DB
+	sqlDB      *sql.DB
+	notif      *NotificationServer // nil disables live pushes (e.g. in tests)
+	refreshTTL time.Duration
+	sessions   *LoginSessions // nil leaves signed-out logins' live delivery to expire
+	reset      PasswordReset  // zero value disables mailed password resets
+	totpIssuer string
+	webauthn   *webauthn.RelyingParty    // nil disables passkeys
+	oauth      map[string]oauth.Provider // by configured name; empty disables OAuth sign-in
 }
 
-// NewAuthServer creates a new AuthServer instance
-func NewAuthServer(sqlDB *sql.DB) *AuthServer {
+// NewAuthServer creates a new AuthServer instance.
+// notif may be nil, in which case live pushes are skipped.
+func NewAuthServer(sqlDB *sql.DB, notif *NotificationServer) *AuthServer {
 	if sqlDB != nil {
 		return &AuthServer{
-			sqlDB: sqlDB,
+			sqlDB:      sqlDB,
+			notif:      notif,
+			refreshTTL: DefaultRefreshTokenTTL,
+			totpIssuer: DefaultTOTPIssuer,
 		}
 	}
 
@@ -46,6 +59,9 @@ func (s *AuthServer) Login(ctx context.Context, req *auth.LoginRequest) (*auth.L
 	if req.Passwd == "" {
 		return nil, status.Error(codes.InvalidArgument, "password is required")
 	}
+	if err := validateDeviceName(req.DeviceName); err != nil {
+		return nil, err
+	}
 
 	// Get queries
 	queries := db.New(s.sqlDB)
@@ -68,13 +84,39 @@ func (s *AuthServer) Login(ctx context.Context, req *auth.LoginRequest) (*auth.L
 		return nil, status.Error(codes.Unauthenticated, "invalid username or password")
 	}
 
-	// Generate JWT token (contains a fresh login_id UUID)
-	token, err := lib.GenerateToken(user.UserID.String(), req.UserName)
+	// Suspended and banned users cannot sign in.
+	standingErr, _, err := accountStandingError(ctx, queries, user.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "login: check account standing: %v", err)
+	}
+	if standingErr != nil {
+		return nil, standingErr
+	}
+
+	// With two-factor sign-in on, the login starts at VerifySecondFactor.
+	challenge, err := secondFactorChallenge(ctx, queries, user.UserID, req.DeviceName)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "login: start second factor: %v", err)
+	}
+	if challenge != "" {
+		return &auth.LoginResponse{SecondFactorRequired: true, ChallengeToken: challenge}, nil
+	}
+
+	// Start a new login: a fresh login_id with its first refresh token.
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "login: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	token, refreshToken, err := s.startLogin(ctx, db.New(tx), user.UserID, user.UserName, req.DeviceName)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "login: generate token: %v", err)
+		return nil, status.Errorf(codes.Internal, "login: start login: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "login: commit: %v", err)
 	}
 
-	return &auth.LoginResponse{Token: token}, nil
+	return &auth.LoginResponse{Token: token, RefreshToken: refreshToken}, nil
 }
 
 // Signup handles user signup
@@ -86,6 +128,11 @@ func (s *AuthServer) Signup(ctx context.Context, req *auth.SignupRequest) (*auth
 	if req.Passwd == "" {
 		return nil, status.Error(codes.InvalidArgument, "password is required")
 	}
+	if req.Email != "" {
+		if err := validateEmail(req.Email); err != nil {
+			return nil, err
+		}
+	}
 
 	// Start a database transaction
 	tx, err := s.sqlDB.BeginTx(ctx, nil)
@@ -105,13 +152,16 @@ func (s *AuthServer) Signup(ctx context.Context, req *auth.SignupRequest) (*auth
 	if err != sql.ErrNoRows {
 		return nil, status.Errorf(codes.Internal, "signup: check username: %v", err)
 	}
+	if err := checkUsernameReservation(ctx, queries, req.UserName, uuid.Nil); err != nil {
+		return nil, err
+	}
 
 	hashedPasswd, err := bcrypt.GenerateFromPassword([]byte(req.Passwd), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "signup: hash password: %v", err)
 	}
 
-	_, err = queries.CreateUser(ctx, db.CreateUserParams{
+	user, err := queries.CreateUser(ctx, db.CreateUserParams{
 		UserName:     req.UserName,
 		HashedPasswd: sql.NullString{String: string(hashedPasswd), Valid: true},
 		SignupType:   db.SignupTypeEmail,
@@ -120,6 +170,15 @@ func (s *AuthServer) Signup(ctx context.Context, req *auth.SignupRequest) (*auth
 		return nil, status.Errorf(codes.Internal, "signup: create user: %v", err)
 	}
 
+	if req.Email != "" {
+		if err := setRecoveryEmail(ctx, queries, user.UserID, req.Email); err != nil {
+			if _, ok := status.FromError(err); ok {
+				return nil, err
+			}
+			return nil, status.Errorf(codes.Internal, "signup: set recovery email: %v", err)
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, status.Errorf(codes.Internal, "signup: commit: %v", err)
 	}
@@ -127,7 +186,9 @@ func (s *AuthServer) Signup(ctx context.Context, req *auth.SignupRequest) (*auth
 	return &auth.SignupResponse{}, nil
 }
 
-// SearchUsers searches for users by username or display name
+// SearchUsers searches for users by username or display name.
+// Exact username matches come first, then the rest by trigram similarity;
+// results are cursor-paged.
 func (s *AuthServer) SearchUsers(ctx context.Context, req *auth.SearchUsersRequest) (*auth.SearchUsersResponse, error) {
 	if req.Query == "" {
 		return nil, status.Error(codes.InvalidArgument, "query is required")
@@ -138,16 +199,37 @@ func (s *AuthServer) SearchUsers(ctx context.Context, req *auth.SearchUsersReque
 		return nil, err
 	}
 
+	params := db.SearchUsersParams{
+		Query:     req.Query,
+		CallerID:  callerID,
+		PageLimit: searchPageLimit(req.Limit),
+	}
+	if req.Cursor != "" {
+		var c searchCursor
+		if err := lib.DecodeCursor(req.Cursor, &c); err != nil || c.UserID == uuid.Nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		}
+		params.CursorExact = sql.NullInt32{Valid: true, Int32: c.Exact}
+		params.CursorScore = sql.NullFloat64{Valid: true, Float64: c.Score}
+		params.CursorUserID = uuid.NullUUID{Valid: true, UUID: c.UserID}
+	}
+
 	queries := db.New(s.sqlDB)
 
-	users, err := queries.SearchUsers(ctx, db.SearchUsersParams{
-		Column1:     sql.NullString{String: req.Query, Valid: true},
-		User1Userid: callerID,
-	})
+	users, err := queries.SearchUsers(ctx, params)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "search users: %v", err)
 	}
 
+	userIDs := make([]uuid.UUID, 0, len(users))
+	for _, u := range users {
+		userIDs = append(userIDs, u.UserID)
+	}
+	mutual, err := mutualFriends(ctx, queries, callerID, userIDs)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "search users: mutual friends: %v", err)
+	}
+
 	var results []*auth.UserResult
 	for _, u := range users {
 		var friendshipStatus string
@@ -165,180 +247,101 @@ func (s *AuthServer) SearchUsers(ctx context.Context, req *auth.SearchUsersReque
 			AvatarUrl:                 u.AvatarUrl.String,
 			FriendshipStatus:          friendshipStatus,
 			FriendshipInitiatorUserid: friendshipInitiatorUserid,
+			MutualFriendCount:         mutual[u.UserID].MutualCount,
+			MutualFriends:             mutual[u.UserID].Sample,
 		}
 		results = append(results, result)
 	}
 
-	return &auth.SearchUsersResponse{Users: results}, nil
-}
-
-// GetGithubOAuthURL returns the GitHub authorization URL for OAuth flow.
-func (s *AuthServer) GetGithubOAuthURL(ctx context.Context, req *auth.GetGithubOAuthURLRequest) (*auth.GetGithubOAuthURLResponse, error) {
-	clientID := lib.Getenv("GITHUB_OAUTH_CLIENT_ID", "")
-	if clientID == "" {
-		return nil, status.Error(codes.InvalidArgument, "GITHUB_OAUTH_CLIENT_ID is not configured")
-	}
-
-	gatewayURL := lib.Getenv("GATEWAY_PUBLIC_URL", "")
-	if gatewayURL == "" {
-		gatewayURL = "http://localhost:8080"
+	resp := &auth.SearchUsersResponse{Users: results}
+	if len(users) == int(params.PageLimit) {
+		last := users[len(users)-1]
+		resp.NextCursor, err = lib.EncodeCursor(searchCursor{Exact: last.ExactMatch, Score: last.Score, UserID: last.UserID})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "search users: encode cursor: %v", err)
+		}
 	}
-
-	redirectURI := gatewayURL + "/oauth/github/callback"
-	authURL := fmt.Sprintf(
-		"https://github.com/login/oauth/authorize?client_id=%s&redirect_uri=%s&scope=read:user",
-		url.QueryEscape(clientID),
-		url.QueryEscape(redirectURI),
-	)
-
-	return &auth.GetGithubOAuthURLResponse{Url: authURL}, nil
-}
-
-// githubTokenResponse represents the JSON response from GitHub's token endpoint.
-type githubTokenResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	Scope       string `json:"scope"`
+	return resp, nil
 }
 
-// githubUser represents the JSON response from GitHub's user info endpoint.
-type githubUser struct {
-	Login     string `json:"login"`
-	Name      string `json:"name"`
-	AvatarURL string `json:"avatar_url"`
+// searchCursor is the keyset position of the last row of a search page.
+// UserID is set for user search and ID for conversation search.
+type searchCursor struct {
+	Exact  int32     `json:"e"`
+	Score  float64   `json:"s"`
+	UserID uuid.UUID `json:"u,omitempty"`
+	ID     int64     `json:"i,omitempty"`
 }
 
-// GithubOAuthCallback exchanges the GitHub OAuth code for a short-lived JWT.
-func (s *AuthServer) GithubOAuthCallback(ctx context.Context, req *auth.GithubOAuthCallbackRequest) (*auth.GithubOAuthCallbackResponse, error) {
-	if req.Code == "" {
-		return nil, status.Error(codes.InvalidArgument, "missing authorization code")
-	}
-
-	clientID := lib.Getenv("GITHUB_OAUTH_CLIENT_ID", "")
-	clientSecret := lib.Getenv("GITHUB_OAUTH_CLIENT_SECRET", "")
-	if clientID == "" || clientSecret == "" {
-		return nil, status.Error(codes.Internal, "GitHub OAuth credentials not configured")
+// searchPageLimit applies the default (20) and maximum (50) search page size.
+func searchPageLimit(limit int32) int32 {
+	if limit <= 0 {
+		return 20
 	}
-
-	gatewayURL := lib.Getenv("GATEWAY_PUBLIC_URL", "")
-	if gatewayURL == "" {
-		gatewayURL = "http://localhost:8080"
+	if limit > 50 {
+		return 50
 	}
+	return limit
+}
 
-	// Exchange code for access token
-	tokenResp, err := exchangeCodeForToken(clientID, clientSecret, gatewayURL, req.Code)
-	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, fmt.Sprintf("failed to exchange code for token: %v", err))
+// ExchangeToken exchanges a short-lived OAuth token for a long-lived session token.
+func (s *AuthServer) ExchangeToken(ctx context.Context, req *auth.ExchangeTokenRequest) (*auth.ExchangeTokenResponse, error) {
+	userID, ok := ctx.Value(lib.ContextKeyUserID).(string)
+	if !ok || userID == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid short-lived token")
 	}
 
-	// Fetch GitHub user profile
-	ghUser, err := fetchGitHubUser(tokenResp.AccessToken)
+	callerID, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to fetch GitHub user: %v", err))
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid short-lived token")
 	}
-
-	username := ghUser.Login
-	if username == "" {
-		return nil, status.Error(codes.Internal, "GitHub user login is empty")
+	if err := validateDeviceName(req.DeviceName); err != nil {
+		return nil, err
 	}
 
+	// Use the current username: the one in the short-lived token may be stale.
 	queries := db.New(s.sqlDB)
-
-	// Check if user exists
-	existingUser, err := queries.GetUserByUsername(ctx, username)
-	if err != nil && err != sql.ErrNoRows {
-		return nil, status.Errorf(codes.Internal, "github callback: get user: %v", err)
-	}
-
+	user, err := queries.GetUserByID(ctx, callerID)
 	if err == sql.ErrNoRows {
-		// Signup path: create new user
-		tx, err := s.sqlDB.BeginTx(ctx, nil)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "github callback: begin tx: %v", err)
-		}
-		defer tx.Rollback()
-
-		txQueries := db.New(tx)
-
-		_, err = txQueries.CreateUser(ctx, db.CreateUserParams{
-			UserName:     username,
-			HashedPasswd: sql.NullString{Valid: false},
-			SignupType:   db.SignupTypeGithub,
-		})
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "github callback: create user: %v", err)
-		}
-
-		// Fetch the created user to get the ID
-		createdUser, err := txQueries.GetUserByUsername(ctx, username)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "github callback: get created user: %v", err)
-		}
-
-		// Update profile with display name and avatar URL
-		_, err = txQueries.UpdateUserProfile(ctx, db.UpdateUserProfileParams{
-			UserName:    username,
-			DisplayName: sql.NullString{String: ghUser.Name, Valid: ghUser.Name != ""},
-			AvatarUrl:   sql.NullString{String: ghUser.AvatarURL, Valid: ghUser.AvatarURL != ""},
-		})
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "github callback: update profile: %v", err)
-		}
-
-		if err := tx.Commit(); err != nil {
-			return nil, status.Errorf(codes.Internal, "github callback: commit: %v", err)
-		}
-
-		// Generate short-lived token
-		shortLivedToken, err := lib.GenerateTokenWithExpiry(createdUser.UserID.String(), username, 60*time.Second)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "github callback: generate token: %v", err)
-		}
-
-		return &auth.GithubOAuthCallbackResponse{
-			ShortLivedToken: shortLivedToken,
-			Username:        username,
-		}, nil
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid short-lived token")
 	}
-
-	// Login path: user exists
-	if existingUser.SignupType != db.SignupTypeGithub {
-		return nil, status.Error(codes.FailedPrecondition, "this account was created with email/password, not GitHub")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "exchange token: get user: %v", err)
 	}
+	username := user.UserName
 
-	// Generate short-lived token
-	shortLivedToken, err := lib.GenerateTokenWithExpiry(existingUser.UserID.String(), username, 60*time.Second)
+	// GitHub stands in for the password only: with two-factor sign-in on,
+	// the login starts at VerifySecondFactor.
+	challenge, err := secondFactorChallenge(ctx, queries, callerID, req.DeviceName)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "github callback: generate token: %v", err)
+		return nil, status.Errorf(codes.Internal, "exchange token: start second factor: %v", err)
 	}
-
-	return &auth.GithubOAuthCallbackResponse{
-		ShortLivedToken: shortLivedToken,
-		Username:        username,
-	}, nil
-}
-
-// ExchangeToken exchanges a short-lived OAuth token for a long-lived session token.
-func (s *AuthServer) ExchangeToken(ctx context.Context, req *auth.ExchangeTokenRequest) (*auth.ExchangeTokenResponse, error) {
-	userID, ok := ctx.Value(lib.ContextKeyUserID).(string)
-	if !ok || userID == "" {
-		return nil, status.Error(codes.Unauthenticated, "missing or invalid short-lived token")
+	if challenge != "" {
+		return &auth.ExchangeTokenResponse{
+			Username:             username,
+			SecondFactorRequired: true,
+			ChallengeToken:       challenge,
+		}, nil
 	}
 
-	username, ok := ctx.Value(lib.ContextKeyUsername).(string)
-	if !ok || username == "" {
-		return nil, status.Error(codes.Unauthenticated, "missing or invalid short-lived token")
+	// Generate long-lived token for a new login, with its first refresh token
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "exchange token: begin tx: %v", err)
 	}
-
-	// Generate long-lived token
-	longLivedToken, err := lib.GenerateToken(userID, username)
+	defer tx.Rollback()
+	longLivedToken, refreshToken, err := s.startLogin(ctx, db.New(tx), callerID, username, req.DeviceName)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "exchange token: generate token: %v", err)
+		return nil, status.Errorf(codes.Internal, "exchange token: start login: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "exchange token: commit: %v", err)
 	}
 
 	return &auth.ExchangeTokenResponse{
-		Token:    longLivedToken,
-		Username: username,
+		Token:        longLivedToken,
+		Username:     username,
+		RefreshToken: refreshToken,
 	}, nil
 }
 
@@ -396,9 +399,30 @@ func (s *AuthServer) SetupKeys(ctx context.Context, req *auth.SetupKeysRequest)
 		return nil, status.Errorf(codes.Internal, "setup keys: set e2ee keys: %v", err)
 	}
 
+	// Record the key change in every conversation so peers re-fetch the key.
+	conversations, err := txQueries.GetConversationsByUser(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "setup keys: get conversations: %v", err)
+	}
+	actor := callerEventUser(ctx, txQueries)
+	pushes := make([]systemEventPush, 0, len(conversations))
+	for _, c := range conversations {
+		push, err := recordSystemEvent(ctx, txQueries, c.ID, lib.SystemEvent{
+			Kind:  lib.SystemEventKeyChanged,
+			Actor: actor,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "setup keys: record system event: %v", err)
+		}
+		pushes = append(pushes, push)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, status.Errorf(codes.Internal, "setup keys: commit: %v", err)
 	}
+	for _, push := range pushes {
+		push.publish(s.notif)
+	}
 
 	return &auth.SetupKeysResponse{IsE2EeReady: true}, nil
 }
@@ -472,73 +496,319 @@ func (s *AuthServer) GetPublicKeys(ctx context.Context, req *auth.GetPublicKeysR
 	return &auth.GetPublicKeysResponse{Keys: entries}, nil
 }
 
-// exchangeCodeForToken exchanges the GitHub OAuth code for an access token.
-func exchangeCodeForToken(clientID, clientSecret, gatewayURL, code string) (*githubTokenResponse, error) {
-	redirectURI := gatewayURL + "/oauth/github/callback"
+// Profile field limits, in characters. They match the column sizes.
+const (
+	maxDisplayNameLen = 100
+	maxBioLen         = 500
+	maxStatusTextLen  = 140
+	maxAvatarURLLen   = 2048
+)
+
+// GetProfile returns a user's profile, or the caller's own when user_id is
+// empty. Users who have blocked the caller are reported as not found. The
+// last-seen time is only included if the user's privacy settings allow it.
+func (s *AuthServer) GetProfile(ctx context.Context, req *auth.GetProfileRequest) (*auth.Profile, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := callerID
+	if req.UserId != "" {
+		userID, err = uuid.Parse(req.UserId)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid user_id: %s", req.UserId)
+		}
+	}
+
+	queries := db.New(s.sqlDB)
+
+	if userID != callerID {
+		block, err := queries.GetBlockState(ctx, db.GetBlockStateParams{UserID: callerID, OtherID: userID})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "get profile: get block state: %v", err)
+		}
+		if block.IsBlockedBy {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+	}
+
+	user, err := queries.GetUserByID(ctx, userID)
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get profile: %v", err)
+	}
+
+	profile := profileFromUser(user, time.Now())
+	if user.LastSeenAt.Valid {
+		visible, err := lastSeenVisible(ctx, queries, userID, callerID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "get profile: check last seen visibility: %v", err)
+		}
+		if visible {
+			profile.LastSeenAt = user.LastSeenAt.Time.Format(time.RFC3339)
+		}
+	}
 
-	data := url.Values{}
-	data.Set("client_id", clientID)
-	data.Set("client_secret", clientSecret)
-	data.Set("code", code)
-	data.Set("redirect_uri", redirectURI)
+	return profile, nil
+}
 
-	req, err := http.NewRequest("POST", "https://github.com/login/oauth/access_token",
-		strings.NewReader(data.Encode()))
+// UpdateProfile changes the caller's display name, avatar, bio or status and
+// pushes a ProfileUpdatedEvent to their friends, conversation peers and own
+// devices.
+func (s *AuthServer) UpdateProfile(ctx context.Context, req *auth.UpdateProfileRequest) (*auth.Profile, error) {
+	callerID, err := lib.CallerUUID(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create token request: %w", err)
+		return nil, err
 	}
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	params, err := updateProfileParams(req, time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("token request failed: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	params.UserID = callerID
+
+	queries := db.New(s.sqlDB)
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("token request failed with status %d: %s", resp.StatusCode, string(body))
+	user, err := queries.UpdateProfile(ctx, params)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "update profile: %v", err)
 	}
+	if err := s.pushProfile(ctx, queries, user); err != nil {
+		return nil, status.Errorf(codes.Internal, "update profile: %v", err)
+	}
+
+	return profileFromUser(user, time.Now()), nil
+}
 
-	var tokenResp githubTokenResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return nil, fmt.Errorf("failed to decode token response: %w", err)
+// pushProfile sends a ProfileUpdatedEvent for user to their friends,
+// conversation peers and own devices. Live push failures are logged only.
+func (s *AuthServer) pushProfile(ctx context.Context, queries *db.Queries, user db.User) error {
+	audience, err := queries.ListProfileAudience(ctx, user.UserID)
+	if err != nil {
+		return fmt.Errorf("list audience: %w", err)
 	}
 
-	if tokenResp.AccessToken == "" {
-		return nil, fmt.Errorf("no access token in GitHub response")
+	profile := profileFromUser(user, time.Now())
+	event := lib.ProfileUpdatedEvent{
+		Type:        lib.NotificationProfileUpdated,
+		UserID:      profile.UserId,
+		UserName:    profile.UserName,
+		DisplayName: profile.DisplayName,
+		AvatarURL:   profile.AvatarUrl,
+		Bio:         profile.Bio,
+		StatusText:  profile.StatusText,
+	}
+	if profile.StatusExpiresAt != "" {
+		event.StatusExpiresAt = &user.StatusExpiresAt.Time
+	}
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
 	}
 
-	return &tokenResp, nil
+	for _, userID := range append(audience, user.UserID) {
+		if err := s.notif.publishIfOnline(userID, lib.NotiSubjectPrefix, eventBytes); err != nil {
+			lib.ErrorLog.Printf("push profile of %s to %s: %v", user.UserID, userID, err)
+		}
+	}
+	return nil
 }
 
-// fetchGitHubUser fetches the user profile from GitHub API.
-func fetchGitHubUser(accessToken string) (*githubUser, error) {
-	req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
+// Username change rules.
+const (
+	// usernameChangeCooldown is how long a user must wait between renames.
+	usernameChangeCooldown = 30 * 24 * time.Hour
+	// usernameReservation is how long a released name stays reserved for the
+	// user who gave it up.
+	usernameReservation = 90 * 24 * time.Hour
+)
+
+// usernamePattern is what ChangeUsername accepts: 3 to 50 letters, digits,
+// '.', '_' or '-', starting with a letter or digit.
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{2,49}$`)
+
+// ChangeUsername renames the caller. The old name is recorded in the username
+// history, which limits renames to one per usernameChangeCooldown and keeps
+// the old name reserved for the caller for usernameReservation. The response
+// carries a fresh token for the same login with the new name; older tokens
+// keep working because every service path keys on user_id.
+func (s *AuthServer) ChangeUsername(ctx context.Context, req *auth.ChangeUsernameRequest) (*auth.ChangeUsernameResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user request: %w", err)
+		return nil, err
+	}
+
+	newName := strings.TrimSpace(req.NewUsername)
+	if !usernamePattern.MatchString(newName) {
+		return nil, status.Error(codes.InvalidArgument, "username must be 3-50 letters, digits, '.', '_' or '-', starting with a letter or digit")
 	}
-	req.Header.Set("Authorization", "Bearer "+accessToken)
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("user request failed: %w", err)
+		return nil, status.Errorf(codes.Internal, "change username: begin tx: %v", err)
 	}
-	defer resp.Body.Close()
+	defer tx.Rollback()
+
+	queries := db.New(tx)
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("user request failed with status %d: %s", resp.StatusCode, string(body))
+	// Locking the row makes a concurrent rename wait here, so it sees this
+	// one's history row and fails the cooldown check.
+	user, err := queries.GetUserByIDForUpdate(ctx, callerID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "change username: get user: %v", err)
+	}
+	if user.UserName == newName {
+		return nil, status.Error(codes.InvalidArgument, "new username is the same as the current one")
 	}
 
-	var user githubUser
-	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
-		return nil, fmt.Errorf("failed to decode user response: %w", err)
+	lastChange, err := queries.GetLastUsernameChange(ctx, callerID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, status.Errorf(codes.Internal, "change username: get last change: %v", err)
 	}
+	if err == nil && time.Since(lastChange) < usernameChangeCooldown {
+		return nil, status.Errorf(codes.FailedPrecondition, "username can be changed again after %s",
+			lastChange.Add(usernameChangeCooldown).UTC().Format(time.RFC3339))
+	}
+
+	if err := checkUsernameReservation(ctx, queries, newName, callerID); err != nil {
+		return nil, err
+	}
+
+	if err := queries.InsertUsernameHistory(ctx, db.InsertUsernameHistoryParams{
+		UserID:   callerID,
+		UserName: user.UserName,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "change username: record history: %v", err)
+	}
+
+	updated, err := queries.ChangeUsername(ctx, db.ChangeUsernameParams{
+		UserID:      callerID,
+		NewUserName: newName,
+	})
+	if lib.IsPgUniqueViolation(err) {
+		return nil, status.Error(codes.AlreadyExists, "username already taken")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "change username: update: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "change username: commit: %v", err)
+	}
+
+	token, err := lib.GenerateTokenForLogin(callerID.String(), newName, lib.CallerLoginID(ctx), lib.AccessTokenTTL)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "change username: generate token: %v", err)
+	}
+
+	if err := s.pushProfile(ctx, db.New(s.sqlDB), updated); err != nil {
+		lib.ErrorLog.Printf("change username: %v", err)
+	}
+
+	return &auth.ChangeUsernameResponse{Username: newName, Token: token}, nil
+}
 
-	return &user, nil
+// checkUsernameReservation returns AlreadyExists if name was released within
+// usernameReservation by anyone other than userID. Pass uuid.Nil for a new
+// account.
+func checkUsernameReservation(ctx context.Context, queries *db.Queries, name string, userID uuid.UUID) error {
+	holder, err := queries.GetUsernameReservation(ctx, db.GetUsernameReservationParams{
+		UserName:      name,
+		ReleasedAfter: time.Now().Add(-usernameReservation),
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "check username reservation: %v", err)
+	}
+	if holder != userID {
+		return status.Errorf(codes.AlreadyExists, "username %q is reserved", name)
+	}
+	return nil
+}
+
+// updateProfileParams validates req and converts it to query parameters.
+// UserID is left for the caller to fill in.
+func updateProfileParams(req *auth.UpdateProfileRequest, now time.Time) (db.UpdateProfileParams, error) {
+	var params db.UpdateProfileParams
+
+	if req.DisplayName != nil {
+		name := strings.TrimSpace(*req.DisplayName)
+		if utf8.RuneCountInString(name) > maxDisplayNameLen {
+			return params, status.Errorf(codes.InvalidArgument, "display_name must be at most %d characters", maxDisplayNameLen)
+		}
+		params.SetDisplayName, params.DisplayName = true, name
+	}
+
+	if req.AvatarUrl != nil {
+		avatar := strings.TrimSpace(*req.AvatarUrl)
+		if avatar != "" {
+			u, err := url.Parse(avatar)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(avatar) > maxAvatarURLLen {
+				return params, status.Error(codes.InvalidArgument, "avatar_url must be an http(s) URL")
+			}
+		}
+		params.SetAvatarUrl, params.AvatarUrl = true, avatar
+	}
+
+	if req.Bio != nil {
+		bio := strings.TrimSpace(*req.Bio)
+		if utf8.RuneCountInString(bio) > maxBioLen {
+			return params, status.Errorf(codes.InvalidArgument, "bio must be at most %d characters", maxBioLen)
+		}
+		params.SetBio, params.Bio = true, bio
+	}
+
+	if req.StatusText != nil {
+		text := strings.TrimSpace(*req.StatusText)
+		if utf8.RuneCountInString(text) > maxStatusTextLen {
+			return params, status.Errorf(codes.InvalidArgument, "status_text must be at most %d characters", maxStatusTextLen)
+		}
+		params.SetStatus, params.StatusText = true, text
+	}
+
+	if req.StatusExpiresAt != "" {
+		if params.StatusText == "" {
+			return params, status.Error(codes.InvalidArgument, "status_expires_at requires status_text")
+		}
+		expiresAt, err := time.Parse(time.RFC3339, req.StatusExpiresAt)
+		if err != nil {
+			return params, status.Errorf(codes.InvalidArgument, "invalid status_expires_at: %v", err)
+		}
+		if !expiresAt.After(now) {
+			return params, status.Error(codes.InvalidArgument, "status_expires_at must be in the future")
+		}
+		params.StatusExpiresAt = sql.NullTime{Valid: true, Time: expiresAt}
+	}
+
+	if !params.SetDisplayName && !params.SetAvatarUrl && !params.SetBio && !params.SetStatus {
+		return params, status.Error(codes.InvalidArgument, "no profile fields to update")
+	}
+	return params, nil
+}
+
+// profileFromUser converts u to its API form as of now, dropping an expired
+// status.
+func profileFromUser(u db.User, now time.Time) *auth.Profile {
+	p := &auth.Profile{
+		UserId:      u.UserID.String(),
+		UserName:    u.UserName,
+		DisplayName: u.DisplayName.String,
+		AvatarUrl:   u.AvatarUrl.String,
+		Bio:         u.Bio.String,
+		StatusText:  u.StatusText.String,
+		UpdatedAt:   u.UpdatedAt.Format(time.RFC3339),
+	}
+	if u.StatusExpiresAt.Valid {
+		if u.StatusExpiresAt.Time.After(now) {
+			p.StatusExpiresAt = u.StatusExpiresAt.Time.Format(time.RFC3339)
+		} else {
+			p.StatusText = ""
+		}
+	}
+	return p
 }
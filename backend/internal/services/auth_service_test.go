@@ -1,13 +1,35 @@
 package services_test
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
 	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
+	"github.com/zukigit/chat/backend/internal/mailer"
+	"github.com/zukigit/chat/backend/internal/oauth"
+	"github.com/zukigit/chat/backend/internal/oauth/oauthtest"
 	"github.com/zukigit/chat/backend/internal/services"
+	"github.com/zukigit/chat/backend/internal/totp"
+	"github.com/zukigit/chat/backend/internal/webauthn"
+	"github.com/zukigit/chat/backend/internal/webauthn/webauthntest"
 	"github.com/zukigit/chat/backend/proto/auth"
+	"github.com/zukigit/chat/backend/proto/friendship"
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestLogin(t *testing.T) {
@@ -44,7 +66,7 @@ func TestLogin(t *testing.T) {
 		{"empty password", "test", "", true},
 	}
 
-	authServer := services.NewAuthServer(sqlDb)
+	authServer := services.NewAuthServer(sqlDb, nil)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			_, err = authServer.Login(t.Context(), &auth.LoginRequest{
@@ -80,7 +102,7 @@ func TestSignup(t *testing.T) {
 		{"duplicate username", "user1", "password", true},
 	}
 
-	authServer := services.NewAuthServer(sqlDb)
+	authServer := services.NewAuthServer(sqlDb, nil)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			_, err := authServer.Signup(t.Context(), &auth.SignupRequest{
@@ -153,7 +175,7 @@ func TestSearchUsers(t *testing.T) {
 		t.Fatalf("failed to update friendship status: %v", err)
 	}
 
-	authServer := services.NewAuthServer(sqlDb)
+	authServer := services.NewAuthServer(sqlDb, nil)
 
 	// SearchUsers returns all matching users (including friends), with
 	// friendship_status populated for existing friendships. Use bob as the caller.
@@ -223,3 +245,1601 @@ func TestSearchUsers(t *testing.T) {
 		t.Errorf("expected empty friendship_status, got %q", resp.Users[0].FriendshipStatus)
 	}
 }
+
+func TestSearchUsers_RankingAndPaging(t *testing.T) {
+	sqlDb := setupTestDB(t)
+	authServer := services.NewAuthServer(sqlDb, nil)
+	ids := createTestUsers(t, sqlDb, "caller", "zuki_sama", "zukii", "zuki", "mizuki")
+	ctx := ctxWithUser("caller", ids["caller"])
+
+	t.Run("exact match first", func(t *testing.T) {
+		resp, err := authServer.SearchUsers(ctx, &auth.SearchUsersRequest{Query: "ZUKI"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Users) != 4 {
+			t.Fatalf("expected 4 results, got %d", len(resp.Users))
+		}
+		if resp.Users[0].UserName != "zuki" {
+			t.Errorf("first result: got %q, want zuki", resp.Users[0].UserName)
+		}
+		if resp.NextCursor != "" {
+			t.Errorf("next_cursor: got %q, want empty", resp.NextCursor)
+		}
+	})
+
+	t.Run("fuzzy match", func(t *testing.T) {
+		resp, err := authServer.SearchUsers(ctx, &auth.SearchUsersRequest{Query: "zukki"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Users) == 0 || resp.Users[0].UserName != "zuki" {
+			t.Errorf("expected zuki as the best fuzzy match, got %v", resp.Users)
+		}
+	})
+
+	t.Run("cursor pages cover every result once", func(t *testing.T) {
+		seen := map[string]bool{}
+		cursor := ""
+		for page := 0; page < 10; page++ {
+			resp, err := authServer.SearchUsers(ctx, &auth.SearchUsersRequest{Query: "zuki", Limit: 1, Cursor: cursor})
+			if err != nil {
+				t.Fatalf("page %d: %v", page, err)
+			}
+			for _, u := range resp.Users {
+				if seen[u.UserName] {
+					t.Fatalf("page %d: %q returned twice", page, u.UserName)
+				}
+				seen[u.UserName] = true
+			}
+			if resp.NextCursor == "" {
+				break
+			}
+			cursor = resp.NextCursor
+		}
+		if len(seen) != 4 {
+			t.Errorf("expected 4 distinct results across pages, got %d", len(seen))
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, err := authServer.SearchUsers(ctx, &auth.SearchUsersRequest{Query: "zuki", Cursor: "!!"})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("got %v, want InvalidArgument", got)
+		}
+	})
+}
+
+func TestProfile(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	js := setupTestNats(t)
+	notif := services.NewNotificationServer(sqlDB, js)
+	authServer := services.NewAuthServer(sqlDB, notif)
+	fs := services.NewFriendshipServer(sqlDB, nil)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "mallory")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+	str := func(s string) *string { return &s }
+
+	cases := []struct {
+		name    string
+		req     *auth.UpdateProfileRequest
+		wantErr codes.Code
+	}{
+		{"no fields", &auth.UpdateProfileRequest{}, codes.InvalidArgument},
+		{"display name too long", &auth.UpdateProfileRequest{DisplayName: str(strings.Repeat("a", 101))}, codes.InvalidArgument},
+		{"avatar not a URL", &auth.UpdateProfileRequest{AvatarUrl: str("javascript:alert(1)")}, codes.InvalidArgument},
+		{"expiry without status", &auth.UpdateProfileRequest{Bio: str("hi"), StatusExpiresAt: time.Now().Add(time.Hour).Format(time.RFC3339)}, codes.InvalidArgument},
+		{"expiry in the past", &auth.UpdateProfileRequest{StatusText: str("away"), StatusExpiresAt: "2000-01-01T00:00:00Z"}, codes.InvalidArgument},
+		{"valid", &auth.UpdateProfileRequest{DisplayName: str("Alice A."), AvatarUrl: str("https://example.com/a.png"), Bio: str("hello")}, codes.OK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := authServer.UpdateProfile(alice, tc.req)
+			if got := grpcCode(err); got != tc.wantErr {
+				t.Errorf("got %v, want %v (err: %v)", got, tc.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("partial update keeps other fields and pushes to friends", func(t *testing.T) {
+		bobMsgs := make(chan *nats.Msg, 4)
+		sub, err := js.ChanSubscribe(lib.NotiSubjectPrefix+ids["bob"].String(), bobMsgs)
+		if err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+		defer sub.Unsubscribe()
+
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		p, err := authServer.UpdateProfile(alice, &auth.UpdateProfileRequest{
+			StatusText: str("in a meeting"), StatusExpiresAt: expiresAt.Format(time.RFC3339),
+		})
+		if err != nil {
+			t.Fatalf("UpdateProfile: %v", err)
+		}
+		if p.DisplayName != "Alice A." || p.Bio != "hello" || p.StatusText != "in a meeting" {
+			t.Errorf("got %+v", p)
+		}
+
+		select {
+		case msg := <-bobMsgs:
+			var event lib.ProfileUpdatedEvent
+			if err := json.Unmarshal(msg.Data, &event); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if event.Type != lib.NotificationProfileUpdated || event.UserID != ids["alice"].String() || event.StatusText != "in a meeting" {
+				t.Errorf("event: got %+v", event)
+			}
+			if event.StatusExpiresAt == nil || !event.StatusExpiresAt.Equal(expiresAt) {
+				t.Errorf("status_expires_at: got %v, want %v", event.StatusExpiresAt, expiresAt)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout: expected profile_updated on bob's notification subject")
+		}
+	})
+
+	t.Run("friend reads profile", func(t *testing.T) {
+		p, err := authServer.GetProfile(bob, &auth.GetProfileRequest{UserId: ids["alice"].String()})
+		if err != nil {
+			t.Fatalf("GetProfile: %v", err)
+		}
+		if p.UserName != "alice" || p.AvatarUrl != "https://example.com/a.png" {
+			t.Errorf("got %+v", p)
+		}
+	})
+
+	t.Run("expired status is hidden", func(t *testing.T) {
+		if _, err := sqlDB.Exec(`UPDATE users SET status_expires_at = NOW() - INTERVAL '1 minute' WHERE user_id = $1`, ids["alice"]); err != nil {
+			t.Fatalf("expire status: %v", err)
+		}
+		p, err := authServer.GetProfile(alice, &auth.GetProfileRequest{})
+		if err != nil {
+			t.Fatalf("GetProfile: %v", err)
+		}
+		if p.StatusText != "" || p.StatusExpiresAt != "" {
+			t.Errorf("status: got %q until %q, want empty", p.StatusText, p.StatusExpiresAt)
+		}
+	})
+
+	t.Run("clearing a field", func(t *testing.T) {
+		p, err := authServer.UpdateProfile(alice, &auth.UpdateProfileRequest{Bio: str("")})
+		if err != nil {
+			t.Fatalf("UpdateProfile: %v", err)
+		}
+		if p.Bio != "" || p.DisplayName != "Alice A." {
+			t.Errorf("got %+v", p)
+		}
+	})
+
+	t.Run("lookup errors", func(t *testing.T) {
+		if _, err := fs.BlockUser(alice, &friendship.FriendRequest{TargetUsername: "mallory"}); err != nil {
+			t.Fatalf("BlockUser: %v", err)
+		}
+		mallory := ctxWithUser("mallory", ids["mallory"])
+		for name, tc := range map[string]struct {
+			id   string
+			want codes.Code
+		}{
+			"invalid id":     {"not-a-uuid", codes.InvalidArgument},
+			"unknown user":   {"00000000-0000-0000-0000-000000000001", codes.NotFound},
+			"blocked caller": {ids["alice"].String(), codes.NotFound},
+		} {
+			_, err := authServer.GetProfile(mallory, &auth.GetProfileRequest{UserId: tc.id})
+			if got := grpcCode(err); got != tc.want {
+				t.Errorf("%s: got %v, want %v", name, got, tc.want)
+			}
+		}
+	})
+}
+
+func TestChangeUsername(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	notif := services.NewNotificationServer(sqlDB, nil)
+	authServer := services.NewAuthServer(sqlDB, notif)
+	fs := services.NewFriendshipServer(sqlDB, notif)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+
+	alice := ctxWithUser("alice", ids["alice"])
+	carol := ctxWithUser("carol", ids["carol"])
+
+	cases := []struct {
+		name    string
+		newName string
+		wantErr codes.Code
+	}{
+		{"too short", "al", codes.InvalidArgument},
+		{"bad characters", "alice smith", codes.InvalidArgument},
+		{"same name", "alice", codes.InvalidArgument},
+		{"taken", "bob", codes.AlreadyExists},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := authServer.ChangeUsername(alice, &auth.ChangeUsernameRequest{NewUsername: tc.newName})
+			if got := grpcCode(err); got != tc.wantErr {
+				t.Errorf("got %v, want %v (err: %v)", got, tc.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("rename returns a token for the same login", func(t *testing.T) {
+		resp, err := authServer.ChangeUsername(alice, &auth.ChangeUsernameRequest{NewUsername: "alice2"})
+		if err != nil {
+			t.Fatalf("ChangeUsername: %v", err)
+		}
+		claims, err := lib.ValidateToken(resp.Token)
+		if err != nil {
+			t.Fatalf("ValidateToken: %v", err)
+		}
+		if claims.Username != "alice2" || claims.UserID != ids["alice"].String() || claims.LoginID != lib.CallerLoginID(alice) {
+			t.Errorf("claims: got %+v", claims)
+		}
+	})
+
+	t.Run("old token uses the current name", func(t *testing.T) {
+		if _, err := fs.SendFriendRequest(alice, &friendship.FriendRequest{TargetUsername: "bob"}); err != nil {
+			t.Fatalf("SendFriendRequest: %v", err)
+		}
+		notis, err := db.New(sqlDB).GetNotificationsForUser(t.Context(), ids["bob"])
+		if err != nil || len(notis) != 1 {
+			t.Fatalf("GetNotificationsForUser: got %d, err %v", len(notis), err)
+		}
+		if want := "alice2 sent a friend request"; notis[0].Message != want {
+			t.Errorf("message: got %q, want %q", notis[0].Message, want)
+		}
+	})
+
+	t.Run("cooldown", func(t *testing.T) {
+		_, err := authServer.ChangeUsername(alice, &auth.ChangeUsernameRequest{NewUsername: "alice3"})
+		if got := grpcCode(err); got != codes.FailedPrecondition {
+			t.Errorf("got %v, want FailedPrecondition (err: %v)", got, err)
+		}
+	})
+
+	t.Run("old name is reserved for its owner", func(t *testing.T) {
+		_, err := authServer.ChangeUsername(carol, &auth.ChangeUsernameRequest{NewUsername: "alice"})
+		if got := grpcCode(err); got != codes.AlreadyExists {
+			t.Errorf("rename: got %v, want AlreadyExists (err: %v)", got, err)
+		}
+		_, err = authServer.Signup(t.Context(), &auth.SignupRequest{UserName: "alice", Passwd: "password"})
+		if got := grpcCode(err); got != codes.AlreadyExists {
+			t.Errorf("signup: got %v, want AlreadyExists (err: %v)", got, err)
+		}
+
+		if _, err := sqlDB.Exec(`UPDATE username_history SET changed_at = NOW() - INTERVAL '31 days' WHERE user_id = $1`, ids["alice"]); err != nil {
+			t.Fatalf("backdate history: %v", err)
+		}
+		resp, err := authServer.ChangeUsername(alice, &auth.ChangeUsernameRequest{NewUsername: "alice"})
+		if err != nil {
+			t.Fatalf("owner takes name back: %v", err)
+		}
+		if resp.Username != "alice" {
+			t.Errorf("username: got %q, want alice", resp.Username)
+		}
+	})
+
+	t.Run("concurrent renames respect the cooldown", func(t *testing.T) {
+		if _, err := sqlDB.Exec(`UPDATE username_history SET changed_at = NOW() - INTERVAL '31 days' WHERE user_id = $1`, ids["alice"]); err != nil {
+			t.Fatalf("backdate history: %v", err)
+		}
+		codesSeen := make([]codes.Code, 2)
+		var wg sync.WaitGroup
+		for i, name := range []string{"alice-a", "alice-b"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := authServer.ChangeUsername(alice, &auth.ChangeUsernameRequest{NewUsername: name})
+				codesSeen[i] = grpcCode(err)
+			}()
+		}
+		wg.Wait()
+		ok, cooldown := 0, 0
+		for _, c := range codesSeen {
+			switch c {
+			case codes.OK:
+				ok++
+			case codes.FailedPrecondition:
+				cooldown++
+			}
+		}
+		if ok != 1 || cooldown != 1 {
+			t.Errorf("got %v, want one OK and one FailedPrecondition", codesSeen)
+		}
+	})
+}
+
+func TestPrivacySettings(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	authServer := services.NewAuthServer(sqlDB, nil)
+	fs := services.NewFriendshipServer(sqlDB, nil)
+	// bob is alice's friend, carol a friend of bob's, dave a stranger.
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol", "dave", "erin")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+	makeFriends(t, sqlDB, ids["bob"], ids["carol"])
+
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+	carol := ctxWithUser("carol", ids["carol"])
+	dave := ctxWithUser("dave", ids["dave"])
+	str := func(s string) *string { return &s }
+	update := func(t *testing.T, req *auth.UpdatePrivacySettingsRequest) {
+		t.Helper()
+		if _, err := authServer.UpdatePrivacySettings(alice, req); err != nil {
+			t.Fatalf("UpdatePrivacySettings: %v", err)
+		}
+	}
+	finds := func(t *testing.T, ctx context.Context) bool {
+		t.Helper()
+		resp, err := authServer.SearchUsers(ctx, &auth.SearchUsersRequest{Query: "alice"})
+		if err != nil {
+			t.Fatalf("SearchUsers: %v", err)
+		}
+		return len(resp.Users) == 1
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		got, err := authServer.GetPrivacySettings(alice, &auth.GetPrivacySettingsRequest{})
+		if err != nil {
+			t.Fatalf("GetPrivacySettings: %v", err)
+		}
+		if got.Discoverability != "everyone" || got.FriendRequests != "everyone" || !got.ReadReceipts || got.LastSeen != "everyone" {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		for name, req := range map[string]*auth.UpdatePrivacySettingsRequest{
+			"no fields":                    {},
+			"unknown audience":             {Discoverability: str("strangers")},
+			"friends discoverability":      {Discoverability: str("friends")},
+			"friends of friends last seen": {LastSeen: str("friends_of_friends")},
+		} {
+			_, err := authServer.UpdatePrivacySettings(alice, req)
+			if got := grpcCode(err); got != codes.InvalidArgument {
+				t.Errorf("%s: got %v, want InvalidArgument", name, got)
+			}
+		}
+	})
+
+	t.Run("partial update keeps other settings", func(t *testing.T) {
+		update(t, &auth.UpdatePrivacySettingsRequest{ReadReceipts: proto.Bool(false)})
+		got, err := authServer.UpdatePrivacySettings(alice, &auth.UpdatePrivacySettingsRequest{LastSeen: str("nobody")})
+		if err != nil {
+			t.Fatalf("UpdatePrivacySettings: %v", err)
+		}
+		if got.ReadReceipts || got.LastSeen != "nobody" || got.Discoverability != "everyone" {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("discoverability", func(t *testing.T) {
+		update(t, &auth.UpdatePrivacySettingsRequest{Discoverability: str("friends_of_friends")})
+		if !finds(t, bob) || !finds(t, carol) {
+			t.Error("friends and friends of friends should find alice")
+		}
+		if finds(t, dave) {
+			t.Error("stranger should not find alice")
+		}
+
+		resp, err := fs.SuggestFriends(carol, &friendship.SuggestFriendsRequest{})
+		if err != nil {
+			t.Fatalf("SuggestFriends: %v", err)
+		}
+		if len(resp.Suggestions) != 1 || resp.Suggestions[0].Username != "alice" {
+			t.Errorf("suggestions for carol: got %+v, want alice", resp.Suggestions)
+		}
+
+		update(t, &auth.UpdatePrivacySettingsRequest{Discoverability: str("nobody")})
+		if finds(t, bob) {
+			t.Error("nobody should find alice")
+		}
+		resp, err = fs.SuggestFriends(carol, &friendship.SuggestFriendsRequest{})
+		if err != nil {
+			t.Fatalf("SuggestFriends: %v", err)
+		}
+		if len(resp.Suggestions) != 0 {
+			t.Errorf("suggestions for carol: got %+v, want none", resp.Suggestions)
+		}
+	})
+
+	t.Run("friend requests", func(t *testing.T) {
+		update(t, &auth.UpdatePrivacySettingsRequest{FriendRequests: str("friends_of_friends")})
+		_, err := fs.SendFriendRequest(dave, &friendship.FriendRequest{TargetUsername: "alice"})
+		if got := grpcCode(err); got != codes.PermissionDenied {
+			t.Errorf("stranger: got %v, want PermissionDenied (err: %v)", got, err)
+		}
+		if _, err := fs.SendFriendRequest(carol, &friendship.FriendRequest{TargetUsername: "alice"}); err != nil {
+			t.Errorf("friend of a friend: %v", err)
+		}
+
+		update(t, &auth.UpdatePrivacySettingsRequest{FriendRequests: str("nobody")})
+		_, err = fs.SendFriendRequest(ctxWithUser("erin", ids["erin"]), &friendship.FriendRequest{TargetUsername: "alice"})
+		if got := grpcCode(err); got != codes.PermissionDenied {
+			t.Errorf("nobody: got %v, want PermissionDenied (err: %v)", got, err)
+		}
+		// Requests the other way are not affected.
+		if _, err := fs.SendFriendRequest(alice, &friendship.FriendRequest{TargetUsername: "dave"}); err != nil {
+			t.Errorf("outgoing request: %v", err)
+		}
+	})
+
+	t.Run("last seen", func(t *testing.T) {
+		if _, err := sqlDB.Exec(`UPDATE users SET last_seen_at = NOW() WHERE user_id = $1`, ids["alice"]); err != nil {
+			t.Fatalf("set last seen: %v", err)
+		}
+		update(t, &auth.UpdatePrivacySettingsRequest{LastSeen: str("friends")})
+		for name, tc := range map[string]struct {
+			ctx  context.Context
+			want bool
+		}{
+			"self":               {alice, true},
+			"friend":             {bob, true},
+			"friend of a friend": {carol, false},
+		} {
+			p, err := authServer.GetProfile(tc.ctx, &auth.GetProfileRequest{UserId: ids["alice"].String()})
+			if err != nil {
+				t.Fatalf("%s: GetProfile: %v", name, err)
+			}
+			if got := p.LastSeenAt != ""; got != tc.want {
+				t.Errorf("%s: last_seen_at %q, want visible=%v", name, p.LastSeenAt, tc.want)
+			}
+		}
+	})
+}
+
+func TestRefreshToken(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	authServer := services.NewAuthServer(sqlDB, nil)
+	createTestUsers(t, sqlDB, "alice")
+	ctx := context.Background()
+
+	login, err := authServer.Login(ctx, &auth.LoginRequest{UserName: "alice", Passwd: "password"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if login.RefreshToken == "" {
+		t.Fatal("Login returned no refresh token")
+	}
+	loginClaims, err := lib.ValidateToken(login.Token)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+
+	refresh := func(token string) (*auth.RefreshTokenResponse, error) {
+		return authServer.RefreshToken(ctx, &auth.RefreshTokenRequest{RefreshToken: token})
+	}
+
+	t.Run("unknown token", func(t *testing.T) {
+		_, err := refresh("not-a-token")
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("got %v, want Unauthenticated", got)
+		}
+	})
+
+	var rotated string
+	t.Run("rotation keeps the login", func(t *testing.T) {
+		resp, err := refresh(login.RefreshToken)
+		if err != nil {
+			t.Fatalf("RefreshToken: %v", err)
+		}
+		if resp.RefreshToken == "" || resp.RefreshToken == login.RefreshToken {
+			t.Errorf("refresh token was not rotated")
+		}
+		claims, err := lib.ValidateToken(resp.Token)
+		if err != nil {
+			t.Fatalf("ValidateToken: %v", err)
+		}
+		if claims.LoginID != loginClaims.LoginID || claims.Username != "alice" {
+			t.Errorf("claims: got login %q user %q, want login %q user alice", claims.LoginID, claims.Username, loginClaims.LoginID)
+		}
+		rotated = resp.RefreshToken
+	})
+
+	t.Run("reuse revokes the login", func(t *testing.T) {
+		_, err := refresh(login.RefreshToken)
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Fatalf("reuse: got %v, want Unauthenticated", got)
+		}
+		_, err = refresh(rotated)
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("successor after reuse: got %v, want Unauthenticated", got)
+		}
+	})
+
+	t.Run("other logins are untouched", func(t *testing.T) {
+		other, err := authServer.Login(ctx, &auth.LoginRequest{UserName: "alice", Passwd: "password"})
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		if _, err := refresh(other.RefreshToken); err != nil {
+			t.Errorf("RefreshToken: %v", err)
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		short := services.NewAuthServer(sqlDB, nil)
+		short.SetRefreshTokenTTL(-time.Second)
+		resp, err := short.Login(ctx, &auth.LoginRequest{UserName: "alice", Passwd: "password"})
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		_, err = refresh(resp.RefreshToken)
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("got %v, want Unauthenticated", got)
+		}
+	})
+}
+
+// recordingNats records the consumers deleted and plain messages published
+// through it.
+type recordingNats struct {
+	deleted   []string
+	published []string
+}
+
+func (r *recordingNats) DeleteConsumer(stream, consumer string, opts ...nats.JSOpt) error {
+	r.deleted = append(r.deleted, stream+"/"+consumer)
+	return nil
+}
+
+func (r *recordingNats) Publish(subj string, data []byte) error {
+	r.published = append(r.published, subj)
+	return nil
+}
+
+func TestLogout(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	recorder := &recordingNats{}
+	sessions := services.NewLoginSessions(sqlDB, time.Minute, recorder, recorder)
+	authServer := services.NewAuthServer(sqlDB, nil)
+	authServer.SetLoginSessions(sessions)
+	ids := createTestUsers(t, sqlDB, "alice")
+	ctx := context.Background()
+
+	login := func() (*auth.LoginResponse, *lib.Claims) {
+		t.Helper()
+		resp, err := authServer.Login(ctx, &auth.LoginRequest{UserName: "alice", Passwd: "password"})
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		claims, err := lib.ValidateToken(resp.Token)
+		if err != nil {
+			t.Fatalf("ValidateToken: %v", err)
+		}
+		return resp, claims
+	}
+	phone, phoneClaims := login()
+	_, laptopClaims := login()
+
+	// Cache the phone login as active first; Logout must clear it.
+	if err := sessions.Check(ctx, phoneClaims.LoginID); err != nil {
+		t.Fatalf("Check before logout: %v", err)
+	}
+
+	callerCtx := context.WithValue(ctx, lib.ContextKeyUserID, ids["alice"].String())
+	callerCtx = context.WithValue(callerCtx, lib.ContextKeyLoginID, phoneClaims.LoginID)
+	if _, err := authServer.Logout(callerCtx, &auth.LogoutRequest{}); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+
+	t.Run("token is rejected", func(t *testing.T) {
+		if got := grpcCode(sessions.Check(ctx, phoneClaims.LoginID)); got != codes.Unauthenticated {
+			t.Errorf("got %v, want Unauthenticated", got)
+		}
+	})
+
+	t.Run("refresh token is revoked", func(t *testing.T) {
+		_, err := authServer.RefreshToken(ctx, &auth.RefreshTokenRequest{RefreshToken: phone.RefreshToken})
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("got %v, want Unauthenticated", got)
+		}
+	})
+
+	t.Run("live delivery is torn down", func(t *testing.T) {
+		wantDeleted := []string{"SESSIONS/chat-" + phoneClaims.LoginID, "SESSIONS/noti-" + phoneClaims.LoginID}
+		if len(recorder.deleted) != 2 || recorder.deleted[0] != wantDeleted[0] || recorder.deleted[1] != wantDeleted[1] {
+			t.Errorf("deleted consumers: got %v, want %v", recorder.deleted, wantDeleted)
+		}
+		if len(recorder.published) != 1 || recorder.published[0] != lib.LoginRevokedSubjectPrefix+phoneClaims.LoginID {
+			t.Errorf("published: got %v, want the revocation of %s", recorder.published, phoneClaims.LoginID)
+		}
+	})
+
+	t.Run("other logins stay signed in", func(t *testing.T) {
+		if err := sessions.Check(ctx, laptopClaims.LoginID); err != nil {
+			t.Errorf("Check: %v", err)
+		}
+	})
+
+	t.Run("login from before the sessions table", func(t *testing.T) {
+		legacy := uuid.NewString()
+		if err := sessions.Check(ctx, legacy); err != nil {
+			t.Fatalf("Check before logout: %v", err)
+		}
+		legacyCtx := context.WithValue(callerCtx, lib.ContextKeyLoginID, legacy)
+		if _, err := authServer.Logout(legacyCtx, &auth.LogoutRequest{}); err != nil {
+			t.Fatalf("Logout: %v", err)
+		}
+		if got := grpcCode(sessions.Check(ctx, legacy)); got != codes.Unauthenticated {
+			t.Errorf("got %v, want Unauthenticated", got)
+		}
+	})
+
+	t.Run("login from before the sessions table after the cutoff", func(t *testing.T) {
+		if _, err := sqlDB.Exec(`UPDATE legacy_login_cutoff SET accept_until = NOW() - INTERVAL '1 minute'`); err != nil {
+			t.Fatalf("move cutoff: %v", err)
+		}
+		if got := grpcCode(sessions.Check(ctx, uuid.NewString())); got != codes.Unauthenticated {
+			t.Errorf("got %v, want Unauthenticated", got)
+		}
+		if err := sessions.Check(ctx, laptopClaims.LoginID); err != nil {
+			t.Errorf("recorded login: %v", err)
+		}
+	})
+}
+
+func TestSessions(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	sessions := services.NewLoginSessions(sqlDB, time.Minute, nil, nil)
+	authServer := services.NewAuthServer(sqlDB, services.NewNotificationServer(sqlDB, nil))
+	authServer.SetLoginSessions(sessions)
+	ids := createTestUsers(t, sqlDB, "alice", "bob")
+	q := db.New(sqlDB)
+
+	// login signs alice in from the given device and returns a caller context
+	// for that login.
+	login := func(device, userAgent, ip string) (context.Context, string) {
+		t.Helper()
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-client-user-agent", userAgent, "x-client-ip", ip))
+		resp, err := authServer.Login(ctx, &auth.LoginRequest{UserName: "alice", Passwd: "password", DeviceName: device})
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		claims, err := lib.ValidateToken(resp.Token)
+		if err != nil {
+			t.Fatalf("ValidateToken: %v", err)
+		}
+		caller := context.WithValue(context.Background(), lib.ContextKeyUserID, claims.UserID)
+		caller = context.WithValue(caller, lib.ContextKeyLoginID, claims.LoginID)
+		return caller, claims.LoginID
+	}
+	newLoginNotifications := func() int {
+		t.Helper()
+		notis, err := q.GetNotificationsForUser(context.Background(), ids["alice"])
+		if err != nil {
+			t.Fatalf("GetNotificationsForUser: %v", err)
+		}
+		n := 0
+		for _, noti := range notis {
+			if noti.Type == db.NotificationTypeNewLogin {
+				n++
+			}
+		}
+		return n
+	}
+
+	_, phoneID := login("Phone", "ChatApp/1.0 (iOS)", "203.0.113.7")
+	if got := newLoginNotifications(); got != 0 {
+		t.Errorf("first login: got %d new_login notifications, want 0", got)
+	}
+	laptopCtx, laptopID := login("Laptop", "Mozilla/5.0", "198.51.100.2")
+	if got := newLoginNotifications(); got != 1 {
+		t.Errorf("second login: got %d new_login notifications, want 1", got)
+	}
+	_, tabletID := login("", "Tablet/2.0", "198.51.100.3")
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := authServer.ListSessions(laptopCtx, &auth.ListSessionsRequest{})
+		if err != nil {
+			t.Fatalf("ListSessions: %v", err)
+		}
+		if len(resp.Sessions) != 3 {
+			t.Fatalf("got %d sessions, want 3", len(resp.Sessions))
+		}
+		for _, sess := range resp.Sessions {
+			if sess.Current != (sess.LoginId == laptopID) {
+				t.Errorf("session %s: current = %v", sess.LoginId, sess.Current)
+			}
+			if sess.LoginId == phoneID && (sess.DeviceName != "Phone" || sess.UserAgent != "ChatApp/1.0 (iOS)" || sess.IpAddress != "203.0.113.7") {
+				t.Errorf("phone session: got %+v", sess)
+			}
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		bob := ctxWithUser("bob", ids["bob"])
+		cases := []struct {
+			name    string
+			ctx     context.Context
+			req     *auth.RevokeSessionRequest
+			wantErr codes.Code
+		}{
+			{"no login id", laptopCtx, &auth.RevokeSessionRequest{}, codes.InvalidArgument},
+			{"both", laptopCtx, &auth.RevokeSessionRequest{LoginId: phoneID, AllOthers: true}, codes.InvalidArgument},
+			{"unknown login", laptopCtx, &auth.RevokeSessionRequest{LoginId: uuid.NewString()}, codes.NotFound},
+			{"someone else's login", bob, &auth.RevokeSessionRequest{LoginId: phoneID}, codes.NotFound},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := authServer.RevokeSession(tc.ctx, tc.req)
+				if got := grpcCode(err); got != tc.wantErr {
+					t.Errorf("got %v, want %v (err: %v)", got, tc.wantErr, err)
+				}
+			})
+		}
+	})
+
+	t.Run("revoke a lost device", func(t *testing.T) {
+		resp, err := authServer.RevokeSession(laptopCtx, &auth.RevokeSessionRequest{LoginId: phoneID})
+		if err != nil {
+			t.Fatalf("RevokeSession: %v", err)
+		}
+		if resp.RevokedCount != 1 {
+			t.Errorf("revoked %d, want 1", resp.RevokedCount)
+		}
+		if got := grpcCode(sessions.Check(context.Background(), phoneID)); got != codes.Unauthenticated {
+			t.Errorf("phone: got %v, want Unauthenticated", got)
+		}
+		_, err = authServer.RevokeSession(laptopCtx, &auth.RevokeSessionRequest{LoginId: phoneID})
+		if got := grpcCode(err); got != codes.NotFound {
+			t.Errorf("second revoke: got %v, want NotFound", got)
+		}
+	})
+
+	t.Run("sign out everywhere else", func(t *testing.T) {
+		resp, err := authServer.RevokeSession(laptopCtx, &auth.RevokeSessionRequest{AllOthers: true})
+		if err != nil {
+			t.Fatalf("RevokeSession: %v", err)
+		}
+		if resp.RevokedCount != 1 {
+			t.Errorf("revoked %d, want 1 (the tablet)", resp.RevokedCount)
+		}
+		if got := grpcCode(sessions.Check(context.Background(), tabletID)); got != codes.Unauthenticated {
+			t.Errorf("tablet: got %v, want Unauthenticated", got)
+		}
+		if err := sessions.Check(context.Background(), laptopID); err != nil {
+			t.Errorf("laptop: %v", err)
+		}
+
+		list, err := authServer.ListSessions(laptopCtx, &auth.ListSessionsRequest{})
+		if err != nil {
+			t.Fatalf("ListSessions: %v", err)
+		}
+		if len(list.Sessions) != 1 || !list.Sessions[0].Current {
+			t.Errorf("sessions: got %+v, want only the current one", list.Sessions)
+		}
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	sessions := services.NewLoginSessions(sqlDB, time.Minute, nil, nil)
+	authServer := services.NewAuthServer(sqlDB, nil)
+	authServer.SetLoginSessions(sessions)
+	createTestUsers(t, sqlDB, "alice")
+
+	login := func(passwd string) (context.Context, string) {
+		t.Helper()
+		resp, err := authServer.Login(context.Background(), &auth.LoginRequest{UserName: "alice", Passwd: passwd})
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		claims, err := lib.ValidateToken(resp.Token)
+		if err != nil {
+			t.Fatalf("ValidateToken: %v", err)
+		}
+		caller := context.WithValue(context.Background(), lib.ContextKeyUserID, claims.UserID)
+		caller = context.WithValue(caller, lib.ContextKeyLoginID, claims.LoginID)
+		return caller, claims.LoginID
+	}
+	_, phoneID := login("password")
+	laptopCtx, laptopID := login("password")
+
+	t.Run("validation", func(t *testing.T) {
+		cases := []struct {
+			name    string
+			req     *auth.ChangePasswordRequest
+			wantErr codes.Code
+		}{
+			{"no current password", &auth.ChangePasswordRequest{NewPassword: "new"}, codes.InvalidArgument},
+			{"no new password", &auth.ChangePasswordRequest{CurrentPassword: "password"}, codes.InvalidArgument},
+			{"wrong current password", &auth.ChangePasswordRequest{CurrentPassword: "guess", NewPassword: "new"}, codes.PermissionDenied},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := authServer.ChangePassword(laptopCtx, tc.req)
+				if got := grpcCode(err); got != tc.wantErr {
+					t.Errorf("got %v, want %v (err: %v)", got, tc.wantErr, err)
+				}
+			})
+		}
+	})
+
+	t.Run("change", func(t *testing.T) {
+		resp, err := authServer.ChangePassword(laptopCtx, &auth.ChangePasswordRequest{CurrentPassword: "password", NewPassword: "n3w-passw0rd"})
+		if err != nil {
+			t.Fatalf("ChangePassword: %v", err)
+		}
+		if resp.RevokedCount != 1 {
+			t.Errorf("revoked %d, want 1 (the phone)", resp.RevokedCount)
+		}
+		if got := grpcCode(sessions.Check(context.Background(), phoneID)); got != codes.Unauthenticated {
+			t.Errorf("phone: got %v, want Unauthenticated", got)
+		}
+		if err := sessions.Check(context.Background(), laptopID); err != nil {
+			t.Errorf("laptop: %v", err)
+		}
+
+		_, err = authServer.Login(context.Background(), &auth.LoginRequest{UserName: "alice", Passwd: "password"})
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("old password: got %v, want Unauthenticated", got)
+		}
+		login("n3w-passw0rd")
+	})
+}
+
+// recordingMailer records the messages sent through it.
+type recordingMailer struct {
+	sent []mailer.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// resetTokenRe finds the token in a reset link.
+var resetTokenRe = regexp.MustCompile(`[?&]token=([^\s&]+)`)
+
+func TestPasswordReset(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	sessions := services.NewLoginSessions(sqlDB, time.Minute, nil, nil)
+	authServer := services.NewAuthServer(sqlDB, nil)
+	authServer.SetLoginSessions(sessions)
+	outbox := &recordingMailer{}
+	resetCfg := services.PasswordReset{Mailer: outbox, LinkURL: "https://chat.example.com/reset-password", TTL: time.Hour, PerHour: 3}
+	authServer.SetPasswordReset(resetCfg)
+	worker := services.NewPasswordResetWorker(sqlDB, resetCfg)
+
+	if _, err := authServer.Signup(context.Background(), &auth.SignupRequest{UserName: "alice", Passwd: "password", Email: "Alice@Example.com"}); err != nil {
+		t.Fatalf("Signup: %v", err)
+	}
+	ids := createTestUsers(t, sqlDB, "bob")
+	bob := ctxWithUser("bob", ids["bob"])
+
+	// requestReset asks for a reset link for email and returns the token
+	// mailed for it, or "" if nothing was sent.
+	requestReset := func(email string) string {
+		t.Helper()
+		before := len(outbox.sent)
+		if _, err := authServer.RequestPasswordReset(context.Background(), &auth.RequestPasswordResetRequest{Email: email}); err != nil {
+			t.Fatalf("RequestPasswordReset: %v", err)
+		}
+		if len(outbox.sent) != before {
+			t.Fatal("mail sent before the worker ran")
+		}
+		if ok, err := worker.ProcessNext(context.Background()); !ok || err != nil {
+			t.Fatalf("ProcessNext: ok=%v err=%v", ok, err)
+		}
+		if len(outbox.sent) == before {
+			return ""
+		}
+		msg := outbox.sent[len(outbox.sent)-1]
+		match := resetTokenRe.FindStringSubmatch(msg.Body)
+		if match == nil {
+			t.Fatalf("no reset link in %q", msg.Body)
+		}
+		token, err := url.QueryUnescape(match[1])
+		if err != nil {
+			t.Fatalf("QueryUnescape: %v", err)
+		}
+		return token
+	}
+
+	t.Run("recovery email", func(t *testing.T) {
+		cases := []struct {
+			name    string
+			req     *auth.SetRecoveryEmailRequest
+			wantErr codes.Code
+		}{
+			{"invalid email", &auth.SetRecoveryEmailRequest{Email: "Bob <bob@example.com>", Password: "password"}, codes.InvalidArgument},
+			{"no password", &auth.SetRecoveryEmailRequest{Email: "bob@example.com"}, codes.InvalidArgument},
+			{"wrong password", &auth.SetRecoveryEmailRequest{Email: "bob@example.com", Password: "guess"}, codes.PermissionDenied},
+			{"taken is not disclosed", &auth.SetRecoveryEmailRequest{Email: "alice@example.com", Password: "password"}, codes.OK},
+			{"ok", &auth.SetRecoveryEmailRequest{Email: "bob@example.com", Password: "password"}, codes.OK},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := authServer.SetRecoveryEmail(bob, tc.req)
+				if got := grpcCode(err); got != tc.wantErr {
+					t.Errorf("got %v, want %v (err: %v)", got, tc.wantErr, err)
+				}
+			})
+		}
+
+		// Neither is it at signup. The reset below still reaches alice.
+		if _, err := authServer.Signup(context.Background(), &auth.SignupRequest{UserName: "carol", Passwd: "password", Email: "ALICE@example.com"}); err != nil {
+			t.Errorf("Signup with a taken email: %v", err)
+		}
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		if token := requestReset("nobody@example.com"); token != "" {
+			t.Errorf("mail sent for an unknown address")
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		loginResp, err := authServer.Login(context.Background(), &auth.LoginRequest{UserName: "alice", Passwd: "password"})
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		claims, err := lib.ValidateToken(loginResp.Token)
+		if err != nil {
+			t.Fatalf("ValidateToken: %v", err)
+		}
+
+		stale := requestReset("alice@example.com")
+		token := requestReset("ALICE@example.com")
+		if token == "" || stale == "" {
+			t.Fatal("no reset mail sent")
+		}
+		if to := outbox.sent[len(outbox.sent)-1].To; to != "Alice@Example.com" {
+			t.Errorf("mail sent to %q, want the stored address", to)
+		}
+
+		_, err = authServer.ResetPassword(context.Background(), &auth.ResetPasswordRequest{Token: stale, NewPassword: "n3w-passw0rd"})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("superseded token: got %v, want InvalidArgument", got)
+		}
+		if _, err := authServer.ResetPassword(context.Background(), &auth.ResetPasswordRequest{Token: token, NewPassword: "n3w-passw0rd"}); err != nil {
+			t.Fatalf("ResetPassword: %v", err)
+		}
+		_, err = authServer.ResetPassword(context.Background(), &auth.ResetPasswordRequest{Token: token, NewPassword: "again"})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("reused token: got %v, want InvalidArgument", got)
+		}
+
+		if got := grpcCode(sessions.Check(context.Background(), claims.LoginID)); got != codes.Unauthenticated {
+			t.Errorf("existing login: got %v, want Unauthenticated", got)
+		}
+		if _, err := authServer.Login(context.Background(), &auth.LoginRequest{UserName: "alice", Passwd: "n3w-passw0rd"}); err != nil {
+			t.Errorf("Login with new password: %v", err)
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		token := requestReset("bob@example.com")
+		if _, err := sqlDB.Exec(`UPDATE password_reset_tokens SET expires_at = NOW() - INTERVAL '1 minute' WHERE user_id = $1`, ids["bob"]); err != nil {
+			t.Fatalf("expire token: %v", err)
+		}
+		_, err := authServer.ResetPassword(context.Background(), &auth.ResetPasswordRequest{Token: token, NewPassword: "n3w-passw0rd"})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("got %v, want InvalidArgument", got)
+		}
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			requestReset("flood@example.com")
+		}
+		for _, email := range []string{"flood@example.com", "FLOOD@example.com"} {
+			_, err := authServer.RequestPasswordReset(context.Background(), &auth.RequestPasswordResetRequest{Email: email})
+			if got := grpcCode(err); got != codes.ResourceExhausted {
+				t.Errorf("%s: got %v, want ResourceExhausted", email, got)
+			}
+		}
+		if ok, err := worker.ProcessNext(context.Background()); ok || err != nil {
+			t.Errorf("refused request was queued: ok=%v err=%v", ok, err)
+		}
+		if _, err := authServer.RequestPasswordReset(context.Background(), &auth.RequestPasswordResetRequest{Email: "other@example.com"}); err != nil {
+			t.Errorf("another address: %v", err)
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		_, err := services.NewAuthServer(sqlDB, nil).RequestPasswordReset(context.Background(), &auth.RequestPasswordResetRequest{Email: "alice@example.com"})
+		if got := grpcCode(err); got != codes.FailedPrecondition {
+			t.Errorf("got %v, want FailedPrecondition", got)
+		}
+	})
+}
+
+func TestTwoFactor(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	authServer := services.NewAuthServer(sqlDB, nil)
+	ids := createTestUsers(t, sqlDB, "alice", "bob")
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+
+	code := func(secret string, step int64) string {
+		t.Helper()
+		c, err := totp.Code(secret, step)
+		if err != nil {
+			t.Fatalf("totp.Code: %v", err)
+		}
+		return c
+	}
+	login := func(username string) *auth.LoginResponse {
+		t.Helper()
+		resp, err := authServer.Login(context.Background(), &auth.LoginRequest{UserName: username, Passwd: "password", DeviceName: "Phone"})
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		return resp
+	}
+	verify := func(challenge, code string) (*auth.VerifySecondFactorResponse, error) {
+		return authServer.VerifySecondFactor(context.Background(), &auth.VerifySecondFactorRequest{ChallengeToken: challenge, Code: code})
+	}
+	// enable turns two-factor sign-in on for ctx's user and returns the
+	// secret, the time step of the code used and the recovery codes.
+	enable := func(ctx context.Context) (string, int64, []string) {
+		t.Helper()
+		enrolled, err := authServer.EnrollTOTP(ctx, &auth.EnrollTOTPRequest{})
+		if err != nil {
+			t.Fatalf("EnrollTOTP: %v", err)
+		}
+		step := totp.Step(time.Now())
+		confirmed, err := authServer.ConfirmTOTP(ctx, &auth.ConfirmTOTPRequest{Code: code(enrolled.Secret, step)})
+		if err != nil {
+			t.Fatalf("ConfirmTOTP: %v", err)
+		}
+		return enrolled.Secret, step, confirmed.RecoveryCodes
+	}
+
+	var secret string
+	var step int64
+	var recoveryCodes []string
+
+	t.Run("enroll", func(t *testing.T) {
+		first, err := authServer.EnrollTOTP(alice, &auth.EnrollTOTPRequest{})
+		if err != nil {
+			t.Fatalf("EnrollTOTP: %v", err)
+		}
+		if !strings.HasPrefix(first.OtpauthUri, "otpauth://totp/Chat:alice?") {
+			t.Errorf("otpauth_uri: got %q", first.OtpauthUri)
+		}
+		if resp := login("alice"); resp.SecondFactorRequired || resp.Token == "" {
+			t.Errorf("login before confirming: got %+v, want tokens", resp)
+		}
+
+		_, err = authServer.ConfirmTOTP(alice, &auth.ConfirmTOTPRequest{Code: "000000"})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("wrong code: got %v, want InvalidArgument", got)
+		}
+
+		secret, step, recoveryCodes = enable(alice)
+		if secret == first.Secret {
+			t.Error("enrolling again did not replace the secret")
+		}
+		if len(recoveryCodes) != 10 {
+			t.Errorf("got %d recovery codes, want 10", len(recoveryCodes))
+		}
+
+		_, err = authServer.EnrollTOTP(alice, &auth.EnrollTOTPRequest{})
+		if got := grpcCode(err); got != codes.FailedPrecondition {
+			t.Errorf("enroll while on: got %v, want FailedPrecondition", got)
+		}
+	})
+
+	t.Run("login with a code", func(t *testing.T) {
+		resp := login("alice")
+		if !resp.SecondFactorRequired || resp.ChallengeToken == "" || resp.Token != "" || resp.RefreshToken != "" {
+			t.Fatalf("got %+v, want only a challenge", resp)
+		}
+
+		_, err := verify(resp.ChallengeToken, code(secret, step))
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("replayed code: got %v, want Unauthenticated", got)
+		}
+		verified, err := verify(resp.ChallengeToken, code(secret, step+1))
+		if err != nil {
+			t.Fatalf("VerifySecondFactor: %v", err)
+		}
+		if verified.Username != "alice" || verified.Token == "" || verified.RefreshToken == "" {
+			t.Errorf("got %+v", verified)
+		}
+
+		_, err = verify(resp.ChallengeToken, code(secret, step+1))
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("spent challenge: got %v, want Unauthenticated", got)
+		}
+	})
+
+	t.Run("login with a recovery code", func(t *testing.T) {
+		recovery := strings.ToUpper(recoveryCodes[0])
+		if _, err := verify(login("alice").ChallengeToken, recovery); err != nil {
+			t.Fatalf("VerifySecondFactor: %v", err)
+		}
+		_, err := verify(login("alice").ChallengeToken, recovery)
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("reused recovery code: got %v, want Unauthenticated", got)
+		}
+	})
+
+	t.Run("github sign-in", func(t *testing.T) {
+		resp, err := authServer.ExchangeToken(alice, &auth.ExchangeTokenRequest{})
+		if err != nil {
+			t.Fatalf("ExchangeToken: %v", err)
+		}
+		if !resp.SecondFactorRequired || resp.ChallengeToken == "" || resp.Token != "" {
+			t.Errorf("got %+v, want only a challenge", resp)
+		}
+	})
+
+	t.Run("lockout", func(t *testing.T) {
+		bobSecret, bobStep, _ := enable(bob)
+		challenge := login("bob").ChallengeToken
+		for i := range 5 {
+			_, err := verify(challenge, "000000")
+			if got := grpcCode(err); got != codes.Unauthenticated {
+				t.Fatalf("wrong code %d: got %v, want Unauthenticated", i+1, got)
+			}
+		}
+		_, err := verify(challenge, code(bobSecret, bobStep+1))
+		if got := grpcCode(err); got != codes.ResourceExhausted {
+			t.Errorf("right code while locked: got %v, want ResourceExhausted", got)
+		}
+		_, err = verify(login("bob").ChallengeToken, code(bobSecret, bobStep+1))
+		if got := grpcCode(err); got != codes.ResourceExhausted {
+			t.Errorf("new challenge while locked: got %v, want ResourceExhausted", got)
+		}
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		_, err := authServer.DisableTOTP(alice, &auth.DisableTOTPRequest{Code: "000000"})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("wrong code: got %v, want InvalidArgument", got)
+		}
+		if _, err := authServer.DisableTOTP(alice, &auth.DisableTOTPRequest{Code: recoveryCodes[1]}); err != nil {
+			t.Fatalf("DisableTOTP: %v", err)
+		}
+		if resp := login("alice"); resp.SecondFactorRequired || resp.Token == "" {
+			t.Errorf("login after disabling: got %+v, want tokens", resp)
+		}
+		_, err = authServer.DisableTOTP(alice, &auth.DisableTOTPRequest{Code: recoveryCodes[2]})
+		if got := grpcCode(err); got != codes.FailedPrecondition {
+			t.Errorf("disable while off: got %v, want FailedPrecondition", got)
+		}
+	})
+}
+
+func TestPasskeys(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	authServer := services.NewAuthServer(sqlDB, nil)
+	authServer.SetWebAuthn(&webauthn.RelyingParty{ID: "localhost", Name: "Chat", Origins: []string{"http://localhost:5173"}})
+	ids := createTestUsers(t, sqlDB, "alice")
+	alice := ctxWithUser("alice", ids["alice"])
+
+	// octocat signed up with GitHub and has no password.
+	octocatUser, err := db.New(sqlDB).CreateUser(t.Context(), db.CreateUserParams{
+		UserName:   "octocat",
+		SignupType: db.SignupTypeGithub,
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	octocat := ctxWithUser("octocat", octocatUser.UserID)
+
+	laptop := webauthntest.New("http://localhost:5173")
+	phone := webauthntest.New("http://localhost:5173")
+
+	// register adds a passkey on authenticator for ctx's user.
+	register := func(ctx context.Context, authenticator *webauthntest.Authenticator, name string) *auth.Passkey {
+		t.Helper()
+		begun, err := authServer.BeginPasskeyRegistration(ctx, &auth.BeginPasskeyRegistrationRequest{})
+		if err != nil {
+			t.Fatalf("BeginPasskeyRegistration: %v", err)
+		}
+		credential, err := authenticator.Register([]byte(begun.OptionsJson))
+		if err != nil {
+			t.Fatalf("authenticator Register: %v", err)
+		}
+		finished, err := authServer.FinishPasskeyRegistration(ctx, &auth.FinishPasskeyRegistrationRequest{CredentialJson: string(credential), Name: name})
+		if err != nil {
+			t.Fatalf("FinishPasskeyRegistration: %v", err)
+		}
+		return finished.Passkey
+	}
+	// passkeyLogin answers a passwordless sign-in ceremony with authenticator.
+	passkeyLogin := func(authenticator *webauthntest.Authenticator) string {
+		t.Helper()
+		begun, err := authServer.BeginPasskeyLogin(context.Background(), &auth.BeginPasskeyLoginRequest{})
+		if err != nil {
+			t.Fatalf("BeginPasskeyLogin: %v", err)
+		}
+		credential, err := authenticator.Login([]byte(begun.OptionsJson))
+		if err != nil {
+			t.Fatalf("authenticator Login: %v", err)
+		}
+		return string(credential)
+	}
+	finishLogin := func(credential string) (*auth.FinishPasskeyLoginResponse, error) {
+		return authServer.FinishPasskeyLogin(context.Background(), &auth.FinishPasskeyLoginRequest{CredentialJson: credential, DeviceName: "Laptop"})
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		_, err := services.NewAuthServer(sqlDB, nil).BeginPasskeyLogin(context.Background(), &auth.BeginPasskeyLoginRequest{})
+		if got := grpcCode(err); got != codes.FailedPrecondition {
+			t.Errorf("got %v, want FailedPrecondition", got)
+		}
+	})
+
+	var laptopKey *auth.Passkey
+
+	t.Run("register", func(t *testing.T) {
+		laptopKey = register(octocat, laptop, "Laptop")
+		register(octocat, phone, "Phone")
+
+		listed, err := authServer.ListPasskeys(octocat, &auth.ListPasskeysRequest{})
+		if err != nil {
+			t.Fatalf("ListPasskeys: %v", err)
+		}
+		if len(listed.Passkeys) != 2 || listed.Passkeys[0].Name != "Laptop" || listed.Passkeys[1].Name != "Phone" {
+			t.Fatalf("got %+v, want Laptop and Phone", listed.Passkeys)
+		}
+		if listed.Passkeys[0].LastUsedAt != "" {
+			t.Errorf("unused passkey has last_used_at %q", listed.Passkeys[0].LastUsedAt)
+		}
+
+		// The laptop's passkey is excluded from a second registration.
+		begun, err := authServer.BeginPasskeyRegistration(octocat, &auth.BeginPasskeyRegistrationRequest{})
+		if err != nil {
+			t.Fatalf("BeginPasskeyRegistration: %v", err)
+		}
+		if _, err := laptop.Register([]byte(begun.OptionsJson)); err == nil {
+			t.Error("authenticator registered twice")
+		}
+
+		// A registration answers one ceremony only.
+		credential, err := webauthntest.New("http://localhost:5173").Register([]byte(begun.OptionsJson))
+		if err != nil {
+			t.Fatalf("authenticator Register: %v", err)
+		}
+		if _, err := authServer.FinishPasskeyRegistration(octocat, &auth.FinishPasskeyRegistrationRequest{CredentialJson: string(credential)}); err != nil {
+			t.Fatalf("FinishPasskeyRegistration: %v", err)
+		}
+		_, err = authServer.FinishPasskeyRegistration(octocat, &auth.FinishPasskeyRegistrationRequest{CredentialJson: string(credential)})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("replayed registration: got %v, want InvalidArgument", got)
+		}
+
+		// Nor can another user finish it.
+		begun, err = authServer.BeginPasskeyRegistration(octocat, &auth.BeginPasskeyRegistrationRequest{})
+		if err != nil {
+			t.Fatalf("BeginPasskeyRegistration: %v", err)
+		}
+		credential, err = webauthntest.New("http://localhost:5173").Register([]byte(begun.OptionsJson))
+		if err != nil {
+			t.Fatalf("authenticator Register: %v", err)
+		}
+		_, err = authServer.FinishPasskeyRegistration(alice, &auth.FinishPasskeyRegistrationRequest{CredentialJson: string(credential)})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("another user's ceremony: got %v, want InvalidArgument", got)
+		}
+	})
+
+	t.Run("passwordless login", func(t *testing.T) {
+		credential := passkeyLogin(phone)
+		resp, err := finishLogin(credential)
+		if err != nil {
+			t.Fatalf("FinishPasskeyLogin: %v", err)
+		}
+		if resp.Username != "octocat" || resp.Token == "" || resp.RefreshToken == "" {
+			t.Errorf("got %+v", resp)
+		}
+		claims, err := lib.ValidateToken(resp.Token)
+		if err != nil || claims.UserID != octocatUser.UserID.String() {
+			t.Errorf("token claims: got %+v, %v", claims, err)
+		}
+
+		_, err = finishLogin(credential)
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("replayed assertion: got %v, want Unauthenticated", got)
+		}
+
+		listed, err := authServer.ListPasskeys(octocat, &auth.ListPasskeysRequest{})
+		if err != nil {
+			t.Fatalf("ListPasskeys: %v", err)
+		}
+		if listed.Passkeys[1].LastUsedAt == "" {
+			t.Error("phone passkey has no last_used_at after signing in")
+		}
+	})
+
+	t.Run("cloned passkey", func(t *testing.T) {
+		phone.SetSignCount(0)
+		_, err := finishLogin(passkeyLogin(phone))
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("counter went backwards: got %v, want Unauthenticated", got)
+		}
+		if _, err := finishLogin(passkeyLogin(phone)); err != nil {
+			t.Errorf("counter moved on again: %v", err)
+		}
+	})
+
+	t.Run("user not verified", func(t *testing.T) {
+		laptop.UserVerified = false
+		defer func() { laptop.UserVerified = true }()
+		_, err := finishLogin(passkeyLogin(laptop))
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("got %v, want Unauthenticated", got)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := authServer.DeletePasskey(alice, &auth.DeletePasskeyRequest{Id: laptopKey.Id})
+		if got := grpcCode(err); got != codes.NotFound {
+			t.Errorf("another user's passkey: got %v, want NotFound", got)
+		}
+		if _, err := authServer.DeletePasskey(octocat, &auth.DeletePasskeyRequest{Id: laptopKey.Id}); err != nil {
+			t.Fatalf("DeletePasskey: %v", err)
+		}
+		_, err = finishLogin(passkeyLogin(laptop))
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("deleted passkey: got %v, want Unauthenticated", got)
+		}
+	})
+
+	t.Run("second factor", func(t *testing.T) {
+		enrolled, err := authServer.EnrollTOTP(alice, &auth.EnrollTOTPRequest{})
+		if err != nil {
+			t.Fatalf("EnrollTOTP: %v", err)
+		}
+		code, err := totp.Code(enrolled.Secret, totp.Step(time.Now()))
+		if err != nil {
+			t.Fatalf("totp.Code: %v", err)
+		}
+		if _, err := authServer.ConfirmTOTP(alice, &auth.ConfirmTOTPRequest{Code: code}); err != nil {
+			t.Fatalf("ConfirmTOTP: %v", err)
+		}
+		login := func() string {
+			t.Helper()
+			resp, err := authServer.Login(context.Background(), &auth.LoginRequest{UserName: "alice", Passwd: "password"})
+			if err != nil || !resp.SecondFactorRequired {
+				t.Fatalf("Login: got %+v, %v; want a challenge", resp, err)
+			}
+			return resp.ChallengeToken
+		}
+
+		_, err = authServer.BeginSecondFactorPasskey(context.Background(), &auth.BeginSecondFactorPasskeyRequest{ChallengeToken: login()})
+		if got := grpcCode(err); got != codes.FailedPrecondition {
+			t.Errorf("no passkeys: got %v, want FailedPrecondition", got)
+		}
+
+		key := webauthntest.New("http://localhost:5173")
+		register(alice, key, "Key")
+
+		challenge := login()
+		begun, err := authServer.BeginSecondFactorPasskey(context.Background(), &auth.BeginSecondFactorPasskeyRequest{ChallengeToken: challenge})
+		if err != nil {
+			t.Fatalf("BeginSecondFactorPasskey: %v", err)
+		}
+		// Another user's passkey is not asked for and does not count.
+		if _, err := phone.Login([]byte(begun.OptionsJson)); err == nil {
+			t.Error("octocat's passkey was allowed for alice")
+		}
+		credential, err := key.Login([]byte(begun.OptionsJson))
+		if err != nil {
+			t.Fatalf("authenticator Login: %v", err)
+		}
+		verified, err := authServer.VerifySecondFactor(context.Background(), &auth.VerifySecondFactorRequest{ChallengeToken: challenge, PasskeyCredentialJson: string(credential)})
+		if err != nil {
+			t.Fatalf("VerifySecondFactor: %v", err)
+		}
+		if verified.Username != "alice" || verified.Token == "" {
+			t.Errorf("got %+v", verified)
+		}
+
+		// The passkey ceremony belongs to the spent challenge.
+		_, err = authServer.VerifySecondFactor(context.Background(), &auth.VerifySecondFactorRequest{ChallengeToken: login(), PasskeyCredentialJson: string(credential)})
+		if got := grpcCode(err); got != codes.Unauthenticated {
+			t.Errorf("replayed passkey: got %v, want Unauthenticated", got)
+		}
+
+		// A passkey sign-in verifies the user itself: no code is asked for.
+		if resp, err := finishLogin(passkeyLogin(key)); err != nil || resp.Username != "alice" {
+			t.Errorf("passkey login with two-factor on: got %+v, %v", resp, err)
+		}
+	})
+}
+
+func TestOAuthSignIn(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	authServer := services.NewAuthServer(sqlDB, nil)
+	createTestUsers(t, sqlDB, "alice")
+
+	iss := oauthtest.NewIssuer("chat", "secret")
+	defer iss.Close()
+	authServer.SetOAuthProviders(map[string]oauth.Provider{
+		"acme": oauth.NewOIDC(iss.URL, oauth.Config{
+			ClientID:     iss.ClientID,
+			ClientSecret: iss.ClientSecret,
+			RedirectURL:  "http://localhost:8080/oauth/acme/callback",
+		}),
+	})
+
+	// authorize starts a sign-in and approves it at the issuer as iss.User.
+	authorize := func() (code, state string) {
+		t.Helper()
+		started, err := authServer.GetOAuthURL(context.Background(), &auth.GetOAuthURLRequest{Provider: "acme"})
+		if err != nil {
+			t.Fatalf("GetOAuthURL: %v", err)
+		}
+		code, state, err = iss.Authorize(started.Url)
+		if err != nil {
+			t.Fatalf("Authorize: %v", err)
+		}
+		return code, state
+	}
+	callback := func(code, state string) (*auth.OAuthCallbackResponse, error) {
+		return authServer.OAuthCallback(context.Background(), &auth.OAuthCallbackRequest{Provider: "acme", Code: code, State: state})
+	}
+
+	t.Run("unknown provider", func(t *testing.T) {
+		_, err := authServer.GetOAuthURL(context.Background(), &auth.GetOAuthURLRequest{Provider: "github"})
+		if got := grpcCode(err); got != codes.NotFound {
+			t.Errorf("got %v, want NotFound", got)
+		}
+	})
+
+	var aliceID string
+
+	t.Run("sign up", func(t *testing.T) {
+		// The issuer suggests "alice", which an email account already has.
+		resp, err := callback(authorize())
+		if err != nil {
+			t.Fatalf("OAuthCallback: %v", err)
+		}
+		if resp.Username != "alice-2" {
+			t.Errorf("username: got %q, want alice-2", resp.Username)
+		}
+		claims, err := lib.ValidateToken(resp.ShortLivedToken)
+		if err != nil {
+			t.Fatalf("ValidateToken: %v", err)
+		}
+		if claims.Scope != lib.ScopeOAuthExchange {
+			t.Errorf("scope: got %q, want %q", claims.Scope, lib.ScopeOAuthExchange)
+		}
+		aliceID = claims.UserID
+
+		user, err := db.New(sqlDB).GetUserByUsername(t.Context(), "alice-2")
+		if err != nil {
+			t.Fatalf("GetUserByUsername: %v", err)
+		}
+		if user.SignupType != db.SignupTypeOidc || user.HashedPasswd.Valid || user.DisplayName.String != "Alice" {
+			t.Errorf("user: got %+v", user)
+		}
+	})
+
+	t.Run("sign in", func(t *testing.T) {
+		// The subject, not the suggested username, picks the account.
+		iss.User.PreferredUsername = "renamed"
+		defer func() { iss.User.PreferredUsername = "alice" }()
+		resp, err := callback(authorize())
+		if err != nil {
+			t.Fatalf("OAuthCallback: %v", err)
+		}
+		claims, err := lib.ValidateToken(resp.ShortLivedToken)
+		if err != nil {
+			t.Fatalf("ValidateToken: %v", err)
+		}
+		if resp.Username != "alice-2" || claims.UserID != aliceID {
+			t.Errorf("got %s (%s), want alice-2 (%s)", resp.Username, claims.UserID, aliceID)
+		}
+	})
+
+	t.Run("another account", func(t *testing.T) {
+		iss.User = oauthtest.User{Subject: "user-2", PreferredUsername: "b"}
+		resp, err := callback(authorize())
+		if err != nil {
+			t.Fatalf("OAuthCallback: %v", err)
+		}
+		// "b" is too short to be a username.
+		if resp.Username != "user" {
+			t.Errorf("username: got %q, want user", resp.Username)
+		}
+	})
+
+	t.Run("state", func(t *testing.T) {
+		code, state := authorize()
+		if _, err := callback(code, "forged"); grpcCode(err) != codes.Unauthenticated {
+			t.Errorf("forged state: got %v, want Unauthenticated", err)
+		}
+		if _, err := callback(code, state); err != nil {
+			t.Fatalf("OAuthCallback: %v", err)
+		}
+		// Each state finishes one sign-in.
+		code, _ = authorize()
+		if _, err := callback(code, state); grpcCode(err) != codes.Unauthenticated {
+			t.Errorf("replayed state: got %v, want Unauthenticated", err)
+		}
+	})
+
+	t.Run("bad code", func(t *testing.T) {
+		_, state := authorize()
+		if _, err := callback("not-a-code", state); grpcCode(err) != codes.Unauthenticated {
+			t.Errorf("got %v, want Unauthenticated", err)
+		}
+	})
+}
+
+func TestOAuthLegacyGitHubLink(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	authServer := services.NewAuthServer(sqlDB, nil)
+	queries := db.New(sqlDB)
+
+	// Accounts from before GitHub IDs were stored. mona was renamed from
+	// "mona-old", so GitHub's "mona" need not be her.
+	legacy := map[string]uuid.UUID{}
+	for _, name := range []string{"octocat", "mona"} {
+		user, err := queries.CreateUser(t.Context(), db.CreateUserParams{UserName: name, SignupType: db.SignupTypeGithub})
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		legacy[name] = user.UserID
+	}
+	if err := queries.InsertUsernameHistory(t.Context(), db.InsertUsernameHistoryParams{UserID: legacy["mona"], UserName: "mona-old"}); err != nil {
+		t.Fatalf("InsertUsernameHistory: %v", err)
+	}
+
+	ghUser := map[string]any{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "token-1", "token_type": "bearer"})
+	})
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ghUser)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	github := oauth.NewGitHub(oauth.Config{ClientID: "chat", ClientSecret: "secret", RedirectURL: "http://localhost:8080/oauth/github/callback"})
+	github.TokenEndpoint = srv.URL + "/token"
+	github.UserEndpoint = srv.URL + "/user"
+	authServer.SetOAuthProviders(map[string]oauth.Provider{"github": github})
+
+	signIn := func(t *testing.T, id int, login string) (string, string) {
+		t.Helper()
+		ghUser = map[string]any{"id": id, "login": login}
+		started, err := authServer.GetOAuthURL(context.Background(), &auth.GetOAuthURLRequest{Provider: "github"})
+		if err != nil {
+			t.Fatalf("GetOAuthURL: %v", err)
+		}
+		u, _ := url.Parse(started.Url)
+		resp, err := authServer.OAuthCallback(context.Background(), &auth.OAuthCallbackRequest{
+			Provider: "github",
+			Code:     "code-1",
+			State:    u.Query().Get("state"),
+		})
+		if err != nil {
+			t.Fatalf("OAuthCallback: %v", err)
+		}
+		claims, err := lib.ValidateToken(resp.ShortLivedToken)
+		if err != nil {
+			t.Fatalf("ValidateToken: %v", err)
+		}
+		return claims.UserID, resp.Username
+	}
+
+	t.Run("unrenamed account is linked", func(t *testing.T) {
+		if id, name := signIn(t, 1, "octocat"); id != legacy["octocat"].String() || name != "octocat" {
+			t.Errorf("got %s (%s), want octocat (%s)", name, id, legacy["octocat"])
+		}
+		// From now on the GitHub ID finds it, whatever the login.
+		if id, _ := signIn(t, 1, "octocat-renamed"); id != legacy["octocat"].String() {
+			t.Errorf("by ID: got %s, want %s", id, legacy["octocat"])
+		}
+	})
+
+	t.Run("renamed account is not linked", func(t *testing.T) {
+		id, name := signIn(t, 2, "mona")
+		if id == legacy["mona"].String() {
+			t.Fatal("signed in to the renamed legacy account")
+		}
+		if name != "mona-2" {
+			t.Errorf("username: got %q, want mona-2", name)
+		}
+	})
+}
@@ -3,7 +3,10 @@ package services
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
 	"github.com/google/uuid"
@@ -30,18 +33,27 @@ func NewChatServer(sqlDB *sql.DB, notif *NotificationServer) *ChatServer {
 // CreateConversation creates a new group conversation or a DM between two users.
 // For DMs (is_group=false), if a conversation already exists between the two users
 // the existing conversation_id is returned without creating a duplicate.
+// If label_id is set, the caller's friends in that label are added to
+// members_username first.
 func (s *ChatServer) CreateConversation(ctx context.Context, req *pb.CreateConversationRequest) (*pb.CreateConversationResponse, error) {
 	callerID, err := lib.CallerUUID(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	q0 := db.New(s.sqlDB)
+
+	if req.GetLabelId() != 0 {
+		if err := expandLabelMembers(ctx, q0, callerID, req); err != nil {
+			return nil, err
+		}
+	}
+
 	if len(req.GetMembersUsername()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "members_username must not be empty")
 	}
 
 	// Resolve usernames to UUIDs and verify each member is an accepted friend.
-	q0 := db.New(s.sqlDB)
 	memberIDs := make([]uuid.UUID, 0, len(req.GetMembersUsername()))
 	for _, username := range req.GetMembersUsername() {
 		user, err := q0.GetUserByUsername(ctx, username)
@@ -56,6 +68,17 @@ func (s *ChatServer) CreateConversation(ctx context.Context, req *pb.CreateConve
 			memberIDs = append(memberIDs, memberID)
 			continue // self-as-member is validated downstream
 		}
+		block, err := q0.GetBlockState(ctx, db.GetBlockStateParams{UserID: callerID, OtherID: memberID})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "CreateConversation: get block state: %v", err)
+		}
+		if block.IsBlockedBy {
+			// Answer as if the user did not exist so the block is not revealed.
+			return nil, status.Errorf(codes.NotFound, "user %q not found", username)
+		}
+		if block.HasBlocked {
+			return nil, status.Errorf(codes.FailedPrecondition, "you have blocked %q", username)
+		}
 		first, second := lib.OrderedUUIDPair(callerID, memberID)
 		friendship, err := q0.GetFriendship(ctx, db.GetFriendshipParams{
 			User1Userid: first,
@@ -79,9 +102,10 @@ func (s *ChatServer) CreateConversation(ctx context.Context, req *pb.CreateConve
 	q := db.New(tx)
 
 	var conversationID int64
+	var created systemEventPush
 
 	if req.GetIsGroup() {
-		conversationID, err = s.createGroupConversation(ctx, q, callerID, req, memberIDs)
+		conversationID, created, err = s.createGroupConversation(ctx, q, callerID, req, memberIDs)
 	} else {
 		conversationID, err = s.createOrGetDmConversation(ctx, q, callerID, memberIDs)
 	}
@@ -92,15 +116,16 @@ func (s *ChatServer) CreateConversation(ctx context.Context, req *pb.CreateConve
 	if err := tx.Commit(); err != nil {
 		return nil, status.Errorf(codes.Internal, "CreateConversation: commit: %v", err)
 	}
+	created.publish(s.notif)
 
 	return &pb.CreateConversationResponse{
 		ConversationId: conversationID,
 	}, nil
 }
 
-func (s *ChatServer) createGroupConversation(ctx context.Context, q *db.Queries, callerID uuid.UUID, req *pb.CreateConversationRequest, memberIDs []uuid.UUID) (int64, error) {
+func (s *ChatServer) createGroupConversation(ctx context.Context, q *db.Queries, callerID uuid.UUID, req *pb.CreateConversationRequest, memberIDs []uuid.UUID) (int64, systemEventPush, error) {
 	if req.GetName() == "" {
-		return 0, status.Error(codes.InvalidArgument, "name is required for group conversations")
+		return 0, systemEventPush{}, status.Error(codes.InvalidArgument, "name is required for group conversations")
 	}
 
 	conv, err := q.CreateConversation(ctx, db.CreateConversationParams{
@@ -108,7 +133,7 @@ func (s *ChatServer) createGroupConversation(ctx context.Context, q *db.Queries,
 		Name:    sql.NullString{Valid: true, String: req.GetName()},
 	})
 	if err != nil {
-		return 0, status.Errorf(codes.Internal, "createGroupConversation: create conversation: %v", err)
+		return 0, systemEventPush{}, status.Errorf(codes.Internal, "createGroupConversation: create conversation: %v", err)
 	}
 
 	// Add the caller as the group owner.
@@ -117,21 +142,42 @@ func (s *ChatServer) createGroupConversation(ctx context.Context, q *db.Queries,
 		UserID:         callerID,
 		Role:           db.MemberRoleOwner,
 	}); err != nil {
-		return 0, status.Errorf(codes.Internal, "createGroupConversation: add owner: %v", err)
+		return 0, systemEventPush{}, status.Errorf(codes.Internal, "createGroupConversation: add owner: %v", err)
 	}
 
 	// Add each requested member as a regular member.
-	for _, memberID := range memberIDs {
+	targets := make([]lib.SystemEventUser, 0, len(memberIDs))
+	for i, memberID := range memberIDs {
 		if _, err := q.AddMemberWithRole(ctx, db.AddMemberWithRoleParams{
 			ConversationID: conv.ID,
 			UserID:         memberID,
 			Role:           db.MemberRoleMember,
 		}); err != nil {
-			return 0, status.Errorf(codes.Internal, "createGroupConversation: add member %s: %v", memberID, err)
+			return 0, systemEventPush{}, status.Errorf(codes.Internal, "createGroupConversation: add member %s: %v", memberID, err)
 		}
+		targets = append(targets, lib.SystemEventUser{UserID: memberID.String(), Username: req.GetMembersUsername()[i]})
 	}
 
-	return conv.ID, nil
+	push, err := recordSystemEvent(ctx, q, conv.ID, lib.SystemEvent{
+		Kind:    lib.SystemEventCreated,
+		Actor:   callerEventUser(ctx, q),
+		Targets: targets,
+		Name:    req.GetName(),
+	})
+	if err != nil {
+		return 0, systemEventPush{}, status.Errorf(codes.Internal, "createGroupConversation: record system event: %v", err)
+	}
+
+	for _, memberID := range memberIDs {
+		if memberID == callerID {
+			continue
+		}
+		if err := notifyGroupMember(ctx, q, s.notif, conv.ID, memberID, db.NotificationTypeGroupInvite, "%s invited you to %s"); err != nil {
+			return 0, systemEventPush{}, status.Errorf(codes.Internal, "createGroupConversation: create notification: %v", err)
+		}
+	}
+
+	return conv.ID, push, nil
 }
 
 func (s *ChatServer) createOrGetDmConversation(ctx context.Context, q *db.Queries, callerID uuid.UUID, memberIDs []uuid.UUID) (int64, error) {
@@ -152,6 +198,13 @@ func (s *ChatServer) createOrGetDmConversation(ctx context.Context, q *db.Querie
 		User2ID: second,
 	})
 	if err == nil {
+		// Reopen the DM if the caller deleted it earlier.
+		if err := q.UnhideConversationForUser(ctx, db.UnhideConversationForUserParams{
+			ConversationID: existing.ConversationID,
+			UserID:         callerID,
+		}); err != nil {
+			return 0, status.Errorf(codes.Internal, "createOrGetDmConversation: unhide: %v", err)
+		}
 		return existing.ConversationID, nil
 	}
 	if err != sql.ErrNoRows {
@@ -222,6 +275,11 @@ func (s *ChatServer) SendMessage(ctx context.Context, req *pb.SendMessageRequest
 		return nil, status.Errorf(codes.InvalidArgument, "invalid message_type %q", msgType)
 	}
 
+	searchTokens, err := normalizeSearchTokens(req.GetSearchTokens())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid search_tokens: %v", err)
+	}
+
 	q := db.New(s.sqlDB)
 
 	isMember, err := q.IsMember(ctx, db.IsMemberParams{
@@ -236,6 +294,14 @@ func (s *ChatServer) SendMessage(ctx context.Context, req *pb.SendMessageRequest
 		return nil, status.Error(codes.PermissionDenied, "caller is not a member of this conversation")
 	}
 
+	readOnly, err := q.IsDmReadOnly(ctx, req.GetConversationId())
+	if err != nil && err != sql.ErrNoRows {
+		return nil, status.Errorf(codes.Internal, "SendMessage: check dm: %v", err)
+	}
+	if readOnly {
+		return nil, lib.NewChatError(codes.FailedPrecondition, lib.ChatErrorReadOnly, "conversation is read-only: you are no longer friends")
+	}
+
 	// get reply message id
 	var replyTo uuid.NullUUID
 	if r := req.GetReplyToMessageId(); r != "" {
@@ -264,8 +330,34 @@ func (s *ChatServer) SendMessage(ctx context.Context, req *pb.SendMessageRequest
 		return nil, status.Errorf(codes.Internal, "SendMessage: get members: %v", err)
 	}
 
-	// prepare message
-	msg := db.Message{
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SendMessage: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	qtx := db.New(tx)
+
+	// Enforce slow mode. The claim is rolled back with the tx if the insert fails.
+	claimed, err := qtx.ClaimSendSlot(ctx, db.ClaimSendSlotParams{
+		ConversationID: req.GetConversationId(),
+		UserID:         callerID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SendMessage: claim send slot: %v", err)
+	}
+	if claimed == 0 {
+		retryAfterMs, err := qtx.GetSlowModeRetryAfter(ctx, db.GetSlowModeRetryAfterParams{
+			ConversationID: req.GetConversationId(),
+			UserID:         callerID,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "SendMessage: get retry-after: %v", err)
+		}
+		return nil, lib.NewSlowModeError(time.Duration(retryAfterMs) * time.Millisecond)
+	}
+
+	// persist the message so it is returned by history paging
+	msg, err := qtx.SendMessage(ctx, db.SendMessageParams{
 		ID:               msgID,
 		ConversationID:   req.GetConversationId(),
 		SenderID:         callerID,
@@ -273,8 +365,35 @@ func (s *ChatServer) SendMessage(ctx context.Context, req *pb.SendMessageRequest
 		ReplyToMessageID: replyTo,
 		Content:          req.GetContent(),
 		MessageType:      msgType,
-		CreatedAt:        time.Now().UTC(),
 		MediaUrl:         sql.NullString{},
+	})
+	if lib.IsPgUniqueViolation(err) {
+		return nil, status.Errorf(codes.AlreadyExists, "message %s already exists", msgID)
+	}
+	if lib.IsPgForeignKeyViolation(err) {
+		return nil, status.Error(codes.InvalidArgument, "reply_to_message_id does not exist")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SendMessage: insert message: %v", err)
+	}
+
+	if len(searchTokens) > 0 {
+		if err := qtx.InsertMessageSearchTokens(ctx, db.InsertMessageSearchTokensParams{
+			MessageID:      msg.ID,
+			ConversationID: msg.ConversationID,
+			Tokens:         searchTokens,
+		}); err != nil {
+			return nil, status.Errorf(codes.Internal, "SendMessage: insert search tokens: %v", err)
+		}
+	}
+
+	// A new message brings a deleted DM back for whoever hid it.
+	if err := qtx.UnhideConversation(ctx, req.GetConversationId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "SendMessage: unhide conversation: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "SendMessage: commit: %v", err)
 	}
 
 	msgBytes, err := lib.NewChatResponseEnvelope(lib.ChatEventMessage, msg)
@@ -305,17 +424,17 @@ func (s *ChatServer) SendMessage(ctx context.Context, req *pb.SendMessageRequest
 	}
 
 	// notify other members
-	callerName := lib.CallerFrom(ctx)
+	senderName := callerName(ctx, q)
 	for _, m := range members {
 		if m.UserID == callerID {
 			continue
 		}
 		s.notif.Send(ctx, q, db.CreateNotificationParams{
-			UserID:      m.UserID,
-			SenderID:    uuid.NullUUID{Valid: true, UUID: callerID},
-			Type:        db.NotificationTypeMessage,
-			Message:     fmt.Sprintf("%s sent a message", callerName),
-			ReferenceID: sql.NullInt64{Valid: true, Int64: req.GetConversationId()},
+			UserID:                  m.UserID,
+			SenderID:                uuid.NullUUID{Valid: true, UUID: callerID},
+			Type:                    db.NotificationTypeMessage,
+			Message:                 fmt.Sprintf("%s sent a message", senderName),
+			ReferenceConversationID: sql.NullInt64{Valid: true, Int64: req.GetConversationId()},
 		})
 	}
 
@@ -367,9 +486,10 @@ func (s *ChatServer) GetConversations(ctx context.Context, req *pb.GetConversati
 	}, nil
 }
 
-// GetConversationsByName returns conversations matching the search pattern.
-// For groups: matches conversation name.
-// For DMs: matches the other member's username.
+// GetConversationsByName returns the caller's conversations matching the search text.
+// Groups match on their name; any conversation also matches on the username or
+// display name of its other members. Results are ranked (exact matches first,
+// then trigram similarity) and cursor-paged.
 func (s *ChatServer) GetConversationsByName(ctx context.Context, req *pb.GetConversationsByNameRequest) (*pb.GetConversationsResponse, error) {
 	callerID, err := lib.CallerUUID(ctx)
 	if err != nil {
@@ -380,12 +500,24 @@ func (s *ChatServer) GetConversationsByName(ctx context.Context, req *pb.GetConv
 		return nil, status.Error(codes.InvalidArgument, "name is required")
 	}
 
+	params := db.GetConversationsByNameParams{
+		Query:     req.GetName(),
+		CallerID:  callerID,
+		PageLimit: searchPageLimit(req.GetLimit()),
+	}
+	if req.GetCursor() != "" {
+		var c searchCursor
+		if err := lib.DecodeCursor(req.GetCursor(), &c); err != nil || c.ID == 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		}
+		params.CursorExact = sql.NullInt32{Valid: true, Int32: c.Exact}
+		params.CursorScore = sql.NullFloat64{Valid: true, Float64: c.Score}
+		params.CursorID = sql.NullInt64{Valid: true, Int64: c.ID}
+	}
+
 	q := db.New(s.sqlDB)
 
-	conversations, err := q.GetConversationsByName(ctx, db.GetConversationsByNameParams{
-		UserID:  callerID,
-		Column2: sql.NullString{Valid: true, String: req.GetName()},
-	})
+	conversations, err := q.GetConversationsByName(ctx, params)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "GetConversationsByName: query: %v", err)
 	}
@@ -416,13 +548,102 @@ func (s *ChatServer) GetConversationsByName(ctx context.Context, req *pb.GetConv
 		})
 	}
 
-	return &pb.GetConversationsResponse{
-		Conversations: results,
+	resp := &pb.GetConversationsResponse{Conversations: results}
+	if len(conversations) == int(params.PageLimit) {
+		last := conversations[len(conversations)-1]
+		resp.NextCursor, err = lib.EncodeCursor(searchCursor{Exact: last.ExactMatch, Score: last.Score, ID: last.ID})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "GetConversationsByName: encode cursor: %v", err)
+		}
+	}
+	return resp, nil
+}
+
+// GetConversation returns a single conversation with its members and the
+// caller's own membership settings. The caller must be a member.
+func (s *ChatServer) GetConversation(ctx context.Context, req *pb.GetConversationRequest) (*pb.GetConversationResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetConversationId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "conversation_id is required")
+	}
+
+	q := db.New(s.sqlDB)
+
+	self, err := q.GetConversationMember(ctx, db.GetConversationMemberParams{
+		ConversationID: req.GetConversationId(),
+		UserID:         callerID,
+	})
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.PermissionDenied, "caller is not a member of this conversation")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "GetConversation: get membership: %v", err)
+	}
+
+	conv, err := q.GetConversation(ctx, req.GetConversationId())
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "conversation %d not found", req.GetConversationId())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "GetConversation: query: %v", err)
+	}
+
+	members, err := q.GetConversationMembers(ctx, conv.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "GetConversation: get members: %v", err)
+	}
+
+	memberProtos := make([]*pb.ConversationMember, 0, len(members))
+	for _, m := range members {
+		memberProtos = append(memberProtos, &pb.ConversationMember{
+			UserId:      m.UserID_2.String(),
+			Username:    m.UserName,
+			DisplayName: m.DisplayName.String,
+			AvatarUrl:   m.AvatarUrl.String,
+			Role:        string(m.Role),
+			JoinedAt:    m.JoinedAt.Format(time.RFC3339),
+			IsE2EeReady: m.IsE2eeReady,
+		})
+	}
+
+	settings := &pb.ConversationSettings{
+		Role:     string(self.Role),
+		JoinedAt: self.JoinedAt.Format(time.RFC3339),
+	}
+	if self.LastReadMessageID.Valid {
+		settings.LastReadMessageId = self.LastReadMessageID.UUID.String()
+	}
+	if self.LastDeliveredMessageID.Valid {
+		settings.LastDeliveredMessageId = self.LastDeliveredMessageID.UUID.String()
+	}
+
+	readOnly := false
+	if !conv.IsGroup {
+		readOnly, err = q.IsDmReadOnly(ctx, conv.ID)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, status.Errorf(codes.Internal, "GetConversation: check dm: %v", err)
+		}
+	}
+
+	return &pb.GetConversationResponse{
+		Id:              conv.ID,
+		IsGroup:         conv.IsGroup,
+		Name:            conv.Name.String,
+		CreatedAt:       conv.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       conv.UpdatedAt.Format(time.RFC3339),
+		Members:         memberProtos,
+		Settings:        settings,
+		SlowModeSeconds: conv.SlowModeSeconds,
+		ReadOnly:        readOnly,
 	}, nil
 }
 
 // UpdateLastReadMessage marks a message as read for the calling user
-// and notifies the original sender via NATS.
+// and notifies the original sender via NATS if the caller has read receipts on.
 func (s *ChatServer) UpdateLastReadMessage(ctx context.Context, req *pb.UpdateMessageRequest) (*pb.UpdateMessageResponse, error) {
 	callerID, err := lib.CallerUUID(ctx)
 	if err != nil {
@@ -450,10 +671,16 @@ func (s *ChatServer) UpdateLastReadMessage(ctx context.Context, req *pb.UpdateMe
 		return nil, status.Errorf(codes.Internal, "UpdateLastReadMessage: %v", err)
 	}
 
-	// Notify the original sender that their message was read.
-	if s.notif != nil && req.GetUserId() != "" {
+	settings, err := q.GetPrivacySettings(ctx, callerID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "UpdateLastReadMessage: get privacy settings: %v", err)
+	}
+
+	// Notify the original sender that their message was read, unless the
+	// caller turned read receipts off.
+	if s.notif != nil && settings.ReadReceipts && req.GetUserId() != "" {
 		senderID, err := uuid.Parse(req.GetUserId())
-		if err == nil {
+		if err == nil && !isBlockedEither(ctx, q, callerID, senderID) {
 			receiptBytes, err := lib.NewChatResponseEnvelope(lib.ChatEventRead, lib.ReadEvent{
 				ConversationID: req.GetConversationId(),
 				MessageID:      req.GetMessageId(),
@@ -505,7 +732,7 @@ func (s *ChatServer) UpdateLastDeliveredMessage(ctx context.Context, req *pb.Upd
 	// Notify the original sender that their message was delivered.
 	if s.notif != nil && req.GetUserId() != "" {
 		senderID, err := uuid.Parse(req.GetUserId())
-		if err == nil {
+		if err == nil && !isBlockedEither(ctx, q, callerID, senderID) {
 			receiptBytes, err := lib.NewChatResponseEnvelope(lib.ChatEventDelivered, lib.DeliveredEvent{
 				ConversationID: req.GetConversationId(),
 				MessageID:      req.GetMessageId(),
@@ -518,3 +745,1049 @@ func (s *ChatServer) UpdateLastDeliveredMessage(ctx context.Context, req *pb.Upd
 
 	return &pb.UpdateMessageResponse{}, nil
 }
+
+// GetMessages returns a page of stored messages in a conversation, oldest first,
+// including system events. Pass next_cursor from a previous response to continue.
+func (s *ChatServer) GetMessages(ctx context.Context, req *pb.GetMessagesRequest) (*pb.GetMessagesResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetConversationId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "conversation_id is required")
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var cursor uuid.NullUUID
+	if c := req.GetCursor(); c != "" {
+		parsed, err := uuid.Parse(c)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cursor: %v", err)
+		}
+		cursor = uuid.NullUUID{Valid: true, UUID: parsed}
+	}
+
+	q := db.New(s.sqlDB)
+
+	isMember, err := q.IsMember(ctx, db.IsMemberParams{
+		ConversationID: req.GetConversationId(),
+		UserID:         callerID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "GetMessages: check membership: %v", err)
+	}
+	if !isMember {
+		return nil, status.Error(codes.PermissionDenied, "caller is not a member of this conversation")
+	}
+
+	if cursor.Valid {
+		found, err := q.IsMessageInConversation(ctx, db.IsMessageInConversationParams{
+			ID:             cursor.UUID,
+			ConversationID: req.GetConversationId(),
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "GetMessages: check cursor: %v", err)
+		}
+		if !found {
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor: no such message in this conversation")
+		}
+	}
+
+	rows, err := q.GetConversationMessages(ctx, db.GetConversationMessagesParams{
+		ConversationID: req.GetConversationId(),
+		ViewerID:       callerID,
+		Cursor:         cursor,
+		PageLimit:      limit,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "GetMessages: query: %v", err)
+	}
+
+	messages := make([]*pb.Message, 0, len(rows))
+	for _, r := range rows {
+		m := &pb.Message{
+			MessageId:      r.ID.String(),
+			ConversationId: req.GetConversationId(),
+			SenderId:       r.SenderID.String(),
+			Content:        r.Content,
+			MessageType:    string(r.MessageType),
+			IsEdited:       r.IsEdited,
+			CreatedAt:      r.CreatedAt.Format(time.RFC3339Nano),
+		}
+		if r.ReplyToMessageID.Valid {
+			m.ReplyToMessageId = r.ReplyToMessageID.UUID.String()
+		}
+		messages = append(messages, m)
+	}
+
+	resp := &pb.GetMessagesResponse{Messages: messages}
+	if len(rows) == int(limit) {
+		resp.NextCursor = rows[len(rows)-1].ID.String()
+	}
+	return resp, nil
+}
+
+// SearchMessages returns the caller's messages that carry every blind-index
+// token of their conversation's token set, newest first. Conversations the
+// caller is not a member of are silently ignored.
+func (s *ChatServer) SearchMessages(ctx context.Context, req *pb.SearchMessagesRequest) (*pb.SearchMessagesResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sets := req.GetTokenSets()
+	if len(sets) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "token_sets is required")
+	}
+	if len(sets) > maxSearchTokenSets {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d token_sets are allowed", maxSearchTokenSets)
+	}
+
+	// Flatten the sets into parallel (conversation_id, token) arrays for the query.
+	var conversationIDs []int64
+	var tokens []string
+	seen := make(map[int64]bool, len(sets))
+	for _, set := range sets {
+		if set.GetConversationId() == 0 {
+			return nil, status.Error(codes.InvalidArgument, "token_sets: conversation_id is required")
+		}
+		if seen[set.GetConversationId()] {
+			return nil, status.Errorf(codes.InvalidArgument, "token_sets: duplicate conversation_id %d", set.GetConversationId())
+		}
+		seen[set.GetConversationId()] = true
+
+		setTokens, err := normalizeSearchTokens(set.GetTokens())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "token_sets: conversation %d: %v", set.GetConversationId(), err)
+		}
+		if len(setTokens) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "token_sets: conversation %d: tokens is required", set.GetConversationId())
+		}
+		for _, t := range setTokens {
+			conversationIDs = append(conversationIDs, set.GetConversationId())
+			tokens = append(tokens, t)
+		}
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var cursor uuid.NullUUID
+	if c := req.GetCursor(); c != "" {
+		parsed, err := uuid.Parse(c)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cursor: %v", err)
+		}
+		cursor = uuid.NullUUID{Valid: true, UUID: parsed}
+	}
+
+	rows, err := db.New(s.sqlDB).SearchMessagesByTokens(ctx, db.SearchMessagesByTokensParams{
+		CallerID:        callerID,
+		Cursor:          cursor,
+		PageLimit:       limit,
+		ConversationIds: conversationIDs,
+		Tokens:          tokens,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SearchMessages: query: %v", err)
+	}
+
+	messages := make([]*pb.Message, 0, len(rows))
+	for _, r := range rows {
+		m := &pb.Message{
+			MessageId:      r.ID.String(),
+			ConversationId: r.ConversationID,
+			SenderId:       r.SenderID.String(),
+			Content:        r.Content,
+			MessageType:    string(r.MessageType),
+			IsEdited:       r.IsEdited,
+			CreatedAt:      r.CreatedAt.Format(time.RFC3339Nano),
+		}
+		if r.ReplyToMessageID.Valid {
+			m.ReplyToMessageId = r.ReplyToMessageID.UUID.String()
+		}
+		messages = append(messages, m)
+	}
+
+	resp := &pb.SearchMessagesResponse{Messages: messages}
+	if len(rows) == int(limit) {
+		resp.NextCursor = rows[len(rows)-1].ID.String()
+	}
+	return resp, nil
+}
+
+// AddMembers adds friends of the caller to a group conversation.
+// Only admins and the owner may add members.
+func (s *ChatServer) AddMembers(ctx context.Context, req *pb.AddMembersRequest) (*pb.UpdateConversationResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.GetMembersUsername()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "members_username must not be empty")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "AddMembers: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	if _, err := requireGroupRole(ctx, q, req.GetConversationId(), callerID, db.MemberRoleAdmin, db.MemberRoleOwner); err != nil {
+		return nil, err
+	}
+
+	targets := make([]lib.SystemEventUser, 0, len(req.GetMembersUsername()))
+	addedIDs := make([]uuid.UUID, 0, len(req.GetMembersUsername()))
+	for _, username := range req.GetMembersUsername() {
+		user, err := q.GetUserByUsername(ctx, username)
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "user %q not found", username)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "AddMembers: lookup user %q: %v", username, err)
+		}
+
+		first, second := lib.OrderedUUIDPair(callerID, user.UserID)
+		friendship, err := q.GetFriendship(ctx, db.GetFriendshipParams{
+			User1Userid: first,
+			User2Userid: second,
+		})
+		if err == sql.ErrNoRows || (err == nil && friendship.Status != db.FriendshipStatusAccepted) {
+			return nil, status.Errorf(codes.PermissionDenied, "user %q is not a friend", username)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "AddMembers: check friendship: %v", err)
+		}
+
+		if _, err := q.AddMemberWithRole(ctx, db.AddMemberWithRoleParams{
+			ConversationID: req.GetConversationId(),
+			UserID:         user.UserID,
+			Role:           db.MemberRoleMember,
+		}); lib.IsPgUniqueViolation(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "user %q is already a member", username)
+		} else if err != nil {
+			return nil, status.Errorf(codes.Internal, "AddMembers: add member %q: %v", username, err)
+		}
+		targets = append(targets, lib.SystemEventUser{UserID: user.UserID.String(), Username: user.UserName})
+		addedIDs = append(addedIDs, user.UserID)
+	}
+
+	push, err := recordSystemEvent(ctx, q, req.GetConversationId(), lib.SystemEvent{
+		Kind:    lib.SystemEventMembersAdded,
+		Actor:   callerEventUser(ctx, q),
+		Targets: targets,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "AddMembers: record system event: %v", err)
+	}
+
+	for _, memberID := range addedIDs {
+		if err := notifyGroupMember(ctx, q, s.notif, req.GetConversationId(), memberID, db.NotificationTypeAddedToGroup, "%s added you to %s"); err != nil {
+			return nil, status.Errorf(codes.Internal, "AddMembers: create notification: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "AddMembers: commit: %v", err)
+	}
+	push.publish(s.notif)
+
+	return &pb.UpdateConversationResponse{}, nil
+}
+
+// RemoveMember removes a member from a group conversation.
+// Passing the caller's own username leaves the conversation (the owner cannot
+// leave); removing anyone else requires admin or owner, and only the owner may
+// remove an admin.
+func (s *ChatServer) RemoveMember(ctx context.Context, req *pb.RemoveMemberRequest) (*pb.UpdateConversationResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetUsername() == "" {
+		return nil, status.Error(codes.InvalidArgument, "username is required")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveMember: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	self, err := requireGroupRole(ctx, q, req.GetConversationId(), callerID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := q.GetUserByUsername(ctx, req.GetUsername())
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "user %q not found", req.GetUsername())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveMember: lookup user: %v", err)
+	}
+
+	targetMember, err := q.GetConversationMember(ctx, db.GetConversationMemberParams{
+		ConversationID: req.GetConversationId(),
+		UserID:         target.UserID,
+	})
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "user %q is not a member", req.GetUsername())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveMember: get target membership: %v", err)
+	}
+
+	event := lib.SystemEvent{
+		Kind:    lib.SystemEventMemberRemoved,
+		Actor:   callerEventUser(ctx, q),
+		Targets: []lib.SystemEventUser{{UserID: target.UserID.String(), Username: target.UserName}},
+	}
+
+	switch {
+	case target.UserID == callerID && self.Role == db.MemberRoleOwner:
+		return nil, status.Error(codes.FailedPrecondition, "the owner cannot leave the conversation")
+	case target.UserID == callerID:
+		event.Kind = lib.SystemEventMemberLeft
+		event.Targets = nil
+	case self.Role == db.MemberRoleMember:
+		return nil, status.Error(codes.PermissionDenied, "only admins and the owner can remove members")
+	case targetMember.Role == db.MemberRoleOwner:
+		return nil, status.Error(codes.PermissionDenied, "the owner cannot be removed")
+	case self.Role == db.MemberRoleAdmin && targetMember.Role == db.MemberRoleAdmin:
+		return nil, status.Error(codes.PermissionDenied, "only the owner can remove an admin")
+	}
+
+	if err := q.RemoveMemberFromConversation(ctx, db.RemoveMemberFromConversationParams{
+		ConversationID: req.GetConversationId(),
+		UserID:         target.UserID,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveMember: delete: %v", err)
+	}
+
+	// The removed user no longer appears in the member list, so deliver the
+	// event to them explicitly.
+	push, err := recordSystemEvent(ctx, q, req.GetConversationId(), event, target.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveMember: record system event: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveMember: commit: %v", err)
+	}
+	push.publish(s.notif)
+
+	return &pb.UpdateConversationResponse{}, nil
+}
+
+// RenameConversation changes the name of a group conversation.
+// Only admins and the owner may rename.
+func (s *ChatServer) RenameConversation(ctx context.Context, req *pb.RenameConversationRequest) (*pb.UpdateConversationResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "RenameConversation: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	if _, err := requireGroupRole(ctx, q, req.GetConversationId(), callerID, db.MemberRoleAdmin, db.MemberRoleOwner); err != nil {
+		return nil, err
+	}
+
+	if err := q.RenameConversation(ctx, db.RenameConversationParams{
+		ID:   req.GetConversationId(),
+		Name: sql.NullString{Valid: true, String: req.GetName()},
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "RenameConversation: update: %v", err)
+	}
+
+	push, err := recordSystemEvent(ctx, q, req.GetConversationId(), lib.SystemEvent{
+		Kind:  lib.SystemEventRenamed,
+		Actor: callerEventUser(ctx, q),
+		Name:  req.GetName(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "RenameConversation: record system event: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "RenameConversation: commit: %v", err)
+	}
+	push.publish(s.notif)
+
+	return &pb.UpdateConversationResponse{}, nil
+}
+
+// SetSlowMode sets the minimum gap between two messages from the same member
+// of a group. Only admins and the owner may change it, and they are exempt from it.
+func (s *ChatServer) SetSlowMode(ctx context.Context, req *pb.SetSlowModeRequest) (*pb.UpdateConversationResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seconds := req.GetSeconds()
+	if seconds < 0 || seconds > maxSlowModeSeconds {
+		return nil, status.Errorf(codes.InvalidArgument, "seconds must be between 0 and %d", maxSlowModeSeconds)
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SetSlowMode: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	if _, err := requireGroupRole(ctx, q, req.GetConversationId(), callerID, db.MemberRoleAdmin, db.MemberRoleOwner); err != nil {
+		return nil, err
+	}
+
+	if _, err := q.SetSlowMode(ctx, db.SetSlowModeParams{
+		ConversationID:  req.GetConversationId(),
+		SlowModeSeconds: seconds,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "SetSlowMode: update: %v", err)
+	}
+
+	push, err := recordSystemEvent(ctx, q, req.GetConversationId(), lib.SystemEvent{
+		Kind:            lib.SystemEventSlowMode,
+		Actor:           callerEventUser(ctx, q),
+		SlowModeSeconds: &seconds,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SetSlowMode: record system event: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "SetSlowMode: commit: %v", err)
+	}
+	push.publish(s.notif)
+
+	return &pb.UpdateConversationResponse{}, nil
+}
+
+// SetMemberRole promotes a member to admin or demotes an admin to member.
+// Only the owner may change roles; ownership itself cannot be assigned here.
+func (s *ChatServer) SetMemberRole(ctx context.Context, req *pb.SetMemberRoleRequest) (*pb.UpdateConversationResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetUsername() == "" {
+		return nil, status.Error(codes.InvalidArgument, "username is required")
+	}
+
+	role := db.MemberRole(req.GetRole())
+	if role != db.MemberRoleMember && role != db.MemberRoleAdmin {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role %q (must be 'member' or 'admin')", req.GetRole())
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SetMemberRole: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	if _, err := requireGroupRole(ctx, q, req.GetConversationId(), callerID, db.MemberRoleOwner); err != nil {
+		return nil, err
+	}
+
+	target, err := q.GetUserByUsername(ctx, req.GetUsername())
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "user %q not found", req.GetUsername())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SetMemberRole: lookup user: %v", err)
+	}
+
+	targetMember, err := q.GetConversationMember(ctx, db.GetConversationMemberParams{
+		ConversationID: req.GetConversationId(),
+		UserID:         target.UserID,
+	})
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "user %q is not a member", req.GetUsername())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SetMemberRole: get target membership: %v", err)
+	}
+	if targetMember.Role == db.MemberRoleOwner {
+		return nil, status.Error(codes.FailedPrecondition, "the owner's role cannot be changed")
+	}
+	if targetMember.Role == role {
+		return &pb.UpdateConversationResponse{}, nil
+	}
+
+	if err := q.UpdateMemberRole(ctx, db.UpdateMemberRoleParams{
+		ConversationID: req.GetConversationId(),
+		UserID:         target.UserID,
+		Role:           role,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "SetMemberRole: update: %v", err)
+	}
+
+	push, err := recordSystemEvent(ctx, q, req.GetConversationId(), lib.SystemEvent{
+		Kind:    lib.SystemEventRoleChanged,
+		Actor:   callerEventUser(ctx, q),
+		Targets: []lib.SystemEventUser{{UserID: target.UserID.String(), Username: target.UserName}},
+		Role:    string(role),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SetMemberRole: record system event: %v", err)
+	}
+
+	if err := notifyGroupMember(ctx, q, s.notif, req.GetConversationId(), target.UserID, db.NotificationTypeRoleChanged, "%s made you "+roleArticle(role)+" in %s"); err != nil {
+		return nil, status.Errorf(codes.Internal, "SetMemberRole: create notification: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "SetMemberRole: commit: %v", err)
+	}
+	push.publish(s.notif)
+
+	return &pb.UpdateConversationResponse{}, nil
+}
+
+// ClearHistory hides every current message in a conversation from the caller.
+// Other members keep their history; new messages show up as usual.
+func (s *ChatServer) ClearHistory(ctx context.Context, req *pb.ClearHistoryRequest) (*pb.UpdateConversationResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetConversationId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "conversation_id is required")
+	}
+
+	q := db.New(s.sqlDB)
+
+	isMember, err := q.IsMember(ctx, db.IsMemberParams{
+		ConversationID: req.GetConversationId(),
+		UserID:         callerID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ClearHistory: check membership: %v", err)
+	}
+	if !isMember {
+		return nil, status.Error(codes.PermissionDenied, "caller is not a member of this conversation")
+	}
+
+	if err := q.ClearConversationHistory(ctx, db.ClearConversationHistoryParams{
+		ConversationID: req.GetConversationId(),
+		UserID:         callerID,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "ClearHistory: update: %v", err)
+	}
+
+	return &pb.UpdateConversationResponse{}, nil
+}
+
+// DeleteConversation removes a conversation from the caller's view.
+// For a DM the caller's history is cleared and the conversation is hidden; the
+// dm_peers row is kept, so the same conversation comes back when either side
+// writes again. The owner of a group deletes it for every member after pushing
+// a "deleted" system event; any other member leaves it instead, as if they had
+// called RemoveMember on themselves.
+func (s *ChatServer) DeleteConversation(ctx context.Context, req *pb.DeleteConversationRequest) (*pb.UpdateConversationResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetConversationId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "conversation_id is required")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DeleteConversation: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	self, err := q.GetConversationMember(ctx, db.GetConversationMemberParams{
+		ConversationID: req.GetConversationId(),
+		UserID:         callerID,
+	})
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.PermissionDenied, "caller is not a member of this conversation")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DeleteConversation: get membership: %v", err)
+	}
+
+	conv, err := q.GetConversation(ctx, req.GetConversationId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DeleteConversation: get conversation: %v", err)
+	}
+
+	var push systemEventPush
+	var archives []sql.NullString
+	if !conv.IsGroup {
+		if err := q.HideConversation(ctx, db.HideConversationParams{
+			ConversationID: req.GetConversationId(),
+			UserID:         callerID,
+		}); err != nil {
+			return nil, status.Errorf(codes.Internal, "DeleteConversation: hide: %v", err)
+		}
+	} else if self.Role != db.MemberRoleOwner {
+		// Dropping the membership row also takes the group out of the
+		// caller's conversation list, so there is nothing left to hide.
+		if err := q.RemoveMemberFromConversation(ctx, db.RemoveMemberFromConversationParams{
+			ConversationID: req.GetConversationId(),
+			UserID:         callerID,
+		}); err != nil {
+			return nil, status.Errorf(codes.Internal, "DeleteConversation: leave: %v", err)
+		}
+
+		// The caller is no longer a member, so deliver the event to their
+		// other devices explicitly.
+		push, err = recordSystemEvent(ctx, q, req.GetConversationId(), lib.SystemEvent{
+			Kind:  lib.SystemEventMemberLeft,
+			Actor: callerEventUser(ctx, q),
+		}, callerID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "DeleteConversation: record system event: %v", err)
+		}
+	} else {
+		// The stored event goes away with the conversation below; members
+		// still receive it live so their clients can drop the conversation.
+		push, err = recordSystemEvent(ctx, q, req.GetConversationId(), lib.SystemEvent{
+			Kind:  lib.SystemEventDeleted,
+			Actor: callerEventUser(ctx, q),
+			Name:  conv.Name.String,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "DeleteConversation: record system event: %v", err)
+		}
+
+		// Export jobs would cascade away with the conversation, leaving
+		// their archives on disk with no row pointing at them.
+		archives, err = q.DeleteConversationExports(ctx, req.GetConversationId())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "DeleteConversation: delete exports: %v", err)
+		}
+		if err := q.DeleteConversation(ctx, req.GetConversationId()); err != nil {
+			return nil, status.Errorf(codes.Internal, "DeleteConversation: delete: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "DeleteConversation: commit: %v", err)
+	}
+	push.publish(s.notif)
+	removeArchives(archives)
+
+	return &pb.UpdateConversationResponse{}, nil
+}
+
+// ExportConversation streams an archive of the conversation to a member.
+// Conversations larger than ExportStreamLimit messages are rejected with
+// FailedPrecondition; use StartConversationExport for those.
+func (s *ChatServer) ExportConversation(req *pb.ExportConversationRequest, stream pb.Chat_ExportConversationServer) error {
+	ctx := stream.Context()
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if req.GetConversationId() == 0 {
+		return status.Error(codes.InvalidArgument, "conversation_id is required")
+	}
+
+	err = exportSnapshot(ctx, s.sqlDB, func(q *db.Queries) error {
+		isMember, err := q.IsMember(ctx, db.IsMemberParams{
+			ConversationID: req.GetConversationId(),
+			UserID:         callerID,
+		})
+		if err != nil {
+			return status.Errorf(codes.Internal, "ExportConversation: check membership: %v", err)
+		}
+		if !isMember {
+			return status.Error(codes.PermissionDenied, "caller is not a member of this conversation")
+		}
+
+		count, err := q.CountConversationMessages(ctx, req.GetConversationId())
+		if err != nil {
+			return status.Errorf(codes.Internal, "ExportConversation: count messages: %v", err)
+		}
+		if count > ExportStreamLimit {
+			return status.Errorf(codes.FailedPrecondition, "conversation has %d messages; use StartConversationExport for more than %d", count, ExportStreamLimit)
+		}
+
+		if err := writeConversationArchive(ctx, q, req.GetConversationId(), callerEventUser(ctx, q), exportChunkWriter{stream}); err != nil {
+			return status.Errorf(codes.Internal, "ExportConversation: %v", err)
+		}
+		return nil
+	})
+	if _, ok := status.FromError(err); !ok {
+		return status.Errorf(codes.Internal, "ExportConversation: %v", err)
+	}
+	return err
+}
+
+// StartConversationExport queues a background job that writes the archive
+// to storage. Poll GetConversationExport for the download link.
+func (s *ChatServer) StartConversationExport(ctx context.Context, req *pb.StartConversationExportRequest) (*pb.ConversationExport, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetConversationId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "conversation_id is required")
+	}
+
+	q := db.New(s.sqlDB)
+
+	isMember, err := q.IsMember(ctx, db.IsMemberParams{
+		ConversationID: req.GetConversationId(),
+		UserID:         callerID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "StartConversationExport: check membership: %v", err)
+	}
+	if !isMember {
+		return nil, status.Error(codes.PermissionDenied, "caller is not a member of this conversation")
+	}
+
+	job, err := q.CreateConversationExport(ctx, db.CreateConversationExportParams{
+		ConversationID: req.GetConversationId(),
+		UserID:         callerID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "StartConversationExport: create job: %v", err)
+	}
+
+	return conversationExportToPB(job), nil
+}
+
+// GetConversationExport returns the state of one of the caller's export jobs.
+func (s *ChatServer) GetConversationExport(ctx context.Context, req *pb.GetConversationExportRequest) (*pb.ConversationExport, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exportID, err := uuid.Parse(req.GetExportId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid export_id: %v", err)
+	}
+
+	job, err := db.New(s.sqlDB).GetConversationExport(ctx, db.GetConversationExportParams{
+		ID:     exportID,
+		UserID: callerID,
+	})
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.NotFound, "export not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "GetConversationExport: %v", err)
+	}
+
+	return conversationExportToPB(job), nil
+}
+
+// DownloadConversationExport streams a finished archive. It is a public
+// method: the unguessable, expiring token in the download link is the
+// credential.
+func (s *ChatServer) DownloadConversationExport(req *pb.DownloadConversationExportRequest, stream pb.Chat_DownloadConversationExportServer) error {
+	ctx := stream.Context()
+
+	if req.GetToken() == "" {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	job, err := db.New(s.sqlDB).GetConversationExportByToken(ctx, sql.NullString{Valid: true, String: req.GetToken()})
+	if err == sql.ErrNoRows {
+		return status.Error(codes.NotFound, "export not found or link expired")
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "DownloadConversationExport: %v", err)
+	}
+
+	f, err := os.Open(job.FilePath.String)
+	if err != nil {
+		return status.Errorf(codes.Internal, "DownloadConversationExport: open archive: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(exportChunkWriter{stream}, f); err != nil {
+		return status.Errorf(codes.Internal, "DownloadConversationExport: send: %v", err)
+	}
+	return nil
+}
+
+// requireGroupRole loads the caller's membership in a group conversation.
+// If roles is non-empty the caller's role must be one of them.
+func requireGroupRole(ctx context.Context, q *db.Queries, conversationID int64, callerID uuid.UUID, roles ...db.MemberRole) (db.ConversationMember, error) {
+	if conversationID == 0 {
+		return db.ConversationMember{}, status.Error(codes.InvalidArgument, "conversation_id is required")
+	}
+
+	self, err := q.GetConversationMember(ctx, db.GetConversationMemberParams{
+		ConversationID: conversationID,
+		UserID:         callerID,
+	})
+	if err == sql.ErrNoRows {
+		return db.ConversationMember{}, status.Error(codes.PermissionDenied, "caller is not a member of this conversation")
+	}
+	if err != nil {
+		return db.ConversationMember{}, status.Errorf(codes.Internal, "get membership: %v", err)
+	}
+
+	conv, err := q.GetConversation(ctx, conversationID)
+	if err != nil {
+		return db.ConversationMember{}, status.Errorf(codes.Internal, "get conversation: %v", err)
+	}
+	if !conv.IsGroup {
+		return db.ConversationMember{}, status.Error(codes.FailedPrecondition, "conversation is not a group")
+	}
+
+	if len(roles) == 0 {
+		return self, nil
+	}
+	for _, r := range roles {
+		if self.Role == r {
+			return self, nil
+		}
+	}
+	return db.ConversationMember{}, status.Errorf(codes.PermissionDenied, "caller's role %q is not allowed to do this", self.Role)
+}
+
+// isBlockedEither reports whether a and b have blocked each other in either
+// direction. Lookup errors count as blocked, so receipts and other presence
+// signals fail closed.
+func isBlockedEither(ctx context.Context, q *db.Queries, a, b uuid.UUID) bool {
+	block, err := q.GetBlockState(ctx, db.GetBlockStateParams{UserID: a, OtherID: b})
+	if err != nil {
+		return true
+	}
+	return block.HasBlocked || block.IsBlockedBy
+}
+
+// expandLabelMembers appends the usernames of the friends in the caller's
+// label req.LabelId to req.MembersUsername, skipping names already listed.
+func expandLabelMembers(ctx context.Context, q *db.Queries, callerID uuid.UUID, req *pb.CreateConversationRequest) error {
+	_, err := q.GetFriendLabel(ctx, db.GetFriendLabelParams{ID: req.GetLabelId(), OwnerID: callerID})
+	if err == sql.ErrNoRows {
+		return status.Error(codes.NotFound, "label not found")
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "CreateConversation: get label: %v", err)
+	}
+
+	usernames, err := q.ListFriendLabelMemberUsernames(ctx, req.GetLabelId())
+	if err != nil {
+		return status.Errorf(codes.Internal, "CreateConversation: list label members: %v", err)
+	}
+
+	listed := make(map[string]bool, len(req.MembersUsername))
+	for _, username := range req.MembersUsername {
+		listed[username] = true
+	}
+	for _, username := range usernames {
+		if !listed[username] {
+			req.MembersUsername = append(req.MembersUsername, username)
+			listed[username] = true
+		}
+	}
+	return nil
+}
+
+// callerEventUser returns the authenticated caller as a SystemEventUser.
+func callerEventUser(ctx context.Context, q *db.Queries) lib.SystemEventUser {
+	return lib.SystemEventUser{UserID: lib.CallerIDFrom(ctx), Username: callerName(ctx, q)}
+}
+
+// callerName returns the caller's current username. The name in the token
+// goes stale after a rename, so it is only used if the lookup fails.
+func callerName(ctx context.Context, q *db.Queries) string {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return lib.CallerFrom(ctx)
+	}
+	user, err := q.GetUserByID(ctx, callerID)
+	if err != nil {
+		return lib.CallerFrom(ctx)
+	}
+	return user.UserName
+}
+
+// notifyGroupMember sends userID a notification of type typ about the group
+// conversationID using q (which may wrap an active transaction). format is
+// filled with the caller's username and the group's name; the notification
+// references the conversation.
+func notifyGroupMember(ctx context.Context, q *db.Queries, notif *NotificationServer, conversationID int64, userID uuid.UUID, typ db.NotificationType, format string) error {
+	if notif == nil {
+		return nil
+	}
+
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return err
+	}
+	conv, err := q.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+
+	return notif.Send(ctx, q, db.CreateNotificationParams{
+		UserID:                  userID,
+		SenderID:                uuid.NullUUID{Valid: true, UUID: callerID},
+		Type:                    typ,
+		Message:                 fmt.Sprintf(format, callerName(ctx, q), conv.Name.String),
+		ReferenceConversationID: sql.NullInt64{Valid: true, Int64: conversationID},
+	})
+}
+
+// roleArticle returns role with its indefinite article, e.g. "an admin".
+func roleArticle(role db.MemberRole) string {
+	if role == db.MemberRoleAdmin || role == db.MemberRoleOwner {
+		return "an " + string(role)
+	}
+	return "a " + string(role)
+}
+
+// systemEventPush is a stored system event waiting to be pushed live. It is
+// built inside the transaction that stores the event and published only after
+// that transaction commits, so clients never see an event that rolled back.
+type systemEventPush struct {
+	envelope   []byte
+	recipients []uuid.UUID
+}
+
+// publish pushes the event to every recipient as a ChatEventSystem envelope.
+// Failures are logged only — the stored message is still returned by history
+// paging. The zero value publishes nothing.
+func (p systemEventPush) publish(notif *NotificationServer) {
+	for _, userID := range p.recipients {
+		if err := notif.publishIfOnline(userID, lib.ChatSubjectPrefix, p.envelope); err != nil {
+			lib.ErrorLog.Printf("system event: publish to %s: %v", userID, err)
+		}
+	}
+}
+
+// recordSystemEvent stores event as a "system" message in the conversation
+// using q (which may wrap an active transaction) and returns the live push for
+// every current member plus any extra recipients. The caller is recorded as
+// the sender. Call publish on the result once the transaction has committed.
+func recordSystemEvent(ctx context.Context, q *db.Queries, conversationID int64, event lib.SystemEvent, extra ...uuid.UUID) (systemEventPush, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return systemEventPush{}, err
+	}
+	loginID, err := uuid.Parse(lib.CallerLoginID(ctx))
+	if err != nil {
+		return systemEventPush{}, fmt.Errorf("parse login_id: %w", err)
+	}
+
+	content, err := json.Marshal(event)
+	if err != nil {
+		return systemEventPush{}, fmt.Errorf("marshal event: %w", err)
+	}
+
+	// v7 IDs are time-ordered, so system messages sort naturally among others.
+	msgID, err := uuid.NewV7()
+	if err != nil {
+		return systemEventPush{}, fmt.Errorf("generate message id: %w", err)
+	}
+
+	msg, err := q.SendMessage(ctx, db.SendMessageParams{
+		ID:             msgID,
+		ConversationID: conversationID,
+		SenderID:       callerID,
+		SenderLoginID:  loginID,
+		Content:        string(content),
+		MessageType:    db.MessageTypeSystem,
+	})
+	if err != nil {
+		return systemEventPush{}, fmt.Errorf("insert system message: %w", err)
+	}
+
+	members, err := q.GetConversationMembers(ctx, conversationID)
+	if err != nil {
+		return systemEventPush{}, fmt.Errorf("get members: %w", err)
+	}
+
+	envelope, err := lib.NewChatResponseEnvelope(lib.ChatEventSystem, msg)
+	if err != nil {
+		return systemEventPush{}, fmt.Errorf("create envelope: %w", err)
+	}
+
+	recipients := extra
+	for _, m := range members {
+		recipients = append(recipients, m.UserID)
+	}
+	return systemEventPush{envelope: envelope, recipients: recipients}, nil
+}
+
+// maxSlowModeSeconds is the longest slow-mode interval (6 hours), matching
+// the check constraint on conversations.slow_mode_seconds.
+const maxSlowModeSeconds = 21600
+
+const (
+	// maxSearchTokens caps the blind-index tokens per message or per search set.
+	maxSearchTokens = 32
+	// maxSearchTokenLen caps the length of a single token; an encoded HMAC-SHA256 fits easily.
+	maxSearchTokenLen = 128
+	// maxSearchTokenSets caps how many conversations one SearchMessages call covers.
+	maxSearchTokenSets = 100
+)
+
+// normalizeSearchTokens validates client-computed blind-index tokens and
+// drops duplicates. The tokens are opaque to the server and are stored as-is.
+func normalizeSearchTokens(tokens []string) ([]string, error) {
+	if len(tokens) > maxSearchTokens {
+		return nil, fmt.Errorf("at most %d tokens are allowed", maxSearchTokens)
+	}
+	out := make([]string, 0, len(tokens))
+	seen := make(map[string]bool, len(tokens))
+	for _, t := range tokens {
+		if t == "" || len(t) > maxSearchTokenLen {
+			return nil, fmt.Errorf("tokens must be 1-%d characters", maxSearchTokenLen)
+		}
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out, nil
+}
@@ -1,8 +1,12 @@
 package services_test
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +16,7 @@ import (
 	"github.com/zukigit/chat/backend/internal/lib"
 	"github.com/zukigit/chat/backend/internal/services"
 	pb "github.com/zukigit/chat/backend/proto/chat"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 )
 
@@ -252,8 +257,8 @@ func TestSendMessage_Notifications(t *testing.T) {
 		if n.Type != db.NotificationTypeMessage {
 			t.Errorf("type: got %q, want %q", n.Type, db.NotificationTypeMessage)
 		}
-		if !n.ReferenceID.Valid || n.ReferenceID.Int64 != convID {
-			t.Errorf("reference_id: got %v, want %d", n.ReferenceID, convID)
+		if !n.ReferenceConversationID.Valid || n.ReferenceConversationID.Int64 != convID {
+			t.Errorf("reference_conversation_id: got %v, want %d", n.ReferenceConversationID, convID)
 		}
 		if !n.SenderID.Valid || n.SenderID.UUID != ids["alice"] {
 			t.Errorf("sender_id: got %v, want alice (%s)", n.SenderID, ids["alice"])
@@ -295,10 +300,11 @@ func TestSendMessage_Notifications(t *testing.T) {
 			if err != nil {
 				t.Fatalf("GetNotificationsForUser %s: %v", recipient, err)
 			}
-			// Filter to this conversation's notifications only (bob may have one from the DM sub-test above).
+			// Filter to this conversation's message notifications only (bob may have one
+			// from the DM sub-test above, and both get a group_invite).
 			var groupNotifs []db.Notification
 			for _, n := range notifs {
-				if n.ReferenceID.Valid && n.ReferenceID.Int64 == convID {
+				if n.Type == db.NotificationTypeMessage && n.ReferenceConversationID.Valid && n.ReferenceConversationID.Int64 == convID {
 					groupNotifs = append(groupNotifs, n)
 				}
 			}
@@ -420,3 +426,1083 @@ func TestGetConversations(t *testing.T) {
 		}
 	})
 }
+
+func TestGetConversation(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	chatServer := services.NewChatServer(sqlDB, nil)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+
+	groupResp, err := chatServer.CreateConversation(
+		ctxWithUser("alice", ids["alice"]),
+		&pb.CreateConversationRequest{IsGroup: true, Name: "test-group", MembersUsername: []string{"bob"}},
+	)
+	if err != nil {
+		t.Fatalf("setup group: %v", err)
+	}
+	convID := groupResp.ConversationId
+
+	cases := []struct {
+		name    string
+		ctx     context.Context
+		convID  int64
+		wantErr codes.Code
+	}{
+		{"owner", ctxWithUser("alice", ids["alice"]), convID, codes.OK},
+		{"member", ctxWithUser("bob", ids["bob"]), convID, codes.OK},
+		{"non-member", ctxWithUser("carol", ids["carol"]), convID, codes.PermissionDenied},
+		{"unknown conversation", ctxWithUser("alice", ids["alice"]), 999999, codes.PermissionDenied},
+		{"zero conversation_id", ctxWithUser("alice", ids["alice"]), 0, codes.InvalidArgument},
+		{"no auth", context.Background(), convID, codes.Internal},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := chatServer.GetConversation(tc.ctx, &pb.GetConversationRequest{ConversationId: tc.convID})
+			if got := grpcCode(err); got != tc.wantErr {
+				t.Errorf("got %v, want %v (err: %v)", got, tc.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("members carry roles and caller settings", func(t *testing.T) {
+		resp, err := chatServer.GetConversation(ctxWithUser("bob", ids["bob"]), &pb.GetConversationRequest{ConversationId: convID})
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if resp.Name != "test-group" || !resp.IsGroup {
+			t.Errorf("metadata: got name=%q is_group=%v", resp.Name, resp.IsGroup)
+		}
+		roles := make(map[string]string, len(resp.Members))
+		for _, m := range resp.Members {
+			roles[m.Username] = m.Role
+			if m.JoinedAt == "" {
+				t.Errorf("%s: joined_at is empty", m.Username)
+			}
+		}
+		if roles["alice"] != string(db.MemberRoleOwner) {
+			t.Errorf("alice role: got %q, want %q", roles["alice"], db.MemberRoleOwner)
+		}
+		if roles["bob"] != string(db.MemberRoleMember) {
+			t.Errorf("bob role: got %q, want %q", roles["bob"], db.MemberRoleMember)
+		}
+		if resp.Settings.GetRole() != string(db.MemberRoleMember) {
+			t.Errorf("settings role: got %q, want %q", resp.Settings.GetRole(), db.MemberRoleMember)
+		}
+	})
+}
+
+func TestGetMessages(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	js := setupTestNats(t)
+	chatServer := services.NewChatServer(sqlDB, services.NewNotificationServer(sqlDB, js))
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+
+	groupResp, err := chatServer.CreateConversation(
+		ctxWithUser("alice", ids["alice"]),
+		&pb.CreateConversationRequest{IsGroup: true, Name: "test-group", MembersUsername: []string{"bob"}},
+	)
+	if err != nil {
+		t.Fatalf("setup group: %v", err)
+	}
+	convID := groupResp.ConversationId
+
+	for _, content := range []string{"one", "two", "three"} {
+		if _, err := chatServer.SendMessage(
+			ctxWithUser("bob", ids["bob"]),
+			&pb.SendMessageRequest{ConversationId: convID, MessageId: uuid.New().String(), Content: content},
+		); err != nil {
+			t.Fatalf("setup SendMessage: %v", err)
+		}
+	}
+
+	t.Run("history starts with created event", func(t *testing.T) {
+		resp, err := chatServer.GetMessages(ctxWithUser("bob", ids["bob"]), &pb.GetMessagesRequest{ConversationId: convID})
+		if err != nil {
+			t.Fatalf("got error: %v", err)
+		}
+		if len(resp.Messages) != 4 {
+			t.Fatalf("want 4 messages, got %d", len(resp.Messages))
+		}
+		first := resp.Messages[0]
+		if first.MessageType != "system" {
+			t.Fatalf("first message type: got %q, want system", first.MessageType)
+		}
+		var event lib.SystemEvent
+		if err := json.Unmarshal([]byte(first.Content), &event); err != nil {
+			t.Fatalf("unmarshal system event: %v", err)
+		}
+		if event.Kind != lib.SystemEventCreated {
+			t.Errorf("kind: got %q, want %q", event.Kind, lib.SystemEventCreated)
+		}
+		if event.Actor.UserID != ids["alice"].String() {
+			t.Errorf("actor: got %s, want alice", event.Actor.UserID)
+		}
+		if resp.Messages[3].Content != "three" {
+			t.Errorf("last message: got %q, want three", resp.Messages[3].Content)
+		}
+		if resp.NextCursor != "" {
+			t.Errorf("next_cursor: got %q, want empty", resp.NextCursor)
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		page1, err := chatServer.GetMessages(ctxWithUser("alice", ids["alice"]), &pb.GetMessagesRequest{ConversationId: convID, Limit: 2})
+		if err != nil {
+			t.Fatalf("page 1: %v", err)
+		}
+		if len(page1.Messages) != 2 || page1.NextCursor == "" {
+			t.Fatalf("page 1: got %d messages, cursor %q", len(page1.Messages), page1.NextCursor)
+		}
+		page2, err := chatServer.GetMessages(ctxWithUser("alice", ids["alice"]), &pb.GetMessagesRequest{ConversationId: convID, Limit: 2, Cursor: page1.NextCursor})
+		if err != nil {
+			t.Fatalf("page 2: %v", err)
+		}
+		if len(page2.Messages) != 2 {
+			t.Fatalf("page 2: want 2 messages, got %d", len(page2.Messages))
+		}
+		if page2.Messages[0].Content != "two" {
+			t.Errorf("page 2 first: got %q, want two", page2.Messages[0].Content)
+		}
+	})
+
+	otherResp, err := chatServer.CreateConversation(
+		ctxWithUser("alice", ids["alice"]),
+		&pb.CreateConversationRequest{IsGroup: true, Name: "other-group", MembersUsername: []string{"bob"}},
+	)
+	if err != nil {
+		t.Fatalf("setup other group: %v", err)
+	}
+	otherMessageID := uuid.New().String()
+	if _, err := chatServer.SendMessage(
+		ctxWithUser("bob", ids["bob"]),
+		&pb.SendMessageRequest{ConversationId: otherResp.ConversationId, MessageId: otherMessageID, Content: "elsewhere"},
+	); err != nil {
+		t.Fatalf("setup SendMessage: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		ctx     context.Context
+		req     *pb.GetMessagesRequest
+		wantErr codes.Code
+	}{
+		{"non-member", ctxWithUser("carol", ids["carol"]), &pb.GetMessagesRequest{ConversationId: convID}, codes.PermissionDenied},
+		{"zero conversation_id", ctxWithUser("alice", ids["alice"]), &pb.GetMessagesRequest{}, codes.InvalidArgument},
+		{"bad cursor", ctxWithUser("alice", ids["alice"]), &pb.GetMessagesRequest{ConversationId: convID, Cursor: "nope"}, codes.InvalidArgument},
+		{"unknown cursor", ctxWithUser("alice", ids["alice"]), &pb.GetMessagesRequest{ConversationId: convID, Cursor: uuid.New().String()}, codes.InvalidArgument},
+		{"cursor from another conversation", ctxWithUser("alice", ids["alice"]), &pb.GetMessagesRequest{ConversationId: convID, Cursor: otherMessageID}, codes.InvalidArgument},
+		{"no auth", context.Background(), &pb.GetMessagesRequest{ConversationId: convID}, codes.Internal},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := chatServer.GetMessages(tc.ctx, tc.req)
+			if got := grpcCode(err); got != tc.wantErr {
+				t.Errorf("got %v, want %v (err: %v)", got, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGroupManagement(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	chatServer := services.NewChatServer(sqlDB, nil)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol", "dave")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+	makeFriends(t, sqlDB, ids["alice"], ids["carol"])
+	makeFriends(t, sqlDB, ids["bob"], ids["dave"])
+
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+	carol := ctxWithUser("carol", ids["carol"])
+
+	groupResp, err := chatServer.CreateConversation(alice,
+		&pb.CreateConversationRequest{IsGroup: true, Name: "test-group", MembersUsername: []string{"bob"}},
+	)
+	if err != nil {
+		t.Fatalf("setup group: %v", err)
+	}
+	convID := groupResp.ConversationId
+
+	steps := []struct {
+		name    string
+		call    func() error
+		wantErr codes.Code
+	}{
+		{"member cannot add", func() error {
+			_, err := chatServer.AddMembers(bob, &pb.AddMembersRequest{ConversationId: convID, MembersUsername: []string{"dave"}})
+			return err
+		}, codes.PermissionDenied},
+		{"owner adds friend", func() error {
+			_, err := chatServer.AddMembers(alice, &pb.AddMembersRequest{ConversationId: convID, MembersUsername: []string{"carol"}})
+			return err
+		}, codes.OK},
+		{"owner adds existing member", func() error {
+			_, err := chatServer.AddMembers(alice, &pb.AddMembersRequest{ConversationId: convID, MembersUsername: []string{"carol"}})
+			return err
+		}, codes.AlreadyExists},
+		{"member cannot rename", func() error {
+			_, err := chatServer.RenameConversation(bob, &pb.RenameConversationRequest{ConversationId: convID, Name: "x"})
+			return err
+		}, codes.PermissionDenied},
+		{"owner renames", func() error {
+			_, err := chatServer.RenameConversation(alice, &pb.RenameConversationRequest{ConversationId: convID, Name: "renamed"})
+			return err
+		}, codes.OK},
+		{"member cannot promote", func() error {
+			_, err := chatServer.SetMemberRole(bob, &pb.SetMemberRoleRequest{ConversationId: convID, Username: "carol", Role: "admin"})
+			return err
+		}, codes.PermissionDenied},
+		{"invalid role", func() error {
+			_, err := chatServer.SetMemberRole(alice, &pb.SetMemberRoleRequest{ConversationId: convID, Username: "bob", Role: "owner"})
+			return err
+		}, codes.InvalidArgument},
+		{"owner promotes bob", func() error {
+			_, err := chatServer.SetMemberRole(alice, &pb.SetMemberRoleRequest{ConversationId: convID, Username: "bob", Role: "admin"})
+			return err
+		}, codes.OK},
+		{"admin adds own friend", func() error {
+			_, err := chatServer.AddMembers(bob, &pb.AddMembersRequest{ConversationId: convID, MembersUsername: []string{"dave"}})
+			return err
+		}, codes.OK},
+		{"admin cannot remove owner", func() error {
+			_, err := chatServer.RemoveMember(bob, &pb.RemoveMemberRequest{ConversationId: convID, Username: "alice"})
+			return err
+		}, codes.PermissionDenied},
+		{"member cannot remove others", func() error {
+			_, err := chatServer.RemoveMember(carol, &pb.RemoveMemberRequest{ConversationId: convID, Username: "dave"})
+			return err
+		}, codes.PermissionDenied},
+		{"admin removes member", func() error {
+			_, err := chatServer.RemoveMember(bob, &pb.RemoveMemberRequest{ConversationId: convID, Username: "dave"})
+			return err
+		}, codes.OK},
+		{"member leaves", func() error {
+			_, err := chatServer.RemoveMember(carol, &pb.RemoveMemberRequest{ConversationId: convID, Username: "carol"})
+			return err
+		}, codes.OK},
+		{"owner cannot leave", func() error {
+			_, err := chatServer.RemoveMember(alice, &pb.RemoveMemberRequest{ConversationId: convID, Username: "alice"})
+			return err
+		}, codes.FailedPrecondition},
+	}
+	for _, st := range steps {
+		t.Run(st.name, func(t *testing.T) {
+			if got := grpcCode(st.call()); got != st.wantErr {
+				t.Errorf("got %v, want %v", got, st.wantErr)
+			}
+		})
+	}
+
+	t.Run("history records every change", func(t *testing.T) {
+		resp, err := chatServer.GetMessages(alice, &pb.GetMessagesRequest{ConversationId: convID})
+		if err != nil {
+			t.Fatalf("GetMessages: %v", err)
+		}
+		want := []lib.SystemEventKind{
+			lib.SystemEventCreated,
+			lib.SystemEventMembersAdded,
+			lib.SystemEventRenamed,
+			lib.SystemEventRoleChanged,
+			lib.SystemEventMembersAdded,
+			lib.SystemEventMemberRemoved,
+			lib.SystemEventMemberLeft,
+		}
+		if len(resp.Messages) != len(want) {
+			t.Fatalf("want %d system messages, got %d", len(want), len(resp.Messages))
+		}
+		for i, m := range resp.Messages {
+			var event lib.SystemEvent
+			if err := json.Unmarshal([]byte(m.Content), &event); err != nil {
+				t.Fatalf("message %d: unmarshal: %v", i, err)
+			}
+			if event.Kind != want[i] {
+				t.Errorf("message %d: got %q, want %q", i, event.Kind, want[i])
+			}
+		}
+	})
+
+	t.Run("removed member loses access", func(t *testing.T) {
+		_, err := chatServer.GetMessages(carol, &pb.GetMessagesRequest{ConversationId: convID})
+		if got := grpcCode(err); got != codes.PermissionDenied {
+			t.Errorf("got %v, want PermissionDenied", got)
+		}
+	})
+}
+
+// exportStream is a minimal server stream that collects exported chunks.
+type exportStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	buf bytes.Buffer
+}
+
+func (s *exportStream) Context() context.Context { return s.ctx }
+
+func (s *exportStream) Send(c *pb.ExportChunk) error {
+	s.buf.Write(c.Data)
+	return nil
+}
+
+// archiveRecordCounts parses a newline-delimited archive and counts records by type.
+func archiveRecordCounts(t *testing.T, data []byte) map[lib.ArchiveRecordType]int {
+	t.Helper()
+	counts := map[lib.ArchiveRecordType]int{}
+	for i, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		var record struct {
+			Type lib.ArchiveRecordType `json:"type"`
+		}
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("line %d: unmarshal: %v", i, err)
+		}
+		if i == 0 && record.Type != lib.ArchiveRecordHeader {
+			t.Fatalf("first record: got %q, want header", record.Type)
+		}
+		counts[record.Type]++
+	}
+	return counts
+}
+
+func TestExportConversation(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	js := setupTestNats(t)
+	chatServer := services.NewChatServer(sqlDB, services.NewNotificationServer(sqlDB, js))
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+
+	alice := ctxWithUser("alice", ids["alice"])
+
+	groupResp, err := chatServer.CreateConversation(alice,
+		&pb.CreateConversationRequest{IsGroup: true, Name: "test-group", MembersUsername: []string{"bob"}},
+	)
+	if err != nil {
+		t.Fatalf("setup group: %v", err)
+	}
+	convID := groupResp.ConversationId
+
+	for _, content := range []string{"one", "two"} {
+		if _, err := chatServer.SendMessage(alice,
+			&pb.SendMessageRequest{ConversationId: convID, MessageId: uuid.New().String(), Content: content},
+		); err != nil {
+			t.Fatalf("setup SendMessage: %v", err)
+		}
+	}
+
+	// created system event + two messages
+	wantCounts := map[lib.ArchiveRecordType]int{
+		lib.ArchiveRecordHeader:  1,
+		lib.ArchiveRecordMember:  2,
+		lib.ArchiveRecordReceipt: 2,
+		lib.ArchiveRecordMessage: 3,
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		stream := &exportStream{ctx: alice}
+		if err := chatServer.ExportConversation(&pb.ExportConversationRequest{ConversationId: convID}, stream); err != nil {
+			t.Fatalf("ExportConversation: %v", err)
+		}
+		got := archiveRecordCounts(t, stream.buf.Bytes())
+		for typ, want := range wantCounts {
+			if got[typ] != want {
+				t.Errorf("%s records: got %d, want %d", typ, got[typ], want)
+			}
+		}
+	})
+
+	t.Run("stream non-member", func(t *testing.T) {
+		stream := &exportStream{ctx: ctxWithUser("carol", ids["carol"])}
+		err := chatServer.ExportConversation(&pb.ExportConversationRequest{ConversationId: convID}, stream)
+		if got := grpcCode(err); got != codes.PermissionDenied {
+			t.Errorf("got %v, want PermissionDenied", got)
+		}
+	})
+
+	t.Run("background job", func(t *testing.T) {
+		job, err := chatServer.StartConversationExport(alice, &pb.StartConversationExportRequest{ConversationId: convID})
+		if err != nil {
+			t.Fatalf("StartConversationExport: %v", err)
+		}
+		if job.Status != "pending" {
+			t.Fatalf("status: got %q, want pending", job.Status)
+		}
+
+		worker := services.NewExportWorker(sqlDB, t.TempDir(), time.Hour)
+		if ok, err := worker.ProcessNext(context.Background()); !ok || err != nil {
+			t.Fatalf("ProcessNext: ok=%v err=%v", ok, err)
+		}
+
+		job, err = chatServer.GetConversationExport(alice, &pb.GetConversationExportRequest{ExportId: job.ExportId})
+		if err != nil {
+			t.Fatalf("GetConversationExport: %v", err)
+		}
+		if job.Status != "done" || job.DownloadUrl == "" || job.ExpiresAt == "" {
+			t.Fatalf("job not finished: %+v", job)
+		}
+
+		_, downloadToken, _ := strings.Cut(job.DownloadUrl, "token=")
+		stream := &exportStream{ctx: context.Background()}
+		if err := chatServer.DownloadConversationExport(&pb.DownloadConversationExportRequest{Token: downloadToken}, stream); err != nil {
+			t.Fatalf("DownloadConversationExport: %v", err)
+		}
+		got := archiveRecordCounts(t, stream.buf.Bytes())
+		if got[lib.ArchiveRecordMessage] != wantCounts[lib.ArchiveRecordMessage] {
+			t.Errorf("message records: got %d, want %d", got[lib.ArchiveRecordMessage], wantCounts[lib.ArchiveRecordMessage])
+		}
+
+		_, err = chatServer.GetConversationExport(ctxWithUser("bob", ids["bob"]), &pb.GetConversationExportRequest{ExportId: job.ExportId})
+		if got := grpcCode(err); got != codes.NotFound {
+			t.Errorf("other user's job: got %v, want NotFound", got)
+		}
+	})
+
+	t.Run("abandoned job is claimed again", func(t *testing.T) {
+		job, err := chatServer.StartConversationExport(alice, &pb.StartConversationExportRequest{ConversationId: convID})
+		if err != nil {
+			t.Fatalf("StartConversationExport: %v", err)
+		}
+		// A worker that claimed the job just now still holds it.
+		if _, err := sqlDB.Exec(`UPDATE conversation_exports SET status = 'running', started_at = NOW() WHERE id = $1`, job.ExportId); err != nil {
+			t.Fatalf("claim job: %v", err)
+		}
+		worker := services.NewExportWorker(sqlDB, t.TempDir(), time.Hour)
+		if ok, err := worker.ProcessNext(context.Background()); ok || err != nil {
+			t.Fatalf("ProcessNext on a held job: ok=%v err=%v", ok, err)
+		}
+
+		// One whose worker died an hour ago has lost its lease.
+		if _, err := sqlDB.Exec(`UPDATE conversation_exports SET started_at = NOW() - INTERVAL '1 hour' WHERE id = $1`, job.ExportId); err != nil {
+			t.Fatalf("age claim: %v", err)
+		}
+		if ok, err := worker.ProcessNext(context.Background()); !ok || err != nil {
+			t.Fatalf("ProcessNext: ok=%v err=%v", ok, err)
+		}
+		job, err = chatServer.GetConversationExport(alice, &pb.GetConversationExportRequest{ExportId: job.ExportId})
+		if err != nil {
+			t.Fatalf("GetConversationExport: %v", err)
+		}
+		if job.Status != "done" {
+			t.Errorf("status: got %q, want done", job.Status)
+		}
+	})
+
+	t.Run("bad download token", func(t *testing.T) {
+		err := chatServer.DownloadConversationExport(&pb.DownloadConversationExportRequest{Token: "nope"}, &exportStream{ctx: context.Background()})
+		if got := grpcCode(err); got != codes.NotFound {
+			t.Errorf("got %v, want NotFound", got)
+		}
+	})
+
+	t.Run("former sender", func(t *testing.T) {
+		bob := ctxWithUser("bob", ids["bob"])
+		if _, err := chatServer.SendMessage(bob,
+			&pb.SendMessageRequest{ConversationId: convID, MessageId: uuid.New().String(), Content: "bye"},
+		); err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+		if _, err := chatServer.RemoveMember(bob, &pb.RemoveMemberRequest{ConversationId: convID, Username: "bob"}); err != nil {
+			t.Fatalf("RemoveMember: %v", err)
+		}
+
+		stream := &exportStream{ctx: alice}
+		if err := chatServer.ExportConversation(&pb.ExportConversationRequest{ConversationId: convID}, stream); err != nil {
+			t.Fatalf("ExportConversation: %v", err)
+		}
+		var members []lib.ArchiveMember
+		for _, line := range bytes.Split(bytes.TrimSpace(stream.buf.Bytes()), []byte("\n")) {
+			var record struct {
+				Type lib.ArchiveRecordType `json:"type"`
+				Data lib.ArchiveMember     `json:"data"`
+			}
+			if err := json.Unmarshal(line, &record); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if record.Type == lib.ArchiveRecordMember {
+				members = append(members, record.Data)
+			}
+		}
+		if len(members) != 2 {
+			t.Fatalf("member records: got %d, want 2", len(members))
+		}
+		if last := members[1]; last.UserID != ids["bob"].String() || !last.Former || last.Role != "" {
+			t.Errorf("last member record: got %+v, want bob as a former member", last)
+		}
+	})
+}
+
+func TestClearHistoryAndDeleteConversation(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	js := setupTestNats(t)
+	chatServer := services.NewChatServer(sqlDB, services.NewNotificationServer(sqlDB, js))
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+	makeFriends(t, sqlDB, ids["alice"], ids["carol"])
+
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+	carol := ctxWithUser("carol", ids["carol"])
+
+	send := func(ctx context.Context, convID int64, content string) {
+		t.Helper()
+		if _, err := chatServer.SendMessage(ctx,
+			&pb.SendMessageRequest{ConversationId: convID, MessageId: uuid.New().String(), Content: content},
+		); err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+	}
+	history := func(ctx context.Context, convID int64) int {
+		t.Helper()
+		resp, err := chatServer.GetMessages(ctx, &pb.GetMessagesRequest{ConversationId: convID})
+		if err != nil {
+			t.Fatalf("GetMessages: %v", err)
+		}
+		return len(resp.Messages)
+	}
+	conversations := func(ctx context.Context) int {
+		t.Helper()
+		resp, err := chatServer.GetConversations(ctx, &pb.GetConversationsRequest{})
+		if err != nil {
+			t.Fatalf("GetConversations: %v", err)
+		}
+		return len(resp.Conversations)
+	}
+
+	dmResp, err := chatServer.CreateConversation(alice,
+		&pb.CreateConversationRequest{IsGroup: false, MembersUsername: []string{"bob"}},
+	)
+	if err != nil {
+		t.Fatalf("setup DM: %v", err)
+	}
+	dmID := dmResp.ConversationId
+	send(alice, dmID, "one")
+	send(bob, dmID, "two")
+
+	t.Run("clear history hides only for the caller", func(t *testing.T) {
+		if _, err := chatServer.ClearHistory(alice, &pb.ClearHistoryRequest{ConversationId: dmID}); err != nil {
+			t.Fatalf("ClearHistory: %v", err)
+		}
+		if got := history(alice, dmID); got != 0 {
+			t.Errorf("alice history: got %d, want 0", got)
+		}
+		if got := history(bob, dmID); got != 2 {
+			t.Errorf("bob history: got %d, want 2", got)
+		}
+		send(bob, dmID, "three")
+		if got := history(alice, dmID); got != 1 {
+			t.Errorf("alice history after new message: got %d, want 1", got)
+		}
+	})
+
+	t.Run("deleted DM comes back on the same conversation", func(t *testing.T) {
+		if _, err := chatServer.DeleteConversation(bob, &pb.DeleteConversationRequest{ConversationId: dmID}); err != nil {
+			t.Fatalf("DeleteConversation: %v", err)
+		}
+		if got := conversations(bob); got != 0 {
+			t.Fatalf("bob conversations after delete: got %d, want 0", got)
+		}
+		if got := conversations(alice); got != 1 {
+			t.Errorf("alice conversations: got %d, want 1", got)
+		}
+
+		send(alice, dmID, "are you there?")
+		if got := conversations(bob); got != 1 {
+			t.Fatalf("bob conversations after new message: got %d, want 1", got)
+		}
+		if got := history(bob, dmID); got != 1 {
+			t.Errorf("bob history: got %d, want 1", got)
+		}
+
+		again, err := chatServer.CreateConversation(bob,
+			&pb.CreateConversationRequest{IsGroup: false, MembersUsername: []string{"alice"}},
+		)
+		if err != nil {
+			t.Fatalf("CreateConversation: %v", err)
+		}
+		if again.ConversationId != dmID {
+			t.Errorf("conversation_id: got %d, want existing %d", again.ConversationId, dmID)
+		}
+	})
+
+	groupResp, err := chatServer.CreateConversation(alice,
+		&pb.CreateConversationRequest{IsGroup: true, Name: "test-group", MembersUsername: []string{"bob", "carol"}},
+	)
+	if err != nil {
+		t.Fatalf("setup group: %v", err)
+	}
+	groupID := groupResp.ConversationId
+
+	t.Run("member deleting a group leaves it", func(t *testing.T) {
+		if got := conversations(bob); got != 2 {
+			t.Fatalf("bob conversations before delete: got %d, want 2", got)
+		}
+		if _, err := chatServer.DeleteConversation(bob, &pb.DeleteConversationRequest{ConversationId: groupID}); err != nil {
+			t.Fatalf("DeleteConversation: %v", err)
+		}
+		if got := conversations(bob); got != 1 {
+			t.Errorf("bob conversations after delete: got %d, want 1", got)
+		}
+		_, err := chatServer.GetMessages(bob, &pb.GetMessagesRequest{ConversationId: groupID})
+		if got := grpcCode(err); got != codes.PermissionDenied {
+			t.Errorf("bob GetMessages: got %v, want PermissionDenied", got)
+		}
+
+		resp, err := chatServer.GetMessages(alice, &pb.GetMessagesRequest{ConversationId: groupID})
+		if err != nil {
+			t.Fatalf("alice GetMessages: %v", err)
+		}
+		var event lib.SystemEvent
+		if err := json.Unmarshal([]byte(resp.Messages[len(resp.Messages)-1].Content), &event); err != nil {
+			t.Fatalf("unmarshal system event: %v", err)
+		}
+		if event.Kind != lib.SystemEventMemberLeft || event.Actor.UserID != ids["bob"].String() {
+			t.Errorf("last event: got %q by %s, want member_left by bob", event.Kind, event.Actor.UserID)
+		}
+	})
+
+	// An archive of the group, which must not outlive it.
+	exportDir := t.TempDir()
+	if _, err := chatServer.StartConversationExport(alice, &pb.StartConversationExportRequest{ConversationId: groupID}); err != nil {
+		t.Fatalf("StartConversationExport: %v", err)
+	}
+	if ok, err := services.NewExportWorker(sqlDB, exportDir, time.Hour).ProcessNext(context.Background()); !ok || err != nil {
+		t.Fatalf("ProcessNext: ok=%v err=%v", ok, err)
+	}
+
+	cases := []struct {
+		name    string
+		ctx     context.Context
+		convID  int64
+		wantErr codes.Code
+	}{
+		{"zero conversation_id", alice, 0, codes.InvalidArgument},
+		{"non-member", carol, dmID, codes.PermissionDenied},
+		{"owner deletes group", alice, groupID, codes.OK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := chatServer.DeleteConversation(tc.ctx, &pb.DeleteConversationRequest{ConversationId: tc.convID})
+			if got := grpcCode(err); got != tc.wantErr {
+				t.Errorf("got %v, want %v (err: %v)", got, tc.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("deleted group is gone for every member", func(t *testing.T) {
+		if got := conversations(carol); got != 0 {
+			t.Errorf("carol conversations: got %d, want 0", got)
+		}
+		_, err := chatServer.GetMessages(bob, &pb.GetMessagesRequest{ConversationId: groupID})
+		if got := grpcCode(err); got != codes.PermissionDenied {
+			t.Errorf("bob GetMessages: got %v, want PermissionDenied", got)
+		}
+		if entries, err := os.ReadDir(exportDir); err != nil || len(entries) != 0 {
+			t.Errorf("export archives left behind: %d (err: %v)", len(entries), err)
+		}
+	})
+}
+
+func TestGetConversationsByName(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	chatServer := services.NewChatServer(sqlDB, nil)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+	makeFriends(t, sqlDB, ids["alice"], ids["carol"])
+	alice := ctxWithUser("alice", ids["alice"])
+
+	dmResp, err := chatServer.CreateConversation(alice,
+		&pb.CreateConversationRequest{IsGroup: false, MembersUsername: []string{"bob"}},
+	)
+	if err != nil {
+		t.Fatalf("setup DM: %v", err)
+	}
+	groupResp, err := chatServer.CreateConversation(alice,
+		&pb.CreateConversationRequest{IsGroup: true, Name: "project team", MembersUsername: []string{"bob", "carol"}},
+	)
+	if err != nil {
+		t.Fatalf("setup group: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		query   string
+		wantIDs []int64
+	}{
+		{"group by name", "project", []int64{groupResp.ConversationId}},
+		{"fuzzy group name", "projct team", []int64{groupResp.ConversationId}},
+		{"member name matches dm and group", "bob", []int64{dmResp.ConversationId, groupResp.ConversationId}},
+		{"group member only", "carol", []int64{groupResp.ConversationId}},
+		{"no results", "zzz", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := chatServer.GetConversationsByName(alice, &pb.GetConversationsByNameRequest{Name: tc.query})
+			if err != nil {
+				t.Fatalf("got error: %v", err)
+			}
+			got := map[int64]bool{}
+			for _, c := range resp.Conversations {
+				got[c.Id] = true
+			}
+			if len(got) != len(tc.wantIDs) {
+				t.Fatalf("want %d conversations, got %d", len(tc.wantIDs), len(got))
+			}
+			for _, id := range tc.wantIDs {
+				if !got[id] {
+					t.Errorf("conversation %d missing from results", id)
+				}
+			}
+		})
+	}
+
+	t.Run("paging", func(t *testing.T) {
+		page1, err := chatServer.GetConversationsByName(alice, &pb.GetConversationsByNameRequest{Name: "bob", Limit: 1})
+		if err != nil {
+			t.Fatalf("page 1: %v", err)
+		}
+		if len(page1.Conversations) != 1 || page1.NextCursor == "" {
+			t.Fatalf("page 1: got %d conversations, cursor %q", len(page1.Conversations), page1.NextCursor)
+		}
+		page2, err := chatServer.GetConversationsByName(alice, &pb.GetConversationsByNameRequest{Name: "bob", Limit: 1, Cursor: page1.NextCursor})
+		if err != nil {
+			t.Fatalf("page 2: %v", err)
+		}
+		if len(page2.Conversations) != 1 || page2.Conversations[0].Id == page1.Conversations[0].Id {
+			t.Fatalf("page 2: unexpected result %v", page2.Conversations)
+		}
+	})
+}
+
+func TestSearchMessages(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	js := setupTestNats(t)
+	chatServer := services.NewChatServer(sqlDB, services.NewNotificationServer(sqlDB, js))
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+	makeFriends(t, sqlDB, ids["carol"], ids["bob"])
+
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+	carol := ctxWithUser("carol", ids["carol"])
+
+	createDM := func(ctx context.Context, peer string) int64 {
+		t.Helper()
+		resp, err := chatServer.CreateConversation(ctx,
+			&pb.CreateConversationRequest{IsGroup: false, MembersUsername: []string{peer}},
+		)
+		if err != nil {
+			t.Fatalf("setup DM: %v", err)
+		}
+		return resp.ConversationId
+	}
+	send := func(ctx context.Context, convID int64, tokens ...string) string {
+		t.Helper()
+		msgID := uuid.New().String()
+		if _, err := chatServer.SendMessage(ctx, &pb.SendMessageRequest{
+			ConversationId: convID,
+			MessageId:      msgID,
+			Content:        "<ciphertext>",
+			SearchTokens:   tokens,
+		}); err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+		return msgID
+	}
+	search := func(ctx context.Context, req *pb.SearchMessagesRequest) []string {
+		t.Helper()
+		resp, err := chatServer.SearchMessages(ctx, req)
+		if err != nil {
+			t.Fatalf("SearchMessages: %v", err)
+		}
+		got := make([]string, 0, len(resp.Messages))
+		for _, m := range resp.Messages {
+			got = append(got, m.MessageId)
+		}
+		return got
+	}
+
+	abID := createDM(alice, "bob")
+	cbID := createDM(carol, "bob")
+
+	lunch := send(alice, abID, "t-lunch", "t-friday")
+	send(bob, abID, "t-lunch")
+	dinner := send(bob, abID, "t-dinner", "t-friday", "t-friday")
+	send(carol, cbID, "t-lunch", "t-friday")
+
+	t.Run("matches every token of the set", func(t *testing.T) {
+		got := search(alice, &pb.SearchMessagesRequest{TokenSets: []*pb.SearchTokenSet{
+			{ConversationId: abID, Tokens: []string{"t-lunch", "t-friday"}},
+		}})
+		if len(got) != 1 || got[0] != lunch {
+			t.Errorf("got %v, want [%s]", got, lunch)
+		}
+	})
+
+	t.Run("newest first across conversations", func(t *testing.T) {
+		got := search(bob, &pb.SearchMessagesRequest{TokenSets: []*pb.SearchTokenSet{
+			{ConversationId: abID, Tokens: []string{"t-friday"}},
+			{ConversationId: cbID, Tokens: []string{"t-friday", "t-lunch"}},
+		}})
+		if len(got) != 3 || got[1] != dinner || got[2] != lunch {
+			t.Errorf("got %v, want [carol's, %s, %s]", got, dinner, lunch)
+		}
+	})
+
+	t.Run("conversations of others are not searched", func(t *testing.T) {
+		got := search(alice, &pb.SearchMessagesRequest{TokenSets: []*pb.SearchTokenSet{
+			{ConversationId: cbID, Tokens: []string{"t-lunch"}},
+		}})
+		if len(got) != 0 {
+			t.Errorf("got %v, want none", got)
+		}
+	})
+
+	t.Run("pages with the cursor", func(t *testing.T) {
+		req := &pb.SearchMessagesRequest{
+			TokenSets: []*pb.SearchTokenSet{{ConversationId: abID, Tokens: []string{"t-lunch"}}},
+			Limit:     1,
+		}
+		resp, err := chatServer.SearchMessages(bob, req)
+		if err != nil {
+			t.Fatalf("SearchMessages: %v", err)
+		}
+		if len(resp.Messages) != 1 || resp.NextCursor == "" {
+			t.Fatalf("first page: got %d messages, cursor %q", len(resp.Messages), resp.NextCursor)
+		}
+		req.Cursor = resp.NextCursor
+		got := search(bob, req)
+		if len(got) != 1 || got[0] != lunch {
+			t.Errorf("second page: got %v, want [%s]", got, lunch)
+		}
+	})
+
+	t.Run("cleared history is not searched", func(t *testing.T) {
+		if _, err := chatServer.ClearHistory(alice, &pb.ClearHistoryRequest{ConversationId: abID}); err != nil {
+			t.Fatalf("ClearHistory: %v", err)
+		}
+		got := search(alice, &pb.SearchMessagesRequest{TokenSets: []*pb.SearchTokenSet{
+			{ConversationId: abID, Tokens: []string{"t-lunch"}},
+		}})
+		if len(got) != 0 {
+			t.Errorf("got %v, want none", got)
+		}
+	})
+
+	invalid := []struct {
+		name string
+		req  *pb.SearchMessagesRequest
+	}{
+		{"no token sets", &pb.SearchMessagesRequest{}},
+		{"empty token set", &pb.SearchMessagesRequest{TokenSets: []*pb.SearchTokenSet{{ConversationId: abID}}}},
+		{"empty token", &pb.SearchMessagesRequest{TokenSets: []*pb.SearchTokenSet{{ConversationId: abID, Tokens: []string{""}}}}},
+		{"missing conversation", &pb.SearchMessagesRequest{TokenSets: []*pb.SearchTokenSet{{Tokens: []string{"t-lunch"}}}}},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := chatServer.SearchMessages(alice, tc.req)
+			if got := grpcCode(err); got != codes.InvalidArgument {
+				t.Errorf("got %v, want InvalidArgument", got)
+			}
+		})
+	}
+
+	t.Run("send rejects too many tokens", func(t *testing.T) {
+		tokens := make([]string, 33)
+		for i := range tokens {
+			tokens[i] = "t-" + strings.Repeat("x", i+1)
+		}
+		_, err := chatServer.SendMessage(alice, &pb.SendMessageRequest{
+			ConversationId: abID,
+			MessageId:      uuid.New().String(),
+			Content:        "<ciphertext>",
+			SearchTokens:   tokens,
+		})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("got %v, want InvalidArgument", got)
+		}
+	})
+}
+
+func TestSlowMode(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	js := setupTestNats(t)
+	chatServer := services.NewChatServer(sqlDB, services.NewNotificationServer(sqlDB, js))
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+	makeFriends(t, sqlDB, ids["alice"], ids["carol"])
+
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+	carol := ctxWithUser("carol", ids["carol"])
+
+	groupResp, err := chatServer.CreateConversation(alice,
+		&pb.CreateConversationRequest{IsGroup: true, Name: "slow-group", MembersUsername: []string{"bob", "carol"}},
+	)
+	if err != nil {
+		t.Fatalf("setup group: %v", err)
+	}
+	convID := groupResp.ConversationId
+	if _, err := chatServer.SetMemberRole(alice,
+		&pb.SetMemberRoleRequest{ConversationId: convID, Username: "carol", Role: "admin"},
+	); err != nil {
+		t.Fatalf("setup admin: %v", err)
+	}
+
+	send := func(ctx context.Context) error {
+		_, err := chatServer.SendMessage(ctx,
+			&pb.SendMessageRequest{ConversationId: convID, MessageId: uuid.New().String(), Content: "hi"},
+		)
+		return err
+	}
+
+	t.Run("member cannot set slow mode", func(t *testing.T) {
+		_, err := chatServer.SetSlowMode(bob, &pb.SetSlowModeRequest{ConversationId: convID, Seconds: 60})
+		if got := grpcCode(err); got != codes.PermissionDenied {
+			t.Errorf("got %v, want PermissionDenied", got)
+		}
+	})
+
+	t.Run("interval out of range", func(t *testing.T) {
+		_, err := chatServer.SetSlowMode(alice, &pb.SetSlowModeRequest{ConversationId: convID, Seconds: 21601})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("got %v, want InvalidArgument", got)
+		}
+	})
+
+	if _, err := chatServer.SetSlowMode(carol, &pb.SetSlowModeRequest{ConversationId: convID, Seconds: 60}); err != nil {
+		t.Fatalf("SetSlowMode: %v", err)
+	}
+
+	t.Run("reported by GetConversation", func(t *testing.T) {
+		resp, err := chatServer.GetConversation(bob, &pb.GetConversationRequest{ConversationId: convID})
+		if err != nil {
+			t.Fatalf("GetConversation: %v", err)
+		}
+		if resp.SlowModeSeconds != 60 {
+			t.Errorf("slow_mode_seconds: got %d, want 60", resp.SlowModeSeconds)
+		}
+	})
+
+	t.Run("member is limited", func(t *testing.T) {
+		if err := send(bob); err != nil {
+			t.Fatalf("first send: %v", err)
+		}
+		err := send(bob)
+		if got := grpcCode(err); got != codes.ResourceExhausted {
+			t.Fatalf("second send: got %v, want ResourceExhausted", got)
+		}
+		errorCode, retryAfter := lib.ChatErrorDetails(err)
+		if errorCode != lib.ChatErrorSlowMode {
+			t.Errorf("error code: got %q, want %q", errorCode, lib.ChatErrorSlowMode)
+		}
+		if retryAfter <= 0 || retryAfter > time.Minute {
+			t.Errorf("retry after: got %v, want within (0, 1m]", retryAfter)
+		}
+	})
+
+	t.Run("admins and owner are exempt", func(t *testing.T) {
+		for name, ctx := range map[string]context.Context{"owner": alice, "admin": carol} {
+			for i := 0; i < 2; i++ {
+				if err := send(ctx); err != nil {
+					t.Errorf("%s send %d: %v", name, i, err)
+				}
+			}
+		}
+	})
+
+	t.Run("turning it off lifts the limit", func(t *testing.T) {
+		if _, err := chatServer.SetSlowMode(alice, &pb.SetSlowModeRequest{ConversationId: convID}); err != nil {
+			t.Fatalf("SetSlowMode: %v", err)
+		}
+		if err := send(bob); err != nil {
+			t.Errorf("send: %v", err)
+		}
+	})
+}
+
+func TestUpdateLastReadMessage_ReadReceiptsOff(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	js := setupTestNats(t)
+	chatServer := services.NewChatServer(sqlDB, services.NewNotificationServer(sqlDB, js))
+
+	ids := createTestUsers(t, sqlDB, "alice", "bob")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+
+	convResp, err := chatServer.CreateConversation(alice, &pb.CreateConversationRequest{MembersUsername: []string{"bob"}})
+	if err != nil {
+		t.Fatalf("CreateConversation: %v", err)
+	}
+	convID := convResp.ConversationId
+
+	aliceMsgs := make(chan *nats.Msg, 8)
+	sub, err := js.ChanSubscribe(lib.ChatSubjectPrefix+ids["alice"].String(), aliceMsgs)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	read := func(content string) string {
+		t.Helper()
+		msgID := uuid.New().String()
+		if _, err := chatServer.SendMessage(alice, &pb.SendMessageRequest{ConversationId: convID, MessageId: msgID, Content: content}); err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+		if _, err := chatServer.UpdateLastReadMessage(bob, &pb.UpdateMessageRequest{
+			ConversationId: convID, MessageId: msgID, UserId: ids["alice"].String(),
+		}); err != nil {
+			t.Fatalf("UpdateLastReadMessage: %v", err)
+		}
+		return msgID
+	}
+
+	q := db.New(sqlDB)
+	if _, err := q.UpsertPrivacySettings(t.Context(), db.UpsertPrivacySettingsParams{
+		UserID:       ids["bob"],
+		ReadReceipts: sql.NullBool{Valid: true, Bool: false},
+	}); err != nil {
+		t.Fatalf("turn read receipts off: %v", err)
+	}
+	hiddenID := read("first")
+
+	member, err := q.GetConversationMember(t.Context(), db.GetConversationMemberParams{ConversationID: convID, UserID: ids["bob"]})
+	if err != nil {
+		t.Fatalf("GetConversationMember: %v", err)
+	}
+	if member.LastReadMessageID.UUID.String() != hiddenID {
+		t.Errorf("bob's read position: got %v, want %s", member.LastReadMessageID, hiddenID)
+	}
+
+	if _, err := q.UpsertPrivacySettings(t.Context(), db.UpsertPrivacySettingsParams{
+		UserID:       ids["bob"],
+		ReadReceipts: sql.NullBool{Valid: true, Bool: true},
+	}); err != nil {
+		t.Fatalf("turn read receipts on: %v", err)
+	}
+	visibleID := read("second")
+
+	// The only read event alice gets is for the message read with receipts on.
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg := <-aliceMsgs:
+			var envelope lib.ChatResponseEnvelope
+			if err := json.Unmarshal(msg.Data, &envelope); err != nil {
+				t.Fatalf("unmarshal envelope: %v", err)
+			}
+			if envelope.Type != lib.ChatEventRead {
+				continue
+			}
+			var event lib.ReadEvent
+			if err := json.Unmarshal(envelope.Data, &event); err != nil {
+				t.Fatalf("unmarshal read event: %v", err)
+			}
+			if event.MessageID != visibleID {
+				t.Fatalf("read event for %s, want only %s", event.MessageID, visibleID)
+			}
+			return
+		case <-timeout:
+			t.Fatal("timeout: expected a read event on alice's chat subject")
+		}
+	}
+}
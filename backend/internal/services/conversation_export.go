@@ -0,0 +1,377 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
+	pb "github.com/zukigit/chat/backend/proto/chat"
+)
+
+// ExportStreamLimit is the largest conversation (in stored messages) that
+// ExportConversation streams directly. Bigger conversations must be exported
+// through a background job.
+const ExportStreamLimit = 5000
+
+// exportPageSize is how many messages are read per query while writing an archive.
+const exportPageSize = 500
+
+// exportChunkSize is the size of the chunks an archive is streamed in.
+const exportChunkSize = 32 << 10
+
+// exportLease is how long a claimed job may stay running before another
+// worker assumes its worker died and claims it again.
+const exportLease = 15 * time.Minute
+
+// exportDownloadPath is the gateway path that serves finished export archives.
+const exportDownloadPath = "/conversations/exports/download?token="
+
+// writeConversationArchive writes the archive of a conversation to w as
+// newline-delimited lib.ArchiveRecord values. q should wrap a read-only
+// repeatable-read transaction so the archive is a consistent snapshot.
+func writeConversationArchive(ctx context.Context, q *db.Queries, conversationID int64, exportedBy lib.SystemEventUser, w io.Writer) error {
+	bw := bufio.NewWriterSize(w, exportChunkSize)
+	enc := json.NewEncoder(bw)
+	write := func(t lib.ArchiveRecordType, data any) error {
+		return enc.Encode(lib.ArchiveRecord{Type: t, Data: data})
+	}
+
+	conv, err := q.GetConversation(ctx, conversationID)
+	if err != nil {
+		return fmt.Errorf("get conversation: %w", err)
+	}
+	if err := write(lib.ArchiveRecordHeader, lib.ArchiveHeader{
+		Format:     lib.ArchiveFormat,
+		Version:    lib.ArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+		ExportedBy: exportedBy,
+		Conversation: lib.ArchiveConversation{
+			ID:        conv.ID,
+			IsGroup:   conv.IsGroup,
+			Name:      conv.Name.String,
+			CreatedAt: conv.CreatedAt,
+			UpdatedAt: conv.UpdatedAt,
+		},
+	}); err != nil {
+		return err
+	}
+
+	members, err := q.GetConversationMembers(ctx, conversationID)
+	if err != nil {
+		return fmt.Errorf("get members: %w", err)
+	}
+	for _, m := range members {
+		joinedAt := m.JoinedAt
+		if err := write(lib.ArchiveRecordMember, lib.ArchiveMember{
+			UserID:      m.UserID.String(),
+			Username:    m.UserName,
+			DisplayName: m.DisplayName.String,
+			Role:        string(m.Role),
+			JoinedAt:    &joinedAt,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Senders who have since left, so every message's sender_id resolves.
+	former, err := q.GetConversationFormerSenders(ctx, conversationID)
+	if err != nil {
+		return fmt.Errorf("get former senders: %w", err)
+	}
+	for _, u := range former {
+		if err := write(lib.ArchiveRecordMember, lib.ArchiveMember{
+			UserID:      u.UserID.String(),
+			Username:    u.UserName,
+			DisplayName: u.DisplayName.String,
+			Former:      true,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Keys of everyone who is or was part of the conversation, with the
+	// window each key was current for.
+	participants, err := q.GetConversationParticipantIDs(ctx, conversationID)
+	if err != nil {
+		return fmt.Errorf("get participants: %w", err)
+	}
+	keys, err := q.GetPublicKeysByUserIDs(ctx, participants)
+	if err != nil {
+		return fmt.Errorf("get public keys: %w", err)
+	}
+	// keys are ordered by created_at, so a user's next key ends the previous one.
+	for i, k := range keys {
+		record := lib.ArchivePublicKey{UserID: k.UserID.String(), Key: k.Key, ValidFrom: k.CreatedAt}
+		for _, next := range keys[i+1:] {
+			if next.UserID == k.UserID {
+				until := next.CreatedAt
+				record.ValidUntil = &until
+				break
+			}
+		}
+		if err := write(lib.ArchiveRecordPublicKey, record); err != nil {
+			return err
+		}
+	}
+
+	receipts, err := q.GetConversationReceipts(ctx, conversationID)
+	if err != nil {
+		return fmt.Errorf("get receipts: %w", err)
+	}
+	for _, r := range receipts {
+		record := lib.ArchiveReceipt{UserID: r.UserID.String()}
+		// Members who turned read receipts off keep their read position private.
+		if r.LastReadMessageID.Valid && (r.ReadReceipts || record.UserID == exportedBy.UserID) {
+			record.LastReadMessageID = r.LastReadMessageID.UUID.String()
+		}
+		if r.LastDeliveredMessageID.Valid {
+			record.LastDeliveredMessageID = r.LastDeliveredMessageID.UUID.String()
+		}
+		if err := write(lib.ArchiveRecordReceipt, record); err != nil {
+			return err
+		}
+	}
+
+	var cursor uuid.NullUUID
+	for {
+		msgs, err := q.GetMessagesForExport(ctx, db.GetMessagesForExportParams{
+			ConversationID: conversationID,
+			Cursor:         cursor,
+			PageLimit:      exportPageSize,
+		})
+		if err != nil {
+			return fmt.Errorf("get messages: %w", err)
+		}
+		for _, m := range msgs {
+			record := lib.ArchiveMessage{
+				ID:          m.ID.String(),
+				SenderID:    m.SenderID.String(),
+				Content:     m.Content,
+				MessageType: string(m.MessageType),
+				MediaURL:    m.MediaUrl.String,
+				IsEdited:    m.IsEdited,
+				CreatedAt:   m.CreatedAt,
+				UpdatedAt:   m.UpdatedAt,
+			}
+			if m.ReplyToMessageID.Valid {
+				record.ReplyToMessageID = m.ReplyToMessageID.UUID.String()
+			}
+			if m.DeletedAt.Valid {
+				deletedAt := m.DeletedAt.Time
+				record.DeletedAt = &deletedAt
+			}
+			if err := write(lib.ArchiveRecordMessage, record); err != nil {
+				return err
+			}
+		}
+		if len(msgs) < exportPageSize {
+			break
+		}
+		cursor = uuid.NullUUID{Valid: true, UUID: msgs[len(msgs)-1].ID}
+	}
+
+	return bw.Flush()
+}
+
+// exportChunkSender is the part of the export streams used by exportChunkWriter.
+type exportChunkSender interface {
+	Send(*pb.ExportChunk) error
+}
+
+// exportChunkWriter adapts an export stream to io.Writer; each Write is sent
+// as one ExportChunk.
+type exportChunkWriter struct {
+	stream exportChunkSender
+}
+
+func (w exportChunkWriter) Write(p []byte) (int, error) {
+	// p may be reused by the caller after Write returns, so send a copy.
+	if err := w.stream.Send(&pb.ExportChunk{Data: append([]byte(nil), p...)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// exportSnapshot runs fn inside a read-only repeatable-read transaction so
+// every query sees the same snapshot of the conversation.
+func exportSnapshot(ctx context.Context, sqlDB *sql.DB, fn func(q *db.Queries) error) error {
+	tx, err := sqlDB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	return fn(db.New(tx))
+}
+
+// ExportWorker produces conversation archives for background export jobs
+// and removes them once their download link has expired.
+type ExportWorker struct {
+	sqlDB    *sql.DB
+	dir      string
+	ttl      time.Duration
+	interval time.Duration
+	lease    time.Duration
+}
+
+// NewExportWorker creates an ExportWorker that writes archives into dir and
+// keeps each one downloadable for ttl.
+func NewExportWorker(sqlDB *sql.DB, dir string, ttl time.Duration) *ExportWorker {
+	return &ExportWorker{sqlDB: sqlDB, dir: dir, ttl: ttl, interval: 10 * time.Second, lease: exportLease}
+}
+
+// Run polls for pending jobs until ctx is cancelled.
+func (w *ExportWorker) Run(ctx context.Context) {
+	if err := os.MkdirAll(w.dir, 0o700); err != nil {
+		lib.ErrorLog.Printf("ExportWorker: create %s: %v", w.dir, err)
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.purgeExpired(ctx)
+		for {
+			ok, err := w.ProcessNext(ctx)
+			if err != nil {
+				lib.ErrorLog.Printf("ExportWorker: %v", err)
+			}
+			if !ok {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessNext claims and runs the oldest pending job, or a running job whose
+// lease has run out. It reports whether a job was claimed; a job that fails is
+// marked failed and does not return an error.
+func (w *ExportWorker) ProcessNext(ctx context.Context) (bool, error) {
+	job, err := db.New(w.sqlDB).ClaimPendingConversationExport(ctx, time.Now().Add(-w.lease))
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("claim job: %w", err)
+	}
+
+	if err := w.run(ctx, job); err != nil {
+		lib.ErrorLog.Printf("ExportWorker: job %s: %v", job.ID, err)
+		if ferr := db.New(w.sqlDB).FailConversationExport(ctx, db.FailConversationExportParams{
+			ID:        job.ID,
+			Error:     sql.NullString{Valid: true, String: err.Error()},
+			StartedAt: job.StartedAt,
+		}); ferr != nil {
+			return true, fmt.Errorf("mark job %s failed: %w", job.ID, ferr)
+		}
+	}
+	return true, nil
+}
+
+// run writes the archive for job to disk and marks the job done.
+func (w *ExportWorker) run(ctx context.Context, job db.ConversationExport) error {
+	user, err := db.New(w.sqlDB).GetUserByID(ctx, job.UserID)
+	if err != nil {
+		return fmt.Errorf("get requester: %w", err)
+	}
+
+	// Each claim writes its own file, so a worker that lost its lease cannot
+	// clobber the archive of the one that took the job over.
+	path := filepath.Join(w.dir, fmt.Sprintf("%s.%d.jsonl", job.ID, job.StartedAt.Time.UnixNano()))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("create archive file: %w", err)
+	}
+	defer f.Close()
+
+	exportedBy := lib.SystemEventUser{UserID: user.UserID.String(), Username: user.UserName}
+	if err := exportSnapshot(ctx, w.sqlDB, func(q *db.Queries) error {
+		return writeConversationArchive(ctx, q, job.ConversationID, exportedBy, f)
+	}); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("write archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("close archive file: %w", err)
+	}
+
+	token, err := lib.RandomToken(32)
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("generate download token: %w", err)
+	}
+
+	completed, err := db.New(w.sqlDB).CompleteConversationExport(ctx, db.CompleteConversationExportParams{
+		ID:            job.ID,
+		DownloadToken: sql.NullString{Valid: true, String: token},
+		FilePath:      sql.NullString{Valid: true, String: path},
+		ExpiresAt:     sql.NullTime{Valid: true, Time: time.Now().Add(w.ttl)},
+		StartedAt:     job.StartedAt,
+	})
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("complete job: %w", err)
+	}
+	if completed == 0 {
+		// The lease ran out and another worker took the job over.
+		os.Remove(path)
+		lib.ErrorLog.Printf("ExportWorker: job %s: lease lost, archive discarded", job.ID)
+	}
+	return nil
+}
+
+// purgeExpired deletes expired jobs and their archive files.
+func (w *ExportWorker) purgeExpired(ctx context.Context) {
+	paths, err := db.New(w.sqlDB).DeleteExpiredConversationExports(ctx)
+	if err != nil {
+		lib.ErrorLog.Printf("ExportWorker: delete expired jobs: %v", err)
+		return
+	}
+	removeArchives(paths)
+}
+
+// removeArchives deletes the archive files of deleted export jobs. Jobs that
+// never finished have no file.
+func removeArchives(paths []sql.NullString) {
+	for _, p := range paths {
+		if !p.Valid {
+			continue
+		}
+		if err := os.Remove(p.String); err != nil && !os.IsNotExist(err) {
+			lib.ErrorLog.Printf("remove export archive %s: %v", p.String, err)
+		}
+	}
+}
+
+// conversationExportToPB converts a job row to its protobuf form.
+func conversationExportToPB(job db.ConversationExport) *pb.ConversationExport {
+	resp := &pb.ConversationExport{
+		ExportId:       job.ID.String(),
+		ConversationId: job.ConversationID,
+		Status:         string(job.Status),
+		Error:          job.Error.String,
+		CreatedAt:      job.CreatedAt.Format(time.RFC3339),
+	}
+	if job.Status == db.ExportStatusDone && job.DownloadToken.Valid {
+		resp.DownloadUrl = exportDownloadPath + job.DownloadToken.String
+		resp.ExpiresAt = job.ExpiresAt.Time.Format(time.RFC3339)
+	}
+	return resp
+}
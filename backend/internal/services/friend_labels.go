@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
+	pb "github.com/zukigit/chat/backend/proto/friendship"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxLabelNameLen is the longest friend label name, in characters.
+const maxLabelNameLen = 50
+
+// CreateLabel creates an empty friend label owned by the caller.
+func (s *FriendshipServer) CreateLabel(ctx context.Context, req *pb.CreateLabelRequest) (*pb.FriendLabel, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := labelName(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	label, err := db.New(s.sqlDB).CreateFriendLabel(ctx, db.CreateFriendLabelParams{OwnerID: callerID, Name: name})
+	if lib.IsPgUniqueViolation(err) {
+		return nil, status.Errorf(codes.AlreadyExists, "label %q already exists", name)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "CreateLabel: %v", err)
+	}
+
+	return &pb.FriendLabel{
+		Id:        label.ID,
+		Name:      label.Name,
+		CreatedAt: label.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: label.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// ListLabels returns the caller's friend labels ordered by name.
+func (s *FriendshipServer) ListLabels(ctx context.Context, _ *pb.ListLabelsRequest) (*pb.ListLabelsResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.New(s.sqlDB).ListFriendLabels(ctx, callerID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ListLabels: %v", err)
+	}
+
+	labels := make([]*pb.FriendLabel, 0, len(rows))
+	for _, r := range rows {
+		labels = append(labels, &pb.FriendLabel{
+			Id:          r.ID,
+			Name:        r.Name,
+			MemberCount: r.MemberCount,
+			CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:   r.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &pb.ListLabelsResponse{Labels: labels}, nil
+}
+
+// RenameLabel renames one of the caller's friend labels.
+func (s *FriendshipServer) RenameLabel(ctx context.Context, req *pb.RenameLabelRequest) (*pb.FriendLabel, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := labelName(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	q := db.New(s.sqlDB)
+	n, err := q.RenameFriendLabel(ctx, db.RenameFriendLabelParams{ID: req.GetLabelId(), OwnerID: callerID, Name: name})
+	if lib.IsPgUniqueViolation(err) {
+		return nil, status.Errorf(codes.AlreadyExists, "label %q already exists", name)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "RenameLabel: %v", err)
+	}
+	if n == 0 {
+		return nil, status.Error(codes.NotFound, "label not found")
+	}
+
+	return getLabel(ctx, q, req.GetLabelId(), callerID)
+}
+
+// DeleteLabel deletes one of the caller's friend labels. The friends in it
+// are not affected.
+func (s *FriendshipServer) DeleteLabel(ctx context.Context, req *pb.DeleteLabelRequest) (*pb.DeleteLabelResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := db.New(s.sqlDB).DeleteFriendLabel(ctx, db.DeleteFriendLabelParams{ID: req.GetLabelId(), OwnerID: callerID})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "DeleteLabel: %v", err)
+	}
+	if n == 0 {
+		return nil, status.Error(codes.NotFound, "label not found")
+	}
+
+	return &pb.DeleteLabelResponse{}, nil
+}
+
+// AddLabelMembers adds friends to one of the caller's labels. Every username
+// must belong to an accepted friend. Friends already in the label are skipped.
+func (s *FriendshipServer) AddLabelMembers(ctx context.Context, req *pb.LabelMembersRequest) (*pb.FriendLabel, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.GetUsernames()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "usernames must not be empty")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "AddLabelMembers: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	if _, err := getLabel(ctx, q, req.GetLabelId(), callerID); err != nil {
+		return nil, err
+	}
+
+	friends, err := q.GetFriendsByUsernames(ctx, db.GetFriendsByUsernamesParams{OwnerID: callerID, Usernames: req.GetUsernames()})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "AddLabelMembers: resolve friends: %v", err)
+	}
+	friendIDs := make(map[string]uuid.UUID, len(friends))
+	for _, f := range friends {
+		friendIDs[f.UserName] = f.UserID
+	}
+	userIDs := make([]uuid.UUID, 0, len(req.GetUsernames()))
+	for _, username := range req.GetUsernames() {
+		id, ok := friendIDs[username]
+		if !ok {
+			return nil, status.Errorf(codes.PermissionDenied, "user %q is not a friend", username)
+		}
+		userIDs = append(userIDs, id)
+	}
+
+	if err := q.AddFriendLabelMembers(ctx, db.AddFriendLabelMembersParams{LabelID: req.GetLabelId(), UserIds: userIDs}); err != nil {
+		return nil, status.Errorf(codes.Internal, "AddLabelMembers: insert: %v", err)
+	}
+
+	label, err := getLabel(ctx, q, req.GetLabelId(), callerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "AddLabelMembers: commit: %v", err)
+	}
+
+	return label, nil
+}
+
+// RemoveLabelMembers takes users out of one of the caller's labels. Usernames
+// that are unknown or not in the label are ignored.
+func (s *FriendshipServer) RemoveLabelMembers(ctx context.Context, req *pb.LabelMembersRequest) (*pb.FriendLabel, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.GetUsernames()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "usernames must not be empty")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveLabelMembers: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	if _, err := getLabel(ctx, q, req.GetLabelId(), callerID); err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(req.GetUsernames()))
+	for _, username := range req.GetUsernames() {
+		user, err := q.GetUserByUsername(ctx, username)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "RemoveLabelMembers: lookup user %q: %v", username, err)
+		}
+		userIDs = append(userIDs, user.UserID)
+	}
+
+	if err := q.RemoveFriendLabelMembers(ctx, db.RemoveFriendLabelMembersParams{LabelID: req.GetLabelId(), UserIds: userIDs}); err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveLabelMembers: delete: %v", err)
+	}
+
+	label, err := getLabel(ctx, q, req.GetLabelId(), callerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveLabelMembers: commit: %v", err)
+	}
+
+	return label, nil
+}
+
+// getLabel returns the label labelID if ownerID owns it, or NotFound.
+func getLabel(ctx context.Context, q *db.Queries, labelID int64, ownerID uuid.UUID) (*pb.FriendLabel, error) {
+	label, err := q.GetFriendLabel(ctx, db.GetFriendLabelParams{ID: labelID, OwnerID: ownerID})
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.NotFound, "label not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get label: %v", err)
+	}
+	return &pb.FriendLabel{
+		Id:          label.ID,
+		Name:        label.Name,
+		MemberCount: label.MemberCount,
+		CreatedAt:   label.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   label.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// labelName trims and validates a label name.
+func labelName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", status.Error(codes.InvalidArgument, "name is required")
+	}
+	if utf8.RuneCountInString(name) > maxLabelNameLen {
+		return "", status.Errorf(codes.InvalidArgument, "name must be at most %d characters", maxLabelNameLen)
+	}
+	return name, nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
+	"google.golang.org/grpc/codes"
+)
+
+// FriendRequestLimits throttles outgoing friend requests. A zero field
+// disables that limit.
+type FriendRequestLimits struct {
+	PerHour int // requests a user may send in any hour
+	PerDay  int // requests a user may send in any 24 hours
+	// DeclineCooldown is how long a user must wait before asking someone who
+	// declined their request again.
+	DeclineCooldown time.Duration
+}
+
+// DefaultFriendRequestLimits are the limits a new FriendshipServer starts with.
+var DefaultFriendRequestLimits = FriendRequestLimits{
+	PerHour:         20,
+	PerDay:          50,
+	DeclineCooldown: 7 * 24 * time.Hour,
+}
+
+// SetRequestLimits replaces the server's friend request limits.
+func (s *FriendshipServer) SetRequestLimits(limits FriendRequestLimits) {
+	s.limits = limits
+}
+
+// checkRequestLimits returns ResourceExhausted if callerID has used up their
+// hourly or daily friend requests, and FailedPrecondition if targetID declined
+// a request from callerID within the decline cooldown. Both carry RetryInfo.
+// It must run inside the transaction that logs the request.
+func (s *FriendshipServer) checkRequestLimits(ctx context.Context, q *db.Queries, callerID, targetID uuid.UUID, target string) error {
+	if s.limits.DeclineCooldown > 0 {
+		declinedAt, err := q.GetFriendRequestDecline(ctx, db.GetFriendRequestDeclineParams{RequesterID: callerID, AddresseeID: targetID})
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("get decline: %w", err)
+		}
+		if err == nil {
+			if next := declinedAt.Add(s.limits.DeclineCooldown); time.Now().Before(next) {
+				return lib.NewRetryError(codes.FailedPrecondition,
+					fmt.Sprintf("%s declined your last friend request; you can ask again after %s", target, next.UTC().Format(time.RFC3339)),
+					time.Until(next))
+			}
+		}
+	}
+
+	if s.limits.PerHour <= 0 && s.limits.PerDay <= 0 {
+		return nil
+	}
+
+	if err := q.LockFriendRequestSender(ctx, callerID); err != nil {
+		return fmt.Errorf("lock sender: %w", err)
+	}
+	counts, err := q.GetFriendRequestCounts(ctx, callerID)
+	if err != nil {
+		return fmt.Errorf("count requests: %w", err)
+	}
+	if s.limits.PerHour > 0 && int(counts.LastHour) >= s.limits.PerHour {
+		return friendRequestLimitError(s.limits.PerHour, "hour", time.Until(counts.OldestInHour.Add(time.Hour)))
+	}
+	if s.limits.PerDay > 0 && int(counts.LastDay) >= s.limits.PerDay {
+		return friendRequestLimitError(s.limits.PerDay, "day", time.Until(counts.OldestInDay.Add(24*time.Hour)))
+	}
+	return nil
+}
+
+// friendRequestLimitError returns the ResourceExhausted error for a request
+// over the limit of n per window.
+func friendRequestLimitError(n int, window string, retryAfter time.Duration) error {
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	msg := fmt.Sprintf("friend request limit reached (%d per %s): try again in %d minutes", n, window, int64(math.Ceil(retryAfter.Minutes())))
+	return lib.NewRetryError(codes.ResourceExhausted, msg, retryAfter)
+}
+
+// FriendRequestExpiryWorker deletes pending friend requests older than its
+// TTL, along with the notifications they caused, and purges the send log
+// once it is too old to count towards any limit.
+type FriendRequestExpiryWorker struct {
+	sqlDB     *sql.DB
+	notif     *NotificationServer
+	ttl       time.Duration
+	interval  time.Duration
+	batchSize int32
+}
+
+// NewFriendRequestExpiryWorker creates a FriendRequestExpiryWorker that
+// expires requests after ttl. notif may be nil, in which case notifications
+// are left alone.
+func NewFriendRequestExpiryWorker(sqlDB *sql.DB, notif *NotificationServer, ttl time.Duration) *FriendRequestExpiryWorker {
+	return &FriendRequestExpiryWorker{sqlDB: sqlDB, notif: notif, ttl: ttl, interval: 10 * time.Minute, batchSize: 100}
+}
+
+// Run expires requests periodically until ctx is cancelled.
+func (w *FriendRequestExpiryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := w.ExpireBatch(ctx)
+			if err != nil {
+				lib.ErrorLog.Printf("FriendRequestExpiryWorker: %v", err)
+			}
+			if n < int(w.batchSize) {
+				break
+			}
+		}
+		if _, err := db.New(w.sqlDB).DeleteFriendRequestLogBefore(ctx, time.Now().Add(-24*time.Hour)); err != nil {
+			lib.ErrorLog.Printf("FriendRequestExpiryWorker: purge request log: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireBatch deletes up to one batch of expired requests and retracts their
+// notifications. It returns how many requests were expired.
+func (w *FriendRequestExpiryWorker) ExpireBatch(ctx context.Context) (int, error) {
+	tx, err := w.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	expired, err := q.ExpireFriendRequests(ctx, db.ExpireFriendRequestsParams{
+		Cutoff:    time.Now().Add(-w.ttl),
+		BatchSize: w.batchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("expire requests: %w", err)
+	}
+
+	for _, f := range expired {
+		recipientID := f.User1Userid
+		if recipientID == f.InitiatorUserid {
+			recipientID = f.User2Userid
+		}
+		if err := w.notif.Retract(ctx, q, db.DeleteNotificationsFromParams{
+			UserID:   recipientID,
+			SenderID: uuid.NullUUID{Valid: true, UUID: f.InitiatorUserid},
+			Type:     db.NotificationTypeFriendRequest,
+		}); err != nil {
+			return 0, fmt.Errorf("retract notification: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return len(expired), nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/zukigit/chat/backend/internal/db"
@@ -16,32 +17,32 @@ import (
 // FriendshipServer implements the friendship.FriendshipServer interface.
 type FriendshipServer struct {
 	pb.UnimplementedFriendshipServer
-	sqlDB *sql.DB
-	notif *NotificationServer // nil disables notifications (e.g. in tests)
+	sqlDB  *sql.DB
+	notif  *NotificationServer // nil disables notifications (e.g. in tests)
+	limits FriendRequestLimits
 }
 
-// NewFriendshipServer creates a new FriendshipServer instance.
-// notif may be nil, in which case notifications are skipped.
+// NewFriendshipServer creates a new FriendshipServer instance with
+// DefaultFriendRequestLimits. notif may be nil, in which case notifications
+// are skipped.
 func NewFriendshipServer(sqlDB *sql.DB, notif *NotificationServer) *FriendshipServer {
-	return &FriendshipServer{sqlDB: sqlDB, notif: notif}
+	return &FriendshipServer{sqlDB: sqlDB, notif: notif, limits: DefaultFriendRequestLimits}
 }
 
 // SendFriendRequest handles a friend request from the caller to target_username.
-// It creates the friendship row and notifies the target user.
+// It creates the friendship row and notifies the target user. The target's
+// friend request setting decides who may send them requests, and the server's
+// FriendRequestLimits throttle how often the caller may send them.
 func (s *FriendshipServer) SendFriendRequest(ctx context.Context, req *pb.FriendRequest) (*pb.FriendResponse, error) {
 	callerID, err := lib.CallerUUID(ctx)
 	if err != nil {
 		return nil, err
 	}
-	callerName := lib.CallerFrom(ctx)
 	target := req.GetTargetUsername()
 
 	if target == "" {
 		return nil, status.Error(codes.InvalidArgument, "target_username is required")
 	}
-	if callerName == target {
-		return nil, status.Error(codes.InvalidArgument, "cannot send a friend request to yourself")
-	}
 
 	tx, err := s.sqlDB.BeginTx(ctx, nil)
 	if err != nil {
@@ -59,6 +60,37 @@ func (s *FriendshipServer) SendFriendRequest(ctx context.Context, req *pb.Friend
 		return nil, status.Errorf(codes.Internal, "SendFriendRequest: get target user: %v", err)
 	}
 	targetID := targetUser.UserID
+	if targetID == callerID {
+		return nil, status.Error(codes.InvalidArgument, "cannot send a friend request to yourself")
+	}
+
+	block, err := q.GetBlockState(ctx, db.GetBlockStateParams{UserID: callerID, OtherID: targetID})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SendFriendRequest: get block state: %v", err)
+	}
+	if block.IsBlockedBy {
+		// Answer as if the user did not exist so the block is not revealed.
+		return nil, status.Errorf(codes.InvalidArgument, "user %q not found", target)
+	}
+	if block.HasBlocked {
+		return nil, status.Error(codes.FailedPrecondition, "you have blocked this user")
+	}
+
+	settings, err := q.GetPrivacySettings(ctx, targetID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SendFriendRequest: get privacy settings: %v", err)
+	}
+	allowed, err := q.AudienceAllows(ctx, db.AudienceAllowsParams{
+		Audience: settings.FriendRequestPolicy,
+		OwnerID:  targetID,
+		ViewerID: callerID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SendFriendRequest: check friend request policy: %v", err)
+	}
+	if !allowed {
+		return nil, status.Errorf(codes.PermissionDenied, "user %q does not accept friend requests from you", target)
+	}
 
 	first, second := lib.OrderedUUIDPair(callerID, targetID)
 
@@ -85,19 +117,31 @@ func (s *FriendshipServer) SendFriendRequest(ctx context.Context, req *pb.Friend
 		return nil, status.Errorf(codes.AlreadyExists, "friend request already exists with status: %s", existing.Status)
 	}
 
+	if err := s.checkRequestLimits(ctx, q, callerID, targetID, target); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "SendFriendRequest: check limits: %v", err)
+	}
+
 	friendship, err := doWrite(q)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "SendFriendRequest: write: %v", err)
 	}
 
+	if err := q.LogFriendRequest(ctx, db.LogFriendRequestParams{SenderID: callerID, RecipientID: targetID}); err != nil {
+		return nil, status.Errorf(codes.Internal, "SendFriendRequest: log request: %v", err)
+	}
+
 	if err := s.notif.Send(ctx, q, db.CreateNotificationParams{
 		UserID: targetID,
 		SenderID: uuid.NullUUID{
 			Valid: true,
 			UUID:  callerID,
 		},
-		Type:    db.NotificationTypeFriendRequest,
-		Message: fmt.Sprintf("%s sent a friend request", callerName),
+		Type:            db.NotificationTypeFriendRequest,
+		Message:         fmt.Sprintf("%s sent a friend request", callerName(ctx, q)),
+		ReferenceUserID: uuid.NullUUID{Valid: true, UUID: callerID},
 	}); err != nil {
 		return nil, status.Errorf(codes.Internal, "SendFriendRequest: create notification: %v", err)
 	}
@@ -117,7 +161,8 @@ func (s *FriendshipServer) AcceptFriendRequest(ctx context.Context, req *pb.Frie
 }
 
 // RejectFriendRequest rejects a pending friend request from target_username
-// by deleting the friendship record.
+// by deleting the friendship record. The requester may not ask again until
+// the decline cooldown has passed.
 func (s *FriendshipServer) RejectFriendRequest(ctx context.Context, req *pb.FriendRequest) (*pb.FriendResponse, error) {
 	callerID, err := lib.CallerUUID(ctx)
 	if err != nil {
@@ -174,6 +219,13 @@ func (s *FriendshipServer) RejectFriendRequest(ctx context.Context, req *pb.Frie
 		return nil, status.Errorf(codes.Internal, "RejectFriendRequest: delete: %v", err)
 	}
 
+	if err := q.RecordFriendRequestDecline(ctx, db.RecordFriendRequestDeclineParams{
+		RequesterID: existing.InitiatorUserid,
+		AddresseeID: callerID,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "RejectFriendRequest: record decline: %v", err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, status.Errorf(codes.Internal, "RejectFriendRequest: commit: %v", err)
 	}
@@ -181,15 +233,466 @@ func (s *FriendshipServer) RejectFriendRequest(ctx context.Context, req *pb.Frie
 	return &pb.FriendResponse{Status: "rejected"}, nil
 }
 
-// GetFriends returns all accepted friends for the authenticated caller.
-func (s *FriendshipServer) GetFriends(ctx context.Context, _ *pb.GetFriendsRequest) (*pb.GetFriendsResponse, error) {
+// ListFriendRequests returns a page of the caller's pending friend requests in
+// the given direction, newest first.
+func (s *FriendshipServer) ListFriendRequests(ctx context.Context, req *pb.ListFriendRequestsRequest) (*pb.ListFriendRequestsResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var outgoing bool
+	switch req.GetDirection() {
+	case "incoming":
+	case "outgoing":
+		outgoing = true
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid direction %q: want incoming or outgoing", req.GetDirection())
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	params := db.ListFriendRequestsParams{
+		UserID:    callerID,
+		Outgoing:  outgoing,
+		PageLimit: limit,
+	}
+	if req.GetCursor() != "" {
+		var c friendRequestCursor
+		if err := lib.DecodeCursor(req.GetCursor(), &c); err != nil || c.UserID == uuid.Nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		}
+		params.CursorCreatedAt = sql.NullTime{Valid: true, Time: c.CreatedAt}
+		params.CursorUserID = uuid.NullUUID{Valid: true, UUID: c.UserID}
+	}
+
+	rows, err := db.New(s.sqlDB).ListFriendRequests(ctx, params)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ListFriendRequests: %v", err)
+	}
+
+	requests := make([]*pb.PendingFriendRequest, 0, len(rows))
+	for _, r := range rows {
+		requests = append(requests, &pb.PendingFriendRequest{
+			UserId:      r.UserID.String(),
+			Username:    r.UserName,
+			DisplayName: r.DisplayName.String,
+			AvatarUrl:   r.AvatarUrl.String,
+			CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	resp := &pb.ListFriendRequestsResponse{Requests: requests}
+	if len(rows) == int(limit) {
+		last := rows[len(rows)-1]
+		resp.NextCursor, err = lib.EncodeCursor(friendRequestCursor{CreatedAt: last.CreatedAt, UserID: last.UserID})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "ListFriendRequests: encode cursor: %v", err)
+		}
+	}
+	return resp, nil
+}
+
+// friendRequestCursor is the keyset position of the last row of a
+// ListFriendRequests page.
+type friendRequestCursor struct {
+	CreatedAt time.Time `json:"t"`
+	UserID    uuid.UUID `json:"u"`
+}
+
+// CancelFriendRequest withdraws a pending request the caller sent to
+// target_username and retracts the notification it created.
+func (s *FriendshipServer) CancelFriendRequest(ctx context.Context, req *pb.FriendRequest) (*pb.FriendResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target := req.GetTargetUsername()
+
+	if target == "" {
+		return nil, status.Error(codes.InvalidArgument, "target_username is required")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "CancelFriendRequest: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	targetUser, err := q.GetUserByUsername(ctx, target)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.InvalidArgument, "user %q not found", target)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "CancelFriendRequest: get target user: %v", err)
+	}
+	targetID := targetUser.UserID
+
+	first, second := lib.OrderedUUIDPair(callerID, targetID)
+
+	existing, err := q.GetFriendship(ctx, db.GetFriendshipParams{
+		User1Userid: first,
+		User2Userid: second,
+	})
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.NotFound, "friend request not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "CancelFriendRequest: get friendship: %v", err)
+	}
+
+	if existing.Status != db.FriendshipStatusPending {
+		return nil, status.Error(codes.FailedPrecondition, "friend request is not pending")
+	}
+
+	if callerID != existing.InitiatorUserid {
+		return nil, status.Error(codes.PermissionDenied, "only the sender can cancel a friend request")
+	}
+
+	if err := q.DeleteFriendship(ctx, db.DeleteFriendshipParams{
+		User1Userid: first,
+		User2Userid: second,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "CancelFriendRequest: delete: %v", err)
+	}
+
+	if err := s.notif.Retract(ctx, q, db.DeleteNotificationsFromParams{
+		UserID:   targetID,
+		SenderID: uuid.NullUUID{Valid: true, UUID: callerID},
+		Type:     db.NotificationTypeFriendRequest,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "CancelFriendRequest: retract notification: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "CancelFriendRequest: commit: %v", err)
+	}
+
+	return &pb.FriendResponse{Status: "cancelled"}, nil
+}
+
+// RemoveFriend ends an accepted friendship with target_username. Their DM is
+// kept but becomes read-only until they are friends again, and the removed
+// user is notified so their friend list refreshes.
+func (s *FriendshipServer) RemoveFriend(ctx context.Context, req *pb.FriendRequest) (*pb.FriendResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target := req.GetTargetUsername()
+
+	if target == "" {
+		return nil, status.Error(codes.InvalidArgument, "target_username is required")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveFriend: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	targetUser, err := q.GetUserByUsername(ctx, target)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.InvalidArgument, "user %q not found", target)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveFriend: get target user: %v", err)
+	}
+	targetID := targetUser.UserID
+
+	first, second := lib.OrderedUUIDPair(callerID, targetID)
+
+	existing, err := q.GetFriendship(ctx, db.GetFriendshipParams{
+		User1Userid: first,
+		User2Userid: second,
+	})
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.NotFound, "friendship not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveFriend: get friendship: %v", err)
+	}
+
+	if existing.Status != db.FriendshipStatusAccepted {
+		return nil, status.Error(codes.FailedPrecondition, "not friends")
+	}
+
+	if err := q.DeleteFriendship(ctx, db.DeleteFriendshipParams{
+		User1Userid: first,
+		User2Userid: second,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveFriend: delete: %v", err)
+	}
+	if err := q.DeleteFriendLabelMembershipsBetween(ctx, db.DeleteFriendLabelMembershipsBetweenParams{
+		UserA: callerID,
+		UserB: targetID,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveFriend: remove from labels: %v", err)
+	}
+
+	// Point the notification at the DM, if any, so the client can mark it read-only.
+	var dmID sql.NullInt64
+	dm, err := q.GetDmPeer(ctx, db.GetDmPeerParams{
+		User1ID: first,
+		User2ID: second,
+	})
+	if err == nil {
+		dmID = sql.NullInt64{Valid: true, Int64: dm.ConversationID}
+	} else if err != sql.ErrNoRows {
+		return nil, status.Errorf(codes.Internal, "RemoveFriend: get dm peer: %v", err)
+	}
+
+	if err := s.notif.Send(ctx, q, db.CreateNotificationParams{
+		UserID: targetID,
+		SenderID: uuid.NullUUID{
+			Valid: true,
+			UUID:  callerID,
+		},
+		Type:                    db.NotificationTypeFriendRemoved,
+		Message:                 fmt.Sprintf("%s removed you as a friend", callerName(ctx, q)),
+		ReferenceConversationID: dmID,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveFriend: create notification: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveFriend: commit: %v", err)
+	}
+
+	return &pb.FriendResponse{Status: "removed"}, nil
+}
+
+// BlockUser blocks target_username for the caller. Any friendship or pending
+// request between them is deleted without notifying the target, which makes
+// their DM read-only. Blocking someone already blocked is a no-op.
+func (s *FriendshipServer) BlockUser(ctx context.Context, req *pb.FriendRequest) (*pb.FriendResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target := req.GetTargetUsername()
+
+	if target == "" {
+		return nil, status.Error(codes.InvalidArgument, "target_username is required")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "BlockUser: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	targetUser, err := q.GetUserByUsername(ctx, target)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.InvalidArgument, "user %q not found", target)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "BlockUser: get target user: %v", err)
+	}
+	targetID := targetUser.UserID
+	if targetID == callerID {
+		return nil, status.Error(codes.InvalidArgument, "cannot block yourself")
+	}
+
+	if _, err := q.BlockUser(ctx, db.BlockUserParams{
+		BlockerID: callerID,
+		BlockedID: targetID,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "BlockUser: insert: %v", err)
+	}
+
+	first, second := lib.OrderedUUIDPair(callerID, targetID)
+	if err := q.DeleteFriendship(ctx, db.DeleteFriendshipParams{
+		User1Userid: first,
+		User2Userid: second,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "BlockUser: delete friendship: %v", err)
+	}
+	if err := q.DeleteFriendLabelMembershipsBetween(ctx, db.DeleteFriendLabelMembershipsBetweenParams{
+		UserA: callerID,
+		UserB: targetID,
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "BlockUser: remove from labels: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "BlockUser: commit: %v", err)
+	}
+
+	return &pb.FriendResponse{Status: "blocked"}, nil
+}
+
+// UnblockUser lifts the caller's block on target_username. The friendship is
+// not restored; either side has to send a new friend request.
+func (s *FriendshipServer) UnblockUser(ctx context.Context, req *pb.FriendRequest) (*pb.FriendResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target := req.GetTargetUsername()
+
+	if target == "" {
+		return nil, status.Error(codes.InvalidArgument, "target_username is required")
+	}
+
+	q := db.New(s.sqlDB)
+
+	targetUser, err := q.GetUserByUsername(ctx, target)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.InvalidArgument, "user %q not found", target)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "UnblockUser: get target user: %v", err)
+	}
+
+	n, err := q.UnblockUser(ctx, db.UnblockUserParams{
+		BlockerID: callerID,
+		BlockedID: targetUser.UserID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "UnblockUser: delete: %v", err)
+	}
+	if n == 0 {
+		return nil, status.Error(codes.NotFound, "user is not blocked")
+	}
+
+	return &pb.FriendResponse{Status: "unblocked"}, nil
+}
+
+// ListBlocked returns the users the caller has blocked, most recent first.
+func (s *FriendshipServer) ListBlocked(ctx context.Context, _ *pb.ListBlockedRequest) (*pb.ListBlockedResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.New(s.sqlDB).ListBlocked(ctx, callerID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ListBlocked: %v", err)
+	}
+
+	users := make([]*pb.BlockedUser, 0, len(rows))
+	for _, r := range rows {
+		users = append(users, &pb.BlockedUser{
+			UserId:      r.UserID.String(),
+			Username:    r.UserName,
+			DisplayName: r.DisplayName.String,
+			AvatarUrl:   r.AvatarUrl.String,
+			BlockedAt:   r.BlockedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &pb.ListBlockedResponse{Users: users}, nil
+}
+
+// SuggestFriends returns people the caller may know: users they are not
+// friends with and have no pending request with, ranked by mutual friends and
+// then by shared group conversations. Blocked users in either direction are
+// never suggested.
+func (s *FriendshipServer) SuggestFriends(ctx context.Context, req *pb.SuggestFriendsRequest) (*pb.SuggestFriendsResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	q := db.New(s.sqlDB)
+	rows, err := q.SuggestFriends(ctx, db.SuggestFriendsParams{CallerID: callerID, PageLimit: limit})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SuggestFriends: %v", err)
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(rows))
+	for _, r := range rows {
+		userIDs = append(userIDs, r.UserID)
+	}
+	mutual, err := mutualFriends(ctx, q, callerID, userIDs)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "SuggestFriends: mutual friends: %v", err)
+	}
+
+	suggestions := make([]*pb.FriendSuggestion, 0, len(rows))
+	for _, r := range rows {
+		suggestions = append(suggestions, &pb.FriendSuggestion{
+			UserId:            r.UserID.String(),
+			Username:          r.UserName,
+			DisplayName:       r.DisplayName.String,
+			AvatarUrl:         r.AvatarUrl.String,
+			MutualFriendCount: r.MutualCount,
+			MutualFriends:     mutual[r.UserID].Sample,
+			SharedGroupCount:  r.SharedGroupCount,
+		})
+	}
+
+	return &pb.SuggestFriendsResponse{Suggestions: suggestions}, nil
+}
+
+// mutualFriendSample is how many mutual friends are named next to a count.
+const mutualFriendSample = 3
+
+// mutualFriends returns, keyed by user, how many accepted friends each of
+// userIDs shares with callerID and up to mutualFriendSample of their
+// usernames. Users with no mutual friends are absent from the map.
+func mutualFriends(ctx context.Context, q *db.Queries, callerID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]db.GetMutualFriendsRow, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := q.GetMutualFriends(ctx, db.GetMutualFriendsParams{
+		CallerID:   callerID,
+		UserIds:    userIDs,
+		SampleSize: mutualFriendSample,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[uuid.UUID]db.GetMutualFriendsRow, len(rows))
+	for _, r := range rows {
+		byUser[r.UserID] = r
+	}
+	return byUser, nil
+}
+
+// GetFriends returns all accepted friends for the authenticated caller, plus
+// pending requests they received. With label_id set, only the accepted
+// friends in that label are returned.
+func (s *FriendshipServer) GetFriends(ctx context.Context, req *pb.GetFriendsRequest) (*pb.GetFriendsResponse, error) {
 	callerID, err := lib.CallerUUID(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	q := db.New(s.sqlDB)
-	rows, err := q.GetFriends(ctx, callerID)
+
+	params := db.GetFriendsParams{UserID: callerID}
+	if req.GetLabelId() != 0 {
+		if _, err := getLabel(ctx, q, req.GetLabelId(), callerID); err != nil {
+			return nil, err
+		}
+		params.LabelID = sql.NullInt64{Valid: true, Int64: req.GetLabelId()}
+	}
+
+	rows, err := q.GetFriends(ctx, params)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "GetFriends: %v", err)
 	}
@@ -200,6 +703,7 @@ func (s *FriendshipServer) GetFriends(ctx context.Context, _ *pb.GetFriendsReque
 			UserId:   r.FriendUserid.String(),
 			Username: r.FriendUsername,
 			Status:   string(r.Status),
+			LabelIds: r.LabelIds,
 		}
 		if r.FriendDisplayName.Valid {
 			f.DisplayName = r.FriendDisplayName.String
@@ -221,7 +725,6 @@ func (s *FriendshipServer) respondToRequest(ctx context.Context, req *pb.FriendR
 	if err != nil {
 		return nil, err
 	}
-	callerName := lib.CallerFrom(ctx)
 	target := req.GetTargetUsername()
 
 	if target == "" {
@@ -282,8 +785,9 @@ func (s *FriendshipServer) respondToRequest(ctx context.Context, req *pb.FriendR
 			UUID:  callerID,
 			Valid: true,
 		},
-		Type:    db.NotificationTypeFriendRequest,
-		Message: callerName + " accepted your friend request",
+		Type:            db.NotificationTypeFriendAccepted,
+		Message:         callerName(ctx, q) + " accepted your friend request",
+		ReferenceUserID: uuid.NullUUID{Valid: true, UUID: callerID},
 	}); err != nil {
 		return nil, status.Errorf(codes.Internal, "respondToRequest: create notification: %v", err)
 	}
@@ -1,10 +1,17 @@
 package services_test
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
 	"github.com/zukigit/chat/backend/internal/services"
+	"github.com/zukigit/chat/backend/proto/auth"
+	"github.com/zukigit/chat/backend/proto/chat"
 	pb "github.com/zukigit/chat/backend/proto/friendship"
 	"google.golang.org/grpc/codes"
 )
@@ -153,12 +160,21 @@ func TestFriendshipStateTransitions(t *testing.T) {
 			wantErr: codes.NotFound,
 		},
 		{
-			name:  "send after rejection returns pending status",
+			name:  "send after rejection is held by the decline cooldown",
+			setup: []setupStep{mustSend("alice", "bob"), mustReject("bob", "alice")},
+			final: func(_ *testing.T, fs *services.FriendshipServer, ids map[string]uuid.UUID) codes.Code {
+				_, err := fs.SendFriendRequest(ctxWithUser("alice", ids["alice"]), &pb.FriendRequest{TargetUsername: "bob"})
+				return grpcCode(err)
+			},
+			wantErr: codes.FailedPrecondition,
+		},
+		{
+			name:  "addressee may send after rejecting",
 			setup: []setupStep{mustSend("alice", "bob"), mustReject("bob", "alice")},
 			final: func(t *testing.T, fs *services.FriendshipServer, ids map[string]uuid.UUID) codes.Code {
-				resp, err := fs.SendFriendRequest(ctxWithUser("alice", ids["alice"]), &pb.FriendRequest{TargetUsername: "bob"})
+				resp, err := fs.SendFriendRequest(ctxWithUser("bob", ids["bob"]), &pb.FriendRequest{TargetUsername: "alice"})
 				if err == nil && resp.Status != "pending" {
-					t.Errorf("expected status 'pending' after re-send, got %q", resp.Status)
+					t.Errorf("expected status 'pending', got %q", resp.Status)
 				}
 				return grpcCode(err)
 			},
@@ -332,3 +348,676 @@ func TestGetFriends(t *testing.T) {
 		}
 	})
 }
+
+func TestRemoveFriend(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	js := setupTestNats(t)
+	notif := services.NewNotificationServer(sqlDB, js)
+	fs := services.NewFriendshipServer(sqlDB, notif)
+	chatServer := services.NewChatServer(sqlDB, notif)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+	if _, err := fs.SendFriendRequest(alice, &pb.FriendRequest{TargetUsername: "carol"}); err != nil {
+		t.Fatalf("setup: send pending request: %v", err)
+	}
+	dm, err := chatServer.CreateConversation(alice,
+		&chat.CreateConversationRequest{IsGroup: false, MembersUsername: []string{"bob"}},
+	)
+	if err != nil {
+		t.Fatalf("setup DM: %v", err)
+	}
+	send := func(ctx context.Context) error {
+		_, err := chatServer.SendMessage(ctx,
+			&chat.SendMessageRequest{ConversationId: dm.ConversationId, MessageId: uuid.New().String(), Content: "hi"},
+		)
+		return err
+	}
+	if err := send(bob); err != nil {
+		t.Fatalf("setup send: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		target  string
+		wantErr codes.Code
+	}{
+		{"missing target", "", codes.InvalidArgument},
+		{"pending request", "carol", codes.FailedPrecondition},
+		{"friend", "bob", codes.OK},
+		{"already removed", "bob", codes.NotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := fs.RemoveFriend(alice, &pb.FriendRequest{TargetUsername: tc.target})
+			if got := grpcCode(err); got != tc.wantErr {
+				t.Errorf("got %v, want %v", got, tc.wantErr)
+			}
+		})
+	}
+
+	t.Run("gone from both friend lists", func(t *testing.T) {
+		resp, err := fs.GetFriends(bob, &pb.GetFriendsRequest{})
+		if err != nil {
+			t.Fatalf("GetFriends: %v", err)
+		}
+		if len(resp.Friends) != 0 {
+			t.Errorf("bob friends: got %d, want 0", len(resp.Friends))
+		}
+	})
+
+	t.Run("DM is read-only", func(t *testing.T) {
+		err := send(bob)
+		if got := grpcCode(err); got != codes.FailedPrecondition {
+			t.Fatalf("send: got %v, want FailedPrecondition", got)
+		}
+		if code, _ := lib.ChatErrorDetails(err); code != lib.ChatErrorReadOnly {
+			t.Errorf("error code: got %q, want %q", code, lib.ChatErrorReadOnly)
+		}
+
+		conv, err := chatServer.GetConversation(bob, &chat.GetConversationRequest{ConversationId: dm.ConversationId})
+		if err != nil {
+			t.Fatalf("GetConversation: %v", err)
+		}
+		if !conv.ReadOnly {
+			t.Error("read_only: got false, want true")
+		}
+
+		msgs, err := chatServer.GetMessages(bob, &chat.GetMessagesRequest{ConversationId: dm.ConversationId})
+		if err != nil {
+			t.Fatalf("GetMessages: %v", err)
+		}
+		if len(msgs.Messages) != 1 {
+			t.Errorf("history: got %d messages, want 1", len(msgs.Messages))
+		}
+	})
+
+	t.Run("DM is writable again after re-friending", func(t *testing.T) {
+		makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+		if err := send(alice); err != nil {
+			t.Errorf("send: %v", err)
+		}
+	})
+}
+
+func TestBlockUser(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	js := setupTestNats(t)
+	notif := services.NewNotificationServer(sqlDB, js)
+	fs := services.NewFriendshipServer(sqlDB, notif)
+	chatServer := services.NewChatServer(sqlDB, notif)
+	authServer := services.NewAuthServer(sqlDB, nil)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "mallory")
+
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+	mallory := ctxWithUser("mallory", ids["mallory"])
+
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+	dm, err := chatServer.CreateConversation(alice,
+		&chat.CreateConversationRequest{IsGroup: false, MembersUsername: []string{"bob"}},
+	)
+	if err != nil {
+		t.Fatalf("setup DM: %v", err)
+	}
+	if _, err := fs.SendFriendRequest(mallory, &pb.FriendRequest{TargetUsername: "alice"}); err != nil {
+		t.Fatalf("setup: send pending request: %v", err)
+	}
+
+	for _, target := range []string{"bob", "mallory"} {
+		if _, err := fs.BlockUser(alice, &pb.FriendRequest{TargetUsername: target}); err != nil {
+			t.Fatalf("BlockUser %s: %v", target, err)
+		}
+	}
+
+	steps := []struct {
+		name    string
+		call    func() error
+		wantErr codes.Code
+	}{
+		{"cannot block yourself", func() error {
+			_, err := fs.BlockUser(alice, &pb.FriendRequest{TargetUsername: "alice"})
+			return err
+		}, codes.InvalidArgument},
+		{"blocking again is a no-op", func() error {
+			_, err := fs.BlockUser(alice, &pb.FriendRequest{TargetUsername: "bob"})
+			return err
+		}, codes.OK},
+		{"pending request was removed", func() error {
+			_, err := fs.AcceptFriendRequest(alice, &pb.FriendRequest{TargetUsername: "mallory"})
+			return err
+		}, codes.NotFound},
+		{"blocked user cannot send a request", func() error {
+			_, err := fs.SendFriendRequest(mallory, &pb.FriendRequest{TargetUsername: "alice"})
+			return err
+		}, codes.InvalidArgument},
+		{"blocker cannot send a request", func() error {
+			_, err := fs.SendFriendRequest(alice, &pb.FriendRequest{TargetUsername: "mallory"})
+			return err
+		}, codes.FailedPrecondition},
+		{"blocked user cannot message the DM", func() error {
+			_, err := chatServer.SendMessage(bob,
+				&chat.SendMessageRequest{ConversationId: dm.ConversationId, MessageId: uuid.New().String(), Content: "hi"},
+			)
+			return err
+		}, codes.FailedPrecondition},
+		{"blocked user cannot open a conversation", func() error {
+			_, err := chatServer.CreateConversation(bob,
+				&chat.CreateConversationRequest{IsGroup: false, MembersUsername: []string{"alice"}},
+			)
+			return err
+		}, codes.NotFound},
+	}
+	for _, st := range steps {
+		t.Run(st.name, func(t *testing.T) {
+			if got := grpcCode(st.call()); got != st.wantErr {
+				t.Errorf("got %v, want %v", got, st.wantErr)
+			}
+		})
+	}
+
+	t.Run("friendship is gone", func(t *testing.T) {
+		resp, err := fs.GetFriends(bob, &pb.GetFriendsRequest{})
+		if err != nil {
+			t.Fatalf("GetFriends: %v", err)
+		}
+		if len(resp.Friends) != 0 {
+			t.Errorf("bob friends: got %d, want 0", len(resp.Friends))
+		}
+	})
+
+	t.Run("blocker is hidden from search", func(t *testing.T) {
+		resp, err := authServer.SearchUsers(mallory, &auth.SearchUsersRequest{Query: "alice"})
+		if err != nil {
+			t.Fatalf("SearchUsers: %v", err)
+		}
+		if len(resp.Users) != 0 {
+			t.Errorf("got %d users, want 0", len(resp.Users))
+		}
+	})
+
+	t.Run("ListBlocked", func(t *testing.T) {
+		resp, err := fs.ListBlocked(alice, &pb.ListBlockedRequest{})
+		if err != nil {
+			t.Fatalf("ListBlocked: %v", err)
+		}
+		if len(resp.Users) != 2 {
+			t.Fatalf("got %d blocked users, want 2", len(resp.Users))
+		}
+		if resp.Users[0].Username != "mallory" {
+			t.Errorf("most recent block: got %q, want mallory", resp.Users[0].Username)
+		}
+	})
+
+	t.Run("unblock", func(t *testing.T) {
+		if _, err := fs.UnblockUser(alice, &pb.FriendRequest{TargetUsername: "mallory"}); err != nil {
+			t.Fatalf("UnblockUser: %v", err)
+		}
+		_, err := fs.UnblockUser(alice, &pb.FriendRequest{TargetUsername: "mallory"})
+		if got := grpcCode(err); got != codes.NotFound {
+			t.Errorf("unblock again: got %v, want NotFound", got)
+		}
+		if _, err := fs.SendFriendRequest(mallory, &pb.FriendRequest{TargetUsername: "alice"}); err != nil {
+			t.Errorf("SendFriendRequest after unblock: %v", err)
+		}
+	})
+}
+
+func TestListAndCancelFriendRequests(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	js := setupTestNats(t)
+	notif := services.NewNotificationServer(sqlDB, js)
+	fs := services.NewFriendshipServer(sqlDB, notif)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+
+	for _, target := range []string{"bob", "carol"} {
+		if _, err := fs.SendFriendRequest(alice, &pb.FriendRequest{TargetUsername: target}); err != nil {
+			t.Fatalf("setup: send to %s: %v", target, err)
+		}
+	}
+
+	t.Run("invalid direction", func(t *testing.T) {
+		_, err := fs.ListFriendRequests(alice, &pb.ListFriendRequestsRequest{Direction: "sideways"})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("got %v, want InvalidArgument", got)
+		}
+	})
+
+	t.Run("outgoing pages newest first", func(t *testing.T) {
+		var got []string
+		cursor := ""
+		for {
+			resp, err := fs.ListFriendRequests(alice, &pb.ListFriendRequestsRequest{Direction: "outgoing", Limit: 1, Cursor: cursor})
+			if err != nil {
+				t.Fatalf("ListFriendRequests: %v", err)
+			}
+			for _, r := range resp.Requests {
+				got = append(got, r.Username)
+			}
+			if resp.NextCursor == "" {
+				break
+			}
+			cursor = resp.NextCursor
+		}
+		if len(got) != 2 || got[0] != "carol" || got[1] != "bob" {
+			t.Errorf("got %v, want [carol bob]", got)
+		}
+	})
+
+	t.Run("incoming", func(t *testing.T) {
+		resp, err := fs.ListFriendRequests(bob, &pb.ListFriendRequestsRequest{Direction: "incoming"})
+		if err != nil {
+			t.Fatalf("ListFriendRequests: %v", err)
+		}
+		if len(resp.Requests) != 1 || resp.Requests[0].Username != "alice" {
+			t.Errorf("got %v, want [alice]", resp.Requests)
+		}
+	})
+
+	cases := []struct {
+		name    string
+		caller  string
+		target  string
+		wantErr codes.Code
+	}{
+		{"missing target", "alice", "", codes.InvalidArgument},
+		{"recipient cannot cancel", "bob", "alice", codes.PermissionDenied},
+		{"requester cancels", "alice", "bob", codes.OK},
+		{"already cancelled", "alice", "bob", codes.NotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := fs.CancelFriendRequest(ctxWithUser(tc.caller, ids[tc.caller]), &pb.FriendRequest{TargetUsername: tc.target})
+			if got := grpcCode(err); got != tc.wantErr {
+				t.Errorf("got %v, want %v (err: %v)", got, tc.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("accepted request cannot be cancelled", func(t *testing.T) {
+		carol := ctxWithUser("carol", ids["carol"])
+		if _, err := fs.AcceptFriendRequest(carol, &pb.FriendRequest{TargetUsername: "alice"}); err != nil {
+			t.Fatalf("AcceptFriendRequest: %v", err)
+		}
+		_, err := fs.CancelFriendRequest(alice, &pb.FriendRequest{TargetUsername: "carol"})
+		if got := grpcCode(err); got != codes.FailedPrecondition {
+			t.Errorf("got %v, want FailedPrecondition", got)
+		}
+	})
+
+	t.Run("notification retracted", func(t *testing.T) {
+		notis, err := db.New(sqlDB).GetNotificationsForUser(context.Background(), ids["bob"])
+		if err != nil {
+			t.Fatalf("GetNotificationsForUser: %v", err)
+		}
+		if len(notis) != 0 {
+			t.Errorf("bob notifications: got %d, want 0", len(notis))
+		}
+	})
+
+	t.Run("cancelled request is gone", func(t *testing.T) {
+		resp, err := fs.ListFriendRequests(bob, &pb.ListFriendRequestsRequest{Direction: "incoming"})
+		if err != nil {
+			t.Fatalf("ListFriendRequests: %v", err)
+		}
+		if len(resp.Requests) != 0 {
+			t.Errorf("got %d requests, want 0", len(resp.Requests))
+		}
+	})
+}
+
+func TestSuggestFriends(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	fs := services.NewFriendshipServer(sqlDB, nil)
+	chatServer := services.NewChatServer(sqlDB, nil)
+	authServer := services.NewAuthServer(sqlDB, nil)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol", "dave", "erin", "frank", "gina", "mallory")
+
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+
+	for _, pair := range [][2]string{
+		{"alice", "bob"}, {"alice", "carol"},
+		{"dave", "bob"}, {"dave", "carol"}, // dave: 2 mutual
+		{"erin", "bob"},  // erin: 1 mutual
+		{"frank", "bob"}, // frank: 1 mutual + shared group
+		{"gina", "bob"}, {"mallory", "bob"},
+	} {
+		makeFriends(t, sqlDB, ids[pair[0]], ids[pair[1]])
+	}
+	if _, err := chatServer.CreateConversation(bob, &chat.CreateConversationRequest{
+		IsGroup: true, Name: "crew", MembersUsername: []string{"alice", "frank"},
+	}); err != nil {
+		t.Fatalf("setup group: %v", err)
+	}
+	if _, err := fs.SendFriendRequest(alice, &pb.FriendRequest{TargetUsername: "gina"}); err != nil {
+		t.Fatalf("setup request: %v", err)
+	}
+	if _, err := fs.BlockUser(alice, &pb.FriendRequest{TargetUsername: "mallory"}); err != nil {
+		t.Fatalf("setup block: %v", err)
+	}
+
+	t.Run("ranked by mutual friends then shared groups", func(t *testing.T) {
+		resp, err := fs.SuggestFriends(alice, &pb.SuggestFriendsRequest{})
+		if err != nil {
+			t.Fatalf("SuggestFriends: %v", err)
+		}
+		var got []string
+		for _, s := range resp.Suggestions {
+			got = append(got, s.Username)
+		}
+		if strings.Join(got, ",") != "dave,frank,erin" {
+			t.Fatalf("got %v, want [dave frank erin]", got)
+		}
+
+		dave, frank := resp.Suggestions[0], resp.Suggestions[1]
+		if dave.MutualFriendCount != 2 || strings.Join(dave.MutualFriends, ",") != "bob,carol" {
+			t.Errorf("dave: got %d %v, want 2 [bob carol]", dave.MutualFriendCount, dave.MutualFriends)
+		}
+		if frank.SharedGroupCount != 1 {
+			t.Errorf("frank shared groups: got %d, want 1", frank.SharedGroupCount)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		resp, err := fs.SuggestFriends(alice, &pb.SuggestFriendsRequest{Limit: 1})
+		if err != nil {
+			t.Fatalf("SuggestFriends: %v", err)
+		}
+		if len(resp.Suggestions) != 1 {
+			t.Errorf("got %d suggestions, want 1", len(resp.Suggestions))
+		}
+	})
+
+	t.Run("search results carry mutual friends", func(t *testing.T) {
+		resp, err := authServer.SearchUsers(alice, &auth.SearchUsersRequest{Query: "dave"})
+		if err != nil {
+			t.Fatalf("SearchUsers: %v", err)
+		}
+		if len(resp.Users) != 1 {
+			t.Fatalf("got %d users, want 1", len(resp.Users))
+		}
+		if u := resp.Users[0]; u.MutualFriendCount != 2 || len(u.MutualFriends) != 2 {
+			t.Errorf("got %d %v, want 2 mutual friends", u.MutualFriendCount, u.MutualFriends)
+		}
+	})
+}
+
+func TestFriendLabels(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	fs := services.NewFriendshipServer(sqlDB, nil)
+	chatServer := services.NewChatServer(sqlDB, nil)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol", "dave", "mallory")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+	makeFriends(t, sqlDB, ids["alice"], ids["carol"])
+	makeFriends(t, sqlDB, ids["alice"], ids["dave"])
+
+	alice := ctxWithUser("alice", ids["alice"])
+	mallory := ctxWithUser("mallory", ids["mallory"])
+
+	work, err := fs.CreateLabel(alice, &pb.CreateLabelRequest{Name: " work "})
+	if err != nil {
+		t.Fatalf("CreateLabel: %v", err)
+	}
+	if work.Name != "work" {
+		t.Errorf("name: got %q, want trimmed %q", work.Name, "work")
+	}
+
+	t.Run("create errors", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			name string
+			want codes.Code
+		}{
+			"empty":     {"  ", codes.InvalidArgument},
+			"too long":  {strings.Repeat("a", 51), codes.InvalidArgument},
+			"duplicate": {"WORK", codes.AlreadyExists},
+		} {
+			_, err := fs.CreateLabel(alice, &pb.CreateLabelRequest{Name: tc.name})
+			if got := grpcCode(err); got != tc.want {
+				t.Errorf("%s: got %v, want %v", name, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("members", func(t *testing.T) {
+		_, err := fs.AddLabelMembers(alice, &pb.LabelMembersRequest{LabelId: work.Id, Usernames: []string{"bob", "mallory"}})
+		if got := grpcCode(err); got != codes.PermissionDenied {
+			t.Errorf("non-friend: got %v, want PermissionDenied", got)
+		}
+		label, err := fs.AddLabelMembers(alice, &pb.LabelMembersRequest{LabelId: work.Id, Usernames: []string{"bob", "carol", "bob"}})
+		if err != nil {
+			t.Fatalf("AddLabelMembers: %v", err)
+		}
+		if label.MemberCount != 2 {
+			t.Errorf("member_count: got %d, want 2", label.MemberCount)
+		}
+		_, err = fs.AddLabelMembers(mallory, &pb.LabelMembersRequest{LabelId: work.Id, Usernames: []string{"alice"}})
+		if got := grpcCode(err); got != codes.NotFound {
+			t.Errorf("other user's label: got %v, want NotFound", got)
+		}
+	})
+
+	t.Run("get friends by label", func(t *testing.T) {
+		resp, err := fs.GetFriends(alice, &pb.GetFriendsRequest{LabelId: work.Id})
+		if err != nil {
+			t.Fatalf("GetFriends: %v", err)
+		}
+		var names []string
+		for _, f := range resp.Friends {
+			names = append(names, f.Username)
+			if len(f.LabelIds) != 1 || f.LabelIds[0] != work.Id {
+				t.Errorf("%s label_ids: got %v, want [%d]", f.Username, f.LabelIds, work.Id)
+			}
+		}
+		if strings.Join(names, ",") != "bob,carol" && strings.Join(names, ",") != "carol,bob" {
+			t.Errorf("friends: got %v, want bob and carol", names)
+		}
+
+		_, err = fs.GetFriends(mallory, &pb.GetFriendsRequest{LabelId: work.Id})
+		if got := grpcCode(err); got != codes.NotFound {
+			t.Errorf("other user's label: got %v, want NotFound", got)
+		}
+	})
+
+	t.Run("create conversation from label", func(t *testing.T) {
+		resp, err := chatServer.CreateConversation(alice, &chat.CreateConversationRequest{
+			IsGroup: true, Name: "team", MembersUsername: []string{"dave", "bob"}, LabelId: work.Id,
+		})
+		if err != nil {
+			t.Fatalf("CreateConversation: %v", err)
+		}
+		conv, err := chatServer.GetConversation(alice, &chat.GetConversationRequest{ConversationId: resp.ConversationId})
+		if err != nil {
+			t.Fatalf("GetConversation: %v", err)
+		}
+		if len(conv.Members) != 4 {
+			t.Errorf("members: got %d, want alice, bob, carol and dave", len(conv.Members))
+		}
+
+		_, err = chatServer.CreateConversation(mallory, &chat.CreateConversationRequest{IsGroup: true, Name: "x", LabelId: work.Id})
+		if got := grpcCode(err); got != codes.NotFound {
+			t.Errorf("other user's label: got %v, want NotFound", got)
+		}
+	})
+
+	t.Run("unfriending removes from labels", func(t *testing.T) {
+		if _, err := fs.RemoveFriend(alice, &pb.FriendRequest{TargetUsername: "carol"}); err != nil {
+			t.Fatalf("RemoveFriend: %v", err)
+		}
+		resp, err := fs.ListLabels(alice, &pb.ListLabelsRequest{})
+		if err != nil {
+			t.Fatalf("ListLabels: %v", err)
+		}
+		if len(resp.Labels) != 1 || resp.Labels[0].MemberCount != 1 {
+			t.Errorf("labels: got %+v, want work with 1 member", resp.Labels)
+		}
+	})
+
+	t.Run("rename, remove and delete", func(t *testing.T) {
+		if _, err := fs.CreateLabel(alice, &pb.CreateLabelRequest{Name: "family"}); err != nil {
+			t.Fatalf("CreateLabel: %v", err)
+		}
+		_, err := fs.RenameLabel(alice, &pb.RenameLabelRequest{LabelId: work.Id, Name: "Family"})
+		if got := grpcCode(err); got != codes.AlreadyExists {
+			t.Errorf("rename to taken name: got %v, want AlreadyExists", got)
+		}
+		label, err := fs.RenameLabel(alice, &pb.RenameLabelRequest{LabelId: work.Id, Name: "colleagues"})
+		if err != nil || label.Name != "colleagues" {
+			t.Fatalf("RenameLabel: got %+v, err %v", label, err)
+		}
+
+		label, err = fs.RemoveLabelMembers(alice, &pb.LabelMembersRequest{LabelId: work.Id, Usernames: []string{"bob", "nobody"}})
+		if err != nil || label.MemberCount != 0 {
+			t.Fatalf("RemoveLabelMembers: got %+v, err %v", label, err)
+		}
+
+		if _, err := fs.DeleteLabel(mallory, &pb.DeleteLabelRequest{LabelId: work.Id}); grpcCode(err) != codes.NotFound {
+			t.Errorf("delete other user's label: got %v, want NotFound", grpcCode(err))
+		}
+		if _, err := fs.DeleteLabel(alice, &pb.DeleteLabelRequest{LabelId: work.Id}); err != nil {
+			t.Fatalf("DeleteLabel: %v", err)
+		}
+		resp, err := fs.GetFriends(alice, &pb.GetFriendsRequest{})
+		if err != nil {
+			t.Fatalf("GetFriends: %v", err)
+		}
+		if len(resp.Friends) != 2 {
+			t.Errorf("friends after deleting label: got %d, want 2", len(resp.Friends))
+		}
+	})
+}
+
+func TestFriendRequestLimits(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	fs := services.NewFriendshipServer(sqlDB, nil)
+	fs.SetRequestLimits(services.FriendRequestLimits{PerHour: 2, PerDay: 3, DeclineCooldown: time.Hour})
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol", "dave", "erin")
+
+	alice := ctxWithUser("alice", ids["alice"])
+	send := func(target string) error {
+		_, err := fs.SendFriendRequest(alice, &pb.FriendRequest{TargetUsername: target})
+		return err
+	}
+	backdateLog := func(t *testing.T, by time.Duration) {
+		t.Helper()
+		if _, err := sqlDB.Exec(`UPDATE friend_request_log SET sent_at = sent_at - $1::interval WHERE sender_id = $2`,
+			by.String(), ids["alice"]); err != nil {
+			t.Fatalf("backdate log: %v", err)
+		}
+	}
+
+	t.Run("hourly limit", func(t *testing.T) {
+		for _, target := range []string{"bob", "carol"} {
+			if err := send(target); err != nil {
+				t.Fatalf("send to %s: %v", target, err)
+			}
+		}
+		err := send("dave")
+		if got := grpcCode(err); got != codes.ResourceExhausted {
+			t.Fatalf("third send: got %v, want ResourceExhausted", got)
+		}
+		if _, retryAfter := lib.ChatErrorDetails(err); retryAfter <= 0 || retryAfter > time.Hour {
+			t.Errorf("retry after: got %v, want (0, 1h]", retryAfter)
+		}
+	})
+
+	t.Run("cancelling does not refund a request", func(t *testing.T) {
+		if _, err := fs.CancelFriendRequest(alice, &pb.FriendRequest{TargetUsername: "bob"}); err != nil {
+			t.Fatalf("CancelFriendRequest: %v", err)
+		}
+		if got := grpcCode(send("dave")); got != codes.ResourceExhausted {
+			t.Errorf("got %v, want ResourceExhausted", got)
+		}
+	})
+
+	t.Run("daily limit", func(t *testing.T) {
+		backdateLog(t, 2*time.Hour)
+		if err := send("dave"); err != nil {
+			t.Fatalf("send to dave: %v", err)
+		}
+		if got := grpcCode(send("erin")); got != codes.ResourceExhausted {
+			t.Errorf("got %v, want ResourceExhausted", got)
+		}
+	})
+
+	t.Run("decline cooldown", func(t *testing.T) {
+		backdateLog(t, 25*time.Hour)
+		if _, err := fs.RejectFriendRequest(ctxWithUser("dave", ids["dave"]), &pb.FriendRequest{TargetUsername: "alice"}); err != nil {
+			t.Fatalf("RejectFriendRequest: %v", err)
+		}
+		if got := grpcCode(send("dave")); got != codes.FailedPrecondition {
+			t.Fatalf("got %v, want FailedPrecondition", got)
+		}
+
+		if _, err := sqlDB.Exec(`UPDATE friend_request_declines SET declined_at = declined_at - INTERVAL '2 hours'`); err != nil {
+			t.Fatalf("backdate decline: %v", err)
+		}
+		if err := send("dave"); err != nil {
+			t.Errorf("send after cooldown: %v", err)
+		}
+	})
+}
+
+func TestFriendRequestExpiry(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	js := setupTestNats(t)
+	notif := services.NewNotificationServer(sqlDB, js)
+	fs := services.NewFriendshipServer(sqlDB, notif)
+	worker := services.NewFriendRequestExpiryWorker(sqlDB, notif, 24*time.Hour)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+	ctx := context.Background()
+
+	alice := ctxWithUser("alice", ids["alice"])
+	for _, target := range []string{"bob", "carol"} {
+		if _, err := fs.SendFriendRequest(alice, &pb.FriendRequest{TargetUsername: target}); err != nil {
+			t.Fatalf("setup: send to %s: %v", target, err)
+		}
+	}
+	first, second := lib.OrderedUUIDPair(ids["alice"], ids["bob"])
+	if _, err := sqlDB.Exec(`UPDATE friendships SET created_at = NOW() - INTERVAL '2 days' WHERE user1_userid = $1 AND user2_userid = $2`,
+		first, second); err != nil {
+		t.Fatalf("backdate request: %v", err)
+	}
+
+	n, err := worker.ExpireBatch(ctx)
+	if err != nil {
+		t.Fatalf("ExpireBatch: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expired: got %d, want 1", n)
+	}
+
+	t.Run("old request is gone", func(t *testing.T) {
+		resp, err := fs.ListFriendRequests(alice, &pb.ListFriendRequestsRequest{Direction: "outgoing"})
+		if err != nil {
+			t.Fatalf("ListFriendRequests: %v", err)
+		}
+		if len(resp.Requests) != 1 || resp.Requests[0].Username != "carol" {
+			t.Errorf("got %v, want [carol]", resp.Requests)
+		}
+	})
+
+	t.Run("notifications", func(t *testing.T) {
+		for name, want := range map[string]int{"bob": 0, "carol": 1} {
+			notis, err := db.New(sqlDB).GetNotificationsForUser(ctx, ids[name])
+			if err != nil {
+				t.Fatalf("GetNotificationsForUser(%s): %v", name, err)
+			}
+			if len(notis) != want {
+				t.Errorf("%s notifications: got %d, want %d", name, len(notis), want)
+			}
+		}
+	})
+
+	t.Run("expired request can be sent again", func(t *testing.T) {
+		if _, err := fs.SendFriendRequest(alice, &pb.FriendRequest{TargetUsername: "bob"}); err != nil {
+			t.Errorf("SendFriendRequest: %v", err)
+		}
+	})
+}
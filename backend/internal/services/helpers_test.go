@@ -20,19 +20,33 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// schemaPath returns the absolute path to sqls/init/20260526000000_init.sql.
-func schemaPath() string {
+// migrationsDir returns the absolute path to supabase/migrations.
+func migrationsDir() string {
 	_, filename, _, _ := runtime.Caller(0)
-	return filepath.Join(filepath.Dir(filename), "..", "..", "..", "supabase", "migrations", "20260526000000_init.sql")
+	return filepath.Join(filepath.Dir(filename), "..", "..", "..", "supabase", "migrations")
 }
 
-// setupTestDB starts a throwaway Postgres container, mounts 20260526000000_init.sql via
-// /docker-entrypoint-initdb.d so Postgres runs it automatically, and returns
-// a connected *sql.DB. Container and DB are cleaned up when the test ends.
+// setupTestDB starts a throwaway Postgres container, mounts every migration in
+// supabase/migrations via /docker-entrypoint-initdb.d so Postgres runs them in
+// filename order, and returns a connected *sql.DB. Container and DB are cleaned
+// up when the test ends.
 func setupTestDB(t *testing.T) *sql.DB {
 	t.Helper()
 	ctx := context.Background()
 
+	migrations, err := filepath.Glob(filepath.Join(migrationsDir(), "*.sql"))
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+	files := make([]testcontainers.ContainerFile, 0, len(migrations))
+	for _, m := range migrations {
+		files = append(files, testcontainers.ContainerFile{
+			HostFilePath:      m,
+			ContainerFilePath: "/docker-entrypoint-initdb.d/" + filepath.Base(m),
+			FileMode:          0755,
+		})
+	}
+
 	req := testcontainers.ContainerRequest{
 		Image:        "postgres:16-alpine",
 		ExposedPorts: []string{"5432/tcp"},
@@ -41,13 +55,7 @@ func setupTestDB(t *testing.T) *sql.DB {
 			"POSTGRES_PASSWORD": "test",
 			"POSTGRES_DB":       "chat",
 		},
-		Files: []testcontainers.ContainerFile{
-			{
-				HostFilePath:      schemaPath(),
-				ContainerFilePath: "/docker-entrypoint-initdb.d/20260526000000_init.sql",
-				FileMode:          0755,
-			},
-		},
+		Files:      files,
 		WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
 	}
 
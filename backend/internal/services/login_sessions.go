@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
+	"github.com/zukigit/chat/backend/proto/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ConsumerDeleter deletes JetStream consumers. Implemented by
+// nats.JetStreamContext.
+type ConsumerDeleter interface {
+	DeleteConsumer(stream, consumer string, opts ...nats.JSOpt) error
+}
+
+// CorePublisher publishes a plain NATS message that is not kept in any stream.
+// Implemented by *nats.Conn.
+type CorePublisher interface {
+	Publish(subj string, data []byte) error
+}
+
+// LoginSessions answers whether a login has been signed out, caching each
+// answer for a short time so the per-RPC check stays cheap, and tears down a
+// signed-out login's live delivery.
+//
+// The cache is per process. Disconnect clears it only in the instance that
+// revoked the login, so other backend instances keep accepting the login's
+// tokens until their cached answer expires, at most ttl later.
+type LoginSessions struct {
+	sqlDB     *sql.DB
+	ttl       time.Duration
+	consumers ConsumerDeleter // nil skips deleting the login's consumers
+	events    CorePublisher   // nil skips closing the login's WebSockets
+
+	mu      sync.Mutex
+	entries map[string]loginEntry
+}
+
+// maxLoginEntries is the cache size above which expired entries are swept.
+const maxLoginEntries = 10000
+
+type loginEntry struct {
+	revoked bool
+	expires time.Time
+}
+
+// NewLoginSessions creates a LoginSessions that caches answers for ttl.
+// consumers and events may be nil, e.g. in tests.
+func NewLoginSessions(sqlDB *sql.DB, ttl time.Duration, consumers ConsumerDeleter, events CorePublisher) *LoginSessions {
+	return &LoginSessions{
+		sqlDB:     sqlDB,
+		ttl:       ttl,
+		consumers: consumers,
+		events:    events,
+		entries:   make(map[string]loginEntry),
+	}
+}
+
+// Check returns an Unauthenticated error if loginID has been signed out, or
+// has no session at all once the legacy login cutoff has passed. It has the
+// signature of interceptors.SessionCheck.
+func (l *LoginSessions) Check(ctx context.Context, loginID string) error {
+	now := time.Now()
+
+	l.mu.Lock()
+	entry, ok := l.entries[loginID]
+	l.mu.Unlock()
+	if !ok || !now.Before(entry.expires) {
+		id, err := uuid.Parse(loginID)
+		if err != nil {
+			return status.Error(codes.Unauthenticated, "invalid or expired token")
+		}
+		// Each cache miss also records activity, so last_active_at is
+		// accurate to about the cache TTL.
+		queries := db.New(l.sqlDB)
+		revoked, err := queries.TouchLoginSession(ctx, id)
+		if err != nil && err != sql.ErrNoRows {
+			return status.Errorf(codes.Internal, "check login session: %v", err)
+		}
+		entry = loginEntry{revoked: revoked, expires: now.Add(l.ttl)}
+		if err == sql.ErrNoRows {
+			// Logins from before sessions were recorded have no row. Their
+			// tokens are honoured only until the cutoff.
+			acceptUntil, err := queries.GetLegacyLoginCutoff(ctx)
+			if err != nil && err != sql.ErrNoRows {
+				return status.Errorf(codes.Internal, "check login session: get legacy cutoff: %v", err)
+			}
+			entry.revoked = !now.Before(acceptUntil)
+			if !entry.revoked && acceptUntil.Before(entry.expires) {
+				entry.expires = acceptUntil
+			}
+		}
+
+		l.mu.Lock()
+		if len(l.entries) >= maxLoginEntries {
+			for id, e := range l.entries {
+				if !now.Before(e.expires) {
+					delete(l.entries, id)
+				}
+			}
+		}
+		l.entries[loginID] = entry
+		l.mu.Unlock()
+	}
+
+	if entry.revoked {
+		return status.Error(codes.Unauthenticated, "session has been signed out")
+	}
+	return nil
+}
+
+// Disconnect is called once the revocation of loginID is committed. It drops
+// the cached answer, deletes the login's chat- and noti- durable consumers
+// (see SessionServer.GetListenPath) and tells the gateways to close its
+// WebSockets. Failures are logged: the revocation itself already holds. It is
+// nil-safe.
+func (l *LoginSessions) Disconnect(loginID uuid.UUID) {
+	if l == nil {
+		return
+	}
+	id := loginID.String()
+
+	l.mu.Lock()
+	delete(l.entries, id)
+	l.mu.Unlock()
+
+	if l.consumers != nil {
+		for _, name := range []string{"chat-" + id, "noti-" + id} {
+			if err := l.consumers.DeleteConsumer("SESSIONS", name); err != nil && !errors.Is(err, nats.ErrConsumerNotFound) {
+				lib.ErrorLog.Printf("Disconnect: delete consumer %s: %v", name, err)
+			}
+		}
+	}
+	if l.events != nil {
+		if err := l.events.Publish(lib.LoginRevokedSubjectPrefix+id, nil); err != nil {
+			lib.ErrorLog.Printf("Disconnect: announce revocation of %s: %v", id, err)
+		}
+	}
+}
+
+// SetLoginSessions makes Logout and RevokeSession disconnect signed-out logins
+// through sessions.
+// Without it, logins are still revoked but their live delivery is left to
+// expire.
+func (s *AuthServer) SetLoginSessions(sessions *LoginSessions) {
+	s.sessions = sessions
+}
+
+// maxDeviceNameLength is the longest device name a login may be given.
+const maxDeviceNameLength = 100
+
+// validateDeviceName checks the optional device name sent at sign-in.
+func validateDeviceName(name string) error {
+	if utf8.RuneCountInString(name) > maxDeviceNameLength {
+		return status.Errorf(codes.InvalidArgument, "device_name must be at most %d characters", maxDeviceNameLength)
+	}
+	return nil
+}
+
+// startLogin records a new login for the user, from the device described by
+// deviceName and the client info the gateway forwarded, and issues its first
+// token pair. If the user is signed in elsewhere, those sessions are told
+// about the new login.
+func (s *AuthServer) startLogin(ctx context.Context, queries *db.Queries, userID uuid.UUID, username, deviceName string) (token, refreshToken string, err error) {
+	client := lib.ClientInfoFrom(ctx)
+	loginID := uuid.New()
+	if err := queries.CreateLoginSession(ctx, db.CreateLoginSessionParams{
+		LoginID:    loginID,
+		UserID:     userID,
+		DeviceName: deviceName,
+		UserAgent:  client.UserAgent,
+		IpAddress:  client.IP,
+	}); err != nil {
+		return "", "", err
+	}
+
+	active, err := queries.ListActiveLoginSessions(ctx, db.ListActiveLoginSessionsParams{
+		UserID:      userID,
+		ActiveSince: time.Now().Add(-lib.AccessTokenTTL),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	if len(active) > 1 { // more than the new login itself
+		if err := s.notif.Send(ctx, queries, db.CreateNotificationParams{
+			UserID:  userID,
+			Type:    db.NotificationTypeNewLogin,
+			Message: newLoginMessage(deviceName, client),
+		}); err != nil {
+			return "", "", err
+		}
+	}
+
+	return s.issueTokens(ctx, queries, userID, username, loginID)
+}
+
+// newLoginMessage describes a new login for the security notification.
+func newLoginMessage(deviceName string, client lib.ClientInfo) string {
+	device := deviceName
+	if device == "" {
+		device = client.UserAgent
+	}
+	if device == "" {
+		device = "an unknown device"
+	}
+	if client.IP == "" {
+		return fmt.Sprintf("New sign-in to your account on %s", device)
+	}
+	return fmt.Sprintf("New sign-in to your account on %s from %s", device, client.IP)
+}
+
+// revokeLogin signs out one of the user's logins: the login is marked revoked
+// and its refresh tokens stop working. The caller commits, then calls
+// LoginSessions.Disconnect.
+func revokeLogin(ctx context.Context, queries *db.Queries, userID, loginID uuid.UUID) error {
+	if err := queries.RevokeLoginSession(ctx, db.RevokeLoginSessionParams{LoginID: loginID, UserID: userID}); err != nil {
+		return err
+	}
+	return queries.RevokeLoginRefreshTokens(ctx, loginID)
+}
+
+// Logout signs out the caller's current login. Its tokens stop working
+// immediately, its durable consumers are deleted and its WebSockets closed.
+// Other logins of the same user are untouched.
+func (s *AuthServer) Logout(ctx context.Context, req *auth.LogoutRequest) (*auth.LogoutResponse, error) {
+	userID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loginID, err := uuid.Parse(lib.CallerLoginID(ctx))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "missing login_id in token")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "logout: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := revokeLogin(ctx, db.New(tx), userID, loginID); err != nil {
+		return nil, status.Errorf(codes.Internal, "logout: revoke login: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "logout: commit: %v", err)
+	}
+
+	s.sessions.Disconnect(loginID)
+	return &auth.LogoutResponse{}, nil
+}
+
+// ListSessions lists the places the caller is signed in, most recently
+// active first. Revoked logins and logins whose tokens have all expired are
+// left out.
+func (s *AuthServer) ListSessions(ctx context.Context, req *auth.ListSessionsRequest) (*auth.ListSessionsResponse, error) {
+	userID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := lib.CallerLoginID(ctx)
+
+	rows, err := db.New(s.sqlDB).ListActiveLoginSessions(ctx, db.ListActiveLoginSessionsParams{
+		UserID:      userID,
+		ActiveSince: time.Now().Add(-lib.AccessTokenTTL),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list sessions: list sessions: %v", err)
+	}
+
+	sessions := make([]*auth.LoginSession, 0, len(rows))
+	for _, r := range rows {
+		sessions = append(sessions, &auth.LoginSession{
+			LoginId:      r.LoginID.String(),
+			DeviceName:   r.DeviceName,
+			UserAgent:    r.UserAgent,
+			IpAddress:    r.IpAddress,
+			CreatedAt:    r.CreatedAt.UTC().Format(time.RFC3339),
+			LastActiveAt: r.LastActiveAt.UTC().Format(time.RFC3339),
+			Current:      r.LoginID.String() == current,
+		})
+	}
+	return &auth.ListSessionsResponse{Sessions: sessions}, nil
+}
+
+// RevokeSession signs out one of the caller's logins, e.g. on a lost device,
+// or with all_others every login but the caller's own. Revoked logins are
+// torn down as by Logout.
+func (s *AuthServer) RevokeSession(ctx context.Context, req *auth.RevokeSessionRequest) (*auth.RevokeSessionResponse, error) {
+	userID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := uuid.Parse(lib.CallerLoginID(ctx))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "missing login_id in token")
+	}
+
+	var target uuid.UUID
+	if !req.AllOthers {
+		if target, err = uuid.Parse(req.LoginId); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "login_id must be a UUID unless all_others is set")
+		}
+	} else if req.LoginId != "" {
+		return nil, status.Error(codes.InvalidArgument, "login_id and all_others are mutually exclusive")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "revoke session: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	q := db.New(tx)
+
+	var loginIDs []uuid.UUID
+	if req.AllOthers {
+		loginIDs, err = q.ListUnrevokedLoginIDs(ctx, db.ListUnrevokedLoginIDsParams{UserID: userID, ExceptLoginID: current})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "revoke session: list logins: %v", err)
+		}
+	} else {
+		_, err := q.GetUnrevokedLoginSessionForUpdate(ctx, db.GetUnrevokedLoginSessionForUpdateParams{LoginID: target, UserID: userID})
+		if err == sql.ErrNoRows {
+			return nil, status.Error(codes.NotFound, "session not found")
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "revoke session: get session: %v", err)
+		}
+		loginIDs = []uuid.UUID{target}
+	}
+
+	for _, loginID := range loginIDs {
+		if err := revokeLogin(ctx, q, userID, loginID); err != nil {
+			return nil, status.Errorf(codes.Internal, "revoke session: revoke login: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "revoke session: commit: %v", err)
+	}
+
+	for _, loginID := range loginIDs {
+		s.sessions.Disconnect(loginID)
+	}
+	return &auth.RevokeSessionResponse{RevokedCount: int32(len(loginIDs))}, nil
+}
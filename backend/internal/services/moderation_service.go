@@ -0,0 +1,524 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
+	pb "github.com/zukigit/chat/backend/proto/moderation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Length limits for the free text in a report, in characters.
+const (
+	maxReportDetailsLen   = 2000
+	maxReportPlaintextLen = 10000
+)
+
+// maxSuspendSeconds is the longest suspension, ten years. Use a ban for a
+// permanent restriction.
+const maxSuspendSeconds = 10 * 365 * 24 * 60 * 60
+
+var reportReasons = []db.ReportReason{
+	db.ReportReasonSpam, db.ReportReasonHarassment, db.ReportReasonHate, db.ReportReasonSexualContent,
+	db.ReportReasonViolence, db.ReportReasonImpersonation, db.ReportReasonOther,
+}
+
+var reportStatuses = []db.ReportStatus{
+	db.ReportStatusOpen, db.ReportStatusTriaged, db.ReportStatusResolved, db.ReportStatusDismissed,
+}
+
+// ModerationServer implements the moderation.ModerationServer interface.
+type ModerationServer struct {
+	pb.UnimplementedModerationServer
+	sqlDB    *sql.DB
+	notif    *NotificationServer // nil disables notifications (e.g. in tests)
+	standing *AccountStanding    // nil when no standing cache needs clearing
+}
+
+// NewModerationServer creates a new ModerationServer. notif and standing may
+// be nil. standing is the cache the account interceptor uses; actions clear
+// the target's entry so they apply immediately.
+func NewModerationServer(sqlDB *sql.DB, notif *NotificationServer, standing *AccountStanding) *ModerationServer {
+	return &ModerationServer{sqlDB: sqlDB, notif: notif, standing: standing}
+}
+
+// Report records the caller's report against a user, optionally about one
+// message. A caller may have only one unresolved report per user and message.
+func (s *ModerationServer) Report(ctx context.Context, req *pb.ReportRequest) (*pb.ReportResponse, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reason, ok := lookupEnum(req.GetReason(), reportReasons)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid reason %q (must be one of %v)", req.GetReason(), reportReasons)
+	}
+	if utf8.RuneCountInString(req.GetDetails()) > maxReportDetailsLen {
+		return nil, status.Errorf(codes.InvalidArgument, "details must be at most %d characters", maxReportDetailsLen)
+	}
+	if utf8.RuneCountInString(req.GetMessagePlaintext()) > maxReportPlaintextLen {
+		return nil, status.Errorf(codes.InvalidArgument, "message_plaintext must be at most %d characters", maxReportPlaintextLen)
+	}
+	if req.GetMessageId() == "" && req.GetMessagePlaintext() != "" {
+		return nil, status.Error(codes.InvalidArgument, "message_plaintext requires message_id")
+	}
+	if req.GetMessageId() == "" && req.GetTargetUsername() == "" {
+		return nil, status.Error(codes.InvalidArgument, "target_username or message_id is required")
+	}
+
+	q := db.New(s.sqlDB)
+
+	params := db.CreateReportParams{
+		ReporterID:       callerID,
+		Reason:           reason,
+		Details:          req.GetDetails(),
+		MessagePlaintext: sql.NullString{Valid: req.GetMessagePlaintext() != "", String: req.GetMessagePlaintext()},
+	}
+
+	if req.GetTargetUsername() != "" {
+		target, err := q.GetUserByUsername(ctx, req.GetTargetUsername())
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "user %q not found", req.GetTargetUsername())
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Report: get target: %v", err)
+		}
+		params.TargetUserID = target.UserID
+	}
+
+	if req.GetMessageId() != "" {
+		messageID, err := uuid.Parse(req.GetMessageId())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid message_id: %v", err)
+		}
+		// Only messages the caller could have seen can be reported.
+		senderID, err := q.GetReportableMessage(ctx, db.GetReportableMessageParams{ViewerID: callerID, MessageID: messageID})
+		if err == sql.ErrNoRows {
+			return nil, status.Error(codes.NotFound, "message not found")
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Report: get message: %v", err)
+		}
+		if params.TargetUserID != uuid.Nil && params.TargetUserID != senderID {
+			return nil, status.Errorf(codes.InvalidArgument, "message was not sent by %q", req.GetTargetUsername())
+		}
+		params.TargetUserID = senderID
+		params.MessageID = uuid.NullUUID{Valid: true, UUID: messageID}
+	}
+
+	if params.TargetUserID == callerID {
+		return nil, status.Error(codes.InvalidArgument, "cannot report yourself")
+	}
+
+	report, err := q.CreateReport(ctx, params)
+	if lib.IsPgUniqueViolation(err) {
+		return nil, status.Error(codes.AlreadyExists, "you already have an open report about this")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Report: create: %v", err)
+	}
+
+	return &pb.ReportResponse{
+		ReportId:  report.ID,
+		Status:    string(report.Status),
+		CreatedAt: report.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// ListReports returns a page of the moderation queue, oldest first.
+func (s *ModerationServer) ListReports(ctx context.Context, req *pb.ListReportsRequest) (*pb.ListReportsResponse, error) {
+	q := db.New(s.sqlDB)
+	if _, err := requireAdmin(ctx, q); err != nil {
+		return nil, err
+	}
+
+	var statusFilter db.NullReportStatus
+	if req.GetStatus() != "" {
+		st, ok := lookupEnum(req.GetStatus(), reportStatuses)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid status %q (must be one of %v)", req.GetStatus(), reportStatuses)
+		}
+		statusFilter = db.NullReportStatus{Valid: true, ReportStatus: st}
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	rows, err := q.ListReports(ctx, db.ListReportsParams{
+		Status:    statusFilter,
+		AfterID:   req.GetAfterId(),
+		PageLimit: limit,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ListReports: %v", err)
+	}
+
+	resp := &pb.ListReportsResponse{Reports: make([]*pb.ModerationReport, 0, len(rows))}
+	for _, r := range rows {
+		report := &pb.ModerationReport{
+			Id:                r.ID,
+			ReporterUsername:  r.ReporterUsername,
+			TargetUsername:    r.TargetUsername,
+			MessagePlaintext:  r.MessagePlaintext.String,
+			Reason:            string(r.Reason),
+			Details:           r.Details,
+			Status:            string(r.Status),
+			AssigneeUsername:  r.AssigneeUsername.String,
+			ResolutionNote:    r.ResolutionNote,
+			TargetReportCount: r.TargetReportCount,
+			CreatedAt:         r.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:         r.UpdatedAt.Format(time.RFC3339),
+		}
+		if r.MessageID.Valid {
+			report.MessageId = r.MessageID.UUID.String()
+		}
+		if r.ResolvedAt.Valid {
+			report.ResolvedAt = r.ResolvedAt.Time.Format(time.RFC3339)
+		}
+		resp.Reports = append(resp.Reports, report)
+	}
+	if len(rows) == int(limit) {
+		resp.NextAfterId = rows[len(rows)-1].ID
+	}
+
+	return resp, nil
+}
+
+// TriageReport assigns an open report to the calling admin.
+func (s *ModerationServer) TriageReport(ctx context.Context, req *pb.TriageReportRequest) (*pb.TriageReportResponse, error) {
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "TriageReport: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	adminID, err := requireAdmin(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := q.GetReportForUpdate(ctx, req.GetReportId())
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.NotFound, "report not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "TriageReport: get report: %v", err)
+	}
+	if report.Status != db.ReportStatusOpen {
+		return nil, status.Errorf(codes.FailedPrecondition, "report is %s", report.Status)
+	}
+
+	if err := q.TriageReport(ctx, db.TriageReportParams{
+		ID:         report.ID,
+		AssignedTo: uuid.NullUUID{Valid: true, UUID: adminID},
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "TriageReport: update: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "TriageReport: commit: %v", err)
+	}
+
+	return &pb.TriageReportResponse{}, nil
+}
+
+// ResolveReport closes an open or triaged report, dismissing it or acting on
+// the reported user.
+func (s *ModerationServer) ResolveReport(ctx context.Context, req *pb.ResolveReportRequest) (*pb.ResolveReportResponse, error) {
+	var action db.ModerationActionType
+	switch req.GetAction() {
+	case "none":
+	case "warn", "suspend", "ban":
+		action = db.ModerationActionType(req.GetAction())
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid action %q: want none, warn, suspend or ban", req.GetAction())
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ResolveReport: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	adminID, err := requireAdmin(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := q.GetReportForUpdate(ctx, req.GetReportId())
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.NotFound, "report not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ResolveReport: get report: %v", err)
+	}
+	if report.Status != db.ReportStatusOpen && report.Status != db.ReportStatusTriaged {
+		return nil, status.Errorf(codes.FailedPrecondition, "report is already %s", report.Status)
+	}
+
+	closed := db.ReportStatusDismissed
+	if action != "" {
+		closed = db.ReportStatusResolved
+		if err := s.applyAction(ctx, q, moderationAction{
+			userID:         report.TargetUserID,
+			moderatorID:    adminID,
+			reportID:       report.ID,
+			action:         action,
+			note:           req.GetNote(),
+			suspendSeconds: req.GetSuspendSeconds(),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := q.CloseReport(ctx, db.CloseReportParams{
+		ID:             report.ID,
+		Status:         closed,
+		ResolutionNote: req.GetNote(),
+		ModeratorID:    uuid.NullUUID{Valid: true, UUID: adminID},
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "ResolveReport: close: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "ResolveReport: commit: %v", err)
+	}
+	s.standing.Forget(report.TargetUserID)
+
+	return &pb.ResolveReportResponse{Status: string(closed)}, nil
+}
+
+// ModerateUser warns, suspends or bans a user without a report, or lifts
+// their suspension or ban.
+func (s *ModerationServer) ModerateUser(ctx context.Context, req *pb.ModerateUserRequest) (*pb.ModerateUserResponse, error) {
+	switch req.GetAction() {
+	case "warn", "suspend", "ban", "lift":
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid action %q: want warn, suspend, ban or lift", req.GetAction())
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ModerateUser: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	q := db.New(tx)
+
+	adminID, err := requireAdmin(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := q.GetUserByUsername(ctx, req.GetUsername())
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "user %q not found", req.GetUsername())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ModerateUser: get user: %v", err)
+	}
+	if user.UserID == adminID {
+		return nil, status.Error(codes.InvalidArgument, "cannot moderate yourself")
+	}
+
+	if err := s.applyAction(ctx, q, moderationAction{
+		userID:         user.UserID,
+		moderatorID:    adminID,
+		action:         db.ModerationActionType(req.GetAction()),
+		note:           req.GetNote(),
+		suspendSeconds: req.GetSuspendSeconds(),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "ModerateUser: commit: %v", err)
+	}
+	s.standing.Forget(user.UserID)
+
+	return &pb.ModerateUserResponse{}, nil
+}
+
+// ListUserActions returns a user's moderation record and the restriction in
+// force, if any.
+func (s *ModerationServer) ListUserActions(ctx context.Context, req *pb.ListUserActionsRequest) (*pb.ListUserActionsResponse, error) {
+	q := db.New(s.sqlDB)
+	if _, err := requireAdmin(ctx, q); err != nil {
+		return nil, err
+	}
+
+	user, err := q.GetUserByUsername(ctx, req.GetUsername())
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "user %q not found", req.GetUsername())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ListUserActions: get user: %v", err)
+	}
+
+	rows, err := q.ListModerationActions(ctx, user.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ListUserActions: list: %v", err)
+	}
+
+	resp := &pb.ListUserActionsResponse{Actions: make([]*pb.ModerationAction, 0, len(rows))}
+	for _, r := range rows {
+		action := &pb.ModerationAction{
+			Id:                r.ID,
+			Action:            string(r.Action),
+			Note:              r.Note,
+			ModeratorUsername: r.ModeratorUsername.String,
+			ReportId:          r.ReportID.Int64,
+			CreatedAt:         r.CreatedAt.Format(time.RFC3339),
+		}
+		if r.ExpiresAt.Valid {
+			action.ExpiresAt = r.ExpiresAt.Time.Format(time.RFC3339)
+		}
+		resp.Actions = append(resp.Actions, action)
+	}
+
+	restriction, err := q.GetAccountRestriction(ctx, user.UserID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, status.Errorf(codes.Internal, "ListUserActions: get restriction: %v", err)
+	}
+	if err == nil {
+		resp.Restriction = string(restriction.Kind)
+		if restriction.ExpiresAt.Valid {
+			resp.RestrictedUntil = restriction.ExpiresAt.Time.Format(time.RFC3339)
+		}
+	}
+
+	return resp, nil
+}
+
+// moderationAction is one action a moderator takes against a user.
+type moderationAction struct {
+	userID         uuid.UUID
+	moderatorID    uuid.UUID
+	reportID       int64 // 0 when taken without a report
+	action         db.ModerationActionType
+	note           string
+	suspendSeconds int64
+}
+
+// applyAction records a and updates the user's restriction using q, which
+// should wrap a transaction. Warnings and suspensions notify the user.
+func (s *ModerationServer) applyAction(ctx context.Context, q *db.Queries, a moderationAction) error {
+	current, err := q.GetAccountRestriction(ctx, a.userID)
+	if err != nil && err != sql.ErrNoRows {
+		return status.Errorf(codes.Internal, "get restriction: %v", err)
+	}
+	restricted := err == nil
+
+	var expiresAt sql.NullTime
+	switch a.action {
+	case db.ModerationActionTypeSuspend:
+		if a.suspendSeconds <= 0 {
+			return status.Error(codes.InvalidArgument, "suspend_seconds must be positive for suspend")
+		}
+		if a.suspendSeconds > maxSuspendSeconds {
+			return status.Errorf(codes.InvalidArgument, "suspend_seconds must be at most %d (ten years); use ban instead", maxSuspendSeconds)
+		}
+		if restricted && current.Kind == db.ModerationActionTypeBan {
+			return status.Error(codes.FailedPrecondition, "user is banned; lift the ban first")
+		}
+		expiresAt = sql.NullTime{Valid: true, Time: time.Now().Add(time.Duration(a.suspendSeconds) * time.Second)}
+	case db.ModerationActionTypeLift:
+		if !restricted {
+			return status.Error(codes.FailedPrecondition, "user is not suspended or banned")
+		}
+	}
+
+	created, err := q.CreateModerationAction(ctx, db.CreateModerationActionParams{
+		UserID:      a.userID,
+		ModeratorID: uuid.NullUUID{Valid: true, UUID: a.moderatorID},
+		ReportID:    sql.NullInt64{Valid: a.reportID != 0, Int64: a.reportID},
+		Action:      a.action,
+		Note:        a.note,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "record action: %v", err)
+	}
+
+	var message string
+	switch a.action {
+	case db.ModerationActionTypeWarn:
+		message = "You received a warning from the moderators"
+	case db.ModerationActionTypeSuspend:
+		message = fmt.Sprintf("Your account is suspended until %s", expiresAt.Time.UTC().Format(time.RFC3339))
+		fallthrough
+	case db.ModerationActionTypeBan:
+		if err := q.SetAccountRestriction(ctx, db.SetAccountRestrictionParams{
+			UserID:    a.userID,
+			ActionID:  created.ID,
+			Kind:      a.action,
+			ExpiresAt: expiresAt,
+		}); err != nil {
+			return status.Errorf(codes.Internal, "set restriction: %v", err)
+		}
+	case db.ModerationActionTypeLift:
+		if _, err := q.DeleteAccountRestriction(ctx, a.userID); err != nil {
+			return status.Errorf(codes.Internal, "lift restriction: %v", err)
+		}
+	}
+
+	if message == "" {
+		return nil
+	}
+	if a.note != "" {
+		message += ": " + a.note
+	}
+	if err := s.notif.Send(ctx, q, db.CreateNotificationParams{
+		UserID:  a.userID,
+		Type:    db.NotificationTypeModerationWarning,
+		Message: message,
+	}); err != nil {
+		return status.Errorf(codes.Internal, "create notification: %v", err)
+	}
+	return nil
+}
+
+// requireAdmin returns the caller's ID if they are a site admin, or
+// PermissionDenied.
+func requireAdmin(ctx context.Context, q *db.Queries) (uuid.UUID, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	isAdmin, err := q.IsSiteAdmin(ctx, callerID)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.Internal, "check admin: %v", err)
+	}
+	if !isAdmin {
+		return uuid.Nil, status.Error(codes.PermissionDenied, "admin only")
+	}
+	return callerID, nil
+}
+
+// lookupEnum returns the value in allowed whose string form is s.
+func lookupEnum[T ~string](s string, allowed []T) (T, bool) {
+	for _, v := range allowed {
+		if string(v) == s {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
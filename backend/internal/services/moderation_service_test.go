@@ -0,0 +1,231 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/services"
+	"github.com/zukigit/chat/backend/proto/auth"
+	"github.com/zukigit/chat/backend/proto/chat"
+	pb "github.com/zukigit/chat/backend/proto/moderation"
+	"google.golang.org/grpc/codes"
+)
+
+func TestReport(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	ms := services.NewModerationServer(sqlDB, nil, nil)
+	cs := services.NewChatServer(sqlDB, nil)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+	makeFriends(t, sqlDB, ids["alice"], ids["bob"])
+
+	alice := ctxWithUser("alice", ids["alice"])
+	bob := ctxWithUser("bob", ids["bob"])
+
+	conv, err := cs.CreateConversation(alice, &chat.CreateConversationRequest{MembersUsername: []string{"bob"}})
+	if err != nil {
+		t.Fatalf("setup CreateConversation: %v", err)
+	}
+	messageID := uuid.NewString()
+	if _, err := cs.SendMessage(bob, &chat.SendMessageRequest{
+		ConversationId: conv.ConversationId,
+		MessageId:      messageID,
+		Content:        "ciphertext",
+	}); err != nil {
+		t.Fatalf("setup SendMessage: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		caller  string
+		req     *pb.ReportRequest
+		wantErr codes.Code
+	}{
+		{"invalid reason", "alice", &pb.ReportRequest{TargetUsername: "bob", Reason: "rude"}, codes.InvalidArgument},
+		{"no target", "alice", &pb.ReportRequest{Reason: "spam"}, codes.InvalidArgument},
+		{"plaintext without message", "alice", &pb.ReportRequest{TargetUsername: "bob", Reason: "spam", MessagePlaintext: "hi"}, codes.InvalidArgument},
+		{"unknown user", "alice", &pb.ReportRequest{TargetUsername: "nobody", Reason: "spam"}, codes.NotFound},
+		{"self", "alice", &pb.ReportRequest{TargetUsername: "alice", Reason: "spam"}, codes.InvalidArgument},
+		{"message from someone else", "alice", &pb.ReportRequest{TargetUsername: "carol", MessageId: messageID, Reason: "spam"}, codes.InvalidArgument},
+		{"message outside caller's conversations", "carol", &pb.ReportRequest{MessageId: messageID, Reason: "spam"}, codes.NotFound},
+		{"user", "alice", &pb.ReportRequest{TargetUsername: "bob", Reason: "harassment", Details: "keeps messaging me"}, codes.OK},
+		{"duplicate", "alice", &pb.ReportRequest{TargetUsername: "bob", Reason: "spam"}, codes.AlreadyExists},
+		{"message with plaintext", "alice", &pb.ReportRequest{MessageId: messageID, Reason: "hate", MessagePlaintext: "the decrypted text"}, codes.OK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ms.Report(ctxWithUser(tc.caller, ids[tc.caller]), tc.req)
+			if got := grpcCode(err); got != tc.wantErr {
+				t.Errorf("got %v, want %v (err: %v)", got, tc.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("non-admin cannot list", func(t *testing.T) {
+		_, err := ms.ListReports(alice, &pb.ListReportsRequest{})
+		if got := grpcCode(err); got != codes.PermissionDenied {
+			t.Errorf("got %v, want PermissionDenied", got)
+		}
+	})
+
+	t.Run("admin lists the queue", func(t *testing.T) {
+		if _, err := sqlDB.Exec(`INSERT INTO site_admins (user_id) VALUES ($1)`, ids["carol"]); err != nil {
+			t.Fatalf("grant admin: %v", err)
+		}
+		resp, err := ms.ListReports(ctxWithUser("carol", ids["carol"]), &pb.ListReportsRequest{Status: "open"})
+		if err != nil {
+			t.Fatalf("ListReports: %v", err)
+		}
+		if len(resp.Reports) != 2 {
+			t.Fatalf("got %d reports, want 2", len(resp.Reports))
+		}
+		r := resp.Reports[1]
+		if r.ReporterUsername != "alice" || r.TargetUsername != "bob" || r.MessageId != messageID ||
+			r.MessagePlaintext != "the decrypted text" || r.TargetReportCount != 2 {
+			t.Errorf("unexpected report: %+v", r)
+		}
+	})
+}
+
+func TestModerationActions(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	standing := services.NewAccountStanding(sqlDB, time.Minute)
+	ms := services.NewModerationServer(sqlDB, services.NewNotificationServer(sqlDB, nil), standing)
+	authServer := services.NewAuthServer(sqlDB, nil)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "mod")
+	ctx := context.Background()
+
+	if _, err := sqlDB.Exec(`INSERT INTO site_admins (user_id) VALUES ($1)`, ids["mod"]); err != nil {
+		t.Fatalf("grant admin: %v", err)
+	}
+	mod := ctxWithUser("mod", ids["mod"])
+
+	report, err := ms.Report(ctxWithUser("alice", ids["alice"]), &pb.ReportRequest{TargetUsername: "bob", Reason: "spam"})
+	if err != nil {
+		t.Fatalf("setup Report: %v", err)
+	}
+
+	login := func() error {
+		_, err := authServer.Login(ctx, &auth.LoginRequest{UserName: "bob", Passwd: "password"})
+		return err
+	}
+
+	t.Run("triage", func(t *testing.T) {
+		if _, err := ms.TriageReport(mod, &pb.TriageReportRequest{ReportId: report.ReportId}); err != nil {
+			t.Fatalf("TriageReport: %v", err)
+		}
+		_, err := ms.TriageReport(mod, &pb.TriageReportRequest{ReportId: report.ReportId})
+		if got := grpcCode(err); got != codes.FailedPrecondition {
+			t.Errorf("second triage: got %v, want FailedPrecondition", got)
+		}
+	})
+
+	t.Run("suspend needs a duration", func(t *testing.T) {
+		_, err := ms.ResolveReport(mod, &pb.ResolveReportRequest{ReportId: report.ReportId, Action: "suspend"})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("got %v, want InvalidArgument", got)
+		}
+	})
+
+	t.Run("suspend too long", func(t *testing.T) {
+		_, err := ms.ResolveReport(mod, &pb.ResolveReportRequest{ReportId: report.ReportId, Action: "suspend", SuspendSeconds: 1 << 62})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("got %v, want InvalidArgument", got)
+		}
+	})
+
+	t.Run("suspend blocks login and API calls", func(t *testing.T) {
+		// Cache bob's good standing first; the action must clear it.
+		if err := standing.Check(ctx, ids["bob"].String()); err != nil {
+			t.Fatalf("Check before suspension: %v", err)
+		}
+
+		resp, err := ms.ResolveReport(mod, &pb.ResolveReportRequest{
+			ReportId:       report.ReportId,
+			Action:         "suspend",
+			Note:           "spam",
+			SuspendSeconds: 3600,
+		})
+		if err != nil {
+			t.Fatalf("ResolveReport: %v", err)
+		}
+		if resp.Status != "resolved" {
+			t.Errorf("status: got %q, want resolved", resp.Status)
+		}
+
+		if got := grpcCode(login()); got != codes.PermissionDenied {
+			t.Errorf("login: got %v, want PermissionDenied", got)
+		}
+		if got := grpcCode(standing.Check(ctx, ids["bob"].String())); got != codes.PermissionDenied {
+			t.Errorf("check: got %v, want PermissionDenied", got)
+		}
+
+		notis, err := db.New(sqlDB).GetNotificationsForUser(ctx, ids["bob"])
+		if err != nil {
+			t.Fatalf("GetNotificationsForUser: %v", err)
+		}
+		if len(notis) != 1 || notis[0].Type != db.NotificationTypeModerationWarning {
+			t.Errorf("notifications: got %+v, want one moderation_warning", notis)
+		}
+	})
+
+	t.Run("resolved report cannot be resolved again", func(t *testing.T) {
+		_, err := ms.ResolveReport(mod, &pb.ResolveReportRequest{ReportId: report.ReportId, Action: "none"})
+		if got := grpcCode(err); got != codes.FailedPrecondition {
+			t.Errorf("got %v, want FailedPrecondition", got)
+		}
+	})
+
+	t.Run("ban then suspend is refused", func(t *testing.T) {
+		if _, err := ms.ModerateUser(mod, &pb.ModerateUserRequest{Username: "bob", Action: "ban"}); err != nil {
+			t.Fatalf("ban: %v", err)
+		}
+		_, err := ms.ModerateUser(mod, &pb.ModerateUserRequest{Username: "bob", Action: "suspend", SuspendSeconds: 60})
+		if got := grpcCode(err); got != codes.FailedPrecondition {
+			t.Errorf("got %v, want FailedPrecondition", got)
+		}
+	})
+
+	t.Run("lift restores access", func(t *testing.T) {
+		if _, err := ms.ModerateUser(mod, &pb.ModerateUserRequest{Username: "bob", Action: "lift"}); err != nil {
+			t.Fatalf("lift: %v", err)
+		}
+		if err := login(); err != nil {
+			t.Errorf("login: %v", err)
+		}
+		if err := standing.Check(ctx, ids["bob"].String()); err != nil {
+			t.Errorf("check: %v", err)
+		}
+		_, err := ms.ModerateUser(mod, &pb.ModerateUserRequest{Username: "bob", Action: "lift"})
+		if got := grpcCode(err); got != codes.FailedPrecondition {
+			t.Errorf("second lift: got %v, want FailedPrecondition", got)
+		}
+	})
+
+	t.Run("record", func(t *testing.T) {
+		resp, err := ms.ListUserActions(mod, &pb.ListUserActionsRequest{Username: "bob"})
+		if err != nil {
+			t.Fatalf("ListUserActions: %v", err)
+		}
+		var got []string
+		for _, a := range resp.Actions {
+			got = append(got, a.Action)
+		}
+		want := []string{"lift", "ban", "suspend"}
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+			t.Errorf("actions: got %v, want %v", got, want)
+		}
+		if resp.Restriction != "" {
+			t.Errorf("restriction: got %q, want none", resp.Restriction)
+		}
+	})
+
+	t.Run("cannot moderate yourself", func(t *testing.T) {
+		_, err := ms.ModerateUser(mod, &pb.ModerateUserRequest{Username: "mod", Action: "warn"})
+		if got := grpcCode(err); got != codes.InvalidArgument {
+			t.Errorf("got %v, want InvalidArgument", got)
+		}
+	})
+}
@@ -49,7 +49,7 @@ func (s *NotificationServer) Send(ctx context.Context, q *db.Queries, notiParams
 		return err
 	}
 
-	notificationBytes, err := json.Marshal(notification)
+	notificationBytes, err := json.Marshal(notificationEvent(notification))
 	if err != nil {
 		return err
 	}
@@ -57,6 +57,58 @@ func (s *NotificationServer) Send(ctx context.Context, q *db.Queries, notiParams
 	return s.publishIfOnline(notiParams.UserID, lib.NotiSubjectPrefix, notificationBytes)
 }
 
+// Retract deletes the notifications matching params using q (which may wrap
+// an active transaction) and pushes a live NotificationRetractedEvent to the
+// recipient. It is nil-safe: if s is nil the call is a no-op.
+func (s *NotificationServer) Retract(ctx context.Context, q *db.Queries, params db.DeleteNotificationsFromParams) error {
+	if s == nil {
+		return nil
+	}
+
+	ids, err := q.DeleteNotificationsFrom(ctx, params)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	eventBytes, err := json.Marshal(lib.NotificationRetractedEvent{
+		Type:          lib.NotificationRetracted,
+		IDs:           ids,
+		RetractedType: string(params.Type),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.publishIfOnline(params.UserID, lib.NotiSubjectPrefix, eventBytes)
+}
+
+// notificationEvent converts a stored notification to its wire form.
+func notificationEvent(n db.Notification) lib.NotificationEvent {
+	event := lib.NotificationEvent{
+		ID:        n.ID,
+		UserID:    n.UserID.String(),
+		Type:      string(n.Type),
+		Message:   n.Message,
+		IsRead:    n.IsRead,
+		CreatedAt: n.CreatedAt,
+	}
+	if n.SenderID.Valid {
+		senderID := n.SenderID.UUID.String()
+		event.SenderID = &senderID
+	}
+	if n.ReferenceConversationID.Valid {
+		event.ReferenceConversationID = &n.ReferenceConversationID.Int64
+	}
+	if n.ReferenceUserID.Valid {
+		userID := n.ReferenceUserID.UUID.String()
+		event.ReferenceUserID = &userID
+	}
+	return event
+}
+
 // MarkNotificationRead implements notification.NotificationServer.
 // It marks a single notification as read (the only supported status).
 func (s *NotificationServer) MarkNotificationRead(ctx context.Context, req *pb.MarkNotificationReadRequest) (*pb.MarkNotificationReadResponse, error) {
@@ -76,10 +128,11 @@ func (s *NotificationServer) MarkNotificationRead(ctx context.Context, req *pb.M
 }
 
 // publishIfOnline publishes payload to the per-user NATS subject.
+// It is nil-safe: if s is nil the call is a no-op.
 // JetStream retains the message so any active durable consumer (device) receives it.
 // All errors are logged and swallowed — delivery via JetStream handles retries.
 func (s *NotificationServer) publishIfOnline(userID uuid.UUID, subjectPrefix string, payload []byte) error {
-	if s.publisher == nil {
+	if s == nil || s.publisher == nil {
 		return nil
 	}
 	subject := subjectPrefix + userID.String()
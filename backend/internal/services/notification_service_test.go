@@ -2,12 +2,15 @@ package services_test
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/zukigit/chat/backend/internal/db"
 	"github.com/zukigit/chat/backend/internal/services"
+	"github.com/zukigit/chat/backend/proto/chat"
+	"github.com/zukigit/chat/backend/proto/friendship"
 	pb "github.com/zukigit/chat/backend/proto/notification"
 	"google.golang.org/grpc/codes"
 )
@@ -52,3 +55,101 @@ func TestMarkNotificationRead(t *testing.T) {
 		})
 	}
 }
+
+func TestSocialNotifications(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	notif := services.NewNotificationServer(sqlDB, nil)
+	fs := services.NewFriendshipServer(sqlDB, notif)
+	chatServer := services.NewChatServer(sqlDB, notif)
+	ids := createTestUsers(t, sqlDB, "alice", "bob", "carol")
+	q := db.New(sqlDB)
+
+	alice := ctxWithUser("alice", ids["alice"])
+	makeFriends(t, sqlDB, ids["alice"], ids["carol"])
+
+	// latest returns the user's newest notification of type typ.
+	latest := func(t *testing.T, user string, typ db.NotificationType) db.Notification {
+		t.Helper()
+		notis, err := q.GetNotificationsForUser(context.Background(), ids[user])
+		if err != nil {
+			t.Fatalf("GetNotificationsForUser %s: %v", user, err)
+		}
+		for _, n := range notis {
+			if n.Type == typ {
+				return n
+			}
+		}
+		t.Fatalf("%s: no %s notification", user, typ)
+		return db.Notification{}
+	}
+	wantConversation := func(t *testing.T, n db.Notification, convID int64) {
+		t.Helper()
+		if !n.ReferenceConversationID.Valid || n.ReferenceConversationID.Int64 != convID {
+			t.Errorf("reference_conversation_id: got %v, want %d", n.ReferenceConversationID, convID)
+		}
+		if n.ReferenceUserID.Valid {
+			t.Errorf("reference_user_id: got %v, want NULL", n.ReferenceUserID)
+		}
+	}
+
+	t.Run("friend_accepted references the accepter", func(t *testing.T) {
+		if _, err := fs.SendFriendRequest(alice, &friendship.FriendRequest{TargetUsername: "bob"}); err != nil {
+			t.Fatalf("SendFriendRequest: %v", err)
+		}
+		bob := ctxWithUser("bob", ids["bob"])
+		if _, err := fs.AcceptFriendRequest(bob, &friendship.FriendRequest{TargetUsername: "alice"}); err != nil {
+			t.Fatalf("AcceptFriendRequest: %v", err)
+		}
+
+		n := latest(t, "alice", db.NotificationTypeFriendAccepted)
+		if !n.ReferenceUserID.Valid || n.ReferenceUserID.UUID != ids["bob"] {
+			t.Errorf("reference_user_id: got %v, want bob", n.ReferenceUserID)
+		}
+		if n.ReferenceConversationID.Valid {
+			t.Errorf("reference_conversation_id: got %v, want NULL", n.ReferenceConversationID)
+		}
+	})
+
+	var convID int64
+	t.Run("group_invite on group creation", func(t *testing.T) {
+		resp, err := chatServer.CreateConversation(alice, &chat.CreateConversationRequest{
+			IsGroup: true, Name: "crew", MembersUsername: []string{"bob"},
+		})
+		if err != nil {
+			t.Fatalf("CreateConversation: %v", err)
+		}
+		convID = resp.ConversationId
+		wantConversation(t, latest(t, "bob", db.NotificationTypeGroupInvite), convID)
+	})
+
+	t.Run("added_to_group on AddMembers", func(t *testing.T) {
+		if _, err := chatServer.AddMembers(alice, &chat.AddMembersRequest{ConversationId: convID, MembersUsername: []string{"carol"}}); err != nil {
+			t.Fatalf("AddMembers: %v", err)
+		}
+		wantConversation(t, latest(t, "carol", db.NotificationTypeAddedToGroup), convID)
+	})
+
+	t.Run("role_changed on SetMemberRole", func(t *testing.T) {
+		if _, err := chatServer.SetMemberRole(alice, &chat.SetMemberRoleRequest{ConversationId: convID, Username: "carol", Role: "admin"}); err != nil {
+			t.Fatalf("SetMemberRole: %v", err)
+		}
+		n := latest(t, "carol", db.NotificationTypeRoleChanged)
+		wantConversation(t, n, convID)
+		if want := "alice made you an admin in crew"; n.Message != want {
+			t.Errorf("message: got %q, want %q", n.Message, want)
+		}
+	})
+
+	t.Run("at most one reference", func(t *testing.T) {
+		_, err := q.CreateNotification(context.Background(), db.CreateNotificationParams{
+			UserID:                  ids["bob"],
+			Type:                    db.NotificationTypeRoleChanged,
+			Message:                 "both",
+			ReferenceConversationID: sql.NullInt64{Valid: true, Int64: convID},
+			ReferenceUserID:         uuid.NullUUID{Valid: true, UUID: ids["alice"]},
+		})
+		if err == nil {
+			t.Error("CreateNotification with two references: got nil error")
+		}
+	})
+}
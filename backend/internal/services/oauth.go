@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
+	"github.com/zukigit/chat/backend/internal/oauth"
+	"github.com/zukigit/chat/backend/proto/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// oauthStateTTL is how long a user has to finish signing in at the provider.
+const oauthStateTTL = 10 * time.Minute
+
+// oauthUsernameAttempts is how many numbered variants of a provider's
+// suggested username are tried before falling back to a random suffix.
+const oauthUsernameAttempts = 20
+
+// SetOAuthProviders enables sign-in with providers, keyed by the name used
+// in the /oauth/{provider} routes. Unknown names fail with NotFound.
+func (s *AuthServer) SetOAuthProviders(providers map[string]oauth.Provider) {
+	s.oauth = providers
+}
+
+func (s *AuthServer) oauthProvider(name string) (oauth.Provider, error) {
+	p, ok := s.oauth[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown sign-in provider %q", name)
+	}
+	return p, nil
+}
+
+// oauthSignupType is the signup_type of accounts created through provider.
+func oauthSignupType(provider string) db.SignupType {
+	switch provider {
+	case "github":
+		return db.SignupTypeGithub
+	case "google":
+		return db.SignupTypeGoogle
+	default:
+		return db.SignupTypeOidc
+	}
+}
+
+// GetOAuthURL starts a sign-in with a provider and returns the page to send
+// the user to. The state in it is good for one callback.
+func (s *AuthServer) GetOAuthURL(ctx context.Context, req *auth.GetOAuthURLRequest) (*auth.GetOAuthURLResponse, error) {
+	provider, err := s.oauthProvider(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := lib.RandomToken(32)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get oauth url: generate state: %v", err)
+	}
+	verifier, err := lib.RandomToken(32)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get oauth url: generate verifier: %v", err)
+	}
+	nonce, err := lib.RandomToken(16)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get oauth url: generate nonce: %v", err)
+	}
+
+	authURL, err := provider.AuthURL(ctx, oauth.AuthRequest{
+		State:         state,
+		CodeChallenge: oauth.CodeChallenge(verifier),
+		Nonce:         nonce,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "get oauth url: %v", err)
+	}
+
+	queries := db.New(s.sqlDB)
+	if err := queries.DeleteExpiredOAuthStates(ctx); err != nil {
+		return nil, status.Errorf(codes.Internal, "get oauth url: sweep states: %v", err)
+	}
+	if err := queries.CreateOAuthState(ctx, db.CreateOAuthStateParams{
+		StateHash:    lib.HashToken(state),
+		Provider:     req.Provider,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(oauthStateTTL),
+	}); err != nil {
+		return nil, status.Errorf(codes.Internal, "get oauth url: store state: %v", err)
+	}
+
+	return &auth.GetOAuthURLResponse{Url: authURL}, nil
+}
+
+// OAuthCallback finishes a sign-in started by GetOAuthURL and returns a
+// short-lived token for ExchangeToken. A provider account seen for the
+// first time gets a new user.
+func (s *AuthServer) OAuthCallback(ctx context.Context, req *auth.OAuthCallbackRequest) (*auth.OAuthCallbackResponse, error) {
+	if req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing authorization code")
+	}
+	if req.State == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing state")
+	}
+	provider, err := s.oauthProvider(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := db.New(s.sqlDB)
+
+	// The state is deleted as it is read, so a replayed callback fails here.
+	started, err := queries.ConsumeOAuthState(ctx, lib.HashToken(req.State))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, status.Errorf(codes.Internal, "oauth callback: consume state: %v", err)
+	}
+	if err == sql.ErrNoRows || started.Provider != req.Provider || !time.Now().Before(started.ExpiresAt) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired sign-in, please start again")
+	}
+
+	identity, err := provider.Exchange(ctx, req.Code, started.CodeVerifier, started.Nonce)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "oauth callback: sign in with %s: %v", req.Provider, err)
+	}
+
+	userID, username, err := s.oauthUser(ctx, req.Provider, identity)
+	if err != nil {
+		if _, isStatus := status.FromError(err); isStatus {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "oauth callback: %v", err)
+	}
+
+	standingErr, _, err := accountStandingError(ctx, queries, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "oauth callback: check account standing: %v", err)
+	}
+	if standingErr != nil {
+		return nil, standingErr
+	}
+
+	shortLivedToken, err := lib.GenerateScopedToken(userID.String(), username, lib.ScopeOAuthExchange, 60*time.Second)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "oauth callback: generate token: %v", err)
+	}
+
+	return &auth.OAuthCallbackResponse{
+		ShortLivedToken: shortLivedToken,
+		Username:        username,
+	}, nil
+}
+
+// oauthUser returns the user linked to identity at provider, creating one
+// if there is none.
+func (s *AuthServer) oauthUser(ctx context.Context, provider string, identity *oauth.Identity) (uuid.UUID, string, error) {
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, "", fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries := db.New(tx)
+
+	linked, err := queries.GetUserByIdentity(ctx, db.GetUserByIdentityParams{Provider: provider, Subject: identity.Subject})
+	if err == nil {
+		return linked.UserID, linked.UserName, nil
+	}
+	if err != sql.ErrNoRows {
+		return uuid.Nil, "", fmt.Errorf("get user: %w", err)
+	}
+
+	// GitHub accounts from before their ID was stored are found by login,
+	// once, and linked. That is only safe while the account still has the
+	// name it was created with: after a rename, whoever holds that login on
+	// GitHub now need not be the account's owner.
+	if provider == "github" {
+		legacy, err := queries.GetUserByUsername(ctx, identity.Username)
+		if err != nil && err != sql.ErrNoRows {
+			return uuid.Nil, "", fmt.Errorf("get user: %w", err)
+		}
+		if err == nil && legacy.SignupType == db.SignupTypeGithub {
+			hasIdentity, err := queries.UserHasIdentity(ctx, db.UserHasIdentityParams{UserID: legacy.UserID, Provider: provider})
+			if err != nil {
+				return uuid.Nil, "", fmt.Errorf("check identity: %w", err)
+			}
+			_, err = queries.GetLastUsernameChange(ctx, legacy.UserID)
+			if err != nil && err != sql.ErrNoRows {
+				return uuid.Nil, "", fmt.Errorf("check username history: %w", err)
+			}
+			renamed := err == nil
+			if !hasIdentity && !renamed {
+				if err := queries.CreateUserIdentity(ctx, db.CreateUserIdentityParams{Provider: provider, Subject: identity.Subject, UserID: legacy.UserID}); err != nil {
+					return uuid.Nil, "", fmt.Errorf("link account: %w", err)
+				}
+				if err := tx.Commit(); err != nil {
+					return uuid.Nil, "", fmt.Errorf("commit: %w", err)
+				}
+				return legacy.UserID, legacy.UserName, nil
+			}
+		}
+	}
+
+	username, err := freeOAuthUsername(ctx, queries, identity.Username)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	created, err := queries.CreateUser(ctx, db.CreateUserParams{
+		UserName:     username,
+		HashedPasswd: sql.NullString{Valid: false},
+		SignupType:   oauthSignupType(provider),
+	})
+	if err != nil {
+		if lib.IsPgUniqueViolation(err) {
+			return uuid.Nil, "", status.Error(codes.Aborted, "sign-in raced another, please try again")
+		}
+		return uuid.Nil, "", fmt.Errorf("create user: %w", err)
+	}
+	if err := queries.CreateUserIdentity(ctx, db.CreateUserIdentityParams{Provider: provider, Subject: identity.Subject, UserID: created.UserID}); err != nil {
+		if lib.IsPgUniqueViolation(err) {
+			return uuid.Nil, "", status.Error(codes.Aborted, "sign-in raced another, please try again")
+		}
+		return uuid.Nil, "", fmt.Errorf("link account: %w", err)
+	}
+
+	displayName, avatarURL := oauthProfile(identity)
+	if _, err := queries.UpdateUserProfile(ctx, db.UpdateUserProfileParams{
+		UserID:      created.UserID,
+		DisplayName: sql.NullString{String: displayName, Valid: displayName != ""},
+		AvatarUrl:   sql.NullString{String: avatarURL, Valid: avatarURL != ""},
+	}); err != nil {
+		return uuid.Nil, "", fmt.Errorf("update profile: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, "", fmt.Errorf("commit: %w", err)
+	}
+	return created.UserID, created.UserName, nil
+}
+
+// freeOAuthUsername turns a provider's suggested username into one that is
+// valid here and neither taken nor reserved, numbering it alice-2, alice-3
+// and so on as needed.
+func freeOAuthUsername(ctx context.Context, queries *db.Queries, suggested string) (string, error) {
+	base := oauthUsernameBase(suggested)
+	for i := 1; i <= oauthUsernameAttempts+1; i++ {
+		name := base
+		switch {
+		case i > oauthUsernameAttempts:
+			suffix, err := lib.RandomToken(6)
+			if err != nil {
+				return "", fmt.Errorf("generate username: %w", err)
+			}
+			name = base + "-" + suffix
+		case i > 1:
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		_, err := queries.GetUserByUsername(ctx, name)
+		if err == nil {
+			continue
+		}
+		if err != sql.ErrNoRows {
+			return "", fmt.Errorf("check username: %w", err)
+		}
+		if err := checkUsernameReservation(ctx, queries, name, uuid.Nil); err != nil {
+			if status.Code(err) == codes.AlreadyExists {
+				continue
+			}
+			return "", err
+		}
+		return name, nil
+	}
+	return "", status.Error(codes.Aborted, "no free username, please try again")
+}
+
+// oauthUsernameBase keeps the characters of suggested that usernamePattern
+// allows, leaving room for a numbered suffix. Too little left gives "user".
+func oauthUsernameBase(suggested string) string {
+	var b strings.Builder
+	for _, r := range suggested {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '_' || r == '.' || r == '-':
+			if b.Len() == 0 {
+				continue // must start with a letter or digit
+			}
+		default:
+			continue
+		}
+		b.WriteRune(r)
+	}
+	base := b.String()
+	if len(base) > 41 {
+		base = base[:41]
+	}
+	if len(base) < 3 {
+		return "user"
+	}
+	return base
+}
+
+// oauthProfile returns the display name and avatar URL a new account takes
+// from identity, dropping what UpdateProfile would reject.
+func oauthProfile(identity *oauth.Identity) (displayName, avatarURL string) {
+	displayName = strings.TrimSpace(identity.Name)
+	if utf8.RuneCountInString(displayName) > maxDisplayNameLen {
+		displayName = string([]rune(displayName)[:maxDisplayNameLen])
+	}
+	if u, err := url.Parse(identity.AvatarURL); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && len(identity.AvatarURL) <= maxAvatarURLLen {
+		avatarURL = identity.AvatarURL
+	}
+	return displayName, avatarURL
+}
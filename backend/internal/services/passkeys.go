@@ -0,0 +1,451 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
+	"github.com/zukigit/chat/backend/internal/webauthn"
+	"github.com/zukigit/chat/backend/proto/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPasskeyNameLength is the longest label a passkey may be given.
+const maxPasskeyNameLength = 100
+
+// Ceremony purposes, as stored in webauthn_challenges.
+const (
+	passkeyPurposeRegister     = "register"
+	passkeyPurposeLogin        = "login"
+	passkeyPurposeSecondFactor = "second_factor"
+)
+
+// SetWebAuthn enables passkeys for the relying party rp. Without it the
+// passkey RPCs fail with FailedPrecondition.
+func (s *AuthServer) SetWebAuthn(rp *webauthn.RelyingParty) {
+	s.webauthn = rp
+}
+
+// relyingParty returns the configured relying party or a FailedPrecondition
+// status.
+func (s *AuthServer) relyingParty() (*webauthn.RelyingParty, error) {
+	if s.webauthn == nil {
+		return nil, status.Error(codes.FailedPrecondition, "passkeys are not enabled")
+	}
+	return s.webauthn, nil
+}
+
+// webauthnChallengeHash is how a ceremony's challenge is stored.
+func webauthnChallengeHash(challenge []byte) string {
+	return lib.HashToken(base64.RawURLEncoding.EncodeToString(challenge))
+}
+
+// newWebAuthnChallenge starts a ceremony for purpose, bound to userID unless
+// it is a passwordless sign-in, and returns its challenge.
+func newWebAuthnChallenge(ctx context.Context, queries *db.Queries, userID uuid.NullUUID, purpose string) ([]byte, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	if err := queries.DeleteExpiredWebAuthnChallenges(ctx); err != nil {
+		return nil, err
+	}
+	if err := queries.CreateWebAuthnChallenge(ctx, db.CreateWebAuthnChallengeParams{
+		ChallengeHash: webauthnChallengeHash(challenge),
+		UserID:        userID,
+		Purpose:       purpose,
+		ExpiresAt:     time.Now().Add(webauthn.CeremonyTimeout),
+	}); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// spendWebAuthnChallenge marks the ceremony with challenge used and returns
+// the user it was started for. ok is false if there is no such unused,
+// unexpired ceremony for purpose.
+func spendWebAuthnChallenge(ctx context.Context, queries *db.Queries, challenge []byte, purpose string) (userID uuid.NullUUID, ok bool, err error) {
+	row, err := queries.GetWebAuthnChallengeForUpdate(ctx, webauthnChallengeHash(challenge))
+	if err == sql.ErrNoRows {
+		return uuid.NullUUID{}, false, nil
+	}
+	if err != nil {
+		return uuid.NullUUID{}, false, err
+	}
+	if row.Purpose != purpose || row.UsedAt.Valid || !time.Now().Before(row.ExpiresAt) {
+		return uuid.NullUUID{}, false, nil
+	}
+	if err := queries.MarkWebAuthnChallengeUsed(ctx, row.ID); err != nil {
+		return uuid.NullUUID{}, false, err
+	}
+	return row.UserID, true, nil
+}
+
+// passkeyDescriptors lists passkeys for the browser to exclude or allow.
+func passkeyDescriptors(rows []db.ListPasskeysRow) []webauthn.CredentialDescriptor {
+	creds := make([]webauthn.CredentialDescriptor, 0, len(rows))
+	for _, r := range rows {
+		var transports []string
+		if r.Transports != "" {
+			transports = strings.Split(r.Transports, ",")
+		}
+		creds = append(creds, webauthn.CredentialDescriptor{ID: r.CredentialID, Transports: transports})
+	}
+	return creds
+}
+
+func passkeyFromRow(r db.ListPasskeysRow) *auth.Passkey {
+	p := &auth.Passkey{
+		Id:             r.ID,
+		Name:           r.Name,
+		CreatedAt:      r.CreatedAt.UTC().Format(time.RFC3339),
+		BackupEligible: r.BackupEligible,
+	}
+	if r.LastUsedAt.Valid {
+		p.LastUsedAt = r.LastUsedAt.Time.UTC().Format(time.RFC3339)
+	}
+	return p
+}
+
+// verifyPasskey checks assertion a against the stored passkey it names and
+// moves that passkey's signature counter on. The ceremony's challenge must
+// already be spent. Failures to verify are Unauthenticated statuses.
+func verifyPasskey(ctx context.Context, queries *db.Queries, rp *webauthn.RelyingParty, a *webauthn.Assertion, requireUV bool) (db.GetPasskeyByCredentialIDForUpdateRow, error) {
+	cred, err := queries.GetPasskeyByCredentialIDForUpdate(ctx, a.CredentialID)
+	if err == sql.ErrNoRows {
+		return cred, status.Error(codes.Unauthenticated, "unknown passkey")
+	}
+	if err != nil {
+		return cred, err
+	}
+	// A discoverable passkey also names its user; it must be the owner.
+	if a.UserHandle != nil && !bytes.Equal(a.UserHandle, cred.UserID[:]) {
+		return cred, status.Error(codes.Unauthenticated, "invalid passkey")
+	}
+
+	signCount, err := rp.VerifyAssertion(a, a.Challenge(), cred.PublicKey, uint32(cred.SignCount), requireUV)
+	if err != nil {
+		return cred, status.Error(codes.Unauthenticated, "invalid passkey")
+	}
+	if err := queries.RecordPasskeyUse(ctx, db.RecordPasskeyUseParams{SignCount: int64(signCount), ID: cred.ID}); err != nil {
+		return cred, err
+	}
+	return cred, nil
+}
+
+// checkSecondFactorPasskey verifies a passkey assertion answering a
+// BeginSecondFactorPasskey ceremony for userID. Possession of the passkey is
+// the second factor, so user verification is not required.
+func (s *AuthServer) checkSecondFactorPasskey(ctx context.Context, queries *db.Queries, userID uuid.UUID, a *webauthn.Assertion) error {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return err
+	}
+	owner, ok, err := spendWebAuthnChallenge(ctx, queries, a.Challenge(), passkeyPurposeSecondFactor)
+	if err != nil {
+		return err
+	}
+	if !ok || owner.UUID != userID {
+		return status.Error(codes.Unauthenticated, "invalid or expired passkey challenge")
+	}
+	cred, err := verifyPasskey(ctx, queries, rp, a, false)
+	if err != nil {
+		return err
+	}
+	if cred.UserID != userID {
+		return status.Error(codes.Unauthenticated, "invalid passkey")
+	}
+	return nil
+}
+
+// BeginPasskeyRegistration starts adding a passkey to the caller's account
+// and returns the options for navigator.credentials.create. Passkeys the
+// caller already has are excluded so one authenticator is not added twice.
+func (s *AuthServer) BeginPasskeyRegistration(ctx context.Context, req *auth.BeginPasskeyRegistrationRequest) (*auth.BeginPasskeyRegistrationResponse, error) {
+	userID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+
+	queries := db.New(s.sqlDB)
+	user, err := queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "begin passkey registration: get user: %v", err)
+	}
+	existing, err := queries.ListPasskeys(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "begin passkey registration: list passkeys: %v", err)
+	}
+	challenge, err := newWebAuthnChallenge(ctx, queries, uuid.NullUUID{UUID: userID, Valid: true}, passkeyPurposeRegister)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "begin passkey registration: create challenge: %v", err)
+	}
+
+	displayName := user.DisplayName.String
+	if displayName == "" {
+		displayName = user.UserName
+	}
+	options, err := rp.CreationOptions(webauthn.User{
+		ID:          userID[:],
+		Name:        user.UserName,
+		DisplayName: displayName,
+	}, challenge, passkeyDescriptors(existing))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "begin passkey registration: build options: %v", err)
+	}
+	return &auth.BeginPasskeyRegistrationResponse{OptionsJson: string(options)}, nil
+}
+
+// FinishPasskeyRegistration verifies the passkey the browser created for
+// BeginPasskeyRegistration and stores it.
+func (s *AuthServer) FinishPasskeyRegistration(ctx context.Context, req *auth.FinishPasskeyRegistrationRequest) (*auth.FinishPasskeyRegistrationResponse, error) {
+	userID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+	if req.CredentialJson == "" {
+		return nil, status.Error(codes.InvalidArgument, "credential_json is required")
+	}
+	name := strings.TrimSpace(req.Name)
+	if utf8.RuneCountInString(name) > maxPasskeyNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "name must be at most %d characters", maxPasskeyNameLength)
+	}
+	reg, err := webauthn.ParseRegistration([]byte(req.CredentialJson))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid credential: %v", err)
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "finish passkey registration: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	queries := db.New(tx)
+
+	owner, ok, err := spendWebAuthnChallenge(ctx, queries, reg.Challenge(), passkeyPurposeRegister)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "finish passkey registration: get challenge: %v", err)
+	}
+	if !ok || owner.UUID != userID {
+		return nil, status.Error(codes.InvalidArgument, "invalid or expired passkey challenge")
+	}
+	cred, err := rp.VerifyRegistration(reg, reg.Challenge())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid credential: %v", err)
+	}
+
+	created, err := queries.CreatePasskey(ctx, db.CreatePasskeyParams{
+		UserID:         userID,
+		CredentialID:   cred.ID,
+		PublicKey:      cred.PublicKey,
+		SignCount:      int64(cred.SignCount),
+		Transports:     strings.Join(cred.Transports, ","),
+		Name:           name,
+		BackupEligible: cred.BackupEligible,
+	})
+	if lib.IsPgUniqueViolation(err) {
+		return nil, status.Error(codes.AlreadyExists, "passkey is already registered")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "finish passkey registration: create passkey: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "finish passkey registration: commit: %v", err)
+	}
+
+	return &auth.FinishPasskeyRegistrationResponse{Passkey: passkeyFromRow(db.ListPasskeysRow{
+		ID:             created.ID,
+		CredentialID:   cred.ID,
+		Name:           name,
+		BackupEligible: cred.BackupEligible,
+		CreatedAt:      created.CreatedAt,
+	})}, nil
+}
+
+// ListPasskeys lists the caller's passkeys, oldest first.
+func (s *AuthServer) ListPasskeys(ctx context.Context, req *auth.ListPasskeysRequest) (*auth.ListPasskeysResponse, error) {
+	userID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.New(s.sqlDB).ListPasskeys(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list passkeys: list passkeys: %v", err)
+	}
+	passkeys := make([]*auth.Passkey, 0, len(rows))
+	for _, r := range rows {
+		passkeys = append(passkeys, passkeyFromRow(r))
+	}
+	return &auth.ListPasskeysResponse{Passkeys: passkeys}, nil
+}
+
+// DeletePasskey removes one of the caller's passkeys. Logins it started stay
+// signed in.
+func (s *AuthServer) DeletePasskey(ctx context.Context, req *auth.DeletePasskeyRequest) (*auth.DeletePasskeyResponse, error) {
+	userID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	deleted, err := db.New(s.sqlDB).DeletePasskey(ctx, db.DeletePasskeyParams{ID: req.Id, UserID: userID})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "delete passkey: %v", err)
+	}
+	if deleted == 0 {
+		return nil, status.Error(codes.NotFound, "passkey not found")
+	}
+	return &auth.DeletePasskeyResponse{}, nil
+}
+
+// BeginPasskeyLogin starts a passwordless sign-in and returns the options for
+// navigator.credentials.get. No username is needed: the user picks a passkey
+// and it says whose it is.
+func (s *AuthServer) BeginPasskeyLogin(ctx context.Context, req *auth.BeginPasskeyLoginRequest) (*auth.BeginPasskeyLoginResponse, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := newWebAuthnChallenge(ctx, db.New(s.sqlDB), uuid.NullUUID{}, passkeyPurposeLogin)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "begin passkey login: create challenge: %v", err)
+	}
+	options, err := rp.RequestOptions(challenge, nil, webauthn.UserVerificationRequired)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "begin passkey login: build options: %v", err)
+	}
+	return &auth.BeginPasskeyLoginResponse{OptionsJson: string(options)}, nil
+}
+
+// FinishPasskeyLogin signs in with the passkey the browser returned for
+// BeginPasskeyLogin. The authenticator must have verified the user, so the
+// passkey counts as both factors and two-factor sign-in asks for nothing
+// more.
+func (s *AuthServer) FinishPasskeyLogin(ctx context.Context, req *auth.FinishPasskeyLoginRequest) (*auth.FinishPasskeyLoginResponse, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+	if req.CredentialJson == "" {
+		return nil, status.Error(codes.InvalidArgument, "credential_json is required")
+	}
+	if err := validateDeviceName(req.DeviceName); err != nil {
+		return nil, err
+	}
+	assertion, err := webauthn.ParseAssertion([]byte(req.CredentialJson))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid credential: %v", err)
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "finish passkey login: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	queries := db.New(tx)
+
+	_, ok, err := spendWebAuthnChallenge(ctx, queries, assertion.Challenge(), passkeyPurposeLogin)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "finish passkey login: get challenge: %v", err)
+	}
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired passkey challenge")
+	}
+	cred, err := verifyPasskey(ctx, queries, rp, assertion, true)
+	if err != nil {
+		if _, isStatus := status.FromError(err); isStatus {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "finish passkey login: verify passkey: %v", err)
+	}
+
+	// Suspended and banned users cannot sign in.
+	standingErr, _, err := accountStandingError(ctx, queries, cred.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "finish passkey login: check account standing: %v", err)
+	}
+	if standingErr != nil {
+		return nil, standingErr
+	}
+	user, err := queries.GetUserByID(ctx, cred.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "finish passkey login: get user: %v", err)
+	}
+
+	token, refreshToken, err := s.startLogin(ctx, queries, user.UserID, user.UserName, req.DeviceName)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "finish passkey login: start login: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "finish passkey login: commit: %v", err)
+	}
+
+	return &auth.FinishPasskeyLoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		Username:     user.UserName,
+	}, nil
+}
+
+// BeginSecondFactorPasskey lets a sign-in that returned
+// second_factor_required be finished with one of the user's passkeys instead
+// of a code. The answer goes to VerifySecondFactor with the same challenge
+// token.
+func (s *AuthServer) BeginSecondFactorPasskey(ctx context.Context, req *auth.BeginSecondFactorPasskeyRequest) (*auth.BeginSecondFactorPasskeyResponse, error) {
+	rp, err := s.relyingParty()
+	if err != nil {
+		return nil, err
+	}
+	if req.ChallengeToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "challenge_token is required")
+	}
+
+	queries := db.New(s.sqlDB)
+	challenge, err := queries.GetLoginChallengeForUpdate(ctx, lib.HashToken(req.ChallengeToken))
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired challenge")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "begin second factor passkey: get challenge: %v", err)
+	}
+	if challenge.UsedAt.Valid || !time.Now().Before(challenge.ExpiresAt) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired challenge")
+	}
+
+	passkeys, err := queries.ListPasskeys(ctx, challenge.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "begin second factor passkey: list passkeys: %v", err)
+	}
+	if len(passkeys) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "no passkeys registered")
+	}
+	webauthnChallenge, err := newWebAuthnChallenge(ctx, queries, uuid.NullUUID{UUID: challenge.UserID, Valid: true}, passkeyPurposeSecondFactor)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "begin second factor passkey: create challenge: %v", err)
+	}
+	options, err := rp.RequestOptions(webauthnChallenge, passkeyDescriptors(passkeys), webauthn.UserVerificationPreferred)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "begin second factor passkey: build options: %v", err)
+	}
+	return &auth.BeginSecondFactorPasskeyResponse{OptionsJson: string(options)}, nil
+}
@@ -0,0 +1,430 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
+	"github.com/zukigit/chat/backend/internal/mailer"
+	"github.com/zukigit/chat/backend/proto/auth"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultPasswordResetTTL is how long a reset link stays usable when
+// PasswordReset.TTL is zero.
+const DefaultPasswordResetTTL = time.Hour
+
+// maxEmailLength is the longest address SMTP can deliver to (RFC 5321).
+const maxEmailLength = 254
+
+// DefaultPasswordResetsPerHour is how many reset requests one address may
+// make in any hour unless configured otherwise.
+const DefaultPasswordResetsPerHour = 3
+
+// PasswordReset configures the mailed password reset flow.
+type PasswordReset struct {
+	Mailer  mailer.Mailer // nil disables RequestPasswordReset
+	LinkURL string        // the reset page; the token is added as ?token=
+	TTL     time.Duration // how long a link stays usable
+	PerHour int           // requests one address may make in any hour; zero disables the limit
+}
+
+// SetPasswordReset enables password reset requests. The links are sent by a
+// PasswordResetWorker created with the same cfg.
+func (s *AuthServer) SetPasswordReset(cfg PasswordReset) {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultPasswordResetTTL
+	}
+	s.reset = cfg
+}
+
+// validateEmail accepts a bare address such as alice@example.com.
+func validateEmail(email string) error {
+	if email == "" {
+		return status.Error(codes.InvalidArgument, "email is required")
+	}
+	if len(email) > maxEmailLength {
+		return status.Errorf(codes.InvalidArgument, "email must be at most %d characters", maxEmailLength)
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return status.Error(codes.InvalidArgument, "email is not a valid address")
+	}
+	return nil
+}
+
+// setRecoveryEmail stores email for userID unless another account already
+// uses it. That case is skipped without an error, so neither Signup nor
+// SetRecoveryEmail tells the caller which addresses are registered.
+func setRecoveryEmail(ctx context.Context, queries *db.Queries, userID uuid.UUID, email string) error {
+	owner, err := queries.GetRecoveryEmailOwner(ctx, email)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil && owner != userID {
+		return nil
+	}
+	err = queries.SetRecoveryEmail(ctx, db.SetRecoveryEmailParams{UserID: userID, Email: email})
+	if lib.IsPgUniqueViolation(err) {
+		return status.Error(codes.Aborted, "recovery email raced another change, please try again")
+	}
+	return err
+}
+
+// hashPassword bcrypt-hashes a new password for storage.
+func hashPassword(passwd string) (sql.NullString, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(passwd), bcrypt.DefaultCost)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(hashed), Valid: true}, nil
+}
+
+// checkPassword loads the user and verifies passwd against their stored hash.
+// Accounts without a password (OAuth sign-ups) fail with FailedPrecondition.
+func checkPassword(ctx context.Context, queries *db.Queries, userID uuid.UUID, passwd string) error {
+	user, err := queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.HashedPasswd.Valid {
+		return status.Error(codes.FailedPrecondition, "account has no password")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.HashedPasswd.String), []byte(passwd)) != nil {
+		return status.Error(codes.PermissionDenied, "password is incorrect")
+	}
+	return nil
+}
+
+// replacePassword stores a new password for userID, retires any outstanding
+// reset links and revokes every login except keep (uuid.Nil revokes all). It
+// returns the revoked logins for the caller to Disconnect after commit.
+func replacePassword(ctx context.Context, queries *db.Queries, userID uuid.UUID, passwd string, keep uuid.UUID) ([]uuid.UUID, error) {
+	hashed, err := hashPassword(passwd)
+	if err != nil {
+		return nil, err
+	}
+	if err := queries.UpdatePassword(ctx, db.UpdatePasswordParams{HashedPasswd: hashed, UserID: userID}); err != nil {
+		return nil, err
+	}
+	if err := queries.RetirePasswordResetTokens(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	loginIDs, err := queries.ListUnrevokedLoginIDs(ctx, db.ListUnrevokedLoginIDsParams{UserID: userID, ExceptLoginID: keep})
+	if err != nil {
+		return nil, err
+	}
+	for _, loginID := range loginIDs {
+		if err := revokeLogin(ctx, queries, userID, loginID); err != nil {
+			return nil, err
+		}
+	}
+	return loginIDs, nil
+}
+
+// ChangePassword replaces the caller's password after checking the current
+// one. Every other login is signed out, as by RevokeSession with all_others,
+// so a stolen session does not outlive the change.
+func (s *AuthServer) ChangePassword(ctx context.Context, req *auth.ChangePasswordRequest) (*auth.ChangePasswordResponse, error) {
+	userID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := uuid.Parse(lib.CallerLoginID(ctx))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "missing login_id in token")
+	}
+	if req.CurrentPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "current_password is required")
+	}
+	if req.NewPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_password is required")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "change password: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	queries := db.New(tx)
+
+	if err := checkPassword(ctx, queries, userID, req.CurrentPassword); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "change password: check password: %v", err)
+	}
+	loginIDs, err := replacePassword(ctx, queries, userID, req.NewPassword, current)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "change password: replace password: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "change password: commit: %v", err)
+	}
+
+	for _, loginID := range loginIDs {
+		s.sessions.Disconnect(loginID)
+	}
+	return &auth.ChangePasswordResponse{RevokedCount: int32(len(loginIDs))}, nil
+}
+
+// SetRecoveryEmail sets where the caller's password reset links are sent.
+// The current password is required so a borrowed session cannot redirect
+// resets to another inbox. An address another account already uses is not
+// stored, and the response is the same as when it is.
+func (s *AuthServer) SetRecoveryEmail(ctx context.Context, req *auth.SetRecoveryEmailRequest) (*auth.SetRecoveryEmailResponse, error) {
+	userID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateEmail(req.Email); err != nil {
+		return nil, err
+	}
+	if req.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	queries := db.New(s.sqlDB)
+	if err := checkPassword(ctx, queries, userID, req.Password); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "set recovery email: check password: %v", err)
+	}
+	if err := setRecoveryEmail(ctx, queries, userID, req.Email); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "set recovery email: %v", err)
+	}
+	return &auth.SetRecoveryEmailResponse{}, nil
+}
+
+// RequestPasswordReset queues a reset link for the account whose recovery
+// email is req.Email; PasswordResetWorker mails it. Only the request is
+// recorded here, so the response and the time it takes are the same whether
+// or not the address is known, and the endpoint cannot be used to probe for
+// accounts. An address gets at most PasswordReset.PerHour requests an hour.
+func (s *AuthServer) RequestPasswordReset(ctx context.Context, req *auth.RequestPasswordResetRequest) (*auth.RequestPasswordResetResponse, error) {
+	if s.reset.Mailer == nil {
+		return nil, status.Error(codes.FailedPrecondition, "password reset is not configured")
+	}
+	if err := validateEmail(req.Email); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "request password reset: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	queries := db.New(tx)
+
+	if s.reset.PerHour > 0 {
+		if err := queries.LockPasswordResetEmail(ctx, req.Email); err != nil {
+			return nil, status.Errorf(codes.Internal, "request password reset: lock email: %v", err)
+		}
+		count, err := queries.GetPasswordResetRequestCount(ctx, req.Email)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "request password reset: count requests: %v", err)
+		}
+		if int(count.LastHour) >= s.reset.PerHour {
+			retryAfter := time.Until(count.OldestInHour.Add(time.Hour))
+			if retryAfter < time.Second {
+				retryAfter = time.Second
+			}
+			msg := fmt.Sprintf("too many reset requests for this address (%d per hour): try again in %d minutes", s.reset.PerHour, int64(math.Ceil(retryAfter.Minutes())))
+			return nil, lib.NewRetryError(codes.ResourceExhausted, msg, retryAfter)
+		}
+	}
+
+	if err := queries.CreatePasswordResetRequest(ctx, req.Email); err != nil {
+		return nil, status.Errorf(codes.Internal, "request password reset: queue request: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "request password reset: commit: %v", err)
+	}
+	return &auth.RequestPasswordResetResponse{}, nil
+}
+
+// PasswordResetWorker mails the reset links queued by RequestPasswordReset
+// and purges requests too old to count towards the rate limit.
+type PasswordResetWorker struct {
+	sqlDB    *sql.DB
+	reset    PasswordReset
+	interval time.Duration
+}
+
+// NewPasswordResetWorker creates a PasswordResetWorker that sends links
+// through cfg.Mailer.
+func NewPasswordResetWorker(sqlDB *sql.DB, cfg PasswordReset) *PasswordResetWorker {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultPasswordResetTTL
+	}
+	return &PasswordResetWorker{sqlDB: sqlDB, reset: cfg, interval: 5 * time.Second}
+}
+
+// Run polls for queued requests until ctx is cancelled.
+func (w *PasswordResetWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	var purged time.Time
+	for {
+		for {
+			ok, err := w.ProcessNext(ctx)
+			if err != nil {
+				lib.ErrorLog.Printf("PasswordResetWorker: %v", err)
+			}
+			if !ok {
+				break
+			}
+		}
+		if time.Since(purged) > time.Hour {
+			if _, err := db.New(w.sqlDB).DeletePasswordResetRequestsBefore(ctx, time.Now().Add(-24*time.Hour)); err != nil {
+				lib.ErrorLog.Printf("PasswordResetWorker: purge requests: %v", err)
+			} else {
+				purged = time.Now()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessNext claims the oldest queued request and, if its address is the
+// recovery email of a password account, retires the account's earlier links
+// and mails a new one. It reports whether a request was claimed. A request
+// is processed once: a failed delivery is logged, not retried.
+func (w *PasswordResetWorker) ProcessNext(ctx context.Context) (bool, error) {
+	tx, err := w.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	queries := db.New(tx)
+
+	req, err := queries.ClaimPasswordResetRequest(ctx)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("claim request: %w", err)
+	}
+
+	user, err := queries.GetPasswordUserByRecoveryEmail(ctx, req.Email)
+	if err == sql.ErrNoRows {
+		if err := tx.Commit(); err != nil {
+			return true, fmt.Errorf("request %d: commit: %w", req.ID, err)
+		}
+		return true, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("request %d: get user: %w", req.ID, err)
+	}
+
+	token, err := lib.RandomToken(32)
+	if err != nil {
+		return true, fmt.Errorf("request %d: generate token: %w", req.ID, err)
+	}
+	if err := queries.RetirePasswordResetTokens(ctx, user.UserID); err != nil {
+		return true, fmt.Errorf("request %d: retire tokens: %w", req.ID, err)
+	}
+	if err := queries.DeleteExpiredPasswordResetTokens(ctx, user.UserID); err != nil {
+		return true, fmt.Errorf("request %d: delete expired tokens: %w", req.ID, err)
+	}
+	if err := queries.CreatePasswordResetToken(ctx, db.CreatePasswordResetTokenParams{
+		UserID:    user.UserID,
+		TokenHash: lib.HashToken(token),
+		ExpiresAt: time.Now().Add(w.reset.TTL),
+	}); err != nil {
+		return true, fmt.Errorf("request %d: create token: %w", req.ID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return true, fmt.Errorf("request %d: commit: %w", req.ID, err)
+	}
+
+	if err := w.reset.Mailer.Send(ctx, resetMessage(user.Email, user.UserName, w.reset.link(token), w.reset.TTL)); err != nil {
+		lib.ErrorLog.Printf("PasswordResetWorker: send mail to user %s: %v", user.UserID, err)
+	}
+	return true, nil
+}
+
+// link returns the reset page URL carrying token.
+func (cfg PasswordReset) link(token string) string {
+	sep := "?"
+	if strings.Contains(cfg.LinkURL, "?") {
+		sep = "&"
+	}
+	return cfg.LinkURL + sep + "token=" + url.QueryEscape(token)
+}
+
+// resetMessage is the email carrying a reset link.
+func resetMessage(to, username, link string, ttl time.Duration) mailer.Message {
+	return mailer.Message{
+		To:      to,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Someone asked to reset the password of your account. To choose a new one, open:\n\n"+
+			"%s\n\n"+
+			"The link works once and expires in %s. If you did not ask for this, ignore this email.\n",
+			username, link, ttl),
+	}
+}
+
+// ResetPassword sets a new password with the token from a reset link. The
+// token is spent, and every login of the account is signed out.
+func (s *AuthServer) ResetPassword(ctx context.Context, req *auth.ResetPasswordRequest) (*auth.ResetPasswordResponse, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	if req.NewPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_password is required")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "reset password: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	queries := db.New(tx)
+
+	stored, err := queries.GetPasswordResetTokenForUpdate(ctx, lib.HashToken(req.Token))
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.InvalidArgument, "invalid or expired reset token")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "reset password: get token: %v", err)
+	}
+	if stored.UsedAt.Valid || !time.Now().Before(stored.ExpiresAt) {
+		return nil, status.Error(codes.InvalidArgument, "invalid or expired reset token")
+	}
+
+	loginIDs, err := replacePassword(ctx, queries, stored.UserID, req.NewPassword, uuid.Nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "reset password: replace password: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "reset password: commit: %v", err)
+	}
+
+	for _, loginID := range loginIDs {
+		s.sessions.Disconnect(loginID)
+	}
+	return &auth.ResetPasswordResponse{}, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
+	"github.com/zukigit/chat/backend/proto/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Audiences accepted by each privacy setting. They mirror the CHECKs on the
+// user_settings table.
+var (
+	discoverabilityAudiences = []db.PrivacyAudience{db.PrivacyAudienceEveryone, db.PrivacyAudienceFriendsOfFriends, db.PrivacyAudienceNobody}
+	friendRequestAudiences   = []db.PrivacyAudience{db.PrivacyAudienceEveryone, db.PrivacyAudienceFriendsOfFriends, db.PrivacyAudienceNobody}
+	lastSeenAudiences        = []db.PrivacyAudience{db.PrivacyAudienceEveryone, db.PrivacyAudienceFriends, db.PrivacyAudienceNobody}
+)
+
+// GetPrivacySettings returns the caller's privacy settings.
+func (s *AuthServer) GetPrivacySettings(ctx context.Context, _ *auth.GetPrivacySettingsRequest) (*auth.PrivacySettings, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := db.New(s.sqlDB).GetPrivacySettings(ctx, callerID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get privacy settings: %v", err)
+	}
+
+	return &auth.PrivacySettings{
+		Discoverability: string(settings.Discoverability),
+		FriendRequests:  string(settings.FriendRequestPolicy),
+		ReadReceipts:    settings.ReadReceipts,
+		LastSeen:        string(settings.LastSeenVisibility),
+	}, nil
+}
+
+// UpdatePrivacySettings changes the caller's privacy settings that are set in
+// req and returns the result.
+func (s *AuthServer) UpdatePrivacySettings(ctx context.Context, req *auth.UpdatePrivacySettingsRequest) (*auth.PrivacySettings, error) {
+	callerID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Discoverability == nil && req.FriendRequests == nil && req.ReadReceipts == nil && req.LastSeen == nil {
+		return nil, status.Error(codes.InvalidArgument, "no settings to update")
+	}
+
+	params := db.UpsertPrivacySettingsParams{UserID: callerID}
+	if params.Discoverability, err = parseAudience("discoverability", req.Discoverability, discoverabilityAudiences); err != nil {
+		return nil, err
+	}
+	if params.FriendRequestPolicy, err = parseAudience("friend_requests", req.FriendRequests, friendRequestAudiences); err != nil {
+		return nil, err
+	}
+	if params.LastSeenVisibility, err = parseAudience("last_seen", req.LastSeen, lastSeenAudiences); err != nil {
+		return nil, err
+	}
+	if req.ReadReceipts != nil {
+		params.ReadReceipts = sql.NullBool{Valid: true, Bool: *req.ReadReceipts}
+	}
+
+	settings, err := db.New(s.sqlDB).UpsertPrivacySettings(ctx, params)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "update privacy settings: %v", err)
+	}
+
+	return &auth.PrivacySettings{
+		Discoverability: string(settings.Discoverability),
+		FriendRequests:  string(settings.FriendRequestPolicy),
+		ReadReceipts:    settings.ReadReceipts,
+		LastSeen:        string(settings.LastSeenVisibility),
+	}, nil
+}
+
+// parseAudience converts an optional setting value to a NullPrivacyAudience,
+// rejecting values outside allowed. A nil value leaves the setting unchanged.
+func parseAudience(field string, value *string, allowed []db.PrivacyAudience) (db.NullPrivacyAudience, error) {
+	if value == nil {
+		return db.NullPrivacyAudience{}, nil
+	}
+	for _, a := range allowed {
+		if string(a) == *value {
+			return db.NullPrivacyAudience{Valid: true, PrivacyAudience: a}, nil
+		}
+	}
+	return db.NullPrivacyAudience{}, status.Errorf(codes.InvalidArgument, "invalid %s: %q (must be one of %v)", field, *value, allowed)
+}
+
+// lastSeenVisible reports whether viewerID may see ownerID's last-seen time.
+func lastSeenVisible(ctx context.Context, q *db.Queries, ownerID, viewerID uuid.UUID) (bool, error) {
+	settings, err := q.GetPrivacySettings(ctx, ownerID)
+	if err != nil {
+		return false, err
+	}
+	return q.AudienceAllows(ctx, db.AudienceAllowsParams{
+		Audience: settings.LastSeenVisibility,
+		OwnerID:  ownerID,
+		ViewerID: viewerID,
+	})
+}
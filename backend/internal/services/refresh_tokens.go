@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
+	"github.com/zukigit/chat/backend/proto/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultRefreshTokenTTL is how long a refresh token stays usable when
+// SetRefreshTokenTTL is not called.
+const DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+// SetRefreshTokenTTL changes how long newly issued refresh tokens stay usable.
+func (s *AuthServer) SetRefreshTokenTTL(ttl time.Duration) {
+	s.refreshTTL = ttl
+}
+
+// issueTokens signs an access token for the user's login loginID and stores a
+// fresh refresh token for the same login.
+func (s *AuthServer) issueTokens(ctx context.Context, queries *db.Queries, userID uuid.UUID, username string, loginID uuid.UUID) (token, refreshToken string, err error) {
+	token, err = lib.GenerateTokenForLogin(userID.String(), username, loginID.String(), lib.AccessTokenTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, err = lib.RandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	if err := queries.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
+		UserID:    userID,
+		LoginID:   loginID,
+		TokenHash: lib.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}); err != nil {
+		return "", "", err
+	}
+
+	// Keep the table small: a user's expired tokens are no longer needed.
+	if err := queries.DeleteExpiredRefreshTokens(ctx, userID); err != nil {
+		return "", "", err
+	}
+	return token, refreshToken, nil
+}
+
+// RefreshToken trades a refresh token for a new access token and its
+// successor refresh token, keeping the login_id so the session's durable
+// consumer carries over. A refresh token that was already used means it was
+// copied; the whole login is signed out, as by Logout.
+func (s *AuthServer) RefreshToken(ctx context.Context, req *auth.RefreshTokenRequest) (*auth.RefreshTokenResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "refresh token: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	queries := db.New(tx)
+
+	stored, err := queries.GetRefreshTokenForUpdate(ctx, lib.HashToken(req.RefreshToken))
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired refresh token")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "refresh token: get token: %v", err)
+	}
+	if stored.RevokedAt.Valid || !time.Now().Before(stored.ExpiresAt) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired refresh token")
+	}
+	if stored.UsedAt.Valid {
+		if err := revokeLogin(ctx, queries, stored.UserID, stored.LoginID); err != nil {
+			return nil, status.Errorf(codes.Internal, "refresh token: revoke login: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, status.Errorf(codes.Internal, "refresh token: commit: %v", err)
+		}
+		s.sessions.Disconnect(stored.LoginID)
+		lib.WarnLog.Printf("refresh token reuse for login %s of user %s; login revoked", stored.LoginID, stored.UserID)
+		return nil, status.Error(codes.Unauthenticated, "refresh token reuse detected; sign in again")
+	}
+
+	standingErr, _, err := accountStandingError(ctx, queries, stored.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "refresh token: check account standing: %v", err)
+	}
+	if standingErr != nil {
+		return nil, standingErr
+	}
+
+	// Use the current username: the user may have been renamed since login.
+	user, err := queries.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "refresh token: get user: %v", err)
+	}
+
+	if err := queries.MarkRefreshTokenUsed(ctx, stored.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "refresh token: mark used: %v", err)
+	}
+	token, refreshToken, err := s.issueTokens(ctx, queries, user.UserID, user.UserName, stored.LoginID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "refresh token: issue tokens: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "refresh token: commit: %v", err)
+	}
+
+	return &auth.RefreshTokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		Username:     user.UserName,
+	}, nil
+}
@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"database/sql"
 
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
 	"github.com/zukigit/chat/backend/internal/lib"
 	"github.com/zukigit/chat/backend/proto/session"
 	"google.golang.org/grpc/codes"
@@ -11,10 +14,11 @@ import (
 
 type SessionServer struct {
 	session.UnimplementedSessionServer
+	sqlDB *sql.DB
 }
 
-func NewSessionServer() *SessionServer {
-	return &SessionServer{}
+func NewSessionServer(sqlDB *sql.DB) *SessionServer {
+	return &SessionServer{sqlDB: sqlDB}
 }
 
 func (s *SessionServer) Ping(ctx context.Context, req *session.PingRequest) (*session.PingResponse, error) {
@@ -25,6 +29,8 @@ func (s *SessionServer) Ping(ctx context.Context, req *session.PingRequest) (*se
 // The JWT is already validated by the gRPC interceptor.
 // Format: sessions.noti.<user_id> or sessions.chat.<user_id>
 // The caller's login_id is used separately for the durable consumer name.
+// Opening a session counts as being seen, so the caller's last_seen_at is
+// updated.
 func (s *SessionServer) GetListenPath(ctx context.Context, req *session.GetListenPathRequest) (*session.GetListenPathResponse, error) {
 	userID := lib.CallerIDFrom(ctx)
 	loginID := lib.CallerLoginID(ctx)
@@ -33,12 +39,21 @@ func (s *SessionServer) GetListenPath(ctx context.Context, req *session.GetListe
 		return nil, status.Error(codes.Unauthenticated, "missing user_id or login_id in token")
 	}
 
+	var resp *session.GetListenPathResponse
 	switch req.GetType() {
 	case "chat":
-		return &session.GetListenPathResponse{ListenPath: lib.ChatSubjectPrefix + userID, ConsumerName: "chat-" + loginID}, nil
+		resp = &session.GetListenPathResponse{ListenPath: lib.ChatSubjectPrefix + userID, ConsumerName: "chat-" + loginID}
 	case "notification":
-		return &session.GetListenPathResponse{ListenPath: lib.NotiSubjectPrefix + userID, ConsumerName: "noti-" + loginID}, nil
+		resp = &session.GetListenPathResponse{ListenPath: lib.NotiSubjectPrefix + userID, ConsumerName: "noti-" + loginID}
 	default:
 		return nil, status.Errorf(codes.InvalidArgument, "unknown type: %q (must be 'chat' or 'notification')", req.GetType())
 	}
+
+	if callerID, err := uuid.Parse(userID); err == nil {
+		if err := db.New(s.sqlDB).UpdateLastSeen(ctx, callerID); err != nil {
+			lib.ErrorLog.Printf("GetListenPath: update last seen of %s: %v", userID, err)
+		}
+	}
+
+	return resp, nil
 }
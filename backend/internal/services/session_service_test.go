@@ -3,7 +3,9 @@ package services_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/zukigit/chat/backend/internal/db"
 	"github.com/zukigit/chat/backend/internal/lib"
 	"github.com/zukigit/chat/backend/internal/services"
 	pb "github.com/zukigit/chat/backend/proto/session"
@@ -11,7 +13,8 @@ import (
 )
 
 func TestGetListenPath(t *testing.T) {
-	sessionServer := services.NewSessionServer()
+	sqlDB := setupTestDB(t)
+	sessionServer := services.NewSessionServer(sqlDB)
 
 	cases := []struct {
 		name    string
@@ -63,4 +66,19 @@ func TestGetListenPath(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("opening a session updates last seen", func(t *testing.T) {
+		ids := createTestUsers(t, sqlDB, "alice")
+		ctx := ctxWithUser("alice", ids["alice"])
+		if _, err := sessionServer.GetListenPath(ctx, &pb.GetListenPathRequest{Type: "chat"}); err != nil {
+			t.Fatalf("GetListenPath: %v", err)
+		}
+		user, err := db.New(sqlDB).GetUserByID(ctx, ids["alice"])
+		if err != nil {
+			t.Fatalf("GetUserByID: %v", err)
+		}
+		if !user.LastSeenAt.Valid || time.Since(user.LastSeenAt.Time) > time.Minute {
+			t.Errorf("last_seen_at: got %v, want about now", user.LastSeenAt)
+		}
+	})
 }
@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zukigit/chat/backend/internal/db"
+	"github.com/zukigit/chat/backend/internal/lib"
+	"github.com/zukigit/chat/backend/internal/totp"
+	"github.com/zukigit/chat/backend/internal/webauthn"
+	"github.com/zukigit/chat/backend/proto/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultTOTPIssuer names the service in authenticator apps when
+// SetTOTPIssuer is not called.
+const DefaultTOTPIssuer = "Chat"
+
+const (
+	// loginChallengeTTL is how long a user has to enter a code after the
+	// first factor.
+	loginChallengeTTL = 5 * time.Minute
+	// recoveryCodeCount is how many recovery codes ConfirmTOTP hands out.
+	recoveryCodeCount = 10
+	// maxSecondFactorFailures wrong codes within secondFactorLockout lock the
+	// second factor until secondFactorLockout after the last of them. The
+	// count is per user, not per challenge, so signing in again does not buy
+	// more guesses.
+	maxSecondFactorFailures = 5
+	secondFactorLockout     = 15 * time.Minute
+)
+
+// SetTOTPIssuer changes the service name authenticator apps show next to the
+// user's codes.
+func (s *AuthServer) SetTOTPIssuer(issuer string) {
+	s.totpIssuer = issuer
+}
+
+// recoveryEncoding spells recovery codes in lowercase base32.
+var recoveryEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// normalizeRecoveryCode drops the dashes and spaces users may type and folds
+// case, so "ABCD-efgh" and "abcdefgh" match.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// replaceRecoveryCodes deletes userID's recovery codes and stores a fresh set,
+// returning them formatted as xxxx-xxxx-xxxx-xxxx.
+func replaceRecoveryCodes(ctx context.Context, queries *db.Queries, userID uuid.UUID) ([]string, error) {
+	if err := queries.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := recoveryEncoding.EncodeToString(b)
+		if err := queries.CreateRecoveryCode(ctx, db.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: lib.HashToken(raw),
+		}); err != nil {
+			return nil, err
+		}
+		recoveryCodes = append(recoveryCodes, raw[0:4]+"-"+raw[4:8]+"-"+raw[8:12]+"-"+raw[12:16])
+	}
+	return recoveryCodes, nil
+}
+
+// checkSecondFactor reports whether code is a valid TOTP code for factor or,
+// with allowRecovery, one of userID's unused recovery codes, which is then
+// spent. A wrong code counts towards the lockout; callers commit even when it
+// returns false so the count sticks. While locked out it returns a
+// ResourceExhausted status without looking at code.
+func checkSecondFactor(ctx context.Context, queries *db.Queries, userID uuid.UUID, factor db.GetTOTPFactorForUpdateRow, code string, allowRecovery bool) (bool, error) {
+	now := time.Now()
+	if factor.FailedAttempts >= maxSecondFactorFailures && factor.LastFailedAt.Valid && now.Sub(factor.LastFailedAt.Time) < secondFactorLockout {
+		return false, status.Error(codes.ResourceExhausted, "too many invalid codes; try again later")
+	}
+
+	code = strings.TrimSpace(code)
+	if step, ok := totp.Validate(factor.Secret, code, now, factor.LastUsedStep); ok {
+		return true, queries.RecordTOTPSuccess(ctx, db.RecordTOTPSuccessParams{Step: step, UserID: userID})
+	}
+	if allowRecovery {
+		used, err := queries.UseRecoveryCode(ctx, db.UseRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: lib.HashToken(normalizeRecoveryCode(code)),
+		})
+		if err != nil {
+			return false, err
+		}
+		if used == 1 {
+			return true, queries.RecordTOTPSuccess(ctx, db.RecordTOTPSuccessParams{Step: factor.LastUsedStep, UserID: userID})
+		}
+	}
+	return false, queries.RecordTOTPFailure(ctx, db.RecordTOTPFailureParams{
+		ResetBefore: now.Add(-secondFactorLockout),
+		UserID:      userID,
+	})
+}
+
+// secondFactorChallenge returns a challenge token for VerifySecondFactor if
+// userID has two-factor sign-in on, or "" if the login can start right away.
+// deviceName is kept for the login the challenge leads to.
+func secondFactorChallenge(ctx context.Context, queries *db.Queries, userID uuid.UUID, deviceName string) (string, error) {
+	enabled, err := queries.IsTOTPEnabled(ctx, userID)
+	if err != nil || !enabled {
+		return "", err
+	}
+
+	token, err := lib.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	if err := queries.DeleteExpiredLoginChallenges(ctx, userID); err != nil {
+		return "", err
+	}
+	if err := queries.CreateLoginChallenge(ctx, db.CreateLoginChallengeParams{
+		UserID:     userID,
+		TokenHash:  lib.HashToken(token),
+		DeviceName: deviceName,
+		ExpiresAt:  time.Now().Add(loginChallengeTTL),
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// EnrollTOTP generates a new authenticator secret for the caller. Sign-in is
+// unchanged until ConfirmTOTP proves the authenticator works; enrolling again
+// before that replaces the secret.
+func (s *AuthServer) EnrollTOTP(ctx context.Context, req *auth.EnrollTOTPRequest) (*auth.EnrollTOTPResponse, error) {
+	userID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := db.New(s.sqlDB)
+	user, err := queries.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "enroll totp: get user: %v", err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "enroll totp: generate secret: %v", err)
+	}
+	started, err := queries.StartTOTPEnrollment(ctx, db.StartTOTPEnrollmentParams{UserID: userID, Secret: secret})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "enroll totp: store secret: %v", err)
+	}
+	if started == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "two-factor sign-in is already on")
+	}
+
+	return &auth.EnrollTOTPResponse{
+		Secret:     secret,
+		OtpauthUri: totp.URI(s.totpIssuer, user.UserName, secret),
+	}, nil
+}
+
+// ConfirmTOTP turns two-factor sign-in on once code shows the enrolled
+// authenticator works, and returns the recovery codes. They are only ever
+// shown here.
+func (s *AuthServer) ConfirmTOTP(ctx context.Context, req *auth.ConfirmTOTPRequest) (*auth.ConfirmTOTPResponse, error) {
+	userID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "confirm totp: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	queries := db.New(tx)
+
+	factor, err := queries.GetTOTPFactorForUpdate(ctx, userID)
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.FailedPrecondition, "no two-factor enrollment in progress")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "confirm totp: get factor: %v", err)
+	}
+	if factor.ConfirmedAt.Valid {
+		return nil, status.Error(codes.FailedPrecondition, "two-factor sign-in is already on")
+	}
+
+	ok, err := checkSecondFactor(ctx, queries, userID, factor, req.Code, false)
+	if err != nil {
+		if _, isStatus := status.FromError(err); isStatus {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "confirm totp: check code: %v", err)
+	}
+	if !ok {
+		if err := tx.Commit(); err != nil {
+			return nil, status.Errorf(codes.Internal, "confirm totp: commit: %v", err)
+		}
+		return nil, status.Error(codes.InvalidArgument, "invalid code")
+	}
+
+	if err := queries.ConfirmTOTPFactor(ctx, userID); err != nil {
+		return nil, status.Errorf(codes.Internal, "confirm totp: confirm factor: %v", err)
+	}
+	recoveryCodes, err := replaceRecoveryCodes(ctx, queries, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "confirm totp: create recovery codes: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "confirm totp: commit: %v", err)
+	}
+
+	return &auth.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// DisableTOTP turns two-factor sign-in off. It takes a current code or a
+// recovery code, so a borrowed session alone cannot turn it off.
+func (s *AuthServer) DisableTOTP(ctx context.Context, req *auth.DisableTOTPRequest) (*auth.DisableTOTPResponse, error) {
+	userID, err := lib.CallerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "disable totp: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	queries := db.New(tx)
+
+	factor, err := queries.GetTOTPFactorForUpdate(ctx, userID)
+	if err == sql.ErrNoRows || (err == nil && !factor.ConfirmedAt.Valid) {
+		return nil, status.Error(codes.FailedPrecondition, "two-factor sign-in is not on")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "disable totp: get factor: %v", err)
+	}
+
+	ok, err := checkSecondFactor(ctx, queries, userID, factor, req.Code, true)
+	if err != nil {
+		if _, isStatus := status.FromError(err); isStatus {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "disable totp: check code: %v", err)
+	}
+	if !ok {
+		if err := tx.Commit(); err != nil {
+			return nil, status.Errorf(codes.Internal, "disable totp: commit: %v", err)
+		}
+		return nil, status.Error(codes.InvalidArgument, "invalid code")
+	}
+
+	if err := queries.DeleteTOTPFactor(ctx, userID); err != nil {
+		return nil, status.Errorf(codes.Internal, "disable totp: delete factor: %v", err)
+	}
+	if err := queries.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, status.Errorf(codes.Internal, "disable totp: delete recovery codes: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "disable totp: commit: %v", err)
+	}
+	return &auth.DisableTOTPResponse{}, nil
+}
+
+// VerifySecondFactor finishes a Login or ExchangeToken that returned
+// second_factor_required: with a valid code, or one of the user's passkeys
+// answering BeginSecondFactorPasskey, the challenge is spent and a login
+// starts as it would have without two-factor sign-in.
+func (s *AuthServer) VerifySecondFactor(ctx context.Context, req *auth.VerifySecondFactorRequest) (*auth.VerifySecondFactorResponse, error) {
+	if req.ChallengeToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "challenge_token is required")
+	}
+	if req.Code == "" && req.PasskeyCredentialJson == "" {
+		return nil, status.Error(codes.InvalidArgument, "code or passkey_credential_json is required")
+	}
+	var assertion *webauthn.Assertion
+	if req.Code == "" {
+		var err error
+		if assertion, err = webauthn.ParseAssertion([]byte(req.PasskeyCredentialJson)); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid credential: %v", err)
+		}
+	}
+
+	tx, err := s.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "verify second factor: begin tx: %v", err)
+	}
+	defer tx.Rollback()
+	queries := db.New(tx)
+
+	challenge, err := queries.GetLoginChallengeForUpdate(ctx, lib.HashToken(req.ChallengeToken))
+	if err == sql.ErrNoRows {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired challenge")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "verify second factor: get challenge: %v", err)
+	}
+	if challenge.UsedAt.Valid || !time.Now().Before(challenge.ExpiresAt) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired challenge")
+	}
+
+	// Two-factor sign-in was turned off since the challenge was issued: the
+	// user can simply sign in again.
+	factor, err := queries.GetTOTPFactorForUpdate(ctx, challenge.UserID)
+	if err == sql.ErrNoRows || (err == nil && !factor.ConfirmedAt.Valid) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired challenge")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "verify second factor: get factor: %v", err)
+	}
+
+	if assertion != nil {
+		if err := s.checkSecondFactorPasskey(ctx, queries, challenge.UserID, assertion); err != nil {
+			if _, isStatus := status.FromError(err); isStatus {
+				return nil, err
+			}
+			return nil, status.Errorf(codes.Internal, "verify second factor: check passkey: %v", err)
+		}
+	} else {
+		ok, err := checkSecondFactor(ctx, queries, challenge.UserID, factor, req.Code, true)
+		if err != nil {
+			if _, isStatus := status.FromError(err); isStatus {
+				return nil, err
+			}
+			return nil, status.Errorf(codes.Internal, "verify second factor: check code: %v", err)
+		}
+		if !ok {
+			if err := tx.Commit(); err != nil {
+				return nil, status.Errorf(codes.Internal, "verify second factor: commit: %v", err)
+			}
+			return nil, status.Error(codes.Unauthenticated, "invalid code")
+		}
+	}
+
+	if err := queries.MarkLoginChallengeUsed(ctx, challenge.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "verify second factor: mark challenge used: %v", err)
+	}
+
+	// The user may have been suspended or renamed since the first factor.
+	standingErr, _, err := accountStandingError(ctx, queries, challenge.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "verify second factor: check account standing: %v", err)
+	}
+	if standingErr != nil {
+		return nil, standingErr
+	}
+	user, err := queries.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "verify second factor: get user: %v", err)
+	}
+
+	token, refreshToken, err := s.startLogin(ctx, queries, user.UserID, user.UserName, challenge.DeviceName)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "verify second factor: start login: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, status.Errorf(codes.Internal, "verify second factor: commit: %v", err)
+	}
+
+	return &auth.VerifySecondFactorResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		Username:     user.UserName,
+	}, nil
+}
@@ -0,0 +1,91 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/zukigit/chat/backend/internal/totp"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238(t *testing.T) {
+	// The RFC lists eight-digit codes; six-digit codes are their last six.
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tc := range cases {
+		got, err := totp.Code(rfcSecret, totp.Step(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code: %v", err)
+		}
+		if got != tc.want {
+			t.Errorf("at %d: got %s, want %s", tc.unix, got, tc.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	step := totp.Step(now)
+	code := func(s int64) string {
+		c, err := totp.Code(secret, s)
+		if err != nil {
+			t.Fatalf("Code: %v", err)
+		}
+		return c
+	}
+
+	cases := []struct {
+		name      string
+		code      string
+		afterStep int64
+		wantOK    bool
+	}{
+		{"current", code(step), 0, true},
+		{"previous period", code(step - 1), 0, true},
+		{"next period", code(step + 1), 0, true},
+		{"too old", code(step - 2), 0, false},
+		{"already used", code(step), step, false},
+		{"wrong length", "12345", 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ok := totp.Validate(secret, tc.code, now, tc.afterStep)
+			if ok != tc.wantOK {
+				t.Errorf("got %v, want %v", ok, tc.wantOK)
+			}
+		})
+	}
+
+	if got, _ := totp.Validate(secret, code(step-1), now, 0); got != step-1 {
+		t.Errorf("step: got %d, want %d", got, step-1)
+	}
+}
+
+func TestURI(t *testing.T) {
+	u, err := url.Parse(totp.URI("Chat", "alice bob", "ABCDEF"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Chat:alice bob" {
+		t.Errorf("got %s", u)
+	}
+	if q := u.Query(); q.Get("secret") != "ABCDEF" || q.Get("issuer") != "Chat" || q.Get("digits") != "6" || q.Get("period") != "30" {
+		t.Errorf("query: got %v", q)
+	}
+}
@@ -0,0 +1,165 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// This is the subset of CBOR (RFC 8949) that attestation objects and COSE
+// keys use: integers, byte and text strings, arrays, maps and simple values,
+// all with definite lengths.
+
+// maxCBORDepth bounds nesting so hostile input cannot exhaust the stack.
+const maxCBORDepth = 16
+
+var errCBORTruncated = errors.New("webauthn: cbor: unexpected end of data")
+
+// cborDecoder reads CBOR items from data, advancing off as it goes.
+type cborDecoder struct {
+	data []byte
+	off  int
+}
+
+// decodeCBOR decodes the single item in data.
+func decodeCBOR(data []byte) (any, error) {
+	d := &cborDecoder{data: data}
+	v, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.off != len(data) {
+		return nil, errors.New("webauthn: cbor: trailing data")
+	}
+	return v, nil
+}
+
+// decodeCBORPrefix decodes the item at the start of data and returns how many
+// bytes it took, for items followed by more data.
+func decodeCBORPrefix(data []byte) (any, int, error) {
+	d := &cborDecoder{data: data}
+	v, err := d.decode(0)
+	return v, d.off, err
+}
+
+// head reads an item's major type and argument.
+func (d *cborDecoder) head() (major byte, arg uint64, err error) {
+	if d.off >= len(d.data) {
+		return 0, 0, errCBORTruncated
+	}
+	b := d.data[d.off]
+	d.off++
+	major, info := b>>5, b&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info <= 27:
+		n := 1 << (info - 24)
+		if len(d.data)-d.off < n {
+			return 0, 0, errCBORTruncated
+		}
+		buf := d.data[d.off : d.off+n]
+		d.off += n
+		switch n {
+		case 1:
+			arg = uint64(buf[0])
+		case 2:
+			arg = uint64(binary.BigEndian.Uint16(buf))
+		case 4:
+			arg = uint64(binary.BigEndian.Uint32(buf))
+		default:
+			arg = binary.BigEndian.Uint64(buf)
+		}
+		return major, arg, nil
+	default:
+		return 0, 0, fmt.Errorf("webauthn: cbor: unsupported additional info %d", info)
+	}
+}
+
+// bytes reads n bytes of string content.
+func (d *cborDecoder) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.off) {
+		return nil, errCBORTruncated
+	}
+	b := d.data[d.off : d.off+int(n)]
+	d.off += int(n)
+	return b, nil
+}
+
+// decode reads one item. Integers decode to int64, byte strings to []byte,
+// text to string, arrays to []any and maps to map[any]any.
+func (d *cborDecoder) decode(depth int) (any, error) {
+	if depth > maxCBORDepth {
+		return nil, errors.New("webauthn: cbor: nested too deeply")
+	}
+	major, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case 0:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("webauthn: cbor: integer overflow")
+		}
+		return int64(arg), nil
+	case 1:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("webauthn: cbor: integer overflow")
+		}
+		return -1 - int64(arg), nil
+	case 2:
+		return d.bytes(arg)
+	case 3:
+		b, err := d.bytes(arg)
+		return string(b), err
+	case 4:
+		// Every item takes at least a byte, which bounds honest lengths.
+		if arg > uint64(len(d.data)-d.off) {
+			return nil, errCBORTruncated
+		}
+		items := make([]any, 0, arg)
+		for range arg {
+			v, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case 5:
+		if arg > uint64(len(d.data)-d.off)/2 {
+			return nil, errCBORTruncated
+		}
+		m := make(map[any]any, arg)
+		for range arg {
+			k, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch k.(type) {
+			case int64, string:
+			default:
+				return nil, errors.New("webauthn: cbor: unsupported map key type")
+			}
+			v, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = v
+		}
+		return m, nil
+	case 7:
+		switch arg {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		}
+		return nil, fmt.Errorf("webauthn: cbor: unsupported simple value %d", arg)
+	default:
+		return nil, fmt.Errorf("webauthn: cbor: unsupported major type %d", major)
+	}
+}
@@ -0,0 +1,114 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE algorithm identifiers (RFC 9053) of the keys passkeys use.
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgRS256 = -257
+)
+
+// supportedAlgs lists the algorithms offered at registration, in order of
+// preference.
+var supportedAlgs = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+// COSE key parameters.
+const (
+	coseKty = 1
+	coseAlg = 3
+	coseCrv = -1 // EC2 and OKP
+	coseX   = -2 // EC2 and OKP
+	coseY   = -3 // EC2
+	coseN   = -1 // RSA
+	coseE   = -2 // RSA
+
+	ktyOKP = 1
+	ktyEC2 = 2
+	ktyRSA = 3
+
+	crvP256    = 1
+	crvEd25519 = 6
+)
+
+// publicKey is a credential public key decoded from its COSE form.
+type publicKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+// parseCOSEKey decodes a COSE_Key holding an ES256, EdDSA or RS256 public key.
+func parseCOSEKey(data []byte) (*publicKey, error) {
+	v, err := decodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[any]any)
+	if !ok {
+		return nil, errors.New("webauthn: public key is not a COSE key")
+	}
+	kty, _ := m[int64(coseKty)].(int64)
+	alg, _ := m[int64(coseAlg)].(int64)
+
+	switch {
+	case kty == ktyEC2 && alg == AlgES256:
+		crv, _ := m[int64(coseCrv)].(int64)
+		x, _ := m[int64(coseX)].([]byte)
+		y, _ := m[int64(coseY)].([]byte)
+		if crv != crvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("webauthn: invalid ES256 key")
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("webauthn: ES256 key is not on the curve")
+		}
+		return &publicKey{alg: alg, key: key}, nil
+
+	case kty == ktyOKP && alg == AlgEdDSA:
+		crv, _ := m[int64(coseCrv)].(int64)
+		x, _ := m[int64(coseX)].([]byte)
+		if crv != crvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("webauthn: invalid EdDSA key")
+		}
+		return &publicKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+
+	case kty == ktyRSA && alg == AlgRS256:
+		n, _ := m[int64(coseN)].([]byte)
+		e, _ := m[int64(coseE)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("webauthn: invalid RS256 key")
+		}
+		exp := new(big.Int).SetBytes(e)
+		return &publicKey{alg: alg, key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}}, nil
+	}
+	return nil, fmt.Errorf("webauthn: unsupported key type %d with algorithm %d", kty, alg)
+}
+
+// verify checks sig over message.
+func (k *publicKey) verify(message, sig []byte) error {
+	var ok bool
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(message)
+		ok = ecdsa.VerifyASN1(key, digest[:], sig)
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(key, message, sig)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(message)
+		ok = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil
+	}
+	if !ok {
+		return errors.New("webauthn: invalid signature")
+	}
+	return nil
+}
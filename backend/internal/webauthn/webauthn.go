@@ -0,0 +1,444 @@
+// Package webauthn implements the relying party side of WebAuthn (passkey)
+// registration and authentication ceremonies: it builds the options the
+// browser passes to navigator.credentials and verifies what comes back.
+//
+// Options and responses use the JSON forms of WebAuthn Level 3
+// (PublicKeyCredential.parseCreationOptionsFromJSON and toJSON), with binary
+// fields as unpadded base64url. Only "none" attestation is accepted: the
+// server trusts credentials to the account that registered them and does not
+// check authenticator makes.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CeremonyTimeout is how long the browser is told to wait for the user, and
+// how long servers should keep a challenge.
+const CeremonyTimeout = 5 * time.Minute
+
+// User verification requirements for RequestOptions.
+const (
+	UserVerificationRequired  = "required"
+	UserVerificationPreferred = "preferred"
+)
+
+// Authenticator data flags.
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagBackupElig   = 0x08
+	flagAttested     = 0x40
+	flagExtensions   = 0x80
+)
+
+// maxCredentialIDLength is the longest credential ID WebAuthn allows.
+const maxCredentialIDLength = 1023
+
+// ErrSignCount reports a signature counter that did not move forward, a sign
+// that the credential's private key was cloned.
+var ErrSignCount = errors.New("webauthn: signature counter did not increase")
+
+// RelyingParty is the site credentials are scoped to.
+type RelyingParty struct {
+	ID      string   // domain, e.g. "chat.example.com"
+	Name    string   // shown by the browser
+	Origins []string // origins the ceremonies may run on, e.g. "https://chat.example.com"
+}
+
+// User identifies the account a credential is registered for. ID is an
+// opaque handle the authenticator stores and returns on discoverable sign-in.
+type User struct {
+	ID          []byte
+	Name        string
+	DisplayName string
+}
+
+// CredentialDescriptor names an existing credential, to exclude it from
+// registration or allow it for sign-in.
+type CredentialDescriptor struct {
+	ID         []byte
+	Transports []string
+}
+
+// NewChallenge returns a random challenge for one ceremony.
+func NewChallenge() ([]byte, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// b64 is the base64url encoding WebAuthn JSON uses.
+var b64 = base64.RawURLEncoding
+
+// decodeB64 decodes base64url with or without padding.
+func decodeB64(s string) ([]byte, error) {
+	return b64.DecodeString(strings.TrimRight(s, "="))
+}
+
+type descriptorJSON struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+func descriptorsJSON(creds []CredentialDescriptor) []descriptorJSON {
+	out := make([]descriptorJSON, 0, len(creds))
+	for _, c := range creds {
+		out = append(out, descriptorJSON{Type: "public-key", ID: b64.EncodeToString(c.ID), Transports: c.Transports})
+	}
+	return out
+}
+
+// CreationOptions returns the PublicKeyCredentialCreationOptions JSON for
+// registering a passkey for user. Discoverable credentials and user
+// verification are required, so the passkey alone can sign the user in.
+func (rp *RelyingParty) CreationOptions(user User, challenge []byte, exclude []CredentialDescriptor) ([]byte, error) {
+	type param struct {
+		Type string `json:"type"`
+		Alg  int64  `json:"alg"`
+	}
+	params := make([]param, 0, len(supportedAlgs))
+	for _, alg := range supportedAlgs {
+		params = append(params, param{Type: "public-key", Alg: alg})
+	}
+	return json.Marshal(map[string]any{
+		"rp": map[string]string{"id": rp.ID, "name": rp.Name},
+		"user": map[string]string{
+			"id":          b64.EncodeToString(user.ID),
+			"name":        user.Name,
+			"displayName": user.DisplayName,
+		},
+		"challenge":          b64.EncodeToString(challenge),
+		"pubKeyCredParams":   params,
+		"timeout":            CeremonyTimeout.Milliseconds(),
+		"excludeCredentials": descriptorsJSON(exclude),
+		"authenticatorSelection": map[string]any{
+			"residentKey":        "required",
+			"requireResidentKey": true,
+			"userVerification":   UserVerificationRequired,
+		},
+		"attestation": "none",
+	})
+}
+
+// RequestOptions returns the PublicKeyCredentialRequestOptions JSON for
+// signing in. An empty allow list lets the user pick any passkey for the
+// site.
+func (rp *RelyingParty) RequestOptions(challenge []byte, allow []CredentialDescriptor, userVerification string) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"challenge":        b64.EncodeToString(challenge),
+		"rpId":             rp.ID,
+		"timeout":          CeremonyTimeout.Milliseconds(),
+		"allowCredentials": descriptorsJSON(allow),
+		"userVerification": userVerification,
+	})
+}
+
+// credentialJSON is PublicKeyCredential.toJSON() for either ceremony.
+type credentialJSON struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON"`
+		AttestationObject string   `json:"attestationObject"`
+		Transports        []string `json:"transports"`
+		AuthenticatorData string   `json:"authenticatorData"`
+		Signature         string   `json:"signature"`
+		UserHandle        string   `json:"userHandle"`
+	} `json:"response"`
+}
+
+// clientData is the part of CollectedClientData the server checks.
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// parseClientData decodes clientDataJSON and its challenge.
+func parseClientData(raw []byte) (*clientData, []byte, error) {
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return nil, nil, fmt.Errorf("webauthn: decode client data: %w", err)
+	}
+	challenge, err := decodeB64(cd.Challenge)
+	if err != nil || len(challenge) == 0 {
+		return nil, nil, errors.New("webauthn: invalid challenge in client data")
+	}
+	return &cd, challenge, nil
+}
+
+// checkClientData verifies the ceremony type, challenge and origin.
+func (rp *RelyingParty) checkClientData(cd *clientData, got []byte, wantType string, challenge []byte) error {
+	if cd.Type != wantType {
+		return fmt.Errorf("webauthn: client data type is %q, want %q", cd.Type, wantType)
+	}
+	if subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return errors.New("webauthn: challenge mismatch")
+	}
+	if !slices.Contains(rp.Origins, cd.Origin) {
+		return fmt.Errorf("webauthn: origin %q is not allowed", cd.Origin)
+	}
+	if cd.CrossOrigin {
+		return errors.New("webauthn: cross-origin ceremonies are not allowed")
+	}
+	return nil
+}
+
+// authenticatorData is the parsed authData of either ceremony.
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	credentialID []byte // registration only
+	publicKey    []byte // registration only; COSE
+}
+
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, errors.New("webauthn: authenticator data too short")
+	}
+	ad := &authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rest := data[37:]
+	if ad.flags&flagAttested != 0 {
+		if len(rest) < 18 {
+			return nil, errors.New("webauthn: attested credential data too short")
+		}
+		n := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if n == 0 || n > maxCredentialIDLength || len(rest) < n {
+			return nil, errors.New("webauthn: invalid credential ID length")
+		}
+		ad.credentialID, rest = rest[:n], rest[n:]
+		_, used, err := decodeCBORPrefix(rest)
+		if err != nil {
+			return nil, err
+		}
+		ad.publicKey, rest = rest[:used], rest[used:]
+	}
+	if ad.flags&flagExtensions != 0 {
+		_, used, err := decodeCBORPrefix(rest)
+		if err != nil {
+			return nil, err
+		}
+		rest = rest[used:]
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("webauthn: trailing authenticator data")
+	}
+	return ad, nil
+}
+
+// checkAuthenticatorData verifies the RP ID hash and the user presence and,
+// if required, verification flags.
+func (rp *RelyingParty) checkAuthenticatorData(ad *authenticatorData, requireUV bool) error {
+	want := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(ad.rpIDHash, want[:]) {
+		return errors.New("webauthn: RP ID mismatch")
+	}
+	if ad.flags&flagUserPresent == 0 {
+		return errors.New("webauthn: user not present")
+	}
+	if requireUV && ad.flags&flagUserVerified == 0 {
+		return errors.New("webauthn: user not verified")
+	}
+	return nil
+}
+
+// Registration is a parsed response to a registration ceremony, not yet
+// verified.
+type Registration struct {
+	clientDataJSON    []byte
+	clientData        *clientData
+	challenge         []byte
+	attestationObject []byte
+	transports        []string
+}
+
+// ParseRegistration decodes the JSON of a credential created with
+// CreationOptions.
+func ParseRegistration(data []byte) (*Registration, error) {
+	var c credentialJSON
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("webauthn: decode credential: %w", err)
+	}
+	if c.Type != "public-key" {
+		return nil, errors.New("webauthn: credential type is not public-key")
+	}
+	r := &Registration{transports: c.Response.Transports}
+	var err error
+	if r.clientDataJSON, err = decodeB64(c.Response.ClientDataJSON); err != nil {
+		return nil, errors.New("webauthn: invalid clientDataJSON encoding")
+	}
+	if r.attestationObject, err = decodeB64(c.Response.AttestationObject); err != nil {
+		return nil, errors.New("webauthn: invalid attestationObject encoding")
+	}
+	if r.clientData, r.challenge, err = parseClientData(r.clientDataJSON); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Challenge returns the challenge the browser signed, to look up the
+// ceremony it answers.
+func (r *Registration) Challenge() []byte { return r.challenge }
+
+// Credential is a verified new credential, to be stored for its user.
+type Credential struct {
+	ID             []byte
+	PublicKey      []byte // COSE_Key
+	SignCount      uint32
+	Transports     []string
+	BackupEligible bool // synced passkey rather than one bound to a device
+}
+
+// VerifyRegistration checks r against challenge, the one CreationOptions was
+// called with, and returns the new credential.
+func (rp *RelyingParty) VerifyRegistration(r *Registration, challenge []byte) (*Credential, error) {
+	if err := rp.checkClientData(r.clientData, r.challenge, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	v, err := decodeCBOR(r.attestationObject)
+	if err != nil {
+		return nil, err
+	}
+	att, ok := v.(map[any]any)
+	if !ok {
+		return nil, errors.New("webauthn: attestation object is not a map")
+	}
+	if format, _ := att["fmt"].(string); format != "none" {
+		return nil, fmt.Errorf("webauthn: unsupported attestation format %q", format)
+	}
+	authData, ok := att["authData"].([]byte)
+	if !ok {
+		return nil, errors.New("webauthn: attestation object has no authData")
+	}
+
+	ad, err := parseAuthenticatorData(authData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.checkAuthenticatorData(ad, true); err != nil {
+		return nil, err
+	}
+	if ad.credentialID == nil {
+		return nil, errors.New("webauthn: no attested credential data")
+	}
+	if _, err := parseCOSEKey(ad.publicKey); err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:             ad.credentialID,
+		PublicKey:      ad.publicKey,
+		SignCount:      ad.signCount,
+		Transports:     r.transports,
+		BackupEligible: ad.flags&flagBackupElig != 0,
+	}, nil
+}
+
+// Assertion is a parsed response to an authentication ceremony, not yet
+// verified.
+type Assertion struct {
+	CredentialID []byte
+	UserHandle   []byte // set for discoverable credentials
+
+	clientDataJSON    []byte
+	clientData        *clientData
+	challenge         []byte
+	authenticatorData []byte
+	signature         []byte
+}
+
+// ParseAssertion decodes the JSON of a credential returned for
+// RequestOptions.
+func ParseAssertion(data []byte) (*Assertion, error) {
+	var c credentialJSON
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("webauthn: decode credential: %w", err)
+	}
+	if c.Type != "public-key" {
+		return nil, errors.New("webauthn: credential type is not public-key")
+	}
+	a := &Assertion{}
+	var err error
+	if a.CredentialID, err = decodeB64(c.RawID); err != nil || len(a.CredentialID) == 0 {
+		return nil, errors.New("webauthn: invalid rawId")
+	}
+	if c.Response.UserHandle != "" {
+		if a.UserHandle, err = decodeB64(c.Response.UserHandle); err != nil {
+			return nil, errors.New("webauthn: invalid userHandle encoding")
+		}
+	}
+	if a.clientDataJSON, err = decodeB64(c.Response.ClientDataJSON); err != nil {
+		return nil, errors.New("webauthn: invalid clientDataJSON encoding")
+	}
+	if a.authenticatorData, err = decodeB64(c.Response.AuthenticatorData); err != nil {
+		return nil, errors.New("webauthn: invalid authenticatorData encoding")
+	}
+	if a.signature, err = decodeB64(c.Response.Signature); err != nil {
+		return nil, errors.New("webauthn: invalid signature encoding")
+	}
+	if a.clientData, a.challenge, err = parseClientData(a.clientDataJSON); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Challenge returns the challenge the authenticator signed, to look up the
+// ceremony it answers.
+func (a *Assertion) Challenge() []byte { return a.challenge }
+
+// VerifyAssertion checks a against challenge and the stored credential's
+// public key and signature counter, and returns the new counter to store.
+// With requireUV the authenticator must have verified the user, e.g. by PIN
+// or biometrics. A counter that did not increase fails with ErrSignCount;
+// authenticators that keep no counter always report zero and pass.
+func (rp *RelyingParty) VerifyAssertion(a *Assertion, challenge, publicKeyCOSE []byte, storedSignCount uint32, requireUV bool) (uint32, error) {
+	if err := rp.checkClientData(a.clientData, a.challenge, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+	ad, err := parseAuthenticatorData(a.authenticatorData)
+	if err != nil {
+		return 0, err
+	}
+	if err := rp.checkAuthenticatorData(ad, requireUV); err != nil {
+		return 0, err
+	}
+
+	key, err := parseCOSEKey(publicKeyCOSE)
+	if err != nil {
+		return 0, err
+	}
+	clientDataHash := sha256.Sum256(a.clientDataJSON)
+	signed := append(slices.Clip(a.authenticatorData), clientDataHash[:]...)
+	if err := key.verify(signed, a.signature); err != nil {
+		return 0, err
+	}
+
+	if (ad.signCount != 0 || storedSignCount != 0) && ad.signCount <= storedSignCount {
+		return 0, ErrSignCount
+	}
+	return ad.signCount, nil
+}
@@ -0,0 +1,209 @@
+package webauthn_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/zukigit/chat/backend/internal/webauthn"
+	"github.com/zukigit/chat/backend/internal/webauthn/webauthntest"
+)
+
+const origin = "https://chat.example.com"
+
+var rp = &webauthn.RelyingParty{ID: "chat.example.com", Name: "Chat", Origins: []string{origin}}
+
+// register runs a registration ceremony with auth and returns the verified
+// credential.
+func register(t *testing.T, auth *webauthntest.Authenticator) *webauthn.Credential {
+	t.Helper()
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		t.Fatalf("NewChallenge: %v", err)
+	}
+	opts, err := rp.CreationOptions(webauthn.User{ID: []byte("user-1"), Name: "alice", DisplayName: "alice"}, challenge, nil)
+	if err != nil {
+		t.Fatalf("CreationOptions: %v", err)
+	}
+	resp, err := auth.Register(opts)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	r, err := webauthn.ParseRegistration(resp)
+	if err != nil {
+		t.Fatalf("ParseRegistration: %v", err)
+	}
+	if !bytes.Equal(r.Challenge(), challenge) {
+		t.Fatalf("Challenge: got %x, want %x", r.Challenge(), challenge)
+	}
+	cred, err := rp.VerifyRegistration(r, challenge)
+	if err != nil {
+		t.Fatalf("VerifyRegistration: %v", err)
+	}
+	return cred
+}
+
+// assert runs an authentication ceremony with auth and returns the parsed
+// assertion and its challenge.
+func assert(t *testing.T, auth *webauthntest.Authenticator, allow []webauthn.CredentialDescriptor) (*webauthn.Assertion, []byte) {
+	t.Helper()
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		t.Fatalf("NewChallenge: %v", err)
+	}
+	opts, err := rp.RequestOptions(challenge, allow, webauthn.UserVerificationRequired)
+	if err != nil {
+		t.Fatalf("RequestOptions: %v", err)
+	}
+	resp, err := auth.Login(opts)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	a, err := webauthn.ParseAssertion(resp)
+	if err != nil {
+		t.Fatalf("ParseAssertion: %v", err)
+	}
+	return a, challenge
+}
+
+func TestRegisterAndAssert(t *testing.T) {
+	auth := webauthntest.New(origin)
+	cred := register(t, auth)
+	if len(cred.ID) == 0 || len(cred.PublicKey) == 0 {
+		t.Fatalf("credential missing ID or key: %+v", cred)
+	}
+	if len(cred.Transports) != 1 || cred.Transports[0] != "internal" {
+		t.Errorf("Transports: got %v", cred.Transports)
+	}
+
+	a, challenge := assert(t, auth, nil)
+	if !bytes.Equal(a.CredentialID, cred.ID) {
+		t.Errorf("CredentialID: got %x, want %x", a.CredentialID, cred.ID)
+	}
+	if string(a.UserHandle) != "user-1" {
+		t.Errorf("UserHandle: got %q, want user-1", a.UserHandle)
+	}
+	count, err := rp.VerifyAssertion(a, challenge, cred.PublicKey, cred.SignCount, true)
+	if err != nil {
+		t.Fatalf("VerifyAssertion: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("sign count: got %d, want 1", count)
+	}
+
+	// A second sign-in moves the counter on.
+	a, challenge = assert(t, auth, []webauthn.CredentialDescriptor{{ID: cred.ID}})
+	if count, err = rp.VerifyAssertion(a, challenge, cred.PublicKey, count, true); err != nil || count != 2 {
+		t.Fatalf("second VerifyAssertion: count %d, err %v", count, err)
+	}
+}
+
+func TestVerifyAssertion_Rejects(t *testing.T) {
+	auth := webauthntest.New(origin)
+	cred := register(t, auth)
+	other := register(t, webauthntest.New(origin))
+
+	t.Run("wrong challenge", func(t *testing.T) {
+		a, _ := assert(t, auth, nil)
+		wrong, _ := webauthn.NewChallenge()
+		if _, err := rp.VerifyAssertion(a, wrong, cred.PublicKey, 0, true); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		a, challenge := assert(t, auth, nil)
+		if _, err := rp.VerifyAssertion(a, challenge, other.PublicKey, 0, true); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("wrong origin", func(t *testing.T) {
+		evil := *auth
+		evil.Origin = "https://evil.example.com"
+		a, challenge := assert(t, &evil, nil)
+		if _, err := rp.VerifyAssertion(a, challenge, cred.PublicKey, 0, true); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("wrong RP ID", func(t *testing.T) {
+		a, challenge := assert(t, auth, nil)
+		otherRP := &webauthn.RelyingParty{ID: "example.com", Origins: rp.Origins}
+		if _, err := otherRP.VerifyAssertion(a, challenge, cred.PublicKey, 0, true); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("user not verified", func(t *testing.T) {
+		auth.UserVerified = false
+		defer func() { auth.UserVerified = true }()
+		a, challenge := assert(t, auth, nil)
+		if _, err := rp.VerifyAssertion(a, challenge, cred.PublicKey, 0, true); err == nil {
+			t.Error("expected an error with user verification required")
+		}
+	})
+
+	t.Run("sign count replay", func(t *testing.T) {
+		auth.SetSignCount(0)
+		a, challenge := assert(t, auth, nil)
+		if _, err := rp.VerifyAssertion(a, challenge, cred.PublicKey, 5, true); !errors.Is(err, webauthn.ErrSignCount) {
+			t.Errorf("got %v, want ErrSignCount", err)
+		}
+	})
+}
+
+func TestVerifyRegistration_Rejects(t *testing.T) {
+	user := webauthn.User{ID: []byte("user-1"), Name: "alice"}
+
+	t.Run("wrong challenge", func(t *testing.T) {
+		challenge, _ := webauthn.NewChallenge()
+		opts, _ := rp.CreationOptions(user, challenge, nil)
+		resp, err := webauthntest.New(origin).Register(opts)
+		if err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+		r, err := webauthn.ParseRegistration(resp)
+		if err != nil {
+			t.Fatalf("ParseRegistration: %v", err)
+		}
+		wrong, _ := webauthn.NewChallenge()
+		if _, err := rp.VerifyRegistration(r, wrong); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("user not verified", func(t *testing.T) {
+		auth := webauthntest.New(origin)
+		auth.UserVerified = false
+		challenge, _ := webauthn.NewChallenge()
+		opts, _ := rp.CreationOptions(user, challenge, nil)
+		resp, err := auth.Register(opts)
+		if err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+		r, err := webauthn.ParseRegistration(resp)
+		if err != nil {
+			t.Fatalf("ParseRegistration: %v", err)
+		}
+		if _, err := rp.VerifyRegistration(r, challenge); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("excluded authenticator", func(t *testing.T) {
+		auth := webauthntest.New(origin)
+		cred := register(t, auth)
+		challenge, _ := webauthn.NewChallenge()
+		opts, _ := rp.CreationOptions(user, challenge, []webauthn.CredentialDescriptor{{ID: cred.ID}})
+		if _, err := auth.Register(opts); err == nil {
+			t.Error("authenticator registered twice")
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		if _, err := webauthn.ParseRegistration([]byte(`{"type":"public-key","response":{"clientDataJSON":"!!"}}`)); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
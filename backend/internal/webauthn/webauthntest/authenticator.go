@@ -0,0 +1,254 @@
+// Package webauthntest provides a software passkey authenticator, standing in
+// for the browser and security key in tests of the webauthn ceremonies.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var b64 = base64.RawURLEncoding
+
+// Authenticator holds ES256 passkeys and answers ceremonies as a browser on
+// Origin would.
+type Authenticator struct {
+	Origin string
+	// UserVerified sets the user verification flag in responses, as when the
+	// user entered a PIN. New sets it.
+	UserVerified bool
+
+	creds []*credential
+}
+
+type credential struct {
+	id         []byte
+	rpID       string
+	userHandle []byte
+	key        *ecdsa.PrivateKey
+	signCount  uint32
+}
+
+// New returns an authenticator with no passkeys that answers for origin.
+func New(origin string) *Authenticator {
+	return &Authenticator{Origin: origin, UserVerified: true}
+}
+
+type descriptor struct {
+	ID string `json:"id"`
+}
+
+// Register creates a passkey for the PublicKeyCredentialCreationOptions JSON
+// in options and returns the registration response JSON.
+func (a *Authenticator) Register(options []byte) ([]byte, error) {
+	var opts struct {
+		RP struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+		Challenge          string       `json:"challenge"`
+		ExcludeCredentials []descriptor `json:"excludeCredentials"`
+	}
+	if err := json.Unmarshal(options, &opts); err != nil {
+		return nil, err
+	}
+	for _, d := range opts.ExcludeCredentials {
+		if a.find(opts.RP.ID, d.ID) != nil {
+			return nil, errors.New("webauthntest: authenticator already registered")
+		}
+	}
+	userHandle, err := b64.DecodeString(opts.User.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	c := &credential{id: make([]byte, 16), rpID: opts.RP.ID, userHandle: userHandle, key: key}
+	if _, err := rand.Read(c.id); err != nil {
+		return nil, err
+	}
+	a.creds = append(a.creds, c)
+
+	authData := a.authData(c.rpID, 0x40, 0)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(c.id)))
+	authData = append(authData, c.id...)
+	authData = append(authData, coseKey(&key.PublicKey)...)
+
+	attestation := encodeMap([]any{
+		"fmt", "none",
+		"attStmt", rawCBOR{0xa0},
+		"authData", authData,
+	})
+	return json.Marshal(map[string]any{
+		"id":    b64.EncodeToString(c.id),
+		"rawId": b64.EncodeToString(c.id),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    b64.EncodeToString(a.clientData("webauthn.create", opts.Challenge)),
+			"attestationObject": b64.EncodeToString(attestation),
+			"transports":        []string{"internal"},
+		},
+		"authenticatorAttachment": "platform",
+		"clientExtensionResults":  map[string]any{},
+	})
+}
+
+// Login signs the PublicKeyCredentialRequestOptions JSON in options with the
+// first passkey it allows, or the first passkey for the RP if it allows any,
+// and returns the authentication response JSON.
+func (a *Authenticator) Login(options []byte) ([]byte, error) {
+	var opts struct {
+		RPID             string       `json:"rpId"`
+		Challenge        string       `json:"challenge"`
+		AllowCredentials []descriptor `json:"allowCredentials"`
+	}
+	if err := json.Unmarshal(options, &opts); err != nil {
+		return nil, err
+	}
+
+	var c *credential
+	if len(opts.AllowCredentials) == 0 {
+		c = a.find(opts.RPID, "")
+	}
+	for _, d := range opts.AllowCredentials {
+		if c = a.find(opts.RPID, d.ID); c != nil {
+			break
+		}
+	}
+	if c == nil {
+		return nil, errors.New("webauthntest: no matching passkey")
+	}
+
+	c.signCount++
+	authData := a.authData(c.rpID, 0, c.signCount)
+	clientData := a.clientData("webauthn.get", opts.Challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(slices.Clip(authData), clientDataHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, c.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]any{
+		"id":    b64.EncodeToString(c.id),
+		"rawId": b64.EncodeToString(c.id),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    b64.EncodeToString(clientData),
+			"authenticatorData": b64.EncodeToString(authData),
+			"signature":         b64.EncodeToString(sig),
+			"userHandle":        b64.EncodeToString(c.userHandle),
+		},
+		"authenticatorAttachment": "platform",
+		"clientExtensionResults":  map[string]any{},
+	})
+}
+
+// SetSignCount sets the signature counter of every passkey, e.g. back to an
+// earlier value to imitate a cloned key.
+func (a *Authenticator) SetSignCount(n uint32) {
+	for _, c := range a.creds {
+		c.signCount = n
+	}
+}
+
+// find returns the passkey for rpID with the base64url ID id, or the first
+// passkey for rpID if id is empty.
+func (a *Authenticator) find(rpID, id string) *credential {
+	for _, c := range a.creds {
+		if c.rpID == rpID && (id == "" || b64.EncodeToString(c.id) == id) {
+			return c
+		}
+	}
+	return nil
+}
+
+// authData returns the fixed part of the authenticator data, with extra
+// flags on top of user presence and, if set, verification.
+func (a *Authenticator) authData(rpID string, flags byte, signCount uint32) []byte {
+	flags |= 0x01
+	if a.UserVerified {
+		flags |= 0x04
+	}
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, signCount)
+}
+
+func (a *Authenticator) clientData(typ, challenge string) []byte {
+	data, _ := json.Marshal(map[string]any{
+		"type":        typ,
+		"challenge":   challenge,
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+	return data
+}
+
+// coseKey encodes an ES256 public key as a COSE_Key.
+func coseKey(pub *ecdsa.PublicKey) []byte {
+	x, y := make([]byte, 32), make([]byte, 32)
+	pub.X.FillBytes(x)
+	pub.Y.FillBytes(y)
+	return encodeMap([]any{
+		int64(1), int64(2), // kty: EC2
+		int64(3), int64(-7), // alg: ES256
+		int64(-1), int64(1), // crv: P-256
+		int64(-2), x,
+		int64(-3), y,
+	})
+}
+
+// rawCBOR is already encoded CBOR.
+type rawCBOR []byte
+
+// encodeMap encodes alternating keys and values as a CBOR map, in the given
+// order. Keys and values are int64, string, []byte or rawCBOR.
+func encodeMap(kv []any) []byte {
+	out := cborHead(5, uint64(len(kv)/2))
+	for _, v := range kv {
+		switch v := v.(type) {
+		case int64:
+			if v >= 0 {
+				out = append(out, cborHead(0, uint64(v))...)
+			} else {
+				out = append(out, cborHead(1, uint64(-1-v))...)
+			}
+		case string:
+			out = append(append(out, cborHead(3, uint64(len(v)))...), v...)
+		case []byte:
+			out = append(append(out, cborHead(2, uint64(len(v)))...), v...)
+		case rawCBOR:
+			out = append(out, v...)
+		default:
+			panic(fmt.Sprintf("webauthntest: cannot encode %T", v))
+		}
+	}
+	return out
+}
+
+func cborHead(major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return []byte{major<<5 | byte(arg)}
+	case arg <= 0xff:
+		return []byte{major<<5 | 24, byte(arg)}
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(arg))
+	default:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(arg))
+	}
+}
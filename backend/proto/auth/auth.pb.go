@@ -25,6 +25,7 @@ type LoginRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserName      string                 `protobuf:"bytes,1,opt,name=userName,proto3" json:"userName,omitempty"`
 	Passwd        string                 `protobuf:"bytes,2,opt,name=passwd,proto3" json:"passwd,omitempty"`
+	DeviceName    string                 `protobuf:"bytes,3,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"` // optional, up to 100 characters; shown in ListSessions
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,11 +74,23 @@ func (x *LoginRequest) GetPasswd() string {
 	return ""
 }
 
+func (x *LoginRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
 type LoginResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Token        string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"` // single-use; trade it for a new token pair at RefreshToken
+	// Set instead of the tokens when the user has two-factor sign-in on: pass
+	// challenge_token and a code to VerifySecondFactor to finish signing in.
+	SecondFactorRequired bool   `protobuf:"varint,3,opt,name=second_factor_required,json=secondFactorRequired,proto3" json:"second_factor_required,omitempty"`
+	ChallengeToken       string `protobuf:"bytes,4,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *LoginResponse) Reset() {
@@ -117,10 +130,32 @@ func (x *LoginResponse) GetToken() string {
 	return ""
 }
 
+func (x *LoginResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetSecondFactorRequired() bool {
+	if x != nil {
+		return x.SecondFactorRequired
+	}
+	return false
+}
+
+func (x *LoginResponse) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
 type SignupRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserName      string                 `protobuf:"bytes,1,opt,name=userName,proto3" json:"userName,omitempty"`
 	Passwd        string                 `protobuf:"bytes,2,opt,name=passwd,proto3" json:"passwd,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"` // optional recovery email for password resets
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -169,6 +204,13 @@ func (x *SignupRequest) GetPasswd() string {
 	return ""
 }
 
+func (x *SignupRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
 type SignupResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -208,6 +250,8 @@ func (*SignupResponse) Descriptor() ([]byte, []int) {
 type SearchUsersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`  // default 20, max 50
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor from the previous page; empty for the first page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -249,6 +293,20 @@ func (x *SearchUsersRequest) GetQuery() string {
 	return ""
 }
 
+func (x *SearchUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchUsersRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
 type UserResult struct {
 	state                     protoimpl.MessageState `protogen:"open.v1"`
 	UserId                    string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -257,6 +315,8 @@ type UserResult struct {
 	AvatarUrl                 string                 `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
 	FriendshipStatus          string                 `protobuf:"bytes,5,opt,name=friendship_status,json=friendshipStatus,proto3" json:"friendship_status,omitempty"`
 	FriendshipInitiatorUserid string                 `protobuf:"bytes,6,opt,name=friendship_initiator_userid,json=friendshipInitiatorUserid,proto3" json:"friendship_initiator_userid,omitempty"`
+	MutualFriendCount         int32                  `protobuf:"varint,7,opt,name=mutual_friend_count,json=mutualFriendCount,proto3" json:"mutual_friend_count,omitempty"`
+	MutualFriends             []string               `protobuf:"bytes,8,rep,name=mutual_friends,json=mutualFriends,proto3" json:"mutual_friends,omitempty"` // usernames of up to 3 mutual friends
 	unknownFields             protoimpl.UnknownFields
 	sizeCache                 protoimpl.SizeCache
 }
@@ -333,9 +393,24 @@ func (x *UserResult) GetFriendshipInitiatorUserid() string {
 	return ""
 }
 
+func (x *UserResult) GetMutualFriendCount() int32 {
+	if x != nil {
+		return x.MutualFriendCount
+	}
+	return 0
+}
+
+func (x *UserResult) GetMutualFriends() []string {
+	if x != nil {
+		return x.MutualFriends
+	}
+	return nil
+}
+
 type SearchUsersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Users         []*UserResult          `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // empty when there are no more results
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -377,26 +452,34 @@ func (x *SearchUsersResponse) GetUsers() []*UserResult {
 	return nil
 }
 
-type GetGithubOAuthURLRequest struct {
+func (x *SearchUsersResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type GetOAuthURLRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"` // configured provider name, e.g. "github" or "google"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetGithubOAuthURLRequest) Reset() {
-	*x = GetGithubOAuthURLRequest{}
+func (x *GetOAuthURLRequest) Reset() {
+	*x = GetOAuthURLRequest{}
 	mi := &file_proto_auth_auth_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetGithubOAuthURLRequest) String() string {
+func (x *GetOAuthURLRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetGithubOAuthURLRequest) ProtoMessage() {}
+func (*GetOAuthURLRequest) ProtoMessage() {}
 
-func (x *GetGithubOAuthURLRequest) ProtoReflect() protoreflect.Message {
+func (x *GetOAuthURLRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_auth_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -408,32 +491,39 @@ func (x *GetGithubOAuthURLRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetGithubOAuthURLRequest.ProtoReflect.Descriptor instead.
-func (*GetGithubOAuthURLRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetOAuthURLRequest.ProtoReflect.Descriptor instead.
+func (*GetOAuthURLRequest) Descriptor() ([]byte, []int) {
 	return file_proto_auth_auth_proto_rawDescGZIP(), []int{7}
 }
 
-type GetGithubOAuthURLResponse struct {
+func (x *GetOAuthURLRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+type GetOAuthURLResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetGithubOAuthURLResponse) Reset() {
-	*x = GetGithubOAuthURLResponse{}
+func (x *GetOAuthURLResponse) Reset() {
+	*x = GetOAuthURLResponse{}
 	mi := &file_proto_auth_auth_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetGithubOAuthURLResponse) String() string {
+func (x *GetOAuthURLResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetGithubOAuthURLResponse) ProtoMessage() {}
+func (*GetOAuthURLResponse) ProtoMessage() {}
 
-func (x *GetGithubOAuthURLResponse) ProtoReflect() protoreflect.Message {
+func (x *GetOAuthURLResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_auth_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -445,39 +535,41 @@ func (x *GetGithubOAuthURLResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetGithubOAuthURLResponse.ProtoReflect.Descriptor instead.
-func (*GetGithubOAuthURLResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetOAuthURLResponse.ProtoReflect.Descriptor instead.
+func (*GetOAuthURLResponse) Descriptor() ([]byte, []int) {
 	return file_proto_auth_auth_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *GetGithubOAuthURLResponse) GetUrl() string {
+func (x *GetOAuthURLResponse) GetUrl() string {
 	if x != nil {
 		return x.Url
 	}
 	return ""
 }
 
-type GithubOAuthCallbackRequest struct {
+type OAuthCallbackRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"` // as returned by the provider, from GetOAuthURL's url
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GithubOAuthCallbackRequest) Reset() {
-	*x = GithubOAuthCallbackRequest{}
+func (x *OAuthCallbackRequest) Reset() {
+	*x = OAuthCallbackRequest{}
 	mi := &file_proto_auth_auth_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GithubOAuthCallbackRequest) String() string {
+func (x *OAuthCallbackRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GithubOAuthCallbackRequest) ProtoMessage() {}
+func (*OAuthCallbackRequest) ProtoMessage() {}
 
-func (x *GithubOAuthCallbackRequest) ProtoReflect() protoreflect.Message {
+func (x *OAuthCallbackRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_auth_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -489,19 +581,33 @@ func (x *GithubOAuthCallbackRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GithubOAuthCallbackRequest.ProtoReflect.Descriptor instead.
-func (*GithubOAuthCallbackRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use OAuthCallbackRequest.ProtoReflect.Descriptor instead.
+func (*OAuthCallbackRequest) Descriptor() ([]byte, []int) {
 	return file_proto_auth_auth_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *GithubOAuthCallbackRequest) GetCode() string {
+func (x *OAuthCallbackRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *OAuthCallbackRequest) GetCode() string {
 	if x != nil {
 		return x.Code
 	}
 	return ""
 }
 
-type GithubOAuthCallbackResponse struct {
+func (x *OAuthCallbackRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type OAuthCallbackResponse struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	ShortLivedToken string                 `protobuf:"bytes,1,opt,name=shortLivedToken,proto3" json:"shortLivedToken,omitempty"`
 	Username        string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
@@ -509,20 +615,20 @@ type GithubOAuthCallbackResponse struct {
 	sizeCache       protoimpl.SizeCache
 }
 
-func (x *GithubOAuthCallbackResponse) Reset() {
-	*x = GithubOAuthCallbackResponse{}
+func (x *OAuthCallbackResponse) Reset() {
+	*x = OAuthCallbackResponse{}
 	mi := &file_proto_auth_auth_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GithubOAuthCallbackResponse) String() string {
+func (x *OAuthCallbackResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GithubOAuthCallbackResponse) ProtoMessage() {}
+func (*OAuthCallbackResponse) ProtoMessage() {}
 
-func (x *GithubOAuthCallbackResponse) ProtoReflect() protoreflect.Message {
+func (x *OAuthCallbackResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_auth_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -534,19 +640,19 @@ func (x *GithubOAuthCallbackResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GithubOAuthCallbackResponse.ProtoReflect.Descriptor instead.
-func (*GithubOAuthCallbackResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use OAuthCallbackResponse.ProtoReflect.Descriptor instead.
+func (*OAuthCallbackResponse) Descriptor() ([]byte, []int) {
 	return file_proto_auth_auth_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *GithubOAuthCallbackResponse) GetShortLivedToken() string {
+func (x *OAuthCallbackResponse) GetShortLivedToken() string {
 	if x != nil {
 		return x.ShortLivedToken
 	}
 	return ""
 }
 
-func (x *GithubOAuthCallbackResponse) GetUsername() string {
+func (x *OAuthCallbackResponse) GetUsername() string {
 	if x != nil {
 		return x.Username
 	}
@@ -554,7 +660,9 @@ func (x *GithubOAuthCallbackResponse) GetUsername() string {
 }
 
 type ExchangeTokenRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Token arrives via gRPC metadata (Authorization: Bearer <short_lived_token>).
+	DeviceName    string `protobuf:"bytes,1,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"` // optional, as in LoginRequest
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -589,12 +697,22 @@ func (*ExchangeTokenRequest) Descriptor() ([]byte, []int) {
 	return file_proto_auth_auth_proto_rawDescGZIP(), []int{11}
 }
 
+func (x *ExchangeTokenRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
 type ExchangeTokenResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // long-lived JWT (24h)
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Token                string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // long-lived JWT (24h)
+	Username             string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	RefreshToken         string                 `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	SecondFactorRequired bool                   `protobuf:"varint,4,opt,name=second_factor_required,json=secondFactorRequired,proto3" json:"second_factor_required,omitempty"` // as in LoginResponse
+	ChallengeToken       string                 `protobuf:"bytes,5,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *ExchangeTokenResponse) Reset() {
@@ -641,28 +759,51 @@ func (x *ExchangeTokenResponse) GetUsername() string {
 	return ""
 }
 
-type SetupKeysRequest struct {
-	state               protoimpl.MessageState `protogen:"open.v1"`
-	PublicKey           string                 `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`                                 // age X25519 public key (age1...)
-	EncryptedPrivateKey string                 `protobuf:"bytes,2,opt,name=encrypted_private_key,json=encryptedPrivateKey,proto3" json:"encrypted_private_key,omitempty"` // PIN-encrypted private key (base64)
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+func (x *ExchangeTokenResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
 }
 
-func (x *SetupKeysRequest) Reset() {
-	*x = SetupKeysRequest{}
+func (x *ExchangeTokenResponse) GetSecondFactorRequired() bool {
+	if x != nil {
+		return x.SecondFactorRequired
+	}
+	return false
+}
+
+func (x *ExchangeTokenResponse) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+// RefreshTokenRequest trades a refresh token for a new access token and a new
+// refresh token. Each refresh token works once; presenting a used one revokes
+// every refresh token of that login.
+type RefreshTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
 	mi := &file_proto_auth_auth_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetupKeysRequest) String() string {
+func (x *RefreshTokenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetupKeysRequest) ProtoMessage() {}
+func (*RefreshTokenRequest) ProtoMessage() {}
 
-func (x *SetupKeysRequest) ProtoReflect() protoreflect.Message {
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_auth_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -674,46 +815,41 @@ func (x *SetupKeysRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetupKeysRequest.ProtoReflect.Descriptor instead.
-func (*SetupKeysRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
 	return file_proto_auth_auth_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *SetupKeysRequest) GetPublicKey() string {
-	if x != nil {
-		return x.PublicKey
-	}
-	return ""
-}
-
-func (x *SetupKeysRequest) GetEncryptedPrivateKey() string {
+func (x *RefreshTokenRequest) GetRefreshToken() string {
 	if x != nil {
-		return x.EncryptedPrivateKey
+		return x.RefreshToken
 	}
 	return ""
 }
 
-type SetupKeysResponse struct {
+type RefreshTokenResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IsE2EeReady   bool                   `protobuf:"varint,1,opt,name=is_e2ee_ready,json=isE2eeReady,proto3" json:"is_e2ee_ready,omitempty"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`                                   // access token for the same login_id
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"` // replaces the one that was presented
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetupKeysResponse) Reset() {
-	*x = SetupKeysResponse{}
+func (x *RefreshTokenResponse) Reset() {
+	*x = RefreshTokenResponse{}
 	mi := &file_proto_auth_auth_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetupKeysResponse) String() string {
+func (x *RefreshTokenResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetupKeysResponse) ProtoMessage() {}
+func (*RefreshTokenResponse) ProtoMessage() {}
 
-func (x *SetupKeysResponse) ProtoReflect() protoreflect.Message {
+func (x *RefreshTokenResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_auth_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -725,38 +861,53 @@ func (x *SetupKeysResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetupKeysResponse.ProtoReflect.Descriptor instead.
-func (*SetupKeysResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RefreshTokenResponse.ProtoReflect.Descriptor instead.
+func (*RefreshTokenResponse) Descriptor() ([]byte, []int) {
 	return file_proto_auth_auth_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *SetupKeysResponse) GetIsE2EeReady() bool {
+func (x *RefreshTokenResponse) GetToken() string {
 	if x != nil {
-		return x.IsE2EeReady
+		return x.Token
 	}
-	return false
+	return ""
 }
 
-type GetMyKeysRequest struct {
+func (x *RefreshTokenResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *RefreshTokenResponse) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+// LogoutRequest signs out the login of the caller's token.
+type LogoutRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMyKeysRequest) Reset() {
-	*x = GetMyKeysRequest{}
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
 	mi := &file_proto_auth_auth_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMyKeysRequest) String() string {
+func (x *LogoutRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMyKeysRequest) ProtoMessage() {}
+func (*LogoutRequest) ProtoMessage() {}
 
-func (x *GetMyKeysRequest) ProtoReflect() protoreflect.Message {
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_auth_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -768,34 +919,31 @@ func (x *GetMyKeysRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMyKeysRequest.ProtoReflect.Descriptor instead.
-func (*GetMyKeysRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
 	return file_proto_auth_auth_proto_rawDescGZIP(), []int{15}
 }
 
-type GetMyKeysResponse struct {
-	state               protoimpl.MessageState `protogen:"open.v1"`
-	EncryptedPrivateKey string                 `protobuf:"bytes,1,opt,name=encrypted_private_key,json=encryptedPrivateKey,proto3" json:"encrypted_private_key,omitempty"` // base64-encoded PIN-encrypted private key
-	PublicKey           string                 `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`                                 // age X25519 public key
-	IsE2EeReady         bool                   `protobuf:"varint,3,opt,name=is_e2ee_ready,json=isE2eeReady,proto3" json:"is_e2ee_ready,omitempty"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+type LogoutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMyKeysResponse) Reset() {
-	*x = GetMyKeysResponse{}
+func (x *LogoutResponse) Reset() {
+	*x = LogoutResponse{}
 	mi := &file_proto_auth_auth_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMyKeysResponse) String() string {
+func (x *LogoutResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMyKeysResponse) ProtoMessage() {}
+func (*LogoutResponse) ProtoMessage() {}
 
-func (x *GetMyKeysResponse) ProtoReflect() protoreflect.Message {
+func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_auth_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -807,53 +955,40 @@ func (x *GetMyKeysResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMyKeysResponse.ProtoReflect.Descriptor instead.
-func (*GetMyKeysResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use LogoutResponse.ProtoReflect.Descriptor instead.
+func (*LogoutResponse) Descriptor() ([]byte, []int) {
 	return file_proto_auth_auth_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *GetMyKeysResponse) GetEncryptedPrivateKey() string {
-	if x != nil {
-		return x.EncryptedPrivateKey
-	}
-	return ""
-}
-
-func (x *GetMyKeysResponse) GetPublicKey() string {
-	if x != nil {
-		return x.PublicKey
-	}
-	return ""
-}
-
-func (x *GetMyKeysResponse) GetIsE2EeReady() bool {
-	if x != nil {
-		return x.IsE2EeReady
-	}
-	return false
-}
-
-type GetPublicKeysRequest struct {
+// LoginSession is one place the user is signed in. user_agent and ip_address
+// are those seen when the login started.
+type LoginSession struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserIds       []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"` // UUIDs of users whose public keys to fetch
+	LoginId       string                 `protobuf:"bytes,1,opt,name=login_id,json=loginId,proto3" json:"login_id,omitempty"`
+	DeviceName    string                 `protobuf:"bytes,2,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,3,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	IpAddress     string                 `protobuf:"bytes,4,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`            // RFC 3339
+	LastActiveAt  string                 `protobuf:"bytes,6,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"` // RFC 3339
+	Current       bool                   `protobuf:"varint,7,opt,name=current,proto3" json:"current,omitempty"`                                // the login of the caller's token
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPublicKeysRequest) Reset() {
-	*x = GetPublicKeysRequest{}
+func (x *LoginSession) Reset() {
+	*x = LoginSession{}
 	mi := &file_proto_auth_auth_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPublicKeysRequest) String() string {
+func (x *LoginSession) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPublicKeysRequest) ProtoMessage() {}
+func (*LoginSession) ProtoMessage() {}
 
-func (x *GetPublicKeysRequest) ProtoReflect() protoreflect.Message {
+func (x *LoginSession) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_auth_auth_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -865,92 +1000,1993 @@ func (x *GetPublicKeysRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPublicKeysRequest.ProtoReflect.Descriptor instead.
-func (*GetPublicKeysRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use LoginSession.ProtoReflect.Descriptor instead.
+func (*LoginSession) Descriptor() ([]byte, []int) {
 	return file_proto_auth_auth_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *GetPublicKeysRequest) GetUserIds() []string {
+func (x *LoginSession) GetLoginId() string {
 	if x != nil {
-		return x.UserIds
+		return x.LoginId
 	}
-	return nil
-}
-
-type PublicKeyEntry struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	PublicKey     string                 `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"` // age X25519 public key
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	return ""
 }
 
-func (x *PublicKeyEntry) Reset() {
-	*x = PublicKeyEntry{}
-	mi := &file_proto_auth_auth_proto_msgTypes[18]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *LoginSession) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
 }
 
-func (x *PublicKeyEntry) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *LoginSession) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
 }
 
-func (*PublicKeyEntry) ProtoMessage() {}
-
-func (x *PublicKeyEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_auth_auth_proto_msgTypes[18]
+func (x *LoginSession) GetIpAddress() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.IpAddress
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use PublicKeyEntry.ProtoReflect.Descriptor instead.
-func (*PublicKeyEntry) Descriptor() ([]byte, []int) {
-	return file_proto_auth_auth_proto_rawDescGZIP(), []int{18}
+func (x *LoginSession) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
 }
 
-func (x *PublicKeyEntry) GetUserId() string {
+func (x *LoginSession) GetLastActiveAt() string {
 	if x != nil {
-		return x.UserId
+		return x.LastActiveAt
 	}
 	return ""
 }
 
-func (x *PublicKeyEntry) GetPublicKey() string {
+func (x *LoginSession) GetCurrent() bool {
 	if x != nil {
-		return x.PublicKey
+		return x.Current
 	}
-	return ""
+	return false
 }
 
-type GetPublicKeysResponse struct {
+type ListSessionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Keys          []*PublicKeyEntry      `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPublicKeysResponse) Reset() {
-	*x = GetPublicKeysResponse{}
-	mi := &file_proto_auth_auth_proto_msgTypes[19]
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPublicKeysResponse) String() string {
+func (x *ListSessionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPublicKeysResponse) ProtoMessage() {}
+func (*ListSessionsRequest) ProtoMessage() {}
 
-func (x *GetPublicKeysResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_auth_auth_proto_msgTypes[19]
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{18}
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*LoginSession        `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"` // most recently active first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*LoginSession {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// RevokeSessionRequest signs out one of the caller's logins, or with
+// all_others every login except the caller's own.
+type RevokeSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoginId       string                 `protobuf:"bytes,1,opt,name=login_id,json=loginId,proto3" json:"login_id,omitempty"` // required unless all_others is set
+	AllOthers     bool                   `protobuf:"varint,2,opt,name=all_others,json=allOthers,proto3" json:"all_others,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionRequest) Reset() {
+	*x = RevokeSessionRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionRequest) ProtoMessage() {}
+
+func (x *RevokeSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RevokeSessionRequest) GetLoginId() string {
+	if x != nil {
+		return x.LoginId
+	}
+	return ""
+}
+
+func (x *RevokeSessionRequest) GetAllOthers() bool {
+	if x != nil {
+		return x.AllOthers
+	}
+	return false
+}
+
+type RevokeSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RevokedCount  int32                  `protobuf:"varint,1,opt,name=revoked_count,json=revokedCount,proto3" json:"revoked_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionResponse) Reset() {
+	*x = RevokeSessionResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionResponse) ProtoMessage() {}
+
+func (x *RevokeSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionResponse.ProtoReflect.Descriptor instead.
+func (*RevokeSessionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RevokeSessionResponse) GetRevokedCount() int32 {
+	if x != nil {
+		return x.RevokedCount
+	}
+	return 0
+}
+
+// ChangePasswordRequest sets a new password for the caller. Every other login
+// is signed out.
+type ChangePasswordRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CurrentPassword string                 `protobuf:"bytes,1,opt,name=current_password,json=currentPassword,proto3" json:"current_password,omitempty"`
+	NewPassword     string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ChangePasswordRequest) GetCurrentPassword() string {
+	if x != nil {
+		return x.CurrentPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type ChangePasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RevokedCount  int32                  `protobuf:"varint,1,opt,name=revoked_count,json=revokedCount,proto3" json:"revoked_count,omitempty"` // other logins signed out
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordResponse) Reset() {
+	*x = ChangePasswordResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordResponse) ProtoMessage() {}
+
+func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordResponse.ProtoReflect.Descriptor instead.
+func (*ChangePasswordResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ChangePasswordResponse) GetRevokedCount() int32 {
+	if x != nil {
+		return x.RevokedCount
+	}
+	return 0
+}
+
+// SetRecoveryEmailRequest sets where password reset links are sent.
+type SetRecoveryEmailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"` // the caller's current password
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRecoveryEmailRequest) Reset() {
+	*x = SetRecoveryEmailRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRecoveryEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRecoveryEmailRequest) ProtoMessage() {}
+
+func (x *SetRecoveryEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRecoveryEmailRequest.ProtoReflect.Descriptor instead.
+func (*SetRecoveryEmailRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SetRecoveryEmailRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *SetRecoveryEmailRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type SetRecoveryEmailResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRecoveryEmailResponse) Reset() {
+	*x = SetRecoveryEmailResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRecoveryEmailResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRecoveryEmailResponse) ProtoMessage() {}
+
+func (x *SetRecoveryEmailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRecoveryEmailResponse.ProtoReflect.Descriptor instead.
+func (*SetRecoveryEmailResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{25}
+}
+
+// RequestPasswordResetRequest mails a single-use reset link to email if it
+// is the recovery email of a password account. The response is the same
+// either way.
+type RequestPasswordResetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPasswordResetRequest) Reset() {
+	*x = RequestPasswordResetRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPasswordResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPasswordResetRequest) ProtoMessage() {}
+
+func (x *RequestPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RequestPasswordResetRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type RequestPasswordResetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPasswordResetResponse) Reset() {
+	*x = RequestPasswordResetResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPasswordResetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPasswordResetResponse) ProtoMessage() {}
+
+func (x *RequestPasswordResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPasswordResetResponse.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{27}
+}
+
+// ResetPasswordRequest sets a new password with the token from a reset link.
+// Every login of the account is signed out.
+type ResetPasswordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	NewPassword   string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetPasswordRequest) Reset() {
+	*x = ResetPasswordRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetPasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetPasswordRequest) ProtoMessage() {}
+
+func (x *ResetPasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetPasswordRequest.ProtoReflect.Descriptor instead.
+func (*ResetPasswordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ResetPasswordRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ResetPasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type ResetPasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetPasswordResponse) Reset() {
+	*x = ResetPasswordResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetPasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetPasswordResponse) ProtoMessage() {}
+
+func (x *ResetPasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetPasswordResponse.ProtoReflect.Descriptor instead.
+func (*ResetPasswordResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{29}
+}
+
+// EnrollTOTPRequest starts turning on two-factor sign-in with a new
+// authenticator secret. Until ConfirmTOTP, sign-in is unchanged.
+type EnrollTOTPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTOTPRequest) Reset() {
+	*x = EnrollTOTPRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTOTPRequest) ProtoMessage() {}
+
+func (x *EnrollTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTOTPRequest.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{30}
+}
+
+type EnrollTOTPResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Secret        string                 `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`                           // base32, for manual entry
+	OtpauthUri    string                 `protobuf:"bytes,2,opt,name=otpauth_uri,json=otpauthUri,proto3" json:"otpauth_uri,omitempty"` // otpauth://totp/... for a QR code
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTOTPResponse) Reset() {
+	*x = EnrollTOTPResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTOTPResponse) ProtoMessage() {}
+
+func (x *EnrollTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTOTPResponse.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *EnrollTOTPResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *EnrollTOTPResponse) GetOtpauthUri() string {
+	if x != nil {
+		return x.OtpauthUri
+	}
+	return ""
+}
+
+// ConfirmTOTPRequest turns two-factor sign-in on with a code from the
+// authenticator enrolled by EnrollTOTP.
+type ConfirmTOTPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTOTPRequest) Reset() {
+	*x = ConfirmTOTPRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTOTPRequest) ProtoMessage() {}
+
+func (x *ConfirmTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTOTPRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ConfirmTOTPRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ConfirmTOTPResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecoveryCodes []string               `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"` // shown once; each works once in place of a code
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTOTPResponse) Reset() {
+	*x = ConfirmTOTPResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTOTPResponse) ProtoMessage() {}
+
+func (x *ConfirmTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTOTPResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ConfirmTOTPResponse) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+// DisableTOTPRequest turns two-factor sign-in off.
+type DisableTOTPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"` // a current code or an unused recovery code
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableTOTPRequest) Reset() {
+	*x = DisableTOTPRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableTOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableTOTPRequest) ProtoMessage() {}
+
+func (x *DisableTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableTOTPRequest.ProtoReflect.Descriptor instead.
+func (*DisableTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *DisableTOTPRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type DisableTOTPResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableTOTPResponse) Reset() {
+	*x = DisableTOTPResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableTOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableTOTPResponse) ProtoMessage() {}
+
+func (x *DisableTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableTOTPResponse.ProtoReflect.Descriptor instead.
+func (*DisableTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{35}
+}
+
+// VerifySecondFactorRequest finishes a sign-in that returned
+// second_factor_required.
+type VerifySecondFactorRequest struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	ChallengeToken        string                 `protobuf:"bytes,1,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	Code                  string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`                                                                  // a current code or an unused recovery code
+	PasskeyCredentialJson string                 `protobuf:"bytes,3,opt,name=passkey_credential_json,json=passkeyCredentialJson,proto3" json:"passkey_credential_json,omitempty"` // instead of code: an answer to BeginSecondFactorPasskey
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *VerifySecondFactorRequest) Reset() {
+	*x = VerifySecondFactorRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifySecondFactorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifySecondFactorRequest) ProtoMessage() {}
+
+func (x *VerifySecondFactorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifySecondFactorRequest.ProtoReflect.Descriptor instead.
+func (*VerifySecondFactorRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *VerifySecondFactorRequest) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *VerifySecondFactorRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *VerifySecondFactorRequest) GetPasskeyCredentialJson() string {
+	if x != nil {
+		return x.PasskeyCredentialJson
+	}
+	return ""
+}
+
+type VerifySecondFactorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifySecondFactorResponse) Reset() {
+	*x = VerifySecondFactorResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifySecondFactorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifySecondFactorResponse) ProtoMessage() {}
+
+func (x *VerifySecondFactorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifySecondFactorResponse.ProtoReflect.Descriptor instead.
+func (*VerifySecondFactorResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *VerifySecondFactorResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *VerifySecondFactorResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *VerifySecondFactorResponse) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+// Passkey is one of the caller's WebAuthn credentials.
+type Passkey struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                 // RFC 3339
+	LastUsedAt     string                 `protobuf:"bytes,4,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`            // RFC 3339; empty if never used to sign in
+	BackupEligible bool                   `protobuf:"varint,5,opt,name=backup_eligible,json=backupEligible,proto3" json:"backup_eligible,omitempty"` // synced between devices rather than bound to one
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Passkey) Reset() {
+	*x = Passkey{}
+	mi := &file_proto_auth_auth_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Passkey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Passkey) ProtoMessage() {}
+
+func (x *Passkey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Passkey.ProtoReflect.Descriptor instead.
+func (*Passkey) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *Passkey) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Passkey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Passkey) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Passkey) GetLastUsedAt() string {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return ""
+}
+
+func (x *Passkey) GetBackupEligible() bool {
+	if x != nil {
+		return x.BackupEligible
+	}
+	return false
+}
+
+// BeginPasskeyRegistrationRequest starts adding a passkey to the caller's
+// account.
+type BeginPasskeyRegistrationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyRegistrationRequest) Reset() {
+	*x = BeginPasskeyRegistrationRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyRegistrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyRegistrationRequest) ProtoMessage() {}
+
+func (x *BeginPasskeyRegistrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyRegistrationRequest.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyRegistrationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{39}
+}
+
+type BeginPasskeyRegistrationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OptionsJson   string                 `protobuf:"bytes,1,opt,name=options_json,json=optionsJson,proto3" json:"options_json,omitempty"` // PublicKeyCredentialCreationOptions JSON for navigator.credentials.create
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyRegistrationResponse) Reset() {
+	*x = BeginPasskeyRegistrationResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyRegistrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyRegistrationResponse) ProtoMessage() {}
+
+func (x *BeginPasskeyRegistrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyRegistrationResponse.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyRegistrationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *BeginPasskeyRegistrationResponse) GetOptionsJson() string {
+	if x != nil {
+		return x.OptionsJson
+	}
+	return ""
+}
+
+// FinishPasskeyRegistrationRequest stores the passkey the browser created.
+type FinishPasskeyRegistrationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CredentialJson string                 `protobuf:"bytes,1,opt,name=credential_json,json=credentialJson,proto3" json:"credential_json,omitempty"` // PublicKeyCredential.toJSON()
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                           // optional label, e.g. "Work laptop"
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FinishPasskeyRegistrationRequest) Reset() {
+	*x = FinishPasskeyRegistrationRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishPasskeyRegistrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishPasskeyRegistrationRequest) ProtoMessage() {}
+
+func (x *FinishPasskeyRegistrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishPasskeyRegistrationRequest.ProtoReflect.Descriptor instead.
+func (*FinishPasskeyRegistrationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *FinishPasskeyRegistrationRequest) GetCredentialJson() string {
+	if x != nil {
+		return x.CredentialJson
+	}
+	return ""
+}
+
+func (x *FinishPasskeyRegistrationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type FinishPasskeyRegistrationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Passkey       *Passkey               `protobuf:"bytes,1,opt,name=passkey,proto3" json:"passkey,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishPasskeyRegistrationResponse) Reset() {
+	*x = FinishPasskeyRegistrationResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishPasskeyRegistrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishPasskeyRegistrationResponse) ProtoMessage() {}
+
+func (x *FinishPasskeyRegistrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishPasskeyRegistrationResponse.ProtoReflect.Descriptor instead.
+func (*FinishPasskeyRegistrationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *FinishPasskeyRegistrationResponse) GetPasskey() *Passkey {
+	if x != nil {
+		return x.Passkey
+	}
+	return nil
+}
+
+type ListPasskeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPasskeysRequest) Reset() {
+	*x = ListPasskeysRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPasskeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPasskeysRequest) ProtoMessage() {}
+
+func (x *ListPasskeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPasskeysRequest.ProtoReflect.Descriptor instead.
+func (*ListPasskeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{43}
+}
+
+type ListPasskeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Passkeys      []*Passkey             `protobuf:"bytes,1,rep,name=passkeys,proto3" json:"passkeys,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPasskeysResponse) Reset() {
+	*x = ListPasskeysResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPasskeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPasskeysResponse) ProtoMessage() {}
+
+func (x *ListPasskeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPasskeysResponse.ProtoReflect.Descriptor instead.
+func (*ListPasskeysResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListPasskeysResponse) GetPasskeys() []*Passkey {
+	if x != nil {
+		return x.Passkeys
+	}
+	return nil
+}
+
+type DeletePasskeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePasskeyRequest) Reset() {
+	*x = DeletePasskeyRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePasskeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePasskeyRequest) ProtoMessage() {}
+
+func (x *DeletePasskeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePasskeyRequest.ProtoReflect.Descriptor instead.
+func (*DeletePasskeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *DeletePasskeyRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeletePasskeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePasskeyResponse) Reset() {
+	*x = DeletePasskeyResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePasskeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePasskeyResponse) ProtoMessage() {}
+
+func (x *DeletePasskeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePasskeyResponse.ProtoReflect.Descriptor instead.
+func (*DeletePasskeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{46}
+}
+
+// BeginPasskeyLoginRequest starts a passwordless sign-in. The user picks one
+// of their passkeys for the site, which says who they are.
+type BeginPasskeyLoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyLoginRequest) Reset() {
+	*x = BeginPasskeyLoginRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyLoginRequest) ProtoMessage() {}
+
+func (x *BeginPasskeyLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyLoginRequest.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyLoginRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{47}
+}
+
+type BeginPasskeyLoginResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OptionsJson   string                 `protobuf:"bytes,1,opt,name=options_json,json=optionsJson,proto3" json:"options_json,omitempty"` // PublicKeyCredentialRequestOptions JSON for navigator.credentials.get
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginPasskeyLoginResponse) Reset() {
+	*x = BeginPasskeyLoginResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginPasskeyLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginPasskeyLoginResponse) ProtoMessage() {}
+
+func (x *BeginPasskeyLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginPasskeyLoginResponse.ProtoReflect.Descriptor instead.
+func (*BeginPasskeyLoginResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *BeginPasskeyLoginResponse) GetOptionsJson() string {
+	if x != nil {
+		return x.OptionsJson
+	}
+	return ""
+}
+
+// FinishPasskeyLoginRequest signs in with the passkey the browser returned.
+// A passkey verifies the user itself, so no second factor is asked for.
+type FinishPasskeyLoginRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CredentialJson string                 `protobuf:"bytes,1,opt,name=credential_json,json=credentialJson,proto3" json:"credential_json,omitempty"` // PublicKeyCredential.toJSON()
+	DeviceName     string                 `protobuf:"bytes,2,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`             // optional label for the new login
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FinishPasskeyLoginRequest) Reset() {
+	*x = FinishPasskeyLoginRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishPasskeyLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishPasskeyLoginRequest) ProtoMessage() {}
+
+func (x *FinishPasskeyLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishPasskeyLoginRequest.ProtoReflect.Descriptor instead.
+func (*FinishPasskeyLoginRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *FinishPasskeyLoginRequest) GetCredentialJson() string {
+	if x != nil {
+		return x.CredentialJson
+	}
+	return ""
+}
+
+func (x *FinishPasskeyLoginRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+type FinishPasskeyLoginResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishPasskeyLoginResponse) Reset() {
+	*x = FinishPasskeyLoginResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishPasskeyLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishPasskeyLoginResponse) ProtoMessage() {}
+
+func (x *FinishPasskeyLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishPasskeyLoginResponse.ProtoReflect.Descriptor instead.
+func (*FinishPasskeyLoginResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *FinishPasskeyLoginResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *FinishPasskeyLoginResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *FinishPasskeyLoginResponse) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+// BeginSecondFactorPasskeyRequest asks for one of the user's passkeys in
+// place of a code for a sign-in that returned second_factor_required.
+type BeginSecondFactorPasskeyRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChallengeToken string                 `protobuf:"bytes,1,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BeginSecondFactorPasskeyRequest) Reset() {
+	*x = BeginSecondFactorPasskeyRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginSecondFactorPasskeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginSecondFactorPasskeyRequest) ProtoMessage() {}
+
+func (x *BeginSecondFactorPasskeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginSecondFactorPasskeyRequest.ProtoReflect.Descriptor instead.
+func (*BeginSecondFactorPasskeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *BeginSecondFactorPasskeyRequest) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+type BeginSecondFactorPasskeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OptionsJson   string                 `protobuf:"bytes,1,opt,name=options_json,json=optionsJson,proto3" json:"options_json,omitempty"` // PublicKeyCredentialRequestOptions JSON for navigator.credentials.get
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginSecondFactorPasskeyResponse) Reset() {
+	*x = BeginSecondFactorPasskeyResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginSecondFactorPasskeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginSecondFactorPasskeyResponse) ProtoMessage() {}
+
+func (x *BeginSecondFactorPasskeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginSecondFactorPasskeyResponse.ProtoReflect.Descriptor instead.
+func (*BeginSecondFactorPasskeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *BeginSecondFactorPasskeyResponse) GetOptionsJson() string {
+	if x != nil {
+		return x.OptionsJson
+	}
+	return ""
+}
+
+type SetupKeysRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	PublicKey           string                 `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`                                 // age X25519 public key (age1...)
+	EncryptedPrivateKey string                 `protobuf:"bytes,2,opt,name=encrypted_private_key,json=encryptedPrivateKey,proto3" json:"encrypted_private_key,omitempty"` // PIN-encrypted private key (base64)
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SetupKeysRequest) Reset() {
+	*x = SetupKeysRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetupKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetupKeysRequest) ProtoMessage() {}
+
+func (x *SetupKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetupKeysRequest.ProtoReflect.Descriptor instead.
+func (*SetupKeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *SetupKeysRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *SetupKeysRequest) GetEncryptedPrivateKey() string {
+	if x != nil {
+		return x.EncryptedPrivateKey
+	}
+	return ""
+}
+
+type SetupKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsE2EeReady   bool                   `protobuf:"varint,1,opt,name=is_e2ee_ready,json=isE2eeReady,proto3" json:"is_e2ee_ready,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetupKeysResponse) Reset() {
+	*x = SetupKeysResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetupKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetupKeysResponse) ProtoMessage() {}
+
+func (x *SetupKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetupKeysResponse.ProtoReflect.Descriptor instead.
+func (*SetupKeysResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *SetupKeysResponse) GetIsE2EeReady() bool {
+	if x != nil {
+		return x.IsE2EeReady
+	}
+	return false
+}
+
+type GetMyKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMyKeysRequest) Reset() {
+	*x = GetMyKeysRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMyKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMyKeysRequest) ProtoMessage() {}
+
+func (x *GetMyKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMyKeysRequest.ProtoReflect.Descriptor instead.
+func (*GetMyKeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{55}
+}
+
+type GetMyKeysResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	EncryptedPrivateKey string                 `protobuf:"bytes,1,opt,name=encrypted_private_key,json=encryptedPrivateKey,proto3" json:"encrypted_private_key,omitempty"` // base64-encoded PIN-encrypted private key
+	PublicKey           string                 `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`                                 // age X25519 public key
+	IsE2EeReady         bool                   `protobuf:"varint,3,opt,name=is_e2ee_ready,json=isE2eeReady,proto3" json:"is_e2ee_ready,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *GetMyKeysResponse) Reset() {
+	*x = GetMyKeysResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMyKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMyKeysResponse) ProtoMessage() {}
+
+func (x *GetMyKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMyKeysResponse.ProtoReflect.Descriptor instead.
+func (*GetMyKeysResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *GetMyKeysResponse) GetEncryptedPrivateKey() string {
+	if x != nil {
+		return x.EncryptedPrivateKey
+	}
+	return ""
+}
+
+func (x *GetMyKeysResponse) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *GetMyKeysResponse) GetIsE2EeReady() bool {
+	if x != nil {
+		return x.IsE2EeReady
+	}
+	return false
+}
+
+type GetPublicKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"` // UUIDs of users whose public keys to fetch
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublicKeysRequest) Reset() {
+	*x = GetPublicKeysRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublicKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublicKeysRequest) ProtoMessage() {}
+
+func (x *GetPublicKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublicKeysRequest.ProtoReflect.Descriptor instead.
+func (*GetPublicKeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *GetPublicKeysRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type PublicKeyEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PublicKey     string                 `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"` // age X25519 public key
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublicKeyEntry) Reset() {
+	*x = PublicKeyEntry{}
+	mi := &file_proto_auth_auth_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublicKeyEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublicKeyEntry) ProtoMessage() {}
+
+func (x *PublicKeyEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublicKeyEntry.ProtoReflect.Descriptor instead.
+func (*PublicKeyEntry) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *PublicKeyEntry) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PublicKeyEntry) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+type GetPublicKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*PublicKeyEntry      `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublicKeysResponse) Reset() {
+	*x = GetPublicKeysResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublicKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublicKeysResponse) ProtoMessage() {}
+
+func (x *GetPublicKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -963,32 +2999,543 @@ func (x *GetPublicKeysResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPublicKeysResponse.ProtoReflect.Descriptor instead.
 func (*GetPublicKeysResponse) Descriptor() ([]byte, []int) {
-	return file_proto_auth_auth_proto_rawDescGZIP(), []int{19}
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *GetPublicKeysResponse) GetKeys() []*PublicKeyEntry {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type Profile struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserName        string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	DisplayName     string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	AvatarUrl       string                 `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	Bio             string                 `protobuf:"bytes,5,opt,name=bio,proto3" json:"bio,omitempty"`
+	StatusText      string                 `protobuf:"bytes,6,opt,name=status_text,json=statusText,proto3" json:"status_text,omitempty"`                  // empty once the status has expired
+	StatusExpiresAt string                 `protobuf:"bytes,7,opt,name=status_expires_at,json=statusExpiresAt,proto3" json:"status_expires_at,omitempty"` // RFC 3339; empty when the status does not expire
+	UpdatedAt       string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	LastSeenAt      string                 `protobuf:"bytes,9,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"` // RFC 3339; empty if never seen or hidden by the user's privacy settings
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Profile) Reset() {
+	*x = Profile{}
+	mi := &file_proto_auth_auth_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Profile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Profile) ProtoMessage() {}
+
+func (x *Profile) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *Profile) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Profile) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *Profile) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *Profile) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *Profile) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+func (x *Profile) GetStatusText() string {
+	if x != nil {
+		return x.StatusText
+	}
+	return ""
+}
+
+func (x *Profile) GetStatusExpiresAt() string {
+	if x != nil {
+		return x.StatusExpiresAt
+	}
+	return ""
+}
+
+func (x *Profile) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Profile) GetLastSeenAt() string {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return ""
+}
+
+type GetProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // empty for the caller's own profile
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProfileRequest) Reset() {
+	*x = GetProfileRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *GetPublicKeysResponse) GetKeys() []*PublicKeyEntry {
+func (*GetProfileRequest) ProtoMessage() {}
+
+func (x *GetProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[61]
 	if x != nil {
-		return x.Keys
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetProfileRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *GetProfileRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// UpdateProfileRequest changes only the fields that are set. An empty string
+// clears a field. Setting status_text replaces the status and its expiry.
+type UpdateProfileRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DisplayName     *string                `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3,oneof" json:"display_name,omitempty"`         // up to 100 characters
+	AvatarUrl       *string                `protobuf:"bytes,2,opt,name=avatar_url,json=avatarUrl,proto3,oneof" json:"avatar_url,omitempty"`               // http(s) URL
+	Bio             *string                `protobuf:"bytes,3,opt,name=bio,proto3,oneof" json:"bio,omitempty"`                                            // up to 500 characters
+	StatusText      *string                `protobuf:"bytes,4,opt,name=status_text,json=statusText,proto3,oneof" json:"status_text,omitempty"`            // up to 140 characters
+	StatusExpiresAt string                 `protobuf:"bytes,5,opt,name=status_expires_at,json=statusExpiresAt,proto3" json:"status_expires_at,omitempty"` // RFC 3339, in the future; needs status_text
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateProfileRequest) Reset() {
+	*x = UpdateProfileRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProfileRequest) ProtoMessage() {}
+
+func (x *UpdateProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProfileRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProfileRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *UpdateProfileRequest) GetDisplayName() string {
+	if x != nil && x.DisplayName != nil {
+		return *x.DisplayName
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequest) GetAvatarUrl() string {
+	if x != nil && x.AvatarUrl != nil {
+		return *x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequest) GetBio() string {
+	if x != nil && x.Bio != nil {
+		return *x.Bio
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequest) GetStatusText() string {
+	if x != nil && x.StatusText != nil {
+		return *x.StatusText
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequest) GetStatusExpiresAt() string {
+	if x != nil {
+		return x.StatusExpiresAt
+	}
+	return ""
+}
+
+// ChangeUsernameRequest renames the caller. Renames are limited to one per 30
+// days, and a released name stays reserved for its previous owner for 90 days.
+type ChangeUsernameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NewUsername   string                 `protobuf:"bytes,1,opt,name=new_username,json=newUsername,proto3" json:"new_username,omitempty"` // 3-50 letters, digits, '.', '_' or '-'
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeUsernameRequest) Reset() {
+	*x = ChangeUsernameRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeUsernameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeUsernameRequest) ProtoMessage() {}
+
+func (x *ChangeUsernameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeUsernameRequest.ProtoReflect.Descriptor instead.
+func (*ChangeUsernameRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *ChangeUsernameRequest) GetNewUsername() string {
+	if x != nil {
+		return x.NewUsername
+	}
+	return ""
+}
+
+type ChangeUsernameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"` // session token for the same login, carrying the new name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeUsernameResponse) Reset() {
+	*x = ChangeUsernameResponse{}
+	mi := &file_proto_auth_auth_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeUsernameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeUsernameResponse) ProtoMessage() {}
+
+func (x *ChangeUsernameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeUsernameResponse.ProtoReflect.Descriptor instead.
+func (*ChangeUsernameResponse) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ChangeUsernameResponse) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ChangeUsernameResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// PrivacySettings controls who can find the caller, send them friend
+// requests, see how far they have read, and see when they were last online.
+type PrivacySettings struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Discoverability string                 `protobuf:"bytes,1,opt,name=discoverability,proto3" json:"discoverability,omitempty"`                     // "everyone" | "friends_of_friends" | "nobody"
+	FriendRequests  string                 `protobuf:"bytes,2,opt,name=friend_requests,json=friendRequests,proto3" json:"friend_requests,omitempty"` // "everyone" | "friends_of_friends" | "nobody"
+	ReadReceipts    bool                   `protobuf:"varint,3,opt,name=read_receipts,json=readReceipts,proto3" json:"read_receipts,omitempty"`
+	LastSeen        string                 `protobuf:"bytes,4,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"` // "everyone" | "friends" | "nobody"
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PrivacySettings) Reset() {
+	*x = PrivacySettings{}
+	mi := &file_proto_auth_auth_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrivacySettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrivacySettings) ProtoMessage() {}
+
+func (x *PrivacySettings) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrivacySettings.ProtoReflect.Descriptor instead.
+func (*PrivacySettings) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *PrivacySettings) GetDiscoverability() string {
+	if x != nil {
+		return x.Discoverability
+	}
+	return ""
+}
+
+func (x *PrivacySettings) GetFriendRequests() string {
+	if x != nil {
+		return x.FriendRequests
+	}
+	return ""
+}
+
+func (x *PrivacySettings) GetReadReceipts() bool {
+	if x != nil {
+		return x.ReadReceipts
+	}
+	return false
+}
+
+func (x *PrivacySettings) GetLastSeen() string {
+	if x != nil {
+		return x.LastSeen
+	}
+	return ""
+}
+
+type GetPrivacySettingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPrivacySettingsRequest) Reset() {
+	*x = GetPrivacySettingsRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPrivacySettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPrivacySettingsRequest) ProtoMessage() {}
+
+func (x *GetPrivacySettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPrivacySettingsRequest.ProtoReflect.Descriptor instead.
+func (*GetPrivacySettingsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{66}
+}
+
+// UpdatePrivacySettingsRequest changes only the fields that are set.
+type UpdatePrivacySettingsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Discoverability *string                `protobuf:"bytes,1,opt,name=discoverability,proto3,oneof" json:"discoverability,omitempty"`
+	FriendRequests  *string                `protobuf:"bytes,2,opt,name=friend_requests,json=friendRequests,proto3,oneof" json:"friend_requests,omitempty"`
+	ReadReceipts    *bool                  `protobuf:"varint,3,opt,name=read_receipts,json=readReceipts,proto3,oneof" json:"read_receipts,omitempty"`
+	LastSeen        *string                `protobuf:"bytes,4,opt,name=last_seen,json=lastSeen,proto3,oneof" json:"last_seen,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdatePrivacySettingsRequest) Reset() {
+	*x = UpdatePrivacySettingsRequest{}
+	mi := &file_proto_auth_auth_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePrivacySettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePrivacySettingsRequest) ProtoMessage() {}
+
+func (x *UpdatePrivacySettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_auth_auth_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePrivacySettingsRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePrivacySettingsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_auth_auth_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *UpdatePrivacySettingsRequest) GetDiscoverability() string {
+	if x != nil && x.Discoverability != nil {
+		return *x.Discoverability
+	}
+	return ""
+}
+
+func (x *UpdatePrivacySettingsRequest) GetFriendRequests() string {
+	if x != nil && x.FriendRequests != nil {
+		return *x.FriendRequests
+	}
+	return ""
+}
+
+func (x *UpdatePrivacySettingsRequest) GetReadReceipts() bool {
+	if x != nil && x.ReadReceipts != nil {
+		return *x.ReadReceipts
+	}
+	return false
+}
+
+func (x *UpdatePrivacySettingsRequest) GetLastSeen() string {
+	if x != nil && x.LastSeen != nil {
+		return *x.LastSeen
+	}
+	return ""
 }
 
 var File_proto_auth_auth_proto protoreflect.FileDescriptor
 
 const file_proto_auth_auth_proto_rawDesc = "" +
 	"\n" +
-	"\x15proto/auth/auth.proto\x12\x04auth\"B\n" +
+	"\x15proto/auth/auth.proto\x12\x04auth\"c\n" +
 	"\fLoginRequest\x12\x1a\n" +
 	"\buserName\x18\x01 \x01(\tR\buserName\x12\x16\n" +
-	"\x06passwd\x18\x02 \x01(\tR\x06passwd\"%\n" +
+	"\x06passwd\x18\x02 \x01(\tR\x06passwd\x12\x1f\n" +
+	"\vdevice_name\x18\x03 \x01(\tR\n" +
+	"deviceName\"\xa9\x01\n" +
 	"\rLoginResponse\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\"C\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x124\n" +
+	"\x16second_factor_required\x18\x03 \x01(\bR\x14secondFactorRequired\x12'\n" +
+	"\x0fchallenge_token\x18\x04 \x01(\tR\x0echallengeToken\"Y\n" +
 	"\rSignupRequest\x12\x1a\n" +
 	"\buserName\x18\x01 \x01(\tR\buserName\x12\x16\n" +
-	"\x06passwd\x18\x02 \x01(\tR\x06passwd\"\x10\n" +
-	"\x0eSignupResponse\"*\n" +
+	"\x06passwd\x18\x02 \x01(\tR\x06passwd\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\"\x10\n" +
+	"\x0eSignupResponse\"X\n" +
 	"\x12SearchUsersRequest\x12\x14\n" +
-	"\x05query\x18\x01 \x01(\tR\x05query\"\xf1\x01\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"\xc8\x02\n" +
 	"\n" +
 	"UserResult\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
@@ -997,21 +3544,135 @@ const file_proto_auth_auth_proto_rawDesc = "" +
 	"\n" +
 	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12+\n" +
 	"\x11friendship_status\x18\x05 \x01(\tR\x10friendshipStatus\x12>\n" +
-	"\x1bfriendship_initiator_userid\x18\x06 \x01(\tR\x19friendshipInitiatorUserid\"=\n" +
+	"\x1bfriendship_initiator_userid\x18\x06 \x01(\tR\x19friendshipInitiatorUserid\x12.\n" +
+	"\x13mutual_friend_count\x18\a \x01(\x05R\x11mutualFriendCount\x12%\n" +
+	"\x0emutual_friends\x18\b \x03(\tR\rmutualFriends\"^\n" +
 	"\x13SearchUsersResponse\x12&\n" +
-	"\x05users\x18\x01 \x03(\v2\x10.auth.UserResultR\x05users\"\x1a\n" +
-	"\x18GetGithubOAuthURLRequest\"-\n" +
-	"\x19GetGithubOAuthURLResponse\x12\x10\n" +
-	"\x03url\x18\x01 \x01(\tR\x03url\"0\n" +
-	"\x1aGithubOAuthCallbackRequest\x12\x12\n" +
-	"\x04code\x18\x01 \x01(\tR\x04code\"c\n" +
-	"\x1bGithubOAuthCallbackResponse\x12(\n" +
+	"\x05users\x18\x01 \x03(\v2\x10.auth.UserResultR\x05users\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"0\n" +
+	"\x12GetOAuthURLRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\"'\n" +
+	"\x13GetOAuthURLResponse\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\"\\\n" +
+	"\x14OAuthCallbackRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\"]\n" +
+	"\x15OAuthCallbackResponse\x12(\n" +
 	"\x0fshortLivedToken\x18\x01 \x01(\tR\x0fshortLivedToken\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\"\x16\n" +
-	"\x14ExchangeTokenRequest\"I\n" +
+	"\busername\x18\x02 \x01(\tR\busername\"7\n" +
+	"\x14ExchangeTokenRequest\x12\x1f\n" +
+	"\vdevice_name\x18\x01 \x01(\tR\n" +
+	"deviceName\"\xcd\x01\n" +
 	"\x15ExchangeTokenResponse\x12\x14\n" +
 	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\"e\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12#\n" +
+	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\x124\n" +
+	"\x16second_factor_required\x18\x04 \x01(\bR\x14secondFactorRequired\x12'\n" +
+	"\x0fchallenge_token\x18\x05 \x01(\tR\x0echallengeToken\":\n" +
+	"\x13RefreshTokenRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"m\n" +
+	"\x14RefreshTokenResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\"\x0f\n" +
+	"\rLogoutRequest\"\x10\n" +
+	"\x0eLogoutResponse\"\xe7\x01\n" +
+	"\fLoginSession\x12\x19\n" +
+	"\blogin_id\x18\x01 \x01(\tR\aloginId\x12\x1f\n" +
+	"\vdevice_name\x18\x02 \x01(\tR\n" +
+	"deviceName\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x03 \x01(\tR\tuserAgent\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x04 \x01(\tR\tipAddress\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12$\n" +
+	"\x0elast_active_at\x18\x06 \x01(\tR\flastActiveAt\x12\x18\n" +
+	"\acurrent\x18\a \x01(\bR\acurrent\"\x15\n" +
+	"\x13ListSessionsRequest\"F\n" +
+	"\x14ListSessionsResponse\x12.\n" +
+	"\bsessions\x18\x01 \x03(\v2\x12.auth.LoginSessionR\bsessions\"P\n" +
+	"\x14RevokeSessionRequest\x12\x19\n" +
+	"\blogin_id\x18\x01 \x01(\tR\aloginId\x12\x1d\n" +
+	"\n" +
+	"all_others\x18\x02 \x01(\bR\tallOthers\"<\n" +
+	"\x15RevokeSessionResponse\x12#\n" +
+	"\rrevoked_count\x18\x01 \x01(\x05R\frevokedCount\"e\n" +
+	"\x15ChangePasswordRequest\x12)\n" +
+	"\x10current_password\x18\x01 \x01(\tR\x0fcurrentPassword\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\"=\n" +
+	"\x16ChangePasswordResponse\x12#\n" +
+	"\rrevoked_count\x18\x01 \x01(\x05R\frevokedCount\"K\n" +
+	"\x17SetRecoveryEmailRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\x1a\n" +
+	"\x18SetRecoveryEmailResponse\"3\n" +
+	"\x1bRequestPasswordResetRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"\x1e\n" +
+	"\x1cRequestPasswordResetResponse\"O\n" +
+	"\x14ResetPasswordRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\"\x17\n" +
+	"\x15ResetPasswordResponse\"\x13\n" +
+	"\x11EnrollTOTPRequest\"M\n" +
+	"\x12EnrollTOTPResponse\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12\x1f\n" +
+	"\votpauth_uri\x18\x02 \x01(\tR\n" +
+	"otpauthUri\"(\n" +
+	"\x12ConfirmTOTPRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"<\n" +
+	"\x13ConfirmTOTPResponse\x12%\n" +
+	"\x0erecovery_codes\x18\x01 \x03(\tR\rrecoveryCodes\"(\n" +
+	"\x12DisableTOTPRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\x15\n" +
+	"\x13DisableTOTPResponse\"\x90\x01\n" +
+	"\x19VerifySecondFactorRequest\x12'\n" +
+	"\x0fchallenge_token\x18\x01 \x01(\tR\x0echallengeToken\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x126\n" +
+	"\x17passkey_credential_json\x18\x03 \x01(\tR\x15passkeyCredentialJson\"s\n" +
+	"\x1aVerifySecondFactorResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\"\x97\x01\n" +
+	"\aPasskey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12 \n" +
+	"\flast_used_at\x18\x04 \x01(\tR\n" +
+	"lastUsedAt\x12'\n" +
+	"\x0fbackup_eligible\x18\x05 \x01(\bR\x0ebackupEligible\"!\n" +
+	"\x1fBeginPasskeyRegistrationRequest\"E\n" +
+	" BeginPasskeyRegistrationResponse\x12!\n" +
+	"\foptions_json\x18\x01 \x01(\tR\voptionsJson\"_\n" +
+	" FinishPasskeyRegistrationRequest\x12'\n" +
+	"\x0fcredential_json\x18\x01 \x01(\tR\x0ecredentialJson\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"L\n" +
+	"!FinishPasskeyRegistrationResponse\x12'\n" +
+	"\apasskey\x18\x01 \x01(\v2\r.auth.PasskeyR\apasskey\"\x15\n" +
+	"\x13ListPasskeysRequest\"A\n" +
+	"\x14ListPasskeysResponse\x12)\n" +
+	"\bpasskeys\x18\x01 \x03(\v2\r.auth.PasskeyR\bpasskeys\"&\n" +
+	"\x14DeletePasskeyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x17\n" +
+	"\x15DeletePasskeyResponse\"\x1a\n" +
+	"\x18BeginPasskeyLoginRequest\">\n" +
+	"\x19BeginPasskeyLoginResponse\x12!\n" +
+	"\foptions_json\x18\x01 \x01(\tR\voptionsJson\"e\n" +
+	"\x19FinishPasskeyLoginRequest\x12'\n" +
+	"\x0fcredential_json\x18\x01 \x01(\tR\x0ecredentialJson\x12\x1f\n" +
+	"\vdevice_name\x18\x02 \x01(\tR\n" +
+	"deviceName\"s\n" +
+	"\x1aFinishPasskeyLoginResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\"J\n" +
+	"\x1fBeginSecondFactorPasskeyRequest\x12'\n" +
+	"\x0fchallenge_token\x18\x01 \x01(\tR\x0echallengeToken\"E\n" +
+	" BeginSecondFactorPasskeyResponse\x12!\n" +
+	"\foptions_json\x18\x01 \x01(\tR\voptionsJson\"e\n" +
 	"\x10SetupKeysRequest\x12\x1d\n" +
 	"\n" +
 	"public_key\x18\x01 \x01(\tR\tpublicKey\x122\n" +
@@ -1031,17 +3692,92 @@ const file_proto_auth_auth_proto_rawDesc = "" +
 	"\n" +
 	"public_key\x18\x02 \x01(\tR\tpublicKey\"A\n" +
 	"\x15GetPublicKeysResponse\x12(\n" +
-	"\x04keys\x18\x01 \x03(\v2\x14.auth.PublicKeyEntryR\x04keys2\xf3\x04\n" +
+	"\x04keys\x18\x01 \x03(\v2\x14.auth.PublicKeyEntryR\x04keys\"\xa1\x02\n" +
+	"\aProfile\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tuser_name\x18\x02 \x01(\tR\buserName\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12\x10\n" +
+	"\x03bio\x18\x05 \x01(\tR\x03bio\x12\x1f\n" +
+	"\vstatus_text\x18\x06 \x01(\tR\n" +
+	"statusText\x12*\n" +
+	"\x11status_expires_at\x18\a \x01(\tR\x0fstatusExpiresAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\tR\tupdatedAt\x12 \n" +
+	"\flast_seen_at\x18\t \x01(\tR\n" +
+	"lastSeenAt\",\n" +
+	"\x11GetProfileRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x83\x02\n" +
+	"\x14UpdateProfileRequest\x12&\n" +
+	"\fdisplay_name\x18\x01 \x01(\tH\x00R\vdisplayName\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"avatar_url\x18\x02 \x01(\tH\x01R\tavatarUrl\x88\x01\x01\x12\x15\n" +
+	"\x03bio\x18\x03 \x01(\tH\x02R\x03bio\x88\x01\x01\x12$\n" +
+	"\vstatus_text\x18\x04 \x01(\tH\x03R\n" +
+	"statusText\x88\x01\x01\x12*\n" +
+	"\x11status_expires_at\x18\x05 \x01(\tR\x0fstatusExpiresAtB\x0f\n" +
+	"\r_display_nameB\r\n" +
+	"\v_avatar_urlB\x06\n" +
+	"\x04_bioB\x0e\n" +
+	"\f_status_text\":\n" +
+	"\x15ChangeUsernameRequest\x12!\n" +
+	"\fnew_username\x18\x01 \x01(\tR\vnewUsername\"J\n" +
+	"\x16ChangeUsernameResponse\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"\xa6\x01\n" +
+	"\x0fPrivacySettings\x12(\n" +
+	"\x0fdiscoverability\x18\x01 \x01(\tR\x0fdiscoverability\x12'\n" +
+	"\x0ffriend_requests\x18\x02 \x01(\tR\x0efriendRequests\x12#\n" +
+	"\rread_receipts\x18\x03 \x01(\bR\freadReceipts\x12\x1b\n" +
+	"\tlast_seen\x18\x04 \x01(\tR\blastSeen\"\x1b\n" +
+	"\x19GetPrivacySettingsRequest\"\x8f\x02\n" +
+	"\x1cUpdatePrivacySettingsRequest\x12-\n" +
+	"\x0fdiscoverability\x18\x01 \x01(\tH\x00R\x0fdiscoverability\x88\x01\x01\x12,\n" +
+	"\x0ffriend_requests\x18\x02 \x01(\tH\x01R\x0efriendRequests\x88\x01\x01\x12(\n" +
+	"\rread_receipts\x18\x03 \x01(\bH\x02R\freadReceipts\x88\x01\x01\x12 \n" +
+	"\tlast_seen\x18\x04 \x01(\tH\x03R\blastSeen\x88\x01\x01B\x12\n" +
+	"\x10_discoverabilityB\x12\n" +
+	"\x10_friend_requestsB\x10\n" +
+	"\x0e_read_receiptsB\f\n" +
+	"\n" +
+	"_last_seen2\xac\x13\n" +
 	"\x04Auth\x120\n" +
 	"\x05Login\x12\x12.auth.LoginRequest\x1a\x13.auth.LoginResponse\x123\n" +
 	"\x06Signup\x12\x13.auth.SignupRequest\x1a\x14.auth.SignupResponse\x12B\n" +
-	"\vSearchUsers\x12\x18.auth.SearchUsersRequest\x1a\x19.auth.SearchUsersResponse\x12T\n" +
-	"\x11GetGithubOAuthURL\x12\x1e.auth.GetGithubOAuthURLRequest\x1a\x1f.auth.GetGithubOAuthURLResponse\x12Z\n" +
-	"\x13GithubOAuthCallback\x12 .auth.GithubOAuthCallbackRequest\x1a!.auth.GithubOAuthCallbackResponse\x12H\n" +
-	"\rExchangeToken\x12\x1a.auth.ExchangeTokenRequest\x1a\x1b.auth.ExchangeTokenResponse\x12<\n" +
+	"\vSearchUsers\x12\x18.auth.SearchUsersRequest\x1a\x19.auth.SearchUsersResponse\x12B\n" +
+	"\vGetOAuthURL\x12\x18.auth.GetOAuthURLRequest\x1a\x19.auth.GetOAuthURLResponse\x12H\n" +
+	"\rOAuthCallback\x12\x1a.auth.OAuthCallbackRequest\x1a\x1b.auth.OAuthCallbackResponse\x12H\n" +
+	"\rExchangeToken\x12\x1a.auth.ExchangeTokenRequest\x1a\x1b.auth.ExchangeTokenResponse\x12E\n" +
+	"\fRefreshToken\x12\x19.auth.RefreshTokenRequest\x1a\x1a.auth.RefreshTokenResponse\x123\n" +
+	"\x06Logout\x12\x13.auth.LogoutRequest\x1a\x14.auth.LogoutResponse\x12E\n" +
+	"\fListSessions\x12\x19.auth.ListSessionsRequest\x1a\x1a.auth.ListSessionsResponse\x12H\n" +
+	"\rRevokeSession\x12\x1a.auth.RevokeSessionRequest\x1a\x1b.auth.RevokeSessionResponse\x12K\n" +
+	"\x0eChangePassword\x12\x1b.auth.ChangePasswordRequest\x1a\x1c.auth.ChangePasswordResponse\x12Q\n" +
+	"\x10SetRecoveryEmail\x12\x1d.auth.SetRecoveryEmailRequest\x1a\x1e.auth.SetRecoveryEmailResponse\x12]\n" +
+	"\x14RequestPasswordReset\x12!.auth.RequestPasswordResetRequest\x1a\".auth.RequestPasswordResetResponse\x12H\n" +
+	"\rResetPassword\x12\x1a.auth.ResetPasswordRequest\x1a\x1b.auth.ResetPasswordResponse\x12?\n" +
+	"\n" +
+	"EnrollTOTP\x12\x17.auth.EnrollTOTPRequest\x1a\x18.auth.EnrollTOTPResponse\x12B\n" +
+	"\vConfirmTOTP\x12\x18.auth.ConfirmTOTPRequest\x1a\x19.auth.ConfirmTOTPResponse\x12B\n" +
+	"\vDisableTOTP\x12\x18.auth.DisableTOTPRequest\x1a\x19.auth.DisableTOTPResponse\x12W\n" +
+	"\x12VerifySecondFactor\x12\x1f.auth.VerifySecondFactorRequest\x1a .auth.VerifySecondFactorResponse\x12i\n" +
+	"\x18BeginPasskeyRegistration\x12%.auth.BeginPasskeyRegistrationRequest\x1a&.auth.BeginPasskeyRegistrationResponse\x12l\n" +
+	"\x19FinishPasskeyRegistration\x12&.auth.FinishPasskeyRegistrationRequest\x1a'.auth.FinishPasskeyRegistrationResponse\x12E\n" +
+	"\fListPasskeys\x12\x19.auth.ListPasskeysRequest\x1a\x1a.auth.ListPasskeysResponse\x12H\n" +
+	"\rDeletePasskey\x12\x1a.auth.DeletePasskeyRequest\x1a\x1b.auth.DeletePasskeyResponse\x12T\n" +
+	"\x11BeginPasskeyLogin\x12\x1e.auth.BeginPasskeyLoginRequest\x1a\x1f.auth.BeginPasskeyLoginResponse\x12W\n" +
+	"\x12FinishPasskeyLogin\x12\x1f.auth.FinishPasskeyLoginRequest\x1a .auth.FinishPasskeyLoginResponse\x12i\n" +
+	"\x18BeginSecondFactorPasskey\x12%.auth.BeginSecondFactorPasskeyRequest\x1a&.auth.BeginSecondFactorPasskeyResponse\x12<\n" +
 	"\tSetupKeys\x12\x16.auth.SetupKeysRequest\x1a\x17.auth.SetupKeysResponse\x12<\n" +
 	"\tGetMyKeys\x12\x16.auth.GetMyKeysRequest\x1a\x17.auth.GetMyKeysResponse\x12H\n" +
-	"\rGetPublicKeys\x12\x1a.auth.GetPublicKeysRequest\x1a\x1b.auth.GetPublicKeysResponseB\rZ\vproto/auth/b\x06proto3"
+	"\rGetPublicKeys\x12\x1a.auth.GetPublicKeysRequest\x1a\x1b.auth.GetPublicKeysResponse\x124\n" +
+	"\n" +
+	"GetProfile\x12\x17.auth.GetProfileRequest\x1a\r.auth.Profile\x12:\n" +
+	"\rUpdateProfile\x12\x1a.auth.UpdateProfileRequest\x1a\r.auth.Profile\x12K\n" +
+	"\x0eChangeUsername\x12\x1b.auth.ChangeUsernameRequest\x1a\x1c.auth.ChangeUsernameResponse\x12L\n" +
+	"\x12GetPrivacySettings\x12\x1f.auth.GetPrivacySettingsRequest\x1a\x15.auth.PrivacySettings\x12R\n" +
+	"\x15UpdatePrivacySettings\x12\".auth.UpdatePrivacySettingsRequest\x1a\x15.auth.PrivacySettingsB\rZ\vproto/auth/b\x06proto3"
 
 var (
 	file_proto_auth_auth_proto_rawDescOnce sync.Once
@@ -1055,55 +3791,154 @@ func file_proto_auth_auth_proto_rawDescGZIP() []byte {
 	return file_proto_auth_auth_proto_rawDescData
 }
 
-var file_proto_auth_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_proto_auth_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 68)
 var file_proto_auth_auth_proto_goTypes = []any{
-	(*LoginRequest)(nil),                // 0: auth.LoginRequest
-	(*LoginResponse)(nil),               // 1: auth.LoginResponse
-	(*SignupRequest)(nil),               // 2: auth.SignupRequest
-	(*SignupResponse)(nil),              // 3: auth.SignupResponse
-	(*SearchUsersRequest)(nil),          // 4: auth.SearchUsersRequest
-	(*UserResult)(nil),                  // 5: auth.UserResult
-	(*SearchUsersResponse)(nil),         // 6: auth.SearchUsersResponse
-	(*GetGithubOAuthURLRequest)(nil),    // 7: auth.GetGithubOAuthURLRequest
-	(*GetGithubOAuthURLResponse)(nil),   // 8: auth.GetGithubOAuthURLResponse
-	(*GithubOAuthCallbackRequest)(nil),  // 9: auth.GithubOAuthCallbackRequest
-	(*GithubOAuthCallbackResponse)(nil), // 10: auth.GithubOAuthCallbackResponse
-	(*ExchangeTokenRequest)(nil),        // 11: auth.ExchangeTokenRequest
-	(*ExchangeTokenResponse)(nil),       // 12: auth.ExchangeTokenResponse
-	(*SetupKeysRequest)(nil),            // 13: auth.SetupKeysRequest
-	(*SetupKeysResponse)(nil),           // 14: auth.SetupKeysResponse
-	(*GetMyKeysRequest)(nil),            // 15: auth.GetMyKeysRequest
-	(*GetMyKeysResponse)(nil),           // 16: auth.GetMyKeysResponse
-	(*GetPublicKeysRequest)(nil),        // 17: auth.GetPublicKeysRequest
-	(*PublicKeyEntry)(nil),              // 18: auth.PublicKeyEntry
-	(*GetPublicKeysResponse)(nil),       // 19: auth.GetPublicKeysResponse
+	(*LoginRequest)(nil),                      // 0: auth.LoginRequest
+	(*LoginResponse)(nil),                     // 1: auth.LoginResponse
+	(*SignupRequest)(nil),                     // 2: auth.SignupRequest
+	(*SignupResponse)(nil),                    // 3: auth.SignupResponse
+	(*SearchUsersRequest)(nil),                // 4: auth.SearchUsersRequest
+	(*UserResult)(nil),                        // 5: auth.UserResult
+	(*SearchUsersResponse)(nil),               // 6: auth.SearchUsersResponse
+	(*GetOAuthURLRequest)(nil),                // 7: auth.GetOAuthURLRequest
+	(*GetOAuthURLResponse)(nil),               // 8: auth.GetOAuthURLResponse
+	(*OAuthCallbackRequest)(nil),              // 9: auth.OAuthCallbackRequest
+	(*OAuthCallbackResponse)(nil),             // 10: auth.OAuthCallbackResponse
+	(*ExchangeTokenRequest)(nil),              // 11: auth.ExchangeTokenRequest
+	(*ExchangeTokenResponse)(nil),             // 12: auth.ExchangeTokenResponse
+	(*RefreshTokenRequest)(nil),               // 13: auth.RefreshTokenRequest
+	(*RefreshTokenResponse)(nil),              // 14: auth.RefreshTokenResponse
+	(*LogoutRequest)(nil),                     // 15: auth.LogoutRequest
+	(*LogoutResponse)(nil),                    // 16: auth.LogoutResponse
+	(*LoginSession)(nil),                      // 17: auth.LoginSession
+	(*ListSessionsRequest)(nil),               // 18: auth.ListSessionsRequest
+	(*ListSessionsResponse)(nil),              // 19: auth.ListSessionsResponse
+	(*RevokeSessionRequest)(nil),              // 20: auth.RevokeSessionRequest
+	(*RevokeSessionResponse)(nil),             // 21: auth.RevokeSessionResponse
+	(*ChangePasswordRequest)(nil),             // 22: auth.ChangePasswordRequest
+	(*ChangePasswordResponse)(nil),            // 23: auth.ChangePasswordResponse
+	(*SetRecoveryEmailRequest)(nil),           // 24: auth.SetRecoveryEmailRequest
+	(*SetRecoveryEmailResponse)(nil),          // 25: auth.SetRecoveryEmailResponse
+	(*RequestPasswordResetRequest)(nil),       // 26: auth.RequestPasswordResetRequest
+	(*RequestPasswordResetResponse)(nil),      // 27: auth.RequestPasswordResetResponse
+	(*ResetPasswordRequest)(nil),              // 28: auth.ResetPasswordRequest
+	(*ResetPasswordResponse)(nil),             // 29: auth.ResetPasswordResponse
+	(*EnrollTOTPRequest)(nil),                 // 30: auth.EnrollTOTPRequest
+	(*EnrollTOTPResponse)(nil),                // 31: auth.EnrollTOTPResponse
+	(*ConfirmTOTPRequest)(nil),                // 32: auth.ConfirmTOTPRequest
+	(*ConfirmTOTPResponse)(nil),               // 33: auth.ConfirmTOTPResponse
+	(*DisableTOTPRequest)(nil),                // 34: auth.DisableTOTPRequest
+	(*DisableTOTPResponse)(nil),               // 35: auth.DisableTOTPResponse
+	(*VerifySecondFactorRequest)(nil),         // 36: auth.VerifySecondFactorRequest
+	(*VerifySecondFactorResponse)(nil),        // 37: auth.VerifySecondFactorResponse
+	(*Passkey)(nil),                           // 38: auth.Passkey
+	(*BeginPasskeyRegistrationRequest)(nil),   // 39: auth.BeginPasskeyRegistrationRequest
+	(*BeginPasskeyRegistrationResponse)(nil),  // 40: auth.BeginPasskeyRegistrationResponse
+	(*FinishPasskeyRegistrationRequest)(nil),  // 41: auth.FinishPasskeyRegistrationRequest
+	(*FinishPasskeyRegistrationResponse)(nil), // 42: auth.FinishPasskeyRegistrationResponse
+	(*ListPasskeysRequest)(nil),               // 43: auth.ListPasskeysRequest
+	(*ListPasskeysResponse)(nil),              // 44: auth.ListPasskeysResponse
+	(*DeletePasskeyRequest)(nil),              // 45: auth.DeletePasskeyRequest
+	(*DeletePasskeyResponse)(nil),             // 46: auth.DeletePasskeyResponse
+	(*BeginPasskeyLoginRequest)(nil),          // 47: auth.BeginPasskeyLoginRequest
+	(*BeginPasskeyLoginResponse)(nil),         // 48: auth.BeginPasskeyLoginResponse
+	(*FinishPasskeyLoginRequest)(nil),         // 49: auth.FinishPasskeyLoginRequest
+	(*FinishPasskeyLoginResponse)(nil),        // 50: auth.FinishPasskeyLoginResponse
+	(*BeginSecondFactorPasskeyRequest)(nil),   // 51: auth.BeginSecondFactorPasskeyRequest
+	(*BeginSecondFactorPasskeyResponse)(nil),  // 52: auth.BeginSecondFactorPasskeyResponse
+	(*SetupKeysRequest)(nil),                  // 53: auth.SetupKeysRequest
+	(*SetupKeysResponse)(nil),                 // 54: auth.SetupKeysResponse
+	(*GetMyKeysRequest)(nil),                  // 55: auth.GetMyKeysRequest
+	(*GetMyKeysResponse)(nil),                 // 56: auth.GetMyKeysResponse
+	(*GetPublicKeysRequest)(nil),              // 57: auth.GetPublicKeysRequest
+	(*PublicKeyEntry)(nil),                    // 58: auth.PublicKeyEntry
+	(*GetPublicKeysResponse)(nil),             // 59: auth.GetPublicKeysResponse
+	(*Profile)(nil),                           // 60: auth.Profile
+	(*GetProfileRequest)(nil),                 // 61: auth.GetProfileRequest
+	(*UpdateProfileRequest)(nil),              // 62: auth.UpdateProfileRequest
+	(*ChangeUsernameRequest)(nil),             // 63: auth.ChangeUsernameRequest
+	(*ChangeUsernameResponse)(nil),            // 64: auth.ChangeUsernameResponse
+	(*PrivacySettings)(nil),                   // 65: auth.PrivacySettings
+	(*GetPrivacySettingsRequest)(nil),         // 66: auth.GetPrivacySettingsRequest
+	(*UpdatePrivacySettingsRequest)(nil),      // 67: auth.UpdatePrivacySettingsRequest
 }
 var file_proto_auth_auth_proto_depIdxs = []int32{
 	5,  // 0: auth.SearchUsersResponse.users:type_name -> auth.UserResult
-	18, // 1: auth.GetPublicKeysResponse.keys:type_name -> auth.PublicKeyEntry
-	0,  // 2: auth.Auth.Login:input_type -> auth.LoginRequest
-	2,  // 3: auth.Auth.Signup:input_type -> auth.SignupRequest
-	4,  // 4: auth.Auth.SearchUsers:input_type -> auth.SearchUsersRequest
-	7,  // 5: auth.Auth.GetGithubOAuthURL:input_type -> auth.GetGithubOAuthURLRequest
-	9,  // 6: auth.Auth.GithubOAuthCallback:input_type -> auth.GithubOAuthCallbackRequest
-	11, // 7: auth.Auth.ExchangeToken:input_type -> auth.ExchangeTokenRequest
-	13, // 8: auth.Auth.SetupKeys:input_type -> auth.SetupKeysRequest
-	15, // 9: auth.Auth.GetMyKeys:input_type -> auth.GetMyKeysRequest
-	17, // 10: auth.Auth.GetPublicKeys:input_type -> auth.GetPublicKeysRequest
-	1,  // 11: auth.Auth.Login:output_type -> auth.LoginResponse
-	3,  // 12: auth.Auth.Signup:output_type -> auth.SignupResponse
-	6,  // 13: auth.Auth.SearchUsers:output_type -> auth.SearchUsersResponse
-	8,  // 14: auth.Auth.GetGithubOAuthURL:output_type -> auth.GetGithubOAuthURLResponse
-	10, // 15: auth.Auth.GithubOAuthCallback:output_type -> auth.GithubOAuthCallbackResponse
-	12, // 16: auth.Auth.ExchangeToken:output_type -> auth.ExchangeTokenResponse
-	14, // 17: auth.Auth.SetupKeys:output_type -> auth.SetupKeysResponse
-	16, // 18: auth.Auth.GetMyKeys:output_type -> auth.GetMyKeysResponse
-	19, // 19: auth.Auth.GetPublicKeys:output_type -> auth.GetPublicKeysResponse
-	11, // [11:20] is the sub-list for method output_type
-	2,  // [2:11] is the sub-list for method input_type
-	2,  // [2:2] is the sub-list for extension type_name
-	2,  // [2:2] is the sub-list for extension extendee
-	0,  // [0:2] is the sub-list for field type_name
+	17, // 1: auth.ListSessionsResponse.sessions:type_name -> auth.LoginSession
+	38, // 2: auth.FinishPasskeyRegistrationResponse.passkey:type_name -> auth.Passkey
+	38, // 3: auth.ListPasskeysResponse.passkeys:type_name -> auth.Passkey
+	58, // 4: auth.GetPublicKeysResponse.keys:type_name -> auth.PublicKeyEntry
+	0,  // 5: auth.Auth.Login:input_type -> auth.LoginRequest
+	2,  // 6: auth.Auth.Signup:input_type -> auth.SignupRequest
+	4,  // 7: auth.Auth.SearchUsers:input_type -> auth.SearchUsersRequest
+	7,  // 8: auth.Auth.GetOAuthURL:input_type -> auth.GetOAuthURLRequest
+	9,  // 9: auth.Auth.OAuthCallback:input_type -> auth.OAuthCallbackRequest
+	11, // 10: auth.Auth.ExchangeToken:input_type -> auth.ExchangeTokenRequest
+	13, // 11: auth.Auth.RefreshToken:input_type -> auth.RefreshTokenRequest
+	15, // 12: auth.Auth.Logout:input_type -> auth.LogoutRequest
+	18, // 13: auth.Auth.ListSessions:input_type -> auth.ListSessionsRequest
+	20, // 14: auth.Auth.RevokeSession:input_type -> auth.RevokeSessionRequest
+	22, // 15: auth.Auth.ChangePassword:input_type -> auth.ChangePasswordRequest
+	24, // 16: auth.Auth.SetRecoveryEmail:input_type -> auth.SetRecoveryEmailRequest
+	26, // 17: auth.Auth.RequestPasswordReset:input_type -> auth.RequestPasswordResetRequest
+	28, // 18: auth.Auth.ResetPassword:input_type -> auth.ResetPasswordRequest
+	30, // 19: auth.Auth.EnrollTOTP:input_type -> auth.EnrollTOTPRequest
+	32, // 20: auth.Auth.ConfirmTOTP:input_type -> auth.ConfirmTOTPRequest
+	34, // 21: auth.Auth.DisableTOTP:input_type -> auth.DisableTOTPRequest
+	36, // 22: auth.Auth.VerifySecondFactor:input_type -> auth.VerifySecondFactorRequest
+	39, // 23: auth.Auth.BeginPasskeyRegistration:input_type -> auth.BeginPasskeyRegistrationRequest
+	41, // 24: auth.Auth.FinishPasskeyRegistration:input_type -> auth.FinishPasskeyRegistrationRequest
+	43, // 25: auth.Auth.ListPasskeys:input_type -> auth.ListPasskeysRequest
+	45, // 26: auth.Auth.DeletePasskey:input_type -> auth.DeletePasskeyRequest
+	47, // 27: auth.Auth.BeginPasskeyLogin:input_type -> auth.BeginPasskeyLoginRequest
+	49, // 28: auth.Auth.FinishPasskeyLogin:input_type -> auth.FinishPasskeyLoginRequest
+	51, // 29: auth.Auth.BeginSecondFactorPasskey:input_type -> auth.BeginSecondFactorPasskeyRequest
+	53, // 30: auth.Auth.SetupKeys:input_type -> auth.SetupKeysRequest
+	55, // 31: auth.Auth.GetMyKeys:input_type -> auth.GetMyKeysRequest
+	57, // 32: auth.Auth.GetPublicKeys:input_type -> auth.GetPublicKeysRequest
+	61, // 33: auth.Auth.GetProfile:input_type -> auth.GetProfileRequest
+	62, // 34: auth.Auth.UpdateProfile:input_type -> auth.UpdateProfileRequest
+	63, // 35: auth.Auth.ChangeUsername:input_type -> auth.ChangeUsernameRequest
+	66, // 36: auth.Auth.GetPrivacySettings:input_type -> auth.GetPrivacySettingsRequest
+	67, // 37: auth.Auth.UpdatePrivacySettings:input_type -> auth.UpdatePrivacySettingsRequest
+	1,  // 38: auth.Auth.Login:output_type -> auth.LoginResponse
+	3,  // 39: auth.Auth.Signup:output_type -> auth.SignupResponse
+	6,  // 40: auth.Auth.SearchUsers:output_type -> auth.SearchUsersResponse
+	8,  // 41: auth.Auth.GetOAuthURL:output_type -> auth.GetOAuthURLResponse
+	10, // 42: auth.Auth.OAuthCallback:output_type -> auth.OAuthCallbackResponse
+	12, // 43: auth.Auth.ExchangeToken:output_type -> auth.ExchangeTokenResponse
+	14, // 44: auth.Auth.RefreshToken:output_type -> auth.RefreshTokenResponse
+	16, // 45: auth.Auth.Logout:output_type -> auth.LogoutResponse
+	19, // 46: auth.Auth.ListSessions:output_type -> auth.ListSessionsResponse
+	21, // 47: auth.Auth.RevokeSession:output_type -> auth.RevokeSessionResponse
+	23, // 48: auth.Auth.ChangePassword:output_type -> auth.ChangePasswordResponse
+	25, // 49: auth.Auth.SetRecoveryEmail:output_type -> auth.SetRecoveryEmailResponse
+	27, // 50: auth.Auth.RequestPasswordReset:output_type -> auth.RequestPasswordResetResponse
+	29, // 51: auth.Auth.ResetPassword:output_type -> auth.ResetPasswordResponse
+	31, // 52: auth.Auth.EnrollTOTP:output_type -> auth.EnrollTOTPResponse
+	33, // 53: auth.Auth.ConfirmTOTP:output_type -> auth.ConfirmTOTPResponse
+	35, // 54: auth.Auth.DisableTOTP:output_type -> auth.DisableTOTPResponse
+	37, // 55: auth.Auth.VerifySecondFactor:output_type -> auth.VerifySecondFactorResponse
+	40, // 56: auth.Auth.BeginPasskeyRegistration:output_type -> auth.BeginPasskeyRegistrationResponse
+	42, // 57: auth.Auth.FinishPasskeyRegistration:output_type -> auth.FinishPasskeyRegistrationResponse
+	44, // 58: auth.Auth.ListPasskeys:output_type -> auth.ListPasskeysResponse
+	46, // 59: auth.Auth.DeletePasskey:output_type -> auth.DeletePasskeyResponse
+	48, // 60: auth.Auth.BeginPasskeyLogin:output_type -> auth.BeginPasskeyLoginResponse
+	50, // 61: auth.Auth.FinishPasskeyLogin:output_type -> auth.FinishPasskeyLoginResponse
+	52, // 62: auth.Auth.BeginSecondFactorPasskey:output_type -> auth.BeginSecondFactorPasskeyResponse
+	54, // 63: auth.Auth.SetupKeys:output_type -> auth.SetupKeysResponse
+	56, // 64: auth.Auth.GetMyKeys:output_type -> auth.GetMyKeysResponse
+	59, // 65: auth.Auth.GetPublicKeys:output_type -> auth.GetPublicKeysResponse
+	60, // 66: auth.Auth.GetProfile:output_type -> auth.Profile
+	60, // 67: auth.Auth.UpdateProfile:output_type -> auth.Profile
+	64, // 68: auth.Auth.ChangeUsername:output_type -> auth.ChangeUsernameResponse
+	65, // 69: auth.Auth.GetPrivacySettings:output_type -> auth.PrivacySettings
+	65, // 70: auth.Auth.UpdatePrivacySettings:output_type -> auth.PrivacySettings
+	38, // [38:71] is the sub-list for method output_type
+	5,  // [5:38] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_proto_auth_auth_proto_init() }
@@ -1111,13 +3946,15 @@ func file_proto_auth_auth_proto_init() {
 	if File_proto_auth_auth_proto != nil {
 		return
 	}
+	file_proto_auth_auth_proto_msgTypes[62].OneofWrappers = []any{}
+	file_proto_auth_auth_proto_msgTypes[67].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_auth_auth_proto_rawDesc), len(file_proto_auth_auth_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   20,
+			NumMessages:   68,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
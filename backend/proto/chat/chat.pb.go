@@ -26,6 +26,7 @@ type CreateConversationRequest struct {
 	IsGroup         bool                   `protobuf:"varint,1,opt,name=is_group,json=isGroup,proto3" json:"is_group,omitempty"`
 	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	MembersUsername []string               `protobuf:"bytes,3,rep,name=members_username,json=membersUsername,proto3" json:"members_username,omitempty"`
+	LabelId         int64                  `protobuf:"varint,4,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"` // adds the caller's friends in this label to members_username
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -81,6 +82,13 @@ func (x *CreateConversationRequest) GetMembersUsername() []string {
 	return nil
 }
 
+func (x *CreateConversationRequest) GetLabelId() int64 {
+	if x != nil {
+		return x.LabelId
+	}
+	return 0
+}
+
 type CreateConversationResponse struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	ConversationId int64                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
@@ -132,8 +140,12 @@ type SendMessageRequest struct {
 	Content          string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
 	MessageType      string                 `protobuf:"bytes,3,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"` // "text" | "image" | "file" | "audio" (default: "text")
 	ReplyToMessageId *string                `protobuf:"bytes,4,opt,name=reply_to_message_id,json=replyToMessageId,proto3,oneof" json:"reply_to_message_id,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// Blind-index keyword tokens computed by the client (keyed HMAC over the
+	// normalised plaintext keywords, using the conversation's search key).
+	// The server stores them opaquely for SearchMessages. Max 32, each 1-128 chars.
+	SearchTokens  []string `protobuf:"bytes,6,rep,name=search_tokens,json=searchTokens,proto3" json:"search_tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SendMessageRequest) Reset() {
@@ -201,6 +213,13 @@ func (x *SendMessageRequest) GetReplyToMessageId() string {
 	return ""
 }
 
+func (x *SendMessageRequest) GetSearchTokens() []string {
+	if x != nil {
+		return x.SearchTokens
+	}
+	return nil
+}
+
 type SendMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
@@ -249,11 +268,12 @@ type Message struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	MessageId        string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
 	SenderId         string                 `protobuf:"bytes,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
-	Content          string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
-	MessageType      string                 `protobuf:"bytes,4,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	Content          string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`                                               // ciphertext; for "system" messages a plaintext JSON event
+	MessageType      string                 `protobuf:"bytes,4,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`                    // "text" | "image" | "file" | "audio" | "system"
 	ReplyToMessageId string                 `protobuf:"bytes,5,opt,name=reply_to_message_id,json=replyToMessageId,proto3" json:"reply_to_message_id,omitempty"` // empty if no reply
 	IsEdited         bool                   `protobuf:"varint,6,opt,name=is_edited,json=isEdited,proto3" json:"is_edited,omitempty"`
 	CreatedAt        string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ConversationId   int64                  `protobuf:"varint,8,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -337,6 +357,13 @@ func (x *Message) GetCreatedAt() string {
 	return ""
 }
 
+func (x *Message) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
 // GetMessagesRequest fetches messages using cursor-based pagination.
 // limit defaults to 50 on the server side if not set (max 100).
 // Pass next_cursor from a previous response to fetch the next page.
@@ -606,6 +633,9 @@ type ConversationMember struct {
 	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
 	DisplayName   string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
 	AvatarUrl     string                 `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	Role          string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"` // "member" | "admin" | "owner"
+	JoinedAt      string                 `protobuf:"bytes,6,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
+	IsE2EeReady   bool                   `protobuf:"varint,7,opt,name=is_e2ee_ready,json=isE2eeReady,proto3" json:"is_e2ee_ready,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -668,6 +698,27 @@ func (x *ConversationMember) GetAvatarUrl() string {
 	return ""
 }
 
+func (x *ConversationMember) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ConversationMember) GetJoinedAt() string {
+	if x != nil {
+		return x.JoinedAt
+	}
+	return ""
+}
+
+func (x *ConversationMember) GetIsE2EeReady() bool {
+	if x != nil {
+		return x.IsE2EeReady
+	}
+	return false
+}
+
 type ConversationResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -747,6 +798,7 @@ func (x *ConversationResult) GetMembers() []*ConversationMember {
 type GetConversationsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Conversations []*ConversationResult  `protobuf:"bytes,1,rep,name=conversations,proto3" json:"conversations,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // GetConversationsByName only; empty when there are no more results
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -788,6 +840,13 @@ func (x *GetConversationsResponse) GetConversations() []*ConversationResult {
 	return nil
 }
 
+func (x *GetConversationsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 type GetConversationsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -827,6 +886,8 @@ func (*GetConversationsRequest) Descriptor() ([]byte, []int) {
 type GetConversationsByNameRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`  // default 20, max 50
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor from the previous page; empty for the first page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -868,82 +929,1290 @@ func (x *GetConversationsByNameRequest) GetName() string {
 	return ""
 }
 
-var File_chat_proto protoreflect.FileDescriptor
+func (x *GetConversationsByNameRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
 
-const file_chat_proto_rawDesc = "" +
-	"\n" +
-	"\n" +
-	"chat.proto\x12\x04chat\"u\n" +
-	"\x19CreateConversationRequest\x12\x19\n" +
-	"\bis_group\x18\x01 \x01(\bR\aisGroup\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12)\n" +
-	"\x10members_username\x18\x03 \x03(\tR\x0fmembersUsername\"E\n" +
-	"\x1aCreateConversationResponse\x12'\n" +
-	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\"\xe5\x01\n" +
-	"\x12SendMessageRequest\x12'\n" +
-	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x1d\n" +
-	"\n" +
-	"message_id\x18\x05 \x01(\tR\tmessageId\x12\x18\n" +
-	"\acontent\x18\x02 \x01(\tR\acontent\x12!\n" +
-	"\fmessage_type\x18\x03 \x01(\tR\vmessageType\x122\n" +
-	"\x13reply_to_message_id\x18\x04 \x01(\tH\x00R\x10replyToMessageId\x88\x01\x01B\x16\n" +
-	"\x14_reply_to_message_id\"4\n" +
-	"\x13SendMessageResponse\x12\x1d\n" +
-	"\n" +
-	"message_id\x18\x01 \x01(\tR\tmessageId\"\xed\x01\n" +
-	"\aMessage\x12\x1d\n" +
-	"\n" +
-	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x1b\n" +
-	"\tsender_id\x18\x02 \x01(\tR\bsenderId\x12\x18\n" +
-	"\acontent\x18\x03 \x01(\tR\acontent\x12!\n" +
-	"\fmessage_type\x18\x04 \x01(\tR\vmessageType\x12-\n" +
-	"\x13reply_to_message_id\x18\x05 \x01(\tR\x10replyToMessageId\x12\x1b\n" +
-	"\tis_edited\x18\x06 \x01(\bR\bisEdited\x12\x1d\n" +
-	"\n" +
-	"created_at\x18\a \x01(\tR\tcreatedAt\"k\n" +
-	"\x12GetMessagesRequest\x12'\n" +
-	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"a\n" +
-	"\x13GetMessagesResponse\x12)\n" +
-	"\bmessages\x18\x01 \x03(\v2\r.chat.MessageR\bmessages\x12\x1f\n" +
-	"\vnext_cursor\x18\x02 \x01(\tR\n" +
-	"nextCursor\"f\n" +
-	"\x1cUpdateLastReadMessageRequest\x12'\n" +
-	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x1d\n" +
-	"\n" +
-	"message_id\x18\x02 \x01(\tR\tmessageId\"w\n" +
-	"\x14UpdateMessageRequest\x12'\n" +
-	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x1d\n" +
-	"\n" +
-	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x17\n" +
-	"\auser_id\x18\x03 \x01(\tR\x06userId\"\x17\n" +
-	"\x15UpdateMessageResponse\"\x8b\x01\n" +
-	"\x12ConversationMember\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\x12!\n" +
-	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1d\n" +
-	"\n" +
-	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\"\xa6\x01\n" +
-	"\x12ConversationResult\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
-	"\bis_group\x18\x02 \x01(\bR\aisGroup\x12\x12\n" +
-	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1d\n" +
-	"\n" +
-	"updated_at\x18\x04 \x01(\tR\tupdatedAt\x122\n" +
-	"\amembers\x18\x05 \x03(\v2\x18.chat.ConversationMemberR\amembers\"Z\n" +
-	"\x18GetConversationsResponse\x12>\n" +
-	"\rconversations\x18\x01 \x03(\v2\x18.chat.ConversationResultR\rconversations\"\x19\n" +
-	"\x17GetConversationsRequest\"3\n" +
-	"\x1dGetConversationsByNameRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name2\xfe\x03\n" +
-	"\x04Chat\x12W\n" +
-	"\x12CreateConversation\x12\x1f.chat.CreateConversationRequest\x1a .chat.CreateConversationResponse\x12B\n" +
-	"\vSendMessage\x12\x18.chat.SendMessageRequest\x1a\x19.chat.SendMessageResponse\x12P\n" +
-	"\x15UpdateLastReadMessage\x12\x1a.chat.UpdateMessageRequest\x1a\x1b.chat.UpdateMessageResponse\x12U\n" +
-	"\x1aUpdateLastDeliveredMessage\x12\x1a.chat.UpdateMessageRequest\x1a\x1b.chat.UpdateMessageResponse\x12Q\n" +
-	"\x10GetConversations\x12\x1d.chat.GetConversationsRequest\x1a\x1e.chat.GetConversationsResponse\x12]\n" +
-	"\x16GetConversationsByName\x12#.chat.GetConversationsByNameRequest\x1a\x1e.chat.GetConversationsResponseB\rZ\vproto/chat/b\x06proto3"
+func (x *GetConversationsByNameRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type GetConversationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId int64                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetConversationRequest) Reset() {
+	*x = GetConversationRequest{}
+	mi := &file_chat_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConversationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConversationRequest) ProtoMessage() {}
+
+func (x *GetConversationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConversationRequest.ProtoReflect.Descriptor instead.
+func (*GetConversationRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetConversationRequest) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+// ConversationSettings holds the caller's own membership state in a conversation.
+type ConversationSettings struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Role                   string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	JoinedAt               string                 `protobuf:"bytes,2,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
+	LastReadMessageId      string                 `protobuf:"bytes,3,opt,name=last_read_message_id,json=lastReadMessageId,proto3" json:"last_read_message_id,omitempty"`                // empty if nothing read yet
+	LastDeliveredMessageId string                 `protobuf:"bytes,4,opt,name=last_delivered_message_id,json=lastDeliveredMessageId,proto3" json:"last_delivered_message_id,omitempty"` // empty if nothing delivered yet
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *ConversationSettings) Reset() {
+	*x = ConversationSettings{}
+	mi := &file_chat_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversationSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversationSettings) ProtoMessage() {}
+
+func (x *ConversationSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversationSettings.ProtoReflect.Descriptor instead.
+func (*ConversationSettings) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ConversationSettings) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ConversationSettings) GetJoinedAt() string {
+	if x != nil {
+		return x.JoinedAt
+	}
+	return ""
+}
+
+func (x *ConversationSettings) GetLastReadMessageId() string {
+	if x != nil {
+		return x.LastReadMessageId
+	}
+	return ""
+}
+
+func (x *ConversationSettings) GetLastDeliveredMessageId() string {
+	if x != nil {
+		return x.LastDeliveredMessageId
+	}
+	return ""
+}
+
+type GetConversationResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	IsGroup         bool                   `protobuf:"varint,2,opt,name=is_group,json=isGroup,proto3" json:"is_group,omitempty"`
+	Name            string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt       string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Members         []*ConversationMember  `protobuf:"bytes,6,rep,name=members,proto3" json:"members,omitempty"`
+	Settings        *ConversationSettings  `protobuf:"bytes,7,opt,name=settings,proto3" json:"settings,omitempty"`
+	SlowModeSeconds int32                  `protobuf:"varint,8,opt,name=slow_mode_seconds,json=slowModeSeconds,proto3" json:"slow_mode_seconds,omitempty"` // 0 when slow mode is off
+	ReadOnly        bool                   `protobuf:"varint,9,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`                        // DM whose members are no longer friends; history only
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetConversationResponse) Reset() {
+	*x = GetConversationResponse{}
+	mi := &file_chat_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConversationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConversationResponse) ProtoMessage() {}
+
+func (x *GetConversationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConversationResponse.ProtoReflect.Descriptor instead.
+func (*GetConversationResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetConversationResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetConversationResponse) GetIsGroup() bool {
+	if x != nil {
+		return x.IsGroup
+	}
+	return false
+}
+
+func (x *GetConversationResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetConversationResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *GetConversationResponse) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *GetConversationResponse) GetMembers() []*ConversationMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *GetConversationResponse) GetSettings() *ConversationSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *GetConversationResponse) GetSlowModeSeconds() int32 {
+	if x != nil {
+		return x.SlowModeSeconds
+	}
+	return 0
+}
+
+func (x *GetConversationResponse) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+type AddMembersRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId  int64                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	MembersUsername []string               `protobuf:"bytes,2,rep,name=members_username,json=membersUsername,proto3" json:"members_username,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AddMembersRequest) Reset() {
+	*x = AddMembersRequest{}
+	mi := &file_chat_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddMembersRequest) ProtoMessage() {}
+
+func (x *AddMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddMembersRequest.ProtoReflect.Descriptor instead.
+func (*AddMembersRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AddMembersRequest) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *AddMembersRequest) GetMembersUsername() []string {
+	if x != nil {
+		return x.MembersUsername
+	}
+	return nil
+}
+
+type RemoveMemberRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId int64                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Username       string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"` // the caller's own username leaves the conversation
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RemoveMemberRequest) Reset() {
+	*x = RemoveMemberRequest{}
+	mi := &file_chat_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveMemberRequest) ProtoMessage() {}
+
+func (x *RemoveMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveMemberRequest.ProtoReflect.Descriptor instead.
+func (*RemoveMemberRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RemoveMemberRequest) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *RemoveMemberRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type RenameConversationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId int64                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RenameConversationRequest) Reset() {
+	*x = RenameConversationRequest{}
+	mi := &file_chat_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameConversationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameConversationRequest) ProtoMessage() {}
+
+func (x *RenameConversationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameConversationRequest.ProtoReflect.Descriptor instead.
+func (*RenameConversationRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RenameConversationRequest) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *RenameConversationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SetMemberRoleRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId int64                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Username       string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Role           string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"` // "member" | "admin"
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetMemberRoleRequest) Reset() {
+	*x = SetMemberRoleRequest{}
+	mi := &file_chat_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMemberRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMemberRoleRequest) ProtoMessage() {}
+
+func (x *SetMemberRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMemberRoleRequest.ProtoReflect.Descriptor instead.
+func (*SetMemberRoleRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetMemberRoleRequest) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *SetMemberRoleRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SetMemberRoleRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+// SetSlowModeRequest sets the minimum gap between two messages from the same
+// member of a group. 0 turns slow mode off; max 21600 (6 hours).
+type SetSlowModeRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId int64                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seconds        int32                  `protobuf:"varint,2,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetSlowModeRequest) Reset() {
+	*x = SetSlowModeRequest{}
+	mi := &file_chat_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSlowModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSlowModeRequest) ProtoMessage() {}
+
+func (x *SetSlowModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSlowModeRequest.ProtoReflect.Descriptor instead.
+func (*SetSlowModeRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SetSlowModeRequest) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *SetSlowModeRequest) GetSeconds() int32 {
+	if x != nil {
+		return x.Seconds
+	}
+	return 0
+}
+
+type UpdateConversationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateConversationResponse) Reset() {
+	*x = UpdateConversationResponse{}
+	mi := &file_chat_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateConversationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateConversationResponse) ProtoMessage() {}
+
+func (x *UpdateConversationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateConversationResponse.ProtoReflect.Descriptor instead.
+func (*UpdateConversationResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{23}
+}
+
+type ClearHistoryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId int64                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ClearHistoryRequest) Reset() {
+	*x = ClearHistoryRequest{}
+	mi := &file_chat_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearHistoryRequest) ProtoMessage() {}
+
+func (x *ClearHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ClearHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ClearHistoryRequest) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+type DeleteConversationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId int64                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DeleteConversationRequest) Reset() {
+	*x = DeleteConversationRequest{}
+	mi := &file_chat_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteConversationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteConversationRequest) ProtoMessage() {}
+
+func (x *DeleteConversationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteConversationRequest.ProtoReflect.Descriptor instead.
+func (*DeleteConversationRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DeleteConversationRequest) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+type ExportConversationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId int64                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExportConversationRequest) Reset() {
+	*x = ExportConversationRequest{}
+	mi := &file_chat_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportConversationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportConversationRequest) ProtoMessage() {}
+
+func (x *ExportConversationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportConversationRequest.ProtoReflect.Descriptor instead.
+func (*ExportConversationRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ExportConversationRequest) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+// ExportChunk carries the next bytes of a newline-delimited JSON conversation
+// archive (see docs/chat_api.md). Concatenate every chunk to get the archive.
+type ExportChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportChunk) Reset() {
+	*x = ExportChunk{}
+	mi := &file_chat_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChunk) ProtoMessage() {}
+
+func (x *ExportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChunk.ProtoReflect.Descriptor instead.
+func (*ExportChunk) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ExportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type StartConversationExportRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId int64                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StartConversationExportRequest) Reset() {
+	*x = StartConversationExportRequest{}
+	mi := &file_chat_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartConversationExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartConversationExportRequest) ProtoMessage() {}
+
+func (x *StartConversationExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartConversationExportRequest.ProtoReflect.Descriptor instead.
+func (*StartConversationExportRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *StartConversationExportRequest) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+type GetConversationExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExportId      string                 `protobuf:"bytes,1,opt,name=export_id,json=exportId,proto3" json:"export_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConversationExportRequest) Reset() {
+	*x = GetConversationExportRequest{}
+	mi := &file_chat_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConversationExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConversationExportRequest) ProtoMessage() {}
+
+func (x *GetConversationExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConversationExportRequest.ProtoReflect.Descriptor instead.
+func (*GetConversationExportRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetConversationExportRequest) GetExportId() string {
+	if x != nil {
+		return x.ExportId
+	}
+	return ""
+}
+
+type ConversationExport struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ExportId       string                 `protobuf:"bytes,1,opt,name=export_id,json=exportId,proto3" json:"export_id,omitempty"`
+	ConversationId int64                  `protobuf:"varint,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Status         string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                              // "pending" | "running" | "done" | "failed"
+	DownloadUrl    string                 `protobuf:"bytes,4,opt,name=download_url,json=downloadUrl,proto3" json:"download_url,omitempty"` // set when status is "done"
+	ExpiresAt      string                 `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`       // download_url expiry; set when status is "done"
+	Error          string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`                                // set when status is "failed"
+	CreatedAt      string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ConversationExport) Reset() {
+	*x = ConversationExport{}
+	mi := &file_chat_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversationExport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversationExport) ProtoMessage() {}
+
+func (x *ConversationExport) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversationExport.ProtoReflect.Descriptor instead.
+func (*ConversationExport) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ConversationExport) GetExportId() string {
+	if x != nil {
+		return x.ExportId
+	}
+	return ""
+}
+
+func (x *ConversationExport) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *ConversationExport) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ConversationExport) GetDownloadUrl() string {
+	if x != nil {
+		return x.DownloadUrl
+	}
+	return ""
+}
+
+func (x *ConversationExport) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *ConversationExport) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ConversationExport) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type DownloadConversationExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadConversationExportRequest) Reset() {
+	*x = DownloadConversationExportRequest{}
+	mi := &file_chat_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadConversationExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadConversationExportRequest) ProtoMessage() {}
+
+func (x *DownloadConversationExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadConversationExportRequest.ProtoReflect.Descriptor instead.
+func (*DownloadConversationExportRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DownloadConversationExportRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// SearchTokenSet is the blind-index tokens to look for in one conversation.
+// Tokens differ per conversation because each has its own search key.
+type SearchTokenSet struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId int64                  `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Tokens         []string               `protobuf:"bytes,2,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SearchTokenSet) Reset() {
+	*x = SearchTokenSet{}
+	mi := &file_chat_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchTokenSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchTokenSet) ProtoMessage() {}
+
+func (x *SearchTokenSet) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchTokenSet.ProtoReflect.Descriptor instead.
+func (*SearchTokenSet) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SearchTokenSet) GetConversationId() int64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *SearchTokenSet) GetTokens() []string {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+// SearchMessagesRequest finds messages carrying every token of their
+// conversation's set. limit defaults to 50 (max 100).
+type SearchMessagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TokenSets     []*SearchTokenSet      `protobuf:"bytes,1,rep,name=token_sets,json=tokenSets,proto3" json:"token_sets,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMessagesRequest) Reset() {
+	*x = SearchMessagesRequest{}
+	mi := &file_chat_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMessagesRequest) ProtoMessage() {}
+
+func (x *SearchMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMessagesRequest.ProtoReflect.Descriptor instead.
+func (*SearchMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SearchMessagesRequest) GetTokenSets() []*SearchTokenSet {
+	if x != nil {
+		return x.TokenSets
+	}
+	return nil
+}
+
+func (x *SearchMessagesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchMessagesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type SearchMessagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*Message             `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"` // newest first
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchMessagesResponse) Reset() {
+	*x = SearchMessagesResponse{}
+	mi := &file_chat_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMessagesResponse) ProtoMessage() {}
+
+func (x *SearchMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMessagesResponse.ProtoReflect.Descriptor instead.
+func (*SearchMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *SearchMessagesResponse) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *SearchMessagesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+var File_chat_proto protoreflect.FileDescriptor
+
+const file_chat_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"chat.proto\x12\x04chat\"\x90\x01\n" +
+	"\x19CreateConversationRequest\x12\x19\n" +
+	"\bis_group\x18\x01 \x01(\bR\aisGroup\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12)\n" +
+	"\x10members_username\x18\x03 \x03(\tR\x0fmembersUsername\x12\x19\n" +
+	"\blabel_id\x18\x04 \x01(\x03R\alabelId\"E\n" +
+	"\x1aCreateConversationResponse\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\"\x8a\x02\n" +
+	"\x12SendMessageRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x05 \x01(\tR\tmessageId\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12!\n" +
+	"\fmessage_type\x18\x03 \x01(\tR\vmessageType\x122\n" +
+	"\x13reply_to_message_id\x18\x04 \x01(\tH\x00R\x10replyToMessageId\x88\x01\x01\x12#\n" +
+	"\rsearch_tokens\x18\x06 \x03(\tR\fsearchTokensB\x16\n" +
+	"\x14_reply_to_message_id\"4\n" +
+	"\x13SendMessageResponse\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\"\x96\x02\n" +
+	"\aMessage\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x1b\n" +
+	"\tsender_id\x18\x02 \x01(\tR\bsenderId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12!\n" +
+	"\fmessage_type\x18\x04 \x01(\tR\vmessageType\x12-\n" +
+	"\x13reply_to_message_id\x18\x05 \x01(\tR\x10replyToMessageId\x12\x1b\n" +
+	"\tis_edited\x18\x06 \x01(\bR\bisEdited\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12'\n" +
+	"\x0fconversation_id\x18\b \x01(\x03R\x0econversationId\"k\n" +
+	"\x12GetMessagesRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"a\n" +
+	"\x13GetMessagesResponse\x12)\n" +
+	"\bmessages\x18\x01 \x03(\v2\r.chat.MessageR\bmessages\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"f\n" +
+	"\x1cUpdateLastReadMessageRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\"w\n" +
+	"\x14UpdateMessageRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\"\x17\n" +
+	"\x15UpdateMessageResponse\"\xe0\x01\n" +
+	"\x12ConversationMember\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12\x12\n" +
+	"\x04role\x18\x05 \x01(\tR\x04role\x12\x1b\n" +
+	"\tjoined_at\x18\x06 \x01(\tR\bjoinedAt\x12\"\n" +
+	"\ris_e2ee_ready\x18\a \x01(\bR\visE2eeReady\"\xa6\x01\n" +
+	"\x12ConversationResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bis_group\x18\x02 \x01(\bR\aisGroup\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\tR\tupdatedAt\x122\n" +
+	"\amembers\x18\x05 \x03(\v2\x18.chat.ConversationMemberR\amembers\"{\n" +
+	"\x18GetConversationsResponse\x12>\n" +
+	"\rconversations\x18\x01 \x03(\v2\x18.chat.ConversationResultR\rconversations\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\x19\n" +
+	"\x17GetConversationsRequest\"a\n" +
+	"\x1dGetConversationsByNameRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"A\n" +
+	"\x16GetConversationRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\"\xb3\x01\n" +
+	"\x14ConversationSettings\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x1b\n" +
+	"\tjoined_at\x18\x02 \x01(\tR\bjoinedAt\x12/\n" +
+	"\x14last_read_message_id\x18\x03 \x01(\tR\x11lastReadMessageId\x129\n" +
+	"\x19last_delivered_message_id\x18\x04 \x01(\tR\x16lastDeliveredMessageId\"\xcb\x02\n" +
+	"\x17GetConversationResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bis_group\x18\x02 \x01(\bR\aisGroup\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\x122\n" +
+	"\amembers\x18\x06 \x03(\v2\x18.chat.ConversationMemberR\amembers\x126\n" +
+	"\bsettings\x18\a \x01(\v2\x1a.chat.ConversationSettingsR\bsettings\x12*\n" +
+	"\x11slow_mode_seconds\x18\b \x01(\x05R\x0fslowModeSeconds\x12\x1b\n" +
+	"\tread_only\x18\t \x01(\bR\breadOnly\"g\n" +
+	"\x11AddMembersRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12)\n" +
+	"\x10members_username\x18\x02 \x03(\tR\x0fmembersUsername\"Z\n" +
+	"\x13RemoveMemberRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\"X\n" +
+	"\x19RenameConversationRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"o\n" +
+	"\x14SetMemberRoleRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\"W\n" +
+	"\x12SetSlowModeRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x18\n" +
+	"\aseconds\x18\x02 \x01(\x05R\aseconds\"\x1c\n" +
+	"\x1aUpdateConversationResponse\">\n" +
+	"\x13ClearHistoryRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\"D\n" +
+	"\x19DeleteConversationRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\"D\n" +
+	"\x19ExportConversationRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\"!\n" +
+	"\vExportChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"I\n" +
+	"\x1eStartConversationExportRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\";\n" +
+	"\x1cGetConversationExportRequest\x12\x1b\n" +
+	"\texport_id\x18\x01 \x01(\tR\bexportId\"\xe9\x01\n" +
+	"\x12ConversationExport\x12\x1b\n" +
+	"\texport_id\x18\x01 \x01(\tR\bexportId\x12'\n" +
+	"\x0fconversation_id\x18\x02 \x01(\x03R\x0econversationId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12!\n" +
+	"\fdownload_url\x18\x04 \x01(\tR\vdownloadUrl\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\tR\texpiresAt\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"9\n" +
+	"!DownloadConversationExportRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"Q\n" +
+	"\x0eSearchTokenSet\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x03R\x0econversationId\x12\x16\n" +
+	"\x06tokens\x18\x02 \x03(\tR\x06tokens\"z\n" +
+	"\x15SearchMessagesRequest\x123\n" +
+	"\n" +
+	"token_sets\x18\x01 \x03(\v2\x14.chat.SearchTokenSetR\ttokenSets\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"d\n" +
+	"\x16SearchMessagesResponse\x12)\n" +
+	"\bmessages\x18\x01 \x03(\v2\r.chat.MessageR\bmessages\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor2\xe8\f\n" +
+	"\x04Chat\x12W\n" +
+	"\x12CreateConversation\x12\x1f.chat.CreateConversationRequest\x1a .chat.CreateConversationResponse\x12B\n" +
+	"\vSendMessage\x12\x18.chat.SendMessageRequest\x1a\x19.chat.SendMessageResponse\x12P\n" +
+	"\x15UpdateLastReadMessage\x12\x1a.chat.UpdateMessageRequest\x1a\x1b.chat.UpdateMessageResponse\x12U\n" +
+	"\x1aUpdateLastDeliveredMessage\x12\x1a.chat.UpdateMessageRequest\x1a\x1b.chat.UpdateMessageResponse\x12Q\n" +
+	"\x10GetConversations\x12\x1d.chat.GetConversationsRequest\x1a\x1e.chat.GetConversationsResponse\x12]\n" +
+	"\x16GetConversationsByName\x12#.chat.GetConversationsByNameRequest\x1a\x1e.chat.GetConversationsResponse\x12N\n" +
+	"\x0fGetConversation\x12\x1c.chat.GetConversationRequest\x1a\x1d.chat.GetConversationResponse\x12B\n" +
+	"\vGetMessages\x12\x18.chat.GetMessagesRequest\x1a\x19.chat.GetMessagesResponse\x12G\n" +
+	"\n" +
+	"AddMembers\x12\x17.chat.AddMembersRequest\x1a .chat.UpdateConversationResponse\x12K\n" +
+	"\fRemoveMember\x12\x19.chat.RemoveMemberRequest\x1a .chat.UpdateConversationResponse\x12W\n" +
+	"\x12RenameConversation\x12\x1f.chat.RenameConversationRequest\x1a .chat.UpdateConversationResponse\x12M\n" +
+	"\rSetMemberRole\x12\x1a.chat.SetMemberRoleRequest\x1a .chat.UpdateConversationResponse\x12I\n" +
+	"\vSetSlowMode\x12\x18.chat.SetSlowModeRequest\x1a .chat.UpdateConversationResponse\x12K\n" +
+	"\fClearHistory\x12\x19.chat.ClearHistoryRequest\x1a .chat.UpdateConversationResponse\x12W\n" +
+	"\x12DeleteConversation\x12\x1f.chat.DeleteConversationRequest\x1a .chat.UpdateConversationResponse\x12J\n" +
+	"\x12ExportConversation\x12\x1f.chat.ExportConversationRequest\x1a\x11.chat.ExportChunk0\x01\x12Y\n" +
+	"\x17StartConversationExport\x12$.chat.StartConversationExportRequest\x1a\x18.chat.ConversationExport\x12U\n" +
+	"\x15GetConversationExport\x12\".chat.GetConversationExportRequest\x1a\x18.chat.ConversationExport\x12Z\n" +
+	"\x1aDownloadConversationExport\x12'.chat.DownloadConversationExportRequest\x1a\x11.chat.ExportChunk0\x01\x12K\n" +
+	"\x0eSearchMessages\x12\x1b.chat.SearchMessagesRequest\x1a\x1c.chat.SearchMessagesResponseB\rZ\vproto/chat/b\x06proto3"
 
 var (
 	file_chat_proto_rawDescOnce sync.Once
@@ -957,45 +2226,97 @@ func file_chat_proto_rawDescGZIP() []byte {
 	return file_chat_proto_rawDescData
 }
 
-var file_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
 var file_chat_proto_goTypes = []any{
-	(*CreateConversationRequest)(nil),     // 0: chat.CreateConversationRequest
-	(*CreateConversationResponse)(nil),    // 1: chat.CreateConversationResponse
-	(*SendMessageRequest)(nil),            // 2: chat.SendMessageRequest
-	(*SendMessageResponse)(nil),           // 3: chat.SendMessageResponse
-	(*Message)(nil),                       // 4: chat.Message
-	(*GetMessagesRequest)(nil),            // 5: chat.GetMessagesRequest
-	(*GetMessagesResponse)(nil),           // 6: chat.GetMessagesResponse
-	(*UpdateLastReadMessageRequest)(nil),  // 7: chat.UpdateLastReadMessageRequest
-	(*UpdateMessageRequest)(nil),          // 8: chat.UpdateMessageRequest
-	(*UpdateMessageResponse)(nil),         // 9: chat.UpdateMessageResponse
-	(*ConversationMember)(nil),            // 10: chat.ConversationMember
-	(*ConversationResult)(nil),            // 11: chat.ConversationResult
-	(*GetConversationsResponse)(nil),      // 12: chat.GetConversationsResponse
-	(*GetConversationsRequest)(nil),       // 13: chat.GetConversationsRequest
-	(*GetConversationsByNameRequest)(nil), // 14: chat.GetConversationsByNameRequest
+	(*CreateConversationRequest)(nil),         // 0: chat.CreateConversationRequest
+	(*CreateConversationResponse)(nil),        // 1: chat.CreateConversationResponse
+	(*SendMessageRequest)(nil),                // 2: chat.SendMessageRequest
+	(*SendMessageResponse)(nil),               // 3: chat.SendMessageResponse
+	(*Message)(nil),                           // 4: chat.Message
+	(*GetMessagesRequest)(nil),                // 5: chat.GetMessagesRequest
+	(*GetMessagesResponse)(nil),               // 6: chat.GetMessagesResponse
+	(*UpdateLastReadMessageRequest)(nil),      // 7: chat.UpdateLastReadMessageRequest
+	(*UpdateMessageRequest)(nil),              // 8: chat.UpdateMessageRequest
+	(*UpdateMessageResponse)(nil),             // 9: chat.UpdateMessageResponse
+	(*ConversationMember)(nil),                // 10: chat.ConversationMember
+	(*ConversationResult)(nil),                // 11: chat.ConversationResult
+	(*GetConversationsResponse)(nil),          // 12: chat.GetConversationsResponse
+	(*GetConversationsRequest)(nil),           // 13: chat.GetConversationsRequest
+	(*GetConversationsByNameRequest)(nil),     // 14: chat.GetConversationsByNameRequest
+	(*GetConversationRequest)(nil),            // 15: chat.GetConversationRequest
+	(*ConversationSettings)(nil),              // 16: chat.ConversationSettings
+	(*GetConversationResponse)(nil),           // 17: chat.GetConversationResponse
+	(*AddMembersRequest)(nil),                 // 18: chat.AddMembersRequest
+	(*RemoveMemberRequest)(nil),               // 19: chat.RemoveMemberRequest
+	(*RenameConversationRequest)(nil),         // 20: chat.RenameConversationRequest
+	(*SetMemberRoleRequest)(nil),              // 21: chat.SetMemberRoleRequest
+	(*SetSlowModeRequest)(nil),                // 22: chat.SetSlowModeRequest
+	(*UpdateConversationResponse)(nil),        // 23: chat.UpdateConversationResponse
+	(*ClearHistoryRequest)(nil),               // 24: chat.ClearHistoryRequest
+	(*DeleteConversationRequest)(nil),         // 25: chat.DeleteConversationRequest
+	(*ExportConversationRequest)(nil),         // 26: chat.ExportConversationRequest
+	(*ExportChunk)(nil),                       // 27: chat.ExportChunk
+	(*StartConversationExportRequest)(nil),    // 28: chat.StartConversationExportRequest
+	(*GetConversationExportRequest)(nil),      // 29: chat.GetConversationExportRequest
+	(*ConversationExport)(nil),                // 30: chat.ConversationExport
+	(*DownloadConversationExportRequest)(nil), // 31: chat.DownloadConversationExportRequest
+	(*SearchTokenSet)(nil),                    // 32: chat.SearchTokenSet
+	(*SearchMessagesRequest)(nil),             // 33: chat.SearchMessagesRequest
+	(*SearchMessagesResponse)(nil),            // 34: chat.SearchMessagesResponse
 }
 var file_chat_proto_depIdxs = []int32{
 	4,  // 0: chat.GetMessagesResponse.messages:type_name -> chat.Message
 	10, // 1: chat.ConversationResult.members:type_name -> chat.ConversationMember
 	11, // 2: chat.GetConversationsResponse.conversations:type_name -> chat.ConversationResult
-	0,  // 3: chat.Chat.CreateConversation:input_type -> chat.CreateConversationRequest
-	2,  // 4: chat.Chat.SendMessage:input_type -> chat.SendMessageRequest
-	8,  // 5: chat.Chat.UpdateLastReadMessage:input_type -> chat.UpdateMessageRequest
-	8,  // 6: chat.Chat.UpdateLastDeliveredMessage:input_type -> chat.UpdateMessageRequest
-	13, // 7: chat.Chat.GetConversations:input_type -> chat.GetConversationsRequest
-	14, // 8: chat.Chat.GetConversationsByName:input_type -> chat.GetConversationsByNameRequest
-	1,  // 9: chat.Chat.CreateConversation:output_type -> chat.CreateConversationResponse
-	3,  // 10: chat.Chat.SendMessage:output_type -> chat.SendMessageResponse
-	9,  // 11: chat.Chat.UpdateLastReadMessage:output_type -> chat.UpdateMessageResponse
-	9,  // 12: chat.Chat.UpdateLastDeliveredMessage:output_type -> chat.UpdateMessageResponse
-	12, // 13: chat.Chat.GetConversations:output_type -> chat.GetConversationsResponse
-	12, // 14: chat.Chat.GetConversationsByName:output_type -> chat.GetConversationsResponse
-	9,  // [9:15] is the sub-list for method output_type
-	3,  // [3:9] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	10, // 3: chat.GetConversationResponse.members:type_name -> chat.ConversationMember
+	16, // 4: chat.GetConversationResponse.settings:type_name -> chat.ConversationSettings
+	32, // 5: chat.SearchMessagesRequest.token_sets:type_name -> chat.SearchTokenSet
+	4,  // 6: chat.SearchMessagesResponse.messages:type_name -> chat.Message
+	0,  // 7: chat.Chat.CreateConversation:input_type -> chat.CreateConversationRequest
+	2,  // 8: chat.Chat.SendMessage:input_type -> chat.SendMessageRequest
+	8,  // 9: chat.Chat.UpdateLastReadMessage:input_type -> chat.UpdateMessageRequest
+	8,  // 10: chat.Chat.UpdateLastDeliveredMessage:input_type -> chat.UpdateMessageRequest
+	13, // 11: chat.Chat.GetConversations:input_type -> chat.GetConversationsRequest
+	14, // 12: chat.Chat.GetConversationsByName:input_type -> chat.GetConversationsByNameRequest
+	15, // 13: chat.Chat.GetConversation:input_type -> chat.GetConversationRequest
+	5,  // 14: chat.Chat.GetMessages:input_type -> chat.GetMessagesRequest
+	18, // 15: chat.Chat.AddMembers:input_type -> chat.AddMembersRequest
+	19, // 16: chat.Chat.RemoveMember:input_type -> chat.RemoveMemberRequest
+	20, // 17: chat.Chat.RenameConversation:input_type -> chat.RenameConversationRequest
+	21, // 18: chat.Chat.SetMemberRole:input_type -> chat.SetMemberRoleRequest
+	22, // 19: chat.Chat.SetSlowMode:input_type -> chat.SetSlowModeRequest
+	24, // 20: chat.Chat.ClearHistory:input_type -> chat.ClearHistoryRequest
+	25, // 21: chat.Chat.DeleteConversation:input_type -> chat.DeleteConversationRequest
+	26, // 22: chat.Chat.ExportConversation:input_type -> chat.ExportConversationRequest
+	28, // 23: chat.Chat.StartConversationExport:input_type -> chat.StartConversationExportRequest
+	29, // 24: chat.Chat.GetConversationExport:input_type -> chat.GetConversationExportRequest
+	31, // 25: chat.Chat.DownloadConversationExport:input_type -> chat.DownloadConversationExportRequest
+	33, // 26: chat.Chat.SearchMessages:input_type -> chat.SearchMessagesRequest
+	1,  // 27: chat.Chat.CreateConversation:output_type -> chat.CreateConversationResponse
+	3,  // 28: chat.Chat.SendMessage:output_type -> chat.SendMessageResponse
+	9,  // 29: chat.Chat.UpdateLastReadMessage:output_type -> chat.UpdateMessageResponse
+	9,  // 30: chat.Chat.UpdateLastDeliveredMessage:output_type -> chat.UpdateMessageResponse
+	12, // 31: chat.Chat.GetConversations:output_type -> chat.GetConversationsResponse
+	12, // 32: chat.Chat.GetConversationsByName:output_type -> chat.GetConversationsResponse
+	17, // 33: chat.Chat.GetConversation:output_type -> chat.GetConversationResponse
+	6,  // 34: chat.Chat.GetMessages:output_type -> chat.GetMessagesResponse
+	23, // 35: chat.Chat.AddMembers:output_type -> chat.UpdateConversationResponse
+	23, // 36: chat.Chat.RemoveMember:output_type -> chat.UpdateConversationResponse
+	23, // 37: chat.Chat.RenameConversation:output_type -> chat.UpdateConversationResponse
+	23, // 38: chat.Chat.SetMemberRole:output_type -> chat.UpdateConversationResponse
+	23, // 39: chat.Chat.SetSlowMode:output_type -> chat.UpdateConversationResponse
+	23, // 40: chat.Chat.ClearHistory:output_type -> chat.UpdateConversationResponse
+	23, // 41: chat.Chat.DeleteConversation:output_type -> chat.UpdateConversationResponse
+	27, // 42: chat.Chat.ExportConversation:output_type -> chat.ExportChunk
+	30, // 43: chat.Chat.StartConversationExport:output_type -> chat.ConversationExport
+	30, // 44: chat.Chat.GetConversationExport:output_type -> chat.ConversationExport
+	27, // 45: chat.Chat.DownloadConversationExport:output_type -> chat.ExportChunk
+	34, // 46: chat.Chat.SearchMessages:output_type -> chat.SearchMessagesResponse
+	27, // [27:47] is the sub-list for method output_type
+	7,  // [7:27] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_chat_proto_init() }
@@ -1010,7 +2331,7 @@ func file_chat_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_chat_proto_rawDesc), len(file_chat_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   15,
+			NumMessages:   35,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,6 +25,20 @@ const (
 	Chat_UpdateLastDeliveredMessage_FullMethodName = "/chat.Chat/UpdateLastDeliveredMessage"
 	Chat_GetConversations_FullMethodName           = "/chat.Chat/GetConversations"
 	Chat_GetConversationsByName_FullMethodName     = "/chat.Chat/GetConversationsByName"
+	Chat_GetConversation_FullMethodName            = "/chat.Chat/GetConversation"
+	Chat_GetMessages_FullMethodName                = "/chat.Chat/GetMessages"
+	Chat_AddMembers_FullMethodName                 = "/chat.Chat/AddMembers"
+	Chat_RemoveMember_FullMethodName               = "/chat.Chat/RemoveMember"
+	Chat_RenameConversation_FullMethodName         = "/chat.Chat/RenameConversation"
+	Chat_SetMemberRole_FullMethodName              = "/chat.Chat/SetMemberRole"
+	Chat_SetSlowMode_FullMethodName                = "/chat.Chat/SetSlowMode"
+	Chat_ClearHistory_FullMethodName               = "/chat.Chat/ClearHistory"
+	Chat_DeleteConversation_FullMethodName         = "/chat.Chat/DeleteConversation"
+	Chat_ExportConversation_FullMethodName         = "/chat.Chat/ExportConversation"
+	Chat_StartConversationExport_FullMethodName    = "/chat.Chat/StartConversationExport"
+	Chat_GetConversationExport_FullMethodName      = "/chat.Chat/GetConversationExport"
+	Chat_DownloadConversationExport_FullMethodName = "/chat.Chat/DownloadConversationExport"
+	Chat_SearchMessages_FullMethodName             = "/chat.Chat/SearchMessages"
 )
 
 // ChatClient is the client API for Chat service.
@@ -37,6 +51,20 @@ type ChatClient interface {
 	UpdateLastDeliveredMessage(ctx context.Context, in *UpdateMessageRequest, opts ...grpc.CallOption) (*UpdateMessageResponse, error)
 	GetConversations(ctx context.Context, in *GetConversationsRequest, opts ...grpc.CallOption) (*GetConversationsResponse, error)
 	GetConversationsByName(ctx context.Context, in *GetConversationsByNameRequest, opts ...grpc.CallOption) (*GetConversationsResponse, error)
+	GetConversation(ctx context.Context, in *GetConversationRequest, opts ...grpc.CallOption) (*GetConversationResponse, error)
+	GetMessages(ctx context.Context, in *GetMessagesRequest, opts ...grpc.CallOption) (*GetMessagesResponse, error)
+	AddMembers(ctx context.Context, in *AddMembersRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error)
+	RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error)
+	RenameConversation(ctx context.Context, in *RenameConversationRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error)
+	SetMemberRole(ctx context.Context, in *SetMemberRoleRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error)
+	SetSlowMode(ctx context.Context, in *SetSlowModeRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error)
+	ClearHistory(ctx context.Context, in *ClearHistoryRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error)
+	DeleteConversation(ctx context.Context, in *DeleteConversationRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error)
+	ExportConversation(ctx context.Context, in *ExportConversationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
+	StartConversationExport(ctx context.Context, in *StartConversationExportRequest, opts ...grpc.CallOption) (*ConversationExport, error)
+	GetConversationExport(ctx context.Context, in *GetConversationExportRequest, opts ...grpc.CallOption) (*ConversationExport, error)
+	DownloadConversationExport(ctx context.Context, in *DownloadConversationExportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
+	SearchMessages(ctx context.Context, in *SearchMessagesRequest, opts ...grpc.CallOption) (*SearchMessagesResponse, error)
 }
 
 type chatClient struct {
@@ -107,6 +135,164 @@ func (c *chatClient) GetConversationsByName(ctx context.Context, in *GetConversa
 	return out, nil
 }
 
+func (c *chatClient) GetConversation(ctx context.Context, in *GetConversationRequest, opts ...grpc.CallOption) (*GetConversationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetConversationResponse)
+	err := c.cc.Invoke(ctx, Chat_GetConversation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) GetMessages(ctx context.Context, in *GetMessagesRequest, opts ...grpc.CallOption) (*GetMessagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMessagesResponse)
+	err := c.cc.Invoke(ctx, Chat_GetMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) AddMembers(ctx context.Context, in *AddMembersRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateConversationResponse)
+	err := c.cc.Invoke(ctx, Chat_AddMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateConversationResponse)
+	err := c.cc.Invoke(ctx, Chat_RemoveMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) RenameConversation(ctx context.Context, in *RenameConversationRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateConversationResponse)
+	err := c.cc.Invoke(ctx, Chat_RenameConversation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) SetMemberRole(ctx context.Context, in *SetMemberRoleRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateConversationResponse)
+	err := c.cc.Invoke(ctx, Chat_SetMemberRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) SetSlowMode(ctx context.Context, in *SetSlowModeRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateConversationResponse)
+	err := c.cc.Invoke(ctx, Chat_SetSlowMode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) ClearHistory(ctx context.Context, in *ClearHistoryRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateConversationResponse)
+	err := c.cc.Invoke(ctx, Chat_ClearHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) DeleteConversation(ctx context.Context, in *DeleteConversationRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateConversationResponse)
+	err := c.cc.Invoke(ctx, Chat_DeleteConversation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) ExportConversation(ctx context.Context, in *ExportConversationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Chat_ServiceDesc.Streams[0], Chat_ExportConversation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportConversationRequest, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Chat_ExportConversationClient = grpc.ServerStreamingClient[ExportChunk]
+
+func (c *chatClient) StartConversationExport(ctx context.Context, in *StartConversationExportRequest, opts ...grpc.CallOption) (*ConversationExport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConversationExport)
+	err := c.cc.Invoke(ctx, Chat_StartConversationExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) GetConversationExport(ctx context.Context, in *GetConversationExportRequest, opts ...grpc.CallOption) (*ConversationExport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConversationExport)
+	err := c.cc.Invoke(ctx, Chat_GetConversationExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatClient) DownloadConversationExport(ctx context.Context, in *DownloadConversationExportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Chat_ServiceDesc.Streams[1], Chat_DownloadConversationExport_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadConversationExportRequest, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Chat_DownloadConversationExportClient = grpc.ServerStreamingClient[ExportChunk]
+
+func (c *chatClient) SearchMessages(ctx context.Context, in *SearchMessagesRequest, opts ...grpc.CallOption) (*SearchMessagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchMessagesResponse)
+	err := c.cc.Invoke(ctx, Chat_SearchMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChatServer is the server API for Chat service.
 // All implementations must embed UnimplementedChatServer
 // for forward compatibility.
@@ -117,6 +303,20 @@ type ChatServer interface {
 	UpdateLastDeliveredMessage(context.Context, *UpdateMessageRequest) (*UpdateMessageResponse, error)
 	GetConversations(context.Context, *GetConversationsRequest) (*GetConversationsResponse, error)
 	GetConversationsByName(context.Context, *GetConversationsByNameRequest) (*GetConversationsResponse, error)
+	GetConversation(context.Context, *GetConversationRequest) (*GetConversationResponse, error)
+	GetMessages(context.Context, *GetMessagesRequest) (*GetMessagesResponse, error)
+	AddMembers(context.Context, *AddMembersRequest) (*UpdateConversationResponse, error)
+	RemoveMember(context.Context, *RemoveMemberRequest) (*UpdateConversationResponse, error)
+	RenameConversation(context.Context, *RenameConversationRequest) (*UpdateConversationResponse, error)
+	SetMemberRole(context.Context, *SetMemberRoleRequest) (*UpdateConversationResponse, error)
+	SetSlowMode(context.Context, *SetSlowModeRequest) (*UpdateConversationResponse, error)
+	ClearHistory(context.Context, *ClearHistoryRequest) (*UpdateConversationResponse, error)
+	DeleteConversation(context.Context, *DeleteConversationRequest) (*UpdateConversationResponse, error)
+	ExportConversation(*ExportConversationRequest, grpc.ServerStreamingServer[ExportChunk]) error
+	StartConversationExport(context.Context, *StartConversationExportRequest) (*ConversationExport, error)
+	GetConversationExport(context.Context, *GetConversationExportRequest) (*ConversationExport, error)
+	DownloadConversationExport(*DownloadConversationExportRequest, grpc.ServerStreamingServer[ExportChunk]) error
+	SearchMessages(context.Context, *SearchMessagesRequest) (*SearchMessagesResponse, error)
 	mustEmbedUnimplementedChatServer()
 }
 
@@ -145,6 +345,48 @@ func (UnimplementedChatServer) GetConversations(context.Context, *GetConversatio
 func (UnimplementedChatServer) GetConversationsByName(context.Context, *GetConversationsByNameRequest) (*GetConversationsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetConversationsByName not implemented")
 }
+func (UnimplementedChatServer) GetConversation(context.Context, *GetConversationRequest) (*GetConversationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetConversation not implemented")
+}
+func (UnimplementedChatServer) GetMessages(context.Context, *GetMessagesRequest) (*GetMessagesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetMessages not implemented")
+}
+func (UnimplementedChatServer) AddMembers(context.Context, *AddMembersRequest) (*UpdateConversationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddMembers not implemented")
+}
+func (UnimplementedChatServer) RemoveMember(context.Context, *RemoveMemberRequest) (*UpdateConversationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveMember not implemented")
+}
+func (UnimplementedChatServer) RenameConversation(context.Context, *RenameConversationRequest) (*UpdateConversationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RenameConversation not implemented")
+}
+func (UnimplementedChatServer) SetMemberRole(context.Context, *SetMemberRoleRequest) (*UpdateConversationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetMemberRole not implemented")
+}
+func (UnimplementedChatServer) SetSlowMode(context.Context, *SetSlowModeRequest) (*UpdateConversationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetSlowMode not implemented")
+}
+func (UnimplementedChatServer) ClearHistory(context.Context, *ClearHistoryRequest) (*UpdateConversationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClearHistory not implemented")
+}
+func (UnimplementedChatServer) DeleteConversation(context.Context, *DeleteConversationRequest) (*UpdateConversationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteConversation not implemented")
+}
+func (UnimplementedChatServer) ExportConversation(*ExportConversationRequest, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Error(codes.Unimplemented, "method ExportConversation not implemented")
+}
+func (UnimplementedChatServer) StartConversationExport(context.Context, *StartConversationExportRequest) (*ConversationExport, error) {
+	return nil, status.Error(codes.Unimplemented, "method StartConversationExport not implemented")
+}
+func (UnimplementedChatServer) GetConversationExport(context.Context, *GetConversationExportRequest) (*ConversationExport, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetConversationExport not implemented")
+}
+func (UnimplementedChatServer) DownloadConversationExport(*DownloadConversationExportRequest, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Error(codes.Unimplemented, "method DownloadConversationExport not implemented")
+}
+func (UnimplementedChatServer) SearchMessages(context.Context, *SearchMessagesRequest) (*SearchMessagesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SearchMessages not implemented")
+}
 func (UnimplementedChatServer) mustEmbedUnimplementedChatServer() {}
 func (UnimplementedChatServer) testEmbeddedByValue()              {}
 
@@ -274,6 +516,244 @@ func _Chat_GetConversationsByName_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Chat_GetConversation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConversationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).GetConversation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_GetConversation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).GetConversation(ctx, req.(*GetConversationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_GetMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).GetMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_GetMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).GetMessages(ctx, req.(*GetMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_AddMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).AddMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_AddMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).AddMembers(ctx, req.(*AddMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_RemoveMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).RemoveMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_RemoveMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).RemoveMember(ctx, req.(*RemoveMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_RenameConversation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameConversationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).RenameConversation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_RenameConversation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).RenameConversation(ctx, req.(*RenameConversationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_SetMemberRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMemberRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).SetMemberRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_SetMemberRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).SetMemberRole(ctx, req.(*SetMemberRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_SetSlowMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSlowModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).SetSlowMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_SetSlowMode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).SetSlowMode(ctx, req.(*SetSlowModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_ClearHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).ClearHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_ClearHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).ClearHistory(ctx, req.(*ClearHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_DeleteConversation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteConversationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).DeleteConversation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_DeleteConversation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).DeleteConversation(ctx, req.(*DeleteConversationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_ExportConversation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportConversationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChatServer).ExportConversation(m, &grpc.GenericServerStream[ExportConversationRequest, ExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Chat_ExportConversationServer = grpc.ServerStreamingServer[ExportChunk]
+
+func _Chat_StartConversationExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartConversationExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).StartConversationExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_StartConversationExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).StartConversationExport(ctx, req.(*StartConversationExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_GetConversationExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConversationExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).GetConversationExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_GetConversationExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).GetConversationExport(ctx, req.(*GetConversationExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chat_DownloadConversationExport_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadConversationExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChatServer).DownloadConversationExport(m, &grpc.GenericServerStream[DownloadConversationExportRequest, ExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Chat_DownloadConversationExportServer = grpc.ServerStreamingServer[ExportChunk]
+
+func _Chat_SearchMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).SearchMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_SearchMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).SearchMessages(ctx, req.(*SearchMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Chat_ServiceDesc is the grpc.ServiceDesc for Chat service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -305,7 +785,66 @@ var Chat_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetConversationsByName",
 			Handler:    _Chat_GetConversationsByName_Handler,
 		},
+		{
+			MethodName: "GetConversation",
+			Handler:    _Chat_GetConversation_Handler,
+		},
+		{
+			MethodName: "GetMessages",
+			Handler:    _Chat_GetMessages_Handler,
+		},
+		{
+			MethodName: "AddMembers",
+			Handler:    _Chat_AddMembers_Handler,
+		},
+		{
+			MethodName: "RemoveMember",
+			Handler:    _Chat_RemoveMember_Handler,
+		},
+		{
+			MethodName: "RenameConversation",
+			Handler:    _Chat_RenameConversation_Handler,
+		},
+		{
+			MethodName: "SetMemberRole",
+			Handler:    _Chat_SetMemberRole_Handler,
+		},
+		{
+			MethodName: "SetSlowMode",
+			Handler:    _Chat_SetSlowMode_Handler,
+		},
+		{
+			MethodName: "ClearHistory",
+			Handler:    _Chat_ClearHistory_Handler,
+		},
+		{
+			MethodName: "DeleteConversation",
+			Handler:    _Chat_DeleteConversation_Handler,
+		},
+		{
+			MethodName: "StartConversationExport",
+			Handler:    _Chat_StartConversationExport_Handler,
+		},
+		{
+			MethodName: "GetConversationExport",
+			Handler:    _Chat_GetConversationExport_Handler,
+		},
+		{
+			MethodName: "SearchMessages",
+			Handler:    _Chat_SearchMessages_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportConversation",
+			Handler:       _Chat_ExportConversation_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DownloadConversationExport",
+			Handler:       _Chat_DownloadConversationExport_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "chat.proto",
 }
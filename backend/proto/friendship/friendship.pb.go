@@ -111,6 +111,7 @@ func (x *FriendResponse) GetStatus() string {
 
 type GetFriendsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	LabelId       int64                  `protobuf:"varint,1,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"` // only accepted friends in this label; 0 for all
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -145,6 +146,13 @@ func (*GetFriendsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{2}
 }
 
+func (x *GetFriendsRequest) GetLabelId() int64 {
+	if x != nil {
+		return x.LabelId
+	}
+	return 0
+}
+
 type Friend struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -152,6 +160,7 @@ type Friend struct {
 	DisplayName   string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
 	AvatarUrl     string                 `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
 	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	LabelIds      []int64                `protobuf:"varint,6,rep,packed,name=label_ids,json=labelIds,proto3" json:"label_ids,omitempty"` // the caller's labels this friend is in
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -221,6 +230,13 @@ func (x *Friend) GetStatus() string {
 	return ""
 }
 
+func (x *Friend) GetLabelIds() []int64 {
+	if x != nil {
+		return x.LabelIds
+	}
+	return nil
+}
+
 type GetFriendsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Friends       []*Friend              `protobuf:"bytes,1,rep,name=friends,proto3" json:"friends,omitempty"`
@@ -265,33 +281,1027 @@ func (x *GetFriendsResponse) GetFriends() []*Friend {
 	return nil
 }
 
-var File_proto_friendship_friendship_proto protoreflect.FileDescriptor
+// ListFriendRequestsRequest lists pending requests the caller sent ("outgoing")
+// or received ("incoming"), newest first. limit defaults to 20 (max 50).
+type ListFriendRequestsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Direction     string                 `protobuf:"bytes,1,opt,name=direction,proto3" json:"direction,omitempty"` // "incoming" | "outgoing"
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor from the previous page; empty for the first page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_friendship_friendship_proto_rawDesc = "" +
-	"\n" +
-	"!proto/friendship/friendship.proto\x12\n" +
-	"friendship\"8\n" +
-	"\rFriendRequest\x12'\n" +
-	"\x0ftarget_username\x18\x01 \x01(\tR\x0etargetUsername\"(\n" +
-	"\x0eFriendResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\"\x13\n" +
-	"\x11GetFriendsRequest\"\x97\x01\n" +
-	"\x06Friend\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\x12!\n" +
-	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1d\n" +
-	"\n" +
-	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12\x16\n" +
-	"\x06status\x18\x05 \x01(\tR\x06status\"B\n" +
-	"\x12GetFriendsResponse\x12,\n" +
-	"\afriends\x18\x01 \x03(\v2\x12.friendship.FriendR\afriends2\xc1\x02\n" +
-	"\n" +
-	"Friendship\x12J\n" +
-	"\x11SendFriendRequest\x12\x19.friendship.FriendRequest\x1a\x1a.friendship.FriendResponse\x12L\n" +
-	"\x13AcceptFriendRequest\x12\x19.friendship.FriendRequest\x1a\x1a.friendship.FriendResponse\x12L\n" +
-	"\x13RejectFriendRequest\x12\x19.friendship.FriendRequest\x1a\x1a.friendship.FriendResponse\x12K\n" +
+func (x *ListFriendRequestsRequest) Reset() {
+	*x = ListFriendRequestsRequest{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFriendRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFriendRequestsRequest) ProtoMessage() {}
+
+func (x *ListFriendRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFriendRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListFriendRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListFriendRequestsRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *ListFriendRequestsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListFriendRequestsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type PendingFriendRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // the other user
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	DisplayName   string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	AvatarUrl     string                 `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PendingFriendRequest) Reset() {
+	*x = PendingFriendRequest{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingFriendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingFriendRequest) ProtoMessage() {}
+
+func (x *PendingFriendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingFriendRequest.ProtoReflect.Descriptor instead.
+func (*PendingFriendRequest) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PendingFriendRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PendingFriendRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *PendingFriendRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *PendingFriendRequest) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *PendingFriendRequest) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListFriendRequestsResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Requests      []*PendingFriendRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	NextCursor    string                  `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFriendRequestsResponse) Reset() {
+	*x = ListFriendRequestsResponse{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFriendRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFriendRequestsResponse) ProtoMessage() {}
+
+func (x *ListFriendRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFriendRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListFriendRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListFriendRequestsResponse) GetRequests() []*PendingFriendRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *ListFriendRequestsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ListBlockedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlockedRequest) Reset() {
+	*x = ListBlockedRequest{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockedRequest) ProtoMessage() {}
+
+func (x *ListBlockedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlockedRequest.ProtoReflect.Descriptor instead.
+func (*ListBlockedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{8}
+}
+
+type BlockedUser struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	DisplayName   string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	AvatarUrl     string                 `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	BlockedAt     string                 `protobuf:"bytes,5,opt,name=blocked_at,json=blockedAt,proto3" json:"blocked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockedUser) Reset() {
+	*x = BlockedUser{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockedUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockedUser) ProtoMessage() {}
+
+func (x *BlockedUser) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockedUser.ProtoReflect.Descriptor instead.
+func (*BlockedUser) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BlockedUser) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *BlockedUser) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *BlockedUser) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *BlockedUser) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *BlockedUser) GetBlockedAt() string {
+	if x != nil {
+		return x.BlockedAt
+	}
+	return ""
+}
+
+type ListBlockedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*BlockedUser         `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlockedResponse) Reset() {
+	*x = ListBlockedResponse{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockedResponse) ProtoMessage() {}
+
+func (x *ListBlockedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlockedResponse.ProtoReflect.Descriptor instead.
+func (*ListBlockedResponse) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListBlockedResponse) GetUsers() []*BlockedUser {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+// SuggestFriendsRequest lists people the caller may know. limit defaults to
+// 10 (max 50).
+type SuggestFriendsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestFriendsRequest) Reset() {
+	*x = SuggestFriendsRequest{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestFriendsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestFriendsRequest) ProtoMessage() {}
+
+func (x *SuggestFriendsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestFriendsRequest.ProtoReflect.Descriptor instead.
+func (*SuggestFriendsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SuggestFriendsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type FriendSuggestion struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	UserId            string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username          string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	DisplayName       string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	AvatarUrl         string                 `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	MutualFriendCount int32                  `protobuf:"varint,5,opt,name=mutual_friend_count,json=mutualFriendCount,proto3" json:"mutual_friend_count,omitempty"`
+	MutualFriends     []string               `protobuf:"bytes,6,rep,name=mutual_friends,json=mutualFriends,proto3" json:"mutual_friends,omitempty"` // usernames of up to 3 mutual friends
+	SharedGroupCount  int32                  `protobuf:"varint,7,opt,name=shared_group_count,json=sharedGroupCount,proto3" json:"shared_group_count,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *FriendSuggestion) Reset() {
+	*x = FriendSuggestion{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FriendSuggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FriendSuggestion) ProtoMessage() {}
+
+func (x *FriendSuggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FriendSuggestion.ProtoReflect.Descriptor instead.
+func (*FriendSuggestion) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FriendSuggestion) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *FriendSuggestion) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *FriendSuggestion) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *FriendSuggestion) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *FriendSuggestion) GetMutualFriendCount() int32 {
+	if x != nil {
+		return x.MutualFriendCount
+	}
+	return 0
+}
+
+func (x *FriendSuggestion) GetMutualFriends() []string {
+	if x != nil {
+		return x.MutualFriends
+	}
+	return nil
+}
+
+func (x *FriendSuggestion) GetSharedGroupCount() int32 {
+	if x != nil {
+		return x.SharedGroupCount
+	}
+	return 0
+}
+
+type SuggestFriendsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Suggestions   []*FriendSuggestion    `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestFriendsResponse) Reset() {
+	*x = SuggestFriendsResponse{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestFriendsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestFriendsResponse) ProtoMessage() {}
+
+func (x *SuggestFriendsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestFriendsResponse.ProtoReflect.Descriptor instead.
+func (*SuggestFriendsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SuggestFriendsResponse) GetSuggestions() []*FriendSuggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+// FriendLabel is a list of friends owned by the caller, e.g. "work" or
+// "family". Labels are private to their owner.
+type FriendLabel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	MemberCount   int32                  `protobuf:"varint,3,opt,name=member_count,json=memberCount,proto3" json:"member_count,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FriendLabel) Reset() {
+	*x = FriendLabel{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FriendLabel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FriendLabel) ProtoMessage() {}
+
+func (x *FriendLabel) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FriendLabel.ProtoReflect.Descriptor instead.
+func (*FriendLabel) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *FriendLabel) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *FriendLabel) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FriendLabel) GetMemberCount() int32 {
+	if x != nil {
+		return x.MemberCount
+	}
+	return 0
+}
+
+func (x *FriendLabel) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *FriendLabel) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type CreateLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // 1-50 characters, unique per user ignoring case
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateLabelRequest) Reset() {
+	*x = CreateLabelRequest{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLabelRequest) ProtoMessage() {}
+
+func (x *CreateLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLabelRequest.ProtoReflect.Descriptor instead.
+func (*CreateLabelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreateLabelRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ListLabelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLabelsRequest) Reset() {
+	*x = ListLabelsRequest{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLabelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLabelsRequest) ProtoMessage() {}
+
+func (x *ListLabelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLabelsRequest.ProtoReflect.Descriptor instead.
+func (*ListLabelsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{16}
+}
+
+type ListLabelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Labels        []*FriendLabel         `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLabelsResponse) Reset() {
+	*x = ListLabelsResponse{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLabelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLabelsResponse) ProtoMessage() {}
+
+func (x *ListLabelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLabelsResponse.ProtoReflect.Descriptor instead.
+func (*ListLabelsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListLabelsResponse) GetLabels() []*FriendLabel {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type RenameLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LabelId       int64                  `protobuf:"varint,1,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameLabelRequest) Reset() {
+	*x = RenameLabelRequest{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameLabelRequest) ProtoMessage() {}
+
+func (x *RenameLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameLabelRequest.ProtoReflect.Descriptor instead.
+func (*RenameLabelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RenameLabelRequest) GetLabelId() int64 {
+	if x != nil {
+		return x.LabelId
+	}
+	return 0
+}
+
+func (x *RenameLabelRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LabelId       int64                  `protobuf:"varint,1,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLabelRequest) Reset() {
+	*x = DeleteLabelRequest{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLabelRequest) ProtoMessage() {}
+
+func (x *DeleteLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLabelRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLabelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteLabelRequest) GetLabelId() int64 {
+	if x != nil {
+		return x.LabelId
+	}
+	return 0
+}
+
+type DeleteLabelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLabelResponse) Reset() {
+	*x = DeleteLabelResponse{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLabelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLabelResponse) ProtoMessage() {}
+
+func (x *DeleteLabelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLabelResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLabelResponse) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{20}
+}
+
+// LabelMembersRequest adds friends to or removes them from a label.
+type LabelMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LabelId       int64                  `protobuf:"varint,1,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"`
+	Usernames     []string               `protobuf:"bytes,2,rep,name=usernames,proto3" json:"usernames,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LabelMembersRequest) Reset() {
+	*x = LabelMembersRequest{}
+	mi := &file_proto_friendship_friendship_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LabelMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelMembersRequest) ProtoMessage() {}
+
+func (x *LabelMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_friendship_friendship_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelMembersRequest.ProtoReflect.Descriptor instead.
+func (*LabelMembersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_friendship_friendship_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *LabelMembersRequest) GetLabelId() int64 {
+	if x != nil {
+		return x.LabelId
+	}
+	return 0
+}
+
+func (x *LabelMembersRequest) GetUsernames() []string {
+	if x != nil {
+		return x.Usernames
+	}
+	return nil
+}
+
+var File_proto_friendship_friendship_proto protoreflect.FileDescriptor
+
+const file_proto_friendship_friendship_proto_rawDesc = "" +
+	"\n" +
+	"!proto/friendship/friendship.proto\x12\n" +
+	"friendship\"8\n" +
+	"\rFriendRequest\x12'\n" +
+	"\x0ftarget_username\x18\x01 \x01(\tR\x0etargetUsername\"(\n" +
+	"\x0eFriendResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\".\n" +
+	"\x11GetFriendsRequest\x12\x19\n" +
+	"\blabel_id\x18\x01 \x01(\x03R\alabelId\"\xb4\x01\n" +
+	"\x06Friend\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1b\n" +
+	"\tlabel_ids\x18\x06 \x03(\x03R\blabelIds\"B\n" +
+	"\x12GetFriendsResponse\x12,\n" +
+	"\afriends\x18\x01 \x03(\v2\x12.friendship.FriendR\afriends\"g\n" +
+	"\x19ListFriendRequestsRequest\x12\x1c\n" +
+	"\tdirection\x18\x01 \x01(\tR\tdirection\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"\xac\x01\n" +
+	"\x14PendingFriendRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\"{\n" +
+	"\x1aListFriendRequestsResponse\x12<\n" +
+	"\brequests\x18\x01 \x03(\v2 .friendship.PendingFriendRequestR\brequests\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\x14\n" +
+	"\x12ListBlockedRequest\"\xa3\x01\n" +
+	"\vBlockedUser\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12\x1d\n" +
+	"\n" +
+	"blocked_at\x18\x05 \x01(\tR\tblockedAt\"D\n" +
+	"\x13ListBlockedResponse\x12-\n" +
+	"\x05users\x18\x01 \x03(\v2\x17.friendship.BlockedUserR\x05users\"-\n" +
+	"\x15SuggestFriendsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\"\x8e\x02\n" +
+	"\x10FriendSuggestion\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x04 \x01(\tR\tavatarUrl\x12.\n" +
+	"\x13mutual_friend_count\x18\x05 \x01(\x05R\x11mutualFriendCount\x12%\n" +
+	"\x0emutual_friends\x18\x06 \x03(\tR\rmutualFriends\x12,\n" +
+	"\x12shared_group_count\x18\a \x01(\x05R\x10sharedGroupCount\"X\n" +
+	"\x16SuggestFriendsResponse\x12>\n" +
+	"\vsuggestions\x18\x01 \x03(\v2\x1c.friendship.FriendSuggestionR\vsuggestions\"\x92\x01\n" +
+	"\vFriendLabel\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12!\n" +
+	"\fmember_count\x18\x03 \x01(\x05R\vmemberCount\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\tR\tupdatedAt\"(\n" +
+	"\x12CreateLabelRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x13\n" +
+	"\x11ListLabelsRequest\"E\n" +
+	"\x12ListLabelsResponse\x12/\n" +
+	"\x06labels\x18\x01 \x03(\v2\x17.friendship.FriendLabelR\x06labels\"C\n" +
+	"\x12RenameLabelRequest\x12\x19\n" +
+	"\blabel_id\x18\x01 \x01(\x03R\alabelId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"/\n" +
+	"\x12DeleteLabelRequest\x12\x19\n" +
+	"\blabel_id\x18\x01 \x01(\x03R\alabelId\"\x15\n" +
+	"\x13DeleteLabelResponse\"N\n" +
+	"\x13LabelMembersRequest\x12\x19\n" +
+	"\blabel_id\x18\x01 \x01(\x03R\alabelId\x12\x1c\n" +
+	"\tusernames\x18\x02 \x03(\tR\tusernames2\xb8\n" +
+	"\n" +
+	"\n" +
+	"Friendship\x12J\n" +
+	"\x11SendFriendRequest\x12\x19.friendship.FriendRequest\x1a\x1a.friendship.FriendResponse\x12L\n" +
+	"\x13AcceptFriendRequest\x12\x19.friendship.FriendRequest\x1a\x1a.friendship.FriendResponse\x12L\n" +
+	"\x13RejectFriendRequest\x12\x19.friendship.FriendRequest\x1a\x1a.friendship.FriendResponse\x12K\n" +
+	"\n" +
+	"GetFriends\x12\x1d.friendship.GetFriendsRequest\x1a\x1e.friendship.GetFriendsResponse\x12c\n" +
+	"\x12ListFriendRequests\x12%.friendship.ListFriendRequestsRequest\x1a&.friendship.ListFriendRequestsResponse\x12L\n" +
+	"\x13CancelFriendRequest\x12\x19.friendship.FriendRequest\x1a\x1a.friendship.FriendResponse\x12E\n" +
+	"\fRemoveFriend\x12\x19.friendship.FriendRequest\x1a\x1a.friendship.FriendResponse\x12B\n" +
+	"\tBlockUser\x12\x19.friendship.FriendRequest\x1a\x1a.friendship.FriendResponse\x12D\n" +
+	"\vUnblockUser\x12\x19.friendship.FriendRequest\x1a\x1a.friendship.FriendResponse\x12N\n" +
+	"\vListBlocked\x12\x1e.friendship.ListBlockedRequest\x1a\x1f.friendship.ListBlockedResponse\x12W\n" +
+	"\x0eSuggestFriends\x12!.friendship.SuggestFriendsRequest\x1a\".friendship.SuggestFriendsResponse\x12F\n" +
+	"\vCreateLabel\x12\x1e.friendship.CreateLabelRequest\x1a\x17.friendship.FriendLabel\x12K\n" +
 	"\n" +
-	"GetFriends\x12\x1d.friendship.GetFriendsRequest\x1a\x1e.friendship.GetFriendsResponseB\x13Z\x11proto/friendship/b\x06proto3"
+	"ListLabels\x12\x1d.friendship.ListLabelsRequest\x1a\x1e.friendship.ListLabelsResponse\x12F\n" +
+	"\vRenameLabel\x12\x1e.friendship.RenameLabelRequest\x1a\x17.friendship.FriendLabel\x12N\n" +
+	"\vDeleteLabel\x12\x1e.friendship.DeleteLabelRequest\x1a\x1f.friendship.DeleteLabelResponse\x12K\n" +
+	"\x0fAddLabelMembers\x12\x1f.friendship.LabelMembersRequest\x1a\x17.friendship.FriendLabel\x12N\n" +
+	"\x12RemoveLabelMembers\x12\x1f.friendship.LabelMembersRequest\x1a\x17.friendship.FriendLabelB\x13Z\x11proto/friendship/b\x06proto3"
 
 var (
 	file_proto_friendship_friendship_proto_rawDescOnce sync.Once
@@ -305,29 +1315,76 @@ func file_proto_friendship_friendship_proto_rawDescGZIP() []byte {
 	return file_proto_friendship_friendship_proto_rawDescData
 }
 
-var file_proto_friendship_friendship_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_proto_friendship_friendship_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_proto_friendship_friendship_proto_goTypes = []any{
-	(*FriendRequest)(nil),      // 0: friendship.FriendRequest
-	(*FriendResponse)(nil),     // 1: friendship.FriendResponse
-	(*GetFriendsRequest)(nil),  // 2: friendship.GetFriendsRequest
-	(*Friend)(nil),             // 3: friendship.Friend
-	(*GetFriendsResponse)(nil), // 4: friendship.GetFriendsResponse
+	(*FriendRequest)(nil),              // 0: friendship.FriendRequest
+	(*FriendResponse)(nil),             // 1: friendship.FriendResponse
+	(*GetFriendsRequest)(nil),          // 2: friendship.GetFriendsRequest
+	(*Friend)(nil),                     // 3: friendship.Friend
+	(*GetFriendsResponse)(nil),         // 4: friendship.GetFriendsResponse
+	(*ListFriendRequestsRequest)(nil),  // 5: friendship.ListFriendRequestsRequest
+	(*PendingFriendRequest)(nil),       // 6: friendship.PendingFriendRequest
+	(*ListFriendRequestsResponse)(nil), // 7: friendship.ListFriendRequestsResponse
+	(*ListBlockedRequest)(nil),         // 8: friendship.ListBlockedRequest
+	(*BlockedUser)(nil),                // 9: friendship.BlockedUser
+	(*ListBlockedResponse)(nil),        // 10: friendship.ListBlockedResponse
+	(*SuggestFriendsRequest)(nil),      // 11: friendship.SuggestFriendsRequest
+	(*FriendSuggestion)(nil),           // 12: friendship.FriendSuggestion
+	(*SuggestFriendsResponse)(nil),     // 13: friendship.SuggestFriendsResponse
+	(*FriendLabel)(nil),                // 14: friendship.FriendLabel
+	(*CreateLabelRequest)(nil),         // 15: friendship.CreateLabelRequest
+	(*ListLabelsRequest)(nil),          // 16: friendship.ListLabelsRequest
+	(*ListLabelsResponse)(nil),         // 17: friendship.ListLabelsResponse
+	(*RenameLabelRequest)(nil),         // 18: friendship.RenameLabelRequest
+	(*DeleteLabelRequest)(nil),         // 19: friendship.DeleteLabelRequest
+	(*DeleteLabelResponse)(nil),        // 20: friendship.DeleteLabelResponse
+	(*LabelMembersRequest)(nil),        // 21: friendship.LabelMembersRequest
 }
 var file_proto_friendship_friendship_proto_depIdxs = []int32{
-	3, // 0: friendship.GetFriendsResponse.friends:type_name -> friendship.Friend
-	0, // 1: friendship.Friendship.SendFriendRequest:input_type -> friendship.FriendRequest
-	0, // 2: friendship.Friendship.AcceptFriendRequest:input_type -> friendship.FriendRequest
-	0, // 3: friendship.Friendship.RejectFriendRequest:input_type -> friendship.FriendRequest
-	2, // 4: friendship.Friendship.GetFriends:input_type -> friendship.GetFriendsRequest
-	1, // 5: friendship.Friendship.SendFriendRequest:output_type -> friendship.FriendResponse
-	1, // 6: friendship.Friendship.AcceptFriendRequest:output_type -> friendship.FriendResponse
-	1, // 7: friendship.Friendship.RejectFriendRequest:output_type -> friendship.FriendResponse
-	4, // 8: friendship.Friendship.GetFriends:output_type -> friendship.GetFriendsResponse
-	5, // [5:9] is the sub-list for method output_type
-	1, // [1:5] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	3,  // 0: friendship.GetFriendsResponse.friends:type_name -> friendship.Friend
+	6,  // 1: friendship.ListFriendRequestsResponse.requests:type_name -> friendship.PendingFriendRequest
+	9,  // 2: friendship.ListBlockedResponse.users:type_name -> friendship.BlockedUser
+	12, // 3: friendship.SuggestFriendsResponse.suggestions:type_name -> friendship.FriendSuggestion
+	14, // 4: friendship.ListLabelsResponse.labels:type_name -> friendship.FriendLabel
+	0,  // 5: friendship.Friendship.SendFriendRequest:input_type -> friendship.FriendRequest
+	0,  // 6: friendship.Friendship.AcceptFriendRequest:input_type -> friendship.FriendRequest
+	0,  // 7: friendship.Friendship.RejectFriendRequest:input_type -> friendship.FriendRequest
+	2,  // 8: friendship.Friendship.GetFriends:input_type -> friendship.GetFriendsRequest
+	5,  // 9: friendship.Friendship.ListFriendRequests:input_type -> friendship.ListFriendRequestsRequest
+	0,  // 10: friendship.Friendship.CancelFriendRequest:input_type -> friendship.FriendRequest
+	0,  // 11: friendship.Friendship.RemoveFriend:input_type -> friendship.FriendRequest
+	0,  // 12: friendship.Friendship.BlockUser:input_type -> friendship.FriendRequest
+	0,  // 13: friendship.Friendship.UnblockUser:input_type -> friendship.FriendRequest
+	8,  // 14: friendship.Friendship.ListBlocked:input_type -> friendship.ListBlockedRequest
+	11, // 15: friendship.Friendship.SuggestFriends:input_type -> friendship.SuggestFriendsRequest
+	15, // 16: friendship.Friendship.CreateLabel:input_type -> friendship.CreateLabelRequest
+	16, // 17: friendship.Friendship.ListLabels:input_type -> friendship.ListLabelsRequest
+	18, // 18: friendship.Friendship.RenameLabel:input_type -> friendship.RenameLabelRequest
+	19, // 19: friendship.Friendship.DeleteLabel:input_type -> friendship.DeleteLabelRequest
+	21, // 20: friendship.Friendship.AddLabelMembers:input_type -> friendship.LabelMembersRequest
+	21, // 21: friendship.Friendship.RemoveLabelMembers:input_type -> friendship.LabelMembersRequest
+	1,  // 22: friendship.Friendship.SendFriendRequest:output_type -> friendship.FriendResponse
+	1,  // 23: friendship.Friendship.AcceptFriendRequest:output_type -> friendship.FriendResponse
+	1,  // 24: friendship.Friendship.RejectFriendRequest:output_type -> friendship.FriendResponse
+	4,  // 25: friendship.Friendship.GetFriends:output_type -> friendship.GetFriendsResponse
+	7,  // 26: friendship.Friendship.ListFriendRequests:output_type -> friendship.ListFriendRequestsResponse
+	1,  // 27: friendship.Friendship.CancelFriendRequest:output_type -> friendship.FriendResponse
+	1,  // 28: friendship.Friendship.RemoveFriend:output_type -> friendship.FriendResponse
+	1,  // 29: friendship.Friendship.BlockUser:output_type -> friendship.FriendResponse
+	1,  // 30: friendship.Friendship.UnblockUser:output_type -> friendship.FriendResponse
+	10, // 31: friendship.Friendship.ListBlocked:output_type -> friendship.ListBlockedResponse
+	13, // 32: friendship.Friendship.SuggestFriends:output_type -> friendship.SuggestFriendsResponse
+	14, // 33: friendship.Friendship.CreateLabel:output_type -> friendship.FriendLabel
+	17, // 34: friendship.Friendship.ListLabels:output_type -> friendship.ListLabelsResponse
+	14, // 35: friendship.Friendship.RenameLabel:output_type -> friendship.FriendLabel
+	20, // 36: friendship.Friendship.DeleteLabel:output_type -> friendship.DeleteLabelResponse
+	14, // 37: friendship.Friendship.AddLabelMembers:output_type -> friendship.FriendLabel
+	14, // 38: friendship.Friendship.RemoveLabelMembers:output_type -> friendship.FriendLabel
+	22, // [22:39] is the sub-list for method output_type
+	5,  // [5:22] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_proto_friendship_friendship_proto_init() }
@@ -341,7 +1398,7 @@ func file_proto_friendship_friendship_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_friendship_friendship_proto_rawDesc), len(file_proto_friendship_friendship_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
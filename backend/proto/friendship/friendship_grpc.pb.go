@@ -23,6 +23,19 @@ const (
 	Friendship_AcceptFriendRequest_FullMethodName = "/friendship.Friendship/AcceptFriendRequest"
 	Friendship_RejectFriendRequest_FullMethodName = "/friendship.Friendship/RejectFriendRequest"
 	Friendship_GetFriends_FullMethodName          = "/friendship.Friendship/GetFriends"
+	Friendship_ListFriendRequests_FullMethodName  = "/friendship.Friendship/ListFriendRequests"
+	Friendship_CancelFriendRequest_FullMethodName = "/friendship.Friendship/CancelFriendRequest"
+	Friendship_RemoveFriend_FullMethodName        = "/friendship.Friendship/RemoveFriend"
+	Friendship_BlockUser_FullMethodName           = "/friendship.Friendship/BlockUser"
+	Friendship_UnblockUser_FullMethodName         = "/friendship.Friendship/UnblockUser"
+	Friendship_ListBlocked_FullMethodName         = "/friendship.Friendship/ListBlocked"
+	Friendship_SuggestFriends_FullMethodName      = "/friendship.Friendship/SuggestFriends"
+	Friendship_CreateLabel_FullMethodName         = "/friendship.Friendship/CreateLabel"
+	Friendship_ListLabels_FullMethodName          = "/friendship.Friendship/ListLabels"
+	Friendship_RenameLabel_FullMethodName         = "/friendship.Friendship/RenameLabel"
+	Friendship_DeleteLabel_FullMethodName         = "/friendship.Friendship/DeleteLabel"
+	Friendship_AddLabelMembers_FullMethodName     = "/friendship.Friendship/AddLabelMembers"
+	Friendship_RemoveLabelMembers_FullMethodName  = "/friendship.Friendship/RemoveLabelMembers"
 )
 
 // FriendshipClient is the client API for Friendship service.
@@ -33,6 +46,19 @@ type FriendshipClient interface {
 	AcceptFriendRequest(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*FriendResponse, error)
 	RejectFriendRequest(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*FriendResponse, error)
 	GetFriends(ctx context.Context, in *GetFriendsRequest, opts ...grpc.CallOption) (*GetFriendsResponse, error)
+	ListFriendRequests(ctx context.Context, in *ListFriendRequestsRequest, opts ...grpc.CallOption) (*ListFriendRequestsResponse, error)
+	CancelFriendRequest(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*FriendResponse, error)
+	RemoveFriend(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*FriendResponse, error)
+	BlockUser(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*FriendResponse, error)
+	UnblockUser(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*FriendResponse, error)
+	ListBlocked(ctx context.Context, in *ListBlockedRequest, opts ...grpc.CallOption) (*ListBlockedResponse, error)
+	SuggestFriends(ctx context.Context, in *SuggestFriendsRequest, opts ...grpc.CallOption) (*SuggestFriendsResponse, error)
+	CreateLabel(ctx context.Context, in *CreateLabelRequest, opts ...grpc.CallOption) (*FriendLabel, error)
+	ListLabels(ctx context.Context, in *ListLabelsRequest, opts ...grpc.CallOption) (*ListLabelsResponse, error)
+	RenameLabel(ctx context.Context, in *RenameLabelRequest, opts ...grpc.CallOption) (*FriendLabel, error)
+	DeleteLabel(ctx context.Context, in *DeleteLabelRequest, opts ...grpc.CallOption) (*DeleteLabelResponse, error)
+	AddLabelMembers(ctx context.Context, in *LabelMembersRequest, opts ...grpc.CallOption) (*FriendLabel, error)
+	RemoveLabelMembers(ctx context.Context, in *LabelMembersRequest, opts ...grpc.CallOption) (*FriendLabel, error)
 }
 
 type friendshipClient struct {
@@ -83,6 +109,136 @@ func (c *friendshipClient) GetFriends(ctx context.Context, in *GetFriendsRequest
 	return out, nil
 }
 
+func (c *friendshipClient) ListFriendRequests(ctx context.Context, in *ListFriendRequestsRequest, opts ...grpc.CallOption) (*ListFriendRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFriendRequestsResponse)
+	err := c.cc.Invoke(ctx, Friendship_ListFriendRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipClient) CancelFriendRequest(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*FriendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendResponse)
+	err := c.cc.Invoke(ctx, Friendship_CancelFriendRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipClient) RemoveFriend(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*FriendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendResponse)
+	err := c.cc.Invoke(ctx, Friendship_RemoveFriend_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipClient) BlockUser(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*FriendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendResponse)
+	err := c.cc.Invoke(ctx, Friendship_BlockUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipClient) UnblockUser(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*FriendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendResponse)
+	err := c.cc.Invoke(ctx, Friendship_UnblockUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipClient) ListBlocked(ctx context.Context, in *ListBlockedRequest, opts ...grpc.CallOption) (*ListBlockedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBlockedResponse)
+	err := c.cc.Invoke(ctx, Friendship_ListBlocked_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipClient) SuggestFriends(ctx context.Context, in *SuggestFriendsRequest, opts ...grpc.CallOption) (*SuggestFriendsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuggestFriendsResponse)
+	err := c.cc.Invoke(ctx, Friendship_SuggestFriends_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipClient) CreateLabel(ctx context.Context, in *CreateLabelRequest, opts ...grpc.CallOption) (*FriendLabel, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendLabel)
+	err := c.cc.Invoke(ctx, Friendship_CreateLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipClient) ListLabels(ctx context.Context, in *ListLabelsRequest, opts ...grpc.CallOption) (*ListLabelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLabelsResponse)
+	err := c.cc.Invoke(ctx, Friendship_ListLabels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipClient) RenameLabel(ctx context.Context, in *RenameLabelRequest, opts ...grpc.CallOption) (*FriendLabel, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendLabel)
+	err := c.cc.Invoke(ctx, Friendship_RenameLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipClient) DeleteLabel(ctx context.Context, in *DeleteLabelRequest, opts ...grpc.CallOption) (*DeleteLabelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteLabelResponse)
+	err := c.cc.Invoke(ctx, Friendship_DeleteLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipClient) AddLabelMembers(ctx context.Context, in *LabelMembersRequest, opts ...grpc.CallOption) (*FriendLabel, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendLabel)
+	err := c.cc.Invoke(ctx, Friendship_AddLabelMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *friendshipClient) RemoveLabelMembers(ctx context.Context, in *LabelMembersRequest, opts ...grpc.CallOption) (*FriendLabel, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FriendLabel)
+	err := c.cc.Invoke(ctx, Friendship_RemoveLabelMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FriendshipServer is the server API for Friendship service.
 // All implementations must embed UnimplementedFriendshipServer
 // for forward compatibility.
@@ -91,6 +247,19 @@ type FriendshipServer interface {
 	AcceptFriendRequest(context.Context, *FriendRequest) (*FriendResponse, error)
 	RejectFriendRequest(context.Context, *FriendRequest) (*FriendResponse, error)
 	GetFriends(context.Context, *GetFriendsRequest) (*GetFriendsResponse, error)
+	ListFriendRequests(context.Context, *ListFriendRequestsRequest) (*ListFriendRequestsResponse, error)
+	CancelFriendRequest(context.Context, *FriendRequest) (*FriendResponse, error)
+	RemoveFriend(context.Context, *FriendRequest) (*FriendResponse, error)
+	BlockUser(context.Context, *FriendRequest) (*FriendResponse, error)
+	UnblockUser(context.Context, *FriendRequest) (*FriendResponse, error)
+	ListBlocked(context.Context, *ListBlockedRequest) (*ListBlockedResponse, error)
+	SuggestFriends(context.Context, *SuggestFriendsRequest) (*SuggestFriendsResponse, error)
+	CreateLabel(context.Context, *CreateLabelRequest) (*FriendLabel, error)
+	ListLabels(context.Context, *ListLabelsRequest) (*ListLabelsResponse, error)
+	RenameLabel(context.Context, *RenameLabelRequest) (*FriendLabel, error)
+	DeleteLabel(context.Context, *DeleteLabelRequest) (*DeleteLabelResponse, error)
+	AddLabelMembers(context.Context, *LabelMembersRequest) (*FriendLabel, error)
+	RemoveLabelMembers(context.Context, *LabelMembersRequest) (*FriendLabel, error)
 	mustEmbedUnimplementedFriendshipServer()
 }
 
@@ -113,6 +282,45 @@ func (UnimplementedFriendshipServer) RejectFriendRequest(context.Context, *Frien
 func (UnimplementedFriendshipServer) GetFriends(context.Context, *GetFriendsRequest) (*GetFriendsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetFriends not implemented")
 }
+func (UnimplementedFriendshipServer) ListFriendRequests(context.Context, *ListFriendRequestsRequest) (*ListFriendRequestsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListFriendRequests not implemented")
+}
+func (UnimplementedFriendshipServer) CancelFriendRequest(context.Context, *FriendRequest) (*FriendResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelFriendRequest not implemented")
+}
+func (UnimplementedFriendshipServer) RemoveFriend(context.Context, *FriendRequest) (*FriendResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveFriend not implemented")
+}
+func (UnimplementedFriendshipServer) BlockUser(context.Context, *FriendRequest) (*FriendResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BlockUser not implemented")
+}
+func (UnimplementedFriendshipServer) UnblockUser(context.Context, *FriendRequest) (*FriendResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnblockUser not implemented")
+}
+func (UnimplementedFriendshipServer) ListBlocked(context.Context, *ListBlockedRequest) (*ListBlockedResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListBlocked not implemented")
+}
+func (UnimplementedFriendshipServer) SuggestFriends(context.Context, *SuggestFriendsRequest) (*SuggestFriendsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SuggestFriends not implemented")
+}
+func (UnimplementedFriendshipServer) CreateLabel(context.Context, *CreateLabelRequest) (*FriendLabel, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateLabel not implemented")
+}
+func (UnimplementedFriendshipServer) ListLabels(context.Context, *ListLabelsRequest) (*ListLabelsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListLabels not implemented")
+}
+func (UnimplementedFriendshipServer) RenameLabel(context.Context, *RenameLabelRequest) (*FriendLabel, error) {
+	return nil, status.Error(codes.Unimplemented, "method RenameLabel not implemented")
+}
+func (UnimplementedFriendshipServer) DeleteLabel(context.Context, *DeleteLabelRequest) (*DeleteLabelResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteLabel not implemented")
+}
+func (UnimplementedFriendshipServer) AddLabelMembers(context.Context, *LabelMembersRequest) (*FriendLabel, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddLabelMembers not implemented")
+}
+func (UnimplementedFriendshipServer) RemoveLabelMembers(context.Context, *LabelMembersRequest) (*FriendLabel, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveLabelMembers not implemented")
+}
 func (UnimplementedFriendshipServer) mustEmbedUnimplementedFriendshipServer() {}
 func (UnimplementedFriendshipServer) testEmbeddedByValue()                    {}
 
@@ -206,6 +414,240 @@ func _Friendship_GetFriends_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Friendship_ListFriendRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFriendRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).ListFriendRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_ListFriendRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).ListFriendRequests(ctx, req.(*ListFriendRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Friendship_CancelFriendRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).CancelFriendRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_CancelFriendRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).CancelFriendRequest(ctx, req.(*FriendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Friendship_RemoveFriend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).RemoveFriend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_RemoveFriend_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).RemoveFriend(ctx, req.(*FriendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Friendship_BlockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).BlockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_BlockUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).BlockUser(ctx, req.(*FriendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Friendship_UnblockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).UnblockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_UnblockUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).UnblockUser(ctx, req.(*FriendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Friendship_ListBlocked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlockedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).ListBlocked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_ListBlocked_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).ListBlocked(ctx, req.(*ListBlockedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Friendship_SuggestFriends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestFriendsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).SuggestFriends(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_SuggestFriends_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).SuggestFriends(ctx, req.(*SuggestFriendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Friendship_CreateLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).CreateLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_CreateLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).CreateLabel(ctx, req.(*CreateLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Friendship_ListLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLabelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).ListLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_ListLabels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).ListLabels(ctx, req.(*ListLabelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Friendship_RenameLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).RenameLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_RenameLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).RenameLabel(ctx, req.(*RenameLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Friendship_DeleteLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).DeleteLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_DeleteLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).DeleteLabel(ctx, req.(*DeleteLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Friendship_AddLabelMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LabelMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).AddLabelMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_AddLabelMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).AddLabelMembers(ctx, req.(*LabelMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Friendship_RemoveLabelMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LabelMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FriendshipServer).RemoveLabelMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Friendship_RemoveLabelMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FriendshipServer).RemoveLabelMembers(ctx, req.(*LabelMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Friendship_ServiceDesc is the grpc.ServiceDesc for Friendship service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +671,58 @@ var Friendship_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetFriends",
 			Handler:    _Friendship_GetFriends_Handler,
 		},
+		{
+			MethodName: "ListFriendRequests",
+			Handler:    _Friendship_ListFriendRequests_Handler,
+		},
+		{
+			MethodName: "CancelFriendRequest",
+			Handler:    _Friendship_CancelFriendRequest_Handler,
+		},
+		{
+			MethodName: "RemoveFriend",
+			Handler:    _Friendship_RemoveFriend_Handler,
+		},
+		{
+			MethodName: "BlockUser",
+			Handler:    _Friendship_BlockUser_Handler,
+		},
+		{
+			MethodName: "UnblockUser",
+			Handler:    _Friendship_UnblockUser_Handler,
+		},
+		{
+			MethodName: "ListBlocked",
+			Handler:    _Friendship_ListBlocked_Handler,
+		},
+		{
+			MethodName: "SuggestFriends",
+			Handler:    _Friendship_SuggestFriends_Handler,
+		},
+		{
+			MethodName: "CreateLabel",
+			Handler:    _Friendship_CreateLabel_Handler,
+		},
+		{
+			MethodName: "ListLabels",
+			Handler:    _Friendship_ListLabels_Handler,
+		},
+		{
+			MethodName: "RenameLabel",
+			Handler:    _Friendship_RenameLabel_Handler,
+		},
+		{
+			MethodName: "DeleteLabel",
+			Handler:    _Friendship_DeleteLabel_Handler,
+		},
+		{
+			MethodName: "AddLabelMembers",
+			Handler:    _Friendship_AddLabelMembers_Handler,
+		},
+		{
+			MethodName: "RemoveLabelMembers",
+			Handler:    _Friendship_RemoveLabelMembers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/friendship/friendship.proto",
@@ -0,0 +1,1081 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v7.34.1
+// source: proto/moderation/moderation.proto
+
+package moderation
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ReportRequest reports target_username, or the sender of message_id. When
+// both are set they must match. Messages are end-to-end encrypted, so the
+// reporter's client may disclose the decrypted text in message_plaintext.
+type ReportRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TargetUsername   string                 `protobuf:"bytes,1,opt,name=target_username,json=targetUsername,proto3" json:"target_username,omitempty"`
+	MessageId        string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`                      // optional; a message in one of the caller's conversations
+	Reason           string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`                                             // spam | harassment | hate | sexual_content | violence | impersonation | other
+	Details          string                 `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`                                           // optional, at most 2000 characters
+	MessagePlaintext string                 `protobuf:"bytes,5,opt,name=message_plaintext,json=messagePlaintext,proto3" json:"message_plaintext,omitempty"` // optional; requires message_id
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ReportRequest) Reset() {
+	*x = ReportRequest{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportRequest) ProtoMessage() {}
+
+func (x *ReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportRequest.ProtoReflect.Descriptor instead.
+func (*ReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ReportRequest) GetTargetUsername() string {
+	if x != nil {
+		return x.TargetUsername
+	}
+	return ""
+}
+
+func (x *ReportRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ReportRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReportRequest) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *ReportRequest) GetMessagePlaintext() string {
+	if x != nil {
+		return x.MessagePlaintext
+	}
+	return ""
+}
+
+type ReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReportId      int64                  `protobuf:"varint,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportResponse) Reset() {
+	*x = ReportResponse{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportResponse) ProtoMessage() {}
+
+func (x *ReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportResponse.ProtoReflect.Descriptor instead.
+func (*ReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReportResponse) GetReportId() int64 {
+	if x != nil {
+		return x.ReportId
+	}
+	return 0
+}
+
+func (x *ReportResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ReportResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// ListReportsRequest pages through the moderation queue, oldest first.
+// limit defaults to 20 (max 100).
+type ListReportsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // open | triaged | resolved | dismissed; empty for all
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	AfterId       int64                  `protobuf:"varint,3,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"` // next_after_id from the previous page; 0 for the first page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReportsRequest) Reset() {
+	*x = ListReportsRequest{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReportsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReportsRequest) ProtoMessage() {}
+
+func (x *ListReportsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReportsRequest.ProtoReflect.Descriptor instead.
+func (*ListReportsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListReportsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListReportsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListReportsRequest) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+type ModerationReport struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ReporterUsername  string                 `protobuf:"bytes,2,opt,name=reporter_username,json=reporterUsername,proto3" json:"reporter_username,omitempty"`
+	TargetUsername    string                 `protobuf:"bytes,3,opt,name=target_username,json=targetUsername,proto3" json:"target_username,omitempty"`
+	MessageId         string                 `protobuf:"bytes,4,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	MessagePlaintext  string                 `protobuf:"bytes,5,opt,name=message_plaintext,json=messagePlaintext,proto3" json:"message_plaintext,omitempty"`
+	Reason            string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	Details           string                 `protobuf:"bytes,7,opt,name=details,proto3" json:"details,omitempty"`
+	Status            string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	AssigneeUsername  string                 `protobuf:"bytes,9,opt,name=assignee_username,json=assigneeUsername,proto3" json:"assignee_username,omitempty"`
+	ResolutionNote    string                 `protobuf:"bytes,10,opt,name=resolution_note,json=resolutionNote,proto3" json:"resolution_note,omitempty"`
+	TargetReportCount int32                  `protobuf:"varint,11,opt,name=target_report_count,json=targetReportCount,proto3" json:"target_report_count,omitempty"` // reports against the target, including this one
+	CreatedAt         string                 `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt         string                 `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ResolvedAt        string                 `protobuf:"bytes,14,opt,name=resolved_at,json=resolvedAt,proto3" json:"resolved_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ModerationReport) Reset() {
+	*x = ModerationReport{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerationReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerationReport) ProtoMessage() {}
+
+func (x *ModerationReport) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerationReport.ProtoReflect.Descriptor instead.
+func (*ModerationReport) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ModerationReport) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ModerationReport) GetReporterUsername() string {
+	if x != nil {
+		return x.ReporterUsername
+	}
+	return ""
+}
+
+func (x *ModerationReport) GetTargetUsername() string {
+	if x != nil {
+		return x.TargetUsername
+	}
+	return ""
+}
+
+func (x *ModerationReport) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ModerationReport) GetMessagePlaintext() string {
+	if x != nil {
+		return x.MessagePlaintext
+	}
+	return ""
+}
+
+func (x *ModerationReport) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ModerationReport) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *ModerationReport) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ModerationReport) GetAssigneeUsername() string {
+	if x != nil {
+		return x.AssigneeUsername
+	}
+	return ""
+}
+
+func (x *ModerationReport) GetResolutionNote() string {
+	if x != nil {
+		return x.ResolutionNote
+	}
+	return ""
+}
+
+func (x *ModerationReport) GetTargetReportCount() int32 {
+	if x != nil {
+		return x.TargetReportCount
+	}
+	return 0
+}
+
+func (x *ModerationReport) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ModerationReport) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *ModerationReport) GetResolvedAt() string {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return ""
+}
+
+type ListReportsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reports       []*ModerationReport    `protobuf:"bytes,1,rep,name=reports,proto3" json:"reports,omitempty"`
+	NextAfterId   int64                  `protobuf:"varint,2,opt,name=next_after_id,json=nextAfterId,proto3" json:"next_after_id,omitempty"` // 0 when there are no more pages
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReportsResponse) Reset() {
+	*x = ListReportsResponse{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReportsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReportsResponse) ProtoMessage() {}
+
+func (x *ListReportsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReportsResponse.ProtoReflect.Descriptor instead.
+func (*ListReportsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListReportsResponse) GetReports() []*ModerationReport {
+	if x != nil {
+		return x.Reports
+	}
+	return nil
+}
+
+func (x *ListReportsResponse) GetNextAfterId() int64 {
+	if x != nil {
+		return x.NextAfterId
+	}
+	return 0
+}
+
+// TriageReportRequest assigns an open report to the caller.
+type TriageReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReportId      int64                  `protobuf:"varint,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriageReportRequest) Reset() {
+	*x = TriageReportRequest{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriageReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriageReportRequest) ProtoMessage() {}
+
+func (x *TriageReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriageReportRequest.ProtoReflect.Descriptor instead.
+func (*TriageReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TriageReportRequest) GetReportId() int64 {
+	if x != nil {
+		return x.ReportId
+	}
+	return 0
+}
+
+type TriageReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriageReportResponse) Reset() {
+	*x = TriageReportResponse{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriageReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriageReportResponse) ProtoMessage() {}
+
+func (x *TriageReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriageReportResponse.ProtoReflect.Descriptor instead.
+func (*TriageReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{6}
+}
+
+// ResolveReportRequest closes an open or triaged report. action "none"
+// dismisses it; warn, suspend and ban act on the reported user.
+type ResolveReportRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ReportId       int64                  `protobuf:"varint,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	Action         string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`                                        // none | warn | suspend | ban
+	Note           string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`                                            // shown to the user for warn and suspend
+	SuspendSeconds int64                  `protobuf:"varint,4,opt,name=suspend_seconds,json=suspendSeconds,proto3" json:"suspend_seconds,omitempty"` // required for suspend
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ResolveReportRequest) Reset() {
+	*x = ResolveReportRequest{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveReportRequest) ProtoMessage() {}
+
+func (x *ResolveReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveReportRequest.ProtoReflect.Descriptor instead.
+func (*ResolveReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ResolveReportRequest) GetReportId() int64 {
+	if x != nil {
+		return x.ReportId
+	}
+	return 0
+}
+
+func (x *ResolveReportRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ResolveReportRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *ResolveReportRequest) GetSuspendSeconds() int64 {
+	if x != nil {
+		return x.SuspendSeconds
+	}
+	return 0
+}
+
+type ResolveReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveReportResponse) Reset() {
+	*x = ResolveReportResponse{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveReportResponse) ProtoMessage() {}
+
+func (x *ResolveReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveReportResponse.ProtoReflect.Descriptor instead.
+func (*ResolveReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ResolveReportResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// ModerateUserRequest acts on a user without a report. action "lift" ends a
+// suspension or ban.
+type ModerateUserRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Username       string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Action         string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"` // warn | suspend | ban | lift
+	Note           string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	SuspendSeconds int64                  `protobuf:"varint,4,opt,name=suspend_seconds,json=suspendSeconds,proto3" json:"suspend_seconds,omitempty"` // required for suspend
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ModerateUserRequest) Reset() {
+	*x = ModerateUserRequest{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerateUserRequest) ProtoMessage() {}
+
+func (x *ModerateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerateUserRequest.ProtoReflect.Descriptor instead.
+func (*ModerateUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ModerateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ModerateUserRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ModerateUserRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *ModerateUserRequest) GetSuspendSeconds() int64 {
+	if x != nil {
+		return x.SuspendSeconds
+	}
+	return 0
+}
+
+type ModerateUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModerateUserResponse) Reset() {
+	*x = ModerateUserResponse{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerateUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerateUserResponse) ProtoMessage() {}
+
+func (x *ModerateUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerateUserResponse.ProtoReflect.Descriptor instead.
+func (*ModerateUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{10}
+}
+
+type ListUserActionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserActionsRequest) Reset() {
+	*x = ListUserActionsRequest{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserActionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserActionsRequest) ProtoMessage() {}
+
+func (x *ListUserActionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserActionsRequest.ProtoReflect.Descriptor instead.
+func (*ListUserActionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListUserActionsRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type ModerationAction struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Action            string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Note              string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	ModeratorUsername string                 `protobuf:"bytes,4,opt,name=moderator_username,json=moderatorUsername,proto3" json:"moderator_username,omitempty"`
+	ReportId          int64                  `protobuf:"varint,5,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`   // 0 when taken without a report
+	ExpiresAt         string                 `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // suspensions only
+	CreatedAt         string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ModerationAction) Reset() {
+	*x = ModerationAction{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerationAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerationAction) ProtoMessage() {}
+
+func (x *ModerationAction) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerationAction.ProtoReflect.Descriptor instead.
+func (*ModerationAction) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ModerationAction) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ModerationAction) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ModerationAction) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *ModerationAction) GetModeratorUsername() string {
+	if x != nil {
+		return x.ModeratorUsername
+	}
+	return ""
+}
+
+func (x *ModerationAction) GetReportId() int64 {
+	if x != nil {
+		return x.ReportId
+	}
+	return 0
+}
+
+func (x *ModerationAction) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *ModerationAction) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListUserActionsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Actions         []*ModerationAction    `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty"`                                        // newest first
+	Restriction     string                 `protobuf:"bytes,2,opt,name=restriction,proto3" json:"restriction,omitempty"`                                // suspend | ban; empty when none is in force
+	RestrictedUntil string                 `protobuf:"bytes,3,opt,name=restricted_until,json=restrictedUntil,proto3" json:"restricted_until,omitempty"` // suspensions only
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListUserActionsResponse) Reset() {
+	*x = ListUserActionsResponse{}
+	mi := &file_proto_moderation_moderation_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserActionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserActionsResponse) ProtoMessage() {}
+
+func (x *ListUserActionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_moderation_moderation_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserActionsResponse.ProtoReflect.Descriptor instead.
+func (*ListUserActionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_moderation_moderation_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListUserActionsResponse) GetActions() []*ModerationAction {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+func (x *ListUserActionsResponse) GetRestriction() string {
+	if x != nil {
+		return x.Restriction
+	}
+	return ""
+}
+
+func (x *ListUserActionsResponse) GetRestrictedUntil() string {
+	if x != nil {
+		return x.RestrictedUntil
+	}
+	return ""
+}
+
+var File_proto_moderation_moderation_proto protoreflect.FileDescriptor
+
+const file_proto_moderation_moderation_proto_rawDesc = "" +
+	"\n" +
+	"!proto/moderation/moderation.proto\x12\n" +
+	"moderation\"\xb6\x01\n" +
+	"\rReportRequest\x12'\n" +
+	"\x0ftarget_username\x18\x01 \x01(\tR\x0etargetUsername\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x18\n" +
+	"\adetails\x18\x04 \x01(\tR\adetails\x12+\n" +
+	"\x11message_plaintext\x18\x05 \x01(\tR\x10messagePlaintext\"d\n" +
+	"\x0eReportResponse\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\x03R\breportId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\"]\n" +
+	"\x12ListReportsRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x19\n" +
+	"\bafter_id\x18\x03 \x01(\x03R\aafterId\"\xf3\x03\n" +
+	"\x10ModerationReport\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12+\n" +
+	"\x11reporter_username\x18\x02 \x01(\tR\x10reporterUsername\x12'\n" +
+	"\x0ftarget_username\x18\x03 \x01(\tR\x0etargetUsername\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x04 \x01(\tR\tmessageId\x12+\n" +
+	"\x11message_plaintext\x18\x05 \x01(\tR\x10messagePlaintext\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x18\n" +
+	"\adetails\x18\a \x01(\tR\adetails\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12+\n" +
+	"\x11assignee_username\x18\t \x01(\tR\x10assigneeUsername\x12'\n" +
+	"\x0fresolution_note\x18\n" +
+	" \x01(\tR\x0eresolutionNote\x12.\n" +
+	"\x13target_report_count\x18\v \x01(\x05R\x11targetReportCount\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\f \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\tR\tupdatedAt\x12\x1f\n" +
+	"\vresolved_at\x18\x0e \x01(\tR\n" +
+	"resolvedAt\"q\n" +
+	"\x13ListReportsResponse\x126\n" +
+	"\areports\x18\x01 \x03(\v2\x1c.moderation.ModerationReportR\areports\x12\"\n" +
+	"\rnext_after_id\x18\x02 \x01(\x03R\vnextAfterId\"2\n" +
+	"\x13TriageReportRequest\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\x03R\breportId\"\x16\n" +
+	"\x14TriageReportResponse\"\x88\x01\n" +
+	"\x14ResolveReportRequest\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\x03R\breportId\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\x12'\n" +
+	"\x0fsuspend_seconds\x18\x04 \x01(\x03R\x0esuspendSeconds\"/\n" +
+	"\x15ResolveReportResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"\x86\x01\n" +
+	"\x13ModerateUserRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\x12'\n" +
+	"\x0fsuspend_seconds\x18\x04 \x01(\x03R\x0esuspendSeconds\"\x16\n" +
+	"\x14ModerateUserResponse\"4\n" +
+	"\x16ListUserActionsRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"\xd8\x01\n" +
+	"\x10ModerationAction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\x12-\n" +
+	"\x12moderator_username\x18\x04 \x01(\tR\x11moderatorUsername\x12\x1b\n" +
+	"\treport_id\x18\x05 \x01(\x03R\breportId\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\tR\texpiresAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"\x9e\x01\n" +
+	"\x17ListUserActionsResponse\x126\n" +
+	"\aactions\x18\x01 \x03(\v2\x1c.moderation.ModerationActionR\aactions\x12 \n" +
+	"\vrestriction\x18\x02 \x01(\tR\vrestriction\x12)\n" +
+	"\x10restricted_until\x18\x03 \x01(\tR\x0frestrictedUntil2\xf5\x03\n" +
+	"\n" +
+	"Moderation\x12?\n" +
+	"\x06Report\x12\x19.moderation.ReportRequest\x1a\x1a.moderation.ReportResponse\x12N\n" +
+	"\vListReports\x12\x1e.moderation.ListReportsRequest\x1a\x1f.moderation.ListReportsResponse\x12Q\n" +
+	"\fTriageReport\x12\x1f.moderation.TriageReportRequest\x1a .moderation.TriageReportResponse\x12T\n" +
+	"\rResolveReport\x12 .moderation.ResolveReportRequest\x1a!.moderation.ResolveReportResponse\x12Q\n" +
+	"\fModerateUser\x12\x1f.moderation.ModerateUserRequest\x1a .moderation.ModerateUserResponse\x12Z\n" +
+	"\x0fListUserActions\x12\".moderation.ListUserActionsRequest\x1a#.moderation.ListUserActionsResponseB\x13Z\x11proto/moderation/b\x06proto3"
+
+var (
+	file_proto_moderation_moderation_proto_rawDescOnce sync.Once
+	file_proto_moderation_moderation_proto_rawDescData []byte
+)
+
+func file_proto_moderation_moderation_proto_rawDescGZIP() []byte {
+	file_proto_moderation_moderation_proto_rawDescOnce.Do(func() {
+		file_proto_moderation_moderation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_moderation_moderation_proto_rawDesc), len(file_proto_moderation_moderation_proto_rawDesc)))
+	})
+	return file_proto_moderation_moderation_proto_rawDescData
+}
+
+var file_proto_moderation_moderation_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_proto_moderation_moderation_proto_goTypes = []any{
+	(*ReportRequest)(nil),           // 0: moderation.ReportRequest
+	(*ReportResponse)(nil),          // 1: moderation.ReportResponse
+	(*ListReportsRequest)(nil),      // 2: moderation.ListReportsRequest
+	(*ModerationReport)(nil),        // 3: moderation.ModerationReport
+	(*ListReportsResponse)(nil),     // 4: moderation.ListReportsResponse
+	(*TriageReportRequest)(nil),     // 5: moderation.TriageReportRequest
+	(*TriageReportResponse)(nil),    // 6: moderation.TriageReportResponse
+	(*ResolveReportRequest)(nil),    // 7: moderation.ResolveReportRequest
+	(*ResolveReportResponse)(nil),   // 8: moderation.ResolveReportResponse
+	(*ModerateUserRequest)(nil),     // 9: moderation.ModerateUserRequest
+	(*ModerateUserResponse)(nil),    // 10: moderation.ModerateUserResponse
+	(*ListUserActionsRequest)(nil),  // 11: moderation.ListUserActionsRequest
+	(*ModerationAction)(nil),        // 12: moderation.ModerationAction
+	(*ListUserActionsResponse)(nil), // 13: moderation.ListUserActionsResponse
+}
+var file_proto_moderation_moderation_proto_depIdxs = []int32{
+	3,  // 0: moderation.ListReportsResponse.reports:type_name -> moderation.ModerationReport
+	12, // 1: moderation.ListUserActionsResponse.actions:type_name -> moderation.ModerationAction
+	0,  // 2: moderation.Moderation.Report:input_type -> moderation.ReportRequest
+	2,  // 3: moderation.Moderation.ListReports:input_type -> moderation.ListReportsRequest
+	5,  // 4: moderation.Moderation.TriageReport:input_type -> moderation.TriageReportRequest
+	7,  // 5: moderation.Moderation.ResolveReport:input_type -> moderation.ResolveReportRequest
+	9,  // 6: moderation.Moderation.ModerateUser:input_type -> moderation.ModerateUserRequest
+	11, // 7: moderation.Moderation.ListUserActions:input_type -> moderation.ListUserActionsRequest
+	1,  // 8: moderation.Moderation.Report:output_type -> moderation.ReportResponse
+	4,  // 9: moderation.Moderation.ListReports:output_type -> moderation.ListReportsResponse
+	6,  // 10: moderation.Moderation.TriageReport:output_type -> moderation.TriageReportResponse
+	8,  // 11: moderation.Moderation.ResolveReport:output_type -> moderation.ResolveReportResponse
+	10, // 12: moderation.Moderation.ModerateUser:output_type -> moderation.ModerateUserResponse
+	13, // 13: moderation.Moderation.ListUserActions:output_type -> moderation.ListUserActionsResponse
+	8,  // [8:14] is the sub-list for method output_type
+	2,  // [2:8] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_proto_moderation_moderation_proto_init() }
+func file_proto_moderation_moderation_proto_init() {
+	if File_proto_moderation_moderation_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_moderation_moderation_proto_rawDesc), len(file_proto_moderation_moderation_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_moderation_moderation_proto_goTypes,
+		DependencyIndexes: file_proto_moderation_moderation_proto_depIdxs,
+		MessageInfos:      file_proto_moderation_moderation_proto_msgTypes,
+	}.Build()
+	File_proto_moderation_moderation_proto = out.File
+	file_proto_moderation_moderation_proto_goTypes = nil
+	file_proto_moderation_moderation_proto_depIdxs = nil
+}
@@ -0,0 +1,317 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.1
+// - protoc             v7.34.1
+// source: proto/moderation/moderation.proto
+
+package moderation
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Moderation_Report_FullMethodName          = "/moderation.Moderation/Report"
+	Moderation_ListReports_FullMethodName     = "/moderation.Moderation/ListReports"
+	Moderation_TriageReport_FullMethodName    = "/moderation.Moderation/TriageReport"
+	Moderation_ResolveReport_FullMethodName   = "/moderation.Moderation/ResolveReport"
+	Moderation_ModerateUser_FullMethodName    = "/moderation.Moderation/ModerateUser"
+	Moderation_ListUserActions_FullMethodName = "/moderation.Moderation/ListUserActions"
+)
+
+// ModerationClient is the client API for Moderation service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Moderation handles abuse reports. Report is open to every signed-in user;
+// every other RPC is for site admins only.
+type ModerationClient interface {
+	Report(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*ReportResponse, error)
+	ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ListReportsResponse, error)
+	TriageReport(ctx context.Context, in *TriageReportRequest, opts ...grpc.CallOption) (*TriageReportResponse, error)
+	ResolveReport(ctx context.Context, in *ResolveReportRequest, opts ...grpc.CallOption) (*ResolveReportResponse, error)
+	ModerateUser(ctx context.Context, in *ModerateUserRequest, opts ...grpc.CallOption) (*ModerateUserResponse, error)
+	ListUserActions(ctx context.Context, in *ListUserActionsRequest, opts ...grpc.CallOption) (*ListUserActionsResponse, error)
+}
+
+type moderationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewModerationClient(cc grpc.ClientConnInterface) ModerationClient {
+	return &moderationClient{cc}
+}
+
+func (c *moderationClient) Report(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (*ReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportResponse)
+	err := c.cc.Invoke(ctx, Moderation_Report_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moderationClient) ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ListReportsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReportsResponse)
+	err := c.cc.Invoke(ctx, Moderation_ListReports_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moderationClient) TriageReport(ctx context.Context, in *TriageReportRequest, opts ...grpc.CallOption) (*TriageReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TriageReportResponse)
+	err := c.cc.Invoke(ctx, Moderation_TriageReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moderationClient) ResolveReport(ctx context.Context, in *ResolveReportRequest, opts ...grpc.CallOption) (*ResolveReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveReportResponse)
+	err := c.cc.Invoke(ctx, Moderation_ResolveReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moderationClient) ModerateUser(ctx context.Context, in *ModerateUserRequest, opts ...grpc.CallOption) (*ModerateUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ModerateUserResponse)
+	err := c.cc.Invoke(ctx, Moderation_ModerateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moderationClient) ListUserActions(ctx context.Context, in *ListUserActionsRequest, opts ...grpc.CallOption) (*ListUserActionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserActionsResponse)
+	err := c.cc.Invoke(ctx, Moderation_ListUserActions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ModerationServer is the server API for Moderation service.
+// All implementations must embed UnimplementedModerationServer
+// for forward compatibility.
+//
+// Moderation handles abuse reports. Report is open to every signed-in user;
+// every other RPC is for site admins only.
+type ModerationServer interface {
+	Report(context.Context, *ReportRequest) (*ReportResponse, error)
+	ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error)
+	TriageReport(context.Context, *TriageReportRequest) (*TriageReportResponse, error)
+	ResolveReport(context.Context, *ResolveReportRequest) (*ResolveReportResponse, error)
+	ModerateUser(context.Context, *ModerateUserRequest) (*ModerateUserResponse, error)
+	ListUserActions(context.Context, *ListUserActionsRequest) (*ListUserActionsResponse, error)
+	mustEmbedUnimplementedModerationServer()
+}
+
+// UnimplementedModerationServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedModerationServer struct{}
+
+func (UnimplementedModerationServer) Report(context.Context, *ReportRequest) (*ReportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Report not implemented")
+}
+func (UnimplementedModerationServer) ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListReports not implemented")
+}
+func (UnimplementedModerationServer) TriageReport(context.Context, *TriageReportRequest) (*TriageReportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TriageReport not implemented")
+}
+func (UnimplementedModerationServer) ResolveReport(context.Context, *ResolveReportRequest) (*ResolveReportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResolveReport not implemented")
+}
+func (UnimplementedModerationServer) ModerateUser(context.Context, *ModerateUserRequest) (*ModerateUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ModerateUser not implemented")
+}
+func (UnimplementedModerationServer) ListUserActions(context.Context, *ListUserActionsRequest) (*ListUserActionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListUserActions not implemented")
+}
+func (UnimplementedModerationServer) mustEmbedUnimplementedModerationServer() {}
+func (UnimplementedModerationServer) testEmbeddedByValue()                    {}
+
+// UnsafeModerationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ModerationServer will
+// result in compilation errors.
+type UnsafeModerationServer interface {
+	mustEmbedUnimplementedModerationServer()
+}
+
+func RegisterModerationServer(s grpc.ServiceRegistrar, srv ModerationServer) {
+	// If the following call panics, it indicates UnimplementedModerationServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Moderation_ServiceDesc, srv)
+}
+
+func _Moderation_Report_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModerationServer).Report(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Moderation_Report_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModerationServer).Report(ctx, req.(*ReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moderation_ListReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModerationServer).ListReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Moderation_ListReports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModerationServer).ListReports(ctx, req.(*ListReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moderation_TriageReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriageReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModerationServer).TriageReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Moderation_TriageReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModerationServer).TriageReport(ctx, req.(*TriageReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moderation_ResolveReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModerationServer).ResolveReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Moderation_ResolveReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModerationServer).ResolveReport(ctx, req.(*ResolveReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moderation_ModerateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModerationServer).ModerateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Moderation_ModerateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModerationServer).ModerateUser(ctx, req.(*ModerateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Moderation_ListUserActions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserActionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ModerationServer).ListUserActions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Moderation_ListUserActions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ModerationServer).ListUserActions(ctx, req.(*ListUserActionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Moderation_ServiceDesc is the grpc.ServiceDesc for Moderation service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Moderation_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "moderation.Moderation",
+	HandlerType: (*ModerationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Report",
+			Handler:    _Moderation_Report_Handler,
+		},
+		{
+			MethodName: "ListReports",
+			Handler:    _Moderation_ListReports_Handler,
+		},
+		{
+			MethodName: "TriageReport",
+			Handler:    _Moderation_TriageReport_Handler,
+		},
+		{
+			MethodName: "ResolveReport",
+			Handler:    _Moderation_ResolveReport_Handler,
+		},
+		{
+			MethodName: "ModerateUser",
+			Handler:    _Moderation_ModerateUser_Handler,
+		},
+		{
+			MethodName: "ListUserActions",
+			Handler:    _Moderation_ListUserActions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/moderation/moderation.proto",
+}
@@ -0,0 +1,9 @@
+-- ── System messages ────────────────────────────────────────────────────────────
+-- Server-generated, unencrypted conversation events (member added, renamed, ...).
+-- content holds a JSON payload; sender_id is the user who triggered the event.
+ALTER TYPE message_type ADD VALUE IF NOT EXISTS 'system';
+
+-- messages: history paging is keyed on (created_at, id)
+CREATE INDEX IF NOT EXISTS idx_messages_conv_time_id
+    ON messages (conversation_id, created_at, id)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,28 @@
+-- ── Conversation Exports ───────────────────────────────────────────────────────
+-- Background archive jobs. The finished archive is written to disk by the
+-- backend and served through download_token until expires_at.
+CREATE TYPE export_status AS ENUM ('pending', 'running', 'done', 'failed');
+
+CREATE TABLE IF NOT EXISTS conversation_exports (
+    id               UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    conversation_id  BIGINT        NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    user_id          UUID          NOT NULL REFERENCES users(user_id)  ON DELETE CASCADE,
+    status           export_status NOT NULL DEFAULT 'pending',
+    download_token   TEXT          UNIQUE,   -- set when status = 'done'
+    file_path        TEXT,                   -- set when status = 'done'
+    error            TEXT,                   -- set when status = 'failed'
+    expires_at       TIMESTAMPTZ,            -- download link expiry
+    created_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    started_at       TIMESTAMPTZ             -- worker lease; set when claimed
+);
+
+-- conversation_exports: worker picks the oldest pending job
+CREATE INDEX IF NOT EXISTS idx_conversation_exports_pending
+    ON conversation_exports (created_at)
+    WHERE status = 'pending';
+
+-- conversation_exports: worker reclaims running jobs whose lease ran out
+CREATE INDEX IF NOT EXISTS idx_conversation_exports_running
+    ON conversation_exports (started_at)
+    WHERE status = 'running';
@@ -0,0 +1,8 @@
+-- ── Per-member conversation visibility ─────────────────────────────────────────
+-- cleared_at: messages created at or before this time are hidden from the
+--             member's history (ClearHistory).
+-- hidden_at:  the member deleted the DM; it is left out of their conversation
+--             list until either side writes again (DeleteConversation).
+ALTER TABLE conversation_members
+    ADD COLUMN IF NOT EXISTS cleared_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS hidden_at  TIMESTAMPTZ;
@@ -0,0 +1,13 @@
+-- ── Trigram search ─────────────────────────────────────────────────────────────
+-- Backs the substring (ILIKE) and fuzzy (%) matching in SearchUsers and
+-- GetConversationsByName.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_users_user_name_trgm
+    ON users USING gin (user_name gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_users_display_name_trgm
+    ON users USING gin (display_name gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_conversations_name_trgm
+    ON conversations USING gin (name gin_trgm_ops);
@@ -0,0 +1,14 @@
+-- ── Message search tokens ──────────────────────────────────────────────────────
+-- Blind-index keywords for E2EE messages. Each token is a keyed HMAC the
+-- client derives from a keyword and a per-conversation search key, so the
+-- server can match equal tokens without learning the plaintext.
+CREATE TABLE IF NOT EXISTS message_search_tokens (
+    message_id       UUID   NOT NULL REFERENCES messages(id)      ON DELETE CASCADE,
+    conversation_id  BIGINT NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    token            TEXT   NOT NULL,
+    PRIMARY KEY (message_id, token)
+);
+
+-- message_search_tokens: look up messages carrying a token within a conversation
+CREATE INDEX IF NOT EXISTS idx_message_search_tokens_lookup
+    ON message_search_tokens (conversation_id, token);
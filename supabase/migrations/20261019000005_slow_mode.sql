@@ -0,0 +1,10 @@
+-- ── Slow mode ──────────────────────────────────────────────────────────────────
+-- slow_mode_seconds: minimum gap between two messages from the same member of
+--                    a group; 0 turns slow mode off. Admins and owners are exempt.
+-- last_sent_at:      when the member last sent a message, used to enforce it.
+ALTER TABLE conversations
+    ADD COLUMN IF NOT EXISTS slow_mode_seconds INT NOT NULL DEFAULT 0
+        CHECK (slow_mode_seconds BETWEEN 0 AND 21600);
+
+ALTER TABLE conversation_members
+    ADD COLUMN IF NOT EXISTS last_sent_at TIMESTAMPTZ;
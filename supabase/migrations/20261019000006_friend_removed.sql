@@ -0,0 +1,4 @@
+-- ── Unfriend ───────────────────────────────────────────────────────────────────
+-- Sent to a user when a friend removes them, so their friend list and the
+-- (now read-only) DM can refresh. reference_id is the DM's conversation_id.
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'friend_removed';
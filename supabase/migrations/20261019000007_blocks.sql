@@ -0,0 +1,14 @@
+-- ── Blocks ─────────────────────────────────────────────────────────────────────
+-- blocker_id has blocked blocked_id. A block is one-directional; either side
+-- blocking is enough to stop friend requests, DMs and receipts between them.
+CREATE TABLE IF NOT EXISTS blocks (
+    blocker_id  UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    blocked_id  UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id != blocked_id)
+);
+
+-- blocks: find everyone who blocked a user (search filtering)
+CREATE INDEX IF NOT EXISTS idx_blocks_blocked
+    ON blocks (blocked_id);
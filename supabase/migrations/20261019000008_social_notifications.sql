@@ -0,0 +1,26 @@
+-- ── Social notifications ───────────────────────────────────────────────────────
+-- friend_accepted: the recipient accepted the user's friend request.
+-- group_invite:    the user was included when a group was created.
+-- added_to_group:  an admin added the user to an existing group.
+-- role_changed:    the owner promoted or demoted the user in a group.
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'friend_accepted';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'group_invite';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'added_to_group';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'role_changed';
+
+-- ── Typed references ───────────────────────────────────────────────────────────
+-- reference_id could only hold a conversation_id and was not checked. Split it
+-- into one foreign key per referenced table; at most one may be set.
+ALTER TABLE notifications RENAME COLUMN reference_id TO reference_conversation_id;
+
+UPDATE notifications n
+SET reference_conversation_id = NULL
+WHERE reference_conversation_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM conversations c WHERE c.id = n.reference_conversation_id);
+
+ALTER TABLE notifications
+    ADD CONSTRAINT notifications_reference_conversation_id_fkey
+        FOREIGN KEY (reference_conversation_id) REFERENCES conversations(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS reference_user_id UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    ADD CONSTRAINT notifications_single_reference
+        CHECK (num_nonnulls(reference_conversation_id, reference_user_id) <= 1);
@@ -0,0 +1,7 @@
+-- ── Profiles ───────────────────────────────────────────────────────────────────
+-- bio and a custom status shown next to the display name. The status is hidden
+-- once status_expires_at has passed; NULL means it does not expire.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS bio               VARCHAR(500),
+    ADD COLUMN IF NOT EXISTS status_text       VARCHAR(140),
+    ADD COLUMN IF NOT EXISTS status_expires_at TIMESTAMPTZ;
@@ -0,0 +1,24 @@
+-- ── Username history ───────────────────────────────────────────────────────────
+-- One row per rename, holding the name the user gave up. It enforces the
+-- rename cooldown and keeps a released name reserved for its previous owner
+-- for a while, so it cannot be picked up to impersonate them.
+CREATE TABLE IF NOT EXISTS username_history (
+    id          BIGSERIAL   PRIMARY KEY,
+    user_id     UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    user_name   VARCHAR(50) NOT NULL,
+    changed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- username_history: a user's renames, newest first (cooldown check)
+CREATE INDEX IF NOT EXISTS idx_username_history_user
+    ON username_history (user_id, changed_at DESC);
+
+-- username_history: who last released a name (reservation check)
+CREATE INDEX IF NOT EXISTS idx_username_history_name
+    ON username_history (user_name, changed_at DESC);
+
+-- ── GitHub identity ────────────────────────────────────────────────────────────
+-- GitHub accounts used to be matched by login == user_name, which breaks once
+-- either side is renamed. Match on GitHub's numeric user ID instead; existing
+-- accounts are linked on their next GitHub login.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS github_id BIGINT UNIQUE;
@@ -0,0 +1,53 @@
+-- ── Privacy settings ───────────────────────────────────────────────────────────
+-- Who a privacy setting applies to. Each setting only allows the values that
+-- make sense for it (see the CHECKs on user_settings).
+CREATE TYPE privacy_audience AS ENUM ('everyone', 'friends_of_friends', 'friends', 'nobody');
+
+-- One row per user who changed a privacy setting; users without a row get the
+-- column defaults.
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id               UUID             PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    -- who can find the user in search and friend suggestions
+    discoverability       privacy_audience NOT NULL DEFAULT 'everyone'
+                          CHECK (discoverability IN ('everyone', 'friends_of_friends', 'nobody')),
+    -- who can send the user a friend request
+    friend_request_policy privacy_audience NOT NULL DEFAULT 'everyone'
+                          CHECK (friend_request_policy IN ('everyone', 'friends_of_friends', 'nobody')),
+    -- whether other members learn how far the user has read
+    read_receipts         BOOLEAN          NOT NULL DEFAULT TRUE,
+    -- who can see users.last_seen_at
+    last_seen_visibility  privacy_audience NOT NULL DEFAULT 'everyone'
+                          CHECK (last_seen_visibility IN ('everyone', 'friends', 'nobody')),
+    updated_at            TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+-- audience_allows reports whether viewer_id is within audience as seen from
+-- owner_id. Users are always within their own audience.
+CREATE OR REPLACE FUNCTION audience_allows(audience privacy_audience, owner_id UUID, viewer_id UUID)
+RETURNS BOOLEAN
+LANGUAGE sql STABLE
+AS $$
+  SELECT owner_id = viewer_id OR CASE audience
+    WHEN 'everyone' THEN TRUE
+    WHEN 'nobody' THEN FALSE
+    WHEN 'friends' THEN EXISTS (
+      SELECT 1 FROM friendships f
+      WHERE f.status = 'accepted'
+        AND ((f.user1_userid = owner_id AND f.user2_userid = viewer_id)
+          OR (f.user1_userid = viewer_id AND f.user2_userid = owner_id))
+    )
+    WHEN 'friends_of_friends' THEN EXISTS (
+      SELECT 1 FROM friendships f
+      WHERE f.status = 'accepted'
+        AND ((f.user1_userid = owner_id AND f.user2_userid = viewer_id)
+          OR (f.user1_userid = viewer_id AND f.user2_userid = owner_id))
+    ) OR EXISTS (
+      SELECT 1 FROM friendships a, friendships b
+      WHERE a.status = 'accepted' AND b.status = 'accepted'
+        AND owner_id IN (a.user1_userid, a.user2_userid)
+        AND viewer_id IN (b.user1_userid, b.user2_userid)
+        AND (CASE WHEN a.user1_userid = owner_id THEN a.user2_userid ELSE a.user1_userid END)
+          = (CASE WHEN b.user1_userid = viewer_id THEN b.user2_userid ELSE b.user1_userid END)
+    )
+  END
+$$;
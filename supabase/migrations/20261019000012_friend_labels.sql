@@ -0,0 +1,27 @@
+-- ── Friend labels ──────────────────────────────────────────────────────────────
+-- User-owned lists for organizing friends, e.g. "work" or "family". Labels are
+-- private to their owner; members are not told they were added.
+CREATE TABLE IF NOT EXISTS friend_labels (
+    id          BIGSERIAL   PRIMARY KEY,
+    owner_id    UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    name        VARCHAR(50) NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- friend_labels: label names are unique per owner, ignoring case
+CREATE UNIQUE INDEX IF NOT EXISTS idx_friend_labels_owner_name
+    ON friend_labels (owner_id, lower(name));
+
+-- One row per friend in a label. Rows are removed when the owner and the
+-- member stop being friends.
+CREATE TABLE IF NOT EXISTS friend_label_members (
+    label_id    BIGINT      NOT NULL REFERENCES friend_labels(id) ON DELETE CASCADE,
+    user_id     UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    added_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (label_id, user_id)
+);
+
+-- friend_label_members: find the labels a user is in (cleanup on unfriend)
+CREATE INDEX IF NOT EXISTS idx_friend_label_members_user
+    ON friend_label_members (user_id);
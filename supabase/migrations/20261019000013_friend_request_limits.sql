@@ -0,0 +1,29 @@
+-- ── Friend request log ─────────────────────────────────────────────────────────
+-- One row per friend request sent. friendships rows disappear when a request
+-- is rejected, cancelled or expires, so the hourly and daily send limits are
+-- counted here instead. Rows older than a day are purged by the expiry job.
+CREATE TABLE IF NOT EXISTS friend_request_log (
+    id            BIGSERIAL   PRIMARY KEY,
+    sender_id     UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    recipient_id  UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    sent_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- friend_request_log: a sender's recent requests (rate limit check)
+CREATE INDEX IF NOT EXISTS idx_friend_request_log_sender
+    ON friend_request_log (sender_id, sent_at DESC);
+
+-- ── Declined friend requests ───────────────────────────────────────────────────
+-- The last time addressee_id declined a request from requester_id. The
+-- requester may not ask again until the decline cooldown has passed.
+CREATE TABLE IF NOT EXISTS friend_request_declines (
+    requester_id  UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    addressee_id  UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    declined_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (requester_id, addressee_id)
+);
+
+-- friendships: pending requests by age (expiry job)
+CREATE INDEX IF NOT EXISTS idx_friendships_pending_created
+    ON friendships (created_at)
+    WHERE status = 'pending';
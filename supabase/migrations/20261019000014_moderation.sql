@@ -0,0 +1,86 @@
+-- ── Site admins ────────────────────────────────────────────────────────────────
+-- Users allowed to use the moderation service. There is no API to grant this;
+-- insert rows directly.
+CREATE TABLE IF NOT EXISTS site_admins (
+    user_id     UUID        PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    granted_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- ── Reports ────────────────────────────────────────────────────────────────────
+CREATE TYPE report_reason AS ENUM ('spam', 'harassment', 'hate', 'sexual_content', 'violence', 'impersonation', 'other');
+
+-- open:      waiting for a moderator.
+-- triaged:   a moderator has taken it (assigned_to).
+-- resolved:  closed with an action against the target (warn, suspend or ban).
+-- dismissed: closed without action.
+CREATE TYPE report_status AS ENUM ('open', 'triaged', 'resolved', 'dismissed');
+
+-- A user's report against another user, optionally about one message.
+-- Messages are end-to-end encrypted, so message_plaintext is whatever the
+-- reporter's client chose to disclose; the server cannot verify it.
+CREATE TABLE IF NOT EXISTS reports (
+    id                 BIGSERIAL     PRIMARY KEY,
+    reporter_id        UUID          NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    target_user_id     UUID          NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    message_id         UUID          REFERENCES messages(id) ON DELETE SET NULL,
+    reason             report_reason NOT NULL,
+    details            TEXT          NOT NULL DEFAULT '',
+    message_plaintext  TEXT,
+    status             report_status NOT NULL DEFAULT 'open',
+    assigned_to        UUID          REFERENCES users(user_id) ON DELETE SET NULL,
+    resolution_note    TEXT          NOT NULL DEFAULT '',
+    created_at         TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    resolved_at        TIMESTAMPTZ,
+    CHECK (reporter_id <> target_user_id),
+    CHECK (message_plaintext IS NULL OR message_id IS NOT NULL)
+);
+
+-- reports: the moderation queue, oldest first per status
+CREATE INDEX IF NOT EXISTS idx_reports_status
+    ON reports (status, id);
+
+-- reports: one unresolved report per reporter, target and message
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_unresolved
+    ON reports (reporter_id, target_user_id, COALESCE(message_id, '00000000-0000-0000-0000-000000000000'::uuid))
+    WHERE status IN ('open', 'triaged');
+
+-- ── Moderation actions ─────────────────────────────────────────────────────────
+-- warn:    a notification to the user; no restriction.
+-- suspend: the user cannot sign in or use the API until expires_at.
+-- ban:     the user cannot sign in or use the API.
+-- lift:    ends the user's current suspension or ban.
+CREATE TYPE moderation_action_type AS ENUM ('warn', 'suspend', 'ban', 'lift');
+
+-- Every action a moderator took, for the user's record.
+CREATE TABLE IF NOT EXISTS moderation_actions (
+    id            BIGSERIAL              PRIMARY KEY,
+    user_id       UUID                   NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    moderator_id  UUID                   REFERENCES users(user_id) ON DELETE SET NULL,
+    report_id     BIGINT                 REFERENCES reports(id) ON DELETE SET NULL,
+    action        moderation_action_type NOT NULL,
+    note          TEXT                   NOT NULL DEFAULT '',
+    expires_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ            NOT NULL DEFAULT NOW(),
+    CHECK ((action = 'suspend') = (expires_at IS NOT NULL))
+);
+
+-- moderation_actions: a user's record, newest first
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_user
+    ON moderation_actions (user_id, created_at DESC);
+
+-- The suspension or ban currently in force for a user, if any. Written by
+-- suspend and ban actions and removed by lift; a suspension past its
+-- expires_at no longer applies.
+CREATE TABLE IF NOT EXISTS account_restrictions (
+    user_id     UUID                   PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    action_id   BIGINT                 NOT NULL REFERENCES moderation_actions(id) ON DELETE CASCADE,
+    kind        moderation_action_type NOT NULL CHECK (kind IN ('suspend', 'ban')),
+    expires_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ            NOT NULL DEFAULT NOW(),
+    CHECK ((kind = 'suspend') = (expires_at IS NOT NULL))
+);
+
+-- ── Moderation notifications ───────────────────────────────────────────────────
+-- moderation_warning: a moderator warned or suspended the user.
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'moderation_warning';
@@ -0,0 +1,23 @@
+-- ── Refresh tokens ─────────────────────────────────────────────────────────────
+-- Long-lived tokens that mint new access tokens for one login. Only a SHA-256
+-- hash of each token is stored. Every refresh marks the presented token used
+-- and issues its successor for the same login_id. Presenting a used token
+-- again means it leaked: every token of that login is revoked.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id          BIGSERIAL   PRIMARY KEY,
+    user_id     UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    login_id    UUID        NOT NULL,
+    token_hash  TEXT        NOT NULL UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ,
+    revoked_at  TIMESTAMPTZ
+);
+
+-- refresh_tokens: every token of one login (rotation and reuse revocation)
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_login
+    ON refresh_tokens (login_id);
+
+-- refresh_tokens: a user's tokens (purging expired ones)
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user
+    ON refresh_tokens (user_id, expires_at);
@@ -0,0 +1,30 @@
+-- ── Login sessions ─────────────────────────────────────────────────────────────
+-- One row per login, keyed by the login_id claim its JWTs carry. A JWT stays
+-- valid until it expires, so signing out marks the login revoked and every
+-- RPC checks the mark. Logins without a row (tokens issued before this table)
+-- count as active until legacy_login_cutoff.accept_until, below, and as
+-- revoked after it.
+CREATE TABLE IF NOT EXISTS login_sessions (
+    login_id    UUID        PRIMARY KEY,
+    user_id     UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at  TIMESTAMPTZ
+);
+
+-- login_sessions: a user's logins, newest first
+CREATE INDEX IF NOT EXISTS idx_login_sessions_user
+    ON login_sessions (user_id, created_at DESC);
+
+-- ── Logins from before login sessions ─────────────────────────────────────────
+-- Access tokens issued before login_sessions existed carry a login_id without
+-- a row. They are honoured until accept_until, a day after the upgrade, by
+-- which time every one of them has expired (access tokens live 24 hours).
+-- After that a login without a row is refused.
+CREATE TABLE IF NOT EXISTS legacy_login_cutoff (
+    id            BOOLEAN     PRIMARY KEY DEFAULT TRUE CHECK (id),
+    accept_until  TIMESTAMPTZ NOT NULL
+);
+
+INSERT INTO legacy_login_cutoff (accept_until)
+VALUES (NOW() + INTERVAL '24 hours')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,12 @@
+-- ── Login devices ──────────────────────────────────────────────────────────────
+-- Where each login came from, so users can recognise their devices and sign
+-- out one they lost. user_agent and ip_address are those the gateway saw at
+-- sign-in; last_active_at advances as the login makes calls.
+ALTER TABLE login_sessions
+    ADD COLUMN IF NOT EXISTS device_name    TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS user_agent     TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip_address     TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_active_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- Tells a user's other sessions that someone signed in to their account.
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'new_login';
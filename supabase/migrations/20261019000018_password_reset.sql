@@ -0,0 +1,50 @@
+-- ── Recovery emails ────────────────────────────────────────────────────────────
+-- Where password reset links are sent. Optional: set at signup or later with
+-- the current password. Compared case-insensitively.
+CREATE TABLE IF NOT EXISTS recovery_emails (
+    user_id     UUID        PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    email       TEXT        NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- recovery_emails: one account per address, looked up when a reset is asked for
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recovery_emails_email
+    ON recovery_emails (lower(email));
+
+-- ── Password reset tokens ──────────────────────────────────────────────────────
+-- Single-use tokens mailed as reset links. Only a SHA-256 hash of each token
+-- is stored. Asking for a new link retires the user's earlier ones.
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id          BIGSERIAL   PRIMARY KEY,
+    user_id     UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    token_hash  TEXT        NOT NULL UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ
+);
+
+-- password_reset_tokens: a user's outstanding tokens
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user
+    ON password_reset_tokens (user_id);
+
+-- ── Password reset requests ───────────────────────────────────────────────────
+-- One row per accepted reset request, whether or not the address belongs to
+-- an account. RequestPasswordReset only writes the row, so a known and an
+-- unknown address take the same time to answer; PasswordResetWorker looks up
+-- the account, issues the token and sends the mail, then sets processed_at.
+-- Rows are also the per-address rate limit log and are purged after a day.
+CREATE TABLE IF NOT EXISTS password_reset_requests (
+    id            BIGSERIAL   PRIMARY KEY,
+    email         TEXT        NOT NULL,
+    requested_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    processed_at  TIMESTAMPTZ
+);
+
+-- password_reset_requests: an address's recent requests (rate limit check)
+CREATE INDEX IF NOT EXISTS idx_password_reset_requests_email
+    ON password_reset_requests (lower(email), requested_at DESC);
+
+-- password_reset_requests: the queue the worker drains, oldest first
+CREATE INDEX IF NOT EXISTS idx_password_reset_requests_pending
+    ON password_reset_requests (id)
+    WHERE processed_at IS NULL;
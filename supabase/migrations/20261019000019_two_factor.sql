@@ -0,0 +1,43 @@
+-- ── TOTP factors ───────────────────────────────────────────────────────────────
+-- A user's authenticator app secret. Two-factor sign-in is on once
+-- confirmed_at is set; an unconfirmed row is an enrollment in progress.
+-- last_used_step stops a code from being replayed within its time window, and
+-- failed_attempts / last_failed_at lock out guessing across sign-in attempts.
+CREATE TABLE IF NOT EXISTS totp_factors (
+    user_id          UUID        PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    secret           TEXT        NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    confirmed_at     TIMESTAMPTZ,
+    last_used_step   BIGINT      NOT NULL DEFAULT 0,
+    failed_attempts  INT         NOT NULL DEFAULT 0,
+    last_failed_at   TIMESTAMPTZ
+);
+
+-- ── Recovery codes ─────────────────────────────────────────────────────────────
+-- One-time codes that stand in for a TOTP code when the authenticator is
+-- lost. Only a SHA-256 hash of each code is stored.
+CREATE TABLE IF NOT EXISTS totp_recovery_codes (
+    id         BIGSERIAL   PRIMARY KEY,
+    user_id    UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    code_hash  TEXT        NOT NULL,
+    used_at    TIMESTAMPTZ,
+    UNIQUE (user_id, code_hash)
+);
+
+-- ── Login challenges ───────────────────────────────────────────────────────────
+-- Issued instead of a session when a user with two-factor sign-in passes the
+-- first factor; redeemed once with a TOTP or recovery code. Only a SHA-256
+-- hash of each token is stored.
+CREATE TABLE IF NOT EXISTS login_challenges (
+    id           BIGSERIAL   PRIMARY KEY,
+    user_id      UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    token_hash   TEXT        NOT NULL UNIQUE,
+    device_name  TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at   TIMESTAMPTZ NOT NULL,
+    used_at      TIMESTAMPTZ
+);
+
+-- login_challenges: a user's outstanding challenges
+CREATE INDEX IF NOT EXISTS idx_login_challenges_user
+    ON login_challenges (user_id);
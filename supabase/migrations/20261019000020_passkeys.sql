@@ -0,0 +1,41 @@
+-- ── Passkeys ───────────────────────────────────────────────────────────────────
+-- WebAuthn credentials. A user may register several, and one alone signs
+-- them in without a password. sign_count is the authenticator's signature
+-- counter as of the last sign-in; a counter that goes backwards means the key
+-- was cloned. transports is the comma-separated list the browser reported,
+-- handed back so it can offer the right authenticator.
+CREATE TABLE IF NOT EXISTS webauthn_credentials (
+    id               BIGSERIAL   PRIMARY KEY,
+    user_id          UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    credential_id    BYTEA       NOT NULL UNIQUE,
+    public_key       BYTEA       NOT NULL,
+    sign_count       BIGINT      NOT NULL DEFAULT 0,
+    transports       TEXT        NOT NULL DEFAULT '',
+    name             TEXT        NOT NULL DEFAULT '',
+    backup_eligible  BOOLEAN     NOT NULL DEFAULT false,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at     TIMESTAMPTZ
+);
+
+-- webauthn_credentials: a user's passkeys
+CREATE INDEX IF NOT EXISTS idx_webauthn_credentials_user
+    ON webauthn_credentials (user_id);
+
+-- ── WebAuthn challenges ────────────────────────────────────────────────────────
+-- One per registration or sign-in ceremony, spent when the browser's answer is
+-- verified. user_id is NULL for passwordless sign-in, where the passkey the
+-- user picks says who they are. Only a SHA-256 hash of each challenge is
+-- stored.
+CREATE TABLE IF NOT EXISTS webauthn_challenges (
+    id              BIGSERIAL   PRIMARY KEY,
+    challenge_hash  TEXT        NOT NULL UNIQUE,
+    user_id         UUID        REFERENCES users(user_id) ON DELETE CASCADE,
+    purpose         TEXT        NOT NULL CHECK (purpose IN ('register', 'login', 'second_factor')),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ NOT NULL,
+    used_at         TIMESTAMPTZ
+);
+
+-- webauthn_challenges: sweeping expired ceremonies
+CREATE INDEX IF NOT EXISTS idx_webauthn_challenges_expires
+    ON webauthn_challenges (expires_at);
@@ -0,0 +1,43 @@
+-- ── OpenID Connect sign-ups ────────────────────────────────────────────────────
+-- Accounts created through a configured OIDC issuer other than Google.
+ALTER TYPE signup_type ADD VALUE IF NOT EXISTS 'oidc';
+
+-- ── External identities ────────────────────────────────────────────────────────
+-- The provider accounts a user signs in with, matched on the provider's
+-- stable subject ID rather than a name either side may change. provider is
+-- the configured name, e.g. 'github' or 'google'. A user links at most one
+-- account per provider.
+CREATE TABLE IF NOT EXISTS user_identities (
+    provider    TEXT        NOT NULL,
+    subject     TEXT        NOT NULL,
+    user_id     UUID        NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, subject),
+    UNIQUE (user_id, provider)
+);
+
+-- GitHub links move over from users.github_id.
+INSERT INTO user_identities (provider, subject, user_id)
+SELECT 'github', github_id::TEXT, user_id
+FROM users
+WHERE github_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users DROP COLUMN IF EXISTS github_id;
+
+-- ── OAuth sign-in states ───────────────────────────────────────────────────────
+-- One per sign-in started, consumed by the provider's callback. Holds the PKCE
+-- verifier and OIDC nonce the callback must prove. Only a SHA-256 hash of the
+-- state, which travels through the browser, is stored.
+CREATE TABLE IF NOT EXISTS oauth_states (
+    state_hash     TEXT        PRIMARY KEY,
+    provider       TEXT        NOT NULL,
+    code_verifier  TEXT        NOT NULL,
+    nonce          TEXT        NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at     TIMESTAMPTZ NOT NULL
+);
+
+-- oauth_states: sweeping abandoned sign-ins
+CREATE INDEX IF NOT EXISTS idx_oauth_states_expires
+    ON oauth_states (expires_at);
@@ -0,0 +1,23 @@
+-- name: BlockUser :execrows
+INSERT INTO blocks (blocker_id, blocked_id)
+VALUES (@blocker_id, @blocked_id)
+ON CONFLICT DO NOTHING;
+
+-- name: UnblockUser :execrows
+DELETE FROM blocks
+WHERE blocker_id = @blocker_id
+  AND blocked_id = @blocked_id;
+
+-- name: ListBlocked :many
+-- Returns the users blocked by blocker_id, most recently blocked first.
+SELECT u.user_id, u.user_name, u.display_name, u.avatar_url, b.created_at AS blocked_at
+FROM blocks b
+JOIN users u ON u.user_id = b.blocked_id
+WHERE b.blocker_id = @blocker_id
+ORDER BY b.created_at DESC, u.user_id;
+
+-- name: GetBlockState :one
+-- Reports whether user_id has blocked other_id and whether other_id has blocked user_id.
+SELECT
+    EXISTS (SELECT 1 FROM blocks b WHERE b.blocker_id = @user_id AND b.blocked_id = @other_id) AS has_blocked,
+    EXISTS (SELECT 1 FROM blocks b WHERE b.blocker_id = @other_id AND b.blocked_id = @user_id) AS is_blocked_by;
@@ -10,7 +10,7 @@ SELECT EXISTS (
 ) AS is_member;
 
 -- name: GetConversation :one
-SELECT id, is_group, name, created_at, updated_at
+SELECT id, is_group, name, created_at, updated_at, slow_mode_seconds
 FROM conversations
 WHERE id = $1
 LIMIT 1;
@@ -21,27 +21,64 @@ SELECT c.id, c.is_group, c.name, c.created_at, c.updated_at
 FROM conversations c
 JOIN conversation_members cm ON cm.conversation_id = c.id
 WHERE cm.user_id = $1
+  AND cm.hidden_at IS NULL
 ORDER BY c.updated_at DESC;
 
 -- name: GetConversationsByName :many
--- Returns conversations matching the search pattern.
--- For groups: matches conversation name.
--- For DMs: matches the other member's username.
-SELECT c.id, c.is_group, c.name, c.created_at, c.updated_at
-FROM conversations c
-JOIN conversation_members cm ON cm.conversation_id = c.id
-WHERE cm.user_id = $1
-  AND (
-    (c.is_group = true AND c.name ILIKE '%' || $2 || '%')
-    OR (c.is_group = false AND EXISTS (
-      SELECT 1 FROM conversation_members cm2
-      JOIN users u ON u.user_id = cm2.user_id
-      WHERE cm2.conversation_id = c.id
-        AND cm2.user_id != $1
-        AND u.user_name ILIKE '%' || $2 || '%'
-    ))
-  )
-ORDER BY c.updated_at DESC;
+-- Returns the caller's conversations matching the search text.
+-- Groups match on their name; every conversation also matches on the
+-- username or display name of its other members. Results are ranked like
+-- SearchUsers (exact matches first, then trigram similarity) and keyset-paged
+-- on (exact_match, score, id).
+WITH matches AS (
+  SELECT c.id, c.is_group, c.name, c.created_at, c.updated_at,
+      (lower(COALESCE(c.name, '')) = lower(@query::text) OR EXISTS (
+        SELECT 1 FROM conversation_members cm2
+        JOIN users u ON u.user_id = cm2.user_id
+        WHERE cm2.conversation_id = c.id
+          AND cm2.user_id != @caller_id
+          AND lower(u.user_name) = lower(@query::text)
+      ))::int AS exact_match,
+      GREATEST(
+        similarity(COALESCE(c.name, ''), @query::text),
+        COALESCE((
+          SELECT MAX(GREATEST(similarity(u.user_name, @query::text), similarity(COALESCE(u.display_name, ''), @query::text)))
+          FROM conversation_members cm2
+          JOIN users u ON u.user_id = cm2.user_id
+          WHERE cm2.conversation_id = c.id
+            AND cm2.user_id != @caller_id
+        ), 0)
+      )::float8 AS score
+  FROM conversations c
+  JOIN conversation_members cm ON cm.conversation_id = c.id
+  WHERE cm.user_id = @caller_id
+    AND cm.hidden_at IS NULL
+    AND (
+      (c.is_group = true AND (c.name ILIKE '%' || @query::text || '%' OR c.name % @query::text))
+      OR EXISTS (
+        SELECT 1 FROM conversation_members cm2
+        JOIN users u ON u.user_id = cm2.user_id
+        WHERE cm2.conversation_id = c.id
+          AND cm2.user_id != @caller_id
+          AND (u.user_name ILIKE '%' || @query::text || '%'
+            OR u.display_name ILIKE '%' || @query::text || '%'
+            OR u.user_name % @query::text
+            OR u.display_name % @query::text)
+      )
+    )
+)
+SELECT m.id, m.is_group, m.name, m.created_at, m.updated_at, m.exact_match, m.score
+FROM matches m
+WHERE sqlc.narg('cursor_id')::bigint IS NULL
+   OR (m.exact_match, m.score, m.id) < (sqlc.narg('cursor_exact')::int, sqlc.narg('cursor_score')::float8, sqlc.narg('cursor_id')::bigint)
+ORDER BY m.exact_match DESC, m.score DESC, m.id DESC
+LIMIT @page_limit;
+
+-- name: RenameConversation :exec
+UPDATE conversations
+SET name       = $2,
+    updated_at = NOW()
+WHERE id = $1;
 
 -- name: AddMemberToConversation :one
 INSERT INTO conversation_members (conversation_id, user_id)
@@ -49,12 +86,20 @@ VALUES ($1, $2)
 RETURNING conversation_id, user_id, joined_at;
 
 -- name: GetConversationMembers :many
-SELECT cm.conversation_id, cm.user_id, cm.joined_at,
-       u.user_id, u.user_name, u.display_name, u.avatar_url, u.last_seen_at
+SELECT cm.conversation_id, cm.user_id, cm.role, cm.joined_at,
+       u.user_id, u.user_name, u.display_name, u.avatar_url, u.is_e2ee_ready, u.last_seen_at
 FROM conversation_members cm
 JOIN users u ON u.user_id = cm.user_id
 WHERE cm.conversation_id = $1;
 
+-- name: GetConversationMember :one
+-- Returns the caller's own membership row; sql.ErrNoRows means not a member.
+SELECT conversation_id, user_id, role, last_read_message_id, last_delivered_message_id, joined_at, cleared_at, hidden_at, last_sent_at
+FROM conversation_members
+WHERE conversation_id = $1
+  AND user_id = $2
+LIMIT 1;
+
 -- name: RemoveMemberFromConversation :exec
 DELETE FROM conversation_members
 WHERE conversation_id = $1
@@ -65,6 +110,12 @@ INSERT INTO conversation_members (conversation_id, user_id, role)
 VALUES ($1, $2, $3)
 RETURNING conversation_id, user_id, joined_at;
 
+-- name: UpdateMemberRole :exec
+UPDATE conversation_members
+SET role = $3
+WHERE conversation_id = $1
+  AND user_id = $2;
+
 -- name: GetDmPeer :one
 SELECT user1_id, user2_id, conversation_id
 FROM dm_peers
@@ -72,6 +123,25 @@ WHERE user1_id = $1
   AND user2_id = $2
 LIMIT 1;
 
+-- name: IsDmReadOnly :one
+-- A DM is read-only while its two members are not accepted friends or either
+-- has blocked the other. sql.ErrNoRows means the conversation is not a DM.
+SELECT (
+  NOT EXISTS (
+    SELECT 1 FROM friendships f
+    WHERE f.user1_userid = d.user1_id
+      AND f.user2_userid = d.user2_id
+      AND f.status = 'accepted'
+  )
+  OR EXISTS (
+    SELECT 1 FROM blocks b
+    WHERE (b.blocker_id = d.user1_id AND b.blocked_id = d.user2_id)
+       OR (b.blocker_id = d.user2_id AND b.blocked_id = d.user1_id)
+  )
+)::boolean AS read_only
+FROM dm_peers d
+WHERE d.conversation_id = $1;
+
 -- name: CreateDmPeer :one
 INSERT INTO dm_peers (user1_id, user2_id, conversation_id)
 VALUES ($1, $2, $3)
@@ -88,3 +158,68 @@ UPDATE conversation_members
 SET last_delivered_message_id = $3
 WHERE conversation_id = $1
   AND user_id = $2;
+
+-- name: GetConversationReceipts :many
+-- read_receipts is the member's privacy setting; when false their read
+-- position is private.
+SELECT cm.user_id, cm.last_read_message_id, cm.last_delivered_message_id,
+    COALESCE(us.read_receipts, TRUE)::bool AS read_receipts
+FROM conversation_members cm
+LEFT JOIN user_settings us ON us.user_id = cm.user_id
+WHERE cm.conversation_id = $1;
+
+-- name: ClearConversationHistory :exec
+UPDATE conversation_members
+SET cleared_at = NOW()
+WHERE conversation_id = $1 AND user_id = $2;
+
+-- name: HideConversation :exec
+-- Hides the conversation and its current history for one member.
+UPDATE conversation_members
+SET hidden_at  = NOW(),
+    cleared_at = NOW()
+WHERE conversation_id = $1 AND user_id = $2;
+
+-- name: UnhideConversation :exec
+-- Makes the conversation visible again for every member that hid it.
+UPDATE conversation_members
+SET hidden_at = NULL
+WHERE conversation_id = $1 AND hidden_at IS NOT NULL;
+
+-- name: UnhideConversationForUser :exec
+UPDATE conversation_members
+SET hidden_at = NULL
+WHERE conversation_id = $1 AND user_id = $2 AND hidden_at IS NOT NULL;
+
+-- name: DeleteConversation :exec
+DELETE FROM conversations
+WHERE id = $1;
+
+-- name: SetSlowMode :execrows
+UPDATE conversations
+SET slow_mode_seconds = @slow_mode_seconds, updated_at = NOW()
+WHERE id = @conversation_id AND is_group = TRUE;
+
+-- name: ClaimSendSlot :execrows
+-- Records a send by the member unless slow mode applies to them and their
+-- previous send is too recent. The row lock serialises concurrent sends by
+-- the same member, so zero rows affected means the member must wait.
+UPDATE conversation_members cm
+SET last_sent_at = NOW()
+FROM conversations c
+WHERE c.id = cm.conversation_id
+  AND cm.conversation_id = @conversation_id
+  AND cm.user_id = @user_id
+  AND (c.slow_mode_seconds = 0
+       OR cm.role IN ('admin', 'owner')
+       OR cm.last_sent_at IS NULL
+       OR cm.last_sent_at <= NOW() - make_interval(secs => c.slow_mode_seconds));
+
+-- name: GetSlowModeRetryAfter :one
+-- Milliseconds until the member may send again under slow mode.
+SELECT GREATEST(0, CEIL(EXTRACT(EPOCH FROM
+         cm.last_sent_at + make_interval(secs => c.slow_mode_seconds) - NOW()) * 1000))::bigint AS retry_after_ms
+FROM conversation_members cm
+JOIN conversations c ON c.id = cm.conversation_id
+WHERE cm.conversation_id = @conversation_id
+  AND cm.user_id = @user_id;
@@ -0,0 +1,70 @@
+-- name: CreateConversationExport :one
+INSERT INTO conversation_exports (conversation_id, user_id)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetConversationExport :one
+SELECT *
+FROM conversation_exports
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetConversationExportByToken :one
+SELECT *
+FROM conversation_exports
+WHERE download_token = $1
+  AND status = 'done'
+  AND expires_at > NOW();
+
+-- name: ClaimPendingConversationExport :one
+-- Marks the oldest pending job as running, or takes over a running job whose
+-- worker claimed it before stale_before and never finished. SKIP LOCKED lets
+-- several backend instances run workers without picking the same job.
+UPDATE conversation_exports
+SET status     = 'running',
+    started_at = NOW(),
+    updated_at = NOW()
+WHERE id = (
+  SELECT e.id FROM conversation_exports e
+  WHERE e.status = 'pending'
+     OR (e.status = 'running' AND e.started_at < @stale_before::timestamptz)
+  ORDER BY e.created_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteConversationExport :execrows
+-- Only the worker holding the current claim (matched on started_at) may
+-- finish the job; a job taken over after its lease ran out affects no rows.
+UPDATE conversation_exports
+SET status         = 'done',
+    download_token = @download_token,
+    file_path      = @file_path,
+    expires_at     = @expires_at,
+    updated_at     = NOW()
+WHERE id = @id
+  AND status = 'running'
+  AND started_at = @started_at;
+
+-- name: FailConversationExport :exec
+UPDATE conversation_exports
+SET status     = 'failed',
+    error      = @error,
+    updated_at = NOW()
+WHERE id = @id
+  AND status = 'running'
+  AND started_at = @started_at;
+
+-- name: DeleteExpiredConversationExports :many
+-- Removes finished jobs whose link has expired and returns their file paths
+-- so the caller can delete the archives from disk.
+DELETE FROM conversation_exports
+WHERE expires_at <= NOW()
+RETURNING file_path;
+
+-- name: DeleteConversationExports :many
+-- Removes every job of a conversation that is being deleted and returns
+-- their file paths so the caller can delete the archives after commit.
+DELETE FROM conversation_exports
+WHERE conversation_id = $1
+RETURNING file_path;
@@ -15,7 +15,7 @@ WHERE  user1_userid = $1
 RETURNING user1_userid, user2_userid, initiator_userid, status, created_at, updated_at;
 
 -- name: DeleteFriendship :exec
--- Deletes the friendship row (used when a request is rejected).
+-- Deletes the friendship row (used when a request is rejected or a friend is removed).
 DELETE FROM friendships
 WHERE user1_userid = $1
   AND user2_userid = $2;
@@ -29,10 +29,12 @@ WHERE user1_userid = $1
 LIMIT 1;
 
 -- name: GetFriends :many
--- Returns all friends (accepted or pending) for a user, including their user_id, username, display_name, avatar_url, and status.
+-- Returns all friends (accepted or pending) for a user, including their user_id, username, display_name, avatar_url, and status,
+-- plus the IDs of the user's labels each friend is in.
+-- When label_id is set, only accepted friends in that label are returned.
 SELECT
     CASE
-        WHEN f.user1_userid = $1 THEN f.user2_userid
+        WHEN f.user1_userid = @user_id THEN f.user2_userid
         ELSE f.user1_userid
     END::uuid AS friend_userid,
     u.user_name AS friend_username,
@@ -40,21 +42,170 @@ SELECT
     u.avatar_url AS friend_avatar_url,
     f.status,
     f.created_at,
-    f.updated_at
+    f.updated_at,
+    ARRAY(
+        SELECT m.label_id FROM friend_label_members m
+        JOIN friend_labels l ON l.id = m.label_id
+        WHERE l.owner_id = @user_id AND m.user_id = u.user_id
+        ORDER BY m.label_id
+    )::bigint[] AS label_ids
 FROM friendships f
 JOIN users u ON u.user_id = (
     CASE
-        WHEN f.user1_userid = $1 THEN f.user2_userid
+        WHEN f.user1_userid = @user_id THEN f.user2_userid
         ELSE f.user1_userid
     END
 )
-WHERE (f.user1_userid = $1 OR f.user2_userid = $1)
-  AND (f.status = 'accepted' OR (f.status = 'pending' AND f.initiator_userid != $1));
+WHERE (f.user1_userid = @user_id OR f.user2_userid = @user_id)
+  AND (f.status = 'accepted' OR (f.status = 'pending' AND f.initiator_userid != @user_id))
+  AND (sqlc.narg('label_id')::bigint IS NULL OR (
+    f.status = 'accepted'
+    AND EXISTS (SELECT 1 FROM friend_label_members m WHERE m.label_id = sqlc.narg('label_id')::bigint AND m.user_id = u.user_id)
+  ));
 
--- name: GetPendingRequests :many
--- Returns incoming friend requests pending for a user.
-SELECT user1_userid, user2_userid, initiator_userid, status, created_at, updated_at
-FROM friendships
-WHERE user2_userid = $1
-  AND status = 'pending'
-ORDER BY created_at DESC;
+-- name: ListFriendRequests :many
+-- Returns pending requests involving user_id with the other user's profile:
+-- outgoing (user_id sent them) or incoming (sent to user_id).
+-- Newest first, keyset-paged on (created_at, other user_id).
+SELECT u.user_id, u.user_name, u.display_name, u.avatar_url, f.created_at
+FROM friendships f
+JOIN users u ON u.user_id = (
+    CASE
+        WHEN f.user1_userid = @user_id THEN f.user2_userid
+        ELSE f.user1_userid
+    END
+)
+WHERE (f.user1_userid = @user_id OR f.user2_userid = @user_id)
+  AND f.status = 'pending'
+  AND (f.initiator_userid = @user_id) = @outgoing::boolean
+  AND (sqlc.narg('cursor_user_id')::uuid IS NULL
+       OR (f.created_at, u.user_id) < (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_user_id')::uuid))
+ORDER BY f.created_at DESC, u.user_id DESC
+LIMIT @page_limit;
+
+-- name: GetMutualFriends :many
+-- For each of @user_ids, counts the accepted friends they share with
+-- @caller_id and returns up to @sample_size of their usernames, alphabetically.
+-- Users with no mutual friends are omitted.
+WITH caller_friends AS (
+  SELECT (CASE WHEN f.user1_userid = @caller_id THEN f.user2_userid ELSE f.user1_userid END)::uuid AS friend_id
+  FROM friendships f
+  WHERE (f.user1_userid = @caller_id OR f.user2_userid = @caller_id)
+    AND f.status = 'accepted'
+),
+mutual AS (
+  SELECT (CASE WHEN f.user1_userid = cf.friend_id THEN f.user2_userid ELSE f.user1_userid END)::uuid AS user_id,
+      cf.friend_id
+  FROM caller_friends cf
+  JOIN friendships f ON (f.user1_userid = cf.friend_id OR f.user2_userid = cf.friend_id)
+    AND f.status = 'accepted'
+)
+SELECT m.user_id,
+    COUNT(*)::int AS mutual_count,
+    (array_agg(u.user_name ORDER BY u.user_name))[1:@sample_size::int]::text[] AS sample
+FROM mutual m
+JOIN users u ON u.user_id = m.friend_id
+WHERE m.user_id = ANY(@user_ids::uuid[])
+GROUP BY m.user_id;
+
+-- name: SuggestFriends :many
+-- Ranks users the caller has no friendship or pending request with by the
+-- number of accepted friends they share, then by the number of group
+-- conversations they share. Users blocked by or blocking the caller are
+-- excluded, as are users whose discoverability or friend request setting does
+-- not include the caller.
+WITH caller_friends AS (
+  SELECT (CASE WHEN f.user1_userid = @caller_id THEN f.user2_userid ELSE f.user1_userid END)::uuid AS friend_id
+  FROM friendships f
+  WHERE (f.user1_userid = @caller_id OR f.user2_userid = @caller_id)
+    AND f.status = 'accepted'
+),
+candidates AS (
+  SELECT (CASE WHEN f.user1_userid = cf.friend_id THEN f.user2_userid ELSE f.user1_userid END)::uuid AS user_id,
+      1 AS mutual, 0 AS shared_group
+  FROM caller_friends cf
+  JOIN friendships f ON (f.user1_userid = cf.friend_id OR f.user2_userid = cf.friend_id)
+    AND f.status = 'accepted'
+  UNION ALL
+  SELECT other.user_id, 0, 1
+  FROM conversation_members me
+  JOIN conversations c ON c.id = me.conversation_id AND c.is_group
+  JOIN conversation_members other ON other.conversation_id = me.conversation_id
+  WHERE me.user_id = @caller_id
+),
+scored AS (
+  SELECT user_id, SUM(mutual)::int AS mutual_count, SUM(shared_group)::int AS shared_group_count
+  FROM candidates
+  GROUP BY user_id
+)
+SELECT u.user_id, u.user_name, u.display_name, u.avatar_url, s.mutual_count, s.shared_group_count
+FROM scored s
+JOIN users u ON u.user_id = s.user_id
+LEFT JOIN user_settings us ON us.user_id = u.user_id
+WHERE u.user_id != @caller_id
+  AND audience_allows(COALESCE(us.discoverability, 'everyone'), u.user_id, @caller_id)
+  AND audience_allows(COALESCE(us.friend_request_policy, 'everyone'), u.user_id, @caller_id)
+  AND NOT EXISTS (
+    SELECT 1 FROM friendships f
+    WHERE (f.user1_userid = @caller_id AND f.user2_userid = u.user_id)
+       OR (f.user1_userid = u.user_id AND f.user2_userid = @caller_id)
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM blocks b
+    WHERE (b.blocker_id = @caller_id AND b.blocked_id = u.user_id)
+       OR (b.blocker_id = u.user_id AND b.blocked_id = @caller_id)
+  )
+ORDER BY s.mutual_count DESC, s.shared_group_count DESC, u.user_name
+LIMIT @page_limit;
+
+-- name: LockFriendRequestSender :exec
+-- Serializes SendFriendRequest per sender so concurrent requests cannot
+-- overshoot the rate limits. Held until the transaction ends.
+SELECT pg_advisory_xact_lock(hashtext('friend_request:' || s.sender_id::text))
+FROM (SELECT @sender_id::uuid AS sender_id) s;
+
+-- name: GetFriendRequestCounts :one
+-- Counts the requests sender_id sent in the last hour and the last day, with
+-- the oldest send time in each window (now when the window is empty).
+SELECT
+    COUNT(*) FILTER (WHERE sent_at > NOW() - INTERVAL '1 hour')::int AS last_hour,
+    COUNT(*)::int AS last_day,
+    COALESCE(MIN(sent_at) FILTER (WHERE sent_at > NOW() - INTERVAL '1 hour'), NOW())::timestamptz AS oldest_in_hour,
+    COALESCE(MIN(sent_at), NOW())::timestamptz AS oldest_in_day
+FROM friend_request_log
+WHERE sender_id = @sender_id
+  AND sent_at > NOW() - INTERVAL '1 day';
+
+-- name: LogFriendRequest :exec
+INSERT INTO friend_request_log (sender_id, recipient_id)
+VALUES (@sender_id, @recipient_id);
+
+-- name: DeleteFriendRequestLogBefore :execrows
+DELETE FROM friend_request_log
+WHERE sent_at < @cutoff;
+
+-- name: RecordFriendRequestDecline :exec
+INSERT INTO friend_request_declines (requester_id, addressee_id)
+VALUES (@requester_id, @addressee_id)
+ON CONFLICT (requester_id, addressee_id) DO UPDATE SET declined_at = NOW();
+
+-- name: GetFriendRequestDecline :one
+SELECT declined_at
+FROM friend_request_declines
+WHERE requester_id = @requester_id
+  AND addressee_id = @addressee_id;
+
+-- name: ExpireFriendRequests :many
+-- Deletes pending requests created before cutoff, oldest first, at most
+-- batch_size of them.
+DELETE FROM friendships
+WHERE (user1_userid, user2_userid) IN (
+    SELECT f.user1_userid, f.user2_userid
+    FROM friendships f
+    WHERE f.status = 'pending'
+      AND f.created_at < @cutoff
+    ORDER BY f.created_at
+    LIMIT @batch_size
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING user1_userid, user2_userid, initiator_userid;
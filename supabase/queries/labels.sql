@@ -0,0 +1,61 @@
+-- name: CreateFriendLabel :one
+INSERT INTO friend_labels (owner_id, name)
+VALUES ($1, $2)
+RETURNING id, owner_id, name, created_at, updated_at;
+
+-- name: GetFriendLabel :one
+-- Returns the label if @owner_id owns it; sql.ErrNoRows otherwise.
+SELECT l.id, l.name, l.created_at, l.updated_at,
+    (SELECT COUNT(*) FROM friend_label_members m WHERE m.label_id = l.id)::int AS member_count
+FROM friend_labels l
+WHERE l.id = @id AND l.owner_id = @owner_id;
+
+-- name: ListFriendLabels :many
+SELECT l.id, l.name, l.created_at, l.updated_at,
+    (SELECT COUNT(*) FROM friend_label_members m WHERE m.label_id = l.id)::int AS member_count
+FROM friend_labels l
+WHERE l.owner_id = $1
+ORDER BY lower(l.name);
+
+-- name: RenameFriendLabel :execrows
+UPDATE friend_labels
+SET name = @name, updated_at = NOW()
+WHERE id = @id AND owner_id = @owner_id;
+
+-- name: DeleteFriendLabel :execrows
+DELETE FROM friend_labels
+WHERE id = @id AND owner_id = @owner_id;
+
+-- name: AddFriendLabelMembers :exec
+INSERT INTO friend_label_members (label_id, user_id)
+SELECT @label_id, unnest(@user_ids::uuid[])
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveFriendLabelMembers :exec
+DELETE FROM friend_label_members
+WHERE label_id = @label_id AND user_id = ANY(@user_ids::uuid[]);
+
+-- name: GetFriendsByUsernames :many
+-- Returns the accepted friends of @owner_id among @usernames.
+SELECT u.user_id, u.user_name
+FROM users u
+JOIN friendships f ON f.status = 'accepted'
+  AND ((f.user1_userid = @owner_id AND f.user2_userid = u.user_id)
+    OR (f.user1_userid = u.user_id AND f.user2_userid = @owner_id))
+WHERE u.user_name = ANY(@usernames::text[]);
+
+-- name: ListFriendLabelMemberUsernames :many
+-- Returns the usernames of the label's members, alphabetically.
+SELECT u.user_name
+FROM friend_label_members m
+JOIN users u ON u.user_id = m.user_id
+WHERE m.label_id = $1
+ORDER BY u.user_name;
+
+-- name: DeleteFriendLabelMembershipsBetween :exec
+-- Takes each user out of the other's labels, e.g. after they stop being friends.
+DELETE FROM friend_label_members m
+USING friend_labels l
+WHERE m.label_id = l.id
+  AND ((l.owner_id = @user_a AND m.user_id = @user_b)
+    OR (l.owner_id = @user_b AND m.user_id = @user_a));
@@ -0,0 +1,64 @@
+-- name: CreateLoginSession :exec
+INSERT INTO login_sessions (login_id, user_id, device_name, user_agent, ip_address)
+VALUES (@login_id, @user_id, @device_name, @user_agent, @ip_address);
+
+-- name: RevokeLoginSession :exec
+-- Marks one of the user's logins revoked, recording logins that predate the
+-- table too. Revoking again keeps the first revocation time.
+INSERT INTO login_sessions (login_id, user_id, revoked_at)
+VALUES (@login_id, @user_id, NOW())
+ON CONFLICT (login_id) DO UPDATE
+SET revoked_at = COALESCE(login_sessions.revoked_at, NOW())
+WHERE login_sessions.user_id = EXCLUDED.user_id;
+
+-- name: TouchLoginSession :one
+-- Records activity on a login and reports whether it was revoked. Logins
+-- without a row return no rows; see GetLegacyLoginCutoff.
+UPDATE login_sessions
+SET last_active_at = CASE WHEN revoked_at IS NULL THEN NOW() ELSE last_active_at END
+WHERE login_id = @login_id
+RETURNING (revoked_at IS NOT NULL)::boolean AS revoked;
+
+-- name: GetLegacyLoginCutoff :one
+-- Until when a login without a login_sessions row, i.e. one from before they
+-- were recorded, counts as active.
+SELECT accept_until
+FROM legacy_login_cutoff;
+
+-- name: ListActiveLoginSessions :many
+-- A user's logins that are not revoked and can still be used: one with a
+-- recent call (its access token may be valid) or an unused, unexpired refresh
+-- token.
+SELECT s.login_id, s.device_name, s.user_agent, s.ip_address, s.created_at, s.last_active_at
+FROM login_sessions s
+WHERE s.user_id = @user_id
+  AND s.revoked_at IS NULL
+  AND (
+      s.last_active_at > @active_since
+      OR EXISTS (
+          SELECT 1
+          FROM refresh_tokens rt
+          WHERE rt.login_id = s.login_id
+            AND rt.used_at IS NULL
+            AND rt.revoked_at IS NULL
+            AND rt.expires_at > NOW()
+      )
+  )
+ORDER BY s.last_active_at DESC, s.login_id;
+
+-- name: GetUnrevokedLoginSessionForUpdate :one
+SELECT login_id
+FROM login_sessions
+WHERE login_id = @login_id
+  AND user_id = @user_id
+  AND revoked_at IS NULL
+FOR UPDATE;
+
+-- name: ListUnrevokedLoginIDs :many
+-- Every login of the user that is not revoked, except except_login_id.
+SELECT login_id
+FROM login_sessions
+WHERE user_id = @user_id
+  AND login_id <> @except_login_id
+  AND revoked_at IS NULL
+FOR UPDATE;
@@ -4,15 +4,30 @@ VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING id, conversation_id, sender_id, sender_login_id, reply_to_message_id, content, message_type, media_url, is_edited, deleted_at, created_at, updated_at;
 
 -- name: GetConversationMessages :many
--- Cursor-based pagination: pass the last seen message id as cursor (empty for first page).
--- Returns non-deleted messages ordered oldest-first.
-SELECT id, conversation_id, sender_id, reply_to_message_id, content, message_type, is_edited, created_at
-FROM messages
-WHERE conversation_id = $1
-  AND deleted_at IS NULL
-  AND ($2::uuid IS NULL OR id > $2)
-ORDER BY id ASC
-LIMIT $3;
+-- Cursor-based pagination: pass the last seen message id as cursor (NULL for first page).
+-- Returns non-deleted messages, including system events, ordered oldest-first.
+-- Messages the viewer cleared with ClearHistory are skipped.
+SELECT m.id, m.conversation_id, m.sender_id, m.reply_to_message_id, m.content, m.message_type, m.is_edited, m.created_at
+FROM messages m
+WHERE m.conversation_id = @conversation_id
+  AND m.deleted_at IS NULL
+  AND m.created_at > COALESCE((
+    SELECT cm.cleared_at FROM conversation_members cm
+    WHERE cm.conversation_id = @conversation_id AND cm.user_id = @viewer_id
+  ), '-infinity'::timestamptz)
+  AND (sqlc.narg('cursor')::uuid IS NULL OR (m.created_at, m.id) > (
+    SELECT c.created_at, c.id FROM messages c WHERE c.id = sqlc.narg('cursor')::uuid
+  ))
+ORDER BY m.created_at ASC, m.id ASC
+LIMIT @page_limit;
+
+-- name: IsMessageInConversation :one
+-- Whether message id exists in the conversation; GetMessages checks its cursor
+-- with this so a stale or foreign cursor is not mistaken for the end of history.
+SELECT EXISTS (
+  SELECT 1 FROM messages
+  WHERE id = @id AND conversation_id = @conversation_id
+);
 
 -- name: EditMessage :one
 UPDATE messages
@@ -30,3 +45,74 @@ SET deleted_at = NOW(),
 WHERE id = $1
   AND deleted_at IS NULL
 RETURNING id, conversation_id, sender_id, content, message_type, media_url, is_edited, deleted_at, created_at, updated_at;
+
+-- name: GetMessagesForExport :many
+-- Same keyset paging as GetConversationMessages but returns full rows,
+-- including soft-deleted messages, for conversation archives.
+SELECT m.*
+FROM messages m
+WHERE m.conversation_id = @conversation_id
+  AND (sqlc.narg('cursor')::uuid IS NULL OR (m.created_at, m.id) > (
+    SELECT c.created_at, c.id FROM messages c WHERE c.id = sqlc.narg('cursor')::uuid
+  ))
+ORDER BY m.created_at ASC, m.id ASC
+LIMIT @page_limit;
+
+-- name: CountConversationMessages :one
+SELECT COUNT(*) FROM messages
+WHERE conversation_id = $1;
+
+-- name: GetConversationParticipantIDs :many
+-- Current members plus anyone who ever sent a message in the conversation.
+SELECT cm.user_id FROM conversation_members cm WHERE cm.conversation_id = @conversation_id::bigint
+UNION
+SELECT m.sender_id FROM messages m WHERE m.conversation_id = @conversation_id::bigint;
+
+-- name: GetConversationFormerSenders :many
+-- Users who sent a message in the conversation but are no longer members.
+SELECT u.user_id, u.user_name, u.display_name
+FROM users u
+WHERE u.user_id IN (SELECT m.sender_id FROM messages m WHERE m.conversation_id = @conversation_id::bigint)
+  AND NOT EXISTS (
+    SELECT 1 FROM conversation_members cm
+    WHERE cm.conversation_id = @conversation_id::bigint AND cm.user_id = u.user_id
+  )
+ORDER BY u.user_name;
+
+-- name: InsertMessageSearchTokens :exec
+INSERT INTO message_search_tokens (message_id, conversation_id, token)
+SELECT @message_id::uuid, @conversation_id::bigint, unnest(@tokens::text[])
+ON CONFLICT DO NOTHING;
+
+-- name: SearchMessagesByTokens :many
+-- Token sets are passed as parallel arrays of (conversation_id, token) pairs.
+-- A message matches when it carries every token of its conversation's set.
+-- Only conversations the caller is a member of are searched, and messages
+-- the caller cleared are skipped. Newest first, keyset-paged on (created_at, id).
+WITH wanted AS (
+  -- set-returning functions in the select list are zipped pairwise
+  SELECT unnest(@conversation_ids::bigint[]) AS conversation_id,
+         unnest(@tokens::text[]) AS token
+), required AS (
+  SELECT wanted.conversation_id, COUNT(DISTINCT wanted.token) AS n
+  FROM wanted
+  GROUP BY wanted.conversation_id
+), hits AS (
+  SELECT t.message_id
+  FROM message_search_tokens t
+  JOIN wanted w ON w.conversation_id = t.conversation_id AND w.token = t.token
+  JOIN required r ON r.conversation_id = t.conversation_id
+  GROUP BY t.message_id, r.n
+  HAVING COUNT(DISTINCT t.token) = r.n
+)
+SELECT m.id, m.conversation_id, m.sender_id, m.reply_to_message_id, m.content, m.message_type, m.is_edited, m.created_at
+FROM hits h
+JOIN messages m ON m.id = h.message_id
+JOIN conversation_members cm ON cm.conversation_id = m.conversation_id AND cm.user_id = @caller_id
+WHERE m.deleted_at IS NULL
+  AND m.created_at > COALESCE(cm.cleared_at, '-infinity'::timestamptz)
+  AND (sqlc.narg('cursor')::uuid IS NULL OR (m.created_at, m.id) < (
+    SELECT c.created_at, c.id FROM messages c WHERE c.id = sqlc.narg('cursor')::uuid
+  ))
+ORDER BY m.created_at DESC, m.id DESC
+LIMIT @page_limit;
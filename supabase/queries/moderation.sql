@@ -0,0 +1,90 @@
+-- name: IsSiteAdmin :one
+SELECT EXISTS (SELECT 1 FROM site_admins WHERE user_id = $1)::boolean AS is_admin;
+
+-- name: GetReportableMessage :one
+-- Returns the sender of message_id if viewer_id is a member of its
+-- conversation and the message is not deleted.
+SELECT m.sender_id
+FROM messages m
+JOIN conversation_members cm ON cm.conversation_id = m.conversation_id AND cm.user_id = @viewer_id
+WHERE m.id = @message_id
+  AND m.deleted_at IS NULL;
+
+-- name: CreateReport :one
+INSERT INTO reports (reporter_id, target_user_id, message_id, reason, details, message_plaintext)
+VALUES (@reporter_id, @target_user_id, sqlc.narg('message_id'), @reason, @details, sqlc.narg('message_plaintext'))
+RETURNING id, status, created_at;
+
+-- name: ListReports :many
+-- The moderation queue: reports in the given status (any when NULL), oldest
+-- first, keyset-paged on id.
+SELECT r.id, r.reason, r.details, r.message_id, r.message_plaintext, r.status,
+    r.resolution_note, r.created_at, r.updated_at, r.resolved_at,
+    reporter.user_name AS reporter_username,
+    target.user_name AS target_username,
+    assignee.user_name AS assignee_username,
+    (SELECT COUNT(*) FROM reports o WHERE o.target_user_id = r.target_user_id)::int AS target_report_count
+FROM reports r
+JOIN users reporter ON reporter.user_id = r.reporter_id
+JOIN users target ON target.user_id = r.target_user_id
+LEFT JOIN users assignee ON assignee.user_id = r.assigned_to
+WHERE (sqlc.narg('status')::report_status IS NULL OR r.status = sqlc.narg('status')::report_status)
+  AND r.id > @after_id
+ORDER BY r.id
+LIMIT @page_limit;
+
+-- name: GetReportForUpdate :one
+SELECT id, reporter_id, target_user_id, status
+FROM reports
+WHERE id = $1
+FOR UPDATE;
+
+-- name: TriageReport :exec
+UPDATE reports
+SET status      = 'triaged',
+    assigned_to = @assigned_to,
+    updated_at  = NOW()
+WHERE id = @id;
+
+-- name: CloseReport :exec
+UPDATE reports
+SET status          = @status,
+    resolution_note = @resolution_note,
+    assigned_to     = COALESCE(assigned_to, @moderator_id),
+    updated_at      = NOW(),
+    resolved_at     = NOW()
+WHERE id = @id;
+
+-- name: CreateModerationAction :one
+INSERT INTO moderation_actions (user_id, moderator_id, report_id, action, note, expires_at)
+VALUES (@user_id, @moderator_id, sqlc.narg('report_id'), @action, @note, sqlc.narg('expires_at'))
+RETURNING id, created_at;
+
+-- name: SetAccountRestriction :exec
+-- Replaces the user's restriction.
+INSERT INTO account_restrictions (user_id, action_id, kind, expires_at)
+VALUES (@user_id, @action_id, @kind, sqlc.narg('expires_at'))
+ON CONFLICT (user_id) DO UPDATE
+SET action_id  = EXCLUDED.action_id,
+    kind       = EXCLUDED.kind,
+    expires_at = EXCLUDED.expires_at,
+    created_at = NOW();
+
+-- name: DeleteAccountRestriction :execrows
+DELETE FROM account_restrictions
+WHERE user_id = $1;
+
+-- name: GetAccountRestriction :one
+-- Returns the suspension or ban in force for user_id.
+SELECT kind, expires_at
+FROM account_restrictions
+WHERE user_id = $1
+  AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: ListModerationActions :many
+SELECT a.id, a.action, a.note, a.expires_at, a.report_id, a.created_at,
+    moderator.user_name AS moderator_username
+FROM moderation_actions a
+LEFT JOIN users moderator ON moderator.user_id = a.moderator_id
+WHERE a.user_id = $1
+ORDER BY a.created_at DESC;
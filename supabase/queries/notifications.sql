@@ -1,11 +1,11 @@
 -- name: CreateNotification :one
-INSERT INTO notifications (user_id, sender_id, type, message, reference_id)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, user_id, sender_id, type, message, reference_id, is_read, created_at;
+INSERT INTO notifications (user_id, sender_id, type, message, reference_conversation_id, reference_user_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, sender_id, type, message, reference_conversation_id, is_read, created_at, reference_user_id;
 
 -- name: GetNotificationsForUser :many
 -- Returns all notifications for a user, newest first.
-SELECT id, user_id, sender_id, type, message, reference_id, is_read, created_at
+SELECT id, user_id, sender_id, type, message, reference_conversation_id, is_read, created_at, reference_user_id
 FROM notifications
 WHERE user_id = $1
 ORDER BY created_at DESC;
@@ -20,10 +20,18 @@ WHERE user_id = $1
 UPDATE notifications
 SET is_read = TRUE
 WHERE id = $1
-RETURNING id, user_id, sender_id, type, message, reference_id, is_read, created_at;
+RETURNING id, user_id, sender_id, type, message, reference_conversation_id, is_read, created_at, reference_user_id;
 
 -- name: MarkAllNotificationsAsRead :exec
 UPDATE notifications
 SET is_read = TRUE
 WHERE user_id = $1
   AND is_read = FALSE;
+
+-- name: DeleteNotificationsFrom :many
+-- Retracts the notifications of one type that sender_id caused for user_id.
+DELETE FROM notifications
+WHERE user_id = @user_id
+  AND sender_id = @sender_id
+  AND type = @type
+RETURNING id;
@@ -0,0 +1,32 @@
+-- name: CreateOAuthState :exec
+INSERT INTO oauth_states (state_hash, provider, code_verifier, nonce, expires_at)
+VALUES (@state_hash, @provider, @code_verifier, @nonce, @expires_at);
+
+-- name: ConsumeOAuthState :one
+-- Deleting the state as it is read makes each one good for a single callback.
+DELETE FROM oauth_states
+WHERE state_hash = $1
+RETURNING provider, code_verifier, nonce, expires_at;
+
+-- name: DeleteExpiredOAuthStates :exec
+DELETE FROM oauth_states
+WHERE expires_at < NOW();
+
+-- name: GetUserByIdentity :one
+SELECT u.user_id, u.user_name, u.signup_type
+FROM user_identities i
+JOIN users u ON u.user_id = i.user_id
+WHERE i.provider = @provider
+  AND i.subject = @subject;
+
+-- name: UserHasIdentity :one
+-- Whether the user has linked an account at provider.
+SELECT EXISTS (
+    SELECT 1 FROM user_identities
+    WHERE user_id = @user_id
+      AND provider = @provider
+);
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (provider, subject, user_id)
+VALUES (@provider, @subject, @user_id);
@@ -0,0 +1,46 @@
+-- name: CreatePasskey :one
+INSERT INTO webauthn_credentials (user_id, credential_id, public_key, sign_count, transports, name, backup_eligible)
+VALUES (@user_id, @credential_id, @public_key, @sign_count, @transports, @name, @backup_eligible)
+RETURNING id, created_at;
+
+-- name: ListPasskeys :many
+SELECT id, credential_id, transports, name, backup_eligible, created_at, last_used_at
+FROM webauthn_credentials
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: GetPasskeyByCredentialIDForUpdate :one
+SELECT id, user_id, public_key, sign_count
+FROM webauthn_credentials
+WHERE credential_id = $1
+FOR UPDATE;
+
+-- name: RecordPasskeyUse :exec
+UPDATE webauthn_credentials
+SET sign_count = @sign_count,
+    last_used_at = NOW()
+WHERE id = @id;
+
+-- name: DeletePasskey :execrows
+DELETE FROM webauthn_credentials
+WHERE id = @id
+  AND user_id = @user_id;
+
+-- name: CreateWebAuthnChallenge :exec
+INSERT INTO webauthn_challenges (challenge_hash, user_id, purpose, expires_at)
+VALUES (@challenge_hash, sqlc.narg(user_id), @purpose, @expires_at);
+
+-- name: GetWebAuthnChallengeForUpdate :one
+SELECT id, user_id, purpose, expires_at, used_at
+FROM webauthn_challenges
+WHERE challenge_hash = $1
+FOR UPDATE;
+
+-- name: MarkWebAuthnChallengeUsed :exec
+UPDATE webauthn_challenges
+SET used_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteExpiredWebAuthnChallenges :exec
+DELETE FROM webauthn_challenges
+WHERE expires_at < NOW();
@@ -0,0 +1,85 @@
+-- name: SetRecoveryEmail :exec
+INSERT INTO recovery_emails (user_id, email)
+VALUES (@user_id, @email)
+ON CONFLICT (user_id) DO UPDATE
+SET email = EXCLUDED.email, updated_at = NOW();
+
+-- name: GetRecoveryEmailOwner :one
+SELECT user_id
+FROM recovery_emails
+WHERE lower(email) = lower(@email);
+
+-- name: GetPasswordUserByRecoveryEmail :one
+-- The account a reset link for email goes to. Accounts without a password
+-- (OAuth sign-ups) cannot reset one.
+SELECT u.user_id, u.user_name, e.email
+FROM recovery_emails e
+JOIN users u ON u.user_id = e.user_id
+WHERE lower(e.email) = lower(@email)
+  AND u.hashed_passwd IS NOT NULL;
+
+-- name: UpdatePassword :exec
+UPDATE users
+SET hashed_passwd = @hashed_passwd, updated_at = NOW()
+WHERE user_id = @user_id;
+
+-- name: RetirePasswordResetTokens :exec
+-- Marks every outstanding reset token of the user used.
+UPDATE password_reset_tokens
+SET used_at = NOW()
+WHERE user_id = $1
+  AND used_at IS NULL;
+
+-- name: DeleteExpiredPasswordResetTokens :exec
+DELETE FROM password_reset_tokens
+WHERE user_id = $1
+  AND expires_at < NOW();
+
+-- name: CreatePasswordResetToken :exec
+INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+VALUES (@user_id, @token_hash, @expires_at);
+
+-- name: GetPasswordResetTokenForUpdate :one
+SELECT id, user_id, expires_at, used_at
+FROM password_reset_tokens
+WHERE token_hash = @token_hash
+FOR UPDATE;
+
+-- name: LockPasswordResetEmail :exec
+-- Serializes RequestPasswordReset per address so concurrent requests cannot
+-- overshoot the rate limit. Held until the transaction ends.
+SELECT pg_advisory_xact_lock(hashtext('password_reset:' || lower(s.email)))
+FROM (SELECT @email::text AS email) s;
+
+-- name: GetPasswordResetRequestCount :one
+-- Counts the reset requests for email in the last hour, with the oldest
+-- request time in that window (now when the window is empty).
+SELECT
+    COUNT(*)::int AS last_hour,
+    COALESCE(MIN(requested_at), NOW())::timestamptz AS oldest_in_hour
+FROM password_reset_requests
+WHERE lower(email) = lower(@email)
+  AND requested_at > NOW() - INTERVAL '1 hour';
+
+-- name: CreatePasswordResetRequest :exec
+INSERT INTO password_reset_requests (email)
+VALUES (@email);
+
+-- name: ClaimPasswordResetRequest :one
+-- Marks the oldest unprocessed request processed. SKIP LOCKED lets several
+-- backend instances run workers without picking the same request.
+UPDATE password_reset_requests
+SET processed_at = NOW()
+WHERE id = (
+  SELECT r.id FROM password_reset_requests r
+  WHERE r.processed_at IS NULL
+  ORDER BY r.id
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, email;
+
+-- name: DeletePasswordResetRequestsBefore :execrows
+DELETE FROM password_reset_requests
+WHERE requested_at < @cutoff
+  AND processed_at IS NOT NULL;
@@ -0,0 +1,26 @@
+-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (user_id, login_id, token_hash, expires_at)
+VALUES (@user_id, @login_id, @token_hash, @expires_at);
+
+-- name: GetRefreshTokenForUpdate :one
+SELECT id, user_id, login_id, expires_at, used_at, revoked_at
+FROM refresh_tokens
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: MarkRefreshTokenUsed :exec
+UPDATE refresh_tokens
+SET used_at = NOW()
+WHERE id = $1;
+
+-- name: RevokeLoginRefreshTokens :exec
+-- Revokes every refresh token of one login.
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE login_id = $1
+  AND revoked_at IS NULL;
+
+-- name: DeleteExpiredRefreshTokens :exec
+DELETE FROM refresh_tokens
+WHERE user_id = $1
+  AND expires_at < NOW();
@@ -0,0 +1,33 @@
+-- name: GetPrivacySettings :one
+-- Returns the user's privacy settings, falling back to the defaults for users
+-- who never changed them.
+SELECT u.user_id,
+    COALESCE(s.discoverability, 'everyone')::privacy_audience AS discoverability,
+    COALESCE(s.friend_request_policy, 'everyone')::privacy_audience AS friend_request_policy,
+    COALESCE(s.read_receipts, TRUE)::bool AS read_receipts,
+    COALESCE(s.last_seen_visibility, 'everyone')::privacy_audience AS last_seen_visibility
+FROM users u
+LEFT JOIN user_settings s ON s.user_id = u.user_id
+WHERE u.user_id = $1;
+
+-- name: UpsertPrivacySettings :one
+-- Changes the settings that are not NULL and keeps the rest.
+INSERT INTO user_settings (user_id, discoverability, friend_request_policy, read_receipts, last_seen_visibility)
+VALUES (
+    @user_id,
+    COALESCE(sqlc.narg('discoverability')::privacy_audience, 'everyone'),
+    COALESCE(sqlc.narg('friend_request_policy')::privacy_audience, 'everyone'),
+    COALESCE(sqlc.narg('read_receipts')::bool, TRUE),
+    COALESCE(sqlc.narg('last_seen_visibility')::privacy_audience, 'everyone')
+)
+ON CONFLICT (user_id) DO UPDATE
+SET discoverability       = COALESCE(sqlc.narg('discoverability')::privacy_audience, user_settings.discoverability),
+    friend_request_policy = COALESCE(sqlc.narg('friend_request_policy')::privacy_audience, user_settings.friend_request_policy),
+    read_receipts         = COALESCE(sqlc.narg('read_receipts')::bool, user_settings.read_receipts),
+    last_seen_visibility  = COALESCE(sqlc.narg('last_seen_visibility')::privacy_audience, user_settings.last_seen_visibility),
+    updated_at            = NOW()
+RETURNING user_id, discoverability, friend_request_policy, read_receipts, last_seen_visibility, updated_at;
+
+-- name: AudienceAllows :one
+-- Reports whether @viewer_id is within @audience as seen from @owner_id.
+SELECT audience_allows(@audience::privacy_audience, @owner_id::uuid, @viewer_id::uuid)::bool AS allowed;
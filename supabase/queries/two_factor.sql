@@ -0,0 +1,88 @@
+-- name: StartTOTPEnrollment :execrows
+-- Stores a new, unconfirmed secret. Affects no rows if two-factor sign-in is
+-- already on.
+INSERT INTO totp_factors (user_id, secret)
+VALUES (@user_id, @secret)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret,
+    created_at = NOW(),
+    last_used_step = 0,
+    failed_attempts = 0,
+    last_failed_at = NULL
+WHERE totp_factors.confirmed_at IS NULL;
+
+-- name: GetTOTPFactorForUpdate :one
+SELECT secret, confirmed_at, last_used_step, failed_attempts, last_failed_at
+FROM totp_factors
+WHERE user_id = $1
+FOR UPDATE;
+
+-- name: IsTOTPEnabled :one
+SELECT EXISTS (
+    SELECT 1 FROM totp_factors
+    WHERE user_id = $1
+      AND confirmed_at IS NOT NULL
+)::boolean;
+
+-- name: ConfirmTOTPFactor :exec
+UPDATE totp_factors
+SET confirmed_at = NOW()
+WHERE user_id = $1;
+
+-- name: RecordTOTPSuccess :exec
+-- Clears the failure count. step is the time step of an accepted TOTP code;
+-- codes at or before it are refused from now on.
+UPDATE totp_factors
+SET last_used_step = GREATEST(last_used_step, @step::bigint),
+    failed_attempts = 0,
+    last_failed_at = NULL
+WHERE user_id = @user_id;
+
+-- name: RecordTOTPFailure :exec
+-- Counts a wrong code. Failures before reset_before no longer count.
+UPDATE totp_factors
+SET failed_attempts = CASE
+        WHEN last_failed_at >= @reset_before::timestamptz THEN failed_attempts + 1
+        ELSE 1
+    END,
+    last_failed_at = NOW()
+WHERE user_id = @user_id;
+
+-- name: DeleteTOTPFactor :exec
+DELETE FROM totp_factors
+WHERE user_id = $1;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM totp_recovery_codes
+WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO totp_recovery_codes (user_id, code_hash)
+VALUES (@user_id, @code_hash);
+
+-- name: UseRecoveryCode :execrows
+UPDATE totp_recovery_codes
+SET used_at = NOW()
+WHERE user_id = @user_id
+  AND code_hash = @code_hash
+  AND used_at IS NULL;
+
+-- name: CreateLoginChallenge :exec
+INSERT INTO login_challenges (user_id, token_hash, device_name, expires_at)
+VALUES (@user_id, @token_hash, @device_name, @expires_at);
+
+-- name: GetLoginChallengeForUpdate :one
+SELECT id, user_id, device_name, expires_at, used_at
+FROM login_challenges
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: MarkLoginChallengeUsed :exec
+UPDATE login_challenges
+SET used_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteExpiredLoginChallenges :exec
+DELETE FROM login_challenges
+WHERE user_id = $1
+  AND expires_at < NOW();
@@ -1,11 +1,19 @@
 -- name: GetUserByID :one
-SELECT user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at
+SELECT user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at, bio, status_text, status_expires_at
 FROM users
 WHERE user_id = $1
 LIMIT 1;
 
+-- name: GetUserByIDForUpdate :one
+-- Locks the user row so changes that check earlier ones, like the username
+-- change cooldown, run one at a time per user.
+SELECT user_id, user_name
+FROM users
+WHERE user_id = $1
+FOR UPDATE;
+
 -- name: GetUserByUsername :one
-SELECT user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at
+SELECT user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at, bio, status_text, status_expires_at
 FROM users
 WHERE user_name = $1
 LIMIT 1;
@@ -13,20 +21,20 @@ LIMIT 1;
 -- name: CreateUser :one
 INSERT INTO users (user_name, hashed_passwd, signup_type)
 VALUES ($1, $2, $3)
-RETURNING user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at;
+RETURNING user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at, bio, status_text, status_expires_at;
 
 -- name: UpdateUserProfile :one
 UPDATE users
 SET display_name = $2,
     avatar_url   = $3,
     updated_at   = NOW()
-WHERE user_name = $1
-RETURNING user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at;
+WHERE user_id = $1
+RETURNING user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at, bio, status_text, status_expires_at;
 
 -- name: UpdateLastSeen :exec
 UPDATE users
 SET last_seen_at = NOW()
-WHERE user_name = $1;
+WHERE user_id = $1;
 
 -- name: SetE2EEKeys :exec
 UPDATE users
@@ -41,26 +49,112 @@ FROM users
 WHERE user_id = $1;
 
 -- name: SearchUsers :many
--- Searches for users by username or display name, excluding the caller.
+-- Searches for users by username or display name, excluding the caller,
+-- anyone who has blocked the caller, and anyone whose discoverability setting
+-- does not include the caller.
+-- Matches substrings and trigram-similar names; exact username matches come
+-- first, then the rest by similarity. Keyset-paged on (exact_match, score, user_id):
+-- pass the last row's values as the cursor (all NULL for the first page).
 -- Returns the friendship status and initiator user ID if a relationship exists, or empty string otherwise.
-SELECT u.user_id, u.user_name, u.display_name, u.avatar_url,
+WITH ranked AS (
+  SELECT u.user_id, u.user_name, u.display_name, u.avatar_url,
+      (lower(u.user_name) = lower(@query::text))::int AS exact_match,
+      GREATEST(
+        similarity(u.user_name, @query::text),
+        similarity(COALESCE(u.display_name, ''), @query::text)
+      )::float8 AS score
+  FROM users u
+  LEFT JOIN user_settings us ON us.user_id = u.user_id
+  WHERE u.user_id != @caller_id
+    AND NOT EXISTS (SELECT 1 FROM blocks b WHERE b.blocker_id = u.user_id AND b.blocked_id = @caller_id)
+    AND audience_allows(COALESCE(us.discoverability, 'everyone'), u.user_id, @caller_id)
+    AND (u.user_name ILIKE '%' || @query::text || '%'
+      OR u.display_name ILIKE '%' || @query::text || '%'
+      OR u.user_name % @query::text
+      OR u.display_name % @query::text)
+)
+SELECT r.user_id, r.user_name, r.display_name, r.avatar_url, r.exact_match, r.score,
     COALESCE(
       (SELECT f.status::text FROM friendships f
-       WHERE (f.user1_userid = $2 AND f.user2_userid = u.user_id)
-          OR (f.user1_userid = u.user_id AND f.user2_userid = $2)
+       WHERE (f.user1_userid = @caller_id AND f.user2_userid = r.user_id)
+          OR (f.user1_userid = r.user_id AND f.user2_userid = @caller_id)
        LIMIT 1),
       ''
     ) AS friendship_status,
     COALESCE(
       (SELECT f.initiator_userid::text FROM friendships f
-       WHERE (f.user1_userid = $2 AND f.user2_userid = u.user_id)
-          OR (f.user1_userid = u.user_id AND f.user2_userid = $2)
+       WHERE (f.user1_userid = @caller_id AND f.user2_userid = r.user_id)
+          OR (f.user1_userid = r.user_id AND f.user2_userid = @caller_id)
        LIMIT 1),
       ''
     ) AS friendship_initiator_userid
-FROM users u
-WHERE (u.user_name ILIKE '%' || $1 || '%'
-    OR u.display_name ILIKE '%' || $1 || '%')
-  AND u.user_id != $2
-ORDER BY u.user_name
-LIMIT 50;
+FROM ranked r
+WHERE sqlc.narg('cursor_user_id')::uuid IS NULL
+   OR (r.exact_match, r.score, r.user_id) < (sqlc.narg('cursor_exact')::int, sqlc.narg('cursor_score')::float8, sqlc.narg('cursor_user_id')::uuid)
+ORDER BY r.exact_match DESC, r.score DESC, r.user_id DESC
+LIMIT @page_limit;
+
+-- name: UpdateProfile :one
+-- Updates the profile fields whose set_* flag is true; the rest are kept.
+-- An empty string clears a field. Setting the status always replaces its expiry.
+UPDATE users
+SET display_name      = CASE WHEN @set_display_name::bool THEN NULLIF(@display_name::text, '') ELSE display_name END,
+    avatar_url        = CASE WHEN @set_avatar_url::bool THEN NULLIF(@avatar_url::text, '') ELSE avatar_url END,
+    bio               = CASE WHEN @set_bio::bool THEN NULLIF(@bio::text, '') ELSE bio END,
+    status_text       = CASE WHEN @set_status::bool THEN NULLIF(@status_text::text, '') ELSE status_text END,
+    status_expires_at = CASE WHEN @set_status::bool THEN sqlc.narg('status_expires_at')::timestamptz ELSE status_expires_at END,
+    updated_at        = NOW()
+WHERE user_id = @user_id
+RETURNING user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at, bio, status_text, status_expires_at;
+
+-- name: ListProfileAudience :many
+-- Returns everyone who should see user_id's profile changes live: accepted
+-- friends and members of any shared conversation, minus users blocked by or
+-- blocking them.
+SELECT DISTINCT peer_id::uuid AS user_id
+FROM (
+  SELECT CASE WHEN f.user1_userid = @user_id THEN f.user2_userid ELSE f.user1_userid END AS peer_id
+  FROM friendships f
+  WHERE (f.user1_userid = @user_id OR f.user2_userid = @user_id)
+    AND f.status = 'accepted'
+  UNION
+  SELECT other.user_id
+  FROM conversation_members me
+  JOIN conversation_members other ON other.conversation_id = me.conversation_id
+  WHERE me.user_id = @user_id
+    AND other.user_id != @user_id
+) peers
+WHERE NOT EXISTS (
+  SELECT 1 FROM blocks b
+  WHERE (b.blocker_id = @user_id AND b.blocked_id = peers.peer_id)
+     OR (b.blocker_id = peers.peer_id AND b.blocked_id = @user_id)
+);
+
+-- name: ChangeUsername :one
+UPDATE users
+SET user_name  = @new_user_name,
+    updated_at = NOW()
+WHERE user_id = @user_id
+RETURNING user_id, user_name, hashed_passwd, signup_type, display_name, avatar_url, encrypted_private_key, is_e2ee_ready, last_seen_at, created_at, updated_at, bio, status_text, status_expires_at;
+
+-- name: InsertUsernameHistory :exec
+INSERT INTO username_history (user_id, user_name)
+VALUES ($1, $2);
+
+-- name: GetLastUsernameChange :one
+-- Returns when user_id last changed their username.
+SELECT changed_at
+FROM username_history
+WHERE user_id = $1
+ORDER BY changed_at DESC
+LIMIT 1;
+
+-- name: GetUsernameReservation :one
+-- Returns the user who released user_name most recently after released_after,
+-- i.e. who still holds a reservation on it.
+SELECT user_id
+FROM username_history
+WHERE user_name = @user_name
+  AND changed_at > @released_after
+ORDER BY changed_at DESC
+LIMIT 1;
@@ -12,6 +12,8 @@ interface SearchResultUser {
   avatar_url: string
   friendship_status: string
   friendship_initiator_userid: string
+  mutual_friend_count?: number
+  mutual_friends?: string[]
 }
 
 interface Props {
@@ -62,8 +64,8 @@ function SearchResults({ open, onStartChat, onAccepted, onDeclined }: { open: bo
         headers: token ? { Authorization: `Bearer ${token}` } : {},
       })
       const json = await res.json()
-      if (json.success && Array.isArray(json.data)) {
-        setResults(json.data)
+      if (json.success) {
+        setResults(json.data?.users ?? [])
       } else {
         setResults([])
       }
@@ -187,7 +189,10 @@ function SearchResults({ open, onStartChat, onAccepted, onDeclined }: { open: bo
               </div>
               <div className="item-body">
                 <div className="item-name">{u.display_name || u.user_name}</div>
-                <div className="item-preview">@{u.user_name}</div>
+                <div className="item-preview">
+                  @{u.user_name}
+                  {u.mutual_friend_count ? ` · ${u.mutual_friend_count} mutual friend${u.mutual_friend_count === 1 ? '' : 's'}` : ''}
+                </div>
                 {requestErrors[u.user_id] && (
                   <div className="error-text">{requestErrors[u.user_id]}</div>
                 )}
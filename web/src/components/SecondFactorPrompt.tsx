@@ -0,0 +1,79 @@
+import { useState } from 'react'
+import { loadConfig } from '../config'
+
+interface SecondFactorPromptProps {
+  challengeToken: string
+  onSignedIn: (token: string) => Promise<void>
+  onCancel: () => void
+}
+
+// Asks for the second factor of a login that /login or /token/exchange
+// answered with second_factor_required, and finishes it at /login/verify.
+export default function SecondFactorPrompt({ challengeToken, onSignedIn, onCancel }: SecondFactorPromptProps) {
+  const [code, setCode] = useState('')
+  const [error, setError] = useState('')
+  const [loading, setLoading] = useState(false)
+
+  async function handleVerify() {
+    setError('')
+    setLoading(true)
+    try {
+      const config = loadConfig()
+      if (!config) {
+        setError('Configuration not found. Please set up the gateway URL.')
+        return
+      }
+      const res = await fetch(`${config.gatewayUrl}/login/verify`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ challenge_token: challengeToken, code: code.trim() }),
+      })
+      const json = await res.json()
+      if (!res.ok) {
+        setError(json?.message ?? 'Verification failed')
+        return
+      }
+      await onSignedIn(json.data.token)
+    } catch {
+      setError('Could not reach server. Check your connection.')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  const disabled = loading || !code.trim()
+
+  return (
+    <div className="auth-page">
+      <div className="auth-card">
+        <div className="auth-header">
+          <h1 className="auth-title">Two-Factor Sign-In</h1>
+          <p className="auth-subtitle">Enter the code from your authenticator app, or a recovery code.</p>
+        </div>
+        <div className="auth-fields">
+          <div className="auth-input-wrap">
+            <span className="auth-label">Code</span>
+            <input
+              className="auth-input"
+              type="text"
+              placeholder="123456"
+              value={code}
+              autoComplete="one-time-code"
+              autoFocus
+              onChange={e => setCode(e.target.value)}
+              onKeyDown={e => e.key === 'Enter' && !disabled && handleVerify()}
+            />
+          </div>
+        </div>
+        <button className="auth-button" onClick={handleVerify} disabled={disabled}>
+          {loading && <span className="auth-spinner" />}
+          {loading ? 'Verifying...' : 'Verify'}
+        </button>
+        {error && <p className="auth-error">{error}</p>}
+        <p className="auth-switch">
+          <a href="#" onClick={e => { e.preventDefault(); onCancel() }}>Back to Login</a>
+        </p>
+      </div>
+    </div>
+  )
+}
@@ -3,12 +3,24 @@ import { useNavigate, useSearchParams } from 'react-router-dom'
 import { loadConfig } from '../config'
 import { setToken } from '../auth'
 import { getMyKeys } from '../api/keysApi'
+import SecondFactorPrompt from '../components/SecondFactorPrompt'
 
 export default function CallbackPage() {
   const [searchParams] = useSearchParams()
   const [error, setError] = useState('')
+  const [challengeToken, setChallengeToken] = useState('')
   const navigate = useNavigate()
 
+  async function finishSignIn(token: string) {
+    setToken(token)
+    const keys = await getMyKeys()
+    if (keys.is_e2ee_ready) {
+      navigate('/pin-entry')
+    } else {
+      navigate('/key-setup')
+    }
+  }
+
   useEffect(() => {
     const urlError = searchParams.get('error')
     if (urlError) {
@@ -39,15 +51,12 @@ export default function CallbackPage() {
           setError(json?.message ?? 'Token exchange failed')
           return
         }
-        setToken(json.data.token)
         window.history.replaceState({}, '', '/callback')
-
-        const keys = await getMyKeys()
-        if (keys.is_e2ee_ready) {
-          navigate('/pin-entry')
-        } else {
-          navigate('/key-setup')
+        if (json.data.second_factor_required) {
+          setChallengeToken(json.data.challenge_token)
+          return
         }
+        await finishSignIn(json.data.token)
       } catch {
         setError('Could not reach server. Check your connection.')
       }
@@ -56,6 +65,16 @@ export default function CallbackPage() {
     exchangeToken()
   }, [])
 
+  if (challengeToken) {
+    return (
+      <SecondFactorPrompt
+        challengeToken={challengeToken}
+        onSignedIn={finishSignIn}
+        onCancel={() => navigate('/login')}
+      />
+    )
+  }
+
   if (error) {
     return (
       <div className="auth-page">
@@ -76,6 +76,8 @@ export default function HomePage() {
     if (noti.type === 'friend_request') {
       setHasUnreadNoti(true)
       loadFriendsRef.current()
+    } else if (noti.type === 'friend_accepted' || noti.type === 'friend_removed' || noti.type === 'retracted' || noti.type === 'profile_updated') {
+      loadFriendsRef.current()
     }
   }, [])
 
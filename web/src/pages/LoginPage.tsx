@@ -3,6 +3,7 @@ import { Link, useNavigate } from 'react-router-dom'
 import { loadConfig } from '../config'
 import { getToken, setToken } from '../auth'
 import { getMyKeys } from '../api/keysApi'
+import SecondFactorPrompt from '../components/SecondFactorPrompt'
 import './auth.css'
 
 const passwordAuthEnabled = import.meta.env.VITE_ENABLE_PASSWORD_AUTH === 'true'
@@ -13,6 +14,7 @@ export default function LoginPage() {
   const [error, setError] = useState('')
   const [githubError, setGithubError] = useState('')
   const [loading, setLoading] = useState(false)
+  const [challengeToken, setChallengeToken] = useState('')
   const navigate = useNavigate()
   const config = loadConfig()
 
@@ -40,13 +42,11 @@ export default function LoginPage() {
         setError(json?.message ?? 'Login failed')
         return
       }
-      setToken(json.data.token)
-      const keys = await getMyKeys()
-      if (keys.is_e2ee_ready) {
-        navigate('/pin-entry')
-      } else {
-        navigate('/key-setup')
+      if (json.data.second_factor_required) {
+        setChallengeToken(json.data.challenge_token)
+        return
       }
+      await finishSignIn(json.data.token)
     } catch {
       setError('Could not reach server. Check your connection.')
     } finally {
@@ -54,6 +54,29 @@ export default function LoginPage() {
     }
   }
 
+  async function finishSignIn(token: string) {
+    setToken(token)
+    const keys = await getMyKeys()
+    if (keys.is_e2ee_ready) {
+      navigate('/pin-entry')
+    } else {
+      navigate('/key-setup')
+    }
+  }
+
+  if (challengeToken) {
+    return (
+      <SecondFactorPrompt
+        challengeToken={challengeToken}
+        onSignedIn={finishSignIn}
+        onCancel={() => {
+          setChallengeToken('')
+          setPassword('')
+        }}
+      />
+    )
+  }
+
   const disabled = loading || !username.trim() || !password.trim()
 
   return (
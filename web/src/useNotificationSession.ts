@@ -8,7 +8,8 @@ export interface Notification {
   sender_id: string | null
   type: string
   message: string
-  reference_id: number | null
+  reference_conversation_id: number | null
+  reference_user_id: string | null
   is_read: boolean
   created_at: string
 }